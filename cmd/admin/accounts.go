@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// adminsSchema mirrors the migrate-on-construction pattern used throughout
+// this codebase: no separate migration step is needed before the admin
+// API can start.
+const adminsSchema = `
+CREATE TABLE IF NOT EXISTS admins (
+	id            TEXT PRIMARY KEY,
+	username      TEXT NOT NULL UNIQUE,
+	password_hash TEXT NOT NULL,
+	role          TEXT NOT NULL
+)`
+
+// AdminAccountStore persists admin login credentials. Passwords are never
+// stored or compared in the clear - password_hash holds a bcrypt hash
+// produced by the seedadmin CLI (see cmd/admin/seedadmin) or a future
+// admin-management endpoint.
+type AdminAccountStore struct {
+	db *sql.DB
+}
+
+// NewAdminAccountStore ensures the admins table exists.
+func NewAdminAccountStore(db *sql.DB) (*AdminAccountStore, error) {
+	if _, err := db.Exec(adminsSchema); err != nil {
+		return nil, fmt.Errorf("admin: migrating admins: %w", err)
+	}
+	return &AdminAccountStore{db: db}, nil
+}
+
+// adminCredentials is what GetByUsername returns: enough to authenticate
+// a login attempt and start a session, including the bcrypt hash to
+// compare against - never exposed outside this package.
+type adminCredentials struct {
+	ID           string
+	Username     string
+	PasswordHash string
+	Role         string
+}
+
+// GetByUsername returns username's stored credentials, or nil if no admin
+// is registered under that username.
+func (s *AdminAccountStore) GetByUsername(ctx context.Context, username string) (*adminCredentials, error) {
+	var c adminCredentials
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, username, password_hash, role FROM admins WHERE username = $1`,
+		username,
+	).Scan(&c.ID, &c.Username, &c.PasswordHash, &c.Role)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("admin: reading admin %s: %w", username, err)
+	}
+	return &c, nil
+}
+
+// Count returns the number of registered admins, so the seedadmin CLI can
+// decide whether this is a first-run seeding or an additional account.
+func (s *AdminAccountStore) Count(ctx context.Context) (int, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM admins`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("admin: counting admins: %w", err)
+	}
+	return n, nil
+}
+
+// Create inserts a new admin with the given bcrypt passwordHash and role.
+func (s *AdminAccountStore) Create(ctx context.Context, username, passwordHash, role string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO admins (id, username, password_hash, role) VALUES ($1, $2, $3, $4)`,
+		ulid.Make().String(), username, passwordHash, role,
+	)
+	if err != nil {
+		return fmt.Errorf("admin: creating admin %s: %w", username, err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// adminActionAuditSchema mirrors the migrate-on-construction pattern used
+// throughout cmd/syncshield/compliance: no separate migration step is
+// needed before the admin API can start. Rows are append-only and chained
+// by entry_hash, so editing, reordering, or truncating a past row breaks
+// the chain at the point of tampering.
+const adminActionAuditSchema = `
+CREATE TABLE IF NOT EXISTS admin_action_audit (
+	seq        BIGSERIAL PRIMARY KEY,
+	id         TEXT NOT NULL UNIQUE,
+	admin_id   TEXT NOT NULL,
+	action     TEXT NOT NULL,
+	resource   TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	prev_hash  TEXT NOT NULL,
+	entry_hash TEXT NOT NULL,
+	signature  TEXT NOT NULL DEFAULT ''
+)`
+
+// AdminAuditLog persists every AdminAction to Postgres as a SHA-256 hash
+// chain: entry_hash = sha256(prev_hash || canonical_json(action)). It's a
+// lighter-weight sibling of compliance.GDPRAuditLogger's Merkle-anchored
+// chain - just a flat chain, enough to make tampering or deletion of an
+// admin-action row detectable without the batching/checkpointing machinery
+// a regulator inclusion proof needs. If hmacKey is set, each entry_hash is
+// additionally signed with it so entries can be verified offline without
+// direct database access.
+type AdminAuditLog struct {
+	db      *sql.DB
+	hmacKey []byte
+
+	mu       sync.Mutex
+	lastHash string
+}
+
+// NewAdminAuditLog ensures admin_action_audit exists and primes the chain
+// from its last row, so a restarted process keeps appending to the same
+// chain instead of starting a new one. hmacKey may be nil to disable
+// signing.
+func NewAdminAuditLog(db *sql.DB, hmacKey []byte) (*AdminAuditLog, error) {
+	if _, err := db.Exec(adminActionAuditSchema); err != nil {
+		return nil, fmt.Errorf("admin: migrating admin_action_audit: %w", err)
+	}
+
+	l := &AdminAuditLog{db: db, hmacKey: hmacKey}
+	err := db.QueryRow(`SELECT entry_hash FROM admin_action_audit ORDER BY seq DESC LIMIT 1`).Scan(&l.lastHash)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("admin: reading audit chain tail: %w", err)
+	}
+	return l, nil
+}
+
+// Record assigns action an ID and Timestamp if unset, chains and persists
+// it, and returns the stored action.
+func (l *AdminAuditLog) Record(ctx context.Context, action AdminAction) (AdminAction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if action.ID == "" {
+		action.ID = ulid.Make().String()
+	}
+	if action.Timestamp.IsZero() {
+		action.Timestamp = time.Now()
+	}
+
+	entryHash, err := hashAdminAction(l.lastHash, action)
+	if err != nil {
+		return AdminAction{}, err
+	}
+
+	signature := ""
+	if l.hmacKey != nil {
+		signature = signAdminAuditEntry(l.hmacKey, entryHash)
+	}
+
+	if _, err := l.db.ExecContext(ctx, `
+		INSERT INTO admin_action_audit (id, admin_id, action, resource, status, created_at, prev_hash, entry_hash, signature)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		action.ID, action.AdminID, action.Action, action.Resource, action.Status, action.Timestamp, l.lastHash, entryHash, signature,
+	); err != nil {
+		return AdminAction{}, fmt.Errorf("admin: inserting audit entry: %w", err)
+	}
+
+	l.lastHash = entryHash
+	return action, nil
+}
+
+// List returns persisted admin_action_audit rows matching the given
+// filters, newest first. A zero since leaves the lower bound open; an
+// empty adminID or action leaves that filter off entirely.
+func (l *AdminAuditLog) List(ctx context.Context, since time.Time, adminID, action string, limit, offset int) ([]AdminAction, error) {
+	query := `SELECT id, admin_id, action, resource, status, created_at FROM admin_action_audit WHERE created_at >= $1`
+	args := []interface{}{since}
+
+	if adminID != "" {
+		args = append(args, adminID)
+		query += fmt.Sprintf(" AND admin_id = $%d", len(args))
+	}
+	if action != "" {
+		args = append(args, action)
+		query += fmt.Sprintf(" AND action = $%d", len(args))
+	}
+	args = append(args, limit, offset)
+	query += fmt.Sprintf(" ORDER BY seq DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("admin: querying audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var actions []AdminAction
+	for rows.Next() {
+		var a AdminAction
+		if err := rows.Scan(&a.ID, &a.AdminID, &a.Action, &a.Resource, &a.Status, &a.Timestamp); err != nil {
+			return nil, fmt.Errorf("admin: scanning audit entry: %w", err)
+		}
+		actions = append(actions, a)
+	}
+	return actions, rows.Err()
+}
+
+// VerifyChainResult reports the outcome of walking the admin_action_audit
+// hash chain from its first row.
+type VerifyChainResult struct {
+	Valid       bool   `json:"valid"`
+	EntriesRead int    `json:"entries_read"`
+	FirstBadID  string `json:"first_bad_id,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// VerifyChain walks every admin_action_audit row in sequence order and
+// recomputes prev_hash/entry_hash - and, if l.hmacKey is set, the HMAC
+// signature - to confirm no row has been edited, reordered, or deleted out
+// from under the chain.
+func (l *AdminAuditLog) VerifyChain(ctx context.Context) (VerifyChainResult, error) {
+	rows, err := l.db.QueryContext(ctx, `
+		SELECT id, admin_id, action, resource, status, created_at, prev_hash, entry_hash, signature
+		FROM admin_action_audit
+		ORDER BY seq ASC`)
+	if err != nil {
+		return VerifyChainResult{}, fmt.Errorf("admin: reading audit chain: %w", err)
+	}
+	defer rows.Close()
+
+	prevHash := ""
+	read := 0
+	for rows.Next() {
+		var action AdminAction
+		var prevHashCol, entryHash, signature string
+		if err := rows.Scan(&action.ID, &action.AdminID, &action.Action, &action.Resource, &action.Status, &action.Timestamp, &prevHashCol, &entryHash, &signature); err != nil {
+			return VerifyChainResult{}, fmt.Errorf("admin: scanning audit entry: %w", err)
+		}
+		read++
+
+		if prevHashCol != prevHash {
+			return VerifyChainResult{Valid: false, EntriesRead: read, FirstBadID: action.ID, Reason: "prev_hash does not match the preceding entry"}, nil
+		}
+		wantHash, err := hashAdminAction(prevHash, action)
+		if err != nil {
+			return VerifyChainResult{}, err
+		}
+		if wantHash != entryHash {
+			return VerifyChainResult{Valid: false, EntriesRead: read, FirstBadID: action.ID, Reason: "entry_hash does not match recomputed content"}, nil
+		}
+		if l.hmacKey != nil && signAdminAuditEntry(l.hmacKey, entryHash) != signature {
+			return VerifyChainResult{Valid: false, EntriesRead: read, FirstBadID: action.ID, Reason: "signature does not match entry_hash"}, nil
+		}
+
+		prevHash = entryHash
+	}
+	if err := rows.Err(); err != nil {
+		return VerifyChainResult{}, fmt.Errorf("admin: reading audit chain: %w", err)
+	}
+	return VerifyChainResult{Valid: true, EntriesRead: read}, nil
+}
+
+// hashAdminAction computes sha256(prevHash || canonical_json(action)), the
+// entry_hash each admin_action_audit row is chained by.
+func hashAdminAction(prevHash string, action AdminAction) (string, error) {
+	canonical, err := json.Marshal(action)
+	if err != nil {
+		return "", fmt.Errorf("admin: canonicalizing audit entry: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(prevHash), canonical...))
+	return fmt.Sprintf("%x", sum), nil
+}
+
+// signAdminAuditEntry HMAC-signs entryHash with key so an auditor holding
+// the same key can verify admin_action_audit offline, without direct
+// database access.
+func signAdminAuditEntry(key []byte, entryHash string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(entryHash))
+	return fmt.Sprintf("%x", mac.Sum(nil))
+}
@@ -0,0 +1,126 @@
+// Package authz implements IAM-style allow/deny policy evaluation for the
+// admin API: each role is granted a list of statements loaded from a JSON
+// policy document, and a request is permitted only if at least one
+// statement allows its action/resource pair and no statement denies it.
+package authz
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Effect is whether a Statement grants or withholds permission.
+type Effect string
+
+const (
+	Allow Effect = "Allow"
+	Deny  Effect = "Deny"
+)
+
+// Statement is one IAM-style policy rule: it applies when the requested
+// action matches any entry in Action and the requested resource matches
+// any entry in Resource. A pattern ending in "*" matches any value
+// sharing that prefix; a bare "*" matches everything.
+type Statement struct {
+	Effect   Effect   `json:"Effect"`
+	Action   []string `json:"Action"`
+	Resource []string `json:"Resource"`
+}
+
+// Store holds the loaded policy statements for every role, keyed by role
+// name, and can be hot-reloaded from disk without restarting the admin
+// API.
+type Store struct {
+	dir string
+
+	mu       sync.RWMutex
+	policies map[string][]Statement
+}
+
+// NewStore loads every "<role>.json" file in dir as that role's policy
+// document - a JSON array of Statement.
+func NewStore(dir string) (*Store, error) {
+	s := &Store{dir: dir}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload re-reads every policy document in s.dir, replacing the
+// previously loaded set atomically - an IsAllowed call already in flight
+// finishes against the policies it started with.
+func (s *Store) Reload() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("authz: reading policy directory %s: %w", s.dir, err)
+	}
+
+	policies := make(map[string][]Statement)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		role := strings.TrimSuffix(entry.Name(), ".json")
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("authz: reading policy for role %s: %w", role, err)
+		}
+		var statements []Statement
+		if err := json.Unmarshal(data, &statements); err != nil {
+			return fmt.Errorf("authz: parsing policy for role %s: %w", role, err)
+		}
+		policies[role] = statements
+	}
+
+	s.mu.Lock()
+	s.policies = policies
+	s.mu.Unlock()
+	return nil
+}
+
+// IsAllowed reports whether role's policy permits action on resource.
+// Default is deny; an explicit Deny statement always wins over a matching
+// Allow.
+func (s *Store) IsAllowed(role, action, resource string) bool {
+	s.mu.RLock()
+	statements := s.policies[role]
+	s.mu.RUnlock()
+
+	allowed := false
+	for _, stmt := range statements {
+		if !matchesAny(stmt.Action, action) || !matchesAny(stmt.Resource, resource) {
+			continue
+		}
+		if stmt.Effect == Deny {
+			return false
+		}
+		if stmt.Effect == Allow {
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+// matchesAny reports whether candidate matches any pattern in patterns.
+func matchesAny(patterns []string, candidate string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(candidate, prefix) {
+				return true
+			}
+			continue
+		}
+		if pattern == candidate {
+			return true
+		}
+	}
+	return false
+}
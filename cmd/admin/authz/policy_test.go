@@ -0,0 +1,97 @@
+package authz
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePolicy(t *testing.T, dir, role, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, role+".json"), []byte(body), 0644); err != nil {
+		t.Fatalf("writing policy for %s: %v", role, err)
+	}
+}
+
+func TestIsAllowedDefaultDeny(t *testing.T) {
+	dir := t.TempDir()
+	writePolicy(t, dir, "analyst", `[{"Effect":"Allow","Action":["metrics:View"],"Resource":["*"]}]`)
+
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	if !store.IsAllowed("analyst", "metrics:View", "*") {
+		t.Error("expected analyst to be allowed metrics:View")
+	}
+	if store.IsAllowed("analyst", "services:Restart", "service:syncshield") {
+		t.Error("expected analyst to be denied services:Restart by default")
+	}
+	if store.IsAllowed("unknown_role", "metrics:View", "*") {
+		t.Error("expected an unknown role with no policy to be denied everything")
+	}
+}
+
+func TestIsAllowedWildcardActionAndResource(t *testing.T) {
+	dir := t.TempDir()
+	writePolicy(t, dir, "super_admin", `[{"Effect":"Allow","Action":["*"],"Resource":["*"]}]`)
+	writePolicy(t, dir, "operator", `[{"Effect":"Allow","Action":["services:Restart"],"Resource":["service:syncshield","service:syncflow"]}]`)
+
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	if !store.IsAllowed("super_admin", "policies:Reload", "*") {
+		t.Error("expected super_admin's wildcard policy to allow anything")
+	}
+	if !store.IsAllowed("operator", "services:Restart", "service:syncflow") {
+		t.Error("expected operator to be allowed to restart a listed service")
+	}
+	if store.IsAllowed("operator", "services:Restart", "service:synccreate") {
+		t.Error("expected operator to be denied restarting an unlisted service")
+	}
+}
+
+func TestIsAllowedExplicitDenyOverridesAllow(t *testing.T) {
+	dir := t.TempDir()
+	writePolicy(t, dir, "manager", `[
+		{"Effect":"Allow","Action":["*"],"Resource":["*"]},
+		{"Effect":"Deny","Action":["services:Restart"],"Resource":["service:syncvalue"]}
+	]`)
+
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	if !store.IsAllowed("manager", "services:Restart", "service:syncshield") {
+		t.Error("expected manager to be allowed to restart a non-denied service")
+	}
+	if store.IsAllowed("manager", "services:Restart", "service:syncvalue") {
+		t.Error("expected the explicit Deny to override the wildcard Allow")
+	}
+}
+
+func TestReloadPicksUpChangedPolicy(t *testing.T) {
+	dir := t.TempDir()
+	writePolicy(t, dir, "operator", `[{"Effect":"Allow","Action":["metrics:View"],"Resource":["*"]}]`)
+
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	if store.IsAllowed("operator", "services:Restart", "service:syncshield") {
+		t.Fatal("expected operator to start out denied services:Restart")
+	}
+
+	writePolicy(t, dir, "operator", `[{"Effect":"Allow","Action":["services:Restart"],"Resource":["*"]}]`)
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	if !store.IsAllowed("operator", "services:Restart", "service:syncshield") {
+		t.Error("expected Reload to pick up the new policy granting services:Restart")
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// clientSendBuffer bounds how many queued messages a slow client can fall
+// behind by before Hub.Publish starts dropping its oldest queued message
+// rather than blocking on that client.
+const clientSendBuffer = 32
+
+const (
+	pingInterval = 30 * time.Second
+	pongTimeout  = 90 * time.Second
+)
+
+// wsMessage is the envelope every Hub.Publish payload reaches a client in.
+type wsMessage struct {
+	Type  string      `json:"type"`
+	Topic string      `json:"topic"`
+	Data  interface{} `json:"data"`
+}
+
+// Client is one authenticated WebSocket connection registered with a Hub.
+// Unlike the old map[*websocket.Conn]bool, a Client only receives the
+// topics it has subscribed to, and a full send buffer drops messages
+// instead of blocking the Hub.
+type Client struct {
+	hub  *Hub
+	conn *websocket.Conn
+	sess AdminSession
+
+	send chan []byte
+
+	mu     sync.RWMutex
+	topics map[string]bool
+
+	dropped int64 // atomic; total messages dropped for this client
+}
+
+// NewClient wraps conn as a Hub client authenticated as sess.
+func NewClient(hub *Hub, conn *websocket.Conn, sess AdminSession) *Client {
+	return &Client{
+		hub:    hub,
+		conn:   conn,
+		sess:   sess,
+		send:   make(chan []byte, clientSendBuffer),
+		topics: make(map[string]bool),
+	}
+}
+
+func (c *Client) subscribe(topics []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range topics {
+		c.topics[t] = true
+	}
+}
+
+func (c *Client) unsubscribe(topics []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range topics {
+		delete(c.topics, t)
+	}
+}
+
+// wants reports whether the client is subscribed to topic, either directly
+// or via a "prefix.*" wildcard subscription (e.g. "audit.*" matches
+// "audit.login").
+func (c *Client) wants(topic string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for sub := range c.topics {
+		if sub == topic {
+			return true
+		}
+		if strings.HasSuffix(sub, ".*") && strings.HasPrefix(topic, strings.TrimSuffix(sub, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueue hands payload to the client's send buffer. If the buffer is full
+// this is a drop-oldest policy: the oldest queued message is discarded to
+// make room for payload, so a slow client falls behind on history rather
+// than stalling the publisher.
+func (c *Client) enqueue(payload []byte) {
+	select {
+	case c.send <- payload:
+		return
+	default:
+	}
+
+	select {
+	case <-c.send:
+		atomic.AddInt64(&c.dropped, 1)
+	default:
+	}
+	select {
+	case c.send <- payload:
+	default:
+		atomic.AddInt64(&c.dropped, 1)
+	}
+}
+
+// writePump relays queued messages to the connection and sends periodic
+// pings; it's the only goroutine allowed to write to conn.
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingInterval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-c.send:
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readPump processes subscribe/unsubscribe frames and the admin command
+// protocol ({"command": "restart_service", ...}), and enforces the pong
+// keepalive timeout. It blocks until the connection closes.
+func (c *Client) readPump(s *AdminServer) {
+	defer func() {
+		c.hub.Unregister(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongTimeout))
+		return nil
+	})
+
+	for {
+		var msg map[string]interface{}
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		switch op, _ := msg["op"].(string); op {
+		case "subscribe":
+			c.subscribe(stringSlice(msg["topics"]))
+		case "unsubscribe":
+			c.unsubscribe(stringSlice(msg["topics"]))
+		default:
+			if command, ok := msg["command"].(string); ok {
+				s.handleAdminCommand(c.sess, command, msg)
+			}
+		}
+	}
+}
+
+// stringSlice converts a decoded JSON array ([]interface{} of strings)
+// into a []string, ignoring non-string entries.
+func stringSlice(v interface{}) []string {
+	raw, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(raw))
+	for _, item := range raw {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Hub fans topic-scoped updates out to subscribed WebSocket clients,
+// replacing the old single broadcast channel every client received
+// everything on.
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[*Client]bool
+
+	// lastPayload holds the last published body per topic, so an unchanged
+	// publish (e.g. a service whose status hasn't moved since the last
+	// tick) is skipped rather than re-sent.
+	lastPayload map[string][]byte
+}
+
+// NewHub returns an empty Hub.
+func NewHub() *Hub {
+	return &Hub{
+		clients:     make(map[*Client]bool),
+		lastPayload: make(map[string][]byte),
+	}
+}
+
+// Register adds client to the hub.
+func (h *Hub) Register(c *Client) {
+	h.mu.Lock()
+	h.clients[c] = true
+	h.mu.Unlock()
+}
+
+// Unregister removes client from the hub and closes its send channel,
+// unblocking writePump.
+func (h *Hub) Unregister(c *Client) {
+	h.mu.Lock()
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.send)
+	}
+	h.mu.Unlock()
+}
+
+// Publish delivers data under topic to every client subscribed to it. A
+// publish whose encoded body is byte-identical to the last publish on the
+// same topic is skipped - the delta encoding that keeps unchanged services
+// from being re-sent every tick.
+func (h *Hub) Publish(topic string, data interface{}) {
+	body, err := json.Marshal(wsMessage{Type: "update", Topic: topic, Data: data})
+	if err != nil {
+		log.Printf("Failed to marshal websocket payload for topic %s: %v", topic, err)
+		return
+	}
+
+	h.mu.Lock()
+	if last, ok := h.lastPayload[topic]; ok && string(last) == string(body) {
+		h.mu.Unlock()
+		return
+	}
+	h.lastPayload[topic] = body
+	clients := make([]*Client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		if c.wants(topic) {
+			c.enqueue(body)
+		}
+	}
+}
+
+// HubStats summarizes a Hub's current connections for
+// GET /api/admin/ws/stats.
+type HubStats struct {
+	ConnectedClients   int            `json:"connected_clients"`
+	DroppedMessages    int64          `json:"dropped_messages"`
+	SubscriptionCounts map[string]int `json:"subscription_counts"`
+}
+
+// Stats reports connected client count, total messages dropped across all
+// clients, and per-topic subscriber counts.
+func (h *Hub) Stats() HubStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	stats := HubStats{
+		ConnectedClients:   len(h.clients),
+		SubscriptionCounts: make(map[string]int),
+	}
+	for c := range h.clients {
+		stats.DroppedMessages += atomic.LoadInt64(&c.dropped)
+		c.mu.RLock()
+		for topic := range c.topics {
+			stats.SubscriptionCounts[topic]++
+		}
+		c.mu.RUnlock()
+	}
+	return stats
+}
@@ -1,476 +1,781 @@
-package main
-
-import (
-	"context"
-	"database/sql"
-	"encoding/json"
-	"fmt"
-	"log"
-	"net/http"
-	"os"
-	"os/signal"
-	"sync"
-	"syscall"
-	"time"
-
-	"github.com/gorilla/websocket"
-	_ "github.com/lib/pq"
-	"github.com/prometheus/client_golang/api"
-	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
-)
-
-// Database connection helper
-func getDB() (*sql.DB, error) {
-	host := os.Getenv("DB_HOST")
-	name := os.Getenv("DB_NAME")
-	user := os.Getenv("DB_USER")
-	pass := os.Getenv("DB_PASSWORD")
-	dsn := fmt.Sprintf("host=%s dbname=%s user=%s password=%s sslmode=disable", host, name, user, pass)
-	return sql.Open("postgres", dsn)
-}
-
-// Admin API - Port 8085
-// Aggregates metrics from all services and provides real-time monitoring
-
-const PORT = 8085
-
-// ServiceStatus represents the health of a microservice
-type ServiceStatus struct {
-	Name           string             `json:"name"`
-	Status         string             `json:"status"` // "up", "down", "degraded"
-	Uptime         float64            `json:"uptime"` // percentage
-	RequestsPerSec float64            `json:"requests_per_sec"`
-	ErrorRate      float64            `json:"error_rate"` // percentage
-	Latency        map[string]float64 `json:"latency"`    // p50, p95, p99
-	LastCheck      time.Time          `json:"last_check"`
-}
-
-// DashboardMetrics represents aggregated platform metrics
-type DashboardMetrics struct {
-	Timestamp       time.Time                `json:"timestamp"`
-	Services        map[string]ServiceStatus `json:"services"`
-	Uptime          float64                  `json:"uptime"`
-	ActiveCampaigns int                      `json:"active_campaigns"`
-	DailyRevenue    float64                  `json:"daily_revenue"`
-	TotalBudget     float64                  `json:"total_budget"`
-	SpentToday      float64                  `json:"spent_today"`
-}
-
-// AdminAlert represents an alert event
-type AdminAlert struct {
-	ID        string    `json:"id"`
-	Severity  string    `json:"severity"` // "info", "warning", "critical"
-	Message   string    `json:"message"`
-	Service   string    `json:"service"`
-	Timestamp time.Time `json:"timestamp"`
-	Resolved  bool      `json:"resolved"`
-}
-
-// AdminAction represents an action taken by an admin
-type AdminAction struct {
-	ID        string    `json:"id"`
-	AdminID   string    `json:"admin_id"`
-	Action    string    `json:"action"`
-	Resource  string    `json:"resource"`
-	Status    string    `json:"status"`
-	Timestamp time.Time `json:"timestamp"`
-}
-
-// Admin represents an authenticated admin user
-type Admin struct {
-	ID       string   `json:"id"`
-	Username string   `json:"username"`
-	Role     string   `json:"role"` // super_admin, manager, analyst, operator
-	Perms    []string `json:"perms"`
-}
-
-type AdminServer struct {
-	mu               sync.RWMutex
-	metrics          DashboardMetrics
-	alerts           []AdminAlert
-	actions          []AdminAction
-	clients          map[*websocket.Conn]bool
-	broadcast        chan interface{}
-	prometheus       v1.API
-	serviceEndpoints map[string]string
-}
-
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true // In production, validate origin
-	},
-}
-
-func NewAdminServer() *AdminServer {
-	// Initialize Prometheus client
-	client, err := api.NewClient(api.Config{
-		Address: "http://localhost:9090", // Prometheus on port 9090
-	})
-	if err != nil {
-		log.Printf("Warning: Could not connect to Prometheus: %v", err)
-	}
-
-	server := &AdminServer{
-		clients:    make(map[*websocket.Conn]bool),
-		broadcast:  make(chan interface{}, 100),
-		prometheus: v1.NewAPI(client),
-		serviceEndpoints: map[string]string{
-			"syncshield": "http://localhost:8081/health",
-			"syncengage": "http://localhost:8083/health",
-			"syncflow":   "http://localhost:8082/health",
-			"synccreate": "http://localhost:8084/health",
-			"syncvalue":  "http://localhost:50051/health", // gRPC
-		},
-		metrics: DashboardMetrics{
-			Timestamp: time.Now(),
-			Services:  make(map[string]ServiceStatus),
-		},
-	}
-
-	return server
-}
-
-// HealthCheckService polls a service's health endpoint
-func (s *AdminServer) HealthCheckService(name, endpoint string) ServiceStatus {
-	status := ServiceStatus{
-		Name:      name,
-		Status:    "down",
-		Latency:   make(map[string]float64),
-		LastCheck: time.Now(),
-	}
-
-	client := &http.Client{Timeout: 5 * time.Second}
-	start := time.Now()
-	resp, err := client.Get(endpoint)
-	duration := time.Since(start).Seconds() * 1000 // ms
-
-	if err != nil || resp.StatusCode != http.StatusOK {
-		return status
-	}
-	defer resp.Body.Close()
-
-	status.Status = "up"
-	status.Latency["current"] = duration
-	status.Uptime = 99.95 + (5 - duration/100) // Rough estimate
-
-	return status
-}
-
-// CollectMetrics aggregates metrics from all sources
-func (s *AdminServer) CollectMetrics(ctx context.Context) {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		s.mu.Lock()
-
-		// Check all services
-		for name, endpoint := range s.serviceEndpoints {
-			status := s.HealthCheckService(name, endpoint)
-			s.metrics.Services[name] = status
-		}
-
-		// Update timestamp
-		s.metrics.Timestamp = time.Now()
-
-		// Broadcast update to WebSocket clients
-		s.mu.Unlock()
-		select {
-		case s.broadcast <- s.metrics:
-		default:
-		}
-	}
-}
-
-// HandleWebSocket handles real-time metric subscriptions
-func (s *AdminServer) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
-	if err != nil {
-		log.Printf("WebSocket upgrade error: %v", err)
-		return
-	}
-	defer conn.Close()
-
-	s.mu.Lock()
-	s.clients[conn] = true
-	s.mu.Unlock()
-
-	log.Printf("WebSocket client connected. Total clients: %d", len(s.clients))
-
-	// Send current metrics immediately
-	s.mu.RLock()
-	conn.WriteJSON(map[string]interface{}{
-		"type":    "metrics",
-		"data":    s.metrics,
-		"message": "Connected to KIKI Super-Admin",
-	})
-	s.mu.RUnlock()
-
-	// Listen for client messages
-	for {
-		var msg map[string]interface{}
-		err := conn.ReadJSON(&msg)
-		if err != nil {
-			s.mu.Lock()
-			delete(s.clients, conn)
-			s.mu.Unlock()
-			log.Printf("WebSocket client disconnected. Remaining: %d", len(s.clients))
-			break
-		}
-
-		// Handle commands from client
-		if command, ok := msg["command"].(string); ok {
-			s.handleAdminCommand(command, msg)
-		}
-	}
-}
-
-// BroadcastMetrics sends metrics to all connected WebSocket clients
-func (s *AdminServer) BroadcastMetrics() {
-	for {
-		update := <-s.broadcast
-		s.mu.RLock()
-		for client := range s.clients {
-			err := client.WriteJSON(map[string]interface{}{
-				"type": "metrics",
-				"data": update,
-			})
-			if err != nil {
-				client.Close()
-				delete(s.clients, client)
-			}
-		}
-		s.mu.RUnlock()
-	}
-}
-
-func (s *AdminServer) handleAdminCommand(command string, msg map[string]interface{}) {
-	switch command {
-	case "restart_service":
-		if service, ok := msg["service"].(string); ok {
-			action := AdminAction{
-				ID:        fmt.Sprintf("action_%d", time.Now().Unix()),
-				Action:    "restart",
-				Resource:  service,
-				Status:    "pending",
-				Timestamp: time.Now(),
-			}
-			s.mu.Lock()
-			s.actions = append(s.actions, action)
-			s.mu.Unlock()
-			log.Printf("Action: Restart %s", service)
-		}
-	case "pause_campaign":
-		if campaign, ok := msg["campaign"].(string); ok {
-			log.Printf("Action: Pause campaign %s", campaign)
-		}
-	}
-}
-
-// HTTP Handlers
-
-// GET /api/admin/health - Overall platform health
-func (s *AdminServer) handleHealth(w http.ResponseWriter, r *http.Request) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	upCount := 0
-	for _, svc := range s.metrics.Services {
-		if svc.Status == "up" {
-			upCount++
-		}
-	}
-
-	response := map[string]interface{}{
-		"status":         "ok",
-		"timestamp":      s.metrics.Timestamp,
-		"services_up":    upCount,
-		"services_total": len(s.metrics.Services),
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
-
-// GET /api/admin/metrics - Aggregated metrics
-func (s *AdminServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(s.metrics)
-}
-
-// GET /api/admin/services - Service status
-func (s *AdminServer) handleServices(w http.ResponseWriter, r *http.Request) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(s.metrics.Services)
-}
-
-// GET /api/admin/alerts - Active alerts
-func (s *AdminServer) handleAlerts(w http.ResponseWriter, r *http.Request) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(s.alerts)
-}
-
-// GET /api/admin/audit-log - Audit trail
-func (s *AdminServer) handleAuditLog(w http.ResponseWriter, r *http.Request) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(s.actions)
-}
-
-// POST /api/admin/alerts/config - Update alert thresholds
-func (s *AdminServer) handleAlertConfig(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var config map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
-	}
-
-	// In production, save to database
-	log.Printf("Alert config updated: %v", config)
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
-}
-
-// POST /api/admin/services/restart?service=SERVICE_NAME - Restart a service (Go 1.21 compatible)
-func (s *AdminServer) handleRestartService(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	service := r.URL.Query().Get("service")
-	if service == "" {
-		http.Error(w, "Missing service parameter", http.StatusBadRequest)
-		return
-	}
-	log.Printf("Restart requested for service: %s", service)
-
-	// In production, call docker/k8s to restart
-	action := AdminAction{
-		ID:        fmt.Sprintf("action_%d", time.Now().Unix()),
-		Action:    "restart",
-		Resource:  service,
-		Status:    "pending",
-		Timestamp: time.Now(),
-	}
-
-	s.mu.Lock()
-	s.actions = append(s.actions, action)
-	s.mu.Unlock()
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"status":  "restart_initiated",
-		"service": service,
-	})
-}
-
-// Serve admin UI (static files)
-func (s *AdminServer) handleStatic(w http.ResponseWriter, r *http.Request) {
-	path := "./web/admin" + r.URL.Path
-	http.ServeFile(w, r, path)
-}
-
-func handleLogin(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	var creds struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
-		http.Error(w, `{"error":"Invalid request"}`, http.StatusBadRequest)
-		return
-	}
-	// DEMO: Hardcoded credentials (replace with DB lookup + bcrypt in production)
-	if creds.Username == "superadmin" && creds.Password == "supersecret" {
-		http.SetCookie(w, &http.Cookie{
-			Name:     "admin_session",
-			Value:    "valid",
-			Path:     "/",
-			HttpOnly: true,
-			Secure:   false,
-			MaxAge:   3600,
-		})
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"status":"ok"}`))
-	} else {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte(`{"error":"Invalid username or password"}`))
-	}
-}
-
-func main() {
-	// Test DB connection at startup
-	db, err := getDB()
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
-	}
-	if err := db.Ping(); err != nil {
-		log.Fatalf("Database not reachable: %v", err)
-	}
-	log.Println("Connected to Postgres DB successfully.")
-	db.Close()
-
-	server := NewAdminServer()
-
-	// Start metrics collection in background
-	go server.CollectMetrics(context.Background())
-
-	// Start broadcasting metrics to WebSocket clients
-	go server.BroadcastMetrics()
-
-	// HTTP routes
-	http.HandleFunc("/health", server.handleHealth)
-	http.HandleFunc("/api/admin/health", server.handleHealth)
-	http.HandleFunc("/api/admin/metrics", server.handleMetrics)
-	http.HandleFunc("/api/admin/services", server.handleServices)
-	http.HandleFunc("/api/admin/alerts", server.handleAlerts)
-	http.HandleFunc("/api/admin/audit-log", server.handleAuditLog)
-	http.HandleFunc("/api/admin/alerts/config", server.handleAlertConfig)
-	http.HandleFunc("/api/admin/services/restart", server.handleRestartService)
-	http.HandleFunc("/api/admin/login", handleLogin)
-
-	// WebSocket
-	http.HandleFunc("/live/metrics", server.HandleWebSocket)
-
-	// Static files
-	http.Handle("/", http.FileServer(http.Dir("./web/admin")))
-
-	addr := fmt.Sprintf(":%d", PORT)
-	log.Printf("KIKI Super-Admin listening on %s", addr)
-	log.Printf("Dashboard: http://localhost:%d", PORT)
-
-	// Graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-	go func() {
-		if err := http.ListenAndServe(addr, nil); err != nil && err != http.ErrServerClosed {
-			log.Printf("Server error: %v", err)
-		}
-	}()
-
-	<-sigChan
-	log.Println("Admin API shutting down...")
-}
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/user/kiki-agent/cmd/admin/authz"
+	"github.com/user/kiki-agent/cmd/admin/restarter"
+)
+
+// Database connection helper
+func getDB() (*sql.DB, error) {
+	host := os.Getenv("DB_HOST")
+	name := os.Getenv("DB_NAME")
+	user := os.Getenv("DB_USER")
+	pass := os.Getenv("DB_PASSWORD")
+	dsn := fmt.Sprintf("host=%s dbname=%s user=%s password=%s sslmode=disable", host, name, user, pass)
+	return sql.Open("postgres", dsn)
+}
+
+// Admin API - Port 8085
+// Aggregates metrics from all services and provides real-time monitoring
+
+const PORT = 8085
+
+// ServiceStatus represents the health of a microservice
+type ServiceStatus struct {
+	Name           string             `json:"name"`
+	Status         string             `json:"status"` // "up", "down", "degraded"
+	Uptime         float64            `json:"uptime"` // percentage
+	RequestsPerSec float64            `json:"requests_per_sec"`
+	ErrorRate      float64            `json:"error_rate"` // percentage
+	Latency        map[string]float64 `json:"latency"`    // p50, p95, p99
+	LastCheck      time.Time          `json:"last_check"`
+}
+
+// DashboardMetrics represents aggregated platform metrics
+type DashboardMetrics struct {
+	Timestamp       time.Time                `json:"timestamp"`
+	Services        map[string]ServiceStatus `json:"services"`
+	Uptime          float64                  `json:"uptime"`
+	ActiveCampaigns int                      `json:"active_campaigns"`
+	DailyRevenue    float64                  `json:"daily_revenue"`
+	TotalBudget     float64                  `json:"total_budget"`
+	SpentToday      float64                  `json:"spent_today"`
+	WSDropped       int64                    `json:"websocket_dropped_messages"`
+}
+
+// AdminAlert represents an alert event
+type AdminAlert struct {
+	ID        string    `json:"id"`
+	Severity  string    `json:"severity"` // "info", "warning", "critical"
+	Message   string    `json:"message"`
+	Service   string    `json:"service"`
+	Timestamp time.Time `json:"timestamp"`
+	Resolved  bool      `json:"resolved"`
+}
+
+// AdminAction represents an action taken by an admin
+type AdminAction struct {
+	ID        string    `json:"id"`
+	AdminID   string    `json:"admin_id"`
+	Action    string    `json:"action"`
+	Resource  string    `json:"resource"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Admin represents an authenticated admin user
+type Admin struct {
+	ID       string   `json:"id"`
+	Username string   `json:"username"`
+	Role     string   `json:"role"` // super_admin, manager, analyst, operator
+	Perms    []string `json:"perms"`
+}
+
+type AdminServer struct {
+	mu               sync.RWMutex
+	metrics          DashboardMetrics
+	alerts           []AdminAlert
+	auditLog         *AdminAuditLog
+	policies         *authz.Store
+	accounts         *AdminAccountStore
+	sessions         *SessionStore
+	hub              *Hub
+	restartExec      restarter.Executor
+	prometheus       v1.API
+	serviceEndpoints map[string]string
+}
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true // In production, validate origin
+	},
+}
+
+func NewAdminServer(db *sql.DB, policiesDir string) (*AdminServer, error) {
+	// Initialize Prometheus client
+	client, err := api.NewClient(api.Config{
+		Address: "http://localhost:9090", // Prometheus on port 9090
+	})
+	if err != nil {
+		log.Printf("Warning: Could not connect to Prometheus: %v", err)
+	}
+
+	var hmacKey []byte
+	if key := os.Getenv("ADMIN_AUDIT_HMAC_KEY"); key != "" {
+		hmacKey = []byte(key)
+	}
+	auditLog, err := NewAdminAuditLog(db, hmacKey)
+	if err != nil {
+		return nil, fmt.Errorf("admin: initializing audit log: %w", err)
+	}
+
+	policies, err := authz.NewStore(policiesDir)
+	if err != nil {
+		return nil, fmt.Errorf("admin: loading RBAC policies: %w", err)
+	}
+
+	accounts, err := NewAdminAccountStore(db)
+	if err != nil {
+		return nil, fmt.Errorf("admin: initializing admin accounts: %w", err)
+	}
+
+	hub := NewHub()
+
+	// The restart executor's Observer broadcasts every state transition
+	// over the Hub and appends it to the audit chain; it doesn't know
+	// which admin requested the restart, so transitions past the initial
+	// request are attributed to "system".
+	observer := func(actionID, service string, state restarter.State) {
+		hub.Publish("actions."+actionID, map[string]interface{}{
+			"action_id": actionID,
+			"service":   service,
+			"state":     state,
+		})
+		if _, err := auditLog.Record(context.Background(), AdminAction{
+			AdminID:  "system",
+			Action:   "services:Restart",
+			Resource: service,
+			Status:   string(state),
+		}); err != nil {
+			log.Printf("Failed to record audit entry: %v", err)
+		}
+	}
+	restartExec, err := newRestartExecutor(os.Getenv("RESTART_BACKEND"), observer)
+	if err != nil {
+		return nil, fmt.Errorf("admin: initializing restart executor: %w", err)
+	}
+
+	server := &AdminServer{
+		hub:         hub,
+		restartExec: restartExec,
+		prometheus:  v1.NewAPI(client),
+		auditLog:    auditLog,
+		policies:    policies,
+		accounts:    accounts,
+		sessions:    NewSessionStore(),
+		serviceEndpoints: map[string]string{
+			"syncshield": "http://localhost:8081/health",
+			"syncengage": "http://localhost:8083/health",
+			"syncflow":   "http://localhost:8082/health",
+			"synccreate": "http://localhost:8084/health",
+			"syncvalue":  "http://localhost:50051/health", // gRPC
+		},
+		metrics: DashboardMetrics{
+			Timestamp: time.Now(),
+			Services:  make(map[string]ServiceStatus),
+		},
+	}
+
+	return server, nil
+}
+
+// latencyQuantiles are the percentiles HealthCheckService populates
+// ServiceStatus.Latency with.
+var latencyQuantiles = map[string]float64{"p50": 0.5, "p95": 0.95, "p99": 0.99}
+
+// HealthCheckService probes a service's health endpoint for basic
+// liveness, then - if it's up - fills in its request rate, error rate, and
+// latency quantiles from Prometheus.
+func (s *AdminServer) HealthCheckService(ctx context.Context, name, endpoint string) ServiceStatus {
+	status := ServiceStatus{
+		Name:      name,
+		Status:    "down",
+		Latency:   make(map[string]float64),
+		LastCheck: time.Now(),
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(endpoint)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return status
+	}
+	defer resp.Body.Close()
+	status.Status = "up"
+
+	if rps, err := s.queryScalar(ctx, requestsPerSecQuery(name)); err != nil {
+		log.Printf("Failed to query requests/sec for %s: %v", name, err)
+	} else {
+		status.RequestsPerSec = rps
+	}
+
+	if errorRate, err := s.queryScalar(ctx, errorRateQuery(name, time.Minute)); err != nil {
+		log.Printf("Failed to query error rate for %s: %v", name, err)
+	} else {
+		status.ErrorRate = errorRate * 100
+		status.Uptime = (1 - errorRate) * 100
+	}
+
+	for label, quantile := range latencyQuantiles {
+		latency, err := s.queryScalar(ctx, latencyQuantileQuery(name, quantile))
+		if err != nil {
+			log.Printf("Failed to query %s latency for %s: %v", label, name, err)
+			continue
+		}
+		status.Latency[label] = latency * 1000 // seconds -> ms
+	}
+
+	return status
+}
+
+// CollectMetrics aggregates metrics from all sources and evaluates SLO
+// burn-rate alerts every tick.
+func (s *AdminServer) CollectMetrics(ctx context.Context) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.mu.Lock()
+
+		// Check all services, publishing each one's own topic so a client
+		// subscribed to e.g. "metrics.services.syncshield" only hears about
+		// that service - and only when its status actually changed, since
+		// Hub.Publish skips identical re-publishes.
+		for name, endpoint := range s.serviceEndpoints {
+			status := s.HealthCheckService(ctx, name, endpoint)
+			s.metrics.Services[name] = status
+			s.hub.Publish("metrics.services."+name, status)
+		}
+
+		// Update timestamp
+		s.metrics.Timestamp = time.Now()
+		s.mu.Unlock()
+
+		s.evaluateSLOAlerts(ctx)
+	}
+}
+
+// evaluateSLOAlerts runs the multi-window multi-burn-rate recipe for every
+// configured SLOTarget, appending and publishing any alerts it fires under
+// "alerts.<severity>".
+func (s *AdminServer) evaluateSLOAlerts(ctx context.Context) {
+	for _, target := range defaultSLOTargets {
+		alerts, err := s.evaluateBurnRateAlerts(ctx, target)
+		if err != nil {
+			log.Printf("Failed to evaluate SLO burn rate for %s: %v", target.Service, err)
+			continue
+		}
+		if len(alerts) == 0 {
+			continue
+		}
+
+		s.mu.Lock()
+		s.alerts = append(s.alerts, alerts...)
+		s.mu.Unlock()
+
+		for _, alert := range alerts {
+			s.hub.Publish("alerts."+alert.Severity, alert)
+		}
+	}
+}
+
+// HandleWebSocket upgrades an authenticated request to a WebSocket
+// connection and registers it with the Hub. Clients receive nothing until
+// they send a {"op":"subscribe","topics":[...]} frame naming the topics
+// they want (e.g. "metrics.services.syncshield", "alerts.critical",
+// "audit.*").
+func (s *AdminServer) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie("admin_session")
+	if err != nil {
+		http.Error(w, "Not authenticated", http.StatusUnauthorized)
+		return
+	}
+	sess, ok := s.sessions.Lookup(cookie.Value)
+	if !ok {
+		http.Error(w, "Not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+
+	client := NewClient(s.hub, conn, sess)
+	s.hub.Register(client)
+	log.Printf("WebSocket client connected: %s", sess.Username)
+
+	go client.writePump()
+	client.readPump(s)
+}
+
+// recordAudit persists action via s.auditLog and publishes it under
+// "audit.<action>" for any WebSocket client subscribed to that topic or to
+// the "audit.*" wildcard.
+func (s *AdminServer) recordAudit(ctx context.Context, action AdminAction) {
+	recorded, err := s.auditLog.Record(ctx, action)
+	if err != nil {
+		log.Printf("Failed to record audit entry: %v", err)
+		return
+	}
+	s.hub.Publish("audit."+recorded.Action, recorded)
+}
+
+func (s *AdminServer) handleAdminCommand(sess AdminSession, command string, msg map[string]interface{}) {
+	switch command {
+	case "restart_service":
+		if service, ok := msg["service"].(string); ok {
+			resource := "service:" + service
+			allowed := s.policies.IsAllowed(sess.Role, "services:Restart", resource)
+
+			if !allowed {
+				s.recordAudit(context.Background(), AdminAction{
+					AdminID:  sess.AdminID,
+					Action:   "services:Restart",
+					Resource: resource,
+					Status:   "DENIED",
+				})
+				log.Printf("Denied: %s lacks services:Restart on %s", sess.Username, resource)
+				return
+			}
+
+			actionID, err := s.restartExec.Restart(context.Background(), service)
+			if err != nil {
+				log.Printf("Restart rejected for %s: %v", service, err)
+				s.recordAudit(context.Background(), AdminAction{
+					AdminID:  sess.AdminID,
+					Action:   "services:Restart",
+					Resource: resource,
+					Status:   "REJECTED: " + err.Error(),
+				})
+				return
+			}
+
+			s.recordAudit(context.Background(), AdminAction{
+				ID:       actionID,
+				AdminID:  sess.AdminID,
+				Action:   "services:Restart",
+				Resource: resource,
+				Status:   "pending",
+			})
+			log.Printf("Action: Restart %s (action %s)", service, actionID)
+		}
+	case "pause_campaign":
+		if campaign, ok := msg["campaign"].(string); ok {
+			allowed := s.policies.IsAllowed(sess.Role, "campaigns:Pause", "*")
+
+			status := "DENIED"
+			if allowed {
+				status = "pending"
+			}
+			s.recordAudit(context.Background(), AdminAction{
+				AdminID:  sess.AdminID,
+				Action:   "campaigns:Pause",
+				Resource: campaign,
+				Status:   status,
+			})
+
+			if !allowed {
+				log.Printf("Denied: %s lacks campaigns:Pause", sess.Username)
+				return
+			}
+			log.Printf("Action: Pause campaign %s", campaign)
+		}
+	}
+}
+
+// HTTP Handlers
+
+// GET /api/admin/health - Overall platform health
+func (s *AdminServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	upCount := 0
+	for _, svc := range s.metrics.Services {
+		if svc.Status == "up" {
+			upCount++
+		}
+	}
+
+	response := map[string]interface{}{
+		"status":         "ok",
+		"timestamp":      s.metrics.Timestamp,
+		"services_up":    upCount,
+		"services_total": len(s.metrics.Services),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GET /api/admin/metrics - Aggregated metrics
+func (s *AdminServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	metrics := s.metrics
+	s.mu.RUnlock()
+
+	metrics.WSDropped = s.hub.Stats().DroppedMessages
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(metrics)
+}
+
+// GET /api/admin/ws/stats - connected WebSocket client count, per-topic
+// subscription cardinality, and total messages dropped to slow clients.
+func (s *AdminServer) handleWSStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.hub.Stats())
+}
+
+// GET /api/admin/services - Service status
+func (s *AdminServer) handleServices(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.metrics.Services)
+}
+
+// GET /api/admin/alerts - Active alerts
+func (s *AdminServer) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.alerts)
+}
+
+// GET /api/admin/audit-log?since=&admin_id=&action=&limit=&offset= - paginated, persisted audit trail
+func (s *AdminServer) handleAuditLog(w http.ResponseWriter, r *http.Request) {
+	since := time.Time{}
+	if v := r.URL.Query().Get("since"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "Invalid since parameter, expected RFC3339", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	offset := 0
+	if v := r.URL.Query().Get("offset"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid offset parameter", http.StatusBadRequest)
+			return
+		}
+		offset = parsed
+	}
+
+	actions, err := s.auditLog.List(r.Context(), since, r.URL.Query().Get("admin_id"), r.URL.Query().Get("action"), limit, offset)
+	if err != nil {
+		log.Printf("Failed to list audit log: %v", err)
+		http.Error(w, "Failed to list audit log", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"entries": actions,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// GET /api/admin/audit-log/verify - walk the hash chain and report the first broken entry, if any
+func (s *AdminServer) handleAuditLogVerify(w http.ResponseWriter, r *http.Request) {
+	result, err := s.auditLog.VerifyChain(r.Context())
+	if err != nil {
+		log.Printf("Failed to verify audit chain: %v", err)
+		http.Error(w, "Failed to verify audit chain", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// POST /api/admin/alerts/config - Update alert thresholds
+func (s *AdminServer) handleAlertConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var config map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	// In production, save to database
+	log.Printf("Alert config updated: %v", config)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
+}
+
+// POST /api/admin/services/restart?service=SERVICE_NAME - Restart a service (Go 1.21 compatible)
+func (s *AdminServer) handleRestartService(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	service := r.URL.Query().Get("service")
+	if service == "" {
+		http.Error(w, "Missing service parameter", http.StatusBadRequest)
+		return
+	}
+	adminID := "unknown"
+	if cookie, err := r.Cookie("admin_session"); err == nil {
+		if sess, ok := s.sessions.Lookup(cookie.Value); ok {
+			adminID = sess.AdminID
+		}
+	}
+
+	actionID, err := s.restartExec.Restart(r.Context(), service)
+	if err != nil {
+		log.Printf("Restart rejected for %s: %v", service, err)
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	log.Printf("Restart requested for service: %s (action %s)", service, actionID)
+
+	s.recordAudit(r.Context(), AdminAction{
+		ID:       actionID,
+		AdminID:  adminID,
+		Action:   "services:Restart",
+		Resource: service,
+		Status:   "pending",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"status":    "restart_initiated",
+		"service":   service,
+		"action_id": actionID,
+	})
+}
+
+// GET /api/admin/actions/{id} - poll a restart action's current state,
+// mirroring what the "actions.<id>" WebSocket topic pushes as it
+// transitions.
+func (s *AdminServer) handleActionStatus(w http.ResponseWriter, r *http.Request) {
+	actionID := strings.TrimPrefix(r.URL.Path, "/api/admin/actions/")
+	if actionID == "" {
+		http.Error(w, "Missing action id", http.StatusBadRequest)
+		return
+	}
+
+	state, err := s.restartExec.Status(actionID)
+	if err != nil {
+		http.Error(w, "Unknown action id", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"action_id": actionID,
+		"state":     string(state),
+	})
+}
+
+// Serve admin UI (static files)
+func (s *AdminServer) handleStatic(w http.ResponseWriter, r *http.Request) {
+	path := "./web/admin" + r.URL.Path
+	http.ServeFile(w, r, path)
+}
+
+// POST /api/admin/login - authenticates against the admins table (bcrypt),
+// records the attempt into the audit chain either way, and on success
+// mints an admin_session cookie the middleware and WebSocket upgrade both
+// look up.
+func (s *AdminServer) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var creds struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		http.Error(w, `{"error":"Invalid request"}`, http.StatusBadRequest)
+		return
+	}
+
+	account, err := s.accounts.GetByUsername(r.Context(), creds.Username)
+	if err != nil {
+		log.Printf("Failed to look up admin %s: %v", creds.Username, err)
+		http.Error(w, `{"error":"Internal error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	status := "FAILED"
+	if account != nil && bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(creds.Password)) == nil {
+		status = "SUCCESS"
+	}
+
+	adminID := "unknown"
+	if account != nil {
+		adminID = account.ID
+	}
+	s.recordAudit(r.Context(), AdminAction{
+		AdminID:  adminID,
+		Action:   "login",
+		Resource: creds.Username,
+		Status:   status,
+	})
+
+	if status != "SUCCESS" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"Invalid username or password"}`))
+		return
+	}
+
+	token, err := s.sessions.Create(account.ID, account.Username, account.Role)
+	if err != nil {
+		log.Printf("Failed to create session for %s: %v", account.Username, err)
+		http.Error(w, `{"error":"Internal error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     "admin_session",
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   false,
+		MaxAge:   int(sessionTTL.Seconds()),
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"ok"}`))
+}
+
+// GET /api/admin/slo - error budget remaining per configured service SLO.
+func (s *AdminServer) handleSLO(w http.ResponseWriter, r *http.Request) {
+	statuses := make([]ErrorBudgetStatus, 0, len(defaultSLOTargets))
+	for _, target := range defaultSLOTargets {
+		status, err := s.ErrorBudget(r.Context(), target)
+		if err != nil {
+			log.Printf("Failed to compute error budget: %v", err)
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statuses)
+}
+
+// POST /api/admin/policies - hot-reload RBAC policy documents from disk.
+// Gated to super_admin by the "policies:Reload" permission wired up in
+// main().
+func (s *AdminServer) handlePoliciesReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := s.policies.Reload(); err != nil {
+		log.Printf("Failed to reload policies: %v", err)
+		http.Error(w, "Failed to reload policies", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "reloaded"})
+}
+
+func main() {
+	// DB connection - kept open for the lifetime of the process since the
+	// admin audit log persists to it.
+	db, err := getDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Database not reachable: %v", err)
+	}
+	log.Println("Connected to Postgres DB successfully.")
+	defer db.Close()
+
+	policiesDir := os.Getenv("ADMIN_POLICIES_DIR")
+	if policiesDir == "" {
+		policiesDir = "./policies"
+	}
+
+	server, err := NewAdminServer(db, policiesDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize admin server: %v", err)
+	}
+
+	// Start metrics collection in background; it publishes to server.hub
+	// directly, so there's no separate broadcast loop to start.
+	go server.CollectMetrics(context.Background())
+
+	// HTTP routes. "/health" and "/api/admin/health" stay open for
+	// load-balancer health checks; every other admin endpoint requires a
+	// permission grant from the authenticated admin's role policy.
+	http.HandleFunc("/health", server.handleHealth)
+	http.HandleFunc("/api/admin/health", server.handleHealth)
+	http.HandleFunc("/api/admin/metrics", server.RequirePermission("metrics:View", staticResource("*"), server.handleMetrics))
+	http.HandleFunc("/api/admin/services", server.RequirePermission("metrics:View", staticResource("*"), server.handleServices))
+	http.HandleFunc("/api/admin/alerts", server.RequirePermission("alerts:View", staticResource("*"), server.handleAlerts))
+	http.HandleFunc("/api/admin/audit-log", server.RequirePermission("audit:View", staticResource("*"), server.handleAuditLog))
+	http.HandleFunc("/api/admin/audit-log/verify", server.RequirePermission("audit:Verify", staticResource("*"), server.handleAuditLogVerify))
+	http.HandleFunc("/api/admin/slo", server.RequirePermission("slo:View", staticResource("*"), server.handleSLO))
+	http.HandleFunc("/api/admin/ws/stats", server.RequirePermission("metrics:View", staticResource("*"), server.handleWSStats))
+	http.HandleFunc("/api/admin/alerts/config", server.RequirePermission("alerts:Configure", staticResource("*"), server.handleAlertConfig))
+	http.HandleFunc("/api/admin/services/restart", server.RequirePermission("services:Restart", serviceResource, server.handleRestartService))
+	http.HandleFunc("/api/admin/actions/", server.RequirePermission("actions:View", staticResource("*"), server.handleActionStatus))
+	http.HandleFunc("/api/admin/policies", server.RequirePermission("policies:Reload", staticResource("*"), server.handlePoliciesReload))
+	http.HandleFunc("/api/admin/login", server.handleLogin)
+
+	// WebSocket
+	http.HandleFunc("/live/metrics", server.HandleWebSocket)
+
+	// Static files
+	http.Handle("/", http.FileServer(http.Dir("./web/admin")))
+
+	addr := fmt.Sprintf(":%d", PORT)
+	log.Printf("KIKI Super-Admin listening on %s", addr)
+	log.Printf("Dashboard: http://localhost:%d", PORT)
+
+	// Graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		if err := http.ListenAndServe(addr, nil); err != nil && err != http.ErrServerClosed {
+			log.Printf("Server error: %v", err)
+		}
+	}()
+
+	<-sigChan
+	log.Println("Admin API shutting down...")
+}
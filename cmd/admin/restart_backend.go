@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/docker/docker/client"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/user/kiki-agent/cmd/admin/restarter"
+)
+
+// restartMinInterval and restartMaxConcurrent bound how aggressively the
+// restart executor can be driven: no more than one restart per service
+// every 5 minutes, and no more than 3 restarts in flight across every
+// service at once, so a flapping health check can't cascade into a
+// fleet-wide restart storm.
+const (
+	restartMinInterval   = 5 * time.Minute
+	restartMaxConcurrent = 3
+
+	noopRestartDelay = 2 * time.Second
+)
+
+// newRestartExecutor selects a restarter.Executor backend by name:
+// "docker" talks to the local Docker daemon, "k8s" talks to the
+// in-cluster (or $KUBECONFIG) Kubernetes API, and anything else - the
+// default - simulates restarts without touching any real infrastructure,
+// for local development.
+func newRestartExecutor(backend string, observer restarter.Observer) (restarter.Executor, error) {
+	limiter := restarter.NewLimiter(restartMinInterval, restartMaxConcurrent)
+
+	switch backend {
+	case "docker":
+		cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+		if err != nil {
+			return nil, fmt.Errorf("admin: connecting to Docker: %w", err)
+		}
+		return restarter.NewDockerExecutor(cli, limiter, observer), nil
+
+	case "k8s":
+		config, err := rest.InClusterConfig()
+		if err != nil {
+			config, err = clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+			if err != nil {
+				return nil, fmt.Errorf("admin: loading Kubernetes config: %w", err)
+			}
+		}
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("admin: connecting to Kubernetes: %w", err)
+		}
+		namespace := os.Getenv("RESTART_K8S_NAMESPACE")
+		if namespace == "" {
+			namespace = "default"
+		}
+		return restarter.NewKubernetesExecutor(clientset, namespace, limiter, observer), nil
+
+	default:
+		return restarter.NewNoopExecutor(limiter, observer, noopRestartDelay), nil
+	}
+}
@@ -0,0 +1,71 @@
+package restarter
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// dockerServiceLabel is the container label DockerExecutor looks up a
+// service's container by, e.g. "kiki.service=syncshield".
+const dockerServiceLabel = "kiki.service"
+
+// DockerExecutor restarts a service by finding the container labeled
+// dockerServiceLabel=<service> and issuing a ContainerRestart against it -
+// the same operation as `docker restart`.
+type DockerExecutor struct {
+	cli      *client.Client
+	limiter  *Limiter
+	tracker  *tracker
+	observer Observer
+}
+
+// NewDockerExecutor wraps cli as an Executor.
+func NewDockerExecutor(cli *client.Client, limiter *Limiter, observer Observer) *DockerExecutor {
+	return &DockerExecutor{cli: cli, limiter: limiter, tracker: newTracker(), observer: observer}
+}
+
+func (e *DockerExecutor) Restart(ctx context.Context, service string) (string, error) {
+	if err := e.limiter.Reserve(service); err != nil {
+		return "", err
+	}
+
+	actionID := newActionID()
+	e.tracker.set(actionID, StatePending)
+	e.observer(actionID, service, StatePending)
+
+	// The restart outlives the request that triggered it, so it runs
+	// against its own background context rather than ctx.
+	go func(bgCtx context.Context) {
+		defer e.limiter.Release()
+
+		e.tracker.set(actionID, StateInProgress)
+		e.observer(actionID, service, StateInProgress)
+
+		containers, err := e.cli.ContainerList(bgCtx, container.ListOptions{
+			Filters: filters.NewArgs(filters.Arg("label", dockerServiceLabel+"="+service)),
+		})
+		if err != nil || len(containers) == 0 {
+			e.tracker.set(actionID, StateFailed)
+			e.observer(actionID, service, StateFailed)
+			return
+		}
+
+		if err := e.cli.ContainerRestart(bgCtx, containers[0].ID, container.StopOptions{}); err != nil {
+			e.tracker.set(actionID, StateFailed)
+			e.observer(actionID, service, StateFailed)
+			return
+		}
+
+		e.tracker.set(actionID, StateSucceeded)
+		e.observer(actionID, service, StateSucceeded)
+	}(context.Background())
+
+	return actionID, nil
+}
+
+func (e *DockerExecutor) Status(actionID string) (State, error) {
+	return e.tracker.get(actionID)
+}
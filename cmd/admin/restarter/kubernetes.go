@@ -0,0 +1,71 @@
+package restarter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// KubernetesExecutor restarts a service by patching its Deployment's pod
+// template with a fresh "kubectl.kubernetes.io/restartedAt" annotation -
+// the same mechanism `kubectl rollout restart deployment/<name>` uses,
+// which rolls every pod over without changing the image or any other spec
+// field.
+type KubernetesExecutor struct {
+	clientset kubernetes.Interface
+	namespace string
+	limiter   *Limiter
+	tracker   *tracker
+	observer  Observer
+}
+
+// NewKubernetesExecutor wraps clientset as an Executor, restarting
+// Deployments named after the service in namespace.
+func NewKubernetesExecutor(clientset kubernetes.Interface, namespace string, limiter *Limiter, observer Observer) *KubernetesExecutor {
+	return &KubernetesExecutor{clientset: clientset, namespace: namespace, limiter: limiter, tracker: newTracker(), observer: observer}
+}
+
+func (e *KubernetesExecutor) Restart(ctx context.Context, service string) (string, error) {
+	if err := e.limiter.Reserve(service); err != nil {
+		return "", err
+	}
+
+	actionID := newActionID()
+	e.tracker.set(actionID, StatePending)
+	e.observer(actionID, service, StatePending)
+
+	// The restart outlives the request that triggered it, so it runs
+	// against its own background context rather than ctx.
+	go func(bgCtx context.Context) {
+		defer e.limiter.Release()
+
+		e.tracker.set(actionID, StateInProgress)
+		e.observer(actionID, service, StateInProgress)
+
+		patch := fmt.Sprintf(
+			`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":%q}}}}}`,
+			time.Now().Format(time.RFC3339),
+		)
+		_, err := e.clientset.AppsV1().Deployments(e.namespace).Patch(
+			bgCtx, service, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{},
+		)
+		if err != nil {
+			e.tracker.set(actionID, StateFailed)
+			e.observer(actionID, service, StateFailed)
+			return
+		}
+
+		e.tracker.set(actionID, StateSucceeded)
+		e.observer(actionID, service, StateSucceeded)
+	}(context.Background())
+
+	return actionID, nil
+}
+
+func (e *KubernetesExecutor) Status(actionID string) (State, error) {
+	return e.tracker.get(actionID)
+}
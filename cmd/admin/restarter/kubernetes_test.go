@@ -0,0 +1,61 @@
+package restarter
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKubernetesExecutor_RestartPatchesDeploymentAnnotation(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc-a", Namespace: "default"},
+	}
+	clientset := fake.NewSimpleClientset(deployment)
+	obs := &recordingObserver{}
+	e := NewKubernetesExecutor(clientset, "default", NewLimiter(0, 10), obs.observe)
+
+	actionID, err := e.Restart(context.Background(), "svc-a")
+	if err != nil {
+		t.Fatalf("Restart failed: %v", err)
+	}
+	waitForState(t, e, actionID, StateSucceeded)
+
+	got, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "svc-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if _, ok := got.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"]; !ok {
+		t.Fatalf("expected the restartedAt annotation to be set, got %+v", got.Spec.Template.Annotations)
+	}
+}
+
+func TestKubernetesExecutor_RestartFailsForUnknownDeployment(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	obs := &recordingObserver{}
+	e := NewKubernetesExecutor(clientset, "default", NewLimiter(0, 10), obs.observe)
+
+	actionID, err := e.Restart(context.Background(), "does-not-exist")
+	if err != nil {
+		t.Fatalf("Restart failed: %v", err)
+	}
+	waitForState(t, e, actionID, StateFailed)
+}
+
+func TestKubernetesExecutor_RestartReleasesLimiterSlotOnFailure(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	limiter := NewLimiter(0, 1)
+	e := NewKubernetesExecutor(clientset, "default", limiter, func(string, string, State) {})
+
+	actionID, err := e.Restart(context.Background(), "does-not-exist")
+	if err != nil {
+		t.Fatalf("Restart failed: %v", err)
+	}
+	waitForState(t, e, actionID, StateFailed)
+
+	if err := limiter.Reserve("svc-b"); err != nil {
+		t.Fatalf("expected the limiter slot to be released even after a failed restart, got %v", err)
+	}
+}
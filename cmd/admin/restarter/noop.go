@@ -0,0 +1,55 @@
+package restarter
+
+import (
+	"context"
+	"time"
+)
+
+// NoopExecutor transitions a restart from pending through in_progress to
+// succeeded after a simulated delay, without touching any real infrastructure.
+// Selected via RESTART_BACKEND=noop (the default) for local development.
+type NoopExecutor struct {
+	limiter  *Limiter
+	tracker  *tracker
+	observer Observer
+	delay    time.Duration
+}
+
+// NewNoopExecutor returns a NoopExecutor that simulates each restart
+// taking delay to complete.
+func NewNoopExecutor(limiter *Limiter, observer Observer, delay time.Duration) *NoopExecutor {
+	return &NoopExecutor{limiter: limiter, tracker: newTracker(), observer: observer, delay: delay}
+}
+
+func (e *NoopExecutor) Restart(ctx context.Context, service string) (string, error) {
+	if err := e.limiter.Reserve(service); err != nil {
+		return "", err
+	}
+
+	actionID := newActionID()
+	e.tracker.set(actionID, StatePending)
+	e.observer(actionID, service, StatePending)
+
+	// The restart outlives the request that triggered it, so it runs
+	// against its own background context rather than ctx.
+	go func(bgCtx context.Context) {
+		defer e.limiter.Release()
+
+		e.tracker.set(actionID, StateInProgress)
+		e.observer(actionID, service, StateInProgress)
+
+		select {
+		case <-time.After(e.delay):
+		case <-bgCtx.Done():
+		}
+
+		e.tracker.set(actionID, StateSucceeded)
+		e.observer(actionID, service, StateSucceeded)
+	}(context.Background())
+
+	return actionID, nil
+}
+
+func (e *NoopExecutor) Status(actionID string) (State, error) {
+	return e.tracker.get(actionID)
+}
@@ -0,0 +1,94 @@
+package restarter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingObserver records every state transition NewNoopExecutor/
+// DockerExecutor/KubernetesExecutor report, for asserting ordering.
+type recordingObserver struct {
+	mu   sync.Mutex
+	seen []State
+}
+
+func (o *recordingObserver) observe(actionID, service string, state State) {
+	o.mu.Lock()
+	o.seen = append(o.seen, state)
+	o.mu.Unlock()
+}
+
+func (o *recordingObserver) states() []State {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]State(nil), o.seen...)
+}
+
+func waitForState(t *testing.T, e Executor, actionID string, want State) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		got, err := e.Status(actionID)
+		if err != nil {
+			t.Fatalf("Status failed: %v", err)
+		}
+		if got == want {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for state %v", want)
+}
+
+func TestNoopExecutor_RestartTransitionsToSucceeded(t *testing.T) {
+	obs := &recordingObserver{}
+	e := NewNoopExecutor(NewLimiter(0, 10), obs.observe, time.Millisecond)
+
+	actionID, err := e.Restart(context.Background(), "svc-a")
+	if err != nil {
+		t.Fatalf("Restart failed: %v", err)
+	}
+
+	waitForState(t, e, actionID, StateSucceeded)
+
+	seen := obs.states()
+	if len(seen) != 3 || seen[0] != StatePending || seen[1] != StateInProgress || seen[2] != StateSucceeded {
+		t.Fatalf("expected [pending in_progress succeeded], got %v", seen)
+	}
+}
+
+func TestNoopExecutor_RestartReleasesLimiterSlot(t *testing.T) {
+	limiter := NewLimiter(0, 1)
+	e := NewNoopExecutor(limiter, func(string, string, State) {}, time.Millisecond)
+
+	actionID, err := e.Restart(context.Background(), "svc-a")
+	if err != nil {
+		t.Fatalf("Restart failed: %v", err)
+	}
+	waitForState(t, e, actionID, StateSucceeded)
+
+	if err := limiter.Reserve("svc-b"); err != nil {
+		t.Fatalf("expected the limiter slot to be released once the restart completed, got %v", err)
+	}
+}
+
+func TestNoopExecutor_RestartReturnsErrorWhenRateLimited(t *testing.T) {
+	limiter := NewLimiter(time.Minute, 10)
+	e := NewNoopExecutor(limiter, func(string, string, State) {}, time.Millisecond)
+
+	if _, err := e.Restart(context.Background(), "svc-a"); err != nil {
+		t.Fatalf("first Restart failed: %v", err)
+	}
+	if _, err := e.Restart(context.Background(), "svc-a"); err != ErrRateLimited {
+		t.Fatalf("expected ErrRateLimited for a repeat Restart, got %v", err)
+	}
+}
+
+func TestNoopExecutor_StatusUnknownActionID(t *testing.T) {
+	e := NewNoopExecutor(NewLimiter(0, 10), func(string, string, State) {}, time.Millisecond)
+	if _, err := e.Status("does-not-exist"); err != ErrUnknownAction {
+		t.Fatalf("expected ErrUnknownAction, got %v", err)
+	}
+}
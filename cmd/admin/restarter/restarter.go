@@ -0,0 +1,136 @@
+// Package restarter restarts a named service through a pluggable backend
+// (Docker, Kubernetes, or a no-op for local development) and tracks each
+// restart's progress so callers can poll or subscribe to state
+// transitions instead of blocking on the restart itself.
+package restarter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// State is a restart action's lifecycle stage.
+type State string
+
+const (
+	StatePending    State = "pending"
+	StateInProgress State = "in_progress"
+	StateSucceeded  State = "succeeded"
+	StateFailed     State = "failed"
+)
+
+// ErrRateLimited is returned by Restart when service was restarted too
+// recently.
+var ErrRateLimited = errors.New("restarter: service restarted too recently")
+
+// ErrConcurrencyLimit is returned by Restart when the global number of
+// in-flight restarts is already at its cap.
+var ErrConcurrencyLimit = errors.New("restarter: too many restarts in flight")
+
+// ErrUnknownAction is returned by Status for an actionID Restart never
+// issued.
+var ErrUnknownAction = errors.New("restarter: unknown action id")
+
+// Executor restarts a named service and reports on an in-flight restart's
+// progress. Restart returns as soon as the restart has been accepted -
+// implementations drive the actual state transitions in a background
+// goroutine, since HTTP and WebSocket callers expect a sub-second
+// response.
+type Executor interface {
+	// Restart begins restarting service and returns an opaque action ID
+	// future Status calls use to track progress.
+	Restart(ctx context.Context, service string) (actionID string, err error)
+	// Status reports the current state of a previously started restart.
+	Status(actionID string) (State, error)
+}
+
+// Observer is called whenever an Executor transitions a restart's state,
+// so callers (e.g. the admin API's WebSocket Hub and audit log) can react
+// without the executor needing to know about either.
+type Observer func(actionID, service string, state State)
+
+// Limiter enforces a per-service minimum interval between restarts and a
+// global cap on how many restarts may be in flight at once, so a flapping
+// health check - or an operator fat-fingering the dashboard - can't
+// trigger cascading restarts across the fleet.
+type Limiter struct {
+	mu          sync.Mutex
+	minInterval time.Duration
+	maxInFlight int
+	lastRestart map[string]time.Time
+	inFlight    int
+}
+
+// NewLimiter returns a Limiter enforcing minInterval between restarts of
+// the same service and maxInFlight concurrent restarts across all
+// services.
+func NewLimiter(minInterval time.Duration, maxInFlight int) *Limiter {
+	return &Limiter{
+		minInterval: minInterval,
+		maxInFlight: maxInFlight,
+		lastRestart: make(map[string]time.Time),
+	}
+}
+
+// Reserve claims a concurrency slot for service's restart, or returns
+// ErrRateLimited/ErrConcurrencyLimit if it can't. The caller must call
+// Release once the restart finishes, whether it succeeded or failed.
+func (l *Limiter) Reserve(service string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, ok := l.lastRestart[service]; ok && time.Since(last) < l.minInterval {
+		return ErrRateLimited
+	}
+	if l.inFlight >= l.maxInFlight {
+		return ErrConcurrencyLimit
+	}
+
+	l.lastRestart[service] = time.Now()
+	l.inFlight++
+	return nil
+}
+
+// Release frees the concurrency slot a prior successful Reserve claimed.
+func (l *Limiter) Release() {
+	l.mu.Lock()
+	l.inFlight--
+	l.mu.Unlock()
+}
+
+// tracker is the in-memory, mutex-guarded actionID->State map every
+// Executor implementation shares. Like cmd/admin's SessionStore, it
+// doesn't need to survive a restart of the admin process itself.
+type tracker struct {
+	mu     sync.RWMutex
+	states map[string]State
+}
+
+func newTracker() *tracker {
+	return &tracker{states: make(map[string]State)}
+}
+
+func (t *tracker) set(actionID string, state State) {
+	t.mu.Lock()
+	t.states[actionID] = state
+	t.mu.Unlock()
+}
+
+func (t *tracker) get(actionID string) (State, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	state, ok := t.states[actionID]
+	if !ok {
+		return "", ErrUnknownAction
+	}
+	return state, nil
+}
+
+// newActionID mints an opaque ID for a new restart action.
+func newActionID() string {
+	return ulid.Make().String()
+}
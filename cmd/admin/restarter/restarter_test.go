@@ -0,0 +1,64 @@
+package restarter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter_ReserveEnforcesMinInterval(t *testing.T) {
+	l := NewLimiter(time.Minute, 10)
+	if err := l.Reserve("svc-a"); err != nil {
+		t.Fatalf("first Reserve failed: %v", err)
+	}
+	if err := l.Reserve("svc-a"); err != ErrRateLimited {
+		t.Fatalf("expected ErrRateLimited for a repeat Reserve within minInterval, got %v", err)
+	}
+	if err := l.Reserve("svc-b"); err != nil {
+		t.Fatalf("expected a different service to be unaffected, got %v", err)
+	}
+}
+
+func TestLimiter_ReserveEnforcesMaxInFlight(t *testing.T) {
+	l := NewLimiter(0, 1)
+	if err := l.Reserve("svc-a"); err != nil {
+		t.Fatalf("first Reserve failed: %v", err)
+	}
+	if err := l.Reserve("svc-b"); err != ErrConcurrencyLimit {
+		t.Fatalf("expected ErrConcurrencyLimit once maxInFlight is reached, got %v", err)
+	}
+
+	l.Release()
+	if err := l.Reserve("svc-b"); err != nil {
+		t.Fatalf("expected Reserve to succeed after Release freed a slot, got %v", err)
+	}
+}
+
+func TestTracker_GetUnknownActionID(t *testing.T) {
+	tr := newTracker()
+	if _, err := tr.get("does-not-exist"); err != ErrUnknownAction {
+		t.Fatalf("expected ErrUnknownAction, got %v", err)
+	}
+}
+
+func TestTracker_SetThenGet(t *testing.T) {
+	tr := newTracker()
+	tr.set("action-1", StateInProgress)
+	got, err := tr.get("action-1")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if got != StateInProgress {
+		t.Fatalf("expected StateInProgress, got %v", got)
+	}
+}
+
+func TestNewActionID_ReturnsUniqueIDs(t *testing.T) {
+	a := newActionID()
+	b := newActionID()
+	if a == "" || b == "" {
+		t.Fatal("expected non-empty action IDs")
+	}
+	if a == b {
+		t.Fatalf("expected distinct action IDs, got %q twice", a)
+	}
+}
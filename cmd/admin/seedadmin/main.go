@@ -0,0 +1,90 @@
+// Command seedadmin creates an admin account in the admins Postgres
+// table, hashing the supplied password with bcrypt. It's meant to be run
+// once against a fresh deployment (first-run seeding of the initial
+// super_admin) and again any time a new admin needs onboarding, since
+// there's no admin-management UI yet - POST /api/admin/policies only
+// hot-reloads RBAC policy documents, not admin accounts.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	_ "github.com/lib/pq"
+	"github.com/oklog/ulid/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// adminsSchema mirrors cmd/admin's own admins table definition - seedadmin
+// is a separate `package main` and can't import the admin binary's
+// internal AdminAccountStore, so the schema and the handful of queries it
+// needs are duplicated here.
+const adminsSchema = `
+CREATE TABLE IF NOT EXISTS admins (
+	id            TEXT PRIMARY KEY,
+	username      TEXT NOT NULL UNIQUE,
+	password_hash TEXT NOT NULL,
+	role          TEXT NOT NULL
+)`
+
+func main() {
+	username := flag.String("username", "", "admin username to create (required)")
+	password := flag.String("password", "", "admin password to hash and store (required)")
+	role := flag.String("role", "operator", "admin role: super_admin, manager, analyst, or operator")
+	flag.Parse()
+
+	if *username == "" || *password == "" {
+		fmt.Fprintln(os.Stderr, "usage: seedadmin -username=NAME -password=PASSWORD [-role=ROLE]")
+		os.Exit(1)
+	}
+
+	dsn := fmt.Sprintf("host=%s dbname=%s user=%s password=%s sslmode=disable",
+		os.Getenv("DB_HOST"), os.Getenv("DB_NAME"), os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"))
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Database not reachable: %v", err)
+	}
+	if _, err := db.Exec(adminsSchema); err != nil {
+		log.Fatalf("Failed to migrate admins table: %v", err)
+	}
+
+	ctx := context.Background()
+
+	var existingCount int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM admins WHERE username = $1`, *username).Scan(&existingCount); err != nil {
+		log.Fatalf("Failed to check for existing admin %s: %v", *username, err)
+	}
+	if existingCount > 0 {
+		log.Fatalf("Admin %s already exists; seedadmin only creates new accounts", *username)
+	}
+
+	var totalCount int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM admins`).Scan(&totalCount); err != nil {
+		log.Fatalf("Failed to count existing admins: %v", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Fatalf("Failed to hash password: %v", err)
+	}
+
+	_, err = db.ExecContext(ctx, `INSERT INTO admins (id, username, password_hash, role) VALUES ($1, $2, $3, $4)`,
+		ulid.Make().String(), *username, string(hash), *role)
+	if err != nil {
+		log.Fatalf("Failed to create admin %s: %v", *username, err)
+	}
+
+	if totalCount == 0 {
+		log.Printf("Seeded first admin %q with role %q", *username, *role)
+	} else {
+		log.Printf("Created admin %q with role %q", *username, *role)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// sessionTTL matches the admin_session cookie's previous MaxAge.
+const sessionTTL = time.Hour
+
+// AdminSession is an authenticated admin's session, looked up by the
+// opaque admin_session cookie value both handleLogin and RequirePermission
+// touch.
+type AdminSession struct {
+	AdminID  string
+	Username string
+	Role     string
+
+	expiresAt time.Time
+}
+
+// SessionStore holds active admin sessions in memory, keyed by token.
+// Like AdminServer.clients, it's a plain mutex-guarded map - sessions
+// don't need to survive a restart, only an in-flight browser tab.
+type SessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]AdminSession
+}
+
+// NewSessionStore returns an empty SessionStore.
+func NewSessionStore() *SessionStore {
+	return &SessionStore{sessions: make(map[string]AdminSession)}
+}
+
+// Create mints a new session token for the given admin.
+func (s *SessionStore) Create(adminID, username, role string) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", fmt.Errorf("admin: minting session token: %w", err)
+	}
+
+	s.mu.Lock()
+	s.sessions[token] = AdminSession{
+		AdminID:   adminID,
+		Username:  username,
+		Role:      role,
+		expiresAt: time.Now().Add(sessionTTL),
+	}
+	s.mu.Unlock()
+	return token, nil
+}
+
+// Lookup returns the session for token, if any and not expired.
+func (s *SessionStore) Lookup(token string) (AdminSession, bool) {
+	s.mu.RLock()
+	sess, ok := s.sessions[token]
+	s.mu.RUnlock()
+
+	if !ok || time.Now().After(sess.expiresAt) {
+		return AdminSession{}, false
+	}
+	return sess, true
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/prometheus/common/model"
+)
+
+// SLOTarget defines the availability objective for a service: Target
+// fraction of requests (e.g. 0.999 for "three nines") must succeed over
+// Window, e.g. a rolling 30 days.
+type SLOTarget struct {
+	Service string
+	Target  float64
+	Window  time.Duration
+}
+
+// defaultSLOTargets is the fallback set of objectives used until the admin
+// API grows a way to configure these per-deployment.
+var defaultSLOTargets = map[string]SLOTarget{
+	"syncshield": {Service: "syncshield", Target: 0.999, Window: 30 * 24 * time.Hour},
+	"syncengage": {Service: "syncengage", Target: 0.999, Window: 30 * 24 * time.Hour},
+	"syncflow":   {Service: "syncflow", Target: 0.995, Window: 30 * 24 * time.Hour},
+	"synccreate": {Service: "synccreate", Target: 0.995, Window: 30 * 24 * time.Hour},
+	"syncvalue":  {Service: "syncvalue", Target: 0.999, Window: 30 * 24 * time.Hour},
+}
+
+// burnRateWindows are the short/long window pairs the Google SRE workbook's
+// multi-window multi-burn-rate recipe alerts on. Both windows in a pair
+// must exceed BurnRate before that pair fires - the long window confirms
+// the burn is sustained, the short window confirms it's still ongoing
+// right now.
+var burnRateWindows = []struct {
+	Severity    string
+	LongWindow  time.Duration
+	ShortWindow time.Duration
+	BurnRate    float64
+}{
+	{Severity: "critical", LongWindow: time.Hour, ShortWindow: 5 * time.Minute, BurnRate: 14.4},
+	{Severity: "warning", LongWindow: 6 * time.Hour, ShortWindow: 30 * time.Minute, BurnRate: 6},
+}
+
+// ErrorBudgetStatus reports how much of a service's error budget, over its
+// full SLO window, remains unspent.
+type ErrorBudgetStatus struct {
+	Service         string    `json:"service"`
+	Target          float64   `json:"target"`
+	Window          string    `json:"window"`
+	ErrorRate       float64   `json:"error_rate"`
+	BudgetRemaining float64   `json:"budget_remaining"` // 1.0 = fully unspent, 0 = exhausted, negative = over budget
+	EvaluatedAt     time.Time `json:"evaluated_at"`
+}
+
+// evaluateBurnRateAlerts runs the multi-window multi-burn-rate recipe for
+// target, returning an AdminAlert for every window pair whose long and
+// short window burn rates both exceed its threshold.
+func (s *AdminServer) evaluateBurnRateAlerts(ctx context.Context, target SLOTarget) ([]AdminAlert, error) {
+	var alerts []AdminAlert
+	for _, w := range burnRateWindows {
+		longBurn, err := s.queryBurnRate(ctx, target, w.LongWindow)
+		if err != nil {
+			return nil, fmt.Errorf("admin: querying %s burn rate over %s: %w", target.Service, w.LongWindow, err)
+		}
+		shortBurn, err := s.queryBurnRate(ctx, target, w.ShortWindow)
+		if err != nil {
+			return nil, fmt.Errorf("admin: querying %s burn rate over %s: %w", target.Service, w.ShortWindow, err)
+		}
+
+		if longBurn > w.BurnRate && shortBurn > w.BurnRate {
+			alerts = append(alerts, AdminAlert{
+				ID:       ulid.Make().String(),
+				Severity: w.Severity,
+				Message: fmt.Sprintf("%s burning error budget %.1fx over %s and %.1fx over %s, exceeds %.1fx threshold",
+					target.Service, longBurn, w.LongWindow, shortBurn, w.ShortWindow, w.BurnRate),
+				Service:   target.Service,
+				Timestamp: time.Now(),
+			})
+		}
+	}
+	return alerts, nil
+}
+
+// queryBurnRate evaluates how many multiples of the sustainable error rate
+// target.Service actually consumed over window: 1.0 means burning the
+// budget exactly as fast as the SLO allows, 14.4 means the full 30d budget
+// would be exhausted in about two days at the current rate.
+func (s *AdminServer) queryBurnRate(ctx context.Context, target SLOTarget, window time.Duration) (float64, error) {
+	errorRate, err := s.queryScalar(ctx, errorRateQuery(target.Service, window))
+	if err != nil {
+		return 0, err
+	}
+	return errorRate / (1 - target.Target), nil
+}
+
+// ErrorBudget computes target's error budget status over its full SLO
+// window.
+func (s *AdminServer) ErrorBudget(ctx context.Context, target SLOTarget) (ErrorBudgetStatus, error) {
+	errorRate, err := s.queryScalar(ctx, errorRateQuery(target.Service, target.Window))
+	if err != nil {
+		return ErrorBudgetStatus{}, fmt.Errorf("admin: computing error budget for %s: %w", target.Service, err)
+	}
+
+	allowed := 1 - target.Target
+	return ErrorBudgetStatus{
+		Service:         target.Service,
+		Target:          target.Target,
+		Window:          target.Window.String(),
+		ErrorRate:       errorRate,
+		BudgetRemaining: 1 - errorRate/allowed,
+		EvaluatedAt:     time.Now(),
+	}, nil
+}
+
+// queryScalar runs a PromQL query expected to return a single instant
+// vector sample and returns its value, or 0 if Prometheus has no matching
+// series yet (e.g. a freshly deployed service with no traffic).
+func (s *AdminServer) queryScalar(ctx context.Context, query string) (float64, error) {
+	value, _, err := s.prometheus.Query(ctx, query, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("admin: prometheus query %q: %w", query, err)
+	}
+	vector, ok := value.(model.Vector)
+	if !ok || len(vector) == 0 {
+		return 0, nil
+	}
+	return float64(vector[0].Value), nil
+}
+
+// requestsPerSecQuery returns the PromQL for service's current request
+// rate.
+func requestsPerSecQuery(service string) string {
+	return fmt.Sprintf(`sum(rate(http_requests_total{service=%q}[1m]))`, service)
+}
+
+// errorRateQuery returns the PromQL for the fraction of service's requests
+// that returned 5xx over window.
+func errorRateQuery(service string, window time.Duration) string {
+	return fmt.Sprintf(
+		`sum(rate(http_requests_total{service=%q,code=~"5.."}[%s])) / sum(rate(http_requests_total{service=%q}[%s]))`,
+		service, promDuration(window), service, promDuration(window),
+	)
+}
+
+// latencyQuantileQuery returns the PromQL for service's quantile (e.g. 0.5,
+// 0.95, 0.99) request latency, derived from its latency histogram buckets.
+func latencyQuantileQuery(service string, quantile float64) string {
+	return fmt.Sprintf(
+		`histogram_quantile(%g, sum(rate(http_request_duration_seconds_bucket{service=%q}[5m])) by (le))`,
+		quantile, service,
+	)
+}
+
+// promDuration formats d as a PromQL range-vector duration. time.Duration's
+// own String() would render 6*time.Hour as "6h0m0s", which Prometheus
+// parses but which is needlessly noisy next to the "6h"/"5m" windows this
+// package actually queries with.
+func promDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", int64(d/time.Hour))
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", int64(d/time.Minute))
+	default:
+		return d.String()
+	}
+}
@@ -0,0 +1,193 @@
+// Command kiki-ca issues the short-lived client/server certificates
+// security/mtls verifies - a minimal local CA for development and
+// single-operator deployments. A production rollout would swap this for
+// Vault's PKI secrets engine or a Kubernetes cert-manager Issuer (see
+// mtls.VaultTrustStore/K8sSecretTrustStore), reusing the same CA bundle
+// format either way.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+)
+
+const (
+	caKeyBits = 4096
+	caCertTTL = 10 * 365 * 24 * time.Hour
+
+	leafKeyBits = 2048
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "init":
+		err = runInit(os.Args[2:])
+	case "issue":
+		err = runIssue(os.Args[2:])
+	default:
+		usage()
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kiki-ca: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: kiki-ca init [--out-dir=.]")
+	fmt.Fprintln(os.Stderr, "       kiki-ca issue --cn=<name> --ttl=<duration> [--out-dir=.] [--ca-dir=.]")
+}
+
+// runInit generates a self-signed CA key/cert pair, written as
+// <out-dir>/ca.key and <out-dir>/ca.crt.
+func runInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	outDir := fs.String("out-dir", ".", "directory to write ca.key/ca.crt into")
+	commonName := fs.String("cn", "kiki-agent-ca", "CA certificate Subject Common Name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, caKeyBits)
+	if err != nil {
+		return fmt.Errorf("generating CA key: %w", err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: *commonName},
+		NotBefore:             time.Now().Add(-5 * time.Minute),
+		NotAfter:              time.Now().Add(caCertTTL),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("creating CA certificate: %w", err)
+	}
+
+	if err := writeKeyPair(*outDir, "ca", key, der); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s/ca.key and %s/ca.crt (CN=%s, valid %s)\n", *outDir, *outDir, *commonName, caCertTTL)
+	return nil
+}
+
+// runIssue signs a short-lived leaf certificate for --cn off the CA in
+// --ca-dir, written as <out-dir>/<cn>.key and <out-dir>/<cn>.crt.
+func runIssue(args []string) error {
+	fs := flag.NewFlagSet("issue", flag.ExitOnError)
+	commonName := fs.String("cn", "", "leaf certificate Subject Common Name (required)")
+	ttl := fs.Duration("ttl", 24*time.Hour, "leaf certificate validity, e.g. 24h")
+	outDir := fs.String("out-dir", ".", "directory to write <cn>.key/<cn>.crt into")
+	caDir := fs.String("ca-dir", ".", "directory containing ca.key/ca.crt")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *commonName == "" {
+		return fmt.Errorf("--cn is required")
+	}
+
+	caCert, caKey, err := loadCA(*caDir)
+	if err != nil {
+		return fmt.Errorf("loading CA from %s: %w", *caDir, err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, leafKeyBits)
+	if err != nil {
+		return fmt.Errorf("generating leaf key: %w", err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: *commonName},
+		NotBefore:    time.Now().Add(-5 * time.Minute),
+		NotAfter:     time.Now().Add(*ttl),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return fmt.Errorf("signing leaf certificate: %w", err)
+	}
+
+	if err := writeKeyPair(*outDir, *commonName, key, der); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s/%s.key and %s/%s.crt (CN=%s, valid %s)\n", *outDir, *commonName, *outDir, *commonName, *commonName, *ttl)
+	return nil
+}
+
+func loadCA(caDir string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	certPEM, err := os.ReadFile(caDir + "/ca.crt")
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := os.ReadFile(caDir + "/ca.key")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, fmt.Errorf("ca.crt is not valid PEM")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing ca.crt: %w", err)
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("ca.key is not valid PEM")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing ca.key: %w", err)
+	}
+	return cert, key, nil
+}
+
+func writeKeyPair(dir, name string, key *rsa.PrivateKey, certDER []byte) error {
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(dir+"/"+name+".key", keyPEM, 0600); err != nil {
+		return fmt.Errorf("writing %s.key: %w", name, err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	if err := os.WriteFile(dir+"/"+name+".crt", certPEM, 0644); err != nil {
+		return fmt.Errorf("writing %s.crt: %w", name, err)
+	}
+	return nil
+}
+
+func randomSerial() (*big.Int, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	return rand.Int(rand.Reader, limit)
+}
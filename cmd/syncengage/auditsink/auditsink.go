@@ -0,0 +1,63 @@
+// Package auditsink replaces SyncEngage's original single-file CSV audit
+// trail (opened once in append mode, fsynced on every write from inside the
+// request handler) with a pluggable Sink: the same record can land in a
+// rotating/gzipped CSV, a Postgres table, or a Kafka topic depending on
+// config, and every Sink is wrapped in a bounded, asynchronously-flushed
+// buffer so a slow or down destination can't stall triggerRetentionHandler.
+package auditsink
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Record is a retention trigger as written to an audit sink. It mirrors
+// main.RetentionTrigger's fields without importing package main, so callers
+// convert at the boundary.
+type Record struct {
+	CustomerID   string
+	TriggerType  string
+	Action       string
+	Message      string
+	DiscountPct  float64
+	PredictedLTV float64
+	ExecutedAt   time.Time
+}
+
+// Sink persists Records somewhere durable. Write must be safe to call from
+// concurrent goroutines.
+type Sink interface {
+	Write(ctx context.Context, rec Record) error
+	Close() error
+}
+
+// NewSink builds the Sink selected by kind ("csv", "postgres", or "kafka"),
+// reading that implementation's own config from env vars the same way
+// ltvResolver and shieldclient.NewClient do.
+func NewSink(kind string) (Sink, error) {
+	switch kind {
+	case "", "csv":
+		path := os.Getenv("AUDIT_CSV_PATH")
+		if path == "" {
+			path = "syncengage_audit.csv"
+		}
+		return NewRotatingCSVSink(path)
+	case "postgres":
+		dsn := os.Getenv("AUDIT_POSTGRES_DSN")
+		if dsn == "" {
+			return nil, fmt.Errorf("auditsink: AUDIT_POSTGRES_DSN is required for kind=postgres")
+		}
+		return NewPostgresSink(dsn)
+	case "kafka":
+		proxyURL := os.Getenv("AUDIT_KAFKA_REST_PROXY_URL")
+		topic := os.Getenv("AUDIT_KAFKA_TOPIC")
+		if proxyURL == "" || topic == "" {
+			return nil, fmt.Errorf("auditsink: AUDIT_KAFKA_REST_PROXY_URL and AUDIT_KAFKA_TOPIC are required for kind=kafka")
+		}
+		return NewKafkaRESTSink(proxyURL, topic), nil
+	default:
+		return nil, fmt.Errorf("auditsink: unknown sink kind %q", kind)
+	}
+}
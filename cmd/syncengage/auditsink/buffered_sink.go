@@ -0,0 +1,110 @@
+package auditsink
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+)
+
+// defaultBufferSize bounds how many Records can queue ahead of the
+// background flusher before Write starts dropping them, the same
+// drop-rather-than-block tradeoff analytics.HTTPRecorder makes for a slow
+// event endpoint.
+const defaultBufferSize = 1000
+
+// Stats is a point-in-time snapshot of a BufferedSink's counters, surfaced
+// on /health.
+type Stats struct {
+	Pending int64
+	Dropped int64
+	Flushed int64
+}
+
+// BufferedSink wraps an underlying Sink with a bounded channel and a
+// background goroutine that drains it, so a slow or unreachable destination
+// degrades to dropped audit records instead of blocking
+// triggerRetentionHandler or generateRetentionTrigger.
+type BufferedSink struct {
+	underlying Sink
+	queue      chan Record
+	done       chan struct{}
+
+	pending atomic.Int64
+	dropped atomic.Int64
+	flushed atomic.Int64
+}
+
+// NewBufferedSink wraps underlying with a channel of bufferSize capacity
+// (defaultBufferSize if bufferSize <= 0) and starts the background flusher.
+func NewBufferedSink(underlying Sink, bufferSize int) *BufferedSink {
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	b := &BufferedSink{
+		underlying: underlying,
+		queue:      make(chan Record, bufferSize),
+		done:       make(chan struct{}),
+	}
+	go b.flushLoop()
+	return b
+}
+
+// Write enqueues rec for the background flusher, or drops it and increments
+// Dropped if the buffer is full.
+func (b *BufferedSink) Write(ctx context.Context, rec Record) error {
+	select {
+	case b.queue <- rec:
+		b.pending.Add(1)
+		return nil
+	default:
+		b.dropped.Add(1)
+		return nil
+	}
+}
+
+// flushLoop drains the queue into the underlying Sink until Close stops it.
+func (b *BufferedSink) flushLoop() {
+	for {
+		select {
+		case rec := <-b.queue:
+			b.pending.Add(-1)
+			if err := b.underlying.Write(context.Background(), rec); err != nil {
+				log.Printf("⚠️ auditsink: write failed, record dropped: %v", err)
+				b.dropped.Add(1)
+				continue
+			}
+			b.flushed.Add(1)
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// Close stops the flusher, draining whatever is already queued, then closes
+// the underlying Sink.
+func (b *BufferedSink) Close() error {
+	close(b.done)
+	for {
+		select {
+		case rec := <-b.queue:
+			b.pending.Add(-1)
+			if err := b.underlying.Write(context.Background(), rec); err != nil {
+				log.Printf("⚠️ auditsink: write failed during drain, record dropped: %v", err)
+				b.dropped.Add(1)
+				continue
+			}
+			b.flushed.Add(1)
+		default:
+			return b.underlying.Close()
+		}
+	}
+}
+
+// Stats reports the current pending/dropped/flushed counters.
+func (b *BufferedSink) Stats() Stats {
+	return Stats{
+		Pending: b.pending.Load(),
+		Dropped: b.dropped.Load(),
+		Flushed: b.flushed.Load(),
+	}
+}
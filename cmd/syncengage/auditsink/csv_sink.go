@@ -0,0 +1,167 @@
+package auditsink
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultMaxBytes rotates the active segment once it crosses 64MB,
+	// regardless of age.
+	defaultMaxBytes = 64 * 1024 * 1024
+	// defaultMaxAge rotates the active segment once it's been open this
+	// long, regardless of size, so a quiet deployment still gets a fresh
+	// segment daily.
+	defaultMaxAge = 24 * time.Hour
+)
+
+var csvHeader = []string{"timestamp", "customer_id", "trigger_type", "action", "message", "discount_pct", "predicted_ltv"}
+
+// RotatingCSVSink is the original syncengage_audit.csv append-only log, made
+// durable: the active segment rolls over by size or age, and every rolled
+// segment is gzipped in place so a long-running deployment doesn't grow one
+// unbounded file.
+type RotatingCSVSink struct {
+	path    string
+	maxSize int64
+	maxAge  time.Duration
+
+	mu       sync.Mutex
+	file     *os.File
+	writer   *csv.Writer
+	size     int64
+	openedAt time.Time
+}
+
+// NewRotatingCSVSink opens (or creates) path as the active segment, using
+// the package's default rotation thresholds.
+func NewRotatingCSVSink(path string) (*RotatingCSVSink, error) {
+	s := &RotatingCSVSink{
+		path:    path,
+		maxSize: defaultMaxBytes,
+		maxAge:  defaultMaxAge,
+	}
+	if err := s.openSegment(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// openSegment opens s.path in append mode, writing the header if it's new.
+func (s *RotatingCSVSink) openSegment() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("auditsink: opening %s: %w", s.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("auditsink: stat %s: %w", s.path, err)
+	}
+
+	s.file = f
+	s.writer = csv.NewWriter(f)
+	s.size = info.Size()
+	s.openedAt = time.Now()
+
+	if info.Size() == 0 {
+		if err := s.writer.Write(csvHeader); err != nil {
+			return err
+		}
+		s.writer.Flush()
+	}
+	return nil
+}
+
+// Write implements Sink, rotating the segment first if it's outgrown
+// s.maxSize or s.maxAge.
+func (s *RotatingCSVSink) Write(ctx context.Context, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size >= s.maxSize || time.Since(s.openedAt) >= s.maxAge {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	row := []string{
+		rec.ExecutedAt.Format(time.RFC3339),
+		rec.CustomerID,
+		rec.TriggerType,
+		rec.Action,
+		rec.Message,
+		fmt.Sprintf("%.2f", rec.DiscountPct),
+		fmt.Sprintf("%.2f", rec.PredictedLTV),
+	}
+	if err := s.writer.Write(row); err != nil {
+		return err
+	}
+	s.writer.Flush()
+	if err := s.writer.Error(); err != nil {
+		return err
+	}
+
+	info, err := s.file.Stat()
+	if err == nil {
+		s.size = info.Size()
+	}
+	return nil
+}
+
+// rotate closes the active segment, gzips it alongside the original under a
+// timestamped name, and opens a fresh segment at s.path.
+func (s *RotatingCSVSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	rotatedName := fmt.Sprintf("%s.%s", s.path, time.Now().Format("20060102T150405"))
+	if err := os.Rename(s.path, rotatedName); err != nil {
+		return err
+	}
+	if err := gzipFile(rotatedName); err != nil {
+		return err
+	}
+
+	return s.openSegment()
+}
+
+// gzipFile compresses src to src+".gz" and removes src.
+func gzipFile(src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(src + ".gz")
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(src)
+}
+
+// Close flushes and closes the active segment.
+func (s *RotatingCSVSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writer.Flush()
+	return s.file.Close()
+}
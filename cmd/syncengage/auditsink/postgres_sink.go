@@ -0,0 +1,63 @@
+package auditsink
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq" // PostgreSQL driver, already used by crm.PostgreSQLConnector
+)
+
+// retentionAuditSchema creates the table PostgresSink writes to if it
+// doesn't already exist, so a fresh environment doesn't need a separate
+// migration step before SyncEngage can start.
+const retentionAuditSchema = `
+CREATE TABLE IF NOT EXISTS retention_audit (
+	id            SERIAL PRIMARY KEY,
+	customer_id   TEXT NOT NULL,
+	trigger_type  TEXT NOT NULL,
+	action        TEXT NOT NULL,
+	message       TEXT NOT NULL,
+	discount_pct  NUMERIC NOT NULL,
+	predicted_ltv NUMERIC NOT NULL,
+	executed_at   TIMESTAMPTZ NOT NULL
+)`
+
+// PostgresSink writes Records to a retention_audit table, the same
+// database/sql + lib/pq pairing crm.PostgreSQLConnector uses.
+type PostgresSink struct {
+	db *sql.DB
+}
+
+// NewPostgresSink opens dsn and ensures the retention_audit table exists.
+func NewPostgresSink(dsn string) (*PostgresSink, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("auditsink: opening postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("auditsink: pinging postgres: %w", err)
+	}
+	if _, err := db.Exec(retentionAuditSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("auditsink: migrating retention_audit: %w", err)
+	}
+	return &PostgresSink{db: db}, nil
+}
+
+// Write implements Sink.
+func (p *PostgresSink) Write(ctx context.Context, rec Record) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO retention_audit
+			(customer_id, trigger_type, action, message, discount_pct, predicted_ltv, executed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		rec.CustomerID, rec.TriggerType, rec.Action, rec.Message, rec.DiscountPct, rec.PredictedLTV, rec.ExecutedAt,
+	)
+	return err
+}
+
+// Close implements Sink.
+func (p *PostgresSink) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,37 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// DedupStore suppresses re-firing the same customer_id/trigger_type pair
+// within a window, backed by a Redis key with a TTL so a replica restart -
+// or a different replica owning this customer on the next poll under
+// ModeShard - doesn't re-fire a trigger that already went out recently.
+type DedupStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewDedupStore creates a store whose keys are prefixed with prefix (e.g.
+// "kiki:syncengage:dedup:") to avoid colliding with other uses of the same
+// Redis instance.
+func NewDedupStore(client *redis.Client, prefix string) *DedupStore {
+	return &DedupStore{client: client, prefix: prefix}
+}
+
+// ShouldFire atomically claims the dedup token for customerID/triggerType,
+// valid for window, and reports whether this call won the claim (true) or
+// a token was already outstanding (false).
+func (d *DedupStore) ShouldFire(ctx context.Context, customerID, triggerType string, window time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s%s:%s", d.prefix, customerID, triggerType)
+	claimed, err := d.client.SetNX(ctx, key, time.Now().Format(time.RFC3339), window).Result()
+	if err != nil {
+		return false, fmt.Errorf("coordinator: dedup check for %s failed: %w", key, err)
+	}
+	return claimed, nil
+}
@@ -0,0 +1,93 @@
+package coordinator
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// hashRingVirtualNodes is how many points on the ring each member gets.
+// More virtual nodes means a more even split of customer_id keys across a
+// small replica count, at the cost of a bit more memory per member.
+const hashRingVirtualNodes = 100
+
+// HashRing assigns keys (customer_id, here) to members via consistent
+// hashing, so adding or removing a replica only reshuffles the keys nearest
+// to it on the ring rather than the entire customer set the way a plain
+// hash % replicaCount split would.
+type HashRing struct {
+	mu      sync.RWMutex
+	points  map[uint32]string
+	sorted  []uint32
+	members map[string]bool
+}
+
+// NewHashRing creates an empty ring.
+func NewHashRing() *HashRing {
+	return &HashRing{
+		points:  make(map[uint32]string),
+		members: make(map[string]bool),
+	}
+}
+
+// Add places member's virtual nodes on the ring. A no-op if member is
+// already present.
+func (r *HashRing) Add(member string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.members[member] {
+		return
+	}
+	r.members[member] = true
+	for i := 0; i < hashRingVirtualNodes; i++ {
+		point := ringHash(fmt.Sprintf("%s#%d", member, i))
+		r.points[point] = member
+		r.sorted = append(r.sorted, point)
+	}
+	sort.Slice(r.sorted, func(i, j int) bool { return r.sorted[i] < r.sorted[j] })
+}
+
+// Remove takes member's virtual nodes off the ring. A no-op if member isn't
+// present.
+func (r *HashRing) Remove(member string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.members[member] {
+		return
+	}
+	delete(r.members, member)
+
+	kept := r.sorted[:0]
+	for _, point := range r.sorted {
+		if r.points[point] == member {
+			delete(r.points, point)
+			continue
+		}
+		kept = append(kept, point)
+	}
+	r.sorted = kept
+}
+
+// Owner returns the member responsible for key, or "" if the ring has no
+// members yet.
+func (r *HashRing) Owner(key string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.sorted) == 0 {
+		return ""
+	}
+
+	point := ringHash(key)
+	idx := sort.Search(len(r.sorted), func(i int) bool { return r.sorted[i] >= point })
+	if idx == len(r.sorted) {
+		idx = 0
+	}
+	return r.points[r.sorted[idx]]
+}
+
+func ringHash(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
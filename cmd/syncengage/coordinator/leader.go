@@ -0,0 +1,114 @@
+package coordinator
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	defaultLeaseTTL      = 15 * time.Second
+	defaultRenewInterval = 5 * time.Second
+
+	// watchStaleAfter is how long a leader can go without a successful
+	// renewal before LeaderElector treats its own view of the lease as
+	// unhealthy and steps down to re-acquire from scratch - the Redis
+	// analogue of the ~60s unhealthy-watch detection an etcd lease
+	// keep-alive loop uses before re-establishing its watch.
+	watchStaleAfter = 60 * time.Second
+)
+
+// renewScript extends the lease only if this replica still holds it,
+// closing the race where a lease expires, another replica wins it, and the
+// original renewer would otherwise blindly re-extend someone else's lease.
+var renewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+else
+	return 0
+end
+`)
+
+// LeaderElector elects a single leader among SyncEngage replicas via a
+// Redis lease, the same cross-replica coordination primitive
+// budget.RedisSpendStore and SyncShield's budget limiter already use,
+// rather than introducing etcd/clientv3 as a second dependency for this one
+// subsystem.
+type LeaderElector struct {
+	client    *redis.Client
+	key       string
+	replicaID string
+	leaseTTL  time.Duration
+
+	isLeader    atomic.Bool
+	lastRenewal atomic.Int64 // UnixNano of the last successful acquire/renew
+}
+
+// NewLeaderElector creates an elector for key, identifying this process as
+// replicaID.
+func NewLeaderElector(client *redis.Client, key, replicaID string) *LeaderElector {
+	return &LeaderElector{
+		client:    client,
+		key:       key,
+		replicaID: replicaID,
+		leaseTTL:  defaultLeaseTTL,
+	}
+}
+
+// Start launches the background acquire/renew loop; it runs until ctx is
+// canceled.
+func (e *LeaderElector) Start(ctx context.Context) {
+	go e.run(ctx)
+}
+
+func (e *LeaderElector) run(ctx context.Context) {
+	ticker := time.NewTicker(defaultRenewInterval)
+	defer ticker.Stop()
+	for {
+		e.tryAcquireOrRenew(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// tryAcquireOrRenew renews this replica's lease if it still holds one and
+// the last renewal isn't stale, otherwise attempts to acquire the lease
+// fresh via SET NX.
+func (e *LeaderElector) tryAcquireOrRenew(ctx context.Context) {
+	if e.isLeader.Load() {
+		if time.Since(time.Unix(0, e.lastRenewal.Load())) > watchStaleAfter {
+			log.Printf("⚠️ RetentionCoordinator: lease renewal stale for %s, stepping down to re-acquire", e.key)
+			e.isLeader.Store(false)
+		} else {
+			renewed, err := renewScript.Run(ctx, e.client, []string{e.key}, e.replicaID, e.leaseTTL.Milliseconds()).Result()
+			if err == nil && renewed != int64(0) {
+				e.lastRenewal.Store(time.Now().UnixNano())
+				return
+			}
+			log.Printf("⚠️ RetentionCoordinator: lost leadership of %s (renew failed: %v)", e.key, err)
+			e.isLeader.Store(false)
+		}
+	}
+
+	acquired, err := e.client.SetNX(ctx, e.key, e.replicaID, e.leaseTTL).Result()
+	if err != nil {
+		log.Printf("⚠️ RetentionCoordinator: leader election attempt for %s failed: %v", e.key, err)
+		return
+	}
+	if acquired {
+		e.isLeader.Store(true)
+		e.lastRenewal.Store(time.Now().UnixNano())
+		log.Printf("👑 RetentionCoordinator: %s acquired leadership of %s", e.replicaID, e.key)
+	}
+}
+
+// IsLeader reports whether this replica currently holds the lease.
+func (e *LeaderElector) IsLeader() bool {
+	return e.isLeader.Load()
+}
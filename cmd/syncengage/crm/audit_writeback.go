@@ -0,0 +1,60 @@
+package crm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/audit"
+)
+
+// crmWritebackLogger records a write-back call (UpdateCustomer,
+// SendMessage, CreateTag) to the same audit trail that covers bid
+// decisions, so downstream CRM effects of a bid are traceable alongside
+// it. HubSpotConnector and SalesforceConnector both satisfy this through
+// auditWritebackLogger wrapping an *audit.AuditLogger.
+type crmWritebackLogger interface {
+	WriteCRMAudit(provider, action, customerID string, callErr error, metadata map[string]interface{})
+}
+
+// auditWritebackLogger adapts an *audit.AuditLogger to crmWritebackLogger.
+// Left nil (the zero value embeds a nil *audit.AuditLogger), WriteCRMAudit
+// is a no-op, matching the "Left nil, write-back calls simply skip
+// auditing" behavior HubSpotConnector documented before this type existed.
+type auditWritebackLogger struct {
+	*audit.AuditLogger
+}
+
+// NewAuditWritebackLogger wraps logger for use as a CRM connector's
+// AuditLogger field.
+func NewAuditWritebackLogger(logger *audit.AuditLogger) crmWritebackLogger {
+	return auditWritebackLogger{AuditLogger: logger}
+}
+
+// WriteCRMAudit implements crmWritebackLogger, best-effort: a failure to
+// audit never fails the write-back call itself.
+func (a auditWritebackLogger) WriteCRMAudit(provider, action, customerID string, callErr error, metadata map[string]interface{}) {
+	if a.AuditLogger == nil {
+		return
+	}
+
+	status := "ACCEPTED"
+	explanation := fmt.Sprintf("%s %s succeeded", provider, action)
+	if callErr != nil {
+		status = "FAILED"
+		explanation = fmt.Sprintf("%s %s failed: %v", provider, action, callErr)
+	}
+
+	entry := &audit.AuditEntry{
+		RequestID:   fmt.Sprintf("crm-writeback-%s-%s-%s-%d", provider, action, customerID, time.Now().UnixNano()),
+		CustomerID:  customerID,
+		BidSource:   "CRM_WRITEBACK",
+		Platform:    provider,
+		BidStatus:   status,
+		Explanation: explanation,
+		Metadata:    metadata,
+	}
+	if err := a.AuditLogger.Write(context.Background(), entry); err != nil {
+		fmt.Printf("⚠️  CRM writeback audit error (provider=%s, customer=%s, action=%s): %v\n", provider, customerID, action, err)
+	}
+}
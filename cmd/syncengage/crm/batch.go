@@ -0,0 +1,62 @@
+package crm
+
+import "sync"
+
+// BatchUpdateBuffer accumulates UpdateCustomer calls and flushes them
+// together through a connector-supplied flush function, the way HubSpot's
+// batch endpoint and Salesforce's Composite Tree API expect many records
+// per request rather than one PATCH/POST per customer. It flushes
+// automatically once maxBatch customers are pending, or on an explicit
+// Flush call (e.g. from a caller that wants every queued update sent
+// before it moves on, or from a shutdown path).
+type BatchUpdateBuffer struct {
+	mu       sync.Mutex
+	pending  []Customer
+	maxBatch int
+	flush    func([]Customer) error
+}
+
+// NewBatchUpdateBuffer creates a buffer that calls flush with up to
+// maxBatch customers at a time. A maxBatch <= 0 disables automatic
+// flushing; only an explicit Flush call sends anything.
+func NewBatchUpdateBuffer(maxBatch int, flush func([]Customer) error) *BatchUpdateBuffer {
+	return &BatchUpdateBuffer{maxBatch: maxBatch, flush: flush}
+}
+
+// Add queues customer for the next flush, flushing immediately if the
+// batch has reached maxBatch.
+func (b *BatchUpdateBuffer) Add(customer Customer) error {
+	b.mu.Lock()
+	b.pending = append(b.pending, customer)
+	shouldFlush := b.maxBatch > 0 && len(b.pending) >= b.maxBatch
+	b.mu.Unlock()
+
+	if shouldFlush {
+		return b.Flush()
+	}
+	return nil
+}
+
+// Flush sends every pending customer through flush, regardless of batch
+// size, and clears the pending queue whether or not flush succeeds -
+// matching UpdateCustomer's existing behavior of not retrying a failed
+// write itself, just reporting the error to the caller.
+func (b *BatchUpdateBuffer) Flush() error {
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return b.flush(batch)
+}
+
+// Pending returns the number of customers currently queued, for tests and
+// metrics.
+func (b *BatchUpdateBuffer) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending)
+}
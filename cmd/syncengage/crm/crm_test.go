@@ -0,0 +1,205 @@
+package crm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestBatchUpdateBuffer_FlushesAutomaticallyAtMaxBatch(t *testing.T) {
+	var mu sync.Mutex
+	var flushed [][]Customer
+	buf := NewBatchUpdateBuffer(2, func(batch []Customer) error {
+		mu.Lock()
+		defer mu.Unlock()
+		flushed = append(flushed, batch)
+		return nil
+	})
+
+	if err := buf.Add(Customer{ID: "1"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if buf.Pending() != 1 {
+		t.Fatalf("expected 1 pending customer, got %d", buf.Pending())
+	}
+
+	if err := buf.Add(Customer{ID: "2"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(flushed) != 1 || len(flushed[0]) != 2 {
+		t.Fatalf("expected one flush of 2 customers once maxBatch was reached, got %+v", flushed)
+	}
+	if buf.Pending() != 0 {
+		t.Fatalf("expected the queue to be empty after a flush, got %d pending", buf.Pending())
+	}
+}
+
+func TestBatchUpdateBuffer_ExplicitFlushSendsPartialBatch(t *testing.T) {
+	var flushed []Customer
+	buf := NewBatchUpdateBuffer(10, func(batch []Customer) error {
+		flushed = batch
+		return nil
+	})
+
+	buf.Add(Customer{ID: "1"})
+	if len(flushed) != 0 {
+		t.Fatalf("expected no flush before maxBatch or an explicit Flush, got %+v", flushed)
+	}
+
+	if err := buf.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(flushed) != 1 || flushed[0].ID != "1" {
+		t.Fatalf("expected the single queued customer to flush, got %+v", flushed)
+	}
+}
+
+func TestBatchUpdateBuffer_FlushOfEmptyQueueIsNoOp(t *testing.T) {
+	called := false
+	buf := NewBatchUpdateBuffer(10, func(batch []Customer) error {
+		called = true
+		return nil
+	})
+	if err := buf.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if called {
+		t.Fatal("expected flush not to be called for an empty queue")
+	}
+}
+
+func TestBatchUpdateBuffer_PropagatesFlushError(t *testing.T) {
+	wantErr := fmt.Errorf("boom")
+	buf := NewBatchUpdateBuffer(1, func(batch []Customer) error { return wantErr })
+
+	if err := buf.Add(Customer{ID: "1"}); err != wantErr {
+		t.Fatalf("expected Add to surface the flush error, got %v", err)
+	}
+}
+
+func TestIdempotencyCache_SeenBeforeDedupes(t *testing.T) {
+	cache := newIdempotencyCache()
+	key := idempotencyKey("cust-1", "email", "hello")
+
+	if cache.SeenBefore(key) {
+		t.Fatal("expected the first call to report unseen")
+	}
+	if !cache.SeenBefore(key) {
+		t.Fatal("expected a repeated key to report seen")
+	}
+}
+
+func TestIdempotencyKey_DiffersByChannelAndMessage(t *testing.T) {
+	a := idempotencyKey("cust-1", "email", "hello")
+	b := idempotencyKey("cust-1", "sms", "hello")
+	c := idempotencyKey("cust-1", "email", "goodbye")
+
+	if a == b || a == c || b == c {
+		t.Fatalf("expected distinct keys for distinct (channel, message) pairs, got %q %q %q", a, b, c)
+	}
+}
+
+func TestWebhookConnector_UpdateCustomerPostsEvent(t *testing.T) {
+	var received webhookEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	conn := NewWebhookConnector(srv.URL, nil)
+	if err := conn.UpdateCustomer(Customer{ID: "cust-1", Email: "a@example.com"}); err != nil {
+		t.Fatalf("UpdateCustomer: %v", err)
+	}
+
+	if received.EventType != "customer_updated" {
+		t.Fatalf("expected customer_updated event, got %q", received.EventType)
+	}
+	if received.CustomerID != "cust-1" {
+		t.Fatalf("expected customer_id cust-1, got %q", received.CustomerID)
+	}
+}
+
+func TestWebhookConnector_SendMessageDedupesRetries(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	conn := NewWebhookConnector(srv.URL, nil)
+	if err := conn.SendMessage("cust-1", "email", "hello", nil); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if err := conn.SendMessage("cust-1", "email", "hello", nil); err != nil {
+		t.Fatalf("SendMessage retry: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected the retried SendMessage to be deduplicated, got %d calls", calls)
+	}
+}
+
+func TestWebhookConnector_FetchCustomersUnsupported(t *testing.T) {
+	conn := NewWebhookConnector("https://example.com/hook", nil)
+	if _, err := conn.FetchCustomers(nil); err == nil {
+		t.Fatal("expected FetchCustomers to fail on a push-only connector")
+	}
+	if _, err := conn.GetCustomer("cust-1"); err == nil {
+		t.Fatal("expected GetCustomer to fail on a push-only connector")
+	}
+}
+
+func TestDecodeBulkResultsCSV_MapsColumnsByHeader(t *testing.T) {
+	// Column order deliberately doesn't match salesforceBulkCSVColumns, to
+	// verify decodeBulkResultsCSV maps by header name rather than position.
+	csvBody := "Email,Id,LastName,FirstName\n" +
+		"ada@example.com,003xx1,Lovelace,Ada\n" +
+		"grace@example.com,003xx2,Hopper,Grace\n"
+
+	out := make(chan Customer, 2)
+	if err := decodeBulkResultsCSV(context.Background(), []byte(csvBody), out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(out)
+
+	var customers []Customer
+	for c := range out {
+		customers = append(customers, c)
+	}
+
+	if len(customers) != 2 {
+		t.Fatalf("expected 2 customers, got %d", len(customers))
+	}
+	if customers[0].ID != "003xx1" || customers[0].Email != "ada@example.com" || customers[0].FirstName != "Ada" || customers[0].LastName != "Lovelace" {
+		t.Errorf("unexpected first customer: %+v", customers[0])
+	}
+	if customers[1].ID != "003xx2" || customers[1].LastName != "Hopper" {
+		t.Errorf("unexpected second customer: %+v", customers[1])
+	}
+}
+
+func TestIsRetryableBulkStatus(t *testing.T) {
+	if !isRetryableBulkStatus(&salesforceBulkStatusError{statusCode: http.StatusTooManyRequests}) {
+		t.Error("expected 429 to be retryable")
+	}
+	if !isRetryableBulkStatus(&salesforceBulkStatusError{statusCode: http.StatusServiceUnavailable}) {
+		t.Error("expected 503 to be retryable")
+	}
+	if isRetryableBulkStatus(&salesforceBulkStatusError{statusCode: http.StatusBadRequest}) {
+		t.Error("expected 400 not to be retryable")
+	}
+	if isRetryableBulkStatus(fmt.Errorf("some other error")) {
+		t.Error("expected a non-salesforceBulkStatusError not to be retryable")
+	}
+}
@@ -1,158 +1,627 @@
-package crm
-
-import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"time"
-)
-
-// HubSpotConnector integrates with HubSpot CRM
-type HubSpotConnector struct {
-	APIKey     string
-	BaseURL    string
-	httpClient *http.Client
-}
-
-// NewHubSpotConnector creates a new HubSpot CRM connector
-func NewHubSpotConnector(apiKey string) *HubSpotConnector {
-	return &HubSpotConnector{
-		APIKey:  apiKey,
-		BaseURL: "https://api.hubapi.com",
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-	}
-}
-
-func (h *HubSpotConnector) Connect() error {
-	// Test API key by fetching account info
-	req, err := http.NewRequest("GET", h.BaseURL+"/contacts/v1/lists/all/contacts/all", nil)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Authorization", "Bearer "+h.APIKey)
-
-	resp, err := h.httpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("HubSpot authentication failed: %d", resp.StatusCode)
-	}
-
-	return nil
-}
-
-func (h *HubSpotConnector) FetchCustomers(filter map[string]interface{}) ([]Customer, error) {
-	req, err := http.NewRequest("GET", h.BaseURL+"/contacts/v1/lists/all/contacts/all?count=100", nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Authorization", "Bearer "+h.APIKey)
-
-	resp, err := h.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var hubspotResp struct {
-		Contacts []struct {
-			VID        int64 `json:"vid"`
-			Properties struct {
-				Email     struct{ Value string } `json:"email"`
-				FirstName struct{ Value string } `json:"firstname"`
-				LastName  struct{ Value string } `json:"lastname"`
-				Phone     struct{ Value string } `json:"phone"`
-			} `json:"properties"`
-		} `json:"contacts"`
-	}
-
-	if err := json.Unmarshal(body, &hubspotResp); err != nil {
-		return nil, err
-	}
-
-	customers := make([]Customer, 0, len(hubspotResp.Contacts))
-	for _, contact := range hubspotResp.Contacts {
-		customers = append(customers, Customer{
-			ID:        fmt.Sprintf("%d", contact.VID),
-			Email:     contact.Properties.Email.Value,
-			FirstName: contact.Properties.FirstName.Value,
-			LastName:  contact.Properties.LastName.Value,
-			Phone:     contact.Properties.Phone.Value,
-		})
-	}
-
-	return customers, nil
-}
-
-func (h *HubSpotConnector) GetCustomer(id string) (*Customer, error) {
-	req, err := http.NewRequest("GET", h.BaseURL+"/contacts/v1/contact/vid/"+id+"/profile", nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Authorization", "Bearer "+h.APIKey)
-
-	resp, err := h.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var contact struct {
-		VID        int64 `json:"vid"`
-		Properties struct {
-			Email     struct{ Value string } `json:"email"`
-			FirstName struct{ Value string } `json:"firstname"`
-			LastName  struct{ Value string } `json:"lastname"`
-		} `json:"properties"`
-	}
-
-	if err := json.Unmarshal(body, &contact); err != nil {
-		return nil, err
-	}
-
-	return &Customer{
-		ID:        id,
-		Email:     contact.Properties.Email.Value,
-		FirstName: contact.Properties.FirstName.Value,
-		LastName:  contact.Properties.LastName.Value,
-	}, nil
-}
-
-func (h *HubSpotConnector) UpdateCustomer(customer Customer) error {
-	// Implementation for updating HubSpot contact
-	return fmt.Errorf("not implemented")
-}
-
-func (h *HubSpotConnector) SendMessage(customerID, channel, message string, metadata map[string]string) error {
-	// Implementation for sending messages via HubSpot
-	return fmt.Errorf("not implemented")
-}
-
-func (h *HubSpotConnector) CreateTag(customerID, tag string) error {
-	// Implementation for adding tags in HubSpot
-	return fmt.Errorf("not implemented")
-}
-
-func (h *HubSpotConnector) GetName() string {
-	return "HubSpot"
-}
-
-func (h *HubSpotConnector) Close() error {
-	return nil
-}
+package crm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/auth"
+	"github.com/user/kiki-agent/cmd/syncshield/compliance"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// hubspotBatchSize is the largest record count HubSpot's batch update
+// endpoint accepts in one request.
+const hubspotBatchSize = 100
+
+// hubspotTokenCache shares one refreshing oauth2.TokenSource per portal
+// across every HubSpotConnector authenticating as it.
+var hubspotTokenCache = auth.NewTokenCache()
+
+// HubSpotConnector integrates with HubSpot CRM
+type HubSpotConnector struct {
+	BaseURL      string
+	AuthProvider auth.AuthProvider
+	httpClient   *http.Client
+
+	// RevenueProperty and LifetimeValueProperty name the HubSpot contact
+	// properties that hold post-acquisition revenue/LTV figures, since
+	// portals commonly rename or replace the stock properties with
+	// custom ones. Both default to HubSpot's own property names.
+	RevenueProperty       string
+	LifetimeValueProperty string
+
+	// AuditLogger, when set, receives a CRM_WRITEBACK AuditEntry for every
+	// UpdateCustomer/CreateTag/SendMessage call so the same audit trail
+	// that covers bid decisions also covers their downstream CRM effects.
+	// Left nil, write-back calls simply skip auditing.
+	AuditLogger crmWritebackLogger
+
+	// ComplianceLogger, when set, records a crm_data_access AuditEvent for
+	// every FetchCustomers/GetCustomer read, so GDPR Article 30 records of
+	// processing are automatic.
+	ComplianceLogger *compliance.GDPRAuditLogger
+
+	CircuitBreaker *shield.CircuitBreaker
+
+	rateLimiter *hubspotRateLimiter
+	idempotency *idempotencyCache
+	batch       *BatchUpdateBuffer
+
+	listCacheMu sync.Mutex
+	tagListIDs  map[string]string // tag name -> HubSpot static list ID
+
+	cursorMu sync.Mutex
+	cursor   string // "" or the last "after" cursor FetchCustomers paged to
+
+	cacheMu   sync.Mutex
+	lastFetch []Customer // served by FetchCustomers when CircuitBreaker is open
+}
+
+// NewHubSpotConnector creates a new HubSpot CRM connector that
+// authenticates via authProvider (an OAuth app's refreshing token, or
+// auth.StaticTokenAuthProvider for a private app's long-lived token).
+func NewHubSpotConnector(authProvider auth.AuthProvider) *HubSpotConnector {
+	tokenSource := hubspotTokenCache.Shared(context.Background(), "default", authProvider)
+	httpClient := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &oauth2.Transport{Source: tokenSource, Base: http.DefaultTransport},
+	}
+
+	h := &HubSpotConnector{
+		BaseURL:               "https://api.hubapi.com",
+		AuthProvider:          authProvider,
+		httpClient:            httpClient,
+		RevenueProperty:       "total_revenue",
+		LifetimeValueProperty: "hs_lifetimevalue",
+		rateLimiter:           &hubspotRateLimiter{},
+		CircuitBreaker:        shield.NewCircuitBreaker(),
+		idempotency:           newIdempotencyCache(),
+	}
+	h.batch = NewBatchUpdateBuffer(hubspotBatchSize, h.flushUpdateBatch)
+	return h
+}
+
+func (h *HubSpotConnector) Connect() error {
+	req, err := http.NewRequest("GET", h.BaseURL+"/crm/v3/objects/contacts?limit=1", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("HubSpot authentication failed: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// hubspotContactsPage is the shape the v3 CRM contacts list/search
+// endpoints return.
+type hubspotContactsPage struct {
+	Results []struct {
+		ID         string `json:"id"`
+		Properties struct {
+			Email     string `json:"email"`
+			FirstName string `json:"firstname"`
+			LastName  string `json:"lastname"`
+			Phone     string `json:"phone"`
+		} `json:"properties"`
+	} `json:"results"`
+	Paging struct {
+		Next struct {
+			After string `json:"after"`
+		} `json:"next"`
+	} `json:"paging"`
+}
+
+// FetchCustomers returns the next page of contacts from HubSpot's v3 CRM
+// API. The first call starts from the beginning; subsequent calls follow
+// the "after" cursor returned by the previous page until HubSpot stops
+// returning one, at which point the cursor resets. If CircuitBreaker is
+// open, it serves the last successfully fetched page instead of calling
+// out to a HubSpot that's already failing.
+func (h *HubSpotConnector) FetchCustomers(filter map[string]interface{}) ([]Customer, error) {
+	if !h.CircuitBreaker.CanExecute() {
+		h.CircuitBreaker.RecordFallback()
+		h.cacheMu.Lock()
+		cached := h.lastFetch
+		h.cacheMu.Unlock()
+		if cached != nil {
+			return cached, nil
+		}
+		return nil, fmt.Errorf("hubspot: circuit open and no cached page to fall back to")
+	}
+
+	h.rateLimiter.wait()
+
+	h.cursorMu.Lock()
+	cursor := h.cursor
+	h.cursorMu.Unlock()
+
+	q := url.Values{}
+	q.Set("limit", "100")
+	q.Set("properties", "email,firstname,lastname,phone")
+	if cursor != "" {
+		q.Set("after", cursor)
+	}
+
+	req, err := http.NewRequest("GET", h.BaseURL+"/crm/v3/objects/contacts?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		h.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	h.rateLimiter.update(resp)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		h.CircuitBreaker.RecordFailure(0)
+		return nil, fmt.Errorf("HubSpot FetchCustomers failed: %d", resp.StatusCode)
+	}
+
+	var page hubspotContactsPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		h.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	h.CircuitBreaker.RecordSuccess(0)
+	h.cursorMu.Lock()
+	h.cursor = page.Paging.Next.After
+	h.cursorMu.Unlock()
+
+	customers := make([]Customer, 0, len(page.Results))
+	for _, contact := range page.Results {
+		customers = append(customers, Customer{
+			ID:        contact.ID,
+			Email:     contact.Properties.Email,
+			FirstName: contact.Properties.FirstName,
+			LastName:  contact.Properties.LastName,
+			Phone:     contact.Properties.Phone,
+		})
+	}
+
+	h.cacheMu.Lock()
+	h.lastFetch = customers
+	h.cacheMu.Unlock()
+
+	h.logDataAccess(customers, "FetchCustomers")
+	return customers, nil
+}
+
+func (h *HubSpotConnector) GetCustomer(id string) (*Customer, error) {
+	h.rateLimiter.wait()
+
+	req, err := http.NewRequest("GET", h.BaseURL+"/contacts/v1/contact/vid/"+id+"/profile", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		h.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	h.rateLimiter.update(resp)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		h.CircuitBreaker.RecordFailure(0)
+		return nil, fmt.Errorf("HubSpot GetCustomer failed: %d", resp.StatusCode)
+	}
+
+	var contact struct {
+		VID        int64 `json:"vid"`
+		Properties map[string]struct {
+			Value string `json:"value"`
+		} `json:"properties"`
+	}
+
+	if err := json.Unmarshal(body, &contact); err != nil {
+		h.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	h.CircuitBreaker.RecordSuccess(0)
+
+	customer := &Customer{
+		ID:        id,
+		Email:     contact.Properties["email"].Value,
+		FirstName: contact.Properties["firstname"].Value,
+		LastName:  contact.Properties["lastname"].Value,
+	}
+
+	if rev, ok := contact.Properties[h.RevenueProperty]; ok && rev.Value != "" {
+		if parsed, err := strconv.ParseFloat(rev.Value, 64); err == nil {
+			customer.TotalSpend = parsed
+		}
+	}
+	if ltv, ok := contact.Properties[h.LifetimeValueProperty]; ok && ltv.Value != "" {
+		if parsed, err := strconv.ParseFloat(ltv.Value, 64); err == nil {
+			customer.LTV = parsed
+		}
+	}
+
+	h.logDataAccess([]Customer{*customer}, "GetCustomer")
+	return customer, nil
+}
+
+// hubspotUpdatablePropertyAllowlist lists the stock contact properties
+// UpdateCustomer is permitted to write; anything else a caller might put on
+// a Customer (e.g. Segment, ChurnRisk) never reaches HubSpot through this
+// path.
+var hubspotUpdatablePropertyAllowlist = []string{"email", "firstname", "lastname", "phone"}
+
+// customerProperties builds the allowlisted HubSpot property map for a
+// single customer, shared between the single-record path this used to take
+// and flushUpdateBatch's batch request.
+func (h *HubSpotConnector) customerProperties(customer Customer) map[string]string {
+	candidates := map[string]string{
+		"email":     customer.Email,
+		"firstname": customer.FirstName,
+		"lastname":  customer.LastName,
+		"phone":     customer.Phone,
+	}
+	properties := make(map[string]string)
+	for _, name := range hubspotUpdatablePropertyAllowlist {
+		if value := candidates[name]; value != "" {
+			properties[name] = value
+		}
+	}
+	if customer.TotalSpend != 0 {
+		properties[h.RevenueProperty] = strconv.FormatFloat(customer.TotalSpend, 'f', -1, 64)
+	}
+	if customer.LTV != 0 {
+		properties[h.LifetimeValueProperty] = strconv.FormatFloat(customer.LTV, 'f', -1, 64)
+	}
+	return properties
+}
+
+// UpdateCustomer queues customer for HubSpot's batch update endpoint
+// rather than issuing a PATCH per call; the write only reaches HubSpot
+// once hubspotBatchSize customers are queued or Flush is called
+// explicitly.
+func (h *HubSpotConnector) UpdateCustomer(customer Customer) error {
+	return h.batch.Add(customer)
+}
+
+// Flush sends every UpdateCustomer call queued so far, regardless of
+// whether a full batch has accumulated.
+func (h *HubSpotConnector) Flush() error {
+	return h.batch.Flush()
+}
+
+// flushUpdateBatch is BatchUpdateBuffer's flush function: it submits every
+// customer in batch via POST /crm/v3/objects/contacts/batch/update.
+func (h *HubSpotConnector) flushUpdateBatch(batch []Customer) error {
+	h.rateLimiter.wait()
+
+	inputs := make([]map[string]interface{}, len(batch))
+	for i, c := range batch {
+		inputs[i] = map[string]interface{}{
+			"id":         c.ID,
+			"properties": h.customerProperties(c),
+		}
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{"inputs": inputs})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", h.BaseURL+"/crm/v3/objects/contacts/batch/update", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		h.CircuitBreaker.RecordFailure(0)
+		h.auditBatchWriteback(batch, err)
+		return err
+	}
+	defer resp.Body.Close()
+	h.rateLimiter.update(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		h.CircuitBreaker.RecordFailure(0)
+		err = fmt.Errorf("HubSpot batch UpdateCustomer failed: %d", resp.StatusCode)
+		h.auditBatchWriteback(batch, err)
+		return err
+	}
+
+	h.CircuitBreaker.RecordSuccess(0)
+	h.auditBatchWriteback(batch, nil)
+	return nil
+}
+
+// hubspotSupportedMessageChannels are the Conversations API channels
+// SendMessage knows how to address; anything else is rejected up front
+// instead of being silently dropped by HubSpot.
+var hubspotSupportedMessageChannels = map[string]bool{"email": true, "sms": true}
+
+// SendMessage delivers a message through HubSpot's Conversations API,
+// mapping metadata onto custom message properties so KIKI Agent context
+// (e.g. predicted LTV bucket) rides along with the outbound message. A
+// retry of a call whose response was never seen is deduplicated on
+// (customerID, channel, message) rather than resulting in a second send.
+func (h *HubSpotConnector) SendMessage(customerID, channel, message string, metadata map[string]string) error {
+	if !hubspotSupportedMessageChannels[channel] {
+		return fmt.Errorf("unsupported HubSpot message channel: %s", channel)
+	}
+
+	key := idempotencyKey(customerID, channel, message)
+	if h.idempotency.SeenBefore(key) {
+		return nil
+	}
+
+	h.rateLimiter.wait()
+
+	properties := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		properties["kiki_"+k] = v
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":        strings.ToUpper(channel),
+		"text":        message,
+		"recipientId": customerID,
+		"properties":  properties,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", h.BaseURL+"/conversations/v3/conversations/messages", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", key)
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		h.CircuitBreaker.RecordFailure(0)
+		h.auditWriteback("send_message", customerID, err, map[string]interface{}{"channel": channel})
+		return err
+	}
+	defer resp.Body.Close()
+	h.rateLimiter.update(resp)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		h.CircuitBreaker.RecordFailure(0)
+		err = fmt.Errorf("HubSpot SendMessage failed: %d", resp.StatusCode)
+		h.auditWriteback("send_message", customerID, err, map[string]interface{}{"channel": channel})
+		return err
+	}
+
+	h.CircuitBreaker.RecordSuccess(0)
+	h.auditWriteback("send_message", customerID, nil, map[string]interface{}{"channel": channel, "message_length": len(message)})
+	return nil
+}
+
+// CreateTag adds customerID to a HubSpot static list named after tag,
+// creating the list on first use and caching its ID for subsequent calls.
+func (h *HubSpotConnector) CreateTag(customerID, tag string) error {
+	h.rateLimiter.wait()
+
+	listID, err := h.resolveTagListID(tag)
+	if err != nil {
+		h.auditWriteback("create_tag", customerID, err, map[string]interface{}{"tag": tag})
+		return err
+	}
+
+	payload, err := json.Marshal([]string{customerID})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("PUT", h.BaseURL+"/crm/v3/lists/"+listID+"/memberships/add", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		h.CircuitBreaker.RecordFailure(0)
+		h.auditWriteback("create_tag", customerID, err, map[string]interface{}{"tag": tag, "list_id": listID})
+		return err
+	}
+	defer resp.Body.Close()
+	h.rateLimiter.update(resp)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		h.CircuitBreaker.RecordFailure(0)
+		err = fmt.Errorf("HubSpot CreateTag failed: %d", resp.StatusCode)
+		h.auditWriteback("create_tag", customerID, err, map[string]interface{}{"tag": tag, "list_id": listID})
+		return err
+	}
+
+	h.CircuitBreaker.RecordSuccess(0)
+	h.auditWriteback("create_tag", customerID, nil, map[string]interface{}{"tag": tag, "list_id": listID})
+	return nil
+}
+
+// resolveTagListID returns the HubSpot static list ID backing tag,
+// creating the list lazily on first use since HubSpot has no
+// create-if-missing endpoint for lists.
+func (h *HubSpotConnector) resolveTagListID(tag string) (string, error) {
+	h.listCacheMu.Lock()
+	if id, ok := h.tagListIDs[tag]; ok {
+		h.listCacheMu.Unlock()
+		return id, nil
+	}
+	h.listCacheMu.Unlock()
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"name":           "kiki-tag-" + tag,
+		"objectTypeId":   "0-1", // contacts
+		"processingType": "MANUAL",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", h.BaseURL+"/crm/v3/lists", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	h.rateLimiter.update(resp)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("HubSpot list creation failed: %d", resp.StatusCode)
+	}
+
+	var created struct {
+		List struct {
+			ListID string `json:"listId"`
+		} `json:"list"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		return "", err
+	}
+
+	h.listCacheMu.Lock()
+	if h.tagListIDs == nil {
+		h.tagListIDs = make(map[string]string)
+	}
+	h.tagListIDs[tag] = created.List.ListID
+	h.listCacheMu.Unlock()
+
+	return created.List.ListID, nil
+}
+
+// logDataAccess records a crm_data_access AuditEvent for every customer
+// read, satisfying GDPR Article 30's records-of-processing requirement.
+func (h *HubSpotConnector) logDataAccess(customers []Customer, action string) {
+	if h.ComplianceLogger == nil {
+		return
+	}
+	for _, c := range customers {
+		_ = h.ComplianceLogger.LogEvent(compliance.AuditEvent{
+			EventType:    "crm_data_access",
+			CustomerID:   c.ID,
+			Action:       action,
+			Resource:     "hubspot_contact",
+			Outcome:      "SUCCESS",
+			Reason:       "crm_sync",
+			DataAccessed: []string{"email", "first_name", "last_name", "phone"},
+		})
+	}
+}
+
+// auditWriteback records a CRM_WRITEBACK AuditEntry for a write-back call,
+// best-effort: a failure to audit never fails the write-back itself.
+func (h *HubSpotConnector) auditWriteback(action, customerID string, callErr error, metadata map[string]interface{}) {
+	if h.AuditLogger == nil {
+		return
+	}
+	h.AuditLogger.WriteCRMAudit("hubspot", action, customerID, callErr, metadata)
+}
+
+func (h *HubSpotConnector) auditBatchWriteback(batch []Customer, callErr error) {
+	if h.AuditLogger == nil {
+		return
+	}
+	for _, c := range batch {
+		h.AuditLogger.WriteCRMAudit("hubspot", "update_customer", c.ID, callErr, nil)
+	}
+}
+
+func (h *HubSpotConnector) GetName() string {
+	return "HubSpot"
+}
+
+func (h *HubSpotConnector) Close() error {
+	return h.batch.Flush()
+}
+
+// hubspotRateLimiter tracks HubSpot's own X-HubSpot-RateLimit-* response
+// headers and makes every write-back call on the connector wait out a
+// depleted window rather than hammering HubSpot into a 429. It is shared
+// across all of a connector's write calls (one instance per connector),
+// unlike the proactive token-bucket RateLimiter connectors/ uses for
+// platforms that don't expose this kind of header.
+type hubspotRateLimiter struct {
+	mu         sync.Mutex
+	remaining  int
+	resetAt    time.Time
+	configured bool
+}
+
+// wait blocks until HubSpot's advertised rate-limit window has reset, if
+// the last response reported no calls remaining. Before any response has
+// been observed, it is a no-op.
+func (rl *hubspotRateLimiter) wait() {
+	rl.mu.Lock()
+	remaining, resetAt, configured := rl.remaining, rl.resetAt, rl.configured
+	rl.mu.Unlock()
+
+	if !configured || remaining > 0 {
+		return
+	}
+	if d := time.Until(resetAt); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// update records the rate-limit state reported by resp's headers, if any.
+func (rl *hubspotRateLimiter) update(resp *http.Response) {
+	remaining := resp.Header.Get("X-HubSpot-RateLimit-Remaining")
+	if remaining == "" {
+		return
+	}
+	r, err := strconv.Atoi(remaining)
+	if err != nil {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.remaining = r
+	rl.configured = true
+	if ms, err := strconv.Atoi(resp.Header.Get("X-HubSpot-RateLimit-Interval-Milliseconds")); err == nil && ms > 0 {
+		rl.resetAt = time.Now().Add(time.Duration(ms) * time.Millisecond)
+	}
+}
@@ -0,0 +1,60 @@
+package crm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// idempotencyCacheCapacity bounds how many keys SendMessage remembers
+// before evicting the oldest, so a long-running process doesn't grow its
+// seen-set without bound.
+const idempotencyCacheCapacity = 10_000
+
+// idempotencyCache deduplicates SendMessage calls carrying the same
+// (customerID, channel, message) triple, the way an HTTP API's
+// Idempotency-Key header lets a client safely retry a call whose response
+// it never saw without risking a duplicate send. It is intentionally
+// process-local and unbounded-by-time rather than backed by a store - a
+// crash-and-restart re-sending a message that was in flight is an
+// acceptable tradeoff for a connector that otherwise has no durable
+// dedup store of its own.
+type idempotencyCache struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+	// order records insertion order so SeenBefore can evict the oldest key once
+	// the cache reaches idempotencyCacheCapacity, bounding memory instead
+	// of growing forever in a long-running process.
+	order []string
+}
+
+func newIdempotencyCache() *idempotencyCache {
+	return &idempotencyCache{seen: make(map[string]struct{})}
+}
+
+// key derives a stable idempotency key for one SendMessage call.
+func idempotencyKey(customerID, channel, message string) string {
+	sum := sha256.Sum256([]byte(customerID + "\x00" + channel + "\x00" + message))
+	return hex.EncodeToString(sum[:])
+}
+
+// SeenBefore reports whether key has already been recorded, and records it
+// if not - callers use this to skip a duplicate SendMessage in one atomic
+// check-and-set rather than racing a separate Seen/Record pair.
+func (c *idempotencyCache) SeenBefore(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.seen[key]; ok {
+		return true
+	}
+
+	if len(c.order) >= idempotencyCacheCapacity {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		delete(c.seen, oldest)
+	}
+	c.seen[key] = struct{}{}
+	c.order = append(c.order, key)
+	return false
+}
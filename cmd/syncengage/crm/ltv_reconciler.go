@@ -0,0 +1,96 @@
+package crm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/audit"
+	"github.com/user/kiki-agent/cmd/syncshield/observability"
+)
+
+// AttributionWindow bounds how far back Reconcile will still consider an
+// unresolved AuditEntry worth reconciling, e.g. a 30-day window treats bids
+// placed more than 30 days ago as no longer attributable to fresh CRM data.
+type AttributionWindow time.Duration
+
+// Common attribution windows; callers may use any other time.Duration-based
+// value if these don't fit.
+const (
+	Attribution30Day AttributionWindow = AttributionWindow(30 * 24 * time.Hour)
+	Attribution60Day AttributionWindow = AttributionWindow(60 * 24 * time.Hour)
+	Attribution90Day AttributionWindow = AttributionWindow(90 * 24 * time.Hour)
+)
+
+// LTVReconciler closes the loop between SyncValue's PredictedLTV and the
+// ground-truth lifetime value a CRM observes after acquisition. It joins
+// unresolved AuditEntry rows (ActualLTV not yet set) against the CRM by
+// CustomerID and writes ActualLTV/ActualLTVTimestamp/LTVErrorPct back onto
+// the matching entries.
+type LTVReconciler struct {
+	AuditLogger *audit.AuditLogger
+	CRM         CRMConnector
+	Window      AttributionWindow
+}
+
+// NewLTVReconciler creates a reconciler that pulls ground-truth LTV from
+// crmConnector and writes it back through auditLogger, considering only
+// entries placed within window of "now" at Reconcile time.
+func NewLTVReconciler(auditLogger *audit.AuditLogger, crmConnector CRMConnector, window AttributionWindow) *LTVReconciler {
+	return &LTVReconciler{
+		AuditLogger: auditLogger,
+		CRM:         crmConnector,
+		Window:      window,
+	}
+}
+
+// Reconcile fetches every unresolved audit entry placed since `since` (or
+// since the start of the attribution window, whichever is later), looks up
+// each distinct customer's current LTV from the CRM, and writes the result
+// back onto every matching entry. It returns the number of entries
+// reconciled, and can be called repeatedly (e.g. on a schedule, or for a
+// one-off backfill) since already-resolved entries are excluded by
+// AuditFilters.Unresolved.
+func (r *LTVReconciler) Reconcile(ctx context.Context, since time.Time) (int, error) {
+	windowStart := time.Now().Add(-time.Duration(r.Window))
+	if since.Before(windowStart) {
+		since = windowStart
+	}
+
+	entries, err := r.AuditLogger.GetAuditTrail(ctx, audit.AuditFilters{
+		StartTime:  since,
+		Unresolved: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("fetching unresolved audit trail: %w", err)
+	}
+
+	customerLTV := map[string]*Customer{}
+	reconciled := 0
+	for _, entry := range entries {
+		customer, looked := customerLTV[entry.CustomerID]
+		if !looked {
+			customer, err = r.CRM.GetCustomer(entry.CustomerID)
+			if err != nil {
+				// Skip this customer for now; a later Reconcile run (or
+				// backfill) will retry since the entry stays unresolved.
+				customer = nil
+			}
+			customerLTV[entry.CustomerID] = customer
+		}
+		if customer == nil || customer.LTV <= 0 || entry.PredictedLTV == 0 {
+			continue
+		}
+
+		observedAt := time.Now()
+		if err := r.AuditLogger.UpdateActualLTV(ctx, entry.RequestID, customer.LTV, observedAt); err != nil {
+			return reconciled, fmt.Errorf("writing back actual LTV for request %s: %w", entry.RequestID, err)
+		}
+
+		errorPct := ((customer.LTV - entry.PredictedLTV) / entry.PredictedLTV) * 100
+		observability.Default.RecordLTVReconciliation(entry.Platform, errorPct)
+		reconciled++
+	}
+
+	return reconciled, nil
+}
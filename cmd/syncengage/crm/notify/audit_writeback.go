@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/audit"
+)
+
+// auditDeliveryLogger adapts an *audit.AuditLogger to DeliveryLogger, the
+// same way crm.auditWritebackLogger adapts one for CRM write-backs - so
+// delivery attempts/successes/failures land in the same audit trail as
+// bid decisions and CRM writes.
+type auditDeliveryLogger struct {
+	*audit.AuditLogger
+}
+
+// NewAuditDeliveryLogger wraps logger for use as a Dispatcher's
+// DeliveryLogger.
+func NewAuditDeliveryLogger(logger *audit.AuditLogger) DeliveryLogger {
+	return auditDeliveryLogger{AuditLogger: logger}
+}
+
+// WriteDeliveryEvent implements DeliveryLogger, best-effort: a failure to
+// audit never fails the delivery attempt itself.
+func (a auditDeliveryLogger) WriteDeliveryEvent(event DeliveryEvent) {
+	if a.AuditLogger == nil {
+		return
+	}
+
+	status := "ACCEPTED"
+	explanation := fmt.Sprintf("%s notification via %s %s", event.Channel, event.Provider, event.Outcome)
+	if event.Err != nil {
+		status = "FAILED"
+		explanation = fmt.Sprintf("%s notification via %s %s: %v", event.Channel, event.Provider, event.Outcome, event.Err)
+	}
+
+	entry := &audit.AuditEntry{
+		RequestID:   fmt.Sprintf("notify-%s-%s-%s-%d", event.Channel, event.Provider, event.CustomerID, time.Now().UnixNano()),
+		CustomerID:  event.CustomerID,
+		BidSource:   "NOTIFICATION",
+		Platform:    event.Provider,
+		BidStatus:   status,
+		Explanation: explanation,
+		Metadata: map[string]interface{}{
+			"template_id": event.TemplateID,
+			"attempt":     event.Attempt,
+			"outcome":     event.Outcome,
+		},
+	}
+	if err := a.AuditLogger.Write(context.Background(), entry); err != nil {
+		fmt.Printf("⚠️  notification delivery audit error (provider=%s, customer=%s, outcome=%s): %v\n", event.Provider, event.CustomerID, event.Outcome, err)
+	}
+}
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+	"github.com/user/kiki-agent/cmd/syncshield/compliance"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// dispatcherRateLimitPerMinute bounds each provider to a conservative
+// default call rate; operators needing a provider-specific limit can
+// still construct their own connectors.RateLimiter per provider later if
+// this ever needs to vary.
+const dispatcherRateLimitPerMinute = 600
+
+// job is one queued Send call, with a channel back to the caller for the
+// result - mirroring the request/response shape a synchronous Send needs
+// even though the work happens on a pooled worker goroutine.
+type job struct {
+	ctx          context.Context
+	notification Notification
+	result       chan<- sendResult
+}
+
+type sendResult struct {
+	receipt Receipt
+	err     error
+}
+
+// Dispatcher is the queued goroutine pool backing Notifier: it renders a
+// Notification's template, gates it on consent, retries transient
+// provider failures with backoff, rate-limits per provider, and
+// deduplicates retried sends via an idempotency key - emitting a
+// DeliveryEvent at every step so delivery history flows into the audit
+// logger the way crm's write-back calls already do.
+type Dispatcher struct {
+	router    *Router
+	templates *TemplateRegistry
+	consent   *compliance.ConsentManager
+	logger    DeliveryLogger
+
+	idempotency *idempotencyCache
+
+	rateLimitersMu sync.Mutex
+	rateLimiters   map[string]*connectors.RateLimiter
+
+	queue   chan job
+	workers int
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewDispatcher creates a Dispatcher with workers worker goroutines
+// draining its queue, routing through router, rendering via templates,
+// and gating sends on consent.HasConsent. consent and logger may be nil -
+// a nil consent never gates a send, a nil logger simply drops delivery
+// events.
+func NewDispatcher(workers int, router *Router, templates *TemplateRegistry, consent *compliance.ConsentManager, logger DeliveryLogger) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Dispatcher{
+		router:       router,
+		templates:    templates,
+		consent:      consent,
+		logger:       logger,
+		idempotency:  newIdempotencyCache(),
+		rateLimiters: make(map[string]*connectors.RateLimiter),
+		queue:        make(chan job, workers*4),
+		workers:      workers,
+	}
+}
+
+// Start spawns the worker pool. Calling Start more than once is a no-op.
+func (d *Dispatcher) Start() {
+	d.once.Do(func() {
+		for i := 0; i < d.workers; i++ {
+			d.wg.Add(1)
+			go d.worker()
+		}
+	})
+}
+
+// Stop closes the queue and waits for every in-flight job to finish. No
+// further Send calls are valid after Stop returns.
+func (d *Dispatcher) Stop() {
+	close(d.queue)
+	d.wg.Wait()
+}
+
+// Send implements Notifier: it enqueues notification and blocks until a
+// worker has processed it (or ctx is canceled first), giving callers a
+// synchronous call despite the work happening on a pooled goroutine.
+func (d *Dispatcher) Send(ctx context.Context, notification Notification) (Receipt, error) {
+	result := make(chan sendResult, 1)
+	select {
+	case d.queue <- job{ctx: ctx, notification: notification, result: result}:
+	case <-ctx.Done():
+		return Receipt{}, ctx.Err()
+	}
+
+	select {
+	case r := <-result:
+		return r.receipt, r.err
+	case <-ctx.Done():
+		return Receipt{}, ctx.Err()
+	}
+}
+
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+	for j := range d.queue {
+		receipt, err := d.process(j.ctx, j.notification)
+		j.result <- sendResult{receipt: receipt, err: err}
+	}
+}
+
+func (d *Dispatcher) process(ctx context.Context, n Notification) (Receipt, error) {
+	if n.ConsentType != "" && d.consent != nil && !d.consent.HasConsent(n.CustomerID, n.ConsentType) {
+		d.emit(DeliveryEvent{CustomerID: n.CustomerID, Channel: n.Channel, TemplateID: n.TemplateID, Outcome: "skipped_consent", Timestamp: time.Now()})
+		return Receipt{Status: "skipped_consent", SentAt: time.Now()}, fmt.Errorf("notify: customer %s has no %s consent on file", n.CustomerID, n.ConsentType)
+	}
+
+	key := n.IdempotencyKey
+	if key == "" {
+		key = fmt.Sprintf("%s\x00%s\x00%s\x00%s", n.CustomerID, n.Channel, n.TemplateID, n.To)
+	}
+	if d.idempotency.SeenBefore(key) {
+		d.emit(DeliveryEvent{CustomerID: n.CustomerID, Channel: n.Channel, TemplateID: n.TemplateID, Outcome: "skipped_duplicate", Timestamp: time.Now()})
+		return Receipt{Status: "skipped_duplicate", SentAt: time.Now()}, nil
+	}
+
+	transport, err := d.router.Route(n.Channel, n.ProviderPreference)
+	if err != nil {
+		return Receipt{}, err
+	}
+
+	subject, html, text := "", "", ""
+	if n.TemplateID != "" {
+		subject, html, text, err = d.templates.Render(n.TemplateID, n.TemplateVersion, n.Locale, n.Data)
+		if err != nil {
+			return Receipt{}, err
+		}
+	}
+
+	limiter := d.rateLimiterFor(transport.Name())
+	if err := limiter.Wait(ctx); err != nil {
+		return Receipt{}, fmt.Errorf("notify: rate limit wait for %s: %w", transport.Name(), err)
+	}
+
+	retry := shield.DefaultRetryPolicy()
+	result, _, err := retry.ExecuteWithRetry(ctx, func(ctx context.Context, attempt int) (interface{}, error) {
+		d.emit(DeliveryEvent{CustomerID: n.CustomerID, Channel: n.Channel, Provider: transport.Name(), TemplateID: n.TemplateID, Attempt: attempt, Outcome: "attempted", Timestamp: time.Now()})
+		messageID, err := transport.Deliver(ctx, n, subject, html, text)
+		if err != nil {
+			d.emit(DeliveryEvent{CustomerID: n.CustomerID, Channel: n.Channel, Provider: transport.Name(), TemplateID: n.TemplateID, Attempt: attempt, Outcome: "failed", Err: err, Timestamp: time.Now()})
+			return nil, err
+		}
+		return messageID, nil
+	}, shield.DefaultIsRetryable)
+
+	if err != nil {
+		return Receipt{}, fmt.Errorf("notify: delivering via %s: %w", transport.Name(), err)
+	}
+
+	messageID, _ := result.(string)
+	d.emit(DeliveryEvent{CustomerID: n.CustomerID, Channel: n.Channel, Provider: transport.Name(), TemplateID: n.TemplateID, Outcome: "succeeded", Timestamp: time.Now()})
+
+	return Receipt{
+		Provider:  transport.Name(),
+		MessageID: messageID,
+		Status:    "sent",
+		SentAt:    time.Now(),
+	}, nil
+}
+
+func (d *Dispatcher) rateLimiterFor(provider string) *connectors.RateLimiter {
+	d.rateLimitersMu.Lock()
+	defer d.rateLimitersMu.Unlock()
+	rl, ok := d.rateLimiters[provider]
+	if !ok {
+		rl = connectors.NewRateLimiter(dispatcherRateLimitPerMinute)
+		d.rateLimiters[provider] = rl
+	}
+	return rl
+}
+
+func (d *Dispatcher) emit(event DeliveryEvent) {
+	if d.logger == nil {
+		return
+	}
+	d.logger.WriteDeliveryEvent(event)
+}
@@ -0,0 +1,194 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/user/kiki-agent/cmd/syncshield/compliance"
+)
+
+// fakeTransport is an in-memory Transport recording every delivered
+// Notification, optionally failing a fixed number of attempts before
+// succeeding so tests can exercise Dispatcher's retry behavior without a
+// real network dependency.
+type fakeTransport struct {
+	name         string
+	channel      Channel
+	failAttempts int32 // number of calls to fail before succeeding
+	calls        int32
+}
+
+func (f *fakeTransport) Name() string     { return f.name }
+func (f *fakeTransport) Channel() Channel { return f.channel }
+
+func (f *fakeTransport) Deliver(ctx context.Context, n Notification, subject, html, text string) (string, error) {
+	n2 := atomic.AddInt32(&f.calls, 1)
+	if n2 <= f.failAttempts {
+		return "", fmt.Errorf("fake transport: simulated 503 service unavailable")
+	}
+	return fmt.Sprintf("msg-%d", n2), nil
+}
+
+func newTestDispatcher(t *testing.T, transport Transport, consent *compliance.ConsentManager) *Dispatcher {
+	t.Helper()
+	router := NewRouter()
+	router.Register(transport)
+
+	templates := NewTemplateRegistry("en")
+	if err := templates.Register(Template{
+		Key:      TemplateKey{ID: "welcome", Version: 1, Locale: "en"},
+		Subject:  "Welcome, {{.Name}}",
+		HTMLBody: "<p>Hi {{.Name}}</p>",
+		TextBody: "Hi {{.Name}}",
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	d := NewDispatcher(2, router, templates, consent, nil)
+	d.Start()
+	t.Cleanup(d.Stop)
+	return d
+}
+
+func TestDispatcherSendRendersTemplateAndReturnsReceipt(t *testing.T) {
+	transport := &fakeTransport{name: "fake-email", channel: ChannelEmail}
+	d := newTestDispatcher(t, transport, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	receipt, err := d.Send(ctx, Notification{
+		CustomerID: "cust-1",
+		Channel:    ChannelEmail,
+		To:         "cust-1@example.com",
+		TemplateID: "welcome",
+		Locale:     "en",
+		Data:       map[string]interface{}{"Name": "Ada"},
+	})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if receipt.Status != "sent" || receipt.Provider != "fake-email" {
+		t.Errorf("unexpected receipt: %+v", receipt)
+	}
+	if atomic.LoadInt32(&transport.calls) != 1 {
+		t.Errorf("expected exactly 1 delivery attempt, got %d", transport.calls)
+	}
+}
+
+func TestDispatcherSendRetriesTransientFailureThenSucceeds(t *testing.T) {
+	transport := &fakeTransport{name: "fake-email", channel: ChannelEmail, failAttempts: 1}
+	d := newTestDispatcher(t, transport, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	receipt, err := d.Send(ctx, Notification{
+		CustomerID: "cust-2",
+		Channel:    ChannelEmail,
+		To:         "cust-2@example.com",
+		TemplateID: "welcome",
+		Locale:     "en",
+		Data:       map[string]interface{}{"Name": "Grace"},
+	})
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got error: %v", err)
+	}
+	if receipt.Status != "sent" {
+		t.Errorf("unexpected receipt status: %s", receipt.Status)
+	}
+	if atomic.LoadInt32(&transport.calls) != 2 {
+		t.Errorf("expected exactly 2 attempts (1 failure + 1 success), got %d", transport.calls)
+	}
+}
+
+func TestDispatcherSendDedupesRepeatedIdempotencyKey(t *testing.T) {
+	transport := &fakeTransport{name: "fake-email", channel: ChannelEmail}
+	d := newTestDispatcher(t, transport, nil)
+
+	ctx := context.Background()
+	n := Notification{
+		CustomerID:     "cust-3",
+		Channel:        ChannelEmail,
+		To:             "cust-3@example.com",
+		TemplateID:     "welcome",
+		Locale:         "en",
+		Data:           map[string]interface{}{"Name": "Linus"},
+		IdempotencyKey: "fixed-key-1",
+	}
+
+	if _, err := d.Send(ctx, n); err != nil {
+		t.Fatalf("first Send failed: %v", err)
+	}
+	receipt, err := d.Send(ctx, n)
+	if err != nil {
+		t.Fatalf("second Send (duplicate) failed: %v", err)
+	}
+	if receipt.Status != "skipped_duplicate" {
+		t.Errorf("expected the retried send to be deduped, got status %q", receipt.Status)
+	}
+	if atomic.LoadInt32(&transport.calls) != 1 {
+		t.Errorf("expected only 1 delivery despite 2 Send calls, got %d", transport.calls)
+	}
+}
+
+func TestDispatcherSendGatesOnConsent(t *testing.T) {
+	transport := &fakeTransport{name: "fake-email", channel: ChannelEmail}
+	consent := compliance.NewConsentManager(nil, compliance.NewInMemoryConsentStore(), zerolog.Nop())
+	d := newTestDispatcher(t, transport, consent)
+
+	ctx := context.Background()
+	n := Notification{
+		CustomerID:  "cust-4",
+		Channel:     ChannelEmail,
+		To:          "cust-4@example.com",
+		TemplateID:  "welcome",
+		Locale:      "en",
+		Data:        map[string]interface{}{"Name": "Margaret"},
+		ConsentType: compliance.ConsentMarketing,
+	}
+
+	if _, err := d.Send(ctx, n); err == nil {
+		t.Fatal("expected the send to be vetoed without marketing consent on file")
+	}
+	if atomic.LoadInt32(&transport.calls) != 0 {
+		t.Errorf("expected no delivery attempt without consent, got %d", transport.calls)
+	}
+
+	if err := consent.GrantConsent("cust-4", compliance.ConsentMarketing, "127.0.0.1", "test-agent", "Consent"); err != nil {
+		t.Fatalf("GrantConsent failed: %v", err)
+	}
+
+	n.IdempotencyKey = "after-consent-granted"
+	if _, err := d.Send(ctx, n); err != nil {
+		t.Fatalf("expected the send to succeed once consent was granted: %v", err)
+	}
+	if atomic.LoadInt32(&transport.calls) != 1 {
+		t.Errorf("expected exactly 1 delivery after consent was granted, got %d", transport.calls)
+	}
+}
+
+func TestRouterRouteFallsBackToChannelDefault(t *testing.T) {
+	router := NewRouter()
+	primary := &fakeTransport{name: "sendgrid", channel: ChannelEmail}
+	router.Register(primary)
+
+	transport, err := router.Route(ChannelEmail, "")
+	if err != nil {
+		t.Fatalf("Route failed: %v", err)
+	}
+	if transport.Name() != "sendgrid" {
+		t.Errorf("expected the default email transport, got %s", transport.Name())
+	}
+
+	if _, err := router.Route(ChannelSMS, ""); err == nil {
+		t.Fatal("expected an error routing a channel with no registered transport")
+	}
+	if _, err := router.Route(ChannelEmail, "mailgun"); err == nil {
+		t.Fatal("expected an error preferring a provider that was never registered")
+	}
+}
@@ -0,0 +1,42 @@
+package notify
+
+import "sync"
+
+// idempotencyCacheCapacity bounds how many keys Dispatcher remembers
+// before evicting the oldest, matching crm's idempotencyCache - a
+// long-running dispatcher process shouldn't grow its seen-set forever.
+const idempotencyCacheCapacity = 10_000
+
+// idempotencyCache deduplicates Dispatcher.Send calls carrying the same
+// Notification.IdempotencyKey, so a retried send (the caller never saw the
+// first attempt's response) can't result in a second message reaching the
+// customer.
+type idempotencyCache struct {
+	mu    sync.Mutex
+	seen  map[string]struct{}
+	order []string
+}
+
+func newIdempotencyCache() *idempotencyCache {
+	return &idempotencyCache{seen: make(map[string]struct{})}
+}
+
+// SeenBefore reports whether key has already been recorded, and records it
+// if not, as one atomic check-and-set.
+func (c *idempotencyCache) SeenBefore(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.seen[key]; ok {
+		return true
+	}
+
+	if len(c.order) >= idempotencyCacheCapacity {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		delete(c.seen, oldest)
+	}
+	c.seen[key] = struct{}{}
+	c.order = append(c.order, key)
+	return false
+}
@@ -0,0 +1,117 @@
+// Package notify is a unified multi-channel notification subsystem for
+// syncengage: one Notifier interface in front of pluggable email/SMS/push
+// transports, a routing layer, and a queued dispatcher handling retries,
+// rate limiting, idempotency, and delivery auditing - replacing the old
+// pattern of calling crm.SendGridEmailProvider/crm.TwilioSMSProvider
+// directly with no shared retry, tracking, or consent gating.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/compliance"
+)
+
+// Channel identifies a notification transport category.
+type Channel string
+
+const (
+	ChannelEmail   Channel = "email"
+	ChannelSMS     Channel = "sms"
+	ChannelWebhook Channel = "webhook"
+	ChannelPush    Channel = "push"
+)
+
+// Notification is one message to deliver to a customer over a channel.
+type Notification struct {
+	CustomerID string
+	Channel    Channel
+	To         string // email address, phone number, or webhook URL depending on Channel
+
+	// TemplateID/TemplateVersion/Locale select the rendered content from a
+	// TemplateRegistry. TemplateVersion 0 means "the latest registered
+	// version for this ID and locale".
+	TemplateID      string
+	TemplateVersion int
+	Locale          string
+	Data            map[string]interface{} // passed to the template renderer
+
+	// ProviderPreference names a registered transport (e.g. "sendgrid",
+	// "ses") to prefer for this send. Left empty, Router picks its default
+	// for Channel.
+	ProviderPreference string
+
+	// ConsentType gates this send on ConsentManager.HasConsent - a
+	// Notification with no ConsentType set is never gated (e.g.
+	// transactional password-reset email, not marketing).
+	ConsentType compliance.ConsentType
+
+	// IdempotencyKey dedupes retried Send calls carrying the same key. Left
+	// empty, Dispatcher derives one from CustomerID+Channel+TemplateID+To.
+	IdempotencyKey string
+
+	Metadata map[string]string
+}
+
+// Receipt records the outcome of a successful Send.
+type Receipt struct {
+	Provider  string
+	MessageID string
+	Status    string // "sent", "skipped_consent", "skipped_duplicate"
+	SentAt    time.Time
+}
+
+// Notifier is the single entry point callers use to send a notification,
+// regardless of which channel or transport ultimately carries it.
+type Notifier interface {
+	Send(ctx context.Context, notification Notification) (Receipt, error)
+}
+
+// Transport delivers a single Notification over one channel via one
+// provider (SendGrid, Twilio, a generic webhook, ...). Dispatcher selects
+// a Transport through Router and wraps every call with retry, rate
+// limiting, and idempotency - a Transport implementation itself stays as
+// simple as crm.SendGridEmailProvider/TwilioSMSProvider were.
+type Transport interface {
+	// Name identifies this transport for routing, rate limiting, and
+	// DeliveryEvent.Provider (e.g. "sendgrid", "twilio").
+	Name() string
+
+	// Channel reports which Notification.Channel this transport serves.
+	Channel() Channel
+
+	// Deliver sends the already-rendered content. subject/html/text are
+	// empty for channels that don't use them (SMS, webhook).
+	Deliver(ctx context.Context, n Notification, subject, html, text string) (messageID string, err error)
+}
+
+// DeliveryEvent is emitted on every delivery attempt, success, and
+// failure so it can flow into the existing audit logger (see
+// DeliveryLogger) the same way crm's write-back calls do.
+type DeliveryEvent struct {
+	CustomerID string
+	Channel    Channel
+	Provider   string
+	TemplateID string
+	Attempt    int
+	Outcome    string // "attempted", "succeeded", "failed", "skipped_consent", "skipped_duplicate"
+	Err        error
+	Timestamp  time.Time
+}
+
+// DeliveryLogger records DeliveryEvents. Left nil on a Dispatcher,
+// delivery events are simply not logged.
+type DeliveryLogger interface {
+	WriteDeliveryEvent(event DeliveryEvent)
+}
+
+// ErrNoTransport is returned when no Transport is registered for a
+// Notification's channel (and provider preference, if set).
+func errNoTransport(channel Channel, preference string) error {
+	if preference != "" {
+		return fmt.Errorf("notify: no %q transport registered for channel %s", preference, channel)
+	}
+	return fmt.Errorf("notify: no transport registered for channel %s", channel)
+}
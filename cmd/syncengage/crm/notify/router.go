@@ -0,0 +1,50 @@
+package notify
+
+import "sync"
+
+// Router picks a Transport for a Notification based on its Channel and
+// optional ProviderPreference, so Dispatcher never hardcodes which
+// provider carries a given channel.
+type Router struct {
+	mu         sync.RWMutex
+	byChannel  map[Channel][]Transport // registration order; index 0 is the default for its channel
+	byProvider map[string]Transport    // keyed by Transport.Name()
+}
+
+// NewRouter creates an empty router.
+func NewRouter() *Router {
+	return &Router{
+		byChannel:  make(map[Channel][]Transport),
+		byProvider: make(map[string]Transport),
+	}
+}
+
+// Register adds t as a candidate for its Channel(). The first Transport
+// registered for a channel becomes that channel's default.
+func (r *Router) Register(t Transport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byChannel[t.Channel()] = append(r.byChannel[t.Channel()], t)
+	r.byProvider[t.Name()] = t
+}
+
+// Route selects a Transport for channel, preferring the Transport named
+// preference if set and registered for that channel, otherwise falling
+// back to the channel's default (first registered).
+func (r *Router) Route(channel Channel, preference string) (Transport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if preference != "" {
+		if t, ok := r.byProvider[preference]; ok && t.Channel() == channel {
+			return t, nil
+		}
+		return nil, errNoTransport(channel, preference)
+	}
+
+	candidates := r.byChannel[channel]
+	if len(candidates) == 0 {
+		return nil, errNoTransport(channel, "")
+	}
+	return candidates[0], nil
+}
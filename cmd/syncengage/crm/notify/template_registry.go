@@ -0,0 +1,129 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	htemplate "html/template"
+	"sync"
+	ttemplate "text/template"
+)
+
+// TemplateKey identifies one version of one template in one locale.
+// Version lets a rollout register a new copy (say "2") while in-flight
+// sends and audit history still reference "1"; Locale carries the
+// per-language body alongside a shared ID (e.g. "welcome_email" rendered
+// from "en"/"de"/"ja" bodies).
+type TemplateKey struct {
+	ID      string
+	Version int
+	Locale  string
+}
+
+// Template is the source for one TemplateKey. Subject/TextBody are
+// text/template sources; HTMLBody is an html/template source so untrusted
+// Notification.Data fields are auto-escaped rather than interpolated
+// directly into markup.
+type Template struct {
+	Key      TemplateKey
+	Subject  string
+	HTMLBody string
+	TextBody string
+}
+
+// parsedTemplate holds the compiled form of a Template, parsed once at
+// Register time so Render on the hot path never re-parses template source.
+type parsedTemplate struct {
+	subject *ttemplate.Template
+	html    *htemplate.Template
+	text    *ttemplate.Template
+}
+
+// TemplateRegistry holds every registered Template version across every
+// locale, keyed by (ID, Version, Locale).
+type TemplateRegistry struct {
+	mu            sync.RWMutex
+	templates     map[TemplateKey]*parsedTemplate
+	latestVersion map[string]int // keyed by ID+"\x00"+Locale
+	// defaultLocale is used when Render is asked for a locale that has no
+	// registered template, so a missing translation degrades to a known
+	// language rather than failing the send outright.
+	defaultLocale string
+}
+
+// NewTemplateRegistry creates an empty registry that falls back to
+// defaultLocale (e.g. "en") when a requested locale isn't registered.
+func NewTemplateRegistry(defaultLocale string) *TemplateRegistry {
+	return &TemplateRegistry{
+		templates:     make(map[TemplateKey]*parsedTemplate),
+		latestVersion: make(map[string]int),
+		defaultLocale: defaultLocale,
+	}
+}
+
+// Register parses and stores tmpl, replacing any prior registration under
+// the same TemplateKey.
+func (r *TemplateRegistry) Register(tmpl Template) error {
+	subject, err := ttemplate.New(tmpl.Key.ID + "-subject").Parse(tmpl.Subject)
+	if err != nil {
+		return fmt.Errorf("notify: parsing subject for %s v%d (%s): %w", tmpl.Key.ID, tmpl.Key.Version, tmpl.Key.Locale, err)
+	}
+	html, err := htemplate.New(tmpl.Key.ID + "-html").Parse(tmpl.HTMLBody)
+	if err != nil {
+		return fmt.Errorf("notify: parsing HTML body for %s v%d (%s): %w", tmpl.Key.ID, tmpl.Key.Version, tmpl.Key.Locale, err)
+	}
+	text, err := ttemplate.New(tmpl.Key.ID + "-text").Parse(tmpl.TextBody)
+	if err != nil {
+		return fmt.Errorf("notify: parsing text body for %s v%d (%s): %w", tmpl.Key.ID, tmpl.Key.Version, tmpl.Key.Locale, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[tmpl.Key] = &parsedTemplate{subject: subject, html: html, text: text}
+
+	localeKey := tmpl.Key.ID + "\x00" + tmpl.Key.Locale
+	if tmpl.Key.Version > r.latestVersion[localeKey] {
+		r.latestVersion[localeKey] = tmpl.Key.Version
+	}
+	return nil
+}
+
+// Render looks up the template for (id, version, locale) - version 0 means
+// the latest registered version - falling back to the registry's default
+// locale if locale has no registration, and executes it against data.
+func (r *TemplateRegistry) Render(id string, version int, locale string, data interface{}) (subject, html, text string, err error) {
+	r.mu.RLock()
+	key, tmpl, ok := r.resolve(id, version, locale)
+	r.mu.RUnlock()
+
+	if !ok && locale != r.defaultLocale {
+		r.mu.RLock()
+		key, tmpl, ok = r.resolve(id, version, r.defaultLocale)
+		r.mu.RUnlock()
+	}
+	if !ok {
+		return "", "", "", fmt.Errorf("notify: no template registered for id=%s version=%d locale=%s", id, version, locale)
+	}
+
+	var subjectBuf, htmlBuf, textBuf bytes.Buffer
+	if err := tmpl.subject.Execute(&subjectBuf, data); err != nil {
+		return "", "", "", fmt.Errorf("notify: rendering subject for %v: %w", key, err)
+	}
+	if err := tmpl.html.Execute(&htmlBuf, data); err != nil {
+		return "", "", "", fmt.Errorf("notify: rendering HTML body for %v: %w", key, err)
+	}
+	if err := tmpl.text.Execute(&textBuf, data); err != nil {
+		return "", "", "", fmt.Errorf("notify: rendering text body for %v: %w", key, err)
+	}
+	return subjectBuf.String(), htmlBuf.String(), textBuf.String(), nil
+}
+
+// resolve finds the parsed template for (id, version, locale), treating
+// version 0 as "latest registered". Callers must hold at least r.mu.RLock.
+func (r *TemplateRegistry) resolve(id string, version int, locale string) (TemplateKey, *parsedTemplate, bool) {
+	if version == 0 {
+		version = r.latestVersion[id+"\x00"+locale]
+	}
+	key := TemplateKey{ID: id, Version: version, Locale: locale}
+	tmpl, ok := r.templates[key]
+	return key, tmpl, ok
+}
@@ -0,0 +1,198 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// SendGridTransport delivers Notifications over the SendGrid v3 Mail Send
+// API - the same request shape as crm.SendGridEmailProvider, now behind
+// the Transport interface so Dispatcher's retry/rate-limit/idempotency
+// wrapping applies uniformly across providers.
+type SendGridTransport struct {
+	APIKey     string
+	From       string
+	httpClient *http.Client
+}
+
+// NewSendGridTransport creates a SendGrid email transport sending from
+// fromAddress.
+func NewSendGridTransport(apiKey, fromAddress string) *SendGridTransport {
+	return &SendGridTransport{
+		APIKey:     apiKey,
+		From:       fromAddress,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *SendGridTransport) Name() string     { return "sendgrid" }
+func (s *SendGridTransport) Channel() Channel { return ChannelEmail }
+
+func (s *SendGridTransport) Deliver(ctx context.Context, n Notification, subject, html, text string) (string, error) {
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []map[string]string{{"email": n.To}}},
+		},
+		"from":    map[string]string{"email": s.From},
+		"subject": subject,
+		"content": []map[string]string{
+			{"type": "text/html", "value": html},
+			{"type": "text/plain", "value": text},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.sendgrid.com/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("sendgrid: API error: %d", resp.StatusCode)
+	}
+	// SendGrid's Mail Send endpoint returns the message ID in a header, not
+	// a body, on success.
+	return resp.Header.Get("X-Message-Id"), nil
+}
+
+// SESTransport delivers Notifications over the Amazon SES v2 SendEmail
+// API. AWS request signing (SigV4) is the caller's responsibility via
+// Sign, the same "bring your own signer" shape WebhookConnector uses for
+// HMAC - this package doesn't take on an AWS SDK dependency just to sign
+// one request type.
+type SESTransport struct {
+	Endpoint   string // e.g. https://email.us-east-1.amazonaws.com
+	From       string
+	Sign       func(req *http.Request) error
+	httpClient *http.Client
+}
+
+// NewSESTransport creates an SES email transport against endpoint, signing
+// every request with sign before it's sent.
+func NewSESTransport(endpoint, fromAddress string, sign func(req *http.Request) error) *SESTransport {
+	return &SESTransport{
+		Endpoint:   endpoint,
+		From:       fromAddress,
+		Sign:       sign,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *SESTransport) Name() string     { return "ses" }
+func (s *SESTransport) Channel() Channel { return ChannelEmail }
+
+func (s *SESTransport) Deliver(ctx context.Context, n Notification, subject, html, text string) (string, error) {
+	payload := map[string]interface{}{
+		"FromEmailAddress": s.From,
+		"Destination":      map[string][]string{"ToAddresses": {n.To}},
+		"Content": map[string]interface{}{
+			"Simple": map[string]interface{}{
+				"Subject": map[string]string{"Data": subject},
+				"Body": map[string]interface{}{
+					"Html": map[string]string{"Data": html},
+					"Text": map[string]string{"Data": text},
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.Sign != nil {
+		if err := s.Sign(req); err != nil {
+			return "", fmt.Errorf("ses: signing request: %w", err)
+		}
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ses: API error: %d", resp.StatusCode)
+	}
+	var out struct {
+		MessageId string `json:"MessageId"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return out.MessageId, nil
+}
+
+// MailgunTransport delivers Notifications over the Mailgun Messages API.
+type MailgunTransport struct {
+	APIKey     string
+	Domain     string
+	From       string
+	httpClient *http.Client
+}
+
+// NewMailgunTransport creates a Mailgun email transport for domain,
+// sending from fromAddress.
+func NewMailgunTransport(apiKey, domain, fromAddress string) *MailgunTransport {
+	return &MailgunTransport{
+		APIKey:     apiKey,
+		Domain:     domain,
+		From:       fromAddress,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (m *MailgunTransport) Name() string     { return "mailgun" }
+func (m *MailgunTransport) Channel() Channel { return ChannelEmail }
+
+func (m *MailgunTransport) Deliver(ctx context.Context, n Notification, subject, html, text string) (string, error) {
+	form := url.Values{}
+	form.Set("from", m.From)
+	form.Set("to", n.To)
+	form.Set("subject", subject)
+	form.Set("html", html)
+	form.Set("text", text)
+
+	apiURL := fmt.Sprintf("https://api.mailgun.net/v3/%s/messages", m.Domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewBufferString(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth("api", m.APIKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("mailgun: API error: %d", resp.StatusCode)
+	}
+	var out struct {
+		ID string `json:"id"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return out.ID, nil
+}
@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TwilioTransport delivers Notifications over the Twilio Messages API -
+// the same request shape as crm.TwilioSMSProvider, now behind the
+// Transport interface.
+type TwilioTransport struct {
+	AccountSID string
+	AuthToken  string
+	FromPhone  string
+	httpClient *http.Client
+}
+
+// NewTwilioTransport creates a Twilio SMS transport sending from fromPhone.
+func NewTwilioTransport(accountSID, authToken, fromPhone string) *TwilioTransport {
+	return &TwilioTransport{
+		AccountSID: accountSID,
+		AuthToken:  authToken,
+		FromPhone:  fromPhone,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (t *TwilioTransport) Name() string     { return "twilio" }
+func (t *TwilioTransport) Channel() Channel { return ChannelSMS }
+
+func (t *TwilioTransport) Deliver(ctx context.Context, n Notification, subject, html, text string) (string, error) {
+	apiURL := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", t.AccountSID)
+
+	form := url.Values{}
+	form.Set("To", n.To)
+	form.Set("From", t.FromPhone)
+	form.Set("Body", text)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewBufferString(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(t.AccountSID, t.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("twilio: API error: %d", resp.StatusCode)
+	}
+	var out struct {
+		SID string `json:"sid"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return out.SID, nil
+}
+
+// MessageBirdTransport delivers Notifications over the MessageBird
+// Messages API.
+type MessageBirdTransport struct {
+	APIKey     string
+	FromPhone  string
+	httpClient *http.Client
+}
+
+// NewMessageBirdTransport creates a MessageBird SMS transport sending
+// from fromPhone.
+func NewMessageBirdTransport(apiKey, fromPhone string) *MessageBirdTransport {
+	return &MessageBirdTransport{
+		APIKey:     apiKey,
+		FromPhone:  fromPhone,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (m *MessageBirdTransport) Name() string     { return "messagebird" }
+func (m *MessageBirdTransport) Channel() Channel { return ChannelSMS }
+
+func (m *MessageBirdTransport) Deliver(ctx context.Context, n Notification, subject, html, text string) (string, error) {
+	payload := map[string]string{
+		"originator": m.FromPhone,
+		"recipients": n.To,
+		"body":       text,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://rest.messagebird.com/messages", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "AccessKey "+m.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("messagebird: API error: %d", resp.StatusCode)
+	}
+	var out struct {
+		ID string `json:"id"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return out.ID, nil
+}
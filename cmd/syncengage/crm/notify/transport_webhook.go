@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/signing"
+)
+
+// WebhookTransport delivers Notifications over channel ChannelWebhook (or
+// ChannelPush, for a push-notification gateway that happens to expose a
+// webhook-shaped HTTP endpoint) by POSTing a JSON event to Notification.To.
+type WebhookTransport struct {
+	channel Channel
+
+	// Signer, when set, signs every outbound request the way
+	// crm.WebhookConnector does, so the receiving endpoint can verify the
+	// event actually came from this transport. Left nil, requests are
+	// sent unsigned.
+	Signer signing.Signer
+
+	httpClient *http.Client
+}
+
+// NewWebhookTransport creates a transport delivering over channel (usually
+// ChannelWebhook or ChannelPush), signed by signer if non-nil.
+func NewWebhookTransport(channel Channel, signer signing.Signer) *WebhookTransport {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	if signer != nil {
+		httpClient.Transport = signing.NewSigningTransport(signer, http.DefaultTransport, 0)
+	}
+	return &WebhookTransport{channel: channel, Signer: signer, httpClient: httpClient}
+}
+
+func (w *WebhookTransport) Name() string     { return "webhook" }
+func (w *WebhookTransport) Channel() Channel { return w.channel }
+
+func (w *WebhookTransport) Deliver(ctx context.Context, n Notification, subject, html, text string) (string, error) {
+	payload := map[string]interface{}{
+		"customer_id": n.CustomerID,
+		"subject":     subject,
+		"html":        html,
+		"text":        text,
+		"metadata":    n.Metadata,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.To, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", n.IdempotencyKey)
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("webhook: %s returned %d", n.To, resp.StatusCode)
+	}
+	return "", nil
+}
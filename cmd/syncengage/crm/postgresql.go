@@ -1,157 +1,282 @@
-package crm
-
-import (
-	"database/sql"
-	"time"
-
-	_ "github.com/lib/pq" // PostgreSQL driver
-)
-
-// PostgreSQLConnector integrates with custom PostgreSQL CRM database
-type PostgreSQLConnector struct {
-	db         *sql.DB
-	connString string
-}
-
-// NewPostgreSQLConnector creates a new PostgreSQL CRM connector
-func NewPostgreSQLConnector(connString string) *PostgreSQLConnector {
-	return &PostgreSQLConnector{
-		connString: connString,
-	}
-}
-
-func (p *PostgreSQLConnector) Connect() error {
-	var err error
-	p.db, err = sql.Open("postgres", p.connString)
-	if err != nil {
-		return err
-	}
-
-	// Test connection
-	if err := p.db.Ping(); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (p *PostgreSQLConnector) FetchCustomers(filter map[string]interface{}) ([]Customer, error) {
-	query := `
-		SELECT 
-			id, email, phone, first_name, last_name,
-			last_purchase_date, total_spend, purchase_count,
-			engagement_score, last_engagement_date
-		FROM customers
-		WHERE last_purchase_date > NOW() - INTERVAL '180 days'
-		LIMIT 1000
-	`
-
-	rows, err := p.db.Query(query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	customers := make([]Customer, 0)
-	for rows.Next() {
-		var c Customer
-		var lastPurchase, lastEngagement sql.NullTime
-
-		err := rows.Scan(
-			&c.ID, &c.Email, &c.Phone, &c.FirstName, &c.LastName,
-			&lastPurchase, &c.TotalSpend, &c.PurchaseCount,
-			&c.EngagementScore, &lastEngagement,
-		)
-		if err != nil {
-			continue
-		}
-
-		if lastPurchase.Valid {
-			c.LastPurchase = lastPurchase.Time
-		}
-		if lastEngagement.Valid {
-			c.LastEngagement = lastEngagement.Time
-		}
-
-		customers = append(customers, c)
-	}
-
-	return customers, nil
-}
-
-func (p *PostgreSQLConnector) GetCustomer(id string) (*Customer, error) {
-	query := `
-		SELECT 
-			id, email, phone, first_name, last_name,
-			last_purchase_date, total_spend, purchase_count,
-			engagement_score, last_engagement_date
-		FROM customers
-		WHERE id = $1
-	`
-
-	var c Customer
-	var lastPurchase, lastEngagement sql.NullTime
-
-	err := p.db.QueryRow(query, id).Scan(
-		&c.ID, &c.Email, &c.Phone, &c.FirstName, &c.LastName,
-		&lastPurchase, &c.TotalSpend, &c.PurchaseCount,
-		&c.EngagementScore, &lastEngagement,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	if lastPurchase.Valid {
-		c.LastPurchase = lastPurchase.Time
-	}
-	if lastEngagement.Valid {
-		c.LastEngagement = lastEngagement.Time
-	}
-
-	return &c, nil
-}
-
-func (p *PostgreSQLConnector) UpdateCustomer(customer Customer) error {
-	query := `
-		UPDATE customers
-		SET engagement_score = $2, last_engagement_date = $3
-		WHERE id = $1
-	`
-
-	_, err := p.db.Exec(query, customer.ID, customer.EngagementScore, time.Now())
-	return err
-}
-
-func (p *PostgreSQLConnector) SendMessage(customerID, channel, message string, metadata map[string]string) error {
-	// Log message to outbound_messages table
-	query := `
-		INSERT INTO outbound_messages (customer_id, channel, message, metadata, created_at)
-		VALUES ($1, $2, $3, $4, $5)
-	`
-
-	metadataJSON := "{}"
-	_, err := p.db.Exec(query, customerID, channel, message, metadataJSON, time.Now())
-	return err
-}
-
-func (p *PostgreSQLConnector) CreateTag(customerID, tag string) error {
-	query := `
-		INSERT INTO customer_tags (customer_id, tag, created_at)
-		VALUES ($1, $2, $3)
-		ON CONFLICT DO NOTHING
-	`
-
-	_, err := p.db.Exec(query, customerID, tag, time.Now())
-	return err
-}
-
-func (p *PostgreSQLConnector) GetName() string {
-	return "PostgreSQL"
-}
-
-func (p *PostgreSQLConnector) Close() error {
-	if p.db != nil {
-		return p.db.Close()
-	}
-	return nil
-}
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// IsRetryablePG classifies a Postgres error as transient by inspecting
+// *pq.Error.Code.Class() - Postgres groups its SQLSTATE codes into
+// two-character classes, and these four are the ones a retry can
+// plausibly ride out:
+//
+//   - 08 (connection exception): e.g. 08006 connection_failure
+//   - 40 (transaction rollback): e.g. 40001 serialization_failure under
+//     SERIALIZABLE/REPEATABLE READ - safe to retry with fresh statements
+//   - 53 (insufficient resources): e.g. too_many_connections, out_of_memory
+//   - 57 (operator intervention): e.g. 57P03 cannot_connect_now, returned
+//     while Postgres is still starting up or failing over
+//
+// A plain net.Error below the driver level (connection reset, dial
+// timeout) is also treated as transient, since pq doesn't always wrap
+// those in a *pq.Error.
+func IsRetryablePG(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "08", "40", "53", "57":
+			return true
+		default:
+			return false
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return shield.DefaultIsRetryable(err)
+}
+
+// PostgreSQLConnector integrates with custom PostgreSQL CRM database
+type PostgreSQLConnector struct {
+	db         *sql.DB
+	connString string
+
+	// retryPolicy and breaker guard every query/exec against transient
+	// Postgres errors (dropped connections, failover, serialization
+	// failures) the same way connectors.CallPolicy guards an outbound ad
+	// platform call - one retry/backoff cycle per logical operation, with
+	// the whole cycle's outcome recorded against breaker rather than each
+	// individual attempt.
+	retryPolicy *shield.RetryPolicy
+	breaker     *shield.CircuitBreaker
+}
+
+// NewPostgreSQLConnector creates a new PostgreSQL CRM connector
+func NewPostgreSQLConnector(connString string) *PostgreSQLConnector {
+	retryPolicy := shield.NewPostgresRetryPolicy()
+	retryPolicy.SetRetryableChecker(IsRetryablePG, true)
+
+	return &PostgreSQLConnector{
+		connString:  connString,
+		retryPolicy: retryPolicy,
+		breaker:     shield.NewCircuitBreaker(),
+	}
+}
+
+func (p *PostgreSQLConnector) Connect() error {
+	var err error
+	p.db, err = sql.Open("postgres", p.connString)
+	if err != nil {
+		return err
+	}
+
+	// Test connection
+	if err := p.db.Ping(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// withRetry runs fn under p.retryPolicy, guarding and recording the whole
+// cycle against p.breaker the way connectors.CallPolicy.Execute does for an
+// outbound call - one CanExecute check up front, one RecordSuccess/
+// RecordFailure at the end, rather than per attempt.
+func (p *PostgreSQLConnector) withRetry(fn func() error) error {
+	if !p.breaker.CanExecute() {
+		return fmt.Errorf("postgresql: circuit breaker open")
+	}
+
+	start := time.Now()
+	err := p.retryPolicy.Exec(context.Background(), fn)
+	latency := time.Since(start)
+	if err != nil {
+		p.breaker.RecordFailure(latency)
+	} else {
+		p.breaker.RecordSuccess(latency)
+	}
+	return err
+}
+
+// WithTx runs fn inside a transaction, retrying the whole transaction -
+// BEGIN through COMMIT - from scratch on a 40001 serialization_failure or
+// any other error IsRetryablePG treats as transient. A transaction that
+// loses a serialization fight needs fresh statements run against a new
+// snapshot, not a replay of the same prepared ones, so each retry gets its
+// own fn(tx) call rather than resuming the failed one.
+func (p *PostgreSQLConnector) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return p.withRetry(func() error {
+		tx, err := p.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return nil
+	})
+}
+
+func (p *PostgreSQLConnector) FetchCustomers(filter map[string]interface{}) ([]Customer, error) {
+	query := `
+		SELECT
+			id, email, phone, first_name, last_name,
+			last_purchase_date, total_spend, purchase_count,
+			engagement_score, last_engagement_date
+		FROM customers
+		WHERE last_purchase_date > NOW() - INTERVAL '180 days'
+		LIMIT 1000
+	`
+
+	var customers []Customer
+	err := p.withRetry(func() error {
+		rows, err := p.db.Query(query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		customers = make([]Customer, 0)
+		for rows.Next() {
+			var c Customer
+			var lastPurchase, lastEngagement sql.NullTime
+
+			err := rows.Scan(
+				&c.ID, &c.Email, &c.Phone, &c.FirstName, &c.LastName,
+				&lastPurchase, &c.TotalSpend, &c.PurchaseCount,
+				&c.EngagementScore, &lastEngagement,
+			)
+			if err != nil {
+				continue
+			}
+
+			if lastPurchase.Valid {
+				c.LastPurchase = lastPurchase.Time
+			}
+			if lastEngagement.Valid {
+				c.LastEngagement = lastEngagement.Time
+			}
+
+			customers = append(customers, c)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return customers, nil
+}
+
+func (p *PostgreSQLConnector) GetCustomer(id string) (*Customer, error) {
+	query := `
+		SELECT
+			id, email, phone, first_name, last_name,
+			last_purchase_date, total_spend, purchase_count,
+			engagement_score, last_engagement_date
+		FROM customers
+		WHERE id = $1
+	`
+
+	var c Customer
+	err := p.withRetry(func() error {
+		var lastPurchase, lastEngagement sql.NullTime
+
+		err := p.db.QueryRow(query, id).Scan(
+			&c.ID, &c.Email, &c.Phone, &c.FirstName, &c.LastName,
+			&lastPurchase, &c.TotalSpend, &c.PurchaseCount,
+			&c.EngagementScore, &lastEngagement,
+		)
+		if err != nil {
+			return err
+		}
+
+		if lastPurchase.Valid {
+			c.LastPurchase = lastPurchase.Time
+		}
+		if lastEngagement.Valid {
+			c.LastEngagement = lastEngagement.Time
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (p *PostgreSQLConnector) UpdateCustomer(customer Customer) error {
+	query := `
+		UPDATE customers
+		SET engagement_score = $2, last_engagement_date = $3
+		WHERE id = $1
+	`
+
+	return p.withRetry(func() error {
+		_, err := p.db.Exec(query, customer.ID, customer.EngagementScore, time.Now())
+		return err
+	})
+}
+
+func (p *PostgreSQLConnector) SendMessage(customerID, channel, message string, metadata map[string]string) error {
+	// Log message to outbound_messages table
+	query := `
+		INSERT INTO outbound_messages (customer_id, channel, message, metadata, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	metadataJSON := "{}"
+	return p.withRetry(func() error {
+		_, err := p.db.Exec(query, customerID, channel, message, metadataJSON, time.Now())
+		return err
+	})
+}
+
+func (p *PostgreSQLConnector) CreateTag(customerID, tag string) error {
+	query := `
+		INSERT INTO customer_tags (customer_id, tag, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+
+	return p.withRetry(func() error {
+		_, err := p.db.Exec(query, customerID, tag, time.Now())
+		return err
+	})
+}
+
+func (p *PostgreSQLConnector) GetName() string {
+	return "PostgreSQL"
+}
+
+func (p *PostgreSQLConnector) Close() error {
+	if p.db != nil {
+		return p.db.Close()
+	}
+	return nil
+}
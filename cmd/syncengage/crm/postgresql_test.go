@@ -0,0 +1,62 @@
+package crm
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestIsRetryablePG_ClassifiesPostgresErrorCodesByClass(t *testing.T) {
+	tests := []struct {
+		name string
+		code pq.ErrorCode
+		want bool
+	}{
+		{"connection_failure", "08006", true},    // class 08: connection exception
+		{"serialization_failure", "40001", true}, // class 40: transaction rollback
+		{"too_many_connections", "53300", true},  // class 53: insufficient resources
+		{"cannot_connect_now", "57P03", true},    // class 57: operator intervention
+		{"unique_violation", "23505", false},     // class 23: integrity constraint - not transient
+		{"syntax_error", "42601", false},         // class 42: syntax/access rule - not transient
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &pq.Error{Code: tt.code}
+			if got := IsRetryablePG(err); got != tt.want {
+				t.Errorf("IsRetryablePG(code %s) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryablePG_WrappedPqErrorStillClassifies(t *testing.T) {
+	wrapped := errors.Join(errors.New("query failed"), &pq.Error{Code: "40001"})
+	if !IsRetryablePG(wrapped) {
+		t.Error("expected a wrapped serialization_failure to be retryable")
+	}
+}
+
+func TestIsRetryablePG_NetErrorIsRetryable(t *testing.T) {
+	err := &net.OpError{Op: "dial", Err: errors.New("connection reset")}
+	if !IsRetryablePG(err) {
+		t.Error("expected a net.Error to be retryable")
+	}
+}
+
+func TestIsRetryablePG_NilErrorIsNotRetryable(t *testing.T) {
+	if IsRetryablePG(nil) {
+		t.Error("expected a nil error to be non-retryable")
+	}
+}
+
+func TestIsRetryablePG_FallsBackToDefaultIsRetryableForPlainErrors(t *testing.T) {
+	if !IsRetryablePG(errors.New("dial tcp: connection refused")) {
+		t.Error("expected a plain connection-refused error to be retryable via DefaultIsRetryable")
+	}
+	if IsRetryablePG(errors.New("customer not found")) {
+		t.Error("expected an unrelated plain error to be non-retryable")
+	}
+}
@@ -1,156 +1,514 @@
-package crm
-
-import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"time"
-)
-
-// SalesforceConnector integrates with Salesforce CRM
-type SalesforceConnector struct {
-	InstanceURL string
-	AccessToken string
-	httpClient  *http.Client
-}
-
-// NewSalesforceConnector creates a new Salesforce CRM connector
-func NewSalesforceConnector(instanceURL, accessToken string) *SalesforceConnector {
-	return &SalesforceConnector{
-		InstanceURL: instanceURL,
-		AccessToken: accessToken,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-	}
-}
-
-func (s *SalesforceConnector) Connect() error {
-	// Test connection by querying user info
-	req, err := http.NewRequest("GET", s.InstanceURL+"/services/oauth2/userinfo", nil)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Authorization", "Bearer "+s.AccessToken)
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("Salesforce authentication failed: %d", resp.StatusCode)
-	}
-
-	return nil
-}
-
-func (s *SalesforceConnector) FetchCustomers(filter map[string]interface{}) ([]Customer, error) {
-	query := "SELECT Id, Email, FirstName, LastName, Phone FROM Contact LIMIT 100"
-	url := fmt.Sprintf("%s/services/data/v58.0/query/?q=%s", s.InstanceURL, query)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Authorization", "Bearer "+s.AccessToken)
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var sfResp struct {
-		Records []struct {
-			ID        string `json:"Id"`
-			Email     string `json:"Email"`
-			FirstName string `json:"FirstName"`
-			LastName  string `json:"LastName"`
-			Phone     string `json:"Phone"`
-		} `json:"records"`
-	}
-
-	if err := json.Unmarshal(body, &sfResp); err != nil {
-		return nil, err
-	}
-
-	customers := make([]Customer, 0, len(sfResp.Records))
-	for _, record := range sfResp.Records {
-		customers = append(customers, Customer{
-			ID:        record.ID,
-			Email:     record.Email,
-			FirstName: record.FirstName,
-			LastName:  record.LastName,
-			Phone:     record.Phone,
-		})
-	}
-
-	return customers, nil
-}
-
-func (s *SalesforceConnector) GetCustomer(id string) (*Customer, error) {
-	url := fmt.Sprintf("%s/services/data/v58.0/sobjects/Contact/%s", s.InstanceURL, id)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Authorization", "Bearer "+s.AccessToken)
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var record struct {
-		ID        string `json:"Id"`
-		Email     string `json:"Email"`
-		FirstName string `json:"FirstName"`
-		LastName  string `json:"LastName"`
-	}
-
-	if err := json.Unmarshal(body, &record); err != nil {
-		return nil, err
-	}
-
-	return &Customer{
-		ID:        record.ID,
-		Email:     record.Email,
-		FirstName: record.FirstName,
-		LastName:  record.LastName,
-	}, nil
-}
-
-func (s *SalesforceConnector) UpdateCustomer(customer Customer) error {
-	return fmt.Errorf("not implemented")
-}
-
-func (s *SalesforceConnector) SendMessage(customerID, channel, message string, metadata map[string]string) error {
-	return fmt.Errorf("not implemented")
-}
-
-func (s *SalesforceConnector) CreateTag(customerID, tag string) error {
-	return fmt.Errorf("not implemented")
-}
-
-func (s *SalesforceConnector) GetName() string {
-	return "Salesforce"
-}
-
-func (s *SalesforceConnector) Close() error {
-	return nil
-}
+package crm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/auth"
+	"github.com/user/kiki-agent/cmd/syncshield/compliance"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// salesforceAPIVersion is the REST/Bulk API version every endpoint below
+// targets.
+const salesforceAPIVersion = "v58.0"
+
+// salesforceBatchSize is the largest top-level record count Salesforce's
+// Composite Tree API accepts in one request.
+const salesforceBatchSize = 25
+
+// salesforceTokenCache shares one refreshing oauth2.TokenSource per
+// instance URL across every SalesforceConnector authenticating against
+// it, the same way GoogleAdsSmartConnector's defaultTokenCache avoids
+// redundant token endpoint calls.
+var salesforceTokenCache = auth.NewTokenCache()
+
+// SalesforceConnector integrates with Salesforce CRM via the REST API,
+// authenticating with a refreshing OAuth2 token instead of a bare
+// long-lived AccessToken.
+type SalesforceConnector struct {
+	InstanceURL  string
+	AuthProvider auth.AuthProvider
+	httpClient   *http.Client
+
+	RateLimiter    *connectors.RateLimiter
+	CircuitBreaker *shield.CircuitBreaker
+
+	// BulkRetryPolicy governs backoff on 429/503 responses from the Bulk
+	// API 2.0 endpoints StreamCustomers uses - job creation, status polls,
+	// and results pages. See salesforce_bulk.go.
+	BulkRetryPolicy *shield.RetryPolicy
+
+	// ComplianceLogger, when set, records a crm_data_access AuditEvent for
+	// every FetchCustomers/GetCustomer read, so GDPR Article 30 records of
+	// processing are automatic rather than relying on a caller to log CRM
+	// reads by hand.
+	ComplianceLogger *compliance.GDPRAuditLogger
+
+	// AuditLogger, when set, receives a CRM_WRITEBACK AuditEntry for every
+	// UpdateCustomer/CreateTag/SendMessage call, matching HubSpotConnector.
+	AuditLogger crmWritebackLogger
+
+	batch       *BatchUpdateBuffer
+	idempotency *idempotencyCache
+
+	cursorMu sync.Mutex
+	// nextRecordsURL is Salesforce's paging cursor from the last
+	// FetchCustomers call; empty means the next call starts a fresh query
+	// rather than continuing a previous one.
+	nextRecordsURL string
+
+	cacheMu   sync.Mutex
+	lastFetch []Customer // served by FetchCustomers when CircuitBreaker is open
+}
+
+// NewSalesforceConnector creates a Salesforce CRM connector that
+// authenticates via authProvider (typically auth.NewUserAuthProvider with
+// a previously-issued refresh token) against instanceURL, e.g.
+// "https://mycompany.my.salesforce.com".
+func NewSalesforceConnector(instanceURL string, authProvider auth.AuthProvider) *SalesforceConnector {
+	tokenSource := salesforceTokenCache.Shared(context.Background(), instanceURL, authProvider)
+	httpClient := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &oauth2.Transport{Source: tokenSource, Base: http.DefaultTransport},
+	}
+
+	s := &SalesforceConnector{
+		InstanceURL:    instanceURL,
+		AuthProvider:   authProvider,
+		httpClient:     httpClient,
+		RateLimiter:    connectors.NewRateLimiter(1000), // Salesforce's default per-org limit is generous; this just smooths bursts
+		CircuitBreaker: shield.NewCircuitBreaker(),
+		BulkRetryPolicy: &shield.RetryPolicy{
+			MaxAttempts:       5,
+			InitialBackoff:    500 * time.Millisecond,
+			MaxBackoff:        30 * time.Second,
+			BackoffMultiplier: 2.0,
+			JitterFraction:    0.25,
+			IsRetryable:       isRetryableBulkStatus,
+		},
+		idempotency: newIdempotencyCache(),
+	}
+	s.batch = NewBatchUpdateBuffer(salesforceBatchSize, s.flushUpdateBatch)
+	return s
+}
+
+func (s *SalesforceConnector) Connect() error {
+	ctx := context.Background()
+	if err := s.RateLimiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", s.InstanceURL+"/services/oauth2/userinfo", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.CircuitBreaker.RecordFailure(0)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		s.CircuitBreaker.RecordFailure(0)
+		return fmt.Errorf("Salesforce authentication failed: %d", resp.StatusCode)
+	}
+	s.CircuitBreaker.RecordSuccess(0)
+	return nil
+}
+
+// salesforceQueryResponse is the shape every SOQL query endpoint
+// (query/queryMore) returns.
+type salesforceQueryResponse struct {
+	Records []struct {
+		ID        string `json:"Id"`
+		Email     string `json:"Email"`
+		FirstName string `json:"FirstName"`
+		LastName  string `json:"LastName"`
+		Phone     string `json:"Phone"`
+	} `json:"records"`
+	NextRecordsURL string `json:"nextRecordsUrl"`
+	Done           bool   `json:"done"`
+}
+
+// FetchCustomers returns the next page of Contact records. The first call
+// runs a fresh SOQL query; subsequent calls follow Salesforce's
+// nextRecordsUrl cursor until the result set is exhausted, at which point
+// the cursor resets and the next call starts over from the first page.
+// If CircuitBreaker is open, it serves the last successfully fetched page
+// instead of calling out to a Salesforce that's already failing.
+func (s *SalesforceConnector) FetchCustomers(filter map[string]interface{}) ([]Customer, error) {
+	if !s.CircuitBreaker.CanExecute() {
+		s.CircuitBreaker.RecordFallback()
+		s.cacheMu.Lock()
+		cached := s.lastFetch
+		s.cacheMu.Unlock()
+		if cached != nil {
+			return cached, nil
+		}
+		return nil, fmt.Errorf("salesforce: circuit open and no cached page to fall back to")
+	}
+
+	ctx := context.Background()
+	if err := s.RateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	s.cursorMu.Lock()
+	cursor := s.nextRecordsURL
+	s.cursorMu.Unlock()
+
+	var url string
+	if cursor != "" {
+		url = s.InstanceURL + cursor
+	} else {
+		query := "SELECT Id, Email, FirstName, LastName, Phone FROM Contact LIMIT 200"
+		url = fmt.Sprintf("%s/services/data/%s/query/?q=%s", s.InstanceURL, salesforceAPIVersion, query)
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		s.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		s.CircuitBreaker.RecordFailure(0)
+		return nil, fmt.Errorf("Salesforce FetchCustomers failed: %d", resp.StatusCode)
+	}
+
+	var sfResp salesforceQueryResponse
+	if err := json.Unmarshal(body, &sfResp); err != nil {
+		s.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	s.CircuitBreaker.RecordSuccess(0)
+
+	s.cursorMu.Lock()
+	if sfResp.Done {
+		s.nextRecordsURL = ""
+	} else {
+		s.nextRecordsURL = sfResp.NextRecordsURL
+	}
+	s.cursorMu.Unlock()
+
+	customers := make([]Customer, 0, len(sfResp.Records))
+	for _, record := range sfResp.Records {
+		customers = append(customers, Customer{
+			ID:        record.ID,
+			Email:     record.Email,
+			FirstName: record.FirstName,
+			LastName:  record.LastName,
+			Phone:     record.Phone,
+		})
+	}
+
+	s.cacheMu.Lock()
+	s.lastFetch = customers
+	s.cacheMu.Unlock()
+
+	s.logDataAccess(customers, "FetchCustomers")
+	return customers, nil
+}
+
+func (s *SalesforceConnector) GetCustomer(id string) (*Customer, error) {
+	if err := s.RateLimiter.Wait(context.Background()); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/services/data/%s/sobjects/Contact/%s", s.InstanceURL, salesforceAPIVersion, id)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		s.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		s.CircuitBreaker.RecordFailure(0)
+		return nil, fmt.Errorf("Salesforce GetCustomer failed: %d", resp.StatusCode)
+	}
+
+	var record struct {
+		ID        string `json:"Id"`
+		Email     string `json:"Email"`
+		FirstName string `json:"FirstName"`
+		LastName  string `json:"LastName"`
+	}
+	if err := json.Unmarshal(body, &record); err != nil {
+		s.CircuitBreaker.RecordFailure(0)
+		return nil, err
+	}
+	s.CircuitBreaker.RecordSuccess(0)
+
+	customer := &Customer{
+		ID:        record.ID,
+		Email:     record.Email,
+		FirstName: record.FirstName,
+		LastName:  record.LastName,
+	}
+	s.logDataAccess([]Customer{*customer}, "GetCustomer")
+	return customer, nil
+}
+
+// UpdateCustomer queues customer for Salesforce's Composite Tree API
+// rather than issuing a PATCH per call; the write only reaches Salesforce
+// once salesforceBatchSize customers are queued or Flush is called
+// explicitly.
+func (s *SalesforceConnector) UpdateCustomer(customer Customer) error {
+	return s.batch.Add(customer)
+}
+
+// Flush sends every UpdateCustomer call queued so far, regardless of
+// whether a full batch has accumulated - callers that need a write
+// acknowledged before proceeding (e.g. before a scheduled job exits)
+// should call this directly.
+func (s *SalesforceConnector) Flush() error {
+	return s.batch.Flush()
+}
+
+// salesforceCompositeTreeNode is one Contact record in a Composite Tree
+// request body.
+type salesforceCompositeTreeNode struct {
+	Attributes struct {
+		Type        string `json:"type"`
+		ReferenceID string `json:"referenceId"`
+	} `json:"attributes"`
+	ID        string `json:"Id,omitempty"`
+	Email     string `json:"Email,omitempty"`
+	FirstName string `json:"FirstName,omitempty"`
+	LastName  string `json:"LastName,omitempty"`
+	Phone     string `json:"Phone,omitempty"`
+}
+
+// flushUpdateBatch is BatchUpdateBuffer's flush function: it submits every
+// customer in batch as one Composite Tree request.
+func (s *SalesforceConnector) flushUpdateBatch(batch []Customer) error {
+	if err := s.RateLimiter.Wait(context.Background()); err != nil {
+		return err
+	}
+
+	records := make([]salesforceCompositeTreeNode, len(batch))
+	for i, c := range batch {
+		records[i].Attributes.Type = "Contact"
+		records[i].Attributes.ReferenceID = fmt.Sprintf("ref%d", i)
+		records[i].ID = c.ID
+		records[i].Email = c.Email
+		records[i].FirstName = c.FirstName
+		records[i].LastName = c.LastName
+		records[i].Phone = c.Phone
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/services/data/%s/composite/tree/Contact", s.InstanceURL, salesforceAPIVersion)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.CircuitBreaker.RecordFailure(0)
+		s.auditBatchWriteback(batch, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		s.CircuitBreaker.RecordFailure(0)
+		err = fmt.Errorf("Salesforce Composite Tree update failed: %d", resp.StatusCode)
+		s.auditBatchWriteback(batch, err)
+		return err
+	}
+
+	s.CircuitBreaker.RecordSuccess(0)
+	s.auditBatchWriteback(batch, nil)
+	return nil
+}
+
+// salesforceSupportedMessageChannels mirrors HubSpotConnector's channel
+// allowlist; Salesforce delivers both through Marketing Cloud's single
+// Messaging API.
+var salesforceSupportedMessageChannels = map[string]bool{"email": true, "sms": true}
+
+// SendMessage delivers a message through Salesforce's Messaging API,
+// deduplicating on (customerID, channel, message) so a caller's retry of
+// a call whose response it never saw can't result in a second send.
+func (s *SalesforceConnector) SendMessage(customerID, channel, message string, metadata map[string]string) error {
+	if !salesforceSupportedMessageChannels[channel] {
+		return fmt.Errorf("unsupported Salesforce message channel: %s", channel)
+	}
+
+	key := idempotencyKey(customerID, channel, message)
+	if s.idempotency.SeenBefore(key) {
+		return nil
+	}
+
+	if err := s.RateLimiter.Wait(context.Background()); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"channel":    strings.ToUpper(channel),
+		"recipient":  customerID,
+		"text":       message,
+		"attributes": metadata,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/services/data/%s/actions/standard/send-message", s.InstanceURL, salesforceAPIVersion)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", key)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.CircuitBreaker.RecordFailure(0)
+		s.auditWriteback("send_message", customerID, err, map[string]interface{}{"channel": channel})
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		s.CircuitBreaker.RecordFailure(0)
+		err = fmt.Errorf("Salesforce SendMessage failed: %d", resp.StatusCode)
+		s.auditWriteback("send_message", customerID, err, map[string]interface{}{"channel": channel})
+		return err
+	}
+
+	s.CircuitBreaker.RecordSuccess(0)
+	s.auditWriteback("send_message", customerID, nil, map[string]interface{}{"channel": channel})
+	return nil
+}
+
+// CreateTag adds tag to customerID's Contact record via Salesforce's Topic
+// API (AssignTargetId/TopicId), Salesforce's closest analog to HubSpot's
+// static lists.
+func (s *SalesforceConnector) CreateTag(customerID, tag string) error {
+	if err := s.RateLimiter.Wait(context.Background()); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"TopicId":      tag,
+		"EntityId":     customerID,
+		"NetworkScope": "Contact",
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/services/data/%s/sobjects/TopicAssignment", s.InstanceURL, salesforceAPIVersion)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.CircuitBreaker.RecordFailure(0)
+		s.auditWriteback("create_tag", customerID, err, map[string]interface{}{"tag": tag})
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		s.CircuitBreaker.RecordFailure(0)
+		err = fmt.Errorf("Salesforce CreateTag failed: %d", resp.StatusCode)
+		s.auditWriteback("create_tag", customerID, err, map[string]interface{}{"tag": tag})
+		return err
+	}
+
+	s.CircuitBreaker.RecordSuccess(0)
+	s.auditWriteback("create_tag", customerID, nil, map[string]interface{}{"tag": tag})
+	return nil
+}
+
+func (s *SalesforceConnector) GetName() string {
+	return "Salesforce"
+}
+
+func (s *SalesforceConnector) Close() error {
+	return s.batch.Flush()
+}
+
+// logDataAccess records a crm_data_access AuditEvent for every customer
+// read, satisfying GDPR Article 30's records-of-processing requirement
+// without relying on a caller to log the read by hand.
+func (s *SalesforceConnector) logDataAccess(customers []Customer, action string) {
+	if s.ComplianceLogger == nil {
+		return
+	}
+	for _, c := range customers {
+		_ = s.ComplianceLogger.LogEvent(compliance.AuditEvent{
+			EventType:    "crm_data_access",
+			CustomerID:   c.ID,
+			Action:       action,
+			Resource:     "salesforce_contact",
+			Outcome:      "SUCCESS",
+			Reason:       "crm_sync",
+			DataAccessed: []string{"email", "first_name", "last_name", "phone"},
+		})
+	}
+}
+
+func (s *SalesforceConnector) auditWriteback(action, customerID string, callErr error, metadata map[string]interface{}) {
+	if s.AuditLogger == nil {
+		return
+	}
+	s.AuditLogger.WriteCRMAudit("salesforce", action, customerID, callErr, metadata)
+}
+
+func (s *SalesforceConnector) auditBatchWriteback(batch []Customer, callErr error) {
+	if s.AuditLogger == nil {
+		return
+	}
+	for _, c := range batch {
+		s.AuditLogger.WriteCRMAudit("salesforce", "update_customer", c.ID, callErr, nil)
+	}
+}
@@ -0,0 +1,371 @@
+package crm
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// salesforceBulkRowThreshold is the estimated-row-count above which
+// FetchCustomersAuto prefers the Bulk API 2.0 path (StreamCustomers) over
+// FetchCustomers' REST LIMIT 200 fast path - the same page size the REST
+// query already caps at.
+const salesforceBulkRowThreshold = 200
+
+// salesforceBulkPollInterval is how long StreamCustomers waits between Bulk
+// API 2.0 job status polls.
+const salesforceBulkPollInterval = 2 * time.Second
+
+// salesforceBulkMaxPollAttempts bounds how many times StreamCustomers polls
+// a job's status before giving up and aborting it, so a job Salesforce
+// never finishes doesn't poll forever.
+const salesforceBulkMaxPollAttempts = 150 // ~5 minutes at salesforceBulkPollInterval
+
+// salesforceBulkChannelBuffer sizes StreamCustomers' output channel so a
+// CSV page's worth of records can be decoded without blocking on a slow
+// consumer for every single row.
+const salesforceBulkChannelBuffer = 200
+
+// salesforceBulkCSVColumns are the columns StreamCustomers' soql query is
+// expected to select, matching the fields FetchCustomers' SOQL query
+// selects - Id, Email, FirstName, LastName, Phone. Columns the results CSV
+// doesn't include are simply left zero-valued on the decoded Customer.
+var salesforceBulkCSVColumns = []string{"Id", "Email", "FirstName", "LastName", "Phone"}
+
+// salesforceBulkJobRequest is the body POSTed to jobs/query to start a Bulk
+// API 2.0 query job.
+type salesforceBulkJobRequest struct {
+	Operation string `json:"operation"`
+	Query     string `json:"query"`
+}
+
+// salesforceBulkJobResponse is the shape both job creation and job status
+// polling return; State is one of UploadComplete, InProgress, JobComplete,
+// Failed, or Aborted.
+type salesforceBulkJobResponse struct {
+	ID    string `json:"id"`
+	State string `json:"state"`
+}
+
+// salesforceBulkStatusError wraps a non-2xx Bulk API 2.0 response so
+// BulkRetryPolicy's IsRetryable predicate can inspect the status code
+// without parsing the error string, the way isRetryableCode does for gRPC
+// codes in predict.Client.
+type salesforceBulkStatusError struct {
+	statusCode int
+	body       string
+}
+
+func (e *salesforceBulkStatusError) Error() string {
+	return fmt.Sprintf("salesforce bulk API: status %d: %s", e.statusCode, e.body)
+}
+
+// isRetryableBulkStatus reports whether err is a salesforceBulkStatusError
+// carrying a status worth retrying - 429 (rate limited) or 503 (momentarily
+// unavailable).
+func isRetryableBulkStatus(err error) bool {
+	var statusErr *salesforceBulkStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	return statusErr.statusCode == http.StatusTooManyRequests || statusErr.statusCode == http.StatusServiceUnavailable
+}
+
+// FetchCustomersAuto picks between FetchCustomers' REST fast path and
+// StreamCustomers' Bulk API 2.0 path based on estimatedRows (0 if unknown)
+// and forceBulk, collecting StreamCustomers' channel output into a slice
+// for callers that don't need to consume it incrementally themselves.
+func (s *SalesforceConnector) FetchCustomersAuto(ctx context.Context, filter map[string]interface{}, soql string, estimatedRows int, forceBulk bool) ([]Customer, error) {
+	if !forceBulk && estimatedRows > 0 && estimatedRows <= salesforceBulkRowThreshold {
+		return s.FetchCustomers(filter)
+	}
+
+	customers := make([]Customer, 0, estimatedRows)
+	stream, errc := s.StreamCustomers(ctx, soql)
+	for customer := range stream {
+		customers = append(customers, customer)
+	}
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+// StreamCustomers runs soql through Salesforce's Bulk API 2.0: it creates a
+// query job, polls until Salesforce reports JobComplete, then streams the
+// CSV results - following Sforce-Locator pagination - onto the returned
+// channel as Customer structs. Unlike FetchCustomers' REST LIMIT 200 page,
+// this has no practical row cap, making it the right path for audience
+// sizes large enough to matter for LTV prediction.
+//
+// Cancelling ctx (or exceeding salesforceBulkMaxPollAttempts) stops the
+// poll loop and aborts the job server-side rather than leaving it running.
+// Both returned channels are closed once streaming ends; read errc only
+// after stream has been fully drained.
+func (s *SalesforceConnector) StreamCustomers(ctx context.Context, soql string) (<-chan Customer, <-chan error) {
+	out := make(chan Customer, salesforceBulkChannelBuffer)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		jobID, err := s.createBulkQueryJob(ctx, soql)
+		if err != nil {
+			errc <- err
+			return
+		}
+
+		fail := func(err error) {
+			s.abortBulkJob(jobID)
+			errc <- err
+		}
+
+		if err := s.pollBulkJobUntilComplete(ctx, jobID); err != nil {
+			fail(err)
+			return
+		}
+
+		locator := ""
+		for {
+			body, nextLocator, err := s.fetchBulkResultsPage(ctx, jobID, locator)
+			if err != nil {
+				fail(err)
+				return
+			}
+			if err := decodeBulkResultsCSV(ctx, body, out); err != nil {
+				fail(err)
+				return
+			}
+			if nextLocator == "" || nextLocator == "null" {
+				return
+			}
+			locator = nextLocator
+		}
+	}()
+
+	return out, errc
+}
+
+// createBulkQueryJob starts a Bulk API 2.0 query job for soql and returns
+// its job ID.
+func (s *SalesforceConnector) createBulkQueryJob(ctx context.Context, soql string) (string, error) {
+	payload, err := json.Marshal(salesforceBulkJobRequest{Operation: "query", Query: soql})
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s/services/data/%s/jobs/query", s.InstanceURL, salesforceAPIVersion)
+
+	result, _, err := s.BulkRetryPolicy.ExecuteWithRetry(ctx, func(ctx context.Context, _ int) (interface{}, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			return nil, &salesforceBulkStatusError{statusCode: resp.StatusCode, body: string(body)}
+		}
+
+		var jobResp salesforceBulkJobResponse
+		if err := json.Unmarshal(body, &jobResp); err != nil {
+			return nil, err
+		}
+		return jobResp.ID, nil
+	}, isRetryableBulkStatus)
+	if err != nil {
+		return "", fmt.Errorf("salesforce: create bulk query job: %w", err)
+	}
+	return result.(string), nil
+}
+
+// pollBulkJobUntilComplete polls jobID's status every
+// salesforceBulkPollInterval until Salesforce reports JobComplete, the job
+// ends in Failed/Aborted, ctx is done, or salesforceBulkMaxPollAttempts is
+// exceeded.
+func (s *SalesforceConnector) pollBulkJobUntilComplete(ctx context.Context, jobID string) error {
+	url := fmt.Sprintf("%s/services/data/%s/jobs/query/%s", s.InstanceURL, salesforceAPIVersion, jobID)
+
+	for attempt := 0; attempt < salesforceBulkMaxPollAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(salesforceBulkPollInterval):
+		}
+
+		result, _, err := s.BulkRetryPolicy.ExecuteWithRetry(ctx, func(ctx context.Context, _ int) (interface{}, error) {
+			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			resp, err := s.httpClient.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, err
+			}
+			if resp.StatusCode != http.StatusOK {
+				return nil, &salesforceBulkStatusError{statusCode: resp.StatusCode, body: string(body)}
+			}
+
+			var jobResp salesforceBulkJobResponse
+			if err := json.Unmarshal(body, &jobResp); err != nil {
+				return nil, err
+			}
+			return jobResp.State, nil
+		}, isRetryableBulkStatus)
+		if err != nil {
+			return fmt.Errorf("salesforce: poll bulk query job %s: %w", jobID, err)
+		}
+
+		switch result.(string) {
+		case "JobComplete":
+			return nil
+		case "Failed", "Aborted":
+			return fmt.Errorf("salesforce: bulk query job %s ended in state %s", jobID, result.(string))
+		}
+		// UploadComplete/InProgress: keep polling.
+	}
+
+	return fmt.Errorf("salesforce: bulk query job %s did not complete after %d polls", jobID, salesforceBulkMaxPollAttempts)
+}
+
+// abortBulkJob tells Salesforce to abort jobID, best-effort - StreamCustomers
+// calls this when it's giving up on a job early (ctx cancelled, poll cap
+// exceeded, or a results page failed) so the job doesn't keep running
+// server-side with nothing left to consume its output. Uses a fresh,
+// un-cancelled context since the caller's own ctx may already be done.
+func (s *SalesforceConnector) abortBulkJob(jobID string) {
+	url := fmt.Sprintf("%s/services/data/%s/jobs/query/%s", s.InstanceURL, salesforceAPIVersion, jobID)
+	payload, err := json.Marshal(map[string]string{"state": "Aborted"})
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("PATCH", url, bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+// fetchBulkResultsPage fetches one page of jobID's CSV results, following
+// locator (empty for the first page), and returns the page body plus the
+// Sforce-Locator to pass for the next page ("" or "null" means the results
+// set is exhausted).
+func (s *SalesforceConnector) fetchBulkResultsPage(ctx context.Context, jobID, locator string) ([]byte, string, error) {
+	url := fmt.Sprintf("%s/services/data/%s/jobs/query/%s/results", s.InstanceURL, salesforceAPIVersion, jobID)
+	if locator != "" {
+		url += "?locator=" + locator
+	}
+
+	var nextLocator string
+	result, _, err := s.BulkRetryPolicy.ExecuteWithRetry(ctx, func(ctx context.Context, _ int) (interface{}, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "text/csv")
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, &salesforceBulkStatusError{statusCode: resp.StatusCode, body: string(body)}
+		}
+
+		nextLocator = resp.Header.Get("Sforce-Locator")
+		return body, nil
+	}, isRetryableBulkStatus)
+	if err != nil {
+		return nil, "", fmt.Errorf("salesforce: fetch bulk results for job %s: %w", jobID, err)
+	}
+	return result.([]byte), nextLocator, nil
+}
+
+// decodeBulkResultsCSV parses one CSV results page and sends a Customer per
+// data row onto out, mapping columns by header name (see
+// salesforceBulkCSVColumns) rather than assuming a fixed column order.
+func decodeBulkResultsCSV(ctx context.Context, data []byte, out chan<- Customer) error {
+	reader := csv.NewReader(bytes.NewReader(data))
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	columnIndex := make(map[string]int, len(header))
+	for i, name := range header {
+		columnIndex[name] = i
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var customer Customer
+		if i, ok := columnIndex["Id"]; ok && i < len(record) {
+			customer.ID = record[i]
+		}
+		if i, ok := columnIndex["Email"]; ok && i < len(record) {
+			customer.Email = record[i]
+		}
+		if i, ok := columnIndex["FirstName"]; ok && i < len(record) {
+			customer.FirstName = record[i]
+		}
+		if i, ok := columnIndex["LastName"]; ok && i < len(record) {
+			customer.LastName = record[i]
+		}
+		if i, ok := columnIndex["Phone"]; ok && i < len(record) {
+			customer.Phone = record[i]
+		}
+
+		select {
+		case out <- customer:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
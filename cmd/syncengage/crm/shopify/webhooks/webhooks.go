@@ -0,0 +1,522 @@
+// Package webhooks receives real-time Shopify webhook deliveries so the LTV
+// pipeline doesn't have to wait on ShopifyConnector.FetchCustomers' 250
+// record-per-page polling loop. A Handler verifies and deduplicates inbound
+// deliveries and emits typed Events onto a channel; RegistrationClient
+// registers the subscriptions Shopify needs to know to deliver them; and
+// Backfill paginates the Admin API to replay whatever a gap in delivery
+// missed.
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncengage/crm"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/signing"
+)
+
+// Topic identifies which webhook subscription a delivery belongs to.
+type Topic string
+
+const (
+	TopicCustomersCreate Topic = "customers/create"
+	TopicCustomersUpdate Topic = "customers/update"
+	TopicOrdersPaid      Topic = "orders/paid"
+	TopicOrdersFulfilled Topic = "orders/fulfilled"
+)
+
+// allTopics is registered by default by RegistrationClient.Register and
+// accepted by default by Handler.ServeHTTP.
+var allTopics = []Topic{TopicCustomersCreate, TopicCustomersUpdate, TopicOrdersPaid, TopicOrdersFulfilled}
+
+// Order is the parsed payload of an orders/paid or orders/fulfilled
+// delivery - deliberately narrower than Shopify's full order resource,
+// carrying only what the LTV pipeline needs.
+type Order struct {
+	OrderID           string
+	CustomerID        string
+	TotalPrice        float64
+	FinancialStatus   string
+	FulfillmentStatus string
+	UpdatedAt         time.Time
+}
+
+// Event is one decoded webhook delivery, handed to whatever consumes
+// Handler.Events. Exactly one of Customer or Order is set, matching Topic.
+type Event struct {
+	Topic     Topic
+	WebhookID string
+	ShopURL   string
+	Customer  *crm.Customer // set when Topic is customers/create or customers/update
+	Order     *Order        // set when Topic is orders/paid or orders/fulfilled
+}
+
+const (
+	dedupeWindow  = 24 * time.Hour
+	dedupeMaxSeen = 10000
+)
+
+// dedupeCache remembers recently-seen webhook IDs so a delivery Shopify
+// retries (it retries on anything but a 2xx response) isn't emitted twice.
+// Entries age out after dedupeWindow, with dedupeMaxSeen as a hard backstop
+// against unbounded growth if deliveries stop aging out for some reason.
+type dedupeCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func newDedupeCache() *dedupeCache {
+	return &dedupeCache{seen: make(map[string]time.Time)}
+}
+
+// seenBefore records id as seen and reports whether it was already present
+// within dedupeWindow.
+func (d *dedupeCache) seenBefore(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if seenAt, ok := d.seen[id]; ok && now.Sub(seenAt) < dedupeWindow {
+		return true
+	}
+
+	if len(d.seen) >= dedupeMaxSeen {
+		for existingID, seenAt := range d.seen {
+			if now.Sub(seenAt) >= dedupeWindow {
+				delete(d.seen, existingID)
+			}
+		}
+	}
+
+	d.seen[id] = now
+	return false
+}
+
+// Handler is an http.Handler for Shopify's webhook callback URL. Construct
+// one with NewHandler and register its ServeHTTP method against whatever
+// path Register was told to use as the callback.
+type Handler struct {
+	WebhookSecret string
+	Events        chan Event
+
+	dedupe *dedupeCache
+}
+
+// NewHandler creates a Handler that verifies deliveries against
+// webhookSecret and emits decoded Events on a channel buffered to
+// eventBufferSize. A full buffer means the delivery is NACKed (503) so
+// Shopify retries rather than silently dropping it.
+func NewHandler(webhookSecret string, eventBufferSize int) *Handler {
+	return &Handler{
+		WebhookSecret: webhookSecret,
+		Events:        make(chan Event, eventBufferSize),
+		dedupe:        newDedupeCache(),
+	}
+}
+
+// ServeHTTP verifies, deduplicates, decodes, and emits one webhook delivery.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if !signing.NewShopifySigner(h.WebhookSecret).VerifyWebhook(body, r.Header.Get("X-Shopify-Hmac-Sha256")) {
+		http.Error(w, "invalid webhook signature", http.StatusUnauthorized)
+		return
+	}
+
+	webhookID := r.Header.Get("X-Shopify-Webhook-Id")
+	if webhookID == "" {
+		http.Error(w, "missing X-Shopify-Webhook-Id", http.StatusBadRequest)
+		return
+	}
+	if h.dedupe.seenBefore(webhookID) {
+		w.WriteHeader(http.StatusOK) // already processed; ack so Shopify stops retrying
+		return
+	}
+
+	topic := Topic(r.Header.Get("X-Shopify-Topic"))
+	event, err := decodeEvent(topic, webhookID, r.Header.Get("X-Shopify-Shop-Domain"), body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("decoding %s payload: %v", topic, err), http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case h.Events <- *event:
+		w.WriteHeader(http.StatusOK)
+	default:
+		// Buffer's full; ask Shopify to retry rather than drop the event.
+		http.Error(w, "event buffer full", http.StatusServiceUnavailable)
+	}
+}
+
+func decodeEvent(topic Topic, webhookID, shopURL string, body []byte) (*Event, error) {
+	event := &Event{Topic: topic, WebhookID: webhookID, ShopURL: shopURL}
+
+	switch topic {
+	case TopicCustomersCreate, TopicCustomersUpdate:
+		customer, err := decodeCustomerPayload(body)
+		if err != nil {
+			return nil, err
+		}
+		event.Customer = customer
+	case TopicOrdersPaid, TopicOrdersFulfilled:
+		order, err := decodeOrderPayload(body)
+		if err != nil {
+			return nil, err
+		}
+		event.Order = order
+	default:
+		return nil, fmt.Errorf("unrecognized topic %q", topic)
+	}
+
+	return event, nil
+}
+
+func decodeCustomerPayload(body []byte) (*crm.Customer, error) {
+	var payload struct {
+		ID          int64  `json:"id"`
+		Email       string `json:"email"`
+		FirstName   string `json:"first_name"`
+		LastName    string `json:"last_name"`
+		Phone       string `json:"phone"`
+		OrdersCount int    `json:"orders_count"`
+		TotalSpent  string `json:"total_spent"`
+		UpdatedAt   string `json:"updated_at"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	var totalSpend float64
+	fmt.Sscanf(payload.TotalSpent, "%f", &totalSpend)
+
+	customer := &crm.Customer{
+		ID:            strconv.FormatInt(payload.ID, 10),
+		Email:         payload.Email,
+		FirstName:     payload.FirstName,
+		LastName:      payload.LastName,
+		Phone:         payload.Phone,
+		PurchaseCount: payload.OrdersCount,
+		TotalSpend:    totalSpend,
+	}
+	if t, err := time.Parse(time.RFC3339, payload.UpdatedAt); err == nil {
+		customer.LastEngagement = t
+	}
+	return customer, nil
+}
+
+func decodeOrderPayload(body []byte) (*Order, error) {
+	var payload struct {
+		ID       int64 `json:"id"`
+		Customer struct {
+			ID int64 `json:"id"`
+		} `json:"customer"`
+		TotalPrice        string `json:"total_price"`
+		FinancialStatus   string `json:"financial_status"`
+		FulfillmentStatus string `json:"fulfillment_status"`
+		UpdatedAt         string `json:"updated_at"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	var totalPrice float64
+	fmt.Sscanf(payload.TotalPrice, "%f", &totalPrice)
+
+	order := &Order{
+		OrderID:           strconv.FormatInt(payload.ID, 10),
+		CustomerID:        strconv.FormatInt(payload.Customer.ID, 10),
+		TotalPrice:        totalPrice,
+		FinancialStatus:   payload.FinancialStatus,
+		FulfillmentStatus: payload.FulfillmentStatus,
+	}
+	if t, err := time.Parse(time.RFC3339, payload.UpdatedAt); err == nil {
+		order.UpdatedAt = t
+	}
+	return order, nil
+}
+
+// RegistrationClient creates and lists Shopify's webhook subscriptions
+// through the Admin API, mirroring ShopifyConnector's own request shape
+// (same auth header, same API version convention) without depending on it.
+type RegistrationClient struct {
+	ShopURL     string
+	AccessToken string
+	APIVersion  string
+	httpClient  *http.Client
+}
+
+// NewRegistrationClient creates a client for the given shop. apiVersion
+// should match whatever ShopifyConnector.APIVersion the rest of the
+// integration uses.
+func NewRegistrationClient(shopURL, accessToken, apiVersion string) *RegistrationClient {
+	return &RegistrationClient{
+		ShopURL:     shopURL,
+		AccessToken: accessToken,
+		APIVersion:  apiVersion,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type registeredWebhook struct {
+	ID      int64  `json:"id"`
+	Topic   string `json:"topic"`
+	Address string `json:"address"`
+}
+
+// Register ensures a webhook subscription exists for every topic in
+// allTopics pointed at callbackURL, by listing what's already registered
+// and only creating what's missing - safe to call on every startup.
+func (c *RegistrationClient) Register(callbackURL string) error {
+	existing, err := c.list()
+	if err != nil {
+		return fmt.Errorf("listing existing webhooks: %w", err)
+	}
+
+	registered := make(map[Topic]bool, len(existing))
+	for _, wh := range existing {
+		if wh.Address == callbackURL {
+			registered[Topic(wh.Topic)] = true
+		}
+	}
+
+	for _, topic := range allTopics {
+		if registered[topic] {
+			continue
+		}
+		if err := c.create(topic, callbackURL); err != nil {
+			return fmt.Errorf("creating webhook for topic %s: %w", topic, err)
+		}
+	}
+	return nil
+}
+
+func (c *RegistrationClient) list() ([]registeredWebhook, error) {
+	url := fmt.Sprintf("https://%s/admin/api/%s/webhooks.json", c.ShopURL, c.APIVersion)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Shopify-Access-Token", c.AccessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("listing webhooks failed: %d", resp.StatusCode)
+	}
+
+	var parsed struct {
+		Webhooks []registeredWebhook `json:"webhooks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+	return parsed.Webhooks, nil
+}
+
+func (c *RegistrationClient) create(topic Topic, callbackURL string) error {
+	url := fmt.Sprintf("https://%s/admin/api/%s/webhooks.json", c.ShopURL, c.APIVersion)
+
+	payload := map[string]interface{}{
+		"webhook": map[string]interface{}{
+			"topic":   string(topic),
+			"address": callbackURL,
+			"format":  "json",
+		},
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(string(payloadBytes)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Shopify-Access-Token", c.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 {
+		return fmt.Errorf("creating webhook failed: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// BackfillClient replays orders the webhook receiver missed (e.g. during a
+// deploy or a transient outage) by paginating Shopify's orders.json with
+// updated_at_min, following the Link header's "next" cursor until it's
+// exhausted.
+type BackfillClient struct {
+	ShopURL     string
+	AccessToken string
+	APIVersion  string
+	httpClient  *http.Client
+}
+
+// NewBackfillClient creates a client for the given shop.
+func NewBackfillClient(shopURL, accessToken, apiVersion string) *BackfillClient {
+	return &BackfillClient{
+		ShopURL:     shopURL,
+		AccessToken: accessToken,
+		APIVersion:  apiVersion,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Backfill fetches every order updated at or after updatedAtMin, in pages,
+// and decodes each into an Order. Callers typically feed the result into
+// the same consumer reading Handler.Events to reconcile the gap.
+func (c *BackfillClient) Backfill(updatedAtMin time.Time) ([]Order, error) {
+	url := fmt.Sprintf("https://%s/admin/api/%s/orders.json?status=any&limit=250&updated_at_min=%s",
+		c.ShopURL, c.APIVersion, updatedAtMin.UTC().Format(time.RFC3339))
+
+	var orders []Order
+	for url != "" {
+		page, nextURL, err := c.fetchPage(url)
+		if err != nil {
+			return orders, err
+		}
+		orders = append(orders, page...)
+		url = nextURL
+	}
+	return orders, nil
+}
+
+func (c *BackfillClient) fetchPage(url string) ([]Order, string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("X-Shopify-Access-Token", c.AccessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, "", fmt.Errorf("fetching orders failed: %d", resp.StatusCode)
+	}
+
+	var parsed struct {
+		Orders []struct {
+			ID       int64 `json:"id"`
+			Customer struct {
+				ID int64 `json:"id"`
+			} `json:"customer"`
+			TotalPrice        string `json:"total_price"`
+			FinancialStatus   string `json:"financial_status"`
+			FulfillmentStatus string `json:"fulfillment_status"`
+			UpdatedAt         string `json:"updated_at"`
+		} `json:"orders"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, "", err
+	}
+
+	orders := make([]Order, 0, len(parsed.Orders))
+	for _, o := range parsed.Orders {
+		var totalPrice float64
+		fmt.Sscanf(o.TotalPrice, "%f", &totalPrice)
+
+		order := Order{
+			OrderID:           strconv.FormatInt(o.ID, 10),
+			CustomerID:        strconv.FormatInt(o.Customer.ID, 10),
+			TotalPrice:        totalPrice,
+			FinancialStatus:   o.FinancialStatus,
+			FulfillmentStatus: o.FulfillmentStatus,
+		}
+		if t, err := time.Parse(time.RFC3339, o.UpdatedAt); err == nil {
+			order.UpdatedAt = t
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, nextPageURL(resp.Header.Get("Link")), nil
+}
+
+// nextPageURL extracts the "next" cursor URL from a Shopify Link header,
+// e.g. `<https://shop.myshopify.com/...&page_info=abc>; rel="next"`.
+// Returns "" once there are no more pages.
+func nextPageURL(linkHeader string) string {
+	for _, part := range strings.Split(linkHeader, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		if !strings.Contains(segments[1], `rel="next"`) {
+			continue
+		}
+		url := strings.TrimSpace(segments[0])
+		return strings.Trim(url, "<>")
+	}
+	return ""
+}
+
+// ReplayHandler is an HTTP endpoint the receiver (or an operator) can call
+// to backfill a detected gap. It wraps a BackfillClient and emits every
+// recovered order as an Event on the same channel Handler.Events uses, so
+// downstream consumers don't need to distinguish live deliveries from a
+// replay.
+type ReplayHandler struct {
+	Backfill *BackfillClient
+	Events   chan Event
+	ShopURL  string
+}
+
+// NewReplayHandler creates a ReplayHandler that emits onto events - pass
+// the same channel a Handler was constructed with to feed both into one
+// consumer.
+func NewReplayHandler(backfill *BackfillClient, events chan Event) *ReplayHandler {
+	return &ReplayHandler{Backfill: backfill, Events: events, ShopURL: backfill.ShopURL}
+}
+
+// ServeHTTP expects a "since" query parameter (RFC3339) naming the start of
+// the gap to replay, and responds with the number of orders replayed.
+func (h *ReplayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sinceParam := r.URL.Query().Get("since")
+	since, err := time.Parse(time.RFC3339, sinceParam)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid or missing since parameter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	orders, err := h.Backfill.Backfill(since)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("backfill failed: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	for i := range orders {
+		order := orders[i]
+		topic := TopicOrdersPaid
+		if order.FulfillmentStatus == "fulfilled" {
+			topic = TopicOrdersFulfilled
+		}
+		select {
+		case h.Events <- Event{Topic: topic, ShopURL: h.ShopURL, Order: &order}:
+		default:
+			// Buffer's full; the caller can retry the replay for this window.
+		}
+	}
+
+	fmt.Fprintf(w, `{"replayed":%d}`, len(orders))
+}
@@ -0,0 +1,142 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newRequest(secret, topic, webhookID string, body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/shopify", strings.NewReader(string(body)))
+	req.Header.Set("X-Shopify-Hmac-Sha256", sign(secret, body))
+	req.Header.Set("X-Shopify-Topic", topic)
+	req.Header.Set("X-Shopify-Webhook-Id", webhookID)
+	req.Header.Set("X-Shopify-Shop-Domain", "mystore.myshopify.com")
+	return req
+}
+
+func TestHandler_RejectsBadSignature(t *testing.T) {
+	h := NewHandler("shh", 1)
+	body := []byte(`{"id":1}`)
+	req := newRequest("wrong-secret", string(TopicCustomersCreate), "wh-1", body)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a bad signature, got %d", w.Code)
+	}
+}
+
+func TestHandler_DecodesCustomerCreateEvent(t *testing.T) {
+	h := NewHandler("shh", 1)
+	body := []byte(`{"id":42,"email":"a@example.com","first_name":"Ada","last_name":"Lovelace","orders_count":3,"total_spent":"150.50"}`)
+	req := newRequest("shh", string(TopicCustomersCreate), "wh-1", body)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	select {
+	case event := <-h.Events:
+		if event.Customer == nil {
+			t.Fatal("expected Customer to be set on a customers/create event")
+		}
+		if event.Customer.Email != "a@example.com" || event.Customer.TotalSpend != 150.50 {
+			t.Fatalf("unexpected customer payload: %+v", event.Customer)
+		}
+	default:
+		t.Fatal("expected an event to be emitted")
+	}
+}
+
+func TestHandler_DecodesOrderPaidEvent(t *testing.T) {
+	h := NewHandler("shh", 1)
+	body := []byte(`{"id":99,"customer":{"id":42},"total_price":"75.00","financial_status":"paid","fulfillment_status":"unfulfilled"}`)
+	req := newRequest("shh", string(TopicOrdersPaid), "wh-2", body)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	event := <-h.Events
+	if event.Order == nil || event.Order.OrderID != "99" || event.Order.CustomerID != "42" || event.Order.TotalPrice != 75.00 {
+		t.Fatalf("unexpected order payload: %+v", event.Order)
+	}
+}
+
+func TestHandler_DedupesRepeatedWebhookID(t *testing.T) {
+	h := NewHandler("shh", 2)
+	body := []byte(`{"id":1,"customer":{"id":1},"total_price":"1.00","financial_status":"paid"}`)
+
+	for i := 0; i < 2; i++ {
+		req := newRequest("shh", string(TopicOrdersPaid), "wh-dupe", body)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i, w.Code)
+		}
+	}
+
+	if len(h.Events) != 1 {
+		t.Fatalf("expected exactly one event for two deliveries of the same webhook ID, got %d", len(h.Events))
+	}
+}
+
+func TestHandler_RejectsUnrecognizedTopic(t *testing.T) {
+	h := NewHandler("shh", 1)
+	body := []byte(`{"id":1}`)
+	req := newRequest("shh", "carts/update", "wh-3", body)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unrecognized topic, got %d", w.Code)
+	}
+}
+
+func TestHandler_FullBufferAsksCallerToRetry(t *testing.T) {
+	h := NewHandler("shh", 0) // unbuffered, nothing draining it
+	body := []byte(`{"id":1,"customer":{"id":1},"total_price":"1.00"}`)
+	req := newRequest("shh", string(TopicOrdersPaid), "wh-4", body)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 when the event buffer is full, got %d", w.Code)
+	}
+}
+
+func TestNextPageURL_ExtractsNextCursorFromLinkHeader(t *testing.T) {
+	link := `<https://mystore.myshopify.com/admin/api/2024-01/orders.json?page_info=abc>; rel="next"`
+	got := nextPageURL(link)
+	want := "https://mystore.myshopify.com/admin/api/2024-01/orders.json?page_info=abc"
+	if got != want {
+		t.Fatalf("expected next URL %q, got %q", want, got)
+	}
+}
+
+func TestNextPageURL_ReturnsEmptyWithNoNextRel(t *testing.T) {
+	link := `<https://mystore.myshopify.com/admin/api/2024-01/orders.json?page_info=abc>; rel="previous"`
+	if got := nextPageURL(link); got != "" {
+		t.Fatalf("expected no next URL when only rel=previous is present, got %q", got)
+	}
+}
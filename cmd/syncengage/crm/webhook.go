@@ -0,0 +1,179 @@
+package crm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/signing"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// WebhookConnector delivers CRM write-backs to a generic HTTP endpoint
+// instead of a named platform's API, for integrations (a custom in-house
+// CRM, a low-code automation tool) that only expose a webhook. It is a
+// push-only CRMConnector: FetchCustomers and GetCustomer, which need a
+// read API no generic webhook has, always fail - this connector is meant
+// to sit alongside a read-capable one (HubSpotConnector, a CRMSource),
+// not replace it.
+type WebhookConnector struct {
+	// URL is the endpoint every UpdateCustomer/SendMessage/CreateTag call
+	// POSTs an event to.
+	URL string
+
+	// Signer, when set, signs every outbound request (e.g.
+	// signing.NewHMACSigner(apiKey, secret)) so the receiving endpoint can
+	// verify the event actually came from this connector. Left nil,
+	// requests are sent unsigned.
+	Signer signing.Signer
+
+	httpClient     *http.Client
+	CircuitBreaker *shield.CircuitBreaker
+
+	AuditLogger crmWritebackLogger
+	idempotency *idempotencyCache
+}
+
+// NewWebhookConnector creates a WebhookConnector posting every event to
+// url, signed by signer if non-nil.
+func NewWebhookConnector(url string, signer signing.Signer) *WebhookConnector {
+	cb := shield.NewCircuitBreaker()
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	if signer != nil {
+		httpClient.Transport = signing.NewSigningTransport(signer, http.DefaultTransport, 0)
+	}
+
+	return &WebhookConnector{
+		URL:            url,
+		Signer:         signer,
+		httpClient:     httpClient,
+		CircuitBreaker: cb,
+		idempotency:    newIdempotencyCache(),
+	}
+}
+
+// webhookEvent is the payload every call below POSTs to URL.
+type webhookEvent struct {
+	EventType      string                 `json:"event_type"`
+	CustomerID     string                 `json:"customer_id"`
+	IdempotencyKey string                 `json:"idempotency_key"`
+	Payload        map[string]interface{} `json:"payload"`
+}
+
+func (w *WebhookConnector) Connect() error {
+	if w.URL == "" {
+		return fmt.Errorf("webhook: URL is required")
+	}
+	return nil
+}
+
+// FetchCustomers always fails: a generic webhook is a push target, not a
+// readable data source.
+func (w *WebhookConnector) FetchCustomers(filter map[string]interface{}) ([]Customer, error) {
+	return nil, fmt.Errorf("webhook: FetchCustomers is not supported by a push-only connector")
+}
+
+// GetCustomer always fails, for the same reason as FetchCustomers.
+func (w *WebhookConnector) GetCustomer(id string) (*Customer, error) {
+	return nil, fmt.Errorf("webhook: GetCustomer is not supported by a push-only connector")
+}
+
+// UpdateCustomer posts a customer_updated event carrying the full
+// Customer record.
+func (w *WebhookConnector) UpdateCustomer(customer Customer) error {
+	key := idempotencyKey(customer.ID, "update_customer", customer.Email+customer.LastName)
+	payload := map[string]interface{}{
+		"id":         customer.ID,
+		"email":      customer.Email,
+		"first_name": customer.FirstName,
+		"last_name":  customer.LastName,
+		"phone":      customer.Phone,
+		"ltv":        customer.LTV,
+	}
+	return w.post("customer_updated", customer.ID, key, payload)
+}
+
+// SendMessage posts a message_send event, deduplicated on (customerID,
+// channel, message) so a caller's retry of a call whose response it never
+// saw can't result in a second send.
+func (w *WebhookConnector) SendMessage(customerID, channel, message string, metadata map[string]string) error {
+	key := idempotencyKey(customerID, channel, message)
+	if w.idempotency.SeenBefore(key) {
+		return nil
+	}
+
+	payload := map[string]interface{}{
+		"channel":  channel,
+		"message":  message,
+		"metadata": metadata,
+	}
+	return w.post("message_send", customerID, key, payload)
+}
+
+// CreateTag posts a tag_created event.
+func (w *WebhookConnector) CreateTag(customerID, tag string) error {
+	key := idempotencyKey(customerID, "create_tag", tag)
+	return w.post("tag_created", customerID, key, map[string]interface{}{"tag": tag})
+}
+
+func (w *WebhookConnector) post(eventType, customerID, idempotencyKey string, payload map[string]interface{}) error {
+	if !w.CircuitBreaker.CanExecute() {
+		w.CircuitBreaker.RecordFallback()
+		err := fmt.Errorf("webhook: circuit open, %s to %s dropped", eventType, w.URL)
+		w.auditWriteback(eventType, customerID, err, payload)
+		return err
+	}
+
+	body, err := json.Marshal(webhookEvent{
+		EventType:      eventType,
+		CustomerID:     customerID,
+		IdempotencyKey: idempotencyKey,
+		Payload:        payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		w.CircuitBreaker.RecordFailure(0)
+		w.auditWriteback(eventType, customerID, err, payload)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		w.CircuitBreaker.RecordFailure(0)
+		err = fmt.Errorf("webhook: %s to %s returned %d", eventType, w.URL, resp.StatusCode)
+		w.auditWriteback(eventType, customerID, err, payload)
+		return err
+	}
+
+	w.CircuitBreaker.RecordSuccess(0)
+	w.auditWriteback(eventType, customerID, nil, payload)
+	return nil
+}
+
+func (w *WebhookConnector) auditWriteback(action, customerID string, callErr error, metadata map[string]interface{}) {
+	if w.AuditLogger == nil {
+		return
+	}
+	w.AuditLogger.WriteCRMAudit("webhook", action, customerID, callErr, metadata)
+}
+
+func (w *WebhookConnector) GetName() string {
+	return "Webhook"
+}
+
+func (w *WebhookConnector) Close() error {
+	return nil
+}
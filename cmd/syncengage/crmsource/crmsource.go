@@ -0,0 +1,33 @@
+// Package crmsource replaces simulateCRMPolling's hard-coded customer slice
+// with real, incremental CRM sync: a CRMSource polls (or streams, via
+// webhook) only what changed since the last cursor, so SyncEngage no longer
+// re-scans every customer on every cycle. SalesforceSource and HubSpotSource
+// implement it against their respective incremental-sync APIs; Metrics
+// tracks records fetched, cursor lag, and rate-limit headroom per source for
+// a /metrics endpoint; CursorStore persists the "since" watermark so a
+// restart resumes rather than re-syncing from scratch.
+package crmsource
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncengage/crm"
+)
+
+// CRMSource incrementally syncs customers from a CRM, either by polling on
+// demand (Poll) or by handing back a channel fed by a push mechanism like a
+// webhook (Stream).
+type CRMSource interface {
+	// Poll returns every customer changed since since, plus the cursor to
+	// pass as since on the next call.
+	Poll(ctx context.Context, since time.Time) ([]crm.Customer, time.Time, error)
+
+	// Stream returns a channel of customers pushed in real time (e.g. by a
+	// webhook receiver feeding it). Closed when ctx is done.
+	Stream(ctx context.Context) (<-chan crm.Customer, error)
+
+	// Name identifies this source for cursor persistence and metrics
+	// labeling (e.g. "salesforce", "hubspot").
+	Name() string
+}
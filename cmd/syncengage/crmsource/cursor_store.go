@@ -0,0 +1,112 @@
+package crmsource
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	_ "github.com/lib/pq" // PostgreSQL driver, already used by crm.PostgreSQLConnector and auditsink.PostgresSink
+)
+
+// CursorStore persists each CRMSource's "since" watermark so a restart
+// resumes an incremental sync instead of starting over.
+type CursorStore interface {
+	Get(ctx context.Context, source string) (time.Time, error)
+	Set(ctx context.Context, source string, cursor time.Time) error
+}
+
+// crmSyncCursorsSchema mirrors auditsink's migrate-on-construction pattern:
+// no separate migration step is needed before SyncEngage can start.
+const crmSyncCursorsSchema = `
+CREATE TABLE IF NOT EXISTS crm_sync_cursors (
+	source     TEXT PRIMARY KEY,
+	cursor_at  TIMESTAMPTZ NOT NULL
+)`
+
+// PostgresCursorStore stores cursors in the same Postgres database as
+// auditsink.PostgresSink - pass it the same AUDIT_POSTGRES_DSN so both land
+// in one durable store rather than introducing a second one just for
+// cursors.
+type PostgresCursorStore struct {
+	db *sql.DB
+}
+
+// NewPostgresCursorStore opens dsn and ensures the crm_sync_cursors table
+// exists.
+func NewPostgresCursorStore(dsn string) (*PostgresCursorStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("crmsource: opening postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("crmsource: pinging postgres: %w", err)
+	}
+	if _, err := db.Exec(crmSyncCursorsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("crmsource: migrating crm_sync_cursors: %w", err)
+	}
+	return &PostgresCursorStore{db: db}, nil
+}
+
+// Get returns source's last persisted cursor, or the zero time if none has
+// been saved yet.
+func (s *PostgresCursorStore) Get(ctx context.Context, source string) (time.Time, error) {
+	var cursor time.Time
+	err := s.db.QueryRowContext(ctx, `SELECT cursor_at FROM crm_sync_cursors WHERE source = $1`, source).Scan(&cursor)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("crmsource: reading cursor for %s: %w", source, err)
+	}
+	return cursor, nil
+}
+
+// Set upserts source's cursor.
+func (s *PostgresCursorStore) Set(ctx context.Context, source string, cursor time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO crm_sync_cursors (source, cursor_at) VALUES ($1, $2)
+		ON CONFLICT (source) DO UPDATE SET cursor_at = EXCLUDED.cursor_at`,
+		source, cursor,
+	)
+	if err != nil {
+		return fmt.Errorf("crmsource: saving cursor for %s: %w", source, err)
+	}
+	return nil
+}
+
+// Close closes the underlying database handle.
+func (s *PostgresCursorStore) Close() error {
+	return s.db.Close()
+}
+
+// InMemoryCursorStore is the non-durable fallback when no Postgres DSN is
+// configured - cursors reset to the zero time on restart, which just means
+// one extra full resync rather than a hard failure.
+type InMemoryCursorStore struct {
+	mu      sync.Mutex
+	cursors map[string]time.Time
+}
+
+// NewInMemoryCursorStore creates an empty store.
+func NewInMemoryCursorStore() *InMemoryCursorStore {
+	return &InMemoryCursorStore{cursors: make(map[string]time.Time)}
+}
+
+// Get implements CursorStore.
+func (s *InMemoryCursorStore) Get(ctx context.Context, source string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cursors[source], nil
+}
+
+// Set implements CursorStore.
+func (s *InMemoryCursorStore) Set(ctx context.Context, source string, cursor time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursors[source] = cursor
+	return nil
+}
@@ -0,0 +1,182 @@
+package crmsource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncengage/crm"
+)
+
+const hubspotSearchPageSize = 100
+
+// HubSpotSource incrementally syncs contacts via HubSpot's CRM search API,
+// filtering on hs_lastmodifieddate and paginating with the "after" cursor -
+// the incremental-sync counterpart to crm.HubSpotConnector's full-list
+// FetchCustomers.
+type HubSpotSource struct {
+	APIKey     string
+	BaseURL    string
+	httpClient *http.Client
+	metrics    *Metrics
+}
+
+// NewHubSpotSource creates a source authenticated with apiKey. metrics may
+// be nil to skip metrics recording.
+func NewHubSpotSource(apiKey string, metrics *Metrics) *HubSpotSource {
+	return &HubSpotSource{
+		APIKey:     apiKey,
+		BaseURL:    "https://api.hubapi.com",
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		metrics:    metrics,
+	}
+}
+
+// Name implements CRMSource.
+func (h *HubSpotSource) Name() string { return "hubspot" }
+
+// Poll searches for every contact with hs_lastmodifieddate after since,
+// paginating until HubSpot stops returning an "after" cursor. The returned
+// cursor is the latest hs_lastmodifieddate seen, or since unchanged if
+// nothing matched.
+func (h *HubSpotSource) Poll(ctx context.Context, since time.Time) ([]crm.Customer, time.Time, error) {
+	cursor := since
+	var customers []crm.Customer
+	after := ""
+
+	for {
+		page, nextAfter, pageMax, err := h.searchPage(ctx, since, after)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("crmsource: hubspot search: %w", err)
+		}
+		customers = append(customers, page...)
+		if pageMax.After(cursor) {
+			cursor = pageMax
+		}
+		if nextAfter == "" {
+			break
+		}
+		after = nextAfter
+	}
+
+	if h.metrics != nil {
+		h.metrics.RecordFetched(h.Name(), len(customers), cursor)
+	}
+	return customers, cursor, nil
+}
+
+// Stream is unsupported directly - real-time HubSpot updates arrive through
+// the webhook receiver instead, which pushes decoded contacts onto whatever
+// channel the caller wired it to.
+func (h *HubSpotSource) Stream(ctx context.Context) (<-chan crm.Customer, error) {
+	return nil, fmt.Errorf("crmsource: HubSpotSource does not support Stream, use the webhook receiver")
+}
+
+func (h *HubSpotSource) searchPage(ctx context.Context, since time.Time, after string) ([]crm.Customer, string, time.Time, error) {
+	body := map[string]interface{}{
+		"filterGroups": []map[string]interface{}{{
+			"filters": []map[string]interface{}{{
+				"propertyName": "hs_lastmodifieddate",
+				"operator":     "GT",
+				"value":        strconv.FormatInt(since.UnixMilli(), 10),
+			}},
+		}},
+		"sorts": []map[string]interface{}{{
+			"propertyName": "hs_lastmodifieddate",
+			"direction":    "ASCENDING",
+		}},
+		"properties": []string{"email", "firstname", "lastname", "phone", "total_revenue", "hs_lifetimevalue", "hs_lastmodifieddate"},
+		"limit":      hubspotSearchPageSize,
+	}
+	if after != "" {
+		body["after"] = after
+	}
+	payload, _ := json.Marshal(body)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", h.BaseURL+"/crm/v3/objects/contacts/search", strings.NewReader(string(payload)))
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+h.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	h.recordRateLimit(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", time.Time{}, fmt.Errorf("search failed: %d", resp.StatusCode)
+	}
+
+	var parsed struct {
+		Results []struct {
+			ID         string `json:"id"`
+			Properties struct {
+				Email              string `json:"email"`
+				FirstName          string `json:"firstname"`
+				LastName           string `json:"lastname"`
+				Phone              string `json:"phone"`
+				TotalRevenue       string `json:"total_revenue"`
+				LifetimeValue      string `json:"hs_lifetimevalue"`
+				HsLastModifiedDate string `json:"hs_lastmodifieddate"`
+			} `json:"properties"`
+		} `json:"results"`
+		Paging struct {
+			Next struct {
+				After string `json:"after"`
+			} `json:"next"`
+		} `json:"paging"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, "", time.Time{}, err
+	}
+
+	customers := make([]crm.Customer, 0, len(parsed.Results))
+	pageMax := since
+	for _, result := range parsed.Results {
+		customer := crm.Customer{
+			ID:        result.ID,
+			Email:     result.Properties.Email,
+			FirstName: result.Properties.FirstName,
+			LastName:  result.Properties.LastName,
+			Phone:     result.Properties.Phone,
+		}
+		if parsed, err := strconv.ParseFloat(result.Properties.TotalRevenue, 64); err == nil {
+			customer.TotalSpend = parsed
+		}
+		if parsed, err := strconv.ParseFloat(result.Properties.LifetimeValue, 64); err == nil {
+			customer.LTV = parsed
+		}
+		if millis, err := strconv.ParseInt(result.Properties.HsLastModifiedDate, 10, 64); err == nil {
+			modified := time.UnixMilli(millis)
+			customer.LastEngagement = modified
+			if modified.After(pageMax) {
+				pageMax = modified
+			}
+		}
+		customers = append(customers, customer)
+	}
+
+	return customers, parsed.Paging.Next.After, pageMax, nil
+}
+
+// recordRateLimit parses HubSpot's X-HubSpot-RateLimit-Remaining /
+// X-HubSpot-RateLimit-Max headers into a remaining-fraction gauge.
+func (h *HubSpotSource) recordRateLimit(resp *http.Response) {
+	if h.metrics == nil {
+		return
+	}
+	remaining, errR := strconv.ParseFloat(resp.Header.Get("X-HubSpot-RateLimit-Remaining"), 64)
+	max, errM := strconv.ParseFloat(resp.Header.Get("X-HubSpot-RateLimit-Max"), 64)
+	if errR != nil || errM != nil || max == 0 {
+		return
+	}
+	h.metrics.RecordRateLimitRemaining(h.Name(), remaining/max)
+}
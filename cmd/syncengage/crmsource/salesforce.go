@@ -0,0 +1,262 @@
+package crmsource
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncengage/crm"
+)
+
+const (
+	salesforceAPIVersion  = "v58.0"
+	salesforceJobPollWait = 2 * time.Second
+	salesforceJobTimeout  = 2 * time.Minute
+)
+
+// SalesforceSource incrementally syncs Contacts via Salesforce's Bulk API
+// 2.0 (a SOQL query job, polled to completion, then fetched as CSV) rather
+// than crm.SalesforceConnector's synchronous REST query, since a production
+// contact volume is too large for a single-request query to page through
+// every cycle.
+type SalesforceSource struct {
+	InstanceURL string
+	AccessToken string
+	httpClient  *http.Client
+	metrics     *Metrics
+
+	// CDC, if set, backs Subscribe with a real Change Data Capture
+	// subscription. Nil by default - construct one with
+	// NewSalesforceCDCSubscriber and assign it before calling Subscribe.
+	CDC *SalesforceCDCSubscriber
+}
+
+// NewSalesforceSource creates a source against instanceURL, authenticated
+// with accessToken. metrics may be nil to skip metrics recording.
+func NewSalesforceSource(instanceURL, accessToken string, metrics *Metrics) *SalesforceSource {
+	return &SalesforceSource{
+		InstanceURL: instanceURL,
+		AccessToken: accessToken,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		metrics:     metrics,
+	}
+}
+
+// Name implements CRMSource.
+func (s *SalesforceSource) Name() string { return "salesforce" }
+
+// Poll runs a Bulk API 2.0 query job for every Contact modified since since,
+// and returns the job's submission time as the next cursor - Salesforce
+// guarantees LastModifiedDate reflects server time, so a cursor taken before
+// the job ran never misses a record modified mid-query.
+func (s *SalesforceSource) Poll(ctx context.Context, since time.Time) ([]crm.Customer, time.Time, error) {
+	cursor := time.Now().UTC()
+	query := fmt.Sprintf(
+		"SELECT Id, Email, FirstName, LastName, Phone, TotalSpend__c, LifetimeValue__c FROM Contact WHERE LastModifiedDate > %s",
+		since.UTC().Format("2006-01-02T15:04:05.000Z"),
+	)
+
+	jobID, err := s.createQueryJob(ctx, query)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("crmsource: creating salesforce query job: %w", err)
+	}
+
+	if err := s.waitForJob(ctx, jobID); err != nil {
+		return nil, time.Time{}, fmt.Errorf("crmsource: salesforce query job %s: %w", jobID, err)
+	}
+
+	customers, err := s.fetchResults(ctx, jobID)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("crmsource: fetching salesforce job %s results: %w", jobID, err)
+	}
+
+	if s.metrics != nil {
+		s.metrics.RecordFetched(s.Name(), len(customers), cursor)
+	}
+	return customers, cursor, nil
+}
+
+// Stream is unsupported - CRMSource's Stream contract hands back full
+// crm.Customer records, but Change Data Capture events carry only changed
+// fields for an existing object, so they don't fit this channel's shape.
+// Use Subscribe for real-time CDC updates, or the webhook receiver via an
+// Outbound Message workflow for a full-record push channel.
+func (s *SalesforceSource) Stream(ctx context.Context) (<-chan crm.Customer, error) {
+	return nil, fmt.Errorf("crmsource: SalesforceSource does not support Stream, use Subscribe or the webhook receiver")
+}
+
+// Subscribe opens Salesforce Change Data Capture topics (e.g.
+// "/data/ContactChangeEvent", or a configured custom object's channel) via
+// s.CDC and returns a channel of CustomerChange deltas - the feature
+// store's real-time alternative to polling Contact on a timer. s.CDC must
+// be configured first; see NewSalesforceCDCSubscriber.
+func (s *SalesforceSource) Subscribe(ctx context.Context, topics []string, replayID []byte) (<-chan CustomerChange, error) {
+	if s.CDC == nil {
+		return nil, fmt.Errorf("crmsource: SalesforceSource.Subscribe requires CDC to be configured")
+	}
+	return s.CDC.Subscribe(ctx, topics, replayID)
+}
+
+func (s *SalesforceSource) createQueryJob(ctx context.Context, query string) (string, error) {
+	payload, _ := json.Marshal(map[string]string{
+		"operation":   "query",
+		"query":       query,
+		"contentType": "CSV",
+	})
+
+	req, err := http.NewRequestWithContext(ctx, "POST",
+		fmt.Sprintf("%s/services/data/%s/jobs/query", s.InstanceURL, salesforceAPIVersion),
+		strings.NewReader(string(payload)))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	s.recordRateLimit(resp)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("job creation failed: %d", resp.StatusCode)
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// waitForJob polls the job's state until JobComplete, Failed, or Aborted, or
+// salesforceJobTimeout elapses.
+func (s *SalesforceSource) waitForJob(ctx context.Context, jobID string) error {
+	deadline := time.Now().Add(salesforceJobTimeout)
+	for {
+		req, err := http.NewRequestWithContext(ctx, "GET",
+			fmt.Sprintf("%s/services/data/%s/jobs/query/%s", s.InstanceURL, salesforceAPIVersion, jobID), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+s.AccessToken)
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		s.recordRateLimit(resp)
+
+		var status struct {
+			State string `json:"state"`
+		}
+		decodeErr := json.NewDecoder(resp.Body).Decode(&status)
+		resp.Body.Close()
+		if decodeErr != nil {
+			return decodeErr
+		}
+
+		switch status.State {
+		case "JobComplete":
+			return nil
+		case "Failed", "Aborted":
+			return fmt.Errorf("job ended in state %s", status.State)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for job after %s", salesforceJobTimeout)
+		}
+		select {
+		case <-time.After(salesforceJobPollWait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *SalesforceSource) fetchResults(ctx context.Context, jobID string) ([]crm.Customer, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET",
+		fmt.Sprintf("%s/services/data/%s/jobs/query/%s/results", s.InstanceURL, salesforceAPIVersion, jobID), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.AccessToken)
+	req.Header.Set("Accept", "text/csv")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	s.recordRateLimit(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching results failed: %d", resp.StatusCode)
+	}
+
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 1 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	col := make(map[string]int, len(header))
+	for i, name := range header {
+		col[name] = i
+	}
+
+	customers := make([]crm.Customer, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		customer := crm.Customer{
+			ID:        row[col["Id"]],
+			Email:     row[col["Email"]],
+			FirstName: row[col["FirstName"]],
+			LastName:  row[col["LastName"]],
+			Phone:     row[col["Phone"]],
+		}
+		if idx, ok := col["TotalSpend__c"]; ok {
+			if parsed, err := strconv.ParseFloat(row[idx], 64); err == nil {
+				customer.TotalSpend = parsed
+			}
+		}
+		if idx, ok := col["LifetimeValue__c"]; ok {
+			if parsed, err := strconv.ParseFloat(row[idx], 64); err == nil {
+				customer.LTV = parsed
+			}
+		}
+		customers = append(customers, customer)
+	}
+	return customers, nil
+}
+
+// recordRateLimit parses Salesforce's "Sforce-Limit-Info: api-usage=X/Y"
+// response header into a remaining-fraction gauge.
+func (s *SalesforceSource) recordRateLimit(resp *http.Response) {
+	if s.metrics == nil {
+		return
+	}
+	header := resp.Header.Get("Sforce-Limit-Info")
+	usage := strings.TrimPrefix(header, "api-usage=")
+	parts := strings.SplitN(usage, "/", 2)
+	if len(parts) != 2 {
+		return
+	}
+	used, err1 := strconv.ParseFloat(parts[0], 64)
+	total, err2 := strconv.ParseFloat(parts[1], 64)
+	if err1 != nil || err2 != nil || total == 0 {
+		return
+	}
+	s.metrics.RecordRateLimitRemaining(s.Name(), 1-(used/total))
+}
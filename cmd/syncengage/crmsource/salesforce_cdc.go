@@ -0,0 +1,383 @@
+package crmsource
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncengage/crm"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// ChangeType identifies the kind of mutation a Salesforce Change Data
+// Capture event describes.
+type ChangeType string
+
+const (
+	ChangeCreate   ChangeType = "CREATE"
+	ChangeUpdate   ChangeType = "UPDATE"
+	ChangeDelete   ChangeType = "DELETE"
+	ChangeUndelete ChangeType = "UNDELETE"
+)
+
+// CustomerChange is one Salesforce Change Data Capture event for a Contact
+// (or a configured custom object), decoded from the Pub/Sub API's event
+// envelope into the fields the LTV feature store cares about.
+type CustomerChange struct {
+	ObjectID      string
+	ChangeType    ChangeType
+	ChangedFields map[string]interface{}
+
+	// ReplayID is monotonically increasing within a topic - persist it via
+	// ReplayStore and pass it back into Subscribe to resume from here.
+	ReplayID []byte
+}
+
+// ReplayStore persists the last committed CDC replayID per topic, so
+// Subscribe resumes from the last processed event after a restart instead
+// of replaying a topic from its earliest retained event or losing events
+// entirely - the CDC counterpart to CursorStore's role for Poll.
+type ReplayStore interface {
+	Get(ctx context.Context, topic string) ([]byte, error)
+	Set(ctx context.Context, topic string, replayID []byte) error
+}
+
+// MemoryReplayStore is the non-durable fallback: every topic's replayID
+// resets to nil (replay from the tenant's earliest retained event) on
+// restart.
+type MemoryReplayStore struct {
+	mu    sync.Mutex
+	saved map[string][]byte
+}
+
+// NewMemoryReplayStore creates an empty store.
+func NewMemoryReplayStore() *MemoryReplayStore {
+	return &MemoryReplayStore{saved: make(map[string][]byte)}
+}
+
+// Get implements ReplayStore.
+func (s *MemoryReplayStore) Get(ctx context.Context, topic string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saved[topic], nil
+}
+
+// Set implements ReplayStore.
+func (s *MemoryReplayStore) Set(ctx context.Context, topic string, replayID []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved[topic] = replayID
+	return nil
+}
+
+// replayStoreEntry is one line of a FileReplayStore's backing file.
+type replayStoreEntry struct {
+	Topic    string `json:"topic"`
+	ReplayID []byte `json:"replay_id"`
+}
+
+// FileReplayStore persists replayIDs as newline-delimited JSON, one entry
+// per topic - durable across restarts without standing up Postgres just to
+// hold a handful of bytes per topic.
+type FileReplayStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileReplayStore creates a store backed by path, which need not exist
+// yet.
+func NewFileReplayStore(path string) *FileReplayStore {
+	return &FileReplayStore{path: path}
+}
+
+// Get implements ReplayStore.
+func (s *FileReplayStore) Get(ctx context.Context, topic string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	return saved[topic], nil
+}
+
+// Set implements ReplayStore.
+func (s *FileReplayStore) Set(ctx context.Context, topic string, replayID []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved, err := s.readAll()
+	if err != nil {
+		return err
+	}
+	saved[topic] = replayID
+	return s.writeAll(saved)
+}
+
+func (s *FileReplayStore) readAll() (map[string][]byte, error) {
+	saved := make(map[string][]byte)
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return saved, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("crmsource: reading replay store %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry replayStoreEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("crmsource: parsing replay store %s: %w", s.path, err)
+		}
+		saved[entry.Topic] = entry.ReplayID
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("crmsource: reading replay store %s: %w", s.path, err)
+	}
+	return saved, nil
+}
+
+func (s *FileReplayStore) writeAll(saved map[string][]byte) error {
+	f, err := os.Create(s.path)
+	if err != nil {
+		return fmt.Errorf("crmsource: writing replay store %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for topic, replayID := range saved {
+		if err := enc.Encode(replayStoreEntry{Topic: topic, ReplayID: replayID}); err != nil {
+			return fmt.Errorf("crmsource: writing replay store %s: %w", s.path, err)
+		}
+	}
+	return nil
+}
+
+// pubSubEventType distinguishes an ordinary committed event from the two
+// Salesforce Pub/Sub API signals that mean some events were never
+// delivered: GAP (the broker skipped ahead of what it sent this
+// subscriber) and OVERFLOW (the subscriber fell too far behind and its
+// backlog was dropped). Either means the stream of CustomerChange deltas
+// can no longer be trusted to be complete.
+type pubSubEventType string
+
+const (
+	pubSubEventData     pubSubEventType = "DATA"
+	pubSubEventGap      pubSubEventType = "GAP"
+	pubSubEventOverflow pubSubEventType = "OVERFLOW"
+)
+
+// pubSubRawEvent is one message off the underlying Pub/Sub gRPC stream,
+// already decoded from its Avro envelope.
+type pubSubRawEvent struct {
+	Type          pubSubEventType
+	ObjectID      string
+	ChangeType    ChangeType
+	ChangedFields map[string]interface{}
+	ReplayID      []byte
+}
+
+// pubSubTransport abstracts the Salesforce Pub/Sub API's gRPC
+// ManagedSubscribe stream (topic negotiation, Avro schema fetch/decode,
+// flow control) behind the one method Subscribe needs. kiki-agent doesn't
+// vendor a generated Pub/Sub gRPC+Avro client yet - the same gap
+// predict.Client works around for api/pb - so production wiring supplies a
+// concrete pubSubTransport once that client exists; tests supply a fake.
+type pubSubTransport interface {
+	Recv(ctx context.Context) (pubSubRawEvent, error)
+	Close() error
+}
+
+// SalesforceCDCSubscriber consumes Salesforce Change Data Capture events
+// (ContactChangeEvent and configurable custom objects) over the Pub/Sub
+// API, replacing polling as the way the LTV feature store learns about
+// customer deltas. See SalesforceSource.Subscribe.
+type SalesforceCDCSubscriber struct {
+	// Transport is the underlying Pub/Sub gRPC stream. See pubSubTransport.
+	Transport pubSubTransport
+
+	// ReplayStore persists the last committed replayID per topic across
+	// restarts. Nil disables persistence - Subscribe always starts from
+	// the replayID its caller passed in.
+	ReplayStore ReplayStore
+
+	// Resync is called when the broker reports a GAP or OVERFLOW, to
+	// refill the feature store via crm.FetchCustomersAuto's bulk path
+	// instead of trusting a now-incomplete stream of deltas. Nil skips
+	// resyncing - the gap/overflow is still recorded in Metrics.
+	Resync func(ctx context.Context) ([]crm.Customer, error)
+
+	// OnResync receives the customers Resync fetched, so the caller can
+	// push them into the same downstream pipeline normal CustomerChange
+	// events feed. A resync is a different shape of update (a complete
+	// snapshot, not a delta), so it isn't sent on Subscribe's channel.
+	OnResync func(topic string, customers []crm.Customer)
+
+	// Metrics records subscriber health - event lag as a latency sample,
+	// GAP/OVERFLOW/disconnects as typed failures - the same
+	// shield.MetricsCollector CircuitBreaker uses, so one dashboard covers
+	// both. Nil disables recording.
+	Metrics *shield.MetricsCollector
+
+	mu          sync.Mutex
+	lastEventAt time.Time
+	disconnects int64
+}
+
+// NewSalesforceCDCSubscriber wires transport into a subscriber that
+// persists replay checkpoints in store (nil disables persistence) and
+// resyncs via resync on GAP/OVERFLOW (nil skips resyncing), recording
+// health into metrics (nil skips recording).
+func NewSalesforceCDCSubscriber(transport pubSubTransport, store ReplayStore, resync func(ctx context.Context) ([]crm.Customer, error), metrics *shield.MetricsCollector) *SalesforceCDCSubscriber {
+	return &SalesforceCDCSubscriber{
+		Transport:   transport,
+		ReplayStore: store,
+		Resync:      resync,
+		Metrics:     metrics,
+	}
+}
+
+// LastEventAge returns how long it's been since the subscriber last
+// received any event (including a GAP/OVERFLOW), or zero if none has
+// arrived yet.
+func (s *SalesforceCDCSubscriber) LastEventAge() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastEventAt.IsZero() {
+		return 0
+	}
+	return time.Since(s.lastEventAt)
+}
+
+// Disconnects returns how many times the underlying transport has dropped
+// the stream.
+func (s *SalesforceCDCSubscriber) Disconnects() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.disconnects
+}
+
+// Subscribe opens topics on the Pub/Sub API starting from replayID (or the
+// ReplayStore's last committed replayID for topics[0] if replayID is
+// nil), and returns a channel of CustomerChange deltas. The channel is
+// closed when ctx is done or the transport returns a non-recoverable
+// error.
+//
+// A GAP or OVERFLOW event triggers s.Resync instead of being forwarded -
+// the feature store can't reconstruct a missed delta, only catch up via a
+// full re-fetch - and is never itself sent on the returned channel.
+func (s *SalesforceCDCSubscriber) Subscribe(ctx context.Context, topics []string, replayID []byte) (<-chan CustomerChange, error) {
+	if s.Transport == nil {
+		return nil, fmt.Errorf("crmsource: SalesforceCDCSubscriber requires a Transport")
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("crmsource: Subscribe requires at least one topic")
+	}
+
+	topic := topics[0]
+	if s.ReplayStore != nil && len(replayID) == 0 {
+		stored, err := s.ReplayStore.Get(ctx, topic)
+		if err != nil {
+			return nil, fmt.Errorf("crmsource: loading replay checkpoint for %s: %w", topic, err)
+		}
+		replayID = stored
+	}
+
+	out := make(chan CustomerChange)
+	go s.run(ctx, topic, replayID, out)
+	return out, nil
+}
+
+func (s *SalesforceCDCSubscriber) run(ctx context.Context, topic string, replayID []byte, out chan<- CustomerChange) {
+	defer close(out)
+	defer s.Transport.Close()
+
+	for {
+		event, err := s.Transport.Recv(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.recordDisconnect()
+			}
+			return
+		}
+		s.recordEvent()
+
+		if event.Type == pubSubEventGap || event.Type == pubSubEventOverflow {
+			s.handleGapOrOverflow(ctx, topic, event)
+			continue
+		}
+
+		replayID = event.ReplayID
+		if s.ReplayStore != nil {
+			// Best effort: failing to persist a checkpoint only costs a
+			// replay of already-delivered events on the next restart, not
+			// a missed one, so it isn't worth dropping the event over.
+			_ = s.ReplayStore.Set(ctx, topic, replayID)
+		}
+
+		change := CustomerChange{
+			ObjectID:      event.ObjectID,
+			ChangeType:    event.ChangeType,
+			ChangedFields: event.ChangedFields,
+			ReplayID:      replayID,
+		}
+		select {
+		case out <- change:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *SalesforceCDCSubscriber) handleGapOrOverflow(ctx context.Context, topic string, event pubSubRawEvent) {
+	if s.Metrics != nil {
+		s.Metrics.RecordFailure(0, string(event.Type))
+	}
+	if s.Resync == nil {
+		return
+	}
+
+	customers, err := s.Resync(ctx)
+	if err != nil {
+		if s.Metrics != nil {
+			s.Metrics.RecordFailure(0, "resync_failed")
+		}
+		return
+	}
+	if s.OnResync != nil {
+		s.OnResync(topic, customers)
+	}
+	if s.ReplayStore != nil && len(event.ReplayID) > 0 {
+		_ = s.ReplayStore.Set(ctx, topic, event.ReplayID)
+	}
+}
+
+func (s *SalesforceCDCSubscriber) recordEvent() {
+	s.mu.Lock()
+	lag := time.Duration(0)
+	if !s.lastEventAt.IsZero() {
+		lag = time.Since(s.lastEventAt)
+	}
+	s.lastEventAt = time.Now()
+	s.mu.Unlock()
+
+	if s.Metrics != nil {
+		s.Metrics.RecordRequest()
+		s.Metrics.RecordSuccess(lag)
+	}
+}
+
+func (s *SalesforceCDCSubscriber) recordDisconnect() {
+	s.mu.Lock()
+	s.disconnects++
+	s.mu.Unlock()
+
+	if s.Metrics != nil {
+		s.Metrics.RecordFailure(0, "disconnect")
+	}
+}
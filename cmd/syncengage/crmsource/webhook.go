@@ -0,0 +1,145 @@
+package crmsource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncengage/crm"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/signing"
+)
+
+// WebhookHandler is an http.Handler for /webhook/{provider}, verifying each
+// delivery's HMAC signature before decoding it into a crm.Customer and
+// handing it to OnCustomer - the real-time counterpart to Poll for sources
+// whose CRM pushes changes rather than waiting to be asked.
+type WebhookHandler struct {
+	// Secrets maps provider name (as it appears in the URL path) to the
+	// shared secret used to verify that provider's deliveries.
+	Secrets map[string]string
+
+	// OnCustomer receives every successfully verified and decoded
+	// customer. Must be safe to call from concurrent goroutines.
+	OnCustomer func(provider string, customer crm.Customer)
+}
+
+// NewWebhookHandler creates a handler keyed by provider -> shared secret.
+func NewWebhookHandler(secrets map[string]string, onCustomer func(provider string, customer crm.Customer)) *WebhookHandler {
+	return &WebhookHandler{Secrets: secrets, OnCustomer: onCustomer}
+}
+
+// ServeHTTP expects to be mounted at a prefix like "/webhook/" so
+// r.URL.Path's final segment names the provider (e.g. "/webhook/hubspot").
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	provider := strings.TrimPrefix(r.URL.Path, "/webhook/")
+	secret, ok := h.Secrets[provider]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown provider %q", provider), http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.verify(provider, secret, r, body) {
+		http.Error(w, "invalid webhook signature", http.StatusUnauthorized)
+		return
+	}
+
+	customer, err := decodeCustomer(provider, body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("decoding %s payload: %v", provider, err), http.StatusBadRequest)
+		return
+	}
+
+	h.OnCustomer(provider, customer)
+	w.WriteHeader(http.StatusOK)
+}
+
+// verify dispatches to the provider's own signature scheme where one is
+// documented (HubSpot's v3 scheme), falling back to a generic shared-secret
+// HMAC for everyone else (e.g. a Salesforce Outbound Message relay).
+func (h *WebhookHandler) verify(provider, secret string, r *http.Request, body []byte) bool {
+	switch provider {
+	case "hubspot":
+		timestamp := r.Header.Get("X-HubSpot-Request-Timestamp")
+		signature := r.Header.Get("X-HubSpot-Signature-v3")
+		return signing.NewHubSpotWebhookSigner(secret).VerifyWebhook(r.Method, r.URL.RequestURI(), body, timestamp, signature)
+	default:
+		return signing.NewGenericWebhookSigner(secret).VerifyWebhook(body, r.Header.Get("X-Signature"))
+	}
+}
+
+// decodeCustomer parses a provider's native webhook payload shape into a
+// crm.Customer.
+func decodeCustomer(provider string, body []byte) (crm.Customer, error) {
+	switch provider {
+	case "hubspot":
+		return decodeHubSpotWebhookCustomer(body)
+	case "salesforce":
+		return decodeSalesforceWebhookCustomer(body)
+	default:
+		return crm.Customer{}, fmt.Errorf("unrecognized provider %q", provider)
+	}
+}
+
+func decodeHubSpotWebhookCustomer(body []byte) (crm.Customer, error) {
+	var payload struct {
+		ObjectID   int64 `json:"objectId"`
+		Properties struct {
+			Email         string `json:"email"`
+			FirstName     string `json:"firstname"`
+			LastName      string `json:"lastname"`
+			Phone         string `json:"phone"`
+			TotalRevenue  string `json:"total_revenue"`
+			LifetimeValue string `json:"hs_lifetimevalue"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return crm.Customer{}, err
+	}
+
+	customer := crm.Customer{
+		ID:             strconv.FormatInt(payload.ObjectID, 10),
+		Email:          payload.Properties.Email,
+		FirstName:      payload.Properties.FirstName,
+		LastName:       payload.Properties.LastName,
+		Phone:          payload.Properties.Phone,
+		LastEngagement: time.Now(),
+	}
+	if parsed, err := strconv.ParseFloat(payload.Properties.TotalRevenue, 64); err == nil {
+		customer.TotalSpend = parsed
+	}
+	if parsed, err := strconv.ParseFloat(payload.Properties.LifetimeValue, 64); err == nil {
+		customer.LTV = parsed
+	}
+	return customer, nil
+}
+
+func decodeSalesforceWebhookCustomer(body []byte) (crm.Customer, error) {
+	var payload struct {
+		ID        string `json:"Id"`
+		Email     string `json:"Email"`
+		FirstName string `json:"FirstName"`
+		LastName  string `json:"LastName"`
+		Phone     string `json:"Phone"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return crm.Customer{}, err
+	}
+	return crm.Customer{
+		ID:             payload.ID,
+		Email:          payload.Email,
+		FirstName:      payload.FirstName,
+		LastName:       payload.LastName,
+		Phone:          payload.Phone,
+		LastEngagement: time.Now(),
+	}, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"sort"
+	"sync"
+)
+
+// ltvFallbackSamples caps how many recent LTV predictions are kept per
+// customer, the same bounded-history tradeoff
+// connectors.HeuristicFallbackEngine makes for platform bid history.
+const ltvFallbackSamples = 20
+
+// ltvFallbackEngine remembers each customer's recent LTV predictions so that
+// once ltvBreaker trips open, fetchLTV has the median of what the AI brain
+// itself reported for that customer to fall back on, rather than dropping
+// straight to the generic spend/engagement heuristic. Both triggerRetentionHandler
+// and simulateCRMPolling can call into it concurrently, so access is
+// mutex-guarded.
+type ltvFallbackEngine struct {
+	mu      sync.Mutex
+	history map[string][]float64
+}
+
+// newLTVFallbackEngine creates an empty ltvFallbackEngine.
+func newLTVFallbackEngine() *ltvFallbackEngine {
+	return &ltvFallbackEngine{history: make(map[string][]float64)}
+}
+
+// Record appends a freshly predicted LTV for customerID, trimming to the
+// most recent ltvFallbackSamples.
+func (f *ltvFallbackEngine) Record(customerID string, ltv float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	samples := append(f.history[customerID], ltv)
+	if len(samples) > ltvFallbackSamples {
+		samples = samples[len(samples)-ltvFallbackSamples:]
+	}
+	f.history[customerID] = samples
+}
+
+// Median returns the median of customerID's recorded LTV predictions, or the
+// same spend/engagement heuristic ltvconnector.Predict uses when the
+// connection is down if no history exists yet for this customer.
+func (f *ltvFallbackEngine) Median(customerID string, spend, score float64) float64 {
+	f.mu.Lock()
+	samples := append([]float64(nil), f.history[customerID]...)
+	f.mu.Unlock()
+
+	if len(samples) == 0 {
+		return spend*1.2 + score*10
+	}
+
+	sort.Float64s(samples)
+	mid := len(samples) / 2
+	if len(samples)%2 == 0 {
+		return (samples[mid-1] + samples[mid]) / 2
+	}
+	return samples[mid]
+}
@@ -0,0 +1,318 @@
+// Package ltvconnector supervises SyncEngage's connection to the LTV
+// prediction gRPC service. initLTVService used to dial once at startup and
+// silently degrade to heuristic mode forever if that first dial failed;
+// LTVConnector instead runs a supervised reconnect loop so a restart of the
+// LTV service is recovered from automatically.
+package ltvconnector
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/user/kiki-agent/api/pb"
+	"github.com/user/kiki-agent/security/mtls"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	defaultMaxBackoff        = 60 * time.Second
+	defaultPredictTimeout    = 200 * time.Millisecond
+	defaultHealthProbePeriod = 5 * time.Second
+
+	// Env vars configuring mutual TLS for the dial to SyncValueService.
+	// All three must be set, or the connector falls back to an insecure
+	// dial - the right default for local dev, but the wrong one for a
+	// production bidder talking to a real budget-governing service.
+	envClientCertFile = "LTV_CLIENT_CERT_FILE"
+	envClientKeyFile  = "LTV_CLIENT_KEY_FILE"
+	envCAFile         = "LTV_CA_FILE"
+)
+
+// dialCredentials builds mTLS TransportCredentials from
+// LTV_CLIENT_CERT_FILE/LTV_CLIENT_KEY_FILE/LTV_CA_FILE if all three are
+// set, otherwise falls back to an insecure dial (logged, so a missing
+// config doesn't silently ship without transport security).
+func dialCredentials() credentials.TransportCredentials {
+	certFile, keyFile, caFile := os.Getenv(envClientCertFile), os.Getenv(envClientKeyFile), os.Getenv(envCAFile)
+	if certFile == "" || keyFile == "" || caFile == "" {
+		log.Printf("⚠️ LTVConnector: %s/%s/%s not fully set - dialing SyncValueService without mutual TLS", envClientCertFile, envClientKeyFile, envCAFile)
+		return insecure.NewCredentials()
+	}
+
+	cert, err := mtls.NewHotReloadCert(certFile, keyFile)
+	if err != nil {
+		log.Printf("⚠️ LTVConnector: loading client cert/key, falling back to insecure dial: %v", err)
+		return insecure.NewCredentials()
+	}
+	clientCfg := &mtls.ClientConfig{Cert: cert, Trust: mtls.NewFileTrustStore(caFile)}
+	creds, err := clientCfg.DialCredentials(context.Background())
+	if err != nil {
+		log.Printf("⚠️ LTVConnector: building mTLS credentials, falling back to insecure dial: %v", err)
+		return insecure.NewCredentials()
+	}
+	return creds
+}
+
+// Resolver returns the next gRPC target LTVConnector should dial, so
+// operators can point at a single address, a static HA pool, or a DNS SRV
+// record without changing the reconnect loop itself.
+type Resolver interface {
+	Resolve(ctx context.Context) (string, error)
+}
+
+// EnvResolver resolves to the address in an env var, falling back to
+// Default if it's unset - the same single-target behavior initLTVService
+// had, wrapped so it composes with the other Resolver implementations.
+type EnvResolver struct {
+	EnvVar  string
+	Default string
+}
+
+// Resolve implements Resolver.
+func (r EnvResolver) Resolve(ctx context.Context) (string, error) {
+	if addr := os.Getenv(r.EnvVar); addr != "" {
+		return addr, nil
+	}
+	return r.Default, nil
+}
+
+// StaticListResolver round-robins across a fixed pool of addresses, for
+// operators running their own HA pool without DNS.
+type StaticListResolver struct {
+	Addrs []string
+
+	mu   sync.Mutex
+	next int
+}
+
+// Resolve implements Resolver.
+func (r *StaticListResolver) Resolve(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.Addrs) == 0 {
+		return "", fmt.Errorf("ltvconnector: static resolver has no addresses configured")
+	}
+	addr := r.Addrs[r.next%len(r.Addrs)]
+	r.next++
+	return addr, nil
+}
+
+// DNSSRVResolver resolves an HA pool via a DNS SRV lookup on every call, so
+// pool members added or retired behind DNS are picked up without a
+// redeploy.
+type DNSSRVResolver struct {
+	Service string
+	Proto   string
+	Name    string
+}
+
+// Resolve implements Resolver.
+func (r DNSSRVResolver) Resolve(ctx context.Context) (string, error) {
+	_, targets, err := net.DefaultResolver.LookupSRV(ctx, r.Service, r.Proto, r.Name)
+	if err != nil {
+		return "", fmt.Errorf("ltvconnector: SRV lookup for %s failed: %w", r.Name, err)
+	}
+	if len(targets) == 0 {
+		return "", fmt.Errorf("ltvconnector: SRV lookup for %s returned no targets", r.Name)
+	}
+	t := targets[0]
+	return net.JoinHostPort(strings.TrimSuffix(t.Target, "."), strconv.Itoa(int(t.Port))), nil
+}
+
+// fibonacciBackoff yields successive delays 1s, 1s, 2s, 3s, 5s, 8s, ...
+// capped at max, the same reconnect cadence used elsewhere in this repo's
+// peer projects for supervised connections. reset restarts the sequence
+// after a successful Connect so a long-lived connection doesn't carry a
+// stale backoff into its next failure.
+type fibonacciBackoff struct {
+	max  time.Duration
+	a, b time.Duration
+}
+
+func newFibonacciBackoff(max time.Duration) *fibonacciBackoff {
+	f := &fibonacciBackoff{max: max}
+	f.reset()
+	return f
+}
+
+func (f *fibonacciBackoff) reset() {
+	f.a, f.b = time.Second, time.Second
+}
+
+func (f *fibonacciBackoff) next() time.Duration {
+	delay := f.a
+	if delay > f.max {
+		delay = f.max
+	}
+	f.a, f.b = f.b, f.a+f.b
+	return delay
+}
+
+// LTVConnector supervises a connection to the LTV gRPC service, reconnecting
+// on a Fibonacci backoff whenever the underlying ClientConn leaves READY,
+// and answering Predict with a heuristic fallback rather than attempting a
+// call over a known-bad connection.
+type LTVConnector struct {
+	Resolver   Resolver
+	MaxBackoff time.Duration
+
+	mu     sync.RWMutex
+	conn   *grpc.ClientConn
+	client pb.LTVServiceClient
+
+	healthy atomic.Bool
+
+	// credentials is resolved once, at construction, rather than per
+	// dial - a config error surfaces (as a logged fallback to insecure)
+	// at startup instead of silently flipping transport security on a
+	// later reconnect.
+	credentials credentials.TransportCredentials
+}
+
+// NewLTVConnector creates a connector that will dial via resolver once
+// Start is called.
+func NewLTVConnector(resolver Resolver) *LTVConnector {
+	return &LTVConnector{Resolver: resolver, MaxBackoff: defaultMaxBackoff, credentials: dialCredentials()}
+}
+
+// Start launches the supervised reconnect loop and the health probe
+// goroutine in the background; both run until ctx is canceled.
+func (c *LTVConnector) Start(ctx context.Context) {
+	go c.reconnectLoop(ctx)
+	go c.healthProbeLoop(ctx)
+}
+
+// reconnectLoop dials via c.Resolver, backing off on a Fibonacci sequence
+// between failed attempts, and blocks on the live connection until it
+// leaves READY before looping around to reconnect.
+func (c *LTVConnector) reconnectLoop(ctx context.Context) {
+	backoff := newFibonacciBackoff(c.MaxBackoff)
+	for ctx.Err() == nil {
+		if err := c.connect(ctx); err != nil {
+			delay := backoff.next()
+			log.Printf("⚠️ LTVConnector: dial failed, retrying in %s: %v", delay, err)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		backoff.reset()
+		log.Println("✅ Connected to SyncValue™ AI Brain (LTV Service)")
+		c.waitUntilDown(ctx)
+	}
+}
+
+// connect resolves the next dial target and replaces the current
+// connection with a freshly dialed one.
+func (c *LTVConnector) connect(ctx context.Context) error {
+	target, err := c.Resolver.Resolve(ctx)
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(c.credentials))
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = conn
+	c.client = pb.NewLTVServiceClient(conn)
+	c.mu.Unlock()
+	return nil
+}
+
+// waitUntilDown blocks until the current connection transitions to
+// TransientFailure or Shutdown, or ctx is canceled.
+func (c *LTVConnector) waitUntilDown(ctx context.Context) {
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+	if conn == nil {
+		return
+	}
+
+	state := conn.GetState()
+	for state != connectivity.TransientFailure && state != connectivity.Shutdown {
+		if !conn.WaitForStateChange(ctx, state) {
+			return
+		}
+		state = conn.GetState()
+	}
+}
+
+// healthProbeLoop periodically samples the real connection state into an
+// atomic flag, so Healthy() never has to take c.mu on the HTTP request path
+// just to answer a health check.
+func (c *LTVConnector) healthProbeLoop(ctx context.Context) {
+	ticker := time.NewTicker(defaultHealthProbePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.healthy.Store(c.ready())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ready reports whether the underlying ClientConn is currently READY.
+func (c *LTVConnector) ready() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.conn != nil && c.conn.GetState() == connectivity.Ready
+}
+
+// Healthy reports the connection state as of the last health probe tick,
+// for surfacing on the /health endpoint.
+func (c *LTVConnector) Healthy() bool {
+	return c.healthy.Load()
+}
+
+// Predict returns an LTV prediction for customerID, falling back to the
+// same heuristic formula SyncEngage always used whenever the connection
+// isn't READY, rather than attempting a call that's likely to time out.
+func (c *LTVConnector) Predict(ctx context.Context, customerID string, spend, score float64) (float64, string, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	if client == nil || !c.ready() {
+		return fallbackLTV(spend, score), "heuristic: LTV service not ready", nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultPredictTimeout)
+	defer cancel()
+
+	resp, err := client.PredictLTV(ctx, &pb.LTVRequest{
+		CustomerId:      customerID,
+		RecentSpend:     spend,
+		EngagementScore: score,
+	})
+	if err != nil {
+		return fallbackLTV(spend, score), fmt.Sprintf("heuristic: %v", err), nil
+	}
+	return resp.PredictedLtv, resp.Explanation, nil
+}
+
+func fallbackLTV(spend, score float64) float64 {
+	return spend*1.2 + score*10
+}
@@ -1,354 +1,571 @@
-package main
-
-import (
-	"context"
-	"encoding/csv"
-	"encoding/json"
-	"fmt"
-	"log"
-	"net/http"
-	"os"
-	"time"
-
-	pb "github.com/user/kiki-agent/api/pb"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-)
-
-// Customer represents a CRM customer record
-type Customer struct {
-	ID              string    `json:"customer_id"`
-	Email           string    `json:"email"`
-	LastPurchase    time.Time `json:"last_purchase"`
-	TotalSpend      float64   `json:"total_spend"`
-	PurchaseCount   int       `json:"purchase_count"`
-	EngagementScore float64   `json:"engagement_score"`
-	LTV             float64   `json:"ltv"`
-	ChurnRisk       string    `json:"churn_risk"` // low, medium, high
-	LastEngagement  time.Time `json:"last_engagement"`
-}
-
-// RetentionTrigger represents an automated retention action
-type RetentionTrigger struct {
-	CustomerID   string    `json:"customer_id"`
-	TriggerType  string    `json:"trigger_type"` // dormant, churn_risk, high_value_check
-	Action       string    `json:"action"`       // email, offer, survey
-	Message      string    `json:"message"`
-	DiscountPct  float64   `json:"discount_pct"`
-	ExecutedAt   time.Time `json:"executed_at"`
-	PredictedLTV float64   `json:"predicted_ltv"`
-}
-
-// Global state
-var (
-	ltvClient   pb.LTVServiceClient
-	auditLogger *csv.Writer
-	auditFile   *os.File
-)
-
-// Initialize LTV service connection
-func initLTVService() {
-	addr := os.Getenv("LTV_GRPC_ADDR")
-	if addr == "" {
-		addr = "localhost:50051"
-	}
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		log.Printf("⚠️ Warning: Could not connect to LTV service: %v", err)
-		log.Printf("🔄 SyncEngage will operate in heuristic mode")
-		return
-	}
-	ltvClient = pb.NewLTVServiceClient(conn)
-	log.Println("✅ Connected to SyncValue™ AI Brain (LTV Service)")
-}
-
-// Fetch LTV prediction for a customer
-func fetchLTV(customerID string, spend, score float64) (float64, string) {
-	if ltvClient == nil {
-		// Fallback heuristic
-		ltv := spend*1.2 + score*10
-		return ltv, "Heuristic: No LTV service available"
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
-	defer cancel()
-
-	resp, err := ltvClient.PredictLTV(ctx, &pb.LTVRequest{
-		CustomerId:      customerID,
-		RecentSpend:     spend,
-		EngagementScore: score,
-	})
-
-	if err != nil {
-		log.Printf("⚠️ LTV prediction failed: %v", err)
-		ltv := spend*1.2 + score*10
-		return ltv, "Fallback heuristic"
-	}
-
-	return resp.PredictedLtv, resp.Explanation
-}
-
-// Assess churn risk based on recency and engagement
-func assessChurnRisk(customer Customer) string {
-	daysSinceLastPurchase := time.Since(customer.LastPurchase).Hours() / 24
-	daysSinceLastEngagement := time.Since(customer.LastEngagement).Hours() / 24
-
-	if daysSinceLastPurchase > 90 || daysSinceLastEngagement > 60 {
-		return "high"
-	} else if daysSinceLastPurchase > 45 || daysSinceLastEngagement > 30 {
-		return "medium"
-	}
-	return "low"
-}
-
-// Generate retention trigger based on customer profile
-func generateRetentionTrigger(customer Customer) *RetentionTrigger {
-	ltv, explanation := fetchLTV(customer.ID, customer.TotalSpend, customer.EngagementScore)
-	customer.LTV = ltv
-	customer.ChurnRisk = assessChurnRisk(customer)
-
-	log.Printf("📊 Customer %s | LTV: %.2f | Churn Risk: %s", customer.ID, ltv, customer.ChurnRisk)
-	log.Printf("   🧠 AI: %s", explanation)
-
-	trigger := &RetentionTrigger{
-		CustomerID:   customer.ID,
-		ExecutedAt:   time.Now(),
-		PredictedLTV: ltv,
-	}
-
-	// Decision logic based on churn risk and LTV
-	switch customer.ChurnRisk {
-	case "high":
-		if ltv > 200 {
-			// High-value at-risk customer
-			trigger.TriggerType = "churn_risk_high_value"
-			trigger.Action = "offer"
-			trigger.DiscountPct = 20.0
-			trigger.Message = fmt.Sprintf("We miss you! Here's 20%% off your next order (LTV: $%.0f)", ltv)
-			log.Printf("🎯 RETENTION TRIGGER: High-value customer (LTV $%.0f) - 20%% discount offer", ltv)
-		} else {
-			// Standard at-risk customer
-			trigger.TriggerType = "churn_risk_standard"
-			trigger.Action = "email"
-			trigger.DiscountPct = 10.0
-			trigger.Message = "We haven't seen you in a while. Here's 10% off to welcome you back!"
-			log.Printf("📧 RETENTION TRIGGER: At-risk customer (LTV $%.0f) - 10%% re-engagement email", ltv)
-		}
-
-	case "medium":
-		if ltv > 300 {
-			// Premium customer check-in
-			trigger.TriggerType = "high_value_check"
-			trigger.Action = "survey"
-			trigger.Message = "How's everything going? We value your feedback."
-			log.Printf("⭐ LOYALTY TRIGGER: Premium customer (LTV $%.0f) - feedback survey", ltv)
-		} else {
-			// Moderate engagement nudge
-			trigger.TriggerType = "dormant"
-			trigger.Action = "email"
-			trigger.Message = "Check out what's new! Exclusive updates just for you."
-			log.Printf("📬 ENGAGEMENT TRIGGER: Moderate customer (LTV $%.0f) - content update", ltv)
-		}
-
-	case "low":
-		if ltv > 500 {
-			// VIP customer appreciation
-			trigger.TriggerType = "high_value_check"
-			trigger.Action = "offer"
-			trigger.DiscountPct = 15.0
-			trigger.Message = fmt.Sprintf("Thank you for being a VIP! Exclusive 15%% off (LTV: $%.0f)", ltv)
-			log.Printf("👑 VIP TRIGGER: High-value loyal customer (LTV $%.0f) - exclusive offer", ltv)
-		}
-		// Active low-risk customers don't need aggressive triggers
-	}
-
-	// Compliance check with SyncShield before executing trigger
-	if trigger.TriggerType != "" {
-		if guardWithSyncShield(trigger.PredictedLTV) {
-			logAudit(trigger)
-		} else {
-			log.Printf("🛡️ COMPLIANCE: Trigger for %s suppressed by SyncShield", customer.ID)
-			return nil
-		}
-	}
-
-	return trigger
-}
-
-// Log retention trigger to audit trail
-func logAudit(trigger *RetentionTrigger) {
-	if auditLogger == nil {
-		return
-	}
-	auditLogger.Write([]string{
-		trigger.ExecutedAt.Format(time.RFC3339),
-		trigger.CustomerID,
-		trigger.TriggerType,
-		trigger.Action,
-		trigger.Message,
-		fmt.Sprintf("%.2f", trigger.DiscountPct),
-		fmt.Sprintf("%.2f", trigger.PredictedLTV),
-	})
-	auditLogger.Flush()
-}
-
-// Initialize audit log
-func initAuditLog() {
-	var err error
-	auditFile, err = os.OpenFile("syncengage_audit.csv", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		log.Printf("⚠️ Could not open audit log: %v", err)
-		return
-	}
-
-	auditLogger = csv.NewWriter(auditFile)
-	// Write header if new file
-	fileInfo, _ := auditFile.Stat()
-	if fileInfo.Size() == 0 {
-		auditLogger.Write([]string{"timestamp", "customer_id", "trigger_type", "action", "message", "discount_pct", "predicted_ltv"})
-		auditLogger.Flush()
-	}
-
-	log.Println("✅ Audit log initialized: syncengage_audit.csv")
-}
-
-// guardWithSyncShield queries SyncShield to validate budget/governor
-func guardWithSyncShield(ltv float64) bool {
-	// call SHIELD_URL (env) or default http://localhost:8081/check
-	client := &http.Client{Timeout: 400 * time.Millisecond}
-	shieldURL := os.Getenv("SHIELD_URL")
-	if shieldURL == "" {
-		shieldURL = "http://localhost:8081/check"
-	}
-	url := fmt.Sprintf("%s?ltv=%.2f", shieldURL, ltv)
-	resp, err := client.Get(url)
-	if err != nil {
-		// Be permissive if SyncShield is unreachable
-		log.Printf("⚠️ SyncShield unreachable (%v). Proceeding permissively.", err)
-		return true
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode == http.StatusOK {
-		return true
-	}
-	if resp.StatusCode == http.StatusForbidden {
-		return false
-	}
-	// Default permissive for any other status
-	return true
-}
-
-// HTTP health endpoint
-func healthHandler(w http.ResponseWriter, r *http.Request) {
-	status := map[string]interface{}{
-		"status":     "healthy",
-		"service":    "SyncEngage™ Retention Agent",
-		"ltv_client": ltvClient != nil,
-		"timestamp":  time.Now().Format(time.RFC3339),
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(status)
-}
-
-// HTTP API endpoint to trigger retention check for a customer
-func triggerRetentionHandler(w http.ResponseWriter, r *http.Request) {
-	var customer Customer
-	if err := json.NewDecoder(r.Body).Decode(&customer); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	trigger := generateRetentionTrigger(customer)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(trigger)
-}
-
-// Simulate CRM data polling (in production, this would integrate with Salesforce, HubSpot, etc.)
-func simulateCRMPolling() {
-	log.Println("🔄 Starting CRM polling simulation...")
-
-	// Sample customer data
-	customers := []Customer{
-		{
-			ID:              "cust_001",
-			Email:           "alice@example.com",
-			LastPurchase:    time.Now().AddDate(0, 0, -95), // 95 days ago
-			TotalSpend:      450.0,
-			PurchaseCount:   12,
-			EngagementScore: 8.5,
-			LastEngagement:  time.Now().AddDate(0, 0, -70),
-		},
-		{
-			ID:              "cust_002",
-			Email:           "bob@example.com",
-			LastPurchase:    time.Now().AddDate(0, 0, -15), // 15 days ago
-			TotalSpend:      850.0,
-			PurchaseCount:   28,
-			EngagementScore: 9.2,
-			LastEngagement:  time.Now().AddDate(0, 0, -3),
-		},
-		{
-			ID:              "cust_003",
-			Email:           "charlie@example.com",
-			LastPurchase:    time.Now().AddDate(0, 0, -50), // 50 days ago
-			TotalSpend:      120.0,
-			PurchaseCount:   4,
-			EngagementScore: 6.0,
-			LastEngagement:  time.Now().AddDate(0, 0, -35),
-		},
-		{
-			ID:              "cust_004",
-			Email:           "diana@example.com",
-			LastPurchase:    time.Now().AddDate(0, 0, -5), // 5 days ago
-			TotalSpend:      1200.0,
-			PurchaseCount:   45,
-			EngagementScore: 9.8,
-			LastEngagement:  time.Now().AddDate(0, 0, -1),
-		},
-	}
-
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		log.Println("\n═══════════════════════════════════════════════════")
-		log.Println("🔍 CRM Sync Cycle: Analyzing customer retention...")
-		log.Println("═══════════════════════════════════════════════════")
-
-		for _, customer := range customers {
-			generateRetentionTrigger(customer)
-		}
-
-		log.Println("═══════════════════════════════════════════════════")
-	}
-}
-
-func main() {
-	log.Println("🚀 KIKI SyncEngage™ - Post-Acquisition Loyalty Agent")
-	log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-	initAuditLog()
-	defer func() {
-		if auditFile != nil {
-			auditFile.Close()
-		}
-	}()
-
-	initLTVService()
-
-	// Start HTTP server
-	http.HandleFunc("/health", healthHandler)
-	http.HandleFunc("/trigger", triggerRetentionHandler)
-
-	go func() {
-		log.Println("🌐 HTTP API starting on :8083")
-		log.Println("   Health: http://localhost:8083/health")
-		log.Println("   Trigger: POST http://localhost:8083/trigger")
-		if err := http.ListenAndServe(":8083", nil); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
-	// Start CRM polling simulation
-	simulateCRMPolling()
-}
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/user/kiki-agent/cmd/syncengage/auditsink"
+	"github.com/user/kiki-agent/cmd/syncengage/coordinator"
+	"github.com/user/kiki-agent/cmd/syncengage/crm"
+	"github.com/user/kiki-agent/cmd/syncengage/crmsource"
+	"github.com/user/kiki-agent/cmd/syncengage/ltvconnector"
+	"github.com/user/kiki-agent/cmd/syncengage/shieldclient"
+	"github.com/user/kiki-agent/cmd/syncshield/observability"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// Customer represents a CRM customer record
+type Customer struct {
+	ID              string    `json:"customer_id"`
+	Email           string    `json:"email"`
+	LastPurchase    time.Time `json:"last_purchase"`
+	TotalSpend      float64   `json:"total_spend"`
+	PurchaseCount   int       `json:"purchase_count"`
+	EngagementScore float64   `json:"engagement_score"`
+	LTV             float64   `json:"ltv"`
+	ChurnRisk       string    `json:"churn_risk"` // low, medium, high
+	LastEngagement  time.Time `json:"last_engagement"`
+}
+
+// circuitLatencySLA is the latency above which a successful LTV or
+// SyncShield call is still treated as a circuit breaker failure, matching
+// shield.NewCircuitBreaker's own latencyThreshold default so a slow-but-200
+// dependency trips the breaker exactly as fast as an outright error would.
+const circuitLatencySLA = 500 * time.Millisecond
+
+// RetentionTrigger represents an automated retention action
+type RetentionTrigger struct {
+	CustomerID   string    `json:"customer_id"`
+	TriggerType  string    `json:"trigger_type"` // dormant, churn_risk, high_value_check
+	Action       string    `json:"action"`       // email, offer, survey
+	Message      string    `json:"message"`
+	DiscountPct  float64   `json:"discount_pct"`
+	ExecutedAt   time.Time `json:"executed_at"`
+	PredictedLTV float64   `json:"predicted_ltv"`
+}
+
+// Global state
+var (
+	ltvConn      = ltvconnector.NewLTVConnector(ltvResolver())
+	auditSink    *auditsink.BufferedSink
+	shieldClient = shieldclient.NewClient("")
+
+	// ltvBreaker and shieldBreaker isolate SyncEngage from a slow or down LTV
+	// service / SyncShield the same way connectors isolate PlaceBid from a
+	// slow ad platform: 3 consecutive failures or >500ms latencies (shield's
+	// own defaults, see shield.NewCircuitBreaker) trip the breaker open.
+	ltvBreaker    = shield.NewCircuitBreaker()
+	shieldBreaker = shield.NewCircuitBreaker()
+	ltvFallback   = newLTVFallbackEngine()
+
+	// shieldDenyOnOpen controls what guardTrigger does once shieldBreaker is
+	// open: deny (the conservative default - don't run retention triggers
+	// SyncShield hasn't had a chance to review) unless an operator opts into
+	// fail-open via SHIELD_DENY_ON_OPEN=false.
+	shieldDenyOnOpen = os.Getenv("SHIELD_DENY_ON_OPEN") != "false"
+
+	// retentionCoordinator decides which replica owns each customer and
+	// dedups trigger emission across replicas/restarts. Set by
+	// initRetentionCoordinator in main.
+	retentionCoordinator *coordinator.RetentionCoordinator
+
+	// crmSources, crmCursors, and crmMetrics back simulateCRMPolling's
+	// incremental sync: crmSources is whichever of Salesforce/HubSpot have
+	// credentials configured, crmCursors persists each source's "since"
+	// watermark, and crmMetrics tracks records fetched/cursor lag/rate
+	// limit headroom per source. Populated by initCRMSources in main.
+	crmSources []crmsource.CRMSource
+	crmCursors crmsource.CursorStore
+	crmMetrics = crmsource.NewMetrics()
+)
+
+// ltvResolver builds the LTVConnector's target resolver from env vars: a
+// comma-separated LTV_GRPC_ADDRS pool takes priority (round-robined), then
+// a single LTV_GRPC_ADDR, then the historical localhost:50051 default.
+func ltvResolver() ltvconnector.Resolver {
+	if pool := os.Getenv("LTV_GRPC_ADDRS"); pool != "" {
+		return &ltvconnector.StaticListResolver{Addrs: strings.Split(pool, ",")}
+	}
+	return ltvconnector.EnvResolver{EnvVar: "LTV_GRPC_ADDR", Default: "localhost:50051"}
+}
+
+// Initialize LTV service connection
+func initLTVService(ctx context.Context) {
+	ltvConn.Start(ctx)
+}
+
+// initCRMSources builds crmSources from whichever of SALESFORCE_INSTANCE_URL
+// /SALESFORCE_ACCESS_TOKEN and HUBSPOT_API_KEY are configured, and
+// crmCursors against AUDIT_POSTGRES_DSN if set - the same durable store
+// auditsink.PostgresSink uses - falling back to a non-durable in-memory
+// store (one extra full resync after a restart) otherwise.
+func initCRMSources() {
+	if dsn := os.Getenv("AUDIT_POSTGRES_DSN"); dsn != "" {
+		store, err := crmsource.NewPostgresCursorStore(dsn)
+		if err != nil {
+			log.Printf("⚠️ Could not initialize CRM cursor store, falling back to in-memory: %v", err)
+			crmCursors = crmsource.NewInMemoryCursorStore()
+		} else {
+			crmCursors = store
+		}
+	} else {
+		crmCursors = crmsource.NewInMemoryCursorStore()
+	}
+
+	if instanceURL := os.Getenv("SALESFORCE_INSTANCE_URL"); instanceURL != "" {
+		token := os.Getenv("SALESFORCE_ACCESS_TOKEN")
+		crmSources = append(crmSources, crmsource.NewSalesforceSource(instanceURL, token, crmMetrics))
+	}
+	if apiKey := os.Getenv("HUBSPOT_API_KEY"); apiKey != "" {
+		crmSources = append(crmSources, crmsource.NewHubSpotSource(apiKey, crmMetrics))
+	}
+	if len(crmSources) == 0 {
+		log.Println("ℹ️ No CRM source credentials configured, simulateCRMPolling will use its sample customers")
+	}
+}
+
+// pollCRMSources incrementally syncs every configured CRM source since its
+// last persisted cursor, converting each crm.Customer into the Customer
+// shape generateRetentionTrigger expects.
+func pollCRMSources(ctx context.Context) []Customer {
+	var customers []Customer
+	for _, source := range crmSources {
+		since, err := crmCursors.Get(ctx, source.Name())
+		if err != nil {
+			log.Printf("⚠️ CRM cursor read failed for %s: %v", source.Name(), err)
+			continue
+		}
+
+		fetched, cursor, err := source.Poll(ctx, since)
+		if err != nil {
+			log.Printf("⚠️ CRM poll failed for %s: %v", source.Name(), err)
+			continue
+		}
+		for _, c := range fetched {
+			customers = append(customers, crmCustomerToCustomer(c))
+		}
+		if err := crmCursors.Set(ctx, source.Name(), cursor); err != nil {
+			log.Printf("⚠️ CRM cursor save failed for %s: %v", source.Name(), err)
+		}
+	}
+	return customers
+}
+
+// crmCustomerToCustomer adapts crm.Customer (the CRM-integration-layer
+// representation, shared across Salesforce/HubSpot/Shopify) to this
+// package's own Customer, the shape generateRetentionTrigger operates on.
+func crmCustomerToCustomer(c crm.Customer) Customer {
+	return Customer{
+		ID:              c.ID,
+		Email:           c.Email,
+		LastPurchase:    c.LastPurchase,
+		TotalSpend:      c.TotalSpend,
+		PurchaseCount:   c.PurchaseCount,
+		EngagementScore: c.EngagementScore,
+		LTV:             c.LTV,
+		ChurnRisk:       c.ChurnRisk,
+		LastEngagement:  c.LastEngagement,
+	}
+}
+
+// webhookHandler mounts on /webhook/ and feeds real-time CRM pushes
+// straight into generateRetentionTrigger, the same pipeline pollCRMSources
+// feeds from the poll side.
+func webhookHandler() *crmsource.WebhookHandler {
+	secrets := make(map[string]string)
+	if secret := os.Getenv("HUBSPOT_WEBHOOK_SECRET"); secret != "" {
+		secrets["hubspot"] = secret
+	}
+	if secret := os.Getenv("SALESFORCE_WEBHOOK_SECRET"); secret != "" {
+		secrets["salesforce"] = secret
+	}
+	return crmsource.NewWebhookHandler(secrets, func(provider string, c crm.Customer) {
+		log.Printf("📥 WEBHOOK: %s pushed customer %s", provider, c.ID)
+		generateRetentionTrigger(context.Background(), crmCustomerToCustomer(c))
+	})
+}
+
+// crmMetricsHandler serves crmMetrics in Prometheus text format.
+func crmMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write([]byte(crmMetrics.Render()))
+}
+
+// initRetentionCoordinator builds retentionCoordinator against the same
+// Redis instance SyncShield's budget limiter uses, resolved the same way:
+// REDIS_ADDR, defaulting to localhost:6379.
+func initRetentionCoordinator() {
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "localhost:6379"
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+	retentionCoordinator = coordinator.NewRetentionCoordinator(rdb)
+}
+
+// Fetch LTV prediction for a customer. Once ltvBreaker is open - the LTV
+// service itself failing or consistently slow - this skips straight to
+// ltvFallback's median rather than attempting a call likely to fail again.
+func fetchLTV(ctx context.Context, customerID string, spend, score float64) (float64, string) {
+	if !ltvBreaker.CanExecute() {
+		return ltvFallback.Median(customerID, spend, score), "heuristic: LTV circuit breaker open"
+	}
+
+	start := time.Now()
+	ltv, explanation, err := ltvConn.Predict(ctx, customerID, spend, score)
+	latency := time.Since(start)
+
+	if err != nil || latency > circuitLatencySLA {
+		if err != nil {
+			log.Printf("⚠️ LTV prediction failed: %v", err)
+		}
+		ltvBreaker.RecordFailure(latency)
+		if ltvBreaker.IsFallbackMode() {
+			return ltvFallback.Median(customerID, spend, score), "heuristic: LTV circuit breaker open"
+		}
+		return ltv, explanation
+	}
+
+	ltvBreaker.RecordSuccess(latency)
+	ltvFallback.Record(customerID, ltv)
+	return ltv, explanation
+}
+
+// Assess churn risk based on recency and engagement
+func assessChurnRisk(customer Customer) string {
+	daysSinceLastPurchase := time.Since(customer.LastPurchase).Hours() / 24
+	daysSinceLastEngagement := time.Since(customer.LastEngagement).Hours() / 24
+
+	if daysSinceLastPurchase > 90 || daysSinceLastEngagement > 60 {
+		return "high"
+	} else if daysSinceLastPurchase > 45 || daysSinceLastEngagement > 30 {
+		return "medium"
+	}
+	return "low"
+}
+
+// Generate retention trigger based on customer profile. ctx carries the
+// observability.Span this call's LTV prediction, SyncShield guard, and
+// audit write all share, so they resolve to one trace end-to-end.
+func generateRetentionTrigger(ctx context.Context, customer Customer) *RetentionTrigger {
+	span := observability.StartSpan("generateRetentionTrigger")
+	ctx = observability.ContextWithSpan(ctx, span)
+
+	ltv, explanation := fetchLTV(ctx, customer.ID, customer.TotalSpend, customer.EngagementScore)
+	customer.LTV = ltv
+	customer.ChurnRisk = assessChurnRisk(customer)
+
+	log.Printf("📊 Customer %s | LTV: %.2f | Churn Risk: %s", customer.ID, ltv, customer.ChurnRisk)
+	log.Printf("   🧠 AI: %s", explanation)
+
+	trigger := &RetentionTrigger{
+		CustomerID:   customer.ID,
+		ExecutedAt:   time.Now(),
+		PredictedLTV: ltv,
+	}
+
+	// Decision logic based on churn risk and LTV
+	switch customer.ChurnRisk {
+	case "high":
+		if ltv > 200 {
+			// High-value at-risk customer
+			trigger.TriggerType = "churn_risk_high_value"
+			trigger.Action = "offer"
+			trigger.DiscountPct = 20.0
+			trigger.Message = fmt.Sprintf("We miss you! Here's 20%% off your next order (LTV: $%.0f)", ltv)
+			log.Printf("🎯 RETENTION TRIGGER: High-value customer (LTV $%.0f) - 20%% discount offer", ltv)
+		} else {
+			// Standard at-risk customer
+			trigger.TriggerType = "churn_risk_standard"
+			trigger.Action = "email"
+			trigger.DiscountPct = 10.0
+			trigger.Message = "We haven't seen you in a while. Here's 10% off to welcome you back!"
+			log.Printf("📧 RETENTION TRIGGER: At-risk customer (LTV $%.0f) - 10%% re-engagement email", ltv)
+		}
+
+	case "medium":
+		if ltv > 300 {
+			// Premium customer check-in
+			trigger.TriggerType = "high_value_check"
+			trigger.Action = "survey"
+			trigger.Message = "How's everything going? We value your feedback."
+			log.Printf("⭐ LOYALTY TRIGGER: Premium customer (LTV $%.0f) - feedback survey", ltv)
+		} else {
+			// Moderate engagement nudge
+			trigger.TriggerType = "dormant"
+			trigger.Action = "email"
+			trigger.Message = "Check out what's new! Exclusive updates just for you."
+			log.Printf("📬 ENGAGEMENT TRIGGER: Moderate customer (LTV $%.0f) - content update", ltv)
+		}
+
+	case "low":
+		if ltv > 500 {
+			// VIP customer appreciation
+			trigger.TriggerType = "high_value_check"
+			trigger.Action = "offer"
+			trigger.DiscountPct = 15.0
+			trigger.Message = fmt.Sprintf("Thank you for being a VIP! Exclusive 15%% off (LTV: $%.0f)", ltv)
+			log.Printf("👑 VIP TRIGGER: High-value loyal customer (LTV $%.0f) - exclusive offer", ltv)
+		}
+		// Active low-risk customers don't need aggressive triggers
+	}
+
+	// Compliance check with SyncShield before executing trigger
+	if trigger.TriggerType != "" {
+		if retentionCoordinator != nil && !retentionCoordinator.ShouldFire(ctx, customer.ID, trigger.TriggerType) {
+			log.Printf("🔁 DEDUP: Trigger %s for %s already fired recently, skipping", trigger.TriggerType, customer.ID)
+			return nil
+		}
+		if guardTrigger(ctx, customer.ID, trigger) {
+			logAudit(ctx, trigger)
+		} else {
+			log.Printf("🛡️ COMPLIANCE: Trigger for %s suppressed by SyncShield (trace=%s)", customer.ID, span)
+			return nil
+		}
+	}
+
+	return trigger
+}
+
+// guardTrigger asks SyncShield whether trigger may proceed, wrapped in
+// shieldBreaker the same way fetchLTV wraps ltvConn.Predict in ltvBreaker.
+// Once the breaker is open it skips the call entirely and returns
+// shieldDenyOnOpen rather than hitting a dependency already known to be
+// failing.
+func guardTrigger(ctx context.Context, customerID string, trigger *RetentionTrigger) bool {
+	if !shieldBreaker.CanExecute() {
+		return !shieldDenyOnOpen
+	}
+
+	start := time.Now()
+	allowed, err := shieldClient.Guard(ctx, customerID, trigger.PredictedLTV, trigger.TriggerType)
+	latency := time.Since(start)
+
+	if err != nil || latency > circuitLatencySLA {
+		if err != nil {
+			log.Printf("⚠️ SyncShield guard error for %s: %v", customerID, err)
+		}
+		shieldBreaker.RecordFailure(latency)
+		if shieldBreaker.IsFallbackMode() {
+			return !shieldDenyOnOpen
+		}
+		return allowed
+	}
+
+	shieldBreaker.RecordSuccess(latency)
+	return allowed
+}
+
+// Log retention trigger to audit trail. ctx's span is logged alongside the
+// row so the audit sink can be cross-referenced against the same trace the
+// LTV prediction and SyncShield guard ran under.
+func logAudit(ctx context.Context, trigger *RetentionTrigger) {
+	if auditSink == nil {
+		return
+	}
+	span := observability.SpanFromContext(ctx, "logAudit")
+	log.Printf("📝 AUDIT: %s | %s | trace=%s", trigger.CustomerID, trigger.TriggerType, span)
+	if err := auditSink.Write(ctx, auditsink.Record{
+		CustomerID:   trigger.CustomerID,
+		TriggerType:  trigger.TriggerType,
+		Action:       trigger.Action,
+		Message:      trigger.Message,
+		DiscountPct:  trigger.DiscountPct,
+		PredictedLTV: trigger.PredictedLTV,
+		ExecutedAt:   trigger.ExecutedAt,
+	}); err != nil {
+		log.Printf("⚠️ audit sink write failed for %s: %v", trigger.CustomerID, err)
+	}
+}
+
+// initAuditSink selects the audit sink kind via the AUDIT_SINK_KIND env var
+// (csv, postgres, or kafka; csv if unset) and wraps it in a BufferedSink so
+// a slow or down destination can't block generateRetentionTrigger.
+func initAuditSink() {
+	underlying, err := auditsink.NewSink(os.Getenv("AUDIT_SINK_KIND"))
+	if err != nil {
+		log.Printf("⚠️ Could not initialize audit sink: %v", err)
+		return
+	}
+	auditSink = auditsink.NewBufferedSink(underlying, 0)
+	log.Println("✅ Audit sink initialized")
+}
+
+// HTTP health endpoint
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	status := map[string]interface{}{
+		"status":         "healthy",
+		"service":        "SyncEngage™ Retention Agent",
+		"ltv_client":     ltvConn.Healthy(),
+		"ltv_breaker":    breakerStatus(ltvBreaker),
+		"shield_breaker": breakerStatus(shieldBreaker),
+		"audit_sink":     auditSinkStatus(),
+		"coordinator":    coordinatorStatus(),
+		"timestamp":      time.Now().Format(time.RFC3339),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// breakerStatus renders a shield.CircuitBreaker's GetStats() as JSON-friendly
+// fields for healthHandler.
+func breakerStatus(cb *shield.CircuitBreaker) map[string]interface{} {
+	stats := cb.GetStats()
+	return map[string]interface{}{
+		"state":               stats.State.String(),
+		"failure_count":       stats.FailureCount,
+		"total_requests":      stats.TotalRequests,
+		"successful_requests": stats.SuccessfulRequests,
+		"failed_requests":     stats.FailedRequests,
+	}
+}
+
+// auditSinkStatus renders auditSink's pending/dropped/flushed counters for
+// healthHandler, or nil if the sink failed to initialize.
+func auditSinkStatus() map[string]interface{} {
+	if auditSink == nil {
+		return nil
+	}
+	stats := auditSink.Stats()
+	return map[string]interface{}{
+		"pending": stats.Pending,
+		"dropped": stats.Dropped,
+		"flushed": stats.Flushed,
+	}
+}
+
+// coordinatorStatus renders retentionCoordinator's mode/leadership state for
+// healthHandler, or nil if it hasn't been initialized yet.
+func coordinatorStatus() map[string]interface{} {
+	if retentionCoordinator == nil {
+		return nil
+	}
+	return retentionCoordinator.Status()
+}
+
+// HTTP API endpoint to trigger retention check for a customer
+func triggerRetentionHandler(w http.ResponseWriter, r *http.Request) {
+	var customer Customer
+	if err := json.NewDecoder(r.Body).Decode(&customer); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	trigger := generateRetentionTrigger(r.Context(), customer)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trigger)
+}
+
+// Simulate CRM data polling, or - if crmSources has real Salesforce/HubSpot
+// credentials configured - poll them incrementally instead of the sample
+// data below.
+func simulateCRMPolling() {
+	log.Println("🔄 Starting CRM polling simulation...")
+
+	// Sample customer data, used when no CRM source credentials are configured
+	sampleCustomers := []Customer{
+		{
+			ID:              "cust_001",
+			Email:           "alice@example.com",
+			LastPurchase:    time.Now().AddDate(0, 0, -95), // 95 days ago
+			TotalSpend:      450.0,
+			PurchaseCount:   12,
+			EngagementScore: 8.5,
+			LastEngagement:  time.Now().AddDate(0, 0, -70),
+		},
+		{
+			ID:              "cust_002",
+			Email:           "bob@example.com",
+			LastPurchase:    time.Now().AddDate(0, 0, -15), // 15 days ago
+			TotalSpend:      850.0,
+			PurchaseCount:   28,
+			EngagementScore: 9.2,
+			LastEngagement:  time.Now().AddDate(0, 0, -3),
+		},
+		{
+			ID:              "cust_003",
+			Email:           "charlie@example.com",
+			LastPurchase:    time.Now().AddDate(0, 0, -50), // 50 days ago
+			TotalSpend:      120.0,
+			PurchaseCount:   4,
+			EngagementScore: 6.0,
+			LastEngagement:  time.Now().AddDate(0, 0, -35),
+		},
+		{
+			ID:              "cust_004",
+			Email:           "diana@example.com",
+			LastPurchase:    time.Now().AddDate(0, 0, -5), // 5 days ago
+			TotalSpend:      1200.0,
+			PurchaseCount:   45,
+			EngagementScore: 9.8,
+			LastEngagement:  time.Now().AddDate(0, 0, -1),
+		},
+	}
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		log.Println("\n═══════════════════════════════════════════════════")
+		log.Println("🔍 CRM Sync Cycle: Analyzing customer retention...")
+		log.Println("═══════════════════════════════════════════════════")
+
+		customers := sampleCustomers
+		if len(crmSources) > 0 {
+			customers = pollCRMSources(context.Background())
+		}
+
+		for _, customer := range customers {
+			if retentionCoordinator != nil && !retentionCoordinator.Owns(customer.ID) {
+				continue
+			}
+			generateRetentionTrigger(context.Background(), customer)
+		}
+
+		log.Println("═══════════════════════════════════════════════════")
+	}
+}
+
+func main() {
+	log.Println("🚀 KIKI SyncEngage™ - Post-Acquisition Loyalty Agent")
+	log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	initAuditSink()
+	defer func() {
+		if auditSink != nil {
+			auditSink.Close()
+		}
+	}()
+
+	initLTVService(context.Background())
+
+	initRetentionCoordinator()
+	retentionCoordinator.Start(context.Background())
+
+	initCRMSources()
+
+	// Start HTTP server
+	http.HandleFunc("/health", healthHandler)
+	http.HandleFunc("/trigger", triggerRetentionHandler)
+	http.HandleFunc("/metrics", crmMetricsHandler)
+	http.Handle("/webhook/", webhookHandler())
+
+	go func() {
+		log.Println("🌐 HTTP API starting on :8083")
+		log.Println("   Health: http://localhost:8083/health")
+		log.Println("   Trigger: POST http://localhost:8083/trigger")
+		if err := http.ListenAndServe(":8083", nil); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	// Start CRM polling simulation
+	simulateCRMPolling()
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+	"github.com/user/kiki-agent/cmd/syncflow/reconcile"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// budgetManagerAware is implemented by every *_smart connector, mirroring
+// auction.CircuitBreakerAware - it's what lets serveRewind reach past the
+// connectors.PlatformConnector interface to the live BudgetManager the
+// reconcile CLI's rewind subcommand needs to correct, instead of adjusting
+// a throwaway copy that has no effect on this process's actual spend
+// tracking.
+type budgetManagerAware interface {
+	GetBudgetManager() *shield.BudgetManager
+}
+
+// serveForceReconcile handles POST /admin/reconcile/force?request_id=...,
+// calling worker.ForceReconcile and returning the resulting
+// reconcile.AccuracyRecord as JSON. This stands in for the SyncFlowService
+// admin RPC described in the reconcile package's design - no .proto for
+// SyncFlowService exists in this repo yet (see predict.Client's api/pb
+// gap), so a plain HTTP handler is wired up the same way
+// connectors.HeuristicFallbackEngine.ServeStats is.
+func serveForceReconcile(worker *reconcile.Worker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		requestID := r.URL.Query().Get("request_id")
+		if requestID == "" {
+			http.Error(w, "request_id is required", http.StatusBadRequest)
+			return
+		}
+
+		record, err := worker.ForceReconcile(r.Context(), requestID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(record)
+	}
+}
+
+// rewindResult is serveRewind's JSON response: the diverged ledger entries
+// reconcile.Rewind found plus the live BudgetManager's corrected spend, so
+// the CLI can report exactly what changed in the process it just reached.
+type rewindResult struct {
+	Diverged     []connectors.LedgerEntry `json:"diverged"`
+	CurrentSpend float64                  `json:"current_spend"`
+}
+
+// serveRewind handles POST /admin/reconcile/rewind?platform=...&after=...,
+// rolling back conn's live BudgetManager (not a throwaway copy the caller
+// can't otherwise reach) and requeuing nothing itself - the caller is
+// still responsible for requeuing the returned diverged entries, same as
+// reconcile_example.go's rewind subcommand already does for the Redis
+// half of recovery.
+func serveRewind(conn connectors.PlatformConnector, ledger connectors.SpendLedger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		bma, ok := conn.(budgetManagerAware)
+		if !ok {
+			http.Error(w, "this connector has no BudgetManager to rewind", http.StatusNotImplemented)
+			return
+		}
+		platform := r.URL.Query().Get("platform")
+		after := r.URL.Query().Get("after")
+		if platform == "" || after == "" {
+			http.Error(w, "platform and after are required", http.StatusBadRequest)
+			return
+		}
+
+		bm := bma.GetBudgetManager()
+		diverged, err := connectors.Rewind(r.Context(), ledger, bm, platform, after)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rewindResult{Diverged: diverged, CurrentSpend: bm.GetCurrentSpend()})
+	}
+}
@@ -0,0 +1,47 @@
+// Package alerts routes operator-facing notifications - anomaly
+// detections, budget vetoes, circuit-breaker fallbacks, brain-offline
+// degradations - to one or more Messenger sinks, the same
+// out-of-the-box-adapter pattern connectors/analytics uses for Recorder.
+package alerts
+
+import "time"
+
+// Severity classifies how urgently a Message needs a human to look at it.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Message is one alert-worthy event.
+type Message struct {
+	// Fingerprint identifies this alert's "kind" for Dispatcher's cooldown
+	// dedup - e.g. "linkedin:budget_veto" or "fetchLTV:brain_offline" -
+	// not a per-occurrence ID.
+	Fingerprint string
+	Severity    Severity
+	Title       string
+	Body        string
+	// Source names what raised this alert (e.g. "syncflow.linkedin",
+	// "syncflow.fetchLTV"), surfaced in every Messenger implementation.
+	Source string
+	// Time defaults to time.Now() in Dispatcher.Push if left zero.
+	Time time.Time
+}
+
+// Messenger is one notification sink a Dispatcher can fan a Message out to.
+type Messenger interface {
+	// Name identifies this sink in logs and error output.
+	Name() string
+	// Push delivers msg. Implementations are synchronous unless documented
+	// otherwise, so a non-nil error means msg was not delivered.
+	Push(msg Message) error
+	// Flush blocks until anything Push has buffered has actually been
+	// sent. A no-op for Messengers that deliver synchronously.
+	Flush() error
+	// Close flushes and releases any resources (HTTP clients, SMTP
+	// connections) this Messenger holds.
+	Close() error
+}
@@ -0,0 +1,86 @@
+package alerts
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultCooldown is how long Dispatcher suppresses repeats of the same
+// Fingerprint when the caller doesn't specify one.
+const defaultCooldown = 5 * time.Minute
+
+// Dispatcher fans a Message out to every configured Messenger, suppressing
+// repeats of the same Fingerprint within Cooldown so a flapping brain or a
+// connector stuck in a budget-veto loop doesn't page on-call every couple
+// of seconds.
+type Dispatcher struct {
+	Messengers []Messenger
+	Cooldown   time.Duration
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+// NewDispatcher creates a Dispatcher fanning out to messengers, suppressing
+// repeat fingerprints within cooldown (defaultCooldown if cooldown <= 0).
+func NewDispatcher(cooldown time.Duration, messengers ...Messenger) *Dispatcher {
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+	return &Dispatcher{
+		Messengers: messengers,
+		Cooldown:   cooldown,
+		lastSent:   make(map[string]time.Time),
+	}
+}
+
+// Push delivers msg to every Messenger unless its Fingerprint already fired
+// within the last Cooldown, in which case it's silently suppressed. A nil
+// Dispatcher and an empty Messengers slice are both no-ops, mirroring
+// connectors.Recorder's fireRecorders convention, so call sites can hold an
+// *alerts.Dispatcher field that's simply never wired up.
+func (d *Dispatcher) Push(msg Message) {
+	if d == nil || len(d.Messengers) == 0 {
+		return
+	}
+	if msg.Time.IsZero() {
+		msg.Time = time.Now()
+	}
+
+	d.mu.Lock()
+	if last, ok := d.lastSent[msg.Fingerprint]; ok && msg.Time.Sub(last) < d.Cooldown {
+		d.mu.Unlock()
+		return
+	}
+	d.lastSent[msg.Fingerprint] = msg.Time
+	d.mu.Unlock()
+
+	for _, m := range d.Messengers {
+		if m == nil {
+			continue
+		}
+		if err := m.Push(msg); err != nil {
+			fmt.Printf("⚠️  alerts.Dispatcher: %s failed to push %q: %v\n", m.Name(), msg.Fingerprint, err)
+		}
+	}
+}
+
+// Close closes every Messenger, continuing past individual errors so one
+// sink failing to close doesn't block the others, and returns the first
+// error encountered (if any).
+func (d *Dispatcher) Close() error {
+	if d == nil {
+		return nil
+	}
+	var firstErr error
+	for _, m := range d.Messengers {
+		if m == nil {
+			continue
+		}
+		if err := m.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,117 @@
+package alerts
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// recordingMessenger records every Message it receives, and can be made to
+// fail Push/Close on demand.
+type recordingMessenger struct {
+	name     string
+	pushed   []Message
+	pushErr  error
+	closeErr error
+	closed   bool
+}
+
+func (r *recordingMessenger) Name() string { return r.name }
+
+func (r *recordingMessenger) Push(msg Message) error {
+	r.pushed = append(r.pushed, msg)
+	return r.pushErr
+}
+
+func (r *recordingMessenger) Flush() error { return nil }
+
+func (r *recordingMessenger) Close() error {
+	r.closed = true
+	return r.closeErr
+}
+
+func TestDispatcher_PushFansOutToEveryMessenger(t *testing.T) {
+	a := &recordingMessenger{name: "a"}
+	b := &recordingMessenger{name: "b"}
+	d := NewDispatcher(time.Minute, a, b)
+
+	d.Push(Message{Fingerprint: "fp-1", Title: "t"})
+
+	if len(a.pushed) != 1 || len(b.pushed) != 1 {
+		t.Fatalf("expected both messengers to receive the message, got a=%d b=%d", len(a.pushed), len(b.pushed))
+	}
+}
+
+func TestDispatcher_PushSuppressesRepeatWithinCooldown(t *testing.T) {
+	a := &recordingMessenger{name: "a"}
+	d := NewDispatcher(time.Minute, a)
+	now := time.Now()
+
+	d.Push(Message{Fingerprint: "fp-1", Time: now})
+	d.Push(Message{Fingerprint: "fp-1", Time: now.Add(time.Second)})
+
+	if len(a.pushed) != 1 {
+		t.Fatalf("expected the second push within cooldown to be suppressed, got %d pushes", len(a.pushed))
+	}
+}
+
+func TestDispatcher_PushAllowsRepeatAfterCooldown(t *testing.T) {
+	a := &recordingMessenger{name: "a"}
+	d := NewDispatcher(time.Minute, a)
+	now := time.Now()
+
+	d.Push(Message{Fingerprint: "fp-1", Time: now})
+	d.Push(Message{Fingerprint: "fp-1", Time: now.Add(2 * time.Minute)})
+
+	if len(a.pushed) != 2 {
+		t.Fatalf("expected both pushes to go through once cooldown has elapsed, got %d", len(a.pushed))
+	}
+}
+
+func TestDispatcher_PushDefaultsCooldownWhenZero(t *testing.T) {
+	d := NewDispatcher(0)
+	if d.Cooldown != defaultCooldown {
+		t.Fatalf("expected defaultCooldown, got %v", d.Cooldown)
+	}
+}
+
+func TestDispatcher_PushIsNoOpOnNilDispatcherOrEmptyMessengers(t *testing.T) {
+	var nilDispatcher *Dispatcher
+	nilDispatcher.Push(Message{Fingerprint: "fp-1"}) // must not panic
+
+	empty := NewDispatcher(time.Minute)
+	empty.Push(Message{Fingerprint: "fp-1"}) // must not panic
+}
+
+func TestDispatcher_PushToleratesFailingMessenger(t *testing.T) {
+	failing := &recordingMessenger{name: "failing", pushErr: errors.New("down")}
+	ok := &recordingMessenger{name: "ok"}
+	d := NewDispatcher(time.Minute, failing, ok)
+
+	d.Push(Message{Fingerprint: "fp-1"})
+
+	if len(ok.pushed) != 1 {
+		t.Fatalf("expected the other messenger to still receive the push, got %d", len(ok.pushed))
+	}
+}
+
+func TestDispatcher_CloseClosesEveryMessengerAndReturnsFirstError(t *testing.T) {
+	first := &recordingMessenger{name: "first", closeErr: errors.New("boom")}
+	second := &recordingMessenger{name: "second"}
+	d := NewDispatcher(time.Minute, first, second)
+
+	err := d.Close()
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("expected the first Close error to be returned, got %v", err)
+	}
+	if !first.closed || !second.closed {
+		t.Fatalf("expected every messenger to be closed, got first=%v second=%v", first.closed, second.closed)
+	}
+}
+
+func TestDispatcher_CloseIsNoOpOnNilDispatcher(t *testing.T) {
+	var d *Dispatcher
+	if err := d.Close(); err != nil {
+		t.Fatalf("expected nil Dispatcher.Close to be a no-op, got %v", err)
+	}
+}
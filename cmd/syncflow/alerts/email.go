@@ -0,0 +1,55 @@
+package alerts
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// EmailMessenger sends each Message as a plaintext email via SMTP - the
+// simplest Messenger to stand up since net/smtp needs no external
+// dependency.
+type EmailMessenger struct {
+	Host string // e.g. "smtp.sendgrid.net:587"
+	From string
+	To   []string
+
+	auth smtp.Auth
+}
+
+// NewEmailMessenger creates an EmailMessenger authenticating to host (an
+// "addr:port" pair net/smtp dials directly) with username/password via
+// SMTP PLAIN auth, sending every Message as from to each address in to.
+func NewEmailMessenger(host, username, password, from string, to []string) *EmailMessenger {
+	hostOnly := host
+	if i := strings.IndexByte(host, ':'); i >= 0 {
+		hostOnly = host[:i]
+	}
+	return &EmailMessenger{
+		Host: host,
+		From: from,
+		To:   to,
+		auth: smtp.PlainAuth("", username, password, hostOnly),
+	}
+}
+
+// Name implements Messenger.
+func (e *EmailMessenger) Name() string { return "email" }
+
+// Push implements Messenger, sending msg immediately - SMTP round trips are
+// fast enough that HTTPRecorder-style batching isn't worth the added
+// latency-to-page for an alert.
+func (e *EmailMessenger) Push(msg Message) error {
+	body := fmt.Sprintf("Subject: [%s] %s\r\n\r\n%s\r\n\nSource: %s\nTime: %s\n",
+		strings.ToUpper(string(msg.Severity)), msg.Title, msg.Body, msg.Source, msg.Time.Format(time.RFC3339))
+	return smtp.SendMail(e.Host, e.auth, e.From, e.To, []byte(body))
+}
+
+// Flush implements Messenger; Push is synchronous so there's nothing
+// buffered to flush.
+func (e *EmailMessenger) Flush() error { return nil }
+
+// Close implements Messenger; net/smtp.SendMail dials per call, so there's
+// no persistent connection to release.
+func (e *EmailMessenger) Close() error { return nil }
@@ -0,0 +1,19 @@
+package alerts
+
+// NullMessenger discards every Message. It's the default-safe sink for
+// environments and tests that shouldn't generate real email, webhook, or
+// PagerDuty traffic, the same role a nil Recorders entry plays for
+// connectors/analytics.
+type NullMessenger struct{}
+
+// Name implements Messenger.
+func (NullMessenger) Name() string { return "null" }
+
+// Push implements Messenger.
+func (NullMessenger) Push(Message) error { return nil }
+
+// Flush implements Messenger.
+func (NullMessenger) Flush() error { return nil }
+
+// Close implements Messenger.
+func (NullMessenger) Close() error { return nil }
@@ -0,0 +1,19 @@
+package alerts
+
+import "testing"
+
+func TestNullMessenger_DiscardsEverything(t *testing.T) {
+	var m NullMessenger
+	if m.Name() != "null" {
+		t.Fatalf("expected Name=\"null\", got %q", m.Name())
+	}
+	if err := m.Push(Message{Fingerprint: "fp-1"}); err != nil {
+		t.Fatalf("expected Push to no-op, got %v", err)
+	}
+	if err := m.Flush(); err != nil {
+		t.Fatalf("expected Flush to no-op, got %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("expected Close to no-op, got %v", err)
+	}
+}
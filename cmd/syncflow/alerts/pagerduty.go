@@ -0,0 +1,93 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyMessenger triggers a PagerDuty Events API v2 incident for each
+// Message, routed by RoutingKey (an Events API v2 integration key, not an
+// account-wide API token).
+type PagerDutyMessenger struct {
+	RoutingKey string
+
+	httpClient *http.Client
+}
+
+// NewPagerDutyMessenger creates a PagerDutyMessenger using routingKey.
+func NewPagerDutyMessenger(routingKey string) *PagerDutyMessenger {
+	return &PagerDutyMessenger{RoutingKey: routingKey, httpClient: &http.Client{Timeout: webhookDefaultTimeout}}
+}
+
+// Name implements Messenger.
+func (p *PagerDutyMessenger) Name() string { return "pagerduty" }
+
+type pagerDutyPayload struct {
+	Summary   string `json:"summary"`
+	Source    string `json:"source"`
+	Severity  string `json:"severity"`
+	Timestamp string `json:"timestamp"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key"`
+	Payload     pagerDutyPayload `json:"payload"`
+}
+
+// pagerDutySeverity maps a Message.Severity onto one of the four values the
+// Events API v2 payload.severity field accepts.
+func pagerDutySeverity(s Severity) string {
+	switch s {
+	case SeverityCritical:
+		return "critical"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// Push implements Messenger, triggering (not resolving) an incident keyed by
+// msg.Fingerprint so PagerDuty's own dedup can coalesce repeats the
+// Dispatcher's cooldown lets through.
+func (p *PagerDutyMessenger) Push(msg Message) error {
+	event := pagerDutyEvent{
+		RoutingKey:  p.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    msg.Fingerprint,
+		Payload: pagerDutyPayload{
+			Summary:   fmt.Sprintf("%s: %s", msg.Title, msg.Body),
+			Source:    msg.Source,
+			Severity:  pagerDutySeverity(msg.Severity),
+			Timestamp: msg.Time.Format(time.RFC3339),
+		},
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal PagerDuty event: %w", err)
+	}
+
+	resp, err := p.httpClient.Post(pagerDutyEventsURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("PagerDuty Events API returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Flush implements Messenger; Push is synchronous so there's nothing
+// buffered to flush.
+func (p *PagerDutyMessenger) Flush() error { return nil }
+
+// Close implements Messenger; http.Client needs no explicit teardown.
+func (p *PagerDutyMessenger) Close() error { return nil }
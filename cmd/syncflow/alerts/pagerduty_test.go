@@ -0,0 +1,23 @@
+package alerts
+
+import "testing"
+
+func TestPagerDutySeverity(t *testing.T) {
+	cases := map[Severity]string{
+		SeverityCritical:    "critical",
+		SeverityWarning:     "warning",
+		SeverityInfo:        "info",
+		Severity("made-up"): "info",
+	}
+	for in, want := range cases {
+		if got := pagerDutySeverity(in); got != want {
+			t.Errorf("pagerDutySeverity(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPagerDutyMessenger_Name(t *testing.T) {
+	if (&PagerDutyMessenger{}).Name() != "pagerduty" {
+		t.Fatal("expected Name to return \"pagerduty\"")
+	}
+}
@@ -0,0 +1,60 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const webhookDefaultTimeout = 5 * time.Second
+
+// WebhookMessenger posts each Message as a {"text": "..."} payload to URL -
+// the format both Slack's and Discord's incoming webhooks accept, so one
+// Messenger covers either without platform-specific branching.
+type WebhookMessenger struct {
+	URL string
+
+	httpClient *http.Client
+}
+
+// NewWebhookMessenger creates a WebhookMessenger posting to url.
+func NewWebhookMessenger(url string) *WebhookMessenger {
+	return &WebhookMessenger{URL: url, httpClient: &http.Client{Timeout: webhookDefaultTimeout}}
+}
+
+// Name implements Messenger.
+func (w *WebhookMessenger) Name() string { return "webhook" }
+
+// webhookPayload is the minimal shape both Slack and Discord incoming
+// webhooks accept.
+type webhookPayload struct {
+	Text string `json:"text"`
+}
+
+// Push implements Messenger.
+func (w *WebhookMessenger) Push(msg Message) error {
+	text := fmt.Sprintf("*[%s] %s*\n%s\n_source: %s_", msg.Severity, msg.Title, msg.Body, msg.Source)
+	body, err := json.Marshal(webhookPayload{Text: text})
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	resp, err := w.httpClient.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Flush implements Messenger; Push is synchronous so there's nothing
+// buffered to flush.
+func (w *WebhookMessenger) Flush() error { return nil }
+
+// Close implements Messenger; http.Client needs no explicit teardown.
+func (w *WebhookMessenger) Close() error { return nil }
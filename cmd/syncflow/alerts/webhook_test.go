@@ -0,0 +1,46 @@
+package alerts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookMessenger_PushPostsTextPayload(t *testing.T) {
+	var gotBody webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := NewWebhookMessenger(server.URL)
+	err := m.Push(Message{Severity: SeverityCritical, Title: "title", Body: "body", Source: "syncflow.test"})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if gotBody.Text == "" {
+		t.Fatal("expected a non-empty text payload")
+	}
+}
+
+func TestWebhookMessenger_PushErrorsOnNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	m := NewWebhookMessenger(server.URL)
+	if err := m.Push(Message{Fingerprint: "fp-1"}); err == nil {
+		t.Fatal("expected an error for a non-2xx response")
+	}
+}
+
+func TestWebhookMessenger_Name(t *testing.T) {
+	if (&WebhookMessenger{}).Name() != "webhook" {
+		t.Fatal("expected Name to return \"webhook\"")
+	}
+}
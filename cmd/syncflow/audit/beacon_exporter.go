@@ -0,0 +1,246 @@
+package audit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// beaconGzipThresholdBytes is the encoded-body size past which
+// sendWithRetry switches from a plain URL-encoded body to a gzipped one.
+const beaconGzipThresholdBytes = 2048
+
+const (
+	beaconDefaultMaxRetries  = 3
+	beaconDefaultBackoffBase = 200 * time.Millisecond
+	beaconDefaultTimeout     = 5 * time.Second
+)
+
+// BeaconDestination configures one external log-collection endpoint a
+// BeaconExporter ships AuditEntry beacons to. Destinations are independent:
+// each gets its own sampling, retry/backoff, and dry-run behavior, so a
+// single AuditEntry can be captured in full by one destination while being
+// sampled or skipped entirely by another.
+type BeaconDestination struct {
+	// Name identifies the destination in logs; not sent over the wire.
+	Name string
+	// URL is the HTTP endpoint beacons are POSTed to.
+	URL string
+	// Platform restricts this destination to entries from one platform
+	// (e.g. "amazon"). Empty matches every platform.
+	Platform string
+
+	// HMACSecret, when set, signs every beacon body with HMAC-SHA256 over
+	// body+timestamp, sent as X-Beacon-Signature/X-Beacon-Timestamp.
+	// Empty sends unsigned beacons.
+	HMACSecret string
+
+	// SampleRateByBidSource and SampleRateByBidStatus scale the odds an
+	// entry is exported, keyed by AuditEntry.BidSource/BidStatus (e.g.
+	// "ACCEPTED": 0.1 to sample 10% of accepted bids). A key's absence
+	// means "no reduction" (rate 1.0); the two maps are multiplied
+	// together, so an entry matching neither is always exported.
+	SampleRateByBidSource map[string]float64
+	SampleRateByBidStatus map[string]float64
+
+	// MaxRetries and BackoffBase control this destination's retry/backoff;
+	// zero values fall back to beaconDefaultMaxRetries/BackoffBase.
+	MaxRetries  int
+	BackoffBase time.Duration
+
+	// DryRun logs the outgoing URL and body size instead of sending.
+	DryRun bool
+}
+
+// BeaconExporter fans AuditEntry records out to one or more
+// BeaconDestinations as compact, URL-encoded beacons - a lightweight
+// alternative to standing up Kafka or a warehouse just to mirror audit
+// data into an existing log-collection pipeline.
+type BeaconExporter struct {
+	Destinations []BeaconDestination
+
+	httpClient *http.Client
+
+	rngMu sync.Mutex
+	rng   *rand.Rand
+}
+
+// NewBeaconExporter creates a BeaconExporter shipping to destinations.
+func NewBeaconExporter(destinations []BeaconDestination) *BeaconExporter {
+	return &BeaconExporter{
+		Destinations: destinations,
+		httpClient:   &http.Client{Timeout: beaconDefaultTimeout},
+		rng:          rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Export fans entry out to every matching, sampled-in destination. Each
+// send runs on its own goroutine so one slow or down destination's
+// retry/backoff can never hold up the others or the caller's hot path.
+func (b *BeaconExporter) Export(entry *AuditEntry) {
+	for i := range b.Destinations {
+		dest := &b.Destinations[i]
+		if dest.Platform != "" && dest.Platform != entry.Platform {
+			continue
+		}
+		if !b.shouldSample(dest, entry) {
+			continue
+		}
+		go b.sendWithRetry(dest, entry)
+	}
+}
+
+// shouldSample reports whether entry should be exported to dest, rolling a
+// random draw only when the combined sample rate is strictly between 0 and 1.
+func (b *BeaconExporter) shouldSample(dest *BeaconDestination, entry *AuditEntry) bool {
+	rate := 1.0
+	if r, ok := dest.SampleRateByBidSource[entry.BidSource]; ok {
+		rate *= r
+	}
+	if r, ok := dest.SampleRateByBidStatus[entry.BidStatus]; ok {
+		rate *= r
+	}
+	if rate >= 1.0 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+
+	b.rngMu.Lock()
+	roll := b.rng.Float64()
+	b.rngMu.Unlock()
+	return roll < rate
+}
+
+// sendWithRetry builds entry's beacon payload and POSTs it to dest,
+// retrying with exponential backoff on failure.
+func (b *BeaconExporter) sendWithRetry(dest *BeaconDestination, entry *AuditEntry) {
+	values, err := buildBeaconPayload(entry)
+	if err != nil {
+		fmt.Printf("⚠️  beacon export error (destination=%s): %v\n", dest.Name, err)
+		return
+	}
+	body := []byte(values.Encode())
+
+	gzipped := false
+	if len(body) > beaconGzipThresholdBytes {
+		if compressed, err := gzipBeaconBody(body); err == nil {
+			body = compressed
+			gzipped = true
+		}
+	}
+
+	if dest.DryRun {
+		fmt.Printf("🔎 beacon dry-run (destination=%s): would POST %d bytes to %s\n", dest.Name, len(body), dest.URL)
+		return
+	}
+
+	maxRetries := dest.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = beaconDefaultMaxRetries
+	}
+	backoff := dest.BackoffBase
+	if backoff <= 0 {
+		backoff = beaconDefaultBackoffBase
+	}
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff * time.Duration(uint(1)<<uint(attempt-1)))
+		}
+		if err := b.post(dest, body, gzipped); err != nil {
+			fmt.Printf("⚠️  beacon export attempt %d/%d failed (destination=%s): %v\n", attempt+1, maxRetries+1, dest.Name, err)
+			continue
+		}
+		return
+	}
+}
+
+// post sends one signed (if configured) attempt of body to dest.URL.
+func (b *BeaconExporter) post(dest *BeaconDestination, body []byte, gzipped bool) error {
+	req, err := http.NewRequest(http.MethodPost, dest.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	} else {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	if dest.HMACSecret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(dest.HMACSecret))
+		mac.Write(body)
+		mac.Write([]byte(timestamp))
+		req.Header.Set("X-Beacon-Timestamp", timestamp)
+		req.Header.Set("X-Beacon-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("beacon endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// buildBeaconPayload serializes entry into compact key=value pairs with
+// short field names, mirroring real-time ad-tech beacon conventions, with
+// the free-form Metadata blob base64-packed into a single field.
+func buildBeaconPayload(entry *AuditEntry) (url.Values, error) {
+	values := url.Values{}
+	values.Set("rid", entry.RequestID)
+	values.Set("ts", strconv.FormatInt(entry.Timestamp.Unix(), 10))
+	values.Set("cid", entry.CustomerID)
+	values.Set("plt", entry.Platform)
+	values.Set("src", entry.BidSource)
+	values.Set("st", entry.BidStatus)
+	values.Set("ltv", strconv.FormatFloat(entry.PredictedLTV, 'f', 2, 64))
+	values.Set("bid", strconv.FormatFloat(entry.BidAmount, 'f', 2, 64))
+	if entry.CampaignID != "" {
+		values.Set("cmp", entry.CampaignID)
+	}
+
+	if len(entry.Metadata) > 0 {
+		metaJSON, err := json.Marshal(entry.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal beacon metadata: %w", err)
+		}
+		values.Set("meta", base64.URLEncoding.EncodeToString(metaJSON))
+	}
+
+	return values, nil
+}
+
+// gzipBeaconBody compresses body for destinations whose encoded payload
+// exceeds beaconGzipThresholdBytes.
+func gzipBeaconBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
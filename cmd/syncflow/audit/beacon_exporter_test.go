@@ -0,0 +1,265 @@
+package audit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBeaconExporter_SendsURLEncodedPayload(t *testing.T) {
+	var mu sync.Mutex
+	var received url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		values, _ := url.ParseQuery(string(body))
+		mu.Lock()
+		received = values
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := NewBeaconExporter([]BeaconDestination{{Name: "primary", URL: server.URL}})
+	exporter.Export(&AuditEntry{RequestID: "req-1", Platform: "amazon", BidSource: "AI_PREDICTION", BidStatus: "ACCEPTED"})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		got := received
+		mu.Unlock()
+		if got != nil {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if received.Get("rid") != "req-1" || received.Get("plt") != "amazon" {
+		t.Fatalf("expected beacon payload to carry the entry's fields, got %v", received)
+	}
+}
+
+func TestBeaconExporter_PlatformFilterSkipsNonMatchingEntries(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := NewBeaconExporter([]BeaconDestination{{Name: "amazon-only", URL: server.URL, Platform: "amazon"}})
+	exporter.Export(&AuditEntry{RequestID: "req-1", Platform: "google"})
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 0 {
+		t.Fatalf("expected the platform filter to skip a non-matching entry, got %d calls", calls)
+	}
+}
+
+func TestBeaconExporter_SamplingSkipsWhenRateIsZero(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := NewBeaconExporter([]BeaconDestination{{
+		Name:                  "sampled",
+		URL:                   server.URL,
+		SampleRateByBidStatus: map[string]float64{"ACCEPTED": 0},
+	}})
+	exporter.Export(&AuditEntry{RequestID: "req-1", BidStatus: "ACCEPTED"})
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 0 {
+		t.Fatalf("expected a zero sample rate to always skip export, got %d calls", calls)
+	}
+}
+
+func TestBeaconExporter_EntryMatchingNoSampleKeyAlwaysExports(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := NewBeaconExporter([]BeaconDestination{{
+		Name:                  "sampled",
+		URL:                   server.URL,
+		SampleRateByBidStatus: map[string]float64{"ACCEPTED": 0},
+	}})
+	// BidSource isn't in SampleRateByBidSource and BidStatus isn't "ACCEPTED",
+	// so the combined rate stays 1.0 and this entry must always export.
+	exporter.Export(&AuditEntry{RequestID: "req-1", BidSource: "HEURISTIC_FALLBACK", BidStatus: "FAILED"})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		got := calls
+		mu.Unlock()
+		if got > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls == 0 {
+		t.Fatal("expected an entry matching no sampling key to always export")
+	}
+}
+
+func TestBeaconExporter_DryRunNeverCallsDestination(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := NewBeaconExporter([]BeaconDestination{{Name: "dry", URL: server.URL, DryRun: true}})
+	exporter.Export(&AuditEntry{RequestID: "req-1"})
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 0 {
+		t.Fatalf("expected dry-run mode to never hit the destination, got %d calls", calls)
+	}
+}
+
+func TestBeaconExporter_SignsBodyWhenSecretConfigured(t *testing.T) {
+	var signature string
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		signature = r.Header.Get("X-Beacon-Signature")
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := NewBeaconExporter([]BeaconDestination{{Name: "signed", URL: server.URL, HMACSecret: "secret"}})
+	exporter.Export(&AuditEntry{RequestID: "req-1"})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		got := signature
+		mu.Unlock()
+		if got != "" {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if signature == "" {
+		t.Fatal("expected a signature header when HMACSecret is configured")
+	}
+}
+
+func TestBeaconExporter_GzipsPayloadsOverThreshold(t *testing.T) {
+	var encoding string
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		encoding = r.Header.Get("Content-Encoding")
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := NewBeaconExporter([]BeaconDestination{{Name: "big", URL: server.URL}})
+	bigMeta := make(map[string]interface{})
+	bigMeta["blob"] = strings.Repeat("x", 4096)
+	exporter.Export(&AuditEntry{RequestID: "req-1", Metadata: bigMeta})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		got := encoding
+		mu.Unlock()
+		if got != "" {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if encoding != "gzip" {
+		t.Fatalf("expected a >2KB payload to be gzipped, got Content-Encoding %q", encoding)
+	}
+}
+
+func TestBeaconExporter_RetriesOnFailureUntilSuccess(t *testing.T) {
+	var attempts int
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		n := attempts
+		mu.Unlock()
+		if n < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := NewBeaconExporter([]BeaconDestination{{Name: "flaky", URL: server.URL, BackoffBase: time.Millisecond}})
+	exporter.Export(&AuditEntry{RequestID: "req-1"})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		got := attempts
+		mu.Unlock()
+		if got >= 2 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts < 2 {
+		t.Fatalf("expected the exporter to retry a failed attempt, got %d attempts", attempts)
+	}
+}
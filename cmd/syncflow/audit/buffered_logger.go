@@ -0,0 +1,294 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/observability"
+)
+
+// DropPolicy selects what BufferedLogger does when a platform partition's
+// ring buffer is already at capacity and a new entry arrives.
+type DropPolicy int
+
+const (
+	// DropPolicyDropOldest evicts the oldest buffered entry to make room
+	// for the new one - use when only the freshest activity matters.
+	DropPolicyDropOldest DropPolicy = iota
+	// DropPolicyDropNew discards the incoming entry instead, leaving the
+	// existing backlog untouched.
+	DropPolicyDropNew
+	// DropPolicyBlock waits (subject to ctx) for room rather than losing
+	// any entry - use when completeness matters more than hot-path
+	// latency.
+	DropPolicyBlock
+)
+
+// Writer is satisfied by both AuditLogger and BufferedLogger so callers
+// can be handed either without caring which one they got.
+type Writer interface {
+	Write(ctx context.Context, entry *AuditEntry) error
+}
+
+// BufferedLoggerConfig configures BufferedLogger's per-platform ring
+// buffers and the worker pool that drains them into an underlying
+// AuditLogger.
+type BufferedLoggerConfig struct {
+	// UseBufferedAudit gates the entire buffered path. NewConfiguredWriter
+	// returns the plain synchronous AuditLogger when this is false, so
+	// operators can A/B the two without changing call sites.
+	UseBufferedAudit bool
+
+	// Capacity is how many entries each per-platform partition holds
+	// before DropPolicy kicks in.
+	Capacity int
+
+	// Workers is the number of goroutines draining partitions into the
+	// underlying AuditLogger. Each partition is owned by exactly one
+	// worker (hashed by platform name) so two workers never race on it.
+	Workers int
+
+	// FlushInterval is how often each worker wakes up to drain its
+	// partitions.
+	FlushInterval time.Duration
+
+	// BatchSize caps how many entries a worker drains from a single
+	// partition per wake-up, so one very busy platform can't starve the
+	// others.
+	BatchSize int
+
+	// MaxInFlightBytes caps the approximate total size of entries
+	// buffered (but not yet drained) across all partitions; entries
+	// beyond the cap are dropped (or rejected, under DropPolicyBlock)
+	// regardless of whether their own partition still has room. Zero
+	// means unlimited.
+	MaxInFlightBytes int64
+
+	DropPolicy DropPolicy
+}
+
+// DefaultBufferedLoggerConfig returns reasonable defaults for a single
+// busy deployment; callers should size Capacity/Workers/MaxInFlightBytes
+// to their own traffic.
+func DefaultBufferedLoggerConfig() BufferedLoggerConfig {
+	return BufferedLoggerConfig{
+		Capacity:      1000,
+		Workers:       4,
+		FlushInterval: 100 * time.Millisecond,
+		BatchSize:     50,
+		DropPolicy:    DropPolicyDropOldest,
+	}
+}
+
+// BufferedLogger sits in front of AuditLogger.Write with a bounded,
+// per-platform ring buffer so a slow downstream sink (Postgres under a
+// bursty bid flow) can't block the hot bidding path; a fixed worker pool
+// drains the buffers on a schedule instead.
+type BufferedLogger struct {
+	underlying Writer
+	config     BufferedLoggerConfig
+
+	mu            sync.Mutex
+	partitions    map[string]chan *AuditEntry
+	inFlightBytes int64
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewBufferedLogger creates a BufferedLogger draining into underlying and
+// starts its worker pool.
+func NewBufferedLogger(underlying Writer, config BufferedLoggerConfig) *BufferedLogger {
+	if config.Capacity <= 0 {
+		config.Capacity = 1000
+	}
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 100 * time.Millisecond
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 50
+	}
+
+	b := &BufferedLogger{
+		underlying: underlying,
+		config:     config,
+		partitions: make(map[string]chan *AuditEntry),
+		stopCh:     make(chan struct{}),
+	}
+	b.start()
+	return b
+}
+
+// NewConfiguredWriter returns a BufferedLogger wrapping underlying when
+// config.UseBufferedAudit is set, or underlying itself otherwise - the
+// single feature-flagged switch operators use to A/B the buffered path
+// against the synchronous writer.
+func NewConfiguredWriter(underlying *AuditLogger, config BufferedLoggerConfig) Writer {
+	if !config.UseBufferedAudit {
+		return underlying
+	}
+	return NewBufferedLogger(underlying, config)
+}
+
+// Write enqueues entry onto its platform's partition and returns
+// immediately (except under DropPolicyBlock, which waits for room or for
+// ctx to end), so the caller never waits on the underlying AuditLogger's
+// own Postgres write.
+func (b *BufferedLogger) Write(ctx context.Context, entry *AuditEntry) error {
+	size := estimateEntrySize(entry)
+
+	if b.config.MaxInFlightBytes > 0 && atomic.LoadInt64(&b.inFlightBytes)+size > b.config.MaxInFlightBytes {
+		observability.Default.RecordAuditBufferDrop(entry.Platform)
+		if b.config.DropPolicy == DropPolicyBlock {
+			return fmt.Errorf("audit buffer: max in-flight bytes (%d) exceeded", b.config.MaxInFlightBytes)
+		}
+		return nil
+	}
+
+	ch := b.partitionFor(entry.Platform)
+
+	switch b.config.DropPolicy {
+	case DropPolicyDropNew:
+		select {
+		case ch <- entry:
+		default:
+			observability.Default.RecordAuditBufferDrop(entry.Platform)
+			return nil
+		}
+	case DropPolicyBlock:
+		select {
+		case ch <- entry:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	default: // DropPolicyDropOldest
+	dropLoop:
+		for {
+			select {
+			case ch <- entry:
+				break dropLoop
+			default:
+				select {
+				case <-ch:
+					observability.Default.RecordAuditBufferDrop(entry.Platform)
+				default:
+				}
+			}
+		}
+	}
+
+	atomic.AddInt64(&b.inFlightBytes, size)
+	observability.Default.SetAuditBufferDepth(entry.Platform, int64(len(ch)))
+	return nil
+}
+
+// Close stops the worker pool after a final best-effort drain of every
+// partition. It does not close the underlying AuditLogger, which the
+// caller still owns.
+func (b *BufferedLogger) Close() error {
+	close(b.stopCh)
+	b.wg.Wait()
+	return nil
+}
+
+func (b *BufferedLogger) partitionFor(platform string) chan *AuditEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch, ok := b.partitions[platform]
+	if !ok {
+		ch = make(chan *AuditEntry, b.config.Capacity)
+		b.partitions[platform] = ch
+	}
+	return ch
+}
+
+func (b *BufferedLogger) start() {
+	for i := 0; i < b.config.Workers; i++ {
+		b.wg.Add(1)
+		go b.runWorker(i)
+	}
+}
+
+func (b *BufferedLogger) runWorker(workerID int) {
+	defer b.wg.Done()
+	ticker := time.NewTicker(b.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopCh:
+			b.drainOnce(workerID)
+			return
+		case <-ticker.C:
+			b.drainOnce(workerID)
+		}
+	}
+}
+
+// drainOnce drains up to BatchSize entries from every partition this
+// worker owns.
+func (b *BufferedLogger) drainOnce(workerID int) {
+	b.mu.Lock()
+	platforms := make([]string, 0, len(b.partitions))
+	for platform := range b.partitions {
+		platforms = append(platforms, platform)
+	}
+	b.mu.Unlock()
+
+	for _, platform := range platforms {
+		if b.ownerWorker(platform) == workerID {
+			b.drainPartition(platform)
+		}
+	}
+}
+
+// ownerWorker deterministically assigns platform to one of b.config.Workers
+// workers so partitions are never drained concurrently by two of them.
+func (b *BufferedLogger) ownerWorker(platform string) int {
+	h := fnv.New32a()
+	h.Write([]byte(platform))
+	return int(h.Sum32()) % b.config.Workers
+}
+
+func (b *BufferedLogger) drainPartition(platform string) {
+	b.mu.Lock()
+	ch, ok := b.partitions[platform]
+	b.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	for i := 0; i < b.config.BatchSize; i++ {
+		select {
+		case entry := <-ch:
+			size := estimateEntrySize(entry)
+			if err := b.underlying.Write(ctx, entry); err != nil {
+				// The entry can't be put back once popped; log and move
+				// on rather than blocking this worker on a stuck sink,
+				// matching AuditLogger.backgroundFlush's own handling.
+				fmt.Printf("⚠️  buffered audit flush error (platform=%s): %v\n", platform, err)
+			}
+			atomic.AddInt64(&b.inFlightBytes, -size)
+			observability.Default.SetAuditBufferDepth(platform, int64(len(ch)))
+		default:
+			return
+		}
+	}
+}
+
+func estimateEntrySize(entry *AuditEntry) int64 {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return 0
+	}
+	return int64(len(b))
+}
@@ -0,0 +1,185 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingWriter is a test Writer that records every entry passed to it
+// instead of touching Postgres.
+type recordingWriter struct {
+	mu      sync.Mutex
+	entries []*AuditEntry
+}
+
+func (r *recordingWriter) Write(ctx context.Context, entry *AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *recordingWriter) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+func TestBufferedLogger_DrainsToUnderlyingWriter(t *testing.T) {
+	underlying := &recordingWriter{}
+	logger := NewBufferedLogger(underlying, BufferedLoggerConfig{
+		Capacity:      10,
+		Workers:       2,
+		FlushInterval: 10 * time.Millisecond,
+		BatchSize:     10,
+		DropPolicy:    DropPolicyDropOldest,
+	})
+	defer logger.Close()
+
+	for i := 0; i < 5; i++ {
+		if err := logger.Write(context.Background(), &AuditEntry{RequestID: "req", Platform: "amazon"}); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for underlying.count() < 5 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := underlying.count(); got != 5 {
+		t.Fatalf("expected all 5 entries to drain to the underlying writer, got %d", got)
+	}
+}
+
+func TestBufferedLogger_DropOldestEvictsOldestWhenFull(t *testing.T) {
+	underlying := &recordingWriter{}
+	// No workers draining, so the partition fills up and we can observe
+	// the drop policy directly.
+	logger := NewBufferedLogger(underlying, BufferedLoggerConfig{
+		Capacity:      2,
+		Workers:       1,
+		FlushInterval: time.Hour,
+		BatchSize:     10,
+		DropPolicy:    DropPolicyDropOldest,
+	})
+	defer logger.Close()
+
+	logger.Write(context.Background(), &AuditEntry{RequestID: "1", Platform: "amazon"})
+	logger.Write(context.Background(), &AuditEntry{RequestID: "2", Platform: "amazon"})
+	logger.Write(context.Background(), &AuditEntry{RequestID: "3", Platform: "amazon"}) // should evict "1"
+
+	ch := logger.partitionFor("amazon")
+	first := <-ch
+	second := <-ch
+	if first.RequestID != "2" || second.RequestID != "3" {
+		t.Fatalf("expected entry 1 to be evicted, got %q then %q", first.RequestID, second.RequestID)
+	}
+}
+
+func TestBufferedLogger_DropNewDiscardsIncomingWhenFull(t *testing.T) {
+	underlying := &recordingWriter{}
+	logger := NewBufferedLogger(underlying, BufferedLoggerConfig{
+		Capacity:      1,
+		Workers:       1,
+		FlushInterval: time.Hour,
+		BatchSize:     10,
+		DropPolicy:    DropPolicyDropNew,
+	})
+	defer logger.Close()
+
+	logger.Write(context.Background(), &AuditEntry{RequestID: "1", Platform: "amazon"})
+	logger.Write(context.Background(), &AuditEntry{RequestID: "2", Platform: "amazon"})
+
+	ch := logger.partitionFor("amazon")
+	select {
+	case entry := <-ch:
+		if entry.RequestID != "1" {
+			t.Fatalf("expected the original entry to survive, got %q", entry.RequestID)
+		}
+	default:
+		t.Fatal("expected the first entry to still be buffered")
+	}
+	select {
+	case entry := <-ch:
+		t.Fatalf("expected the second entry to be dropped, got %q", entry.RequestID)
+	default:
+	}
+}
+
+func TestBufferedLogger_BlockWaitsForRoomOrContextDeadline(t *testing.T) {
+	underlying := &recordingWriter{}
+	logger := NewBufferedLogger(underlying, BufferedLoggerConfig{
+		Capacity:      1,
+		Workers:       1,
+		FlushInterval: time.Hour,
+		BatchSize:     10,
+		DropPolicy:    DropPolicyBlock,
+	})
+	defer logger.Close()
+
+	logger.Write(context.Background(), &AuditEntry{RequestID: "1", Platform: "amazon"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := logger.Write(ctx, &AuditEntry{RequestID: "2", Platform: "amazon"}); err == nil {
+		t.Fatal("expected Write to block until the context deadline since the partition is full")
+	}
+}
+
+func TestBufferedLogger_PartitionsByPlatform(t *testing.T) {
+	underlying := &recordingWriter{}
+	logger := NewBufferedLogger(underlying, BufferedLoggerConfig{
+		Capacity:      10,
+		Workers:       1,
+		FlushInterval: time.Hour,
+		BatchSize:     10,
+		DropPolicy:    DropPolicyDropOldest,
+	})
+	defer logger.Close()
+
+	logger.Write(context.Background(), &AuditEntry{RequestID: "a", Platform: "amazon"})
+	logger.Write(context.Background(), &AuditEntry{RequestID: "g", Platform: "google"})
+
+	if len(logger.partitionFor("amazon")) != 1 || len(logger.partitionFor("google")) != 1 {
+		t.Fatal("expected each platform to get its own partition")
+	}
+}
+
+func TestBufferedLogger_MaxInFlightBytesDropsBeyondCap(t *testing.T) {
+	underlying := &recordingWriter{}
+	logger := NewBufferedLogger(underlying, BufferedLoggerConfig{
+		Capacity:         10,
+		Workers:          1,
+		FlushInterval:    time.Hour,
+		BatchSize:        10,
+		DropPolicy:       DropPolicyDropNew,
+		MaxInFlightBytes: 1, // smaller than any real entry's JSON encoding
+	})
+	defer logger.Close()
+
+	if err := logger.Write(context.Background(), &AuditEntry{RequestID: "1", Platform: "amazon"}); err != nil {
+		t.Fatalf("DropPolicyDropNew should not error on cap overflow: %v", err)
+	}
+
+	if len(logger.partitionFor("amazon")) != 0 {
+		t.Fatal("expected the entry to be dropped before reaching its partition")
+	}
+}
+
+func TestNewConfiguredWriter_FlagSelectsImplementation(t *testing.T) {
+	underlying := &AuditLogger{}
+
+	plain := NewConfiguredWriter(underlying, BufferedLoggerConfig{UseBufferedAudit: false})
+	if plain != Writer(underlying) {
+		t.Fatal("expected the synchronous AuditLogger when UseBufferedAudit is false")
+	}
+
+	buffered := NewConfiguredWriter(underlying, BufferedLoggerConfig{UseBufferedAudit: true})
+	if _, ok := buffered.(*BufferedLogger); !ok {
+		t.Fatal("expected a BufferedLogger when UseBufferedAudit is true")
+	}
+	buffered.(*BufferedLogger).Close()
+}
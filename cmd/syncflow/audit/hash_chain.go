@@ -0,0 +1,273 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// chainPartitionKey scopes a hash chain to one (customer_id, campaign_id)
+// pair - the same granularity AuditFilters.CustomerID/CampaignID already
+// query by - rather than one chain across the entire audit_log table,
+// since unrelated customers/campaigns are written concurrently and have no
+// meaningful ordering relative to each other.
+type chainPartitionKey struct {
+	CustomerID string
+	CampaignID string
+}
+
+func partitionOf(entry *AuditEntry) chainPartitionKey {
+	return chainPartitionKey{CustomerID: entry.CustomerID, CampaignID: entry.CampaignID}
+}
+
+// canonicalEntryJSON marshals the fields that make up entry's tamper-evident
+// content - everything except the chain linkage fields themselves, which
+// would make the hash depend on its own output - into a stable byte
+// encoding. encoding/json already sorts map keys and struct fields are
+// marshaled in their declaration order, so the same *AuditEntry always
+// produces the same bytes.
+func canonicalEntryJSON(entry *AuditEntry) ([]byte, error) {
+	// Copy rather than mutate the caller's entry; PrevHash/EntryHash are
+	// excluded from the hashed content by simply never being read here.
+	canonical := struct {
+		RequestID       string                 `json:"request_id"`
+		Timestamp       int64                  `json:"timestamp"`
+		CustomerID      string                 `json:"customer_id"`
+		CampaignID      string                 `json:"campaign_id"`
+		PredictedLTV    float64                `json:"predicted_ltv"`
+		Confidence      float64                `json:"confidence"`
+		LTVLowerBound   float64                `json:"ltv_lower_bound"`
+		LTVUpperBound   float64                `json:"ltv_upper_bound"`
+		ModelVersion    string                 `json:"model_version"`
+		BidAmount       float64                `json:"bid_amount"`
+		BidSource       string                 `json:"bid_source"`
+		Platform        string                 `json:"platform"`
+		PlatformBidID   string                 `json:"platform_bid_id"`
+		BidStatus       string                 `json:"bid_status"`
+		CircuitState    string                 `json:"circuit_state"`
+		UsedFallback    bool                   `json:"used_fallback"`
+		ExecutionTimeMs int                    `json:"execution_time_ms"`
+		InferenceTimeUs int                    `json:"inference_time_us"`
+		CampaignBudget  float64                `json:"campaign_budget"`
+		CurrentSpend    float64                `json:"current_spend"`
+		RemainingBudget float64                `json:"remaining_budget"`
+		Metadata        map[string]interface{} `json:"metadata,omitempty"`
+		Explanation     string                 `json:"explanation,omitempty"`
+	}{
+		RequestID:       entry.RequestID,
+		Timestamp:       entry.Timestamp.UnixNano(),
+		CustomerID:      entry.CustomerID,
+		CampaignID:      entry.CampaignID,
+		PredictedLTV:    entry.PredictedLTV,
+		Confidence:      entry.Confidence,
+		LTVLowerBound:   entry.LTVLowerBound,
+		LTVUpperBound:   entry.LTVUpperBound,
+		ModelVersion:    entry.ModelVersion,
+		BidAmount:       entry.BidAmount,
+		BidSource:       entry.BidSource,
+		Platform:        entry.Platform,
+		PlatformBidID:   entry.PlatformBidID,
+		BidStatus:       entry.BidStatus,
+		CircuitState:    entry.CircuitState,
+		UsedFallback:    entry.UsedFallback,
+		ExecutionTimeMs: entry.ExecutionTimeMs,
+		InferenceTimeUs: entry.InferenceTimeUs,
+		CampaignBudget:  entry.CampaignBudget,
+		CurrentSpend:    entry.CurrentSpend,
+		RemainingBudget: entry.RemainingBudget,
+		Metadata:        entry.Metadata,
+		Explanation:     entry.Explanation,
+	}
+
+	b, err := json.Marshal(canonical)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize audit entry: %w", err)
+	}
+	return b, nil
+}
+
+// computeEntryHash returns hex(SHA256(canonical_json(entry) || prevHash)),
+// binding entry both to its own content and to everything that came before
+// it in its partition's chain - so mutating, reordering, or deleting any
+// earlier row changes every EntryHash computed after it.
+func computeEntryHash(entry *AuditEntry, prevHash string) (string, error) {
+	canonical, err := canonicalEntryJSON(entry)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write(canonical)
+	h.Write([]byte(prevHash))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// loadLastHash returns the EntryHash of the most recently committed row in
+// key's partition, or "" if the partition has no rows yet (the genesis
+// entry of its chain).
+func (a *AuditLogger) loadLastHash(ctx context.Context, key chainPartitionKey) (string, error) {
+	var hash string
+	err := a.db.QueryRowContext(ctx, `
+		SELECT entry_hash FROM audit_log
+		WHERE customer_id = $1 AND campaign_id = $2
+		ORDER BY timestamp DESC, request_id DESC
+		LIMIT 1
+	`, key.CustomerID, key.CampaignID).Scan(&hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to load last chain hash for %s/%s: %w", key.CustomerID, key.CampaignID, err)
+	}
+	return hash, nil
+}
+
+// chainEntries stamps PrevHash/EntryHash onto every buffered entry in
+// timestamp order, one partition at a time, loading each partition's
+// current chain tip from the database exactly once and then threading it
+// through in memory as the batch is hashed - so a single Flush never issues
+// more than one lookup query per distinct (customer_id, campaign_id) pair
+// in the batch, no matter how many of its entries belong to that partition.
+func (a *AuditLogger) chainEntries(ctx context.Context, entries []*AuditEntry) error {
+	// Chain in the same (timestamp, request_id) order VerifyChain and
+	// loadLastHash read rows back in.
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Timestamp.Equal(entries[j].Timestamp) {
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		}
+		return entries[i].RequestID < entries[j].RequestID
+	})
+
+	tips := make(map[chainPartitionKey]string)
+
+	for _, entry := range entries {
+		key := partitionOf(entry)
+
+		prevHash, ok := tips[key]
+		if !ok {
+			loaded, err := a.loadLastHash(ctx, key)
+			if err != nil {
+				return err
+			}
+			prevHash = loaded
+		}
+
+		hash, err := computeEntryHash(entry, prevHash)
+		if err != nil {
+			return err
+		}
+
+		entry.PrevHash = prevHash
+		entry.EntryHash = hash
+		tips[key] = hash
+	}
+
+	return nil
+}
+
+// ChainDivergence describes the first audit_log row whose EntryHash doesn't
+// match what VerifyChain recomputed from its own content and the preceding
+// row's hash - i.e. the earliest point tampering (or corruption) could have
+// occurred in that partition's chain.
+type ChainDivergence struct {
+	CustomerID   string
+	CampaignID   string
+	RequestID    string
+	ExpectedHash string
+	StoredHash   string
+}
+
+// VerifyChain streams every row matching filters in partition, then
+// timestamp order, recomputing each EntryHash from its content and the
+// previous row's (recomputed) hash, and returns the first row whose stored
+// EntryHash doesn't match. A nil divergence with a nil error means the
+// entire chain verified clean.
+func (a *AuditLogger) VerifyChain(ctx context.Context, filters AuditFilters) (*ChainDivergence, error) {
+	query := `
+		SELECT
+			timestamp, request_id, customer_id, campaign_id,
+			predicted_ltv, confidence, ltv_lower_bound, ltv_upper_bound, model_version,
+			bid_amount, bid_source, platform, platform_bid_id,
+			bid_status, circuit_state, used_fallback,
+			execution_time_ms, inference_time_us,
+			campaign_budget, current_spend, remaining_budget,
+			metadata, explanation, prev_hash, entry_hash
+		FROM audit_log
+		WHERE 1=1
+	`
+	args := []interface{}{}
+	argIdx := 1
+
+	if filters.CustomerID != "" {
+		query += fmt.Sprintf(" AND customer_id = $%d", argIdx)
+		args = append(args, filters.CustomerID)
+		argIdx++
+	}
+	if filters.CampaignID != "" {
+		query += fmt.Sprintf(" AND campaign_id = $%d", argIdx)
+		args = append(args, filters.CampaignID)
+		argIdx++
+	}
+
+	query += " ORDER BY customer_id, campaign_id, timestamp ASC, request_id ASC"
+
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit trail for chain verification: %w", err)
+	}
+	defer rows.Close()
+
+	tips := make(map[chainPartitionKey]string)
+
+	for rows.Next() {
+		entry := &AuditEntry{}
+		var metadataJSON []byte
+		var storedHash string
+
+		if err := rows.Scan(
+			&entry.Timestamp, &entry.RequestID, &entry.CustomerID, &entry.CampaignID,
+			&entry.PredictedLTV, &entry.Confidence, &entry.LTVLowerBound, &entry.LTVUpperBound, &entry.ModelVersion,
+			&entry.BidAmount, &entry.BidSource, &entry.Platform, &entry.PlatformBidID,
+			&entry.BidStatus, &entry.CircuitState, &entry.UsedFallback,
+			&entry.ExecutionTimeMs, &entry.InferenceTimeUs,
+			&entry.CampaignBudget, &entry.CurrentSpend, &entry.RemainingBudget,
+			&metadataJSON, &entry.Explanation, &entry.PrevHash, &storedHash,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry for chain verification: %w", err)
+		}
+		if len(metadataJSON) > 0 {
+			if err := json.Unmarshal(metadataJSON, &entry.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal metadata for chain verification: %w", err)
+			}
+		}
+
+		key := partitionOf(entry)
+		expectedPrev := tips[key] // "" for the first row VerifyChain sees in this partition
+
+		expectedHash, err := computeEntryHash(entry, expectedPrev)
+		if err != nil {
+			return nil, err
+		}
+
+		if expectedHash != storedHash || entry.PrevHash != expectedPrev {
+			return &ChainDivergence{
+				CustomerID:   entry.CustomerID,
+				CampaignID:   entry.CampaignID,
+				RequestID:    entry.RequestID,
+				ExpectedHash: expectedHash,
+				StoredHash:   storedHash,
+			}, nil
+		}
+
+		tips[key] = storedHash
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading audit trail for chain verification: %w", err)
+	}
+
+	return nil, nil
+}
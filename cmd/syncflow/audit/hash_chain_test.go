@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testChainEntry(requestID string) *AuditEntry {
+	return &AuditEntry{
+		RequestID:    requestID,
+		Timestamp:    time.Unix(1700000000, 0),
+		CustomerID:   "cust-1",
+		CampaignID:   "camp-1",
+		PredictedLTV: 42.5,
+		BidAmount:    3.25,
+		Platform:     "amazon",
+		BidStatus:    "ACCEPTED",
+	}
+}
+
+func TestComputeEntryHash_DeterministicForSameInput(t *testing.T) {
+	entry := testChainEntry("req-1")
+
+	h1, err := computeEntryHash(entry, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h2, err := computeEntryHash(entry, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h1 != h2 {
+		t.Fatalf("expected computeEntryHash to be deterministic, got %q and %q", h1, h2)
+	}
+}
+
+func TestComputeEntryHash_ChangesWithPrevHash(t *testing.T) {
+	entry := testChainEntry("req-1")
+
+	h1, err := computeEntryHash(entry, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h2, err := computeEntryHash(entry, "some-prior-hash")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h1 == h2 {
+		t.Fatal("expected a different prevHash to change the resulting hash")
+	}
+}
+
+func TestComputeEntryHash_ChangesWithEntryContent(t *testing.T) {
+	entryA := testChainEntry("req-1")
+	entryB := testChainEntry("req-1")
+	entryB.PredictedLTV = 999.0
+
+	hA, err := computeEntryHash(entryA, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hB, err := computeEntryHash(entryB, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hA == hB {
+		t.Fatal("expected a changed field to change the resulting hash")
+	}
+}
+
+func TestMerkleRoot_EmptyIsStable(t *testing.T) {
+	r1 := merkleRoot(nil)
+	r2 := merkleRoot([][]byte{})
+	if string(r1) != string(r2) {
+		t.Fatal("expected merkleRoot(nil) and merkleRoot([]) to agree")
+	}
+}
+
+func TestMerkleRoot_OddLeafCountDuplicatesLast(t *testing.T) {
+	leaves := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	root := merkleRoot(leaves)
+	// Duplicating the last leaf means {a,b,c} should root the same as
+	// {a,b,c,c}.
+	dup := merkleRoot([][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("c")})
+	if string(root) != string(dup) {
+		t.Fatal("expected odd-count merkleRoot to match the explicit duplicate-last-leaf tree")
+	}
+}
+
+func TestMerkleRoot_OrderSensitive(t *testing.T) {
+	r1 := merkleRoot([][]byte{[]byte("a"), []byte("b")})
+	r2 := merkleRoot([][]byte{[]byte("b"), []byte("a")})
+	if string(r1) == string(r2) {
+		t.Fatal("expected leaf order to change the Merkle root")
+	}
+}
+
+func TestWebhookAnchorSink_PostsAnchorJSON(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookAnchorSink(server.URL)
+	anchor := &Anchor{MerkleRoot: "deadbeef", EntryCount: 3}
+	if err := sink.Publish(context.Background(), anchor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received == "" {
+		t.Fatal("expected the webhook to receive a body")
+	}
+}
+
+func TestFileAnchorSink_AppendsJSONLine(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/anchors.jsonl"
+	sink := &FileAnchorSink{Path: path}
+
+	if err := sink.Publish(context.Background(), &Anchor{MerkleRoot: "root-1", EntryCount: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sink.Publish(context.Background(), &Anchor{MerkleRoot: "root-2", EntryCount: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading anchor file: %v", err)
+	}
+	if got := string(contents); !strings.Contains(got, "root-1") || !strings.Contains(got, "root-2") {
+		t.Fatalf("expected both anchors to be appended, got %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// kafkaRESTTimeout bounds one produce request - the same conservative
+// per-call budget auditsink.KafkaRESTSink uses for SyncEngage's retention
+// trail.
+const kafkaRESTTimeout = 5 * time.Second
+
+// KafkaSink publishes every AuditEntry in a batch as its own Kafka message,
+// keyed by RequestID so downstream attribution consumers can look up a
+// single bid's record directly rather than scanning a partition. Like
+// auditsink.KafkaRESTSink, it talks to a Confluent REST Proxy
+// (https://docs.confluent.io/platform/current/kafka-rest/api.html) instead
+// of a native client, since go.mod carries no Kafka client dependency and
+// the repo's established convention is to avoid adding a heavyweight one
+// when an HTTP-based integration covers the same need.
+type KafkaSink struct {
+	proxyURL string
+	topic    string
+
+	httpClient *http.Client
+}
+
+// NewKafkaSink creates a KafkaSink producing to <proxyURL>/topics/<topic>.
+func NewKafkaSink(proxyURL, topic string) *KafkaSink {
+	return &KafkaSink{
+		proxyURL:   proxyURL,
+		topic:      topic,
+		httpClient: &http.Client{Timeout: kafkaRESTTimeout},
+	}
+}
+
+type kafkaEntryRecord struct {
+	Key   string      `json:"key"`
+	Value *AuditEntry `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaEntryRecord `json:"records"`
+}
+
+// WriteBatch implements Sink, producing every entry in one REST Proxy
+// request.
+func (k *KafkaSink) WriteBatch(ctx context.Context, entries []*AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	records := make([]kafkaEntryRecord, len(entries))
+	for i, entry := range entries {
+		records[i] = kafkaEntryRecord{Key: entry.RequestID, Value: entry}
+	}
+
+	body, err := json.Marshal(kafkaProduceRequest{Records: records})
+	if err != nil {
+		return fmt.Errorf("audit: marshaling kafka records: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/topics/%s", k.proxyURL, k.topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("audit: posting to kafka rest proxy: %w", err)
+	}
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body) // drain so the connection can be reused
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("audit: kafka rest proxy returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close implements Sink. The REST proxy holds no per-sink connection state
+// to release.
+func (k *KafkaSink) Close() error {
+	return nil
+}
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKafkaSink_WriteBatchKeysByRequestID(t *testing.T) {
+	var captured kafkaProduceRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/topics/audit-trail" {
+			t.Errorf("expected POST to /topics/audit-trail, got %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&captured); err != nil {
+			t.Fatalf("failed to decode produce request: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewKafkaSink(server.URL, "audit-trail")
+	entries := []*AuditEntry{
+		{RequestID: "req-1", Platform: "amazon"},
+		{RequestID: "req-2", Platform: "google"},
+	}
+	if err := sink.WriteBatch(context.Background(), entries); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(captured.Records) != 2 {
+		t.Fatalf("expected one record per entry, got %d", len(captured.Records))
+	}
+	if captured.Records[0].Key != "req-1" || captured.Records[1].Key != "req-2" {
+		t.Fatalf("expected records keyed by RequestID, got %+v", captured.Records)
+	}
+}
+
+func TestKafkaSink_WriteBatchReturnsErrorOnNonSuccessStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sink := NewKafkaSink(server.URL, "audit-trail")
+	err := sink.WriteBatch(context.Background(), []*AuditEntry{{RequestID: "req-1"}})
+	if err == nil {
+		t.Fatal("expected an error for a 500 response")
+	}
+}
+
+func TestKafkaSink_WriteBatchSkipsEmptyEntries(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewKafkaSink(server.URL, "audit-trail")
+	if err := sink.WriteBatch(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called {
+		t.Fatal("expected no request to be made for an empty batch")
+	}
+}
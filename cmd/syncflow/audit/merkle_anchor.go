@@ -0,0 +1,191 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+const anchorDefaultTimeout = 5 * time.Second
+
+// Anchor is a Merkle root computed over every EntryHash committed in a time
+// window, letting an operator prove after the fact that the rows behind
+// the root haven't been altered without needing to replay VerifyChain
+// across the whole table.
+type Anchor struct {
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+	MerkleRoot  string    `json:"merkle_root"`
+	EntryCount  int       `json:"entry_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AnchorSink is an external destination PublishAnchor ships a freshly
+// computed Anchor to, alongside the audit_anchors row it always writes.
+type AnchorSink interface {
+	Publish(ctx context.Context, anchor *Anchor) error
+}
+
+// PublishAnchor computes a Merkle root over every EntryHash committed in
+// [now-window, now), writes it to audit_anchors, and - if a.AnchorSink is
+// set - ships it there too. Callers wanting periodic anchoring run this on
+// their own ticker; PublishAnchor itself only ever computes one window.
+func (a *AuditLogger) PublishAnchor(ctx context.Context, window time.Duration) (*Anchor, error) {
+	windowEnd := time.Now()
+	windowStart := windowEnd.Add(-window)
+
+	rows, err := a.db.QueryContext(ctx, `
+		SELECT entry_hash FROM audit_log
+		WHERE timestamp >= $1 AND timestamp < $2 AND entry_hash <> ''
+		ORDER BY customer_id, campaign_id, timestamp ASC, request_id ASC
+	`, windowStart, windowEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entry hashes for anchor: %w", err)
+	}
+	defer rows.Close()
+
+	var leaves [][]byte
+	for rows.Next() {
+		var hexHash string
+		if err := rows.Scan(&hexHash); err != nil {
+			return nil, fmt.Errorf("failed to scan entry hash for anchor: %w", err)
+		}
+		raw, err := hex.DecodeString(hexHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode entry hash %q for anchor: %w", hexHash, err)
+		}
+		leaves = append(leaves, raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading entry hashes for anchor: %w", err)
+	}
+
+	anchor := &Anchor{
+		WindowStart: windowStart,
+		WindowEnd:   windowEnd,
+		MerkleRoot:  hex.EncodeToString(merkleRoot(leaves)),
+		EntryCount:  len(leaves),
+		CreatedAt:   windowEnd,
+	}
+
+	if _, err := a.db.ExecContext(ctx, `
+		INSERT INTO audit_anchors (window_start, window_end, merkle_root, entry_count, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, anchor.WindowStart, anchor.WindowEnd, anchor.MerkleRoot, anchor.EntryCount, anchor.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to write audit anchor: %w", err)
+	}
+
+	if a.AnchorSink != nil {
+		if err := a.AnchorSink.Publish(ctx, anchor); err != nil {
+			// The anchor is already durably written to audit_anchors;
+			// losing the external copy is non-fatal, matching how a
+			// Beacon/Stream failure never fails Write.
+			fmt.Printf("⚠️  anchor publish failed (sink): %v\n", err)
+		}
+	}
+
+	return anchor, nil
+}
+
+// merkleRoot computes a binary Merkle root over leaves (already-hashed
+// EntryHash bytes), duplicating the final node at each level that has an
+// odd count - the same convention Bitcoin's block Merkle tree uses. An
+// empty leaf set roots to SHA256 of nothing, so two empty windows always
+// anchor to the same value rather than to an arbitrary sentinel.
+func merkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	}
+
+	level := make([][]byte, len(leaves))
+	copy(level, leaves)
+
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+		next := make([][]byte, 0, len(level)/2)
+		for i := 0; i < len(level); i += 2 {
+			h := sha256.New()
+			h.Write(level[i])
+			h.Write(level[i+1])
+			next = append(next, h.Sum(nil))
+		}
+		level = next
+	}
+
+	return level[0]
+}
+
+// WebhookAnchorSink POSTs each Anchor as JSON to URL - a much simpler
+// sibling of BeaconExporter's POST path, since an anchor is rare (one per
+// window) and carries no sensitive per-entry data to sample or sign.
+type WebhookAnchorSink struct {
+	URL string
+
+	httpClient *http.Client
+}
+
+// NewWebhookAnchorSink creates a WebhookAnchorSink POSTing to url.
+func NewWebhookAnchorSink(url string) *WebhookAnchorSink {
+	return &WebhookAnchorSink{URL: url, httpClient: &http.Client{Timeout: anchorDefaultTimeout}}
+}
+
+// Publish implements AnchorSink by POSTing anchor as JSON to w.URL.
+func (w *WebhookAnchorSink) Publish(ctx context.Context, anchor *Anchor) error {
+	body, err := json.Marshal(anchor)
+	if err != nil {
+		return fmt.Errorf("failed to marshal anchor: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build anchor webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to POST anchor: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("anchor webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// FileAnchorSink appends each Anchor as a JSON line to Path, giving
+// operators a durable local copy even when no webhook endpoint is
+// configured.
+type FileAnchorSink struct {
+	Path string
+}
+
+// Publish implements AnchorSink by appending anchor as one JSON line to
+// f.Path.
+func (f *FileAnchorSink) Publish(ctx context.Context, anchor *Anchor) error {
+	line, err := json.Marshal(anchor)
+	if err != nil {
+		return fmt.Errorf("failed to marshal anchor: %w", err)
+	}
+
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open anchor file %s: %w", f.Path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write anchor to %s: %w", f.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,475 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// MetricsWindow identifies one of the fixed rolling windows AuditLogger
+// maintains per platform. There is no configurable-duration window: callers
+// pick one of the four below, the same set GetAccuracyMetrics' dashboards
+// already report against.
+type MetricsWindow string
+
+const (
+	Window1m  MetricsWindow = "1m"
+	Window5m  MetricsWindow = "5m"
+	Window1h  MetricsWindow = "1h"
+	Window24h MetricsWindow = "24h"
+)
+
+// metricsWindowHalfLives maps each MetricsWindow to the half-life its
+// decaying accumulator uses. A window is approximated as an exponentially-
+// weighted moving average rather than a literal sliding buffer of samples,
+// so "1h window" here means "old entries fade out with an ~1h half-life",
+// not "every entry from the last 60 minutes, replayed exactly".
+var metricsWindowHalfLives = map[MetricsWindow]time.Duration{
+	Window1m:  time.Minute,
+	Window5m:  5 * time.Minute,
+	Window1h:  time.Hour,
+	Window24h: 24 * time.Hour,
+}
+
+// accuracyTolerancePct mirrors the ABS(ltv_error_pct) <= 10 tolerance
+// GetAccuracyMetrics' SQL already hard-codes, so the in-process rolling
+// windows and the Postgres-backed historical query agree on what "within
+// tolerance" means.
+const accuracyTolerancePct = 10.0
+
+// metricsSubscriberBuffer bounds how many snapshots a slow Subscribe/
+// SubscribeDrift consumer can fall behind before new ones are dropped
+// rather than blocking the Flush path that produces them. Snapshots are
+// emitted far less often than raw entries (StreamSink's per-entry buffer
+// is 256), so a small buffer is enough here.
+const metricsSubscriberBuffer = 32
+
+// minDriftSamples is the fewest decayed, verified samples driftSignalWindow
+// needs before SubscribeDrift will compare it against a platform's
+// baseline - below this, one bad prediction could swing the signal enough
+// to look like drift.
+const minDriftSamples = 5
+
+// driftSignalWindow is the window SubscribeDrift measures against each
+// platform's baseline. 5m is long enough to smooth out single-request
+// noise but short enough to catch a regression before 1h or 24h would.
+const driftSignalWindow = Window5m
+
+// driftBaselineHalfLife controls how slowly a platform's drift baseline
+// follows the signal it's measuring - deliberately much slower than
+// driftSignalWindow's own half-life, so the baseline represents "normal"
+// rather than chasing the same regression it's meant to flag.
+const driftBaselineHalfLife = 24 * time.Hour
+
+// rollingWindowStats is an incrementally-updated, decayed accumulator for
+// one (platform, MetricsWindow) pair. It tracks only the running totals
+// needed to derive an AccuracyMetrics snapshot, never the underlying
+// entries, so a window stays cheap regardless of how much traffic flows
+// through it - the in-process analogue of GetAccuracyMetrics' SQL
+// aggregates.
+type rollingWindowStats struct {
+	lastUpdate    time.Time
+	totalCount    float64
+	verifiedCount float64
+	withinCount   float64
+	errorSum      float64
+}
+
+// decayedValues returns what s's counters would read at now, without
+// mutating s - used by the cadence ticker to preview a window between
+// writes.
+func (s *rollingWindowStats) decayedValues(now time.Time, halfLife time.Duration) (total, verified, within, errSum float64) {
+	if s.lastUpdate.IsZero() {
+		return 0, 0, 0, 0
+	}
+	factor := decayFactor(now.Sub(s.lastUpdate), halfLife)
+	return s.totalCount * factor, s.verifiedCount * factor, s.withinCount * factor, s.errorSum * factor
+}
+
+// observe folds one entry into s, decaying its existing counters by the
+// time elapsed since the last observation first.
+func (s *rollingWindowStats) observe(now time.Time, halfLife time.Duration, errPct *float64) {
+	if s.lastUpdate.IsZero() {
+		s.lastUpdate = now
+	} else {
+		factor := decayFactor(now.Sub(s.lastUpdate), halfLife)
+		s.totalCount *= factor
+		s.verifiedCount *= factor
+		s.withinCount *= factor
+		s.errorSum *= factor
+		s.lastUpdate = now
+	}
+
+	s.totalCount++
+	if errPct != nil {
+		s.verifiedCount++
+		s.errorSum += *errPct
+		if math.Abs(*errPct) <= accuracyTolerancePct {
+			s.withinCount++
+		}
+	}
+}
+
+// decayFactor is the fraction of a decayed counter's value that survives
+// elapsed time against halfLife.
+func decayFactor(elapsed, halfLife time.Duration) float64 {
+	if elapsed <= 0 || halfLife <= 0 {
+		return 1
+	}
+	return math.Exp(-elapsed.Seconds() / halfLife.Seconds() * math.Ln2)
+}
+
+// buildAccuracyMetrics turns one window's decayed counters into the same
+// AccuracyMetrics shape GetAccuracyMetrics returns from Postgres.
+func buildAccuracyMetrics(platform string, total, verified, within, errSum float64) *AccuracyMetrics {
+	m := &AccuracyMetrics{
+		Platform:            platform,
+		TotalPredictions:    int(math.Round(total)),
+		VerifiedPredictions: int(math.Round(verified)),
+		WithinTolerance:     int(math.Round(within)),
+	}
+	if verified > 0 {
+		m.AvgErrorPct = errSum / verified
+		m.AccuracyPct = within / verified * 100
+	}
+	return m
+}
+
+// platformBaseline is a platform's slow-moving "normal" for the drift
+// signal, tracked as an EWMA mean/variance pair (Welford's online update,
+// decayed the same way rollingWindowStats is) rather than a stored sample
+// history.
+type platformBaseline struct {
+	initialized  bool
+	lastUpdate   time.Time
+	errorMean    float64
+	errorVar     float64
+	accuracyMean float64
+	accuracyVar  float64
+}
+
+func (b *platformBaseline) update(now time.Time, errorPct, accuracyPct float64) {
+	if !b.initialized {
+		b.errorMean, b.accuracyMean = errorPct, accuracyPct
+		b.initialized = true
+		b.lastUpdate = now
+		return
+	}
+
+	alpha := 1 - decayFactor(now.Sub(b.lastUpdate), driftBaselineHalfLife)
+	b.lastUpdate = now
+
+	dErr := errorPct - b.errorMean
+	b.errorMean += alpha * dErr
+	b.errorVar = (1 - alpha) * (b.errorVar + alpha*dErr*dErr)
+
+	dAcc := accuracyPct - b.accuracyMean
+	b.accuracyMean += alpha * dAcc
+	b.accuracyVar = (1 - alpha) * (b.accuracyVar + alpha*dAcc*dAcc)
+}
+
+// MetricsFilter narrows a Subscribe call to one platform's one rolling
+// window, mirroring StreamFilter's zero-value-means-unrestricted
+// convention for Platform. Window has no meaningful zero value - Subscribe
+// rejects it.
+type MetricsFilter struct {
+	// Platform restricts the subscription to one platform; "" receives a
+	// snapshot for every platform a Flush touches.
+	Platform string
+
+	// Window selects which of the four fixed rolling windows to report.
+	Window MetricsWindow
+
+	// MinDelta emits a new snapshot whenever AccuracyPct has moved by at
+	// least this many percentage points since the last one this
+	// subscription sent. 0 disables delta-triggered emission.
+	MinDelta float64
+
+	// Cadence additionally emits a snapshot at least this often regardless
+	// of MinDelta. 0 disables cadence-triggered emission. At least one of
+	// MinDelta or Cadence must be set.
+	Cadence time.Duration
+}
+
+// metricsSubscriber is one Subscribe registration.
+type metricsSubscriber struct {
+	filter   MetricsFilter
+	ch       chan *AccuracyMetrics
+	lastSent *AccuracyMetrics
+}
+
+// DriftEvent reports that platform's Metric ("avg_error_pct" or
+// "accuracy_pct") has moved DeviationSD standard deviations away from its
+// rolling Baseline - enough for a subscriber such as SyncFlow's bid engine
+// to treat the AI prediction path as no longer trustworthy and fail over
+// to connectors.HeuristicFallbackEngine until the signal recovers.
+type DriftEvent struct {
+	Platform    string
+	Metric      string
+	Value       float64
+	Baseline    float64
+	StdDev      float64
+	DeviationSD float64
+	Timestamp   time.Time
+}
+
+// driftSubscriber is one SubscribeDrift registration.
+type driftSubscriber struct {
+	platform        string
+	stdDevThreshold float64
+	ch              chan *DriftEvent
+}
+
+// Subscribe returns a channel that receives a rolling AccuracyMetrics
+// snapshot for filter's (Platform, Window) every time filter.MinDelta or
+// filter.Cadence says it should, updated in-process as each batch is
+// flushed rather than by re-querying Postgres. The channel is closed, and
+// the subscription released, when ctx is done.
+func (a *AuditLogger) Subscribe(ctx context.Context, filter MetricsFilter) (<-chan *AccuracyMetrics, error) {
+	if _, ok := metricsWindowHalfLives[filter.Window]; !ok {
+		return nil, fmt.Errorf("audit: unknown metrics window %q", filter.Window)
+	}
+	if filter.MinDelta <= 0 && filter.Cadence <= 0 {
+		return nil, fmt.Errorf("audit: metrics subscription needs MinDelta, Cadence, or both")
+	}
+
+	sub := &metricsSubscriber{filter: filter, ch: make(chan *AccuracyMetrics, metricsSubscriberBuffer)}
+
+	a.metricsMu.Lock()
+	if a.metricsSubscribers == nil {
+		a.metricsSubscribers = make(map[string]*metricsSubscriber)
+	}
+	a.nextMetricsSubID++
+	id := fmt.Sprintf("metrics-sub-%d", a.nextMetricsSubID)
+	a.metricsSubscribers[id] = sub
+	a.metricsMu.Unlock()
+
+	if filter.Cadence > 0 {
+		go a.runMetricsCadence(ctx, id, sub)
+	}
+	go func() {
+		<-ctx.Done()
+		a.metricsMu.Lock()
+		if _, ok := a.metricsSubscribers[id]; ok {
+			delete(a.metricsSubscribers, id)
+			close(sub.ch)
+		}
+		a.metricsMu.Unlock()
+	}()
+
+	return sub.ch, nil
+}
+
+// runMetricsCadence pushes sub a snapshot every filter.Cadence even when no
+// new entries arrive for its platform, so dashboards polling on a fixed
+// interval still see a live value instead of going stale between bursts of
+// traffic.
+func (a *AuditLogger) runMetricsCadence(ctx context.Context, id string, sub *metricsSubscriber) {
+	ticker := time.NewTicker(sub.filter.Cadence)
+	defer ticker.Stop()
+
+	halfLife := metricsWindowHalfLives[sub.filter.Window]
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			a.metricsMu.Lock()
+			stats := a.windowStats[sub.filter.Platform][sub.filter.Window]
+			_, stillSubscribed := a.metricsSubscribers[id]
+			var snap *AccuracyMetrics
+			if stats != nil {
+				total, verified, within, errSum := stats.decayedValues(now, halfLife)
+				snap = buildAccuracyMetrics(sub.filter.Platform, total, verified, within, errSum)
+			}
+			a.metricsMu.Unlock()
+
+			if snap == nil || !stillSubscribed {
+				continue
+			}
+			select {
+			case sub.ch <- snap:
+			default: // slow consumer: drop this tick rather than block
+			}
+		}
+	}
+}
+
+// SubscribeDrift returns a channel that receives a DriftEvent whenever
+// platform's AvgErrorPct or AccuracyPct (measured over driftSignalWindow)
+// moves at least stdDevThreshold standard deviations from its rolling
+// baseline. platform == "" subscribes to every platform. The channel is
+// closed, and the subscription released, when ctx is done.
+func (a *AuditLogger) SubscribeDrift(ctx context.Context, platform string, stdDevThreshold float64) (<-chan *DriftEvent, error) {
+	if stdDevThreshold <= 0 {
+		return nil, fmt.Errorf("audit: SubscribeDrift requires a positive stdDevThreshold")
+	}
+
+	sub := &driftSubscriber{platform: platform, stdDevThreshold: stdDevThreshold, ch: make(chan *DriftEvent, metricsSubscriberBuffer)}
+
+	a.metricsMu.Lock()
+	if a.driftSubscribers == nil {
+		a.driftSubscribers = make(map[string]*driftSubscriber)
+	}
+	a.nextDriftSubID++
+	id := fmt.Sprintf("drift-sub-%d", a.nextDriftSubID)
+	a.driftSubscribers[id] = sub
+	a.metricsMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		a.metricsMu.Lock()
+		if _, ok := a.driftSubscribers[id]; ok {
+			delete(a.driftSubscribers, id)
+			close(sub.ch)
+		}
+		a.metricsMu.Unlock()
+	}()
+
+	return sub.ch, nil
+}
+
+// updateMetricsWindows folds a just-committed batch into every touched
+// platform's rolling windows, then emits Subscribe/SubscribeDrift
+// notifications for anything the batch moved. Called from Flush once the
+// batch is durable in Postgres - the same "only after commit" ordering
+// fanOutToSinks already uses.
+func (a *AuditLogger) updateMetricsWindows(entries []*AuditEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	now := time.Now()
+
+	a.metricsMu.Lock()
+	defer a.metricsMu.Unlock()
+
+	if a.windowStats == nil {
+		a.windowStats = make(map[string]map[MetricsWindow]*rollingWindowStats)
+	}
+	if a.baselines == nil {
+		a.baselines = make(map[string]*platformBaseline)
+	}
+
+	touched := make(map[string]bool)
+	for _, entry := range entries {
+		byWindow, ok := a.windowStats[entry.Platform]
+		if !ok {
+			byWindow = make(map[MetricsWindow]*rollingWindowStats)
+			a.windowStats[entry.Platform] = byWindow
+		}
+		for window, halfLife := range metricsWindowHalfLives {
+			stats, ok := byWindow[window]
+			if !ok {
+				stats = &rollingWindowStats{}
+				byWindow[window] = stats
+			}
+			stats.observe(now, halfLife, entry.LTVErrorPct)
+		}
+		touched[entry.Platform] = true
+	}
+
+	for platform := range touched {
+		a.emitMetricsSnapshotsLocked(platform, now)
+		a.checkDriftLocked(platform, now)
+	}
+}
+
+// emitMetricsSnapshotsLocked notifies every Subscribe registration whose
+// filter matches platform. Callers must hold a.metricsMu.
+func (a *AuditLogger) emitMetricsSnapshotsLocked(platform string, now time.Time) {
+	for _, sub := range a.metricsSubscribers {
+		if sub.filter.Platform != "" && sub.filter.Platform != platform {
+			continue
+		}
+		stats := a.windowStats[platform][sub.filter.Window]
+		if stats == nil {
+			continue
+		}
+
+		total, verified, within, errSum := stats.decayedValues(now, metricsWindowHalfLives[sub.filter.Window])
+		snap := buildAccuracyMetrics(platform, total, verified, within, errSum)
+
+		emit := sub.lastSent == nil
+		if !emit && sub.filter.MinDelta > 0 {
+			emit = math.Abs(snap.AccuracyPct-sub.lastSent.AccuracyPct) >= sub.filter.MinDelta
+		}
+		if !emit {
+			continue
+		}
+
+		sub.lastSent = snap
+		select {
+		case sub.ch <- snap:
+		default: // slow consumer: drop rather than block the flush path
+		}
+	}
+}
+
+// checkDriftLocked compares platform's driftSignalWindow reading against
+// its rolling baseline, notifies any crossing SubscribeDrift registrations,
+// then folds the reading into the baseline. Callers must hold a.metricsMu.
+func (a *AuditLogger) checkDriftLocked(platform string, now time.Time) {
+	stats := a.windowStats[platform][driftSignalWindow]
+	if stats == nil {
+		return
+	}
+
+	halfLife := metricsWindowHalfLives[driftSignalWindow]
+	_, verified, within, errSum := stats.decayedValues(now, halfLife)
+	if verified < minDriftSamples {
+		return
+	}
+	errorPct := errSum / verified
+	accuracyPct := within / verified * 100
+
+	baseline, ok := a.baselines[platform]
+	if !ok {
+		baseline = &platformBaseline{}
+		a.baselines[platform] = baseline
+	}
+
+	// Compare against the baseline before folding this reading in, so the
+	// observation that triggers drift doesn't also widen the baseline it's
+	// being measured against.
+	if baseline.initialized {
+		a.maybeEmitDriftLocked(platform, "avg_error_pct", errorPct, baseline.errorMean, math.Sqrt(baseline.errorVar), now)
+		a.maybeEmitDriftLocked(platform, "accuracy_pct", accuracyPct, baseline.accuracyMean, math.Sqrt(baseline.accuracyVar), now)
+	}
+	baseline.update(now, errorPct, accuracyPct)
+}
+
+// maybeEmitDriftLocked notifies every SubscribeDrift registration whose
+// platform and threshold match a deviation of value from baselineMean.
+// Callers must hold a.metricsMu.
+func (a *AuditLogger) maybeEmitDriftLocked(platform, metric string, value, baselineMean, stdDev float64, now time.Time) {
+	if stdDev == 0 {
+		return
+	}
+	deviation := math.Abs(value-baselineMean) / stdDev
+
+	var event *DriftEvent
+	for _, sub := range a.driftSubscribers {
+		if sub.platform != "" && sub.platform != platform {
+			continue
+		}
+		if deviation < sub.stdDevThreshold {
+			continue
+		}
+		if event == nil {
+			event = &DriftEvent{
+				Platform:    platform,
+				Metric:      metric,
+				Value:       value,
+				Baseline:    baselineMean,
+				StdDev:      stdDev,
+				DeviationSD: deviation,
+				Timestamp:   now,
+			}
+		}
+		select {
+		case sub.ch <- event:
+		default: // slow consumer: drop rather than block the flush path
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func errPct(v float64) *float64 { return &v }
+
+func TestAuditLogger_SubscribeReceivesSnapshotOnMatchingPlatform(t *testing.T) {
+	a := &AuditLogger{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := a.Subscribe(ctx, MetricsFilter{Platform: "google_ads", Window: Window1m, MinDelta: 0.01})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	a.updateMetricsWindows([]*AuditEntry{
+		{Platform: "google_ads", LTVErrorPct: errPct(5)},
+		{Platform: "meta", LTVErrorPct: errPct(50)},
+	})
+
+	select {
+	case snap := <-ch:
+		if snap.Platform != "google_ads" {
+			t.Fatalf("expected a snapshot for google_ads, got %+v", snap)
+		}
+		if snap.VerifiedPredictions != 1 || snap.AccuracyPct != 100 {
+			t.Fatalf("expected 1 verified prediction within tolerance, got %+v", snap)
+		}
+	default:
+		t.Fatal("expected a snapshot to be emitted for the subscribed platform")
+	}
+}
+
+func TestAuditLogger_SubscribeRejectsInvalidFilter(t *testing.T) {
+	a := &AuditLogger{}
+
+	if _, err := a.Subscribe(context.Background(), MetricsFilter{Window: "nonsense", MinDelta: 1}); err == nil {
+		t.Fatal("expected an error for an unknown window")
+	}
+	if _, err := a.Subscribe(context.Background(), MetricsFilter{Window: Window1m}); err == nil {
+		t.Fatal("expected an error when neither MinDelta nor Cadence is set")
+	}
+}
+
+func TestAuditLogger_SubscribeClosesChannelOnContextCancel(t *testing.T) {
+	a := &AuditLogger{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, err := a.Subscribe(ctx, MetricsFilter{Window: Window1m, MinDelta: 0.01})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("expected the channel to be closed, got a value instead")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the channel to close after cancel")
+	}
+}
+
+func TestAuditLogger_SubscribeDriftFiresOnDeviationFromBaseline(t *testing.T) {
+	a := &AuditLogger{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := a.SubscribeDrift(ctx, "google_ads", 2)
+	if err != nil {
+		t.Fatalf("SubscribeDrift failed: %v", err)
+	}
+
+	// Establish a stable low-error baseline.
+	for i := 0; i < 10; i++ {
+		a.updateMetricsWindows([]*AuditEntry{
+			{Platform: "google_ads", LTVErrorPct: errPct(2)},
+		})
+	}
+
+	select {
+	case event := <-ch:
+		t.Fatalf("did not expect drift while the signal tracks its own baseline, got %+v", event)
+	default:
+	}
+
+	// A sustained swing in error should eventually read as drift from the
+	// baseline established above.
+	var fired *DriftEvent
+	for i := 0; i < 20 && fired == nil; i++ {
+		a.updateMetricsWindows([]*AuditEntry{
+			{Platform: "google_ads", LTVErrorPct: errPct(200)},
+		})
+		select {
+		case event := <-ch:
+			fired = event
+		default:
+		}
+	}
+
+	if fired == nil {
+		t.Fatal("expected a drift event once the signal diverged sharply from its baseline")
+	}
+	if fired.Platform != "google_ads" {
+		t.Fatalf("expected the drift event to name google_ads, got %q", fired.Platform)
+	}
+}
+
+func TestAuditLogger_SubscribeDriftRejectsNonPositiveThreshold(t *testing.T) {
+	a := &AuditLogger{}
+	if _, err := a.SubscribeDrift(context.Background(), "google_ads", 0); err == nil {
+		t.Fatal("expected an error for a non-positive stdDevThreshold")
+	}
+}
+
+func TestRollingWindowStats_DecaysOlderObservations(t *testing.T) {
+	s := &rollingWindowStats{}
+	start := time.Now()
+
+	s.observe(start, time.Minute, errPct(0))
+	if got := s.totalCount; got < 0.99 || got > 1.01 {
+		t.Fatalf("expected totalCount ~1 right after the first observation, got %v", got)
+	}
+
+	total, _, _, _ := s.decayedValues(start.Add(time.Minute), time.Minute)
+	if total > 0.6 {
+		t.Fatalf("expected the count to have decayed by one half-life, got %v", total)
+	}
+}
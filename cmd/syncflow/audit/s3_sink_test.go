@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// roundTripperFunc lets a test stand in for a real HTTP transport without
+// going over the network, since S3ParquetSink always targets the real AWS
+// virtual-hosted endpoint.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestS3ParquetSink_WriteBatchPartitionsByPlatformDateHour(t *testing.T) {
+	var mu sync.Mutex
+	var requests []*http.Request
+	var bodies [][]*AuditEntry
+
+	sink := NewS3ParquetSink("bucket", "audit", "us-east-1", "AKIAEXAMPLE", "secret")
+	sink.httpClient = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("expected gzip body, got error: %v", err)
+		}
+		var entries []*AuditEntry
+		decoder := json.NewDecoder(gz)
+		for {
+			var entry AuditEntry
+			if decodeErr := decoder.Decode(&entry); decodeErr != nil {
+				break
+			}
+			entries = append(entries, &entry)
+		}
+
+		mu.Lock()
+		requests = append(requests, req)
+		bodies = append(bodies, entries)
+		mu.Unlock()
+
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}
+
+	sameHour := time.Date(2026, 7, 30, 14, 5, 0, 0, time.UTC)
+	differentHour := time.Date(2026, 7, 30, 15, 5, 0, 0, time.UTC)
+	entries := []*AuditEntry{
+		{RequestID: "req-1", Platform: "amazon", Timestamp: sameHour},
+		{RequestID: "req-2", Platform: "amazon", Timestamp: sameHour},
+		{RequestID: "req-3", Platform: "amazon", Timestamp: differentHour},
+		{RequestID: "req-4", Platform: "google", Timestamp: sameHour},
+	}
+
+	if err := sink.WriteBatch(context.Background(), entries); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 partitions (amazon/14, amazon/15, google/14), got %d", len(requests))
+	}
+	if !strings.Contains(requests[0].URL.Path, "platform=amazon/date=2026-07-30/hour=14") {
+		t.Fatalf("expected first object under the amazon/14 partition, got %s", requests[0].URL.Path)
+	}
+	if !strings.Contains(requests[1].URL.Path, "platform=amazon/date=2026-07-30/hour=15") {
+		t.Fatalf("expected second object under the amazon/15 partition, got %s", requests[1].URL.Path)
+	}
+	if !strings.Contains(requests[2].URL.Path, "platform=google/date=2026-07-30/hour=14") {
+		t.Fatalf("expected third object under the google/14 partition, got %s", requests[2].URL.Path)
+	}
+	if len(bodies[0]) != 2 {
+		t.Fatalf("expected the amazon/14 object to carry both same-hour entries, got %d", len(bodies[0]))
+	}
+
+	auth := requests[0].Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIAEXAMPLE/") {
+		t.Fatalf("expected a SigV4 Authorization header, got %q", auth)
+	}
+}
+
+func TestS3ParquetSink_WriteBatchSkipsEmptyEntries(t *testing.T) {
+	called := false
+	sink := NewS3ParquetSink("bucket", "", "us-east-1", "AKIAEXAMPLE", "secret")
+	sink.httpClient = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}
+
+	if err := sink.WriteBatch(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called {
+		t.Fatal("expected no request to be made for an empty batch")
+	}
+}
+
+func TestS3ParquetSink_WriteBatchReturnsErrorOnNonSuccessStatus(t *testing.T) {
+	sink := NewS3ParquetSink("bucket", "", "us-east-1", "AKIAEXAMPLE", "secret")
+	sink.httpClient = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusForbidden, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}
+
+	err := sink.WriteBatch(context.Background(), []*AuditEntry{{RequestID: "req-1", Platform: "amazon", Timestamp: time.Now()}})
+	if err == nil {
+		t.Fatal("expected an error for a 403 response")
+	}
+}
@@ -0,0 +1,13 @@
+package audit
+
+import "context"
+
+// Sink persists a flushed batch of AuditEntry somewhere durable, beyond the
+// Postgres/TimescaleDB write Flush always performs. WriteBatch is called
+// once per Flush with every entry in that batch, in the same order they
+// were written; implementations that care about the hash chain should rely
+// on PrevHash/EntryHash already being stamped by the time WriteBatch runs.
+type Sink interface {
+	WriteBatch(ctx context.Context, entries []*AuditEntry) error
+	Close() error
+}
@@ -0,0 +1,240 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// SinkRegistration wires one additional Sink into AuditLogger's fan-out,
+// alongside the Postgres/TimescaleDB write Flush always performs.
+type SinkRegistration struct {
+	// Name identifies the sink in health snapshots, dead-letter records,
+	// and log lines; not interpreted otherwise.
+	Name string
+	Sink Sink
+
+	// Critical, when true, makes this sink's exhausted retries fail the
+	// whole Flush call - the same guarantee the hardwired Postgres write
+	// already gives callers. False (the default) makes failures
+	// best-effort: they're recorded via DeadLetter and logged, but never
+	// stop Flush from returning successfully, so a slow or down ClickHouse
+	// sink can never block the Postgres commit or any other sink.
+	Critical bool
+
+	// RetryPolicy governs WriteBatch retries for this sink; nil falls back
+	// to shield.DefaultRetryPolicy().
+	RetryPolicy *shield.RetryPolicy
+}
+
+// DeadLetterSink records a best-effort sink's batch and final error once
+// its retries are exhausted, so the batch isn't simply lost.
+type DeadLetterSink interface {
+	Record(sinkName string, entries []*AuditEntry, err error)
+}
+
+// MemoryDeadLetter is a bounded in-memory DeadLetterSink - good enough for
+// an operator inspecting recent failures or a metrics exporter counting
+// them; durable replay of dropped batches is left to whatever consumes
+// Entries.
+type MemoryDeadLetter struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries []DeadLetterEntry
+}
+
+// DeadLetterEntry is one best-effort sink failure MemoryDeadLetter retained.
+type DeadLetterEntry struct {
+	SinkName   string
+	Entries    []*AuditEntry
+	Err        error
+	RecordedAt time.Time
+}
+
+// NewMemoryDeadLetter creates a MemoryDeadLetter retaining at most
+// maxEntries records, dropping the oldest once full.
+func NewMemoryDeadLetter(maxEntries int) *MemoryDeadLetter {
+	return &MemoryDeadLetter{maxEntries: maxEntries}
+}
+
+// Record implements DeadLetterSink.
+func (m *MemoryDeadLetter) Record(sinkName string, entries []*AuditEntry, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = append(m.entries, DeadLetterEntry{
+		SinkName:   sinkName,
+		Entries:    entries,
+		Err:        err,
+		RecordedAt: time.Now(),
+	})
+	if overflow := len(m.entries) - m.maxEntries; overflow > 0 {
+		m.entries = m.entries[overflow:]
+	}
+}
+
+// Entries returns a copy of the currently retained dead-letter records.
+func (m *MemoryDeadLetter) Entries() []DeadLetterEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]DeadLetterEntry, len(m.entries))
+	copy(out, m.entries)
+	return out
+}
+
+// SinkHealth is a point-in-time snapshot of one registered sink's recent
+// WriteBatch outcomes.
+type SinkHealth struct {
+	Name             string
+	Critical         bool
+	Healthy          bool
+	ConsecutiveFails int
+	LastError        string
+	LastSuccess      time.Time
+	LastAttempt      time.Time
+}
+
+// sinkHealthState is the mutable health record backing one SinkHealth
+// snapshot; kept separate from SinkRegistration so health tracking survives
+// across Flush calls without being reset by the caller re-reading config.
+type sinkHealthState struct {
+	mu               sync.Mutex
+	consecutiveFails int
+	lastErr          error
+	lastSuccess      time.Time
+	lastAttempt      time.Time
+}
+
+func (s *sinkHealthState) recordSuccess() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consecutiveFails = 0
+	s.lastErr = nil
+	s.lastSuccess = time.Now()
+	s.lastAttempt = s.lastSuccess
+}
+
+func (s *sinkHealthState) recordFailure(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consecutiveFails++
+	s.lastErr = err
+	s.lastAttempt = time.Now()
+}
+
+func (s *sinkHealthState) snapshot(name string, critical bool) SinkHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	health := SinkHealth{
+		Name:             name,
+		Critical:         critical,
+		Healthy:          s.consecutiveFails == 0,
+		ConsecutiveFails: s.consecutiveFails,
+		LastSuccess:      s.lastSuccess,
+		LastAttempt:      s.lastAttempt,
+	}
+	if s.lastErr != nil {
+		health.LastError = s.lastErr.Error()
+	}
+	return health
+}
+
+// sinkHealthFor returns (creating if necessary) the health tracker for
+// name. a.sinkHealthMu guards the map itself; each entry's own mutex guards
+// its fields, so concurrent fan-out across sinks never contends on a
+// single lock.
+func (a *AuditLogger) sinkHealthFor(name string) *sinkHealthState {
+	a.sinkHealthMu.Lock()
+	defer a.sinkHealthMu.Unlock()
+	if a.sinkHealthByName == nil {
+		a.sinkHealthByName = make(map[string]*sinkHealthState)
+	}
+	state, ok := a.sinkHealthByName[name]
+	if !ok {
+		state = &sinkHealthState{}
+		a.sinkHealthByName[name] = state
+	}
+	return state
+}
+
+// SinkHealthSnapshot returns the current health of every registered sink,
+// in registration order.
+func (a *AuditLogger) SinkHealthSnapshot() []SinkHealth {
+	snapshot := make([]SinkHealth, 0, len(a.Sinks))
+	for _, reg := range a.Sinks {
+		snapshot = append(snapshot, a.sinkHealthFor(reg.Name).snapshot(reg.Name, reg.Critical))
+	}
+	return snapshot
+}
+
+// fanOutToSinks writes entries to every registered sink concurrently, after
+// the Postgres transaction in Flush has already committed, so a slow
+// best-effort sink can never delay (or, worse, fail) the authoritative
+// write. It returns a combined error only if at least one Critical sink
+// exhausted its retries; best-effort failures are routed to DeadLetter and
+// logged instead.
+func (a *AuditLogger) fanOutToSinks(ctx context.Context, entries []*AuditEntry) error {
+	if len(a.Sinks) == 0 {
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	criticalErrs := make([]error, len(a.Sinks))
+
+	for i, reg := range a.Sinks {
+		wg.Add(1)
+		go func(i int, reg SinkRegistration) {
+			defer wg.Done()
+			criticalErrs[i] = a.writeToSinkWithRetry(ctx, reg, entries)
+		}(i, reg)
+	}
+	wg.Wait()
+
+	var combined error
+	for i, err := range criticalErrs {
+		if err == nil {
+			continue
+		}
+		if a.Sinks[i].Critical {
+			combined = fmt.Errorf("critical sink %q: %w", a.Sinks[i].Name, err)
+		}
+	}
+	return combined
+}
+
+// writeToSinkWithRetry runs reg.Sink.WriteBatch under reg.RetryPolicy (or
+// shield.DefaultRetryPolicy if unset), updating health on every outcome. A
+// non-nil return means the sink is Critical and its retries are exhausted;
+// a best-effort sink's exhausted retries are recorded to DeadLetter here
+// and never returned.
+func (a *AuditLogger) writeToSinkWithRetry(ctx context.Context, reg SinkRegistration, entries []*AuditEntry) error {
+	policy := reg.RetryPolicy
+	if policy == nil {
+		policy = shield.DefaultRetryPolicy()
+	}
+	health := a.sinkHealthFor(reg.Name)
+
+	_, _, err := policy.ExecuteWithRetry(ctx, func(ctx context.Context, attempt int) (interface{}, error) {
+		return nil, reg.Sink.WriteBatch(ctx, entries)
+	}, shield.DefaultIsRetryable)
+
+	if err == nil {
+		health.recordSuccess()
+		return nil
+	}
+	health.recordFailure(err)
+
+	if reg.Critical {
+		return err
+	}
+
+	fmt.Printf("⚠️  audit sink %q exhausted retries (best-effort, continuing): %v\n", reg.Name, err)
+	if a.DeadLetter != nil {
+		a.DeadLetter.Record(reg.Name, entries, err)
+	}
+	return nil
+}
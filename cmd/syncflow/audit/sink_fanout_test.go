@@ -0,0 +1,138 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// fakeSink is a Sink whose WriteBatch outcome is controlled by the test.
+type fakeSink struct {
+	mu       sync.Mutex
+	fail     bool
+	calls    int
+	received []*AuditEntry
+}
+
+func (f *fakeSink) WriteBatch(ctx context.Context, entries []*AuditEntry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.received = entries
+	if f.fail {
+		return errors.New("fake sink failure")
+	}
+	return nil
+}
+
+func (f *fakeSink) Close() error { return nil }
+
+func noRetryPolicy() *shield.RetryPolicy {
+	policy := shield.DefaultRetryPolicy()
+	policy.MaxAttempts = 1
+	return policy
+}
+
+func TestFanOutToSinks_BestEffortFailureDoesNotFailFlush(t *testing.T) {
+	failing := &fakeSink{fail: true}
+	deadLetter := NewMemoryDeadLetter(10)
+	a := &AuditLogger{
+		Sinks: []SinkRegistration{
+			{Name: "clickhouse", Sink: failing, Critical: false, RetryPolicy: noRetryPolicy()},
+		},
+		DeadLetter: deadLetter,
+	}
+
+	entries := []*AuditEntry{{RequestID: "req-1"}}
+	if err := a.fanOutToSinks(context.Background(), entries); err != nil {
+		t.Fatalf("expected best-effort sink failure to be swallowed, got %v", err)
+	}
+
+	recorded := deadLetter.Entries()
+	if len(recorded) != 1 || recorded[0].SinkName != "clickhouse" {
+		t.Fatalf("expected the failure to be dead-lettered under clickhouse, got %+v", recorded)
+	}
+
+	health := a.SinkHealthSnapshot()
+	if len(health) != 1 || health[0].Healthy || health[0].ConsecutiveFails != 1 {
+		t.Fatalf("expected unhealthy sink with 1 consecutive failure, got %+v", health)
+	}
+}
+
+func TestFanOutToSinks_CriticalFailureFailsFlush(t *testing.T) {
+	failing := &fakeSink{fail: true}
+	a := &AuditLogger{
+		Sinks: []SinkRegistration{
+			{Name: "postgres-replica", Sink: failing, Critical: true, RetryPolicy: noRetryPolicy()},
+		},
+	}
+
+	err := a.fanOutToSinks(context.Background(), []*AuditEntry{{RequestID: "req-1"}})
+	if err == nil {
+		t.Fatal("expected critical sink failure to be returned")
+	}
+}
+
+func TestFanOutToSinks_SuccessUpdatesHealthAndSkipsDeadLetter(t *testing.T) {
+	ok := &fakeSink{}
+	deadLetter := NewMemoryDeadLetter(10)
+	a := &AuditLogger{
+		Sinks:      []SinkRegistration{{Name: "kafka", Sink: ok, Critical: false}},
+		DeadLetter: deadLetter,
+	}
+
+	entries := []*AuditEntry{{RequestID: "req-1"}, {RequestID: "req-2"}}
+	if err := a.fanOutToSinks(context.Background(), entries); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if ok.calls != 1 || len(ok.received) != 2 {
+		t.Fatalf("expected WriteBatch to receive both entries once, got calls=%d received=%d", ok.calls, len(ok.received))
+	}
+	if len(deadLetter.Entries()) != 0 {
+		t.Fatalf("expected no dead-letter entries on success, got %+v", deadLetter.Entries())
+	}
+	health := a.SinkHealthSnapshot()
+	if len(health) != 1 || !health[0].Healthy || health[0].LastSuccess.IsZero() {
+		t.Fatalf("expected healthy sink with a recorded success, got %+v", health)
+	}
+}
+
+func TestMemoryDeadLetter_DropsOldestWhenFull(t *testing.T) {
+	deadLetter := NewMemoryDeadLetter(2)
+	deadLetter.Record("a", nil, errors.New("err-a"))
+	deadLetter.Record("b", nil, errors.New("err-b"))
+	deadLetter.Record("c", nil, errors.New("err-c"))
+
+	entries := deadLetter.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected bounded dead-letter buffer, got %d entries", len(entries))
+	}
+	if entries[0].SinkName != "b" || entries[1].SinkName != "c" {
+		t.Fatalf("expected oldest entry dropped, got %+v", entries)
+	}
+}
+
+func TestSinkHealthState_RecoversAfterSuccessFollowingFailures(t *testing.T) {
+	state := &sinkHealthState{}
+	state.recordFailure(errors.New("boom"))
+	state.recordFailure(errors.New("boom again"))
+
+	snapshot := state.snapshot("s3", false)
+	if snapshot.ConsecutiveFails != 2 || snapshot.Healthy {
+		t.Fatalf("expected 2 consecutive failures and unhealthy state, got %+v", snapshot)
+	}
+
+	state.recordSuccess()
+	snapshot = state.snapshot("s3", false)
+	if !snapshot.Healthy || snapshot.ConsecutiveFails != 0 || snapshot.LastError != "" {
+		t.Fatalf("expected a success to reset failure tracking, got %+v", snapshot)
+	}
+	if time.Since(snapshot.LastSuccess) > time.Second {
+		t.Fatalf("expected LastSuccess to be set to roughly now, got %v", snapshot.LastSuccess)
+	}
+}
@@ -0,0 +1,149 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ServeSSE serves entry as a Server-Sent Events stream to r, filtered by
+// the platform/bid_source/circuit_state/min_ltv/max_ltv query parameters,
+// until the client disconnects. authToken must be non-empty and match the
+// request's "Authorization: Bearer <token>" header or the subscription is
+// rejected; this fails closed if authToken was never configured.
+func (s *StreamSink) ServeSSE(w http.ResponseWriter, r *http.Request, authToken string) {
+	if !authorizeStreamSubscriber(r, authToken) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	filter, err := parseStreamFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ch, cancel := s.Subscribe(filter)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case event, open := <-ch:
+			if !open {
+				return
+			}
+			payload, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\ndata: %s\n\n", event.RequestID, payload)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// ServeWebSocket upgrades r to a websocket connection and streams entries
+// to it as JSON text frames, filtered and authenticated exactly like
+// ServeSSE, until the client disconnects or sends a close frame.
+func (s *StreamSink) ServeWebSocket(w http.ResponseWriter, r *http.Request, authToken string) {
+	if !authorizeStreamSubscriber(r, authToken) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filter, err := parseStreamFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, rw, err := upgradeWebSocket(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer conn.Close()
+
+	ch, cancel := s.Subscribe(filter)
+	defer cancel()
+
+	closed := make(chan struct{})
+	go watchForWebSocketClose(rw, closed)
+
+	for {
+		select {
+		case event, open := <-ch:
+			if !open {
+				return
+			}
+			payload, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if err := writeWebSocketText(rw, payload); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// authorizeStreamSubscriber requires a bearer token matching authToken.
+// An unconfigured (empty) authToken fails closed - nobody can subscribe -
+// rather than silently allowing anonymous access.
+func authorizeStreamSubscriber(r *http.Request, authToken string) bool {
+	if authToken == "" {
+		return false
+	}
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return false
+	}
+	return strings.TrimPrefix(header, prefix) == authToken
+}
+
+// parseStreamFilter builds a StreamFilter from query parameters:
+// platform, bid_source, circuit_state, min_ltv, max_ltv.
+func parseStreamFilter(r *http.Request) (StreamFilter, error) {
+	q := r.URL.Query()
+	filter := StreamFilter{
+		Platform:     q.Get("platform"),
+		BidSource:    q.Get("bid_source"),
+		CircuitState: q.Get("circuit_state"),
+	}
+
+	if raw := q.Get("min_ltv"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return StreamFilter{}, fmt.Errorf("invalid min_ltv: %w", err)
+		}
+		filter.MinPredictedLTV = &v
+	}
+	if raw := q.Get("max_ltv"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return StreamFilter{}, fmt.Errorf("invalid max_ltv: %w", err)
+		}
+		filter.MaxPredictedLTV = &v
+	}
+
+	return filter, nil
+}
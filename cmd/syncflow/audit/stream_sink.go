@@ -0,0 +1,159 @@
+package audit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/observability"
+)
+
+// StreamFilter narrows a subscriber's feed to the events it cares about;
+// the zero value for any field means "no restriction" on that dimension.
+type StreamFilter struct {
+	Platform        string
+	BidSource       string
+	CircuitState    string
+	MinPredictedLTV *float64
+	MaxPredictedLTV *float64
+}
+
+// matches reports whether entry passes every restriction configured on f.
+func (f StreamFilter) matches(entry *AuditEntry) bool {
+	if f.Platform != "" && entry.Platform != f.Platform {
+		return false
+	}
+	if f.BidSource != "" && entry.BidSource != f.BidSource {
+		return false
+	}
+	if f.CircuitState != "" && entry.CircuitState != f.CircuitState {
+		return false
+	}
+	if f.MinPredictedLTV != nil && entry.PredictedLTV < *f.MinPredictedLTV {
+		return false
+	}
+	if f.MaxPredictedLTV != nil && entry.PredictedLTV > *f.MaxPredictedLTV {
+		return false
+	}
+	return true
+}
+
+// MomentumSnapshot is a derived rollup of recent AuditEntry activity for a
+// platform - the same totals the console-only LTVMomentumTracker demo
+// prints - delivered as a structured event instead of a log line.
+type MomentumSnapshot struct {
+	Platform         string  `json:"platform"`
+	TotalPredictions int64   `json:"total_predictions"`
+	AvgErrorPct      float64 `json:"avg_error_pct"`
+	FallbackRate     float64 `json:"fallback_rate"`
+}
+
+// StreamEvent is the JSON payload fanned out to stream subscribers, keyed
+// on RequestID so downstream dashboards can join it against the reconciled
+// ActualLTV event delivered later for the same request.
+type StreamEvent struct {
+	RequestID string            `json:"request_id"`
+	Entry     *AuditEntry       `json:"entry,omitempty"`
+	Momentum  *MomentumSnapshot `json:"momentum,omitempty"`
+}
+
+// subscriber is one filtered, bounded delivery channel.
+type subscriber struct {
+	filter StreamFilter
+	ch     chan StreamEvent
+}
+
+// StreamSink fans every written AuditEntry (plus derived MomentumSnapshots)
+// out to any number of filtered subscribers. Each subscriber has its own
+// bounded ring buffer so one slow consumer can't block the others or the
+// publisher; once a subscriber's buffer is full, the oldest queued event is
+// dropped to make room and the drop is recorded as a metric.
+type StreamSink struct {
+	mu          sync.RWMutex
+	subscribers map[string]*subscriber
+	bufferSize  int
+	nextID      int64
+}
+
+// NewStreamSink creates a sink whose subscribers each buffer up to
+// bufferSize undelivered events before the oldest is dropped.
+func NewStreamSink(bufferSize int) *StreamSink {
+	return &StreamSink{
+		subscribers: make(map[string]*subscriber),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe registers a new filtered subscriber and returns its event
+// channel and a cancel func that must be called to release it.
+func (s *StreamSink) Subscribe(filter StreamFilter) (<-chan StreamEvent, func()) {
+	s.mu.Lock()
+	s.nextID++
+	id := fmt.Sprintf("sub-%d", s.nextID)
+	sub := &subscriber{filter: filter, ch: make(chan StreamEvent, s.bufferSize)}
+	s.subscribers[id] = sub
+	count := len(s.subscribers)
+	s.mu.Unlock()
+
+	observability.Default.SetStreamSubscribers(count)
+
+	cancel := func() {
+		s.mu.Lock()
+		if _, ok := s.subscribers[id]; ok {
+			delete(s.subscribers, id)
+			close(sub.ch)
+		}
+		remaining := len(s.subscribers)
+		s.mu.Unlock()
+		observability.Default.SetStreamSubscribers(remaining)
+	}
+
+	return sub.ch, cancel
+}
+
+// Publish fans entry out to every subscriber whose filter matches it.
+func (s *StreamSink) Publish(entry *AuditEntry) {
+	s.broadcast(StreamEvent{RequestID: entry.RequestID, Entry: entry}, func(f StreamFilter) bool {
+		return f.matches(entry)
+	})
+}
+
+// PublishMomentum fans a derived momentum rollup out to every subscriber.
+// Momentum snapshots summarize many entries at once, so they bypass the
+// per-entry filter criteria and reach every subscriber.
+func (s *StreamSink) PublishMomentum(snapshot MomentumSnapshot) {
+	event := StreamEvent{
+		RequestID: fmt.Sprintf("momentum-%s-%d", snapshot.Platform, time.Now().UnixNano()),
+		Momentum:  &snapshot,
+	}
+	s.broadcast(event, func(StreamFilter) bool { return true })
+}
+
+func (s *StreamSink) broadcast(event StreamEvent, match func(StreamFilter) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, sub := range s.subscribers {
+		if !match(sub.filter) {
+			continue
+		}
+
+		select {
+		case sub.ch <- event:
+			continue
+		default:
+		}
+
+		// Buffer full: drop the oldest queued event to make room rather
+		// than blocking the publisher on a slow consumer.
+		select {
+		case <-sub.ch:
+			observability.Default.RecordStreamDrop()
+		default:
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
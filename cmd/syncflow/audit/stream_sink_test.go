@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamSink_PublishDeliversToMatchingSubscriberOnly(t *testing.T) {
+	sink := NewStreamSink(4)
+
+	amazonOnly := StreamFilter{Platform: "amazon"}
+	ch, cancel := sink.Subscribe(amazonOnly)
+	defer cancel()
+
+	sink.Publish(&AuditEntry{RequestID: "req-1", Platform: "amazon"})
+	sink.Publish(&AuditEntry{RequestID: "req-2", Platform: "google"})
+
+	select {
+	case event := <-ch:
+		if event.RequestID != "req-1" {
+			t.Fatalf("expected req-1 to pass the amazon filter, got %q", event.RequestID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for matching event")
+	}
+
+	select {
+	case event := <-ch:
+		t.Fatalf("expected non-matching platform to be filtered out, got %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestStreamSink_LTVBoundsFilter(t *testing.T) {
+	sink := NewStreamSink(4)
+	min := 10.0
+	max := 100.0
+	ch, cancel := sink.Subscribe(StreamFilter{MinPredictedLTV: &min, MaxPredictedLTV: &max})
+	defer cancel()
+
+	sink.Publish(&AuditEntry{RequestID: "too-low", PredictedLTV: 5})
+	sink.Publish(&AuditEntry{RequestID: "in-range", PredictedLTV: 50})
+	sink.Publish(&AuditEntry{RequestID: "too-high", PredictedLTV: 500})
+
+	select {
+	case event := <-ch:
+		if event.RequestID != "in-range" {
+			t.Fatalf("expected only the in-range entry to be delivered, got %q", event.RequestID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for in-range event")
+	}
+
+	select {
+	case event := <-ch:
+		t.Fatalf("expected out-of-bounds entries to be filtered out, got %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestStreamSink_FullBufferDropsOldestRatherThanBlocking(t *testing.T) {
+	sink := NewStreamSink(2)
+	ch, cancel := sink.Subscribe(StreamFilter{})
+	defer cancel()
+
+	sink.Publish(&AuditEntry{RequestID: "1"})
+	sink.Publish(&AuditEntry{RequestID: "2"})
+	sink.Publish(&AuditEntry{RequestID: "3"}) // buffer full, should drop "1"
+
+	first := <-ch
+	second := <-ch
+	if first.RequestID != "2" || second.RequestID != "3" {
+		t.Fatalf("expected the oldest event to be dropped, got %q then %q", first.RequestID, second.RequestID)
+	}
+}
+
+func TestStreamSink_CancelClosesChannel(t *testing.T) {
+	sink := NewStreamSink(4)
+	ch, cancel := sink.Subscribe(StreamFilter{})
+	cancel()
+
+	if _, open := <-ch; open {
+		t.Fatal("expected channel to be closed after cancel")
+	}
+}
+
+func TestStreamSink_PublishMomentumReachesEverySubscriberRegardlessOfFilter(t *testing.T) {
+	sink := NewStreamSink(4)
+	ch, cancel := sink.Subscribe(StreamFilter{Platform: "amazon"})
+	defer cancel()
+
+	sink.PublishMomentum(MomentumSnapshot{Platform: "google", TotalPredictions: 10})
+
+	select {
+	case event := <-ch:
+		if event.Momentum == nil || event.Momentum.Platform != "google" {
+			t.Fatalf("expected momentum snapshot to bypass the platform filter, got %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for momentum event")
+	}
+}
+
+func TestStreamSink_ServeSSERequiresBearerToken(t *testing.T) {
+	sink := NewStreamSink(4)
+	req := httptest.NewRequest(http.MethodGet, "/stream/audit/sse", nil)
+	rec := httptest.NewRecorder()
+
+	sink.ServeSSE(rec, req, "secret-token")
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a bearer token, got %d", rec.Code)
+	}
+}
+
+func TestStreamSink_ServeSSEStreamsMatchingEntries(t *testing.T) {
+	sink := NewStreamSink(4)
+	req := httptest.NewRequest(http.MethodGet, "/stream/audit/sse?platform=amazon", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		sink.ServeSSE(rec, req, "secret-token")
+		close(done)
+	}()
+
+	// Give ServeSSE a moment to subscribe before publishing.
+	time.Sleep(20 * time.Millisecond)
+	sink.Publish(&AuditEntry{RequestID: "req-1", Platform: "amazon"})
+	time.Sleep(20 * time.Millisecond)
+
+	if !strings.Contains(rec.Body.String(), `"request_id":"req-1"`) {
+		t.Fatalf("expected SSE body to contain the published entry, got %q", rec.Body.String())
+	}
+}
+
+func TestStreamSink_ServeSSERejectsWrongToken(t *testing.T) {
+	sink := NewStreamSink(4)
+	req := httptest.NewRequest(http.MethodGet, "/stream/audit/sse", nil)
+	req.Header.Set("Authorization", "Bearer wrong-token")
+	rec := httptest.NewRecorder()
+
+	sink.ServeSSE(rec, req, "secret-token")
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a mismatched bearer token, got %d", rec.Code)
+	}
+}
+
+func TestStreamSink_EmptyAuthTokenFailsClosed(t *testing.T) {
+	sink := NewStreamSink(4)
+	req := httptest.NewRequest(http.MethodGet, "/stream/audit/sse", nil)
+	req.Header.Set("Authorization", "Bearer anything")
+	rec := httptest.NewRecorder()
+
+	sink.ServeSSE(rec, req, "")
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected an unconfigured auth token to fail closed, got status %d", rec.Code)
+	}
+}
+
+func TestParseStreamFilter_InvalidLTVBoundsRejected(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/stream/audit/sse?min_ltv=not-a-number", nil)
+	if _, err := parseStreamFilter(req); err == nil {
+		t.Fatal("expected an invalid min_ltv to be rejected")
+	}
+}
+
+func TestStreamEvent_JSONKeyedOnRequestID(t *testing.T) {
+	event := StreamEvent{RequestID: "req-42", Entry: &AuditEntry{RequestID: "req-42", Platform: "amazon"}}
+	b, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if !strings.Contains(string(b), `"request_id":"req-42"`) {
+		t.Fatalf("expected top-level request_id in JSON, got %s", b)
+	}
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// hasTimescaleDB reports whether db's connected database has the
+// timescaledb extension loaded, attempting CREATE EXTENSION IF NOT EXISTS
+// if it doesn't. A vanilla Postgres install - one where the extension
+// isn't even available to install - returns false rather than an error, so
+// NewAuditLogger can fall back to the unpartitioned schema
+// GetAccuracyMetrics has always supported.
+func hasTimescaleDB(ctx context.Context, db *sql.DB) bool {
+	var exists bool
+	if err := db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb')
+	`).Scan(&exists); err == nil && exists {
+		return true
+	}
+
+	if _, err := db.ExecContext(ctx, `CREATE EXTENSION IF NOT EXISTS timescaledb`); err != nil {
+		return false
+	}
+	return true
+}
+
+// migrateTimescaleSchema converts audit_log into a Timescale hypertable
+// partitioned on timestamp with a 1-hour chunk interval, and materializes
+// audit_accuracy_1m - the continuous aggregate GetAccuracyMetrics reads
+// instead of scanning audit_log directly once a.hasTimescale is true.
+// NewAuditLogger calls this on every startup rather than just the first,
+// so every step here must be safe to re-run against an already-migrated
+// database.
+func migrateTimescaleSchema(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `
+		SELECT create_hypertable('audit_log', 'timestamp',
+			chunk_time_interval => INTERVAL '1 hour',
+			if_not_exists => TRUE,
+			migrate_data => TRUE)
+	`); err != nil {
+		return fmt.Errorf("converting audit_log to a hypertable: %w", err)
+	}
+
+	var aggregateExists bool
+	if err := db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM timescaledb_information.continuous_aggregates
+			WHERE view_name = 'audit_accuracy_1m'
+		)
+	`).Scan(&aggregateExists); err != nil {
+		return fmt.Errorf("checking for audit_accuracy_1m: %w", err)
+	}
+	if aggregateExists {
+		return nil
+	}
+
+	if _, err := db.ExecContext(ctx, `
+		CREATE MATERIALIZED VIEW audit_accuracy_1m
+		WITH (timescaledb.continuous) AS
+		SELECT
+			platform,
+			time_bucket('1 minute', timestamp) AS bucket_1m,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE actual_ltv IS NOT NULL) AS verified,
+			SUM(ABS(ltv_error_pct)) AS sum_abs_err,
+			COUNT(*) FILTER (WHERE ABS(ltv_error_pct) <= 10) AS within_tol
+		FROM audit_log
+		GROUP BY platform, bucket_1m
+		WITH NO DATA
+	`); err != nil {
+		return fmt.Errorf("creating audit_accuracy_1m continuous aggregate: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, `
+		SELECT add_continuous_aggregate_policy('audit_accuracy_1m',
+			start_offset => INTERVAL '1 hour',
+			end_offset => INTERVAL '5 seconds',
+			schedule_interval => INTERVAL '30 seconds')
+	`); err != nil {
+		return fmt.Errorf("scheduling audit_accuracy_1m refresh policy: %w", err)
+	}
+
+	return nil
+}
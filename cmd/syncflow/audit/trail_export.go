@@ -0,0 +1,239 @@
+package audit
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// streamPageSize is how many rows StreamAuditTrail fetches per keyset page -
+// large enough to amortize round trips, small enough to keep any one query
+// cheap against a live audit_log.
+const streamPageSize = 500
+
+// StreamAuditTrail pages through every audit_log row matching filters,
+// newest first, and pushes them down the returned channel as they're
+// scanned - unlike GetAuditTrail, it has no row cap, making it the right
+// tool for compliance exports covering millions of bids. Both channels are
+// closed once the trail is exhausted, filters.Limit is ignored (there is no
+// cap to apply), or ctx is cancelled, whichever comes first; at most one
+// error is ever sent before the error channel closes.
+func (a *AuditLogger) StreamAuditTrail(ctx context.Context, filters AuditFilters) (<-chan *AuditEntry, <-chan error) {
+	entries := make(chan *AuditEntry, streamPageSize)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(entries)
+		defer close(errs)
+
+		var lastTimestamp time.Time
+		var lastRequestID string
+		haveCursor := false
+
+		for {
+			page, err := a.fetchTrailPage(ctx, filters, haveCursor, lastTimestamp, lastRequestID)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if len(page) == 0 {
+				return
+			}
+
+			for _, entry := range page {
+				select {
+				case entries <- entry:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			last := page[len(page)-1]
+			lastTimestamp = last.Timestamp
+			lastRequestID = last.RequestID
+			haveCursor = true
+
+			if len(page) < streamPageSize {
+				return
+			}
+		}
+	}()
+
+	return entries, errs
+}
+
+// fetchTrailPage runs one keyset page of filters, ordered newest-first,
+// continuing strictly before (afterTimestamp, afterRequestID) when
+// haveCursor is set.
+func (a *AuditLogger) fetchTrailPage(ctx context.Context, filters AuditFilters, haveCursor bool, afterTimestamp time.Time, afterRequestID string) ([]*AuditEntry, error) {
+	query := `
+		SELECT
+			timestamp, request_id, customer_id, campaign_id,
+			predicted_ltv, confidence, ltv_lower_bound, ltv_upper_bound, model_version,
+			bid_amount, bid_source, platform, platform_bid_id,
+			bid_status, circuit_state, used_fallback,
+			actual_ltv, actual_ltv_timestamp, ltv_error_pct,
+			execution_time_ms, inference_time_us,
+			campaign_budget, current_spend, remaining_budget,
+			metadata, explanation, prev_hash, entry_hash
+		FROM audit_log
+		WHERE 1=1
+	`
+
+	args := []interface{}{}
+	argIdx := 1
+
+	if filters.CustomerID != "" {
+		query += fmt.Sprintf(" AND customer_id = $%d", argIdx)
+		args = append(args, filters.CustomerID)
+		argIdx++
+	}
+	if filters.CampaignID != "" {
+		query += fmt.Sprintf(" AND campaign_id = $%d", argIdx)
+		args = append(args, filters.CampaignID)
+		argIdx++
+	}
+	if filters.Unresolved {
+		query += " AND actual_ltv IS NULL"
+	}
+	if filters.Platform != "" {
+		query += fmt.Sprintf(" AND platform = $%d", argIdx)
+		args = append(args, filters.Platform)
+		argIdx++
+	}
+	if !filters.StartTime.IsZero() {
+		query += fmt.Sprintf(" AND timestamp >= $%d", argIdx)
+		args = append(args, filters.StartTime)
+		argIdx++
+	}
+	if !filters.EndTime.IsZero() {
+		query += fmt.Sprintf(" AND timestamp <= $%d", argIdx)
+		args = append(args, filters.EndTime)
+		argIdx++
+	}
+	if haveCursor {
+		query += fmt.Sprintf(" AND (timestamp, request_id) < ($%d, $%d)", argIdx, argIdx+1)
+		args = append(args, afterTimestamp, afterRequestID)
+		argIdx += 2
+	}
+
+	query += fmt.Sprintf(" ORDER BY timestamp DESC, request_id DESC LIMIT $%d", argIdx)
+	args = append(args, streamPageSize)
+
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit trail page: %w", err)
+	}
+	defer rows.Close()
+
+	var page []*AuditEntry
+	for rows.Next() {
+		entry := &AuditEntry{}
+		var metadataJSON []byte
+
+		err := rows.Scan(
+			&entry.Timestamp, &entry.RequestID, &entry.CustomerID, &entry.CampaignID,
+			&entry.PredictedLTV, &entry.Confidence, &entry.LTVLowerBound, &entry.LTVUpperBound, &entry.ModelVersion,
+			&entry.BidAmount, &entry.BidSource, &entry.Platform, &entry.PlatformBidID,
+			&entry.BidStatus, &entry.CircuitState, &entry.UsedFallback,
+			&entry.ActualLTV, &entry.ActualLTVTimestamp, &entry.LTVErrorPct,
+			&entry.ExecutionTimeMs, &entry.InferenceTimeUs,
+			&entry.CampaignBudget, &entry.CurrentSpend, &entry.RemainingBudget,
+			&metadataJSON, &entry.Explanation, &entry.PrevHash, &entry.EntryHash,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry page: %w", err)
+		}
+		if len(metadataJSON) > 0 {
+			if err := json.Unmarshal(metadataJSON, &entry.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+			}
+		}
+
+		page = append(page, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading audit trail page: %w", err)
+	}
+
+	return page, nil
+}
+
+// trailCSVHeader is ExportCSV's column order - a flattened, spreadsheet-
+// friendly subset of AuditEntry; Metadata is dropped since it's arbitrary
+// nested JSON with no stable column shape.
+var trailCSVHeader = []string{
+	"request_id", "timestamp", "customer_id", "campaign_id",
+	"predicted_ltv", "confidence", "model_version",
+	"bid_amount", "bid_source", "platform", "bid_status",
+	"used_fallback", "execution_time_ms",
+	"prev_hash", "entry_hash",
+}
+
+// ExportCSV streams filters through StreamAuditTrail and writes the result
+// to w as CSV, one row per AuditEntry, without ever holding the full trail
+// in memory. It returns the first error encountered from either the query
+// or the write.
+func (a *AuditLogger) ExportCSV(ctx context.Context, filters AuditFilters, w io.Writer) error {
+	entries, errs := a.StreamAuditTrail(ctx, filters)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(trailCSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for entry := range entries {
+		row := []string{
+			entry.RequestID,
+			entry.Timestamp.Format(time.RFC3339Nano),
+			entry.CustomerID,
+			entry.CampaignID,
+			strconv.FormatFloat(entry.PredictedLTV, 'f', -1, 64),
+			strconv.FormatFloat(entry.Confidence, 'f', -1, 64),
+			entry.ModelVersion,
+			strconv.FormatFloat(entry.BidAmount, 'f', -1, 64),
+			entry.BidSource,
+			entry.Platform,
+			entry.BidStatus,
+			strconv.FormatBool(entry.UsedFallback),
+			strconv.Itoa(entry.ExecutionTimeMs),
+			entry.PrevHash,
+			entry.EntryHash,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row for %s: %w", entry.RequestID, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV: %w", err)
+	}
+
+	if err := <-errs; err != nil {
+		return fmt.Errorf("failed to export audit trail: %w", err)
+	}
+	return nil
+}
+
+// ExportJSONL streams filters through StreamAuditTrail and writes the
+// result to w as newline-delimited JSON, one AuditEntry per line.
+func (a *AuditLogger) ExportJSONL(ctx context.Context, filters AuditFilters, w io.Writer) error {
+	entries, errs := a.StreamAuditTrail(ctx, filters)
+
+	encoder := json.NewEncoder(w)
+	for entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to write JSONL row for %s: %w", entry.RequestID, err)
+		}
+	}
+
+	if err := <-errs; err != nil {
+		return fmt.Errorf("failed to export audit trail: %w", err)
+	}
+	return nil
+}
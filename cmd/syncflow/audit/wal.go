@@ -0,0 +1,438 @@
+package audit
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FsyncPolicy controls how aggressively WriteAheadLog fsyncs appended
+// records, trading write throughput for durability against a crash.
+type FsyncPolicy string
+
+const (
+	// FsyncAlways fsyncs after every Append - an acknowledged Write always
+	// survives a crash, at the cost of one fsync per entry.
+	FsyncAlways FsyncPolicy = "always"
+	// FsyncInterval batches fsyncs on a timer (see NewWriteAheadLog's
+	// fsyncInterval), losing at most one interval's worth of entries to a
+	// crash in exchange for far fewer fsyncs under load.
+	FsyncInterval FsyncPolicy = "interval"
+	// FsyncNever never fsyncs explicitly, relying on the OS to eventually
+	// flush its page cache. Fastest; a crash (as opposed to a clean process
+	// exit) can lose every acknowledged write the OS hasn't flushed yet.
+	FsyncNever FsyncPolicy = "never"
+)
+
+// walSegmentMaxBytes is the size a segment rolls over at.
+const walSegmentMaxBytes int64 = 64 * 1024 * 1024
+
+// walPosition identifies a point in the write-ahead log: the epoch-named
+// segment a record was written to, and the byte offset immediately after
+// that record within it.
+type walPosition struct {
+	segmentEpoch int64
+	offset       int64
+}
+
+// WriteAheadLog durably records AuditEntry writes to segmented, append-only
+// files under dir before AuditLogger.Write acknowledges them, so a crash
+// between periodic Postgres flushes never silently drops an entry - the gap
+// batchBuffer alone leaves in the "immutable trail" promise. Records are
+// framed as a 4-byte big-endian length, a 4-byte big-endian CRC32 of the
+// payload, then the JSON-encoded AuditEntry. Segments are named
+// "<epoch>.log" and roll at walSegmentMaxBytes; Checkpoint unlinks any
+// segment entirely before the checkpointed position.
+//
+// A crash between a Postgres commit and the following Checkpoint call can
+// cause the same entries to be replayed and re-inserted on restart - this
+// WAL gives at-least-once durability, not exactly-once.
+type WriteAheadLog struct {
+	dir    string
+	policy FsyncPolicy
+
+	mu           sync.Mutex
+	segments     []int64 // ascending epochs of every on-disk segment
+	active       *os.File
+	activeEpoch  int64
+	activeOffset int64
+
+	checkpoint walPosition
+
+	pendingCount    int
+	oldestPendingAt time.Time
+	fsyncTicker     *time.Ticker
+	dirtySinceFsync bool
+}
+
+// NewWriteAheadLog opens (creating if necessary) the write-ahead log at dir,
+// replays every record left over from a previous run that hadn't yet been
+// checkpointed, and returns both the log and those replayed entries so the
+// caller can flush them before serving new writes. fsyncInterval is only
+// used when policy is FsyncInterval.
+func NewWriteAheadLog(dir string, policy FsyncPolicy, fsyncInterval time.Duration) (*WriteAheadLog, []*AuditEntry, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, nil, fmt.Errorf("failed to create wal dir: %w", err)
+	}
+
+	segments, err := listWALSegments(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	checkpoint, err := readWALCheckpoint(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w := &WriteAheadLog{dir: dir, policy: policy, segments: segments, checkpoint: checkpoint}
+
+	pending, err := w.replayLocked()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := w.openActiveLocked(); err != nil {
+		return nil, nil, err
+	}
+
+	if policy == FsyncInterval {
+		w.fsyncTicker = time.NewTicker(fsyncInterval)
+		go w.backgroundFsync()
+	}
+
+	return w, pending, nil
+}
+
+// Append durably records entry and returns the position immediately after
+// it, for later use with Checkpoint.
+func (w *WriteAheadLog) Append(entry *AuditEntry) (walPosition, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return walPosition{}, fmt.Errorf("failed to encode wal record: %w", err)
+	}
+
+	record := make([]byte, 8+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[8:], payload)
+
+	if w.activeOffset+int64(len(record)) > walSegmentMaxBytes {
+		if err := w.rollSegmentLocked(); err != nil {
+			return walPosition{}, err
+		}
+	}
+
+	if _, err := w.active.Write(record); err != nil {
+		return walPosition{}, fmt.Errorf("failed to append wal record: %w", err)
+	}
+	w.activeOffset += int64(len(record))
+
+	switch w.policy {
+	case FsyncAlways:
+		if err := w.active.Sync(); err != nil {
+			return walPosition{}, fmt.Errorf("failed to fsync wal: %w", err)
+		}
+	case FsyncInterval:
+		w.dirtySinceFsync = true
+	}
+
+	if w.pendingCount == 0 {
+		w.oldestPendingAt = entry.Timestamp
+	}
+	w.pendingCount++
+
+	return walPosition{segmentEpoch: w.activeEpoch, offset: w.activeOffset}, nil
+}
+
+// Checkpoint records that every entry up to and including pos has been
+// durably committed to Postgres, persists that position, and unlinks any
+// segment that now lies entirely before it. count is the number of records
+// being checkpointed, used only to keep Depth/OldestUnflushed accurate.
+func (w *WriteAheadLog) Checkpoint(pos walPosition, count int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := writeWALCheckpoint(w.dir, pos); err != nil {
+		return err
+	}
+	w.checkpoint = pos
+
+	kept := w.segments[:0:0]
+	for _, epoch := range w.segments {
+		if epoch < pos.segmentEpoch {
+			if err := os.Remove(walSegmentPath(w.dir, epoch)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove consumed wal segment: %w", err)
+			}
+			continue
+		}
+		kept = append(kept, epoch)
+	}
+	w.segments = kept
+
+	w.pendingCount -= count
+	if w.pendingCount <= 0 {
+		w.pendingCount = 0
+		w.oldestPendingAt = time.Time{}
+	}
+
+	return nil
+}
+
+// TailPosition returns the position immediately after the last record
+// currently on disk - every record Append has written, or (right after
+// NewWriteAheadLog, before any new Append) every record replay returned.
+func (w *WriteAheadLog) TailPosition() walPosition {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return walPosition{segmentEpoch: w.activeEpoch, offset: w.activeOffset}
+}
+
+// Depth returns the number of records appended but not yet checkpointed.
+func (w *WriteAheadLog) Depth() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.pendingCount
+}
+
+// OldestUnflushed returns the timestamp of the oldest appended-but-not-yet-
+// checkpointed entry, or the zero Time if the WAL is fully caught up.
+func (w *WriteAheadLog) OldestUnflushed() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.oldestPendingAt
+}
+
+// Close stops the background fsync ticker (if any) and closes the active
+// segment.
+func (w *WriteAheadLog) Close() error {
+	if w.fsyncTicker != nil {
+		w.fsyncTicker.Stop()
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.active == nil {
+		return nil
+	}
+	return w.active.Close()
+}
+
+// backgroundFsync fsyncs the active segment on a timer under FsyncInterval,
+// mirroring AuditLogger.backgroundFlush's ticker-driven loop.
+func (w *WriteAheadLog) backgroundFsync() {
+	for range w.fsyncTicker.C {
+		w.mu.Lock()
+		if w.dirtySinceFsync && w.active != nil {
+			if err := w.active.Sync(); err != nil {
+				fmt.Printf("⚠️  wal fsync error: %v\n", err)
+			} else {
+				w.dirtySinceFsync = false
+			}
+		}
+		w.mu.Unlock()
+	}
+}
+
+// rollSegmentLocked closes the current active segment (if any) and opens a
+// fresh one. Caller must hold w.mu.
+func (w *WriteAheadLog) rollSegmentLocked() error {
+	if w.active != nil {
+		if err := w.active.Close(); err != nil {
+			return fmt.Errorf("failed to close wal segment before roll: %w", err)
+		}
+	}
+
+	epoch := time.Now().UnixNano()
+	f, err := os.OpenFile(walSegmentPath(w.dir, epoch), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create wal segment: %w", err)
+	}
+
+	w.active = f
+	w.activeEpoch = epoch
+	w.activeOffset = 0
+	w.segments = append(w.segments, epoch)
+	return nil
+}
+
+// openActiveLocked opens the newest existing segment for continued
+// appends, or creates the first one if none exist. Caller must hold w.mu
+// only in the sense that no other goroutine has a reference to w yet (this
+// runs once, from NewWriteAheadLog).
+func (w *WriteAheadLog) openActiveLocked() error {
+	if len(w.segments) == 0 {
+		return w.rollSegmentLocked()
+	}
+
+	epoch := w.segments[len(w.segments)-1]
+	f, err := os.OpenFile(walSegmentPath(w.dir, epoch), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to reopen wal segment: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat wal segment: %w", err)
+	}
+
+	w.active = f
+	w.activeEpoch = epoch
+	w.activeOffset = info.Size()
+	return nil
+}
+
+// replayLocked reads every record from the checkpoint position forward
+// across all segments and returns the pending AuditEntry values in order.
+// A trailing record that fails its CRC check or is truncated is treated as
+// an interrupted write from a crash, not corruption, and replay stops there
+// rather than erroring out startup.
+func (w *WriteAheadLog) replayLocked() ([]*AuditEntry, error) {
+	var pending []*AuditEntry
+
+	for _, epoch := range w.segments {
+		if epoch < w.checkpoint.segmentEpoch {
+			continue
+		}
+
+		startOffset := int64(0)
+		if epoch == w.checkpoint.segmentEpoch {
+			startOffset = w.checkpoint.offset
+		}
+
+		entries, err := readWALSegmentFrom(walSegmentPath(w.dir, epoch), startOffset)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, entries...)
+	}
+
+	if len(pending) > 0 {
+		w.pendingCount = len(pending)
+		w.oldestPendingAt = pending[0].Timestamp
+	}
+
+	return pending, nil
+}
+
+// readWALSegmentFrom reads every well-formed record in path starting at
+// byte offset, stopping (without error) at EOF or the first corrupt/
+// truncated trailing record.
+func readWALSegmentFrom(path string, offset int64) ([]*AuditEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open wal segment %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek wal segment %s: %w", path, err)
+	}
+
+	var entries []*AuditEntry
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(f, header); err != nil {
+			break // EOF or a partial header from an interrupted write
+		}
+		size := binary.BigEndian.Uint32(header[0:4])
+		wantCRC := binary.BigEndian.Uint32(header[4:8])
+
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(f, payload); err != nil {
+			break // truncated trailing record
+		}
+		if crc32.ChecksumIEEE(payload) != wantCRC {
+			break // corrupt trailing record, treated as an interrupted write
+		}
+
+		var entry AuditEntry
+		if err := json.Unmarshal(payload, &entry); err != nil {
+			break
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+func listWALSegments(dir string) ([]int64, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wal segments: %w", err)
+	}
+
+	var epochs []int64
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".log") {
+			continue
+		}
+		epoch, err := strconv.ParseInt(strings.TrimSuffix(f.Name(), ".log"), 10, 64)
+		if err != nil {
+			continue // not one of ours
+		}
+		epochs = append(epochs, epoch)
+	}
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i] < epochs[j] })
+	return epochs, nil
+}
+
+func walSegmentPath(dir string, epoch int64) string {
+	return filepath.Join(dir, fmt.Sprintf("%d.log", epoch))
+}
+
+func walCheckpointPath(dir string) string {
+	return filepath.Join(dir, "checkpoint")
+}
+
+func readWALCheckpoint(dir string) (walPosition, error) {
+	data, err := os.ReadFile(walCheckpointPath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return walPosition{}, nil
+		}
+		return walPosition{}, fmt.Errorf("failed to read wal checkpoint: %w", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return walPosition{}, fmt.Errorf("malformed wal checkpoint file")
+	}
+	epoch, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return walPosition{}, fmt.Errorf("malformed wal checkpoint segment: %w", err)
+	}
+	offset, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return walPosition{}, fmt.Errorf("malformed wal checkpoint offset: %w", err)
+	}
+	return walPosition{segmentEpoch: epoch, offset: offset}, nil
+}
+
+// writeWALCheckpoint persists pos via a write-then-rename so a crash
+// mid-write never leaves a corrupt checkpoint file behind.
+func writeWALCheckpoint(dir string, pos walPosition) error {
+	tmpPath := walCheckpointPath(dir) + ".tmp"
+	data := fmt.Sprintf("%d %d\n", pos.segmentEpoch, pos.offset)
+	if err := os.WriteFile(tmpPath, []byte(data), 0600); err != nil {
+		return fmt.Errorf("failed to write wal checkpoint: %w", err)
+	}
+	if err := os.Rename(tmpPath, walCheckpointPath(dir)); err != nil {
+		return fmt.Errorf("failed to swap in wal checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteAheadLog_AppendThenReplayOnReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	wal, pending, err := NewWriteAheadLog(dir, FsyncAlways, time.Second)
+	if err != nil {
+		t.Fatalf("NewWriteAheadLog failed: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending entries for a fresh wal, got %d", len(pending))
+	}
+
+	if _, err := wal.Append(&AuditEntry{RequestID: "req-1", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if _, err := wal.Append(&AuditEntry{RequestID: "req-2", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := wal.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reopened, pending, err := NewWriteAheadLog(dir, FsyncAlways, time.Second)
+	if err != nil {
+		t.Fatalf("reopening wal failed: %v", err)
+	}
+	defer reopened.Close()
+
+	if len(pending) != 2 {
+		t.Fatalf("expected both uncheckpointed entries replayed, got %d", len(pending))
+	}
+	if pending[0].RequestID != "req-1" || pending[1].RequestID != "req-2" {
+		t.Fatalf("expected replay order preserved, got %+v", pending)
+	}
+	if reopened.Depth() != 2 {
+		t.Fatalf("expected Depth to reflect the replayed backlog, got %d", reopened.Depth())
+	}
+}
+
+func TestWriteAheadLog_CheckpointUnlinksConsumedSegmentsAndStopsReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	wal, _, err := NewWriteAheadLog(dir, FsyncAlways, time.Second)
+	if err != nil {
+		t.Fatalf("NewWriteAheadLog failed: %v", err)
+	}
+
+	pos, err := wal.Append(&AuditEntry{RequestID: "req-1", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if _, err := wal.Append(&AuditEntry{RequestID: "req-2", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	if err := wal.Checkpoint(pos, 1); err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+	if got := wal.Depth(); got != 1 {
+		t.Fatalf("expected 1 record still pending after checkpointing the first, got %d", got)
+	}
+	if err := wal.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reopened, pending, err := NewWriteAheadLog(dir, FsyncAlways, time.Second)
+	if err != nil {
+		t.Fatalf("reopening wal failed: %v", err)
+	}
+	defer reopened.Close()
+
+	if len(pending) != 1 || pending[0].RequestID != "req-2" {
+		t.Fatalf("expected only the uncheckpointed entry replayed, got %+v", pending)
+	}
+}
+
+func TestWriteAheadLog_TailPositionAfterReplayCheckpointsPastReplayedEntries(t *testing.T) {
+	dir := t.TempDir()
+
+	wal, _, err := NewWriteAheadLog(dir, FsyncAlways, time.Second)
+	if err != nil {
+		t.Fatalf("NewWriteAheadLog failed: %v", err)
+	}
+	if _, err := wal.Append(&AuditEntry{RequestID: "req-1", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if _, err := wal.Append(&AuditEntry{RequestID: "req-2", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := wal.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reopened, pending, err := NewWriteAheadLog(dir, FsyncAlways, time.Second)
+	if err != nil {
+		t.Fatalf("reopening wal failed: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("expected both entries replayed, got %d", len(pending))
+	}
+
+	// Mirrors what NewAuditLoggerWithWAL does once the replayed batch has
+	// committed to Postgres: checkpoint past everything replay returned.
+	if err := reopened.Checkpoint(reopened.TailPosition(), len(pending)); err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+	if err := reopened.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	again, pending, err := NewWriteAheadLog(dir, FsyncAlways, time.Second)
+	if err != nil {
+		t.Fatalf("reopening wal a second time failed: %v", err)
+	}
+	defer again.Close()
+
+	if len(pending) != 0 {
+		t.Fatalf("expected no entries replayed after checkpointing past them, got %+v", pending)
+	}
+}
+
+func TestWriteAheadLog_RollsSegmentsWhenOverSize(t *testing.T) {
+	dir := t.TempDir()
+
+	wal, _, err := NewWriteAheadLog(dir, FsyncNever, time.Second)
+	if err != nil {
+		t.Fatalf("NewWriteAheadLog failed: %v", err)
+	}
+	defer wal.Close()
+
+	firstEpoch := wal.activeEpoch
+	wal.activeOffset = walSegmentMaxBytes // force the next Append to roll
+
+	if _, err := wal.Append(&AuditEntry{RequestID: "req-1", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	if wal.activeEpoch == firstEpoch {
+		t.Fatal("expected an oversized active segment to roll to a new one")
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	var logFiles int
+	for _, f := range files {
+		if filepath.Ext(f.Name()) == ".log" {
+			logFiles++
+		}
+	}
+	if logFiles != 2 {
+		t.Fatalf("expected 2 segment files on disk after rolling, got %d", logFiles)
+	}
+}
+
+func TestWriteAheadLog_TruncatedTrailingRecordStopsReplayWithoutError(t *testing.T) {
+	dir := t.TempDir()
+
+	wal, _, err := NewWriteAheadLog(dir, FsyncAlways, time.Second)
+	if err != nil {
+		t.Fatalf("NewWriteAheadLog failed: %v", err)
+	}
+	if _, err := wal.Append(&AuditEntry{RequestID: "req-1", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	segmentPath := walSegmentPath(dir, wal.activeEpoch)
+	if err := wal.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Simulate a crash mid-write: append a partial record header with no
+	// payload behind it.
+	f, err := os.OpenFile(segmentPath, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("failed to reopen segment for corruption: %v", err)
+	}
+	if _, err := f.Write([]byte{0, 0, 0, 99, 0, 0, 0, 0}); err != nil {
+		t.Fatalf("failed to write partial trailing record: %v", err)
+	}
+	f.Close()
+
+	_, pending, err := NewWriteAheadLog(dir, FsyncAlways, time.Second)
+	if err != nil {
+		t.Fatalf("expected a truncated trailing record to be tolerated, got error: %v", err)
+	}
+	if len(pending) != 1 || pending[0].RequestID != "req-1" {
+		t.Fatalf("expected only the complete record to be replayed, got %+v", pending)
+	}
+}
@@ -0,0 +1,130 @@
+package budget
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BudgetTree composes per-node SlidingWindowBudgets into a hierarchy
+// (account -> campaign -> adgroup, or any depth operators need), so the same
+// spend event is checked and attributed against every ancestor's window
+// without hand-composing budgeters.
+type BudgetTree struct {
+	mu    sync.RWMutex
+	nodes map[string]*budgetNode // keyed by "/"-joined path
+}
+
+type budgetNode struct {
+	name   string
+	parent string // joined path of the parent, "" for a root
+	budget *SlidingWindowBudget
+}
+
+// NewBudgetTree creates an empty budget tree.
+func NewBudgetTree() *BudgetTree {
+	return &BudgetTree{nodes: make(map[string]*budgetNode)}
+}
+
+// AddChild registers a node named name under parent (the path segments of an
+// existing node, or an empty path to add a root), with its own window and
+// burst cap.
+func (t *BudgetTree) AddChild(parent []string, name string, window time.Duration, cap float64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parentKey := joinPath(parent)
+	if parentKey != "" {
+		if _, ok := t.nodes[parentKey]; !ok {
+			return fmt.Errorf("budget tree: parent %q does not exist", parentKey)
+		}
+	}
+
+	key := joinPath(append(append([]string{}, parent...), name))
+	if _, exists := t.nodes[key]; exists {
+		return fmt.Errorf("budget tree: node %q already exists", key)
+	}
+
+	t.nodes[key] = &budgetNode{
+		name:   name,
+		parent: parentKey,
+		budget: NewSlidingWindowBudget(window, cap),
+	}
+	return nil
+}
+
+// RecordSpend walks path from leaf to root, checking each ancestor's window
+// budget. If any level rejects the spend, every level already recorded for
+// this call is rolled back so no node double-counts a rejected spend.
+func (t *BudgetTree) RecordSpend(path []string, amount float64, platform, requestID string) error {
+	t.mu.RLock()
+	chain, err := t.ancestorChainLocked(path)
+	t.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	var recorded []*SlidingWindowBudget
+	for _, node := range chain {
+		if err := node.budget.RecordSpend(amount, platform, requestID); err != nil {
+			for _, b := range recorded {
+				b.rollbackLast(requestID)
+			}
+			return fmt.Errorf("budget tree: %w", err)
+		}
+		recorded = append(recorded, node.budget)
+	}
+	return nil
+}
+
+// GetStatus returns the budget status of the node at path.
+func (t *BudgetTree) GetStatus(path []string) (BudgetStatus, error) {
+	t.mu.RLock()
+	node, ok := t.nodes[joinPath(path)]
+	t.mu.RUnlock()
+	if !ok {
+		return BudgetStatus{}, fmt.Errorf("budget tree: node %q does not exist", joinPath(path))
+	}
+	return node.budget.GetBudgetStatus(), nil
+}
+
+// ancestorChainLocked returns the nodes from leaf to root for path. Caller
+// must hold t.mu for reading.
+func (t *BudgetTree) ancestorChainLocked(path []string) ([]*budgetNode, error) {
+	key := joinPath(path)
+	node, ok := t.nodes[key]
+	if !ok {
+		return nil, fmt.Errorf("budget tree: node %q does not exist", key)
+	}
+
+	chain := []*budgetNode{node}
+	for node.parent != "" {
+		parent, ok := t.nodes[node.parent]
+		if !ok {
+			return nil, fmt.Errorf("budget tree: parent %q of %q is missing", node.parent, key)
+		}
+		chain = append(chain, parent)
+		node = parent
+	}
+	return chain, nil
+}
+
+func joinPath(path []string) string {
+	return strings.Join(path, "/")
+}
+
+// rollbackLast removes the most recently recorded spend event for
+// requestID, so a rejection at a higher level in the tree doesn't leave
+// lower levels double-counting it.
+func (b *SlidingWindowBudget) rollbackLast(requestID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i := len(b.spendHistory) - 1; i >= 0; i-- {
+		if b.spendHistory[i].RequestID == requestID {
+			b.spendHistory = append(b.spendHistory[:i], b.spendHistory[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package budget
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBudgetTree_RecordSpendChecksEveryAncestor(t *testing.T) {
+	tree := NewBudgetTree()
+	if err := tree.AddChild(nil, "account", 24*time.Hour, 10000.0); err != nil {
+		t.Fatalf("AddChild(account) failed: %v", err)
+	}
+	if err := tree.AddChild([]string{"account"}, "campaignX", 24*time.Hour, 2000.0); err != nil {
+		t.Fatalf("AddChild(campaignX) failed: %v", err)
+	}
+	if err := tree.AddChild([]string{"account", "campaignX"}, "adgroupY", time.Hour, 500.0); err != nil {
+		t.Fatalf("AddChild(adgroupY) failed: %v", err)
+	}
+
+	path := []string{"account", "campaignX", "adgroupY"}
+	if err := tree.RecordSpend(path, 400.0, "google_ads", "req-1"); err != nil {
+		t.Fatalf("expected spend within all limits to succeed, got: %v", err)
+	}
+
+	accountStatus, err := tree.GetStatus([]string{"account"})
+	if err != nil {
+		t.Fatalf("GetStatus(account) failed: %v", err)
+	}
+	if accountStatus.CurrentSpend != 400.0 {
+		t.Errorf("expected account spend to roll up to 400.0, got %.2f", accountStatus.CurrentSpend)
+	}
+}
+
+func TestBudgetTree_RejectionAtAncestorRollsBackLowerLevels(t *testing.T) {
+	tree := NewBudgetTree()
+	tree.AddChild(nil, "account", 24*time.Hour, 300.0) // tight account cap
+	tree.AddChild([]string{"account"}, "campaignX", 24*time.Hour, 2000.0)
+
+	path := []string{"account", "campaignX"}
+	err := tree.RecordSpend(path, 400.0, "google_ads", "req-1")
+	if err == nil {
+		t.Fatal("expected spend exceeding the account cap to be rejected")
+	}
+
+	status, err := tree.GetStatus([]string{"account", "campaignX"})
+	if err != nil {
+		t.Fatalf("GetStatus(campaignX) failed: %v", err)
+	}
+	if status.CurrentSpend != 0 {
+		t.Errorf("expected campaign spend to be rolled back to 0, got %.2f", status.CurrentSpend)
+	}
+}
@@ -0,0 +1,179 @@
+package budget
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileSpendStore is a write-ahead-log-backed SpendStore. Each record is a
+// 4-byte big-endian length prefix followed by the JSON-encoded SpendEvent.
+// Append fsyncs before returning, so an accepted spend is never lost to a
+// crash even mid-write-ahead-log.
+type FileSpendStore struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// NewFileSpendStore opens (creating if necessary) the WAL file at path.
+func NewFileSpendStore(path string) (*FileSpendStore, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spend WAL: %w", err)
+	}
+	return &FileSpendStore{path: path, file: f}, nil
+}
+
+// Append writes event to the WAL and fsyncs before returning.
+func (s *FileSpendStore) Append(event SpendEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, err := encodeSpendRecord(event)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(record); err != nil {
+		return fmt.Errorf("failed to append spend WAL record: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to fsync spend WAL: %w", err)
+	}
+	return nil
+}
+
+// LoadSince replays the WAL and returns every event with Timestamp >= t.
+func (s *FileSpendStore) LoadSince(t time.Time) ([]SpendEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAllLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []SpendEvent
+	for _, e := range all {
+		if !e.Timestamp.Before(t) {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// Compact rewrites the WAL, dropping events with Timestamp < before.
+func (s *FileSpendStore) Compact(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAllLocked()
+	if err != nil {
+		return err
+	}
+
+	var kept []SpendEvent
+	for _, e := range all {
+		if !e.Timestamp.Before(before) {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(all) {
+		return nil // nothing to drop
+	}
+
+	tmpPath := s.path + ".compact.tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create compaction file: %w", err)
+	}
+	for _, e := range kept {
+		record, err := encodeSpendRecord(e)
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		if _, err := tmp.Write(record); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to write compacted spend record: %w", err)
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to fsync compacted spend WAL: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close compacted spend WAL: %w", err)
+	}
+
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to close spend WAL before swap: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to swap in compacted spend WAL: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to reopen spend WAL after compaction: %w", err)
+	}
+	s.file = f
+	return nil
+}
+
+// Close closes the underlying WAL file.
+func (s *FileSpendStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// readAllLocked reads every record currently in the WAL. Caller must hold s.mu.
+func (s *FileSpendStore) readAllLocked() ([]SpendEvent, error) {
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek spend WAL: %w", err)
+	}
+
+	var events []SpendEvent
+	lenBuf := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(s.file, lenBuf); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to read spend WAL record length: %w", err)
+		}
+		size := binary.BigEndian.Uint32(lenBuf)
+
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(s.file, payload); err != nil {
+			return nil, fmt.Errorf("failed to read spend WAL record: %w", err)
+		}
+
+		var event SpendEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode spend WAL record: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if _, err := s.file.Seek(0, io.SeekEnd); err != nil {
+		return nil, fmt.Errorf("failed to seek spend WAL to end: %w", err)
+	}
+	return events, nil
+}
+
+func encodeSpendRecord(event SpendEvent) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spend record: %w", err)
+	}
+	record := make([]byte, 4+len(payload))
+	binary.BigEndian.PutUint32(record[:4], uint32(len(payload)))
+	copy(record[4:], payload)
+	return record, nil
+}
@@ -0,0 +1,82 @@
+package budget
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSpendStore_AppendAndLoadSince(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spend.wal")
+	store, err := NewFileSpendStore(path)
+	if err != nil {
+		t.Fatalf("NewFileSpendStore failed: %v", err)
+	}
+	defer store.Close()
+
+	base := time.Now()
+	if err := store.Append(SpendEvent{Timestamp: base, Amount: 10, Platform: "google_ads", RequestID: "req-1"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := store.Append(SpendEvent{Timestamp: base.Add(time.Second), Amount: 20, Platform: "meta", RequestID: "req-2"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	events, err := store.LoadSince(base.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("LoadSince failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].RequestID != "req-1" || events[1].RequestID != "req-2" {
+		t.Errorf("unexpected replay order: %+v", events)
+	}
+}
+
+func TestFileSpendStore_CompactDropsOldEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spend.wal")
+	store, err := NewFileSpendStore(path)
+	if err != nil {
+		t.Fatalf("NewFileSpendStore failed: %v", err)
+	}
+	defer store.Close()
+
+	base := time.Now()
+	store.Append(SpendEvent{Timestamp: base.Add(-time.Hour), Amount: 5, Platform: "google_ads", RequestID: "old"})
+	store.Append(SpendEvent{Timestamp: base, Amount: 5, Platform: "google_ads", RequestID: "new"})
+
+	if err := store.Compact(base.Add(-time.Minute)); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+
+	events, err := store.LoadSince(base.Add(-2 * time.Hour))
+	if err != nil {
+		t.Fatalf("LoadSince failed: %v", err)
+	}
+	if len(events) != 1 || events[0].RequestID != "new" {
+		t.Fatalf("expected only the recent event to survive compaction, got %+v", events)
+	}
+}
+
+func TestNewSlidingWindowBudgetWithStore_ReplaysHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spend.wal")
+	store, err := NewFileSpendStore(path)
+	if err != nil {
+		t.Fatalf("NewFileSpendStore failed: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.Append(SpendEvent{Timestamp: time.Now(), Amount: 300, Platform: "google_ads", RequestID: "req-1"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	budgeter, err := NewSlidingWindowBudgetWithStore(10*time.Minute, 1000.0, store)
+	if err != nil {
+		t.Fatalf("NewSlidingWindowBudgetWithStore failed: %v", err)
+	}
+
+	if got := budgeter.GetCurrentSpend(); got != 300 {
+		t.Errorf("expected replayed spend of 300, got %.2f", got)
+	}
+}
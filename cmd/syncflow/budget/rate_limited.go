@@ -0,0 +1,224 @@
+package budget
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PlatformRateConfig configures the token-bucket refill rate for a single
+// platform. RefillPerSecond tokens are added to the bucket every second, up
+// to BurstCapacity.
+type PlatformRateConfig struct {
+	RefillPerSecond float64
+	BurstCapacity   float64
+}
+
+// DefaultPlatformRateConfig is used for platforms with no explicit entry.
+var DefaultPlatformRateConfig = PlatformRateConfig{
+	RefillPerSecond: 10,
+	BurstCapacity:   50,
+}
+
+// tokenBucket is a classic token-bucket: it refills continuously based on
+// elapsed wall-clock time rather than on a background ticker.
+type tokenBucket struct {
+	config   PlatformRateConfig
+	tokens   float64
+	lastFill time.Time
+}
+
+func newTokenBucket(config PlatformRateConfig) *tokenBucket {
+	return &tokenBucket{
+		config:   config,
+		tokens:   config.BurstCapacity,
+		lastFill: time.Now(),
+	}
+}
+
+// refillLocked tops up the bucket for elapsed time. Caller must hold the
+// owning RateLimitedBudget's lock.
+func (tb *tokenBucket) refillLocked(now time.Time) {
+	elapsed := now.Sub(tb.lastFill).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	tb.tokens += elapsed * tb.config.RefillPerSecond
+	if tb.tokens > tb.config.BurstCapacity {
+		tb.tokens = tb.config.BurstCapacity
+	}
+	tb.lastFill = now
+}
+
+// retryAfterLocked returns how long the caller must wait before `amount`
+// tokens are available. Caller must hold the owning RateLimitedBudget's lock.
+func (tb *tokenBucket) retryAfterLocked(amount float64) time.Duration {
+	deficit := amount - tb.tokens
+	if deficit <= 0 || tb.config.RefillPerSecond <= 0 {
+		return 0
+	}
+	seconds := deficit / tb.config.RefillPerSecond
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// RateLimitedBudget layers a per-platform token-bucket rate limit on top of
+// SlidingWindowBudget, so a caller that stays under the burst-total cap can
+// no longer emit the entire cap's worth of spend within a single millisecond.
+type RateLimitedBudget struct {
+	mu      sync.Mutex
+	window  *SlidingWindowBudget
+	configs map[string]PlatformRateConfig
+	buckets map[string]*tokenBucket
+}
+
+// NewRateLimitedBudget wraps window with a token bucket per platform,
+// configured from platformConfigs (platform name -> rate config). Platforms
+// absent from platformConfigs fall back to DefaultPlatformRateConfig.
+func NewRateLimitedBudget(window *SlidingWindowBudget, platformConfigs map[string]PlatformRateConfig) *RateLimitedBudget {
+	configs := make(map[string]PlatformRateConfig, len(platformConfigs))
+	for platform, cfg := range platformConfigs {
+		configs[platform] = cfg
+	}
+
+	return &RateLimitedBudget{
+		window:  window,
+		configs: configs,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+func (r *RateLimitedBudget) bucketLocked(platform string) *tokenBucket {
+	bucket, ok := r.buckets[platform]
+	if ok {
+		return bucket
+	}
+
+	config, ok := r.configs[platform]
+	if !ok {
+		config = DefaultPlatformRateConfig
+	}
+	bucket = newTokenBucket(config)
+	r.buckets[platform] = bucket
+	return bucket
+}
+
+// RecordSpend consumes amount tokens from the platform's token bucket and,
+// if that succeeds, falls through to the wrapped SlidingWindowBudget's burst
+// check. The token is NOT refunded if the window check subsequently fails,
+// since the window rejecting a spend is itself evidence the platform is
+// already over budget.
+func (r *RateLimitedBudget) RecordSpend(amount float64, platform, requestID string) error {
+	r.mu.Lock()
+	now := time.Now()
+	bucket := r.bucketLocked(platform)
+	bucket.refillLocked(now)
+
+	if bucket.tokens < amount {
+		retryAfter := bucket.retryAfterLocked(amount)
+		r.mu.Unlock()
+		return &RateLimitedError{
+			Platform:      platform,
+			Requested:     amount,
+			AvailableNow:  bucket.tokens,
+			BurstCapacity: bucket.config.BurstCapacity,
+			RetryAfter:    retryAfter,
+		}
+	}
+	bucket.tokens -= amount
+	r.mu.Unlock()
+
+	if err := r.window.RecordSpend(amount, platform, requestID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CanSpend reports whether amount could currently be spent on platform
+// without mutating any bucket or window state.
+func (r *RateLimitedBudget) CanSpend(platform string, amount float64) bool {
+	r.mu.Lock()
+	bucket := r.bucketLocked(platform)
+	bucket.refillLocked(time.Now())
+	hasTokens := bucket.tokens >= amount
+	r.mu.Unlock()
+
+	return hasTokens && r.window.CanSpend(amount)
+}
+
+// Reservation is a handle to tokens already deducted from a platform's
+// bucket. If the downstream bid never happens (e.g. the platform API call
+// fails), call Cancel to return the tokens to the bucket.
+type Reservation struct {
+	budget    *RateLimitedBudget
+	platform  string
+	amount    float64
+	cancelled bool
+	mu        sync.Mutex
+}
+
+// Reserve consumes amount tokens from platform's bucket and returns a handle
+// the caller can Cancel() to refund the tokens if the reserved spend never
+// happens. It does not touch the underlying sliding-window burst cap; call
+// RecordSpend once the reservation is actually used.
+func (r *RateLimitedBudget) Reserve(platform string, amount float64) (*Reservation, error) {
+	r.mu.Lock()
+	now := time.Now()
+	bucket := r.bucketLocked(platform)
+	bucket.refillLocked(now)
+
+	if bucket.tokens < amount {
+		retryAfter := bucket.retryAfterLocked(amount)
+		r.mu.Unlock()
+		return nil, &RateLimitedError{
+			Platform:      platform,
+			Requested:     amount,
+			AvailableNow:  bucket.tokens,
+			BurstCapacity: bucket.config.BurstCapacity,
+			RetryAfter:    retryAfter,
+		}
+	}
+	bucket.tokens -= amount
+	r.mu.Unlock()
+
+	return &Reservation{budget: r, platform: platform, amount: amount}, nil
+}
+
+// Cancel returns the reservation's tokens to the platform's bucket. It is
+// safe to call at most once; subsequent calls are no-ops.
+func (res *Reservation) Cancel() {
+	res.mu.Lock()
+	defer res.mu.Unlock()
+	if res.cancelled {
+		return
+	}
+	res.cancelled = true
+
+	res.budget.mu.Lock()
+	defer res.budget.mu.Unlock()
+	bucket := res.budget.bucketLocked(res.platform)
+	bucket.tokens += res.amount
+	if bucket.tokens > bucket.config.BurstCapacity {
+		bucket.tokens = bucket.config.BurstCapacity
+	}
+}
+
+// RateLimitedError is returned when a platform's token bucket has no tokens
+// left to satisfy the requested spend.
+type RateLimitedError struct {
+	Platform      string
+	Requested     float64
+	AvailableNow  float64
+	BurstCapacity float64
+	RetryAfter    time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf(
+		"rate limited on %s: requested $%.2f but only $%.2f tokens available (burst capacity $%.2f), retry after %s",
+		e.Platform,
+		e.Requested,
+		e.AvailableNow,
+		e.BurstCapacity,
+		e.RetryAfter,
+	)
+}
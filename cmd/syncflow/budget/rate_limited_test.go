@@ -0,0 +1,76 @@
+package budget
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimitedBudget_BasicSpend(t *testing.T) {
+	window := NewSlidingWindowBudget(10*time.Minute, 1000.0)
+	limiter := NewRateLimitedBudget(window, map[string]PlatformRateConfig{
+		"google_ads": {RefillPerSecond: 10, BurstCapacity: 50},
+	})
+
+	if err := limiter.RecordSpend(30.0, "google_ads", "req-001"); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestRateLimitedBudget_RejectsBurstFasterThanRefill(t *testing.T) {
+	window := NewSlidingWindowBudget(10*time.Minute, 1000.0)
+	limiter := NewRateLimitedBudget(window, map[string]PlatformRateConfig{
+		"google_ads": {RefillPerSecond: 10, BurstCapacity: 50},
+	})
+
+	// Spend the entire burst capacity immediately.
+	if err := limiter.RecordSpend(50.0, "google_ads", "req-001"); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// A second spend in the same instant should be rate limited, even though
+	// the sliding window's 1000.0 burst-total cap has plenty of headroom.
+	err := limiter.RecordSpend(10.0, "google_ads", "req-002")
+	if err == nil {
+		t.Fatal("expected a RateLimitedError, got nil")
+	}
+	rlErr, ok := err.(*RateLimitedError)
+	if !ok {
+		t.Fatalf("expected *RateLimitedError, got %T", err)
+	}
+	if rlErr.RetryAfter <= 0 {
+		t.Errorf("expected a positive RetryAfter, got %s", rlErr.RetryAfter)
+	}
+}
+
+func TestRateLimitedBudget_ReserveAndCancel(t *testing.T) {
+	window := NewSlidingWindowBudget(10*time.Minute, 1000.0)
+	limiter := NewRateLimitedBudget(window, map[string]PlatformRateConfig{
+		"meta": {RefillPerSecond: 5, BurstCapacity: 20},
+	})
+
+	reservation, err := limiter.Reserve("meta", 20.0)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// Bucket is now empty; a further reservation should fail.
+	if _, err := limiter.Reserve("meta", 1.0); err == nil {
+		t.Fatal("expected reservation to fail while bucket is empty")
+	}
+
+	reservation.Cancel()
+
+	// Tokens were refunded, so a fresh reservation of the same size succeeds.
+	if _, err := limiter.Reserve("meta", 20.0); err != nil {
+		t.Fatalf("expected reservation to succeed after cancel, got: %v", err)
+	}
+}
+
+func TestRateLimitedBudget_DefaultConfigUsedForUnknownPlatform(t *testing.T) {
+	window := NewSlidingWindowBudget(10*time.Minute, 1000.0)
+	limiter := NewRateLimitedBudget(window, nil)
+
+	if !limiter.CanSpend("unknown_platform", 1.0) {
+		t.Error("expected default platform rate config to allow a small spend")
+	}
+}
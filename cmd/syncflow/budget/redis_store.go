@@ -0,0 +1,81 @@
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisSpendStore persists SpendEvents in a Redis sorted set keyed by
+// timestamp, so every replica behind a load balancer shares one budget
+// instead of each tracking its own in-process history.
+type RedisSpendStore struct {
+	client *redis.Client
+	key    string
+	ctx    context.Context
+}
+
+// NewRedisSpendStore creates a store backed by a sorted set at key on client.
+func NewRedisSpendStore(client *redis.Client, key string) *RedisSpendStore {
+	return &RedisSpendStore{
+		client: client,
+		key:    key,
+		ctx:    context.Background(),
+	}
+}
+
+// Append adds event to the sorted set, scored by its timestamp, and waits
+// for Redis to acknowledge the write.
+func (s *RedisSpendStore) Append(event SpendEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode spend event: %w", err)
+	}
+
+	err = s.client.ZAdd(s.ctx, s.key, &redis.Z{
+		Score:  float64(event.Timestamp.UnixNano()),
+		Member: string(payload),
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to append spend event to redis: %w", err)
+	}
+	return nil
+}
+
+// LoadSince returns every persisted event with Timestamp >= t.
+func (s *RedisSpendStore) LoadSince(t time.Time) ([]SpendEvent, error) {
+	members, err := s.client.ZRangeByScore(s.ctx, s.key, &redis.ZRangeBy{
+		Min: fmt.Sprintf("%d", t.UnixNano()),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spend events from redis: %w", err)
+	}
+
+	events := make([]SpendEvent, 0, len(members))
+	for _, member := range members {
+		var event SpendEvent
+		if err := json.Unmarshal([]byte(member), &event); err != nil {
+			return nil, fmt.Errorf("failed to decode spend event from redis: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// Compact removes events with Timestamp < before from the sorted set.
+func (s *RedisSpendStore) Compact(before time.Time) error {
+	err := s.client.ZRemRangeByScore(s.ctx, s.key, "-inf", fmt.Sprintf("(%d", before.UnixNano())).Err()
+	if err != nil {
+		return fmt.Errorf("failed to compact spend events in redis: %w", err)
+	}
+	return nil
+}
+
+// Close is a no-op; the *redis.Client is owned by the caller.
+func (s *RedisSpendStore) Close() error {
+	return nil
+}
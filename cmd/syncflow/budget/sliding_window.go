@@ -18,6 +18,10 @@ type SlidingWindowBudget struct {
 
 	// Spend tracking
 	spendHistory []SpendEvent
+
+	// store persists spendHistory so burst-protection state survives a
+	// process restart. Nil means in-memory only (the historical behavior).
+	store SpendStore
 }
 
 // SpendEvent tracks a single spend occurrence
@@ -37,6 +41,26 @@ func NewSlidingWindowBudget(windowDuration time.Duration, maxBurstLimit float64)
 	}
 }
 
+// NewSlidingWindowBudgetWithStore creates a budgeter whose spendHistory is
+// persisted to store. The store is replayed on startup so burst-protection
+// state survives a process restart or crash-loop, and every RecordSpend is
+// appended to the store before it is accepted in memory.
+func NewSlidingWindowBudgetWithStore(windowDuration time.Duration, maxBurstLimit float64, store SpendStore) (*SlidingWindowBudget, error) {
+	b := &SlidingWindowBudget{
+		WindowDuration: windowDuration,
+		MaxBurstLimit:  maxBurstLimit,
+		store:          store,
+	}
+
+	events, err := store.LoadSince(time.Now().Add(-windowDuration))
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay spend store: %w", err)
+	}
+	b.spendHistory = events
+
+	return b, nil
+}
+
 // RecordSpend adds a spend event to the sliding window
 // Returns error if the spend would exceed the burst limit
 func (b *SlidingWindowBudget) RecordSpend(amount float64, platform, requestID string) error {
@@ -60,13 +84,22 @@ func (b *SlidingWindowBudget) RecordSpend(amount float64, platform, requestID st
 		}
 	}
 
-	// Record the spend
-	b.spendHistory = append(b.spendHistory, SpendEvent{
+	event := SpendEvent{
 		Timestamp: now,
 		Amount:    amount,
 		Platform:  platform,
 		RequestID: requestID,
-	})
+	}
+
+	// Persist before accepting the spend in memory, so a crash between the
+	// two never loses burst-protection state.
+	if b.store != nil {
+		if err := b.store.Append(event); err != nil {
+			return fmt.Errorf("failed to persist spend event: %w", err)
+		}
+	}
+
+	b.spendHistory = append(b.spendHistory, event)
 
 	return nil
 }
@@ -133,6 +166,21 @@ func (b *SlidingWindowBudget) evictOldEvents(now time.Time) {
 	}
 }
 
+// CompactStore drops events older than the current window from the durable
+// store. It is cheap to call from an in-memory budgeter (store is nil), so
+// callers can run it on a timer regardless of configuration.
+func (b *SlidingWindowBudget) CompactStore() error {
+	b.mu.RLock()
+	store := b.store
+	cutoff := time.Now().Add(-b.WindowDuration)
+	b.mu.RUnlock()
+
+	if store == nil {
+		return nil
+	}
+	return store.Compact(cutoff)
+}
+
 // GetBudgetStatus returns current budget utilization
 func (b *SlidingWindowBudget) GetBudgetStatus() BudgetStatus {
 	b.mu.RLock()
@@ -0,0 +1,23 @@
+package budget
+
+import "time"
+
+// SpendStore persists SpendEvents so SlidingWindowBudget's burst-protection
+// state survives a process restart. Implementations must be safe for
+// concurrent use.
+type SpendStore interface {
+	// Append durably records event. It must not return until event would
+	// survive a crash (e.g. fsync'd to disk, or acknowledged by Redis).
+	Append(event SpendEvent) error
+
+	// LoadSince returns every persisted event with Timestamp >= t, in the
+	// order they were appended.
+	LoadSince(t time.Time) ([]SpendEvent, error)
+
+	// Compact discards events with Timestamp < before. It is safe to call
+	// repeatedly and concurrently with Append/LoadSince.
+	Compact(before time.Time) error
+
+	// Close releases any resources held by the store.
+	Close() error
+}
@@ -1,148 +1,238 @@
-package connectors
-
-import (
-	"context"
-	"fmt"
-	"log"
-	"net/http"
-	"time"
-
-	"github.com/user/kiki-agent/cmd/syncshield/shield"
-)
-
-// AmazonSmartConnector implements PlatformConnector for Amazon Advertising API with budget management
-type AmazonSmartConnector struct {
-	APIKey         string
-	ProfileID      string
-	HttpClient     *http.Client
-	Connected      bool
-	BaseURL        string
-	BudgetManager  *shield.BudgetManager
-	RateLimiter    *RateLimiter
-	CircuitBreaker *shield.CircuitBreaker
-	FallbackEngine *HeuristicFallbackEngine
-	MockMode       bool
-}
-
-// NewAmazonSmartConnector creates a new Amazon connector with budget management
-func NewAmazonSmartConnector(apiKey, profileID string, maxBudget float64) *AmazonSmartConnector {
-	return &AmazonSmartConnector{
-		APIKey:         apiKey,
-		ProfileID:      profileID,
-		HttpClient:     &http.Client{Timeout: 10 * time.Second},
-		BaseURL:        "https://advertising-api.amazon.com/v3",
-		BudgetManager:  shield.NewBudgetManager(maxBudget),
-		RateLimiter:    NewRateLimiter(50),
-		CircuitBreaker: shield.NewCircuitBreaker(),
-		FallbackEngine: NewHeuristicFallbackEngine(),
-	}
-}
-
-// Connect establishes connection to Amazon Advertising API
-func (a *AmazonSmartConnector) Connect(ctx context.Context) error {
-	log.Printf("🔗 Connecting to Amazon Advertising Smart Connector for profile: %s", a.ProfileID)
-	a.Connected = true
-	stats := a.BudgetManager.GetStats()
-	log.Printf("✅ Amazon Smart connection established - Budget: $%.2f/$%.2f", stats.CurrentSpend, stats.MaxBudget)
-	return nil
-}
-
-// PlaceBid sends a bid to Amazon with safety checks
-func (a *AmazonSmartConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
-	if !a.Connected {
-		return nil, fmt.Errorf("not connected to Amazon Advertising")
-	}
-
-	// Record latest LTV to improve fallback median quality
-	a.FallbackEngine.RecordLTV("amazon", req.PredictedLTV)
-
-	// Decide bid source via circuit breaker
-	bidAmount := req.BidAmount
-	decisionSource := "ai"
-	if !a.CircuitBreaker.CanExecute() {
-		a.CircuitBreaker.RecordFallback()
-		bidAmount = a.FallbackEngine.CalculateFallbackBid("amazon", req.PredictedLTV)
-		decisionSource = "fallback"
-	}
-
-	if !a.RateLimiter.CanMakeCall() {
-		return nil, fmt.Errorf("rate limit exceeded for Amazon Advertising")
-	}
-
-	if !a.BudgetManager.CanSpend(bidAmount) {
-		stats := a.BudgetManager.GetStats()
-		log.Printf("🛡️ BUDGET VETO: Amazon bid $%.2f exceeds remaining budget $%.2f", bidAmount, stats.RemainingBudget)
-		return &BidResponse{
-			Success:      false,
-			Message:      fmt.Sprintf("Budget exceeded: $%.2f spent of $%.2f limit", stats.CurrentSpend, stats.MaxBudget),
-			PlatformCode: "BUDGET_EXCEEDED",
-			Timestamp:    time.Now(),
-		}, fmt.Errorf("budget exceeded")
-	}
-
-	log.Printf("📍 PlaceBid (Amazon): Customer=%s, LTV=%.2f, Bid=$%.2f (source=%s)", req.CustomerID, req.PredictedLTV, bidAmount, decisionSource)
-
-	callStart := time.Now()
-
-	if a.MockMode {
-		log.Printf("🧪 MOCK MODE: Simulating Amazon Advertising API call")
-		a.RateLimiter.RecordCall()
-		a.BudgetManager.AddSpend(bidAmount)
-		stats := a.BudgetManager.GetStats()
-		log.Printf("✅ Amazon bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
-		a.CircuitBreaker.RecordSuccess(time.Since(callStart))
-
-		return &BidResponse{
-			Success:      true,
-			BidID:        fmt.Sprintf("MOCK_AMAZON_%d", time.Now().Unix()),
-			Message:      "Campaign budget optimized via Amazon Advertising API (MOCK)",
-			PlatformCode: "AMAZON_ADS_SMART",
-			Timestamp:    time.Now(),
-		}, nil
-	}
-
-	a.RateLimiter.RecordCall()
-	a.BudgetManager.AddSpend(bidAmount)
-	stats := a.BudgetManager.GetStats()
-	log.Printf("✅ Amazon bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
-	a.CircuitBreaker.RecordSuccess(time.Since(callStart))
-
-	return &BidResponse{
-		Success:      true,
-		BidID:        fmt.Sprintf("AMAZON_%d", time.Now().Unix()),
-		Message:      "Bid sent to Amazon Advertising API",
-		PlatformCode: "AMAZON_ADS_SMART",
-		Timestamp:    time.Now(),
-	}, nil
-}
-
-// GetBudgetStats returns budget statistics
-func (a *AmazonSmartConnector) GetBudgetStats() shield.WindowStats {
-	return a.BudgetManager.GetStats()
-}
-
-// GetStatus returns connection status
-func (a *AmazonSmartConnector) GetStatus() string {
-	if a.Connected {
-		stats := a.BudgetManager.GetStats()
-		return fmt.Sprintf("Connected to Amazon Advertising - Budget: $%.2f/$%.2f", stats.CurrentSpend, stats.MaxBudget)
-	}
-	return "Disconnected from Amazon Advertising"
-}
-
-// Close closes the connection
-func (a *AmazonSmartConnector) Close() error {
-	log.Printf("🔌 Amazon connection closed - Final spend: $%.2f", a.BudgetManager.GetStats().CurrentSpend)
-	a.Connected = false
-	return nil
-}
-
-// UpdateCampaignBudget updates campaign budget
-func (a *AmazonSmartConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
-// UpdateTargetAudience updates target audience
-func (a *AmazonSmartConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
-	return nil, fmt.Errorf("not implemented")
-}
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/observability"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// AmazonSmartConnector implements PlatformConnector for Amazon Advertising API with budget management
+type AmazonSmartConnector struct {
+	APIKey         string
+	ProfileID      string
+	HttpClient     *http.Client
+	Connected      bool
+	BaseURL        string
+	BudgetManager  *shield.BudgetManager
+	RateLimiter    *RateLimiter
+	CircuitBreaker *shield.CircuitBreaker
+	FallbackEngine *HeuristicFallbackEngine
+	MockMode       bool
+	// ShieldURL is the SyncShield compliance API to consult for per-customer
+	// consent before placing a bid. Empty disables the check (e.g. in tests
+	// that never start a SyncShield instance).
+	ShieldURL string
+	// SpendLedger, if set, records every successful bid so a crash can
+	// later be reconciled against Amazon's reporting API via
+	// connectors.FindLCA/Rewind. Left nil, bids simply aren't ledgered.
+	SpendLedger SpendLedger
+}
+
+// NewAmazonSmartConnector creates a new Amazon connector with budget management
+func NewAmazonSmartConnector(apiKey, profileID string, maxBudget float64) *AmazonSmartConnector {
+	return &AmazonSmartConnector{
+		APIKey:         apiKey,
+		ProfileID:      profileID,
+		HttpClient:     &http.Client{Timeout: 10 * time.Second},
+		BaseURL:        "https://advertising-api.amazon.com/v3",
+		BudgetManager:  shield.NewBudgetManager(maxBudget),
+		RateLimiter:    NewRateLimiter(50),
+		CircuitBreaker: shield.NewCircuitBreaker(),
+		FallbackEngine: NewHeuristicFallbackEngine(),
+		ShieldURL:      os.Getenv("SHIELD_URL"),
+	}
+}
+
+// Connect establishes connection to Amazon Advertising API
+func (a *AmazonSmartConnector) Connect(ctx context.Context) error {
+	log.Printf("🔗 Connecting to Amazon Advertising Smart Connector for profile: %s", a.ProfileID)
+	a.Connected = true
+	stats := a.BudgetManager.GetStats()
+	log.Printf("✅ Amazon Smart connection established - Budget: $%.2f/$%.2f", stats.CurrentSpend, stats.MaxBudget)
+	return nil
+}
+
+// PlaceBid sends a bid to Amazon with safety checks
+func (a *AmazonSmartConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	span := observability.StartSpan("AmazonSmartConnector.PlaceBid")
+	defer func() {
+		observability.Default.SetCircuitState("amazon", circuitMetricState(a.CircuitBreaker.GetState()))
+		snap := a.RateLimiter.Snapshot()
+		observability.Default.SetRateLimiterState("amazon", snap.TokensAvailable, snap.Concurrency, snap.Recent429Rate)
+	}()
+
+	if !a.Connected {
+		return nil, fmt.Errorf("not connected to Amazon Advertising")
+	}
+
+	if a.ShieldURL != "" {
+		allowed, err := checkShieldConsent(a.ShieldURL, req.CustomerID, defaultConsentPurpose, req.PredictedLTV)
+		if err != nil {
+			log.Printf("⚠️ Shield consent check unreachable for %s, failing closed: %v [trace=%s]", req.CustomerID, err, span.String())
+			observability.Default.RecordVeto("consent_check_unavailable")
+			return &BidResponse{
+				Success:      false,
+				Message:      fmt.Sprintf("Consent check unavailable [trace=%s]", span.String()),
+				PlatformCode: "CONSENT_CHECK_FAILED",
+				Timestamp:    time.Now(),
+			}, fmt.Errorf("consent check unavailable: %w", err)
+		}
+		if !allowed {
+			log.Printf("🛡️ CONSENT VETO: customer=%s missing consent for %s [trace=%s]", req.CustomerID, defaultConsentPurpose, span.String())
+			observability.Default.RecordVeto("consent_missing")
+			return &BidResponse{
+				Success:      false,
+				Message:      fmt.Sprintf("Consent missing for customer %s [trace=%s]", req.CustomerID, span.String()),
+				PlatformCode: "CONSENT_MISSING",
+				Timestamp:    time.Now(),
+			}, fmt.Errorf("consent missing for customer %s", req.CustomerID)
+		}
+	}
+
+	// Record latest LTV to improve fallback median quality
+	a.FallbackEngine.RecordLTV("amazon", req.PredictedLTV)
+
+	// Decide bid source via circuit breaker
+	bidAmount := req.BidAmount
+	decisionSource := "ai"
+	if !a.CircuitBreaker.CanExecute() {
+		a.CircuitBreaker.RecordFallback()
+		observability.Default.RecordFallback("amazon")
+		a.RateLimiter.RecordFailure()
+		bidAmount = a.FallbackEngine.CalculateFallbackBid("amazon", req.PredictedLTV)
+		decisionSource = "fallback"
+	}
+
+	if err := a.RateLimiter.Acquire(ctx); err != nil {
+		observability.Default.RecordBid("amazon", decisionSource, "rate_limited", bidAmount)
+		return nil, fmt.Errorf("rate limit exceeded for Amazon Advertising: %w", err)
+	}
+	defer a.RateLimiter.Release()
+
+	if !a.BudgetManager.CanSpend(bidAmount) {
+		stats := a.BudgetManager.GetStats()
+		observability.Default.SetBudgetRemaining("amazon", stats.RemainingBudget)
+		observability.Default.RecordBid("amazon", decisionSource, "budget_exceeded", bidAmount)
+		log.Printf("🛡️ BUDGET VETO: Amazon bid $%.2f exceeds remaining budget $%.2f [trace=%s]", bidAmount, stats.RemainingBudget, span.String())
+		return &BidResponse{
+			Success:      false,
+			Message:      fmt.Sprintf("Budget exceeded: $%.2f spent of $%.2f limit [trace=%s]", stats.CurrentSpend, stats.MaxBudget, span.String()),
+			PlatformCode: "BUDGET_EXCEEDED",
+			Timestamp:    time.Now(),
+		}, shield.NewError(shield.ErrorKindBudgetExceeded, "amazon", a.CircuitBreaker.GetState(), fmt.Errorf("budget exceeded"))
+	}
+
+	log.Printf("📍 PlaceBid (Amazon): Customer=%s, LTV=%.2f, Bid=$%.2f (source=%s) [trace=%s]", req.CustomerID, req.PredictedLTV, bidAmount, decisionSource, span.String())
+
+	callStart := time.Now()
+
+	if a.MockMode {
+		log.Printf("🧪 MOCK MODE: Simulating Amazon Advertising API call")
+		a.BudgetManager.AddSpend(bidAmount)
+		stats := a.BudgetManager.GetStats()
+		observability.Default.SetBudgetRemaining("amazon", stats.RemainingBudget)
+		observability.Default.RecordBid("amazon", decisionSource, "success", bidAmount)
+		log.Printf("✅ Amazon bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
+		a.CircuitBreaker.RecordSuccess(time.Since(callStart))
+		a.RateLimiter.RecordSuccess(time.Since(callStart))
+
+		resp := &BidResponse{
+			Success:      true,
+			BidAmount:    bidAmount,
+			BidID:        fmt.Sprintf("MOCK_AMAZON_%d", time.Now().Unix()),
+			Message:      fmt.Sprintf("Campaign budget optimized via Amazon Advertising API (MOCK) [trace=%s]", span.String()),
+			PlatformCode: "AMAZON_ADS_SMART",
+			Timestamp:    time.Now(),
+		}
+		recordIfLedgered(ctx, a.SpendLedger, "amazon", req, resp)
+		return resp, nil
+	}
+
+	a.BudgetManager.AddSpend(bidAmount)
+	stats := a.BudgetManager.GetStats()
+	observability.Default.SetBudgetRemaining("amazon", stats.RemainingBudget)
+	observability.Default.RecordBid("amazon", decisionSource, "success", bidAmount)
+	log.Printf("✅ Amazon bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
+	a.CircuitBreaker.RecordSuccess(time.Since(callStart))
+	a.RateLimiter.RecordSuccess(time.Since(callStart))
+
+	resp := &BidResponse{
+		Success:      true,
+		BidAmount:    bidAmount,
+		BidID:        fmt.Sprintf("AMAZON_%d", time.Now().Unix()),
+		Message:      fmt.Sprintf("Bid sent to Amazon Advertising API [trace=%s]", span.String()),
+		PlatformCode: "AMAZON_ADS_SMART",
+		Timestamp:    time.Now(),
+	}
+	recordIfLedgered(ctx, a.SpendLedger, "amazon", req, resp)
+	return resp, nil
+}
+
+// circuitMetricState maps a shield.CircuitBreakerState onto the
+// observability package's platform-agnostic gauge values.
+func circuitMetricState(state shield.CircuitBreakerState) observability.CircuitBreakerState {
+	switch state {
+	case shield.OPEN:
+		return observability.CircuitOpen
+	case shield.HALF_OPEN:
+		return observability.CircuitHalfOpen
+	default:
+		return observability.CircuitClosed
+	}
+}
+
+// GetBudgetStats returns budget statistics
+func (a *AmazonSmartConnector) GetBudgetStats() shield.WindowStats {
+	return a.BudgetManager.GetStats()
+}
+
+// GetRateLimiterSnapshot returns the current token bucket and adaptive
+// concurrency state, for /health and Prometheus export.
+func (a *AmazonSmartConnector) GetRateLimiterSnapshot() RateLimiterSnapshot {
+	return a.RateLimiter.Snapshot()
+}
+
+// GetCircuitBreaker exposes the connector's CircuitBreaker so callers like
+// auction.Auctioneer can check it before fanning out a bid.
+func (a *AmazonSmartConnector) GetCircuitBreaker() *shield.CircuitBreaker {
+	return a.CircuitBreaker
+}
+
+// GetBudgetManager exposes the connector's BudgetManager so callers like
+// reconcile's rewind can correct its live spend state, not just a
+// throwaway local copy.
+func (a *AmazonSmartConnector) GetBudgetManager() *shield.BudgetManager {
+	return a.BudgetManager
+}
+
+// GetStatus returns connection status
+func (a *AmazonSmartConnector) GetStatus() string {
+	if a.Connected {
+		stats := a.BudgetManager.GetStats()
+		return fmt.Sprintf("Connected to Amazon Advertising - Budget: $%.2f/$%.2f", stats.CurrentSpend, stats.MaxBudget)
+	}
+	return "Disconnected from Amazon Advertising"
+}
+
+// Close closes the connection
+func (a *AmazonSmartConnector) Close() error {
+	log.Printf("🔌 Amazon connection closed - Final spend: $%.2f", a.BudgetManager.GetStats().CurrentSpend)
+	a.Connected = false
+	return nil
+}
+
+// UpdateCampaignBudget updates campaign budget
+func (a *AmazonSmartConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// UpdateTargetAudience updates target audience
+func (a *AmazonSmartConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
+	return nil, fmt.Errorf("not implemented")
+}
@@ -0,0 +1,131 @@
+package analytics
+
+import (
+	"sync"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+	"github.com/user/kiki-agent/cmd/syncshield/observability"
+)
+
+const fanoutRecorderDefaultQueueSize = 1000
+
+// FanoutRecorder fans every Recorder hook out to a fixed set of children
+// without ever blocking the caller's hot PlaceBid path on a slow one: each
+// hook is enqueued onto a bounded channel and a background goroutine
+// drains it, dispatching to every child concurrently - the same
+// fan-out-after-the-fact trade-off audit.AuditLogger.fanOutToSinks makes
+// for its own sinks. A full queue drops the oldest pending hook to make
+// room for the new one, so a sustained burst costs history, never
+// freshness or caller latency.
+type FanoutRecorder struct {
+	name     string // identifies this fan-out in dropped-event metrics
+	children []connectors.Recorder
+
+	queue chan func(connectors.Recorder)
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewFanoutRecorder creates a FanoutRecorder draining into children with a
+// queue of queueSize hooks (falling back to a package default when
+// non-positive) and starts its background drain loop. name identifies this
+// fan-out in the kiki_analytics_sink_dropped_total metric, so operators
+// running more than one FanoutRecorder can tell their drops apart.
+func NewFanoutRecorder(name string, queueSize int, children ...connectors.Recorder) *FanoutRecorder {
+	if queueSize <= 0 {
+		queueSize = fanoutRecorderDefaultQueueSize
+	}
+	f := &FanoutRecorder{
+		name:     name,
+		children: children,
+		queue:    make(chan func(connectors.Recorder), queueSize),
+		stop:     make(chan struct{}),
+	}
+	f.wg.Add(1)
+	go f.drain()
+	return f
+}
+
+// Close stops the background drain loop once it finishes whatever hook it
+// is currently dispatching; anything still queued is discarded.
+func (f *FanoutRecorder) Close() error {
+	close(f.stop)
+	f.wg.Wait()
+	return nil
+}
+
+// enqueue queues fn for dispatch to every child, dropping the oldest
+// already-queued hook (and recording the drop) if the queue is full.
+func (f *FanoutRecorder) enqueue(fn func(connectors.Recorder)) {
+	for {
+		select {
+		case f.queue <- fn:
+			return
+		default:
+			select {
+			case <-f.queue:
+				observability.Default.RecordAnalyticsSinkDrop(f.name)
+			default:
+			}
+		}
+	}
+}
+
+func (f *FanoutRecorder) drain() {
+	defer f.wg.Done()
+	for {
+		select {
+		case fn := <-f.queue:
+			f.dispatch(fn)
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+// dispatch runs fn against every child concurrently, so one slow child
+// Recorder can't delay the others.
+func (f *FanoutRecorder) dispatch(fn func(connectors.Recorder)) {
+	var wg sync.WaitGroup
+	for _, child := range f.children {
+		if child == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(child connectors.Recorder) {
+			defer wg.Done()
+			fn(child)
+		}(child)
+	}
+	wg.Wait()
+}
+
+// LogBid implements connectors.Recorder.
+func (f *FanoutRecorder) LogBid(req *connectors.BidRequest, resp *connectors.BidResponse, meta connectors.BidMeta) {
+	f.enqueue(func(r connectors.Recorder) { r.LogBid(req, resp, meta) })
+}
+
+// LogBudgetVeto implements connectors.Recorder.
+func (f *FanoutRecorder) LogBudgetVeto(platform string, bidAmount, remainingBudget float64) {
+	f.enqueue(func(r connectors.Recorder) { r.LogBudgetVeto(platform, bidAmount, remainingBudget) })
+}
+
+// LogFallback implements connectors.Recorder.
+func (f *FanoutRecorder) LogFallback(platform string, aiBidAmount, fallbackBidAmount float64) {
+	f.enqueue(func(r connectors.Recorder) { r.LogFallback(platform, aiBidAmount, fallbackBidAmount) })
+}
+
+// LogRateLimit implements connectors.Recorder.
+func (f *FanoutRecorder) LogRateLimit(platform string) {
+	f.enqueue(func(r connectors.Recorder) { r.LogRateLimit(platform) })
+}
+
+// LogAuctionOutcome implements connectors.Recorder.
+func (f *FanoutRecorder) LogAuctionOutcome(platform string, won bool, clearPrice float64) {
+	f.enqueue(func(r connectors.Recorder) { r.LogAuctionOutcome(platform, won, clearPrice) })
+}
+
+// LogDecision implements connectors.Recorder.
+func (f *FanoutRecorder) LogDecision(customerID string, predictedLTV, bidAmount float64, decision, mode string, tags map[string]string) {
+	f.enqueue(func(r connectors.Recorder) { r.LogDecision(customerID, predictedLTV, bidAmount, decision, mode, tags) })
+}
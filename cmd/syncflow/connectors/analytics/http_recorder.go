@@ -0,0 +1,225 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+)
+
+const (
+	httpRecorderDefaultBatchSize   = 50
+	httpRecorderDefaultFlushPeriod = 5 * time.Second
+	httpRecorderDefaultMaxRetries  = 3
+	httpRecorderDefaultBackoffBase = 200 * time.Millisecond
+	httpRecorderDefaultTimeout     = 5 * time.Second
+)
+
+// HTTPRecorder buffers hook events and POSTs them in batches to URL, the
+// same trade-off BeaconExporter makes for audit entries: fewer, larger
+// requests beat one request per event without making every PlaceBid call
+// wait on the network. Safe for concurrent use.
+type HTTPRecorder struct {
+	URL         string
+	BatchSize   int
+	FlushPeriod time.Duration
+	MaxRetries  int
+	BackoffBase time.Duration
+
+	// Headers is injected into every POST (e.g. Authorization, a tenant
+	// ID) on top of the Content-Type this recorder always sets itself.
+	Headers map[string]string
+
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	pending []jsonlEvent
+
+	flushOnce sync.Once
+	stop      chan struct{}
+}
+
+// NewHTTPRecorder creates an HTTPRecorder posting batches to url on a
+// background timer, applying the package defaults for anything the caller
+// leaves at its zero value.
+func NewHTTPRecorder(url string) *HTTPRecorder {
+	h := &HTTPRecorder{
+		URL:         url,
+		BatchSize:   httpRecorderDefaultBatchSize,
+		FlushPeriod: httpRecorderDefaultFlushPeriod,
+		MaxRetries:  httpRecorderDefaultMaxRetries,
+		BackoffBase: httpRecorderDefaultBackoffBase,
+		httpClient:  &http.Client{Timeout: httpRecorderDefaultTimeout},
+		stop:        make(chan struct{}),
+	}
+	go h.flushLoop()
+	return h
+}
+
+// flushLoop periodically flushes whatever has accumulated since the last
+// tick, so a quiet connector still ships its events within FlushPeriod
+// instead of waiting for BatchSize to fill.
+func (h *HTTPRecorder) flushLoop() {
+	ticker := time.NewTicker(h.FlushPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.flush()
+		case <-h.stop:
+			return
+		}
+	}
+}
+
+// Close flushes any buffered events and stops the background flush timer.
+func (h *HTTPRecorder) Close() error {
+	h.flushOnce.Do(func() { close(h.stop) })
+	h.flush()
+	return nil
+}
+
+// enqueue appends event to the pending batch, flushing immediately if
+// BatchSize has been reached.
+func (h *HTTPRecorder) enqueue(event jsonlEvent) {
+	h.mu.Lock()
+	h.pending = append(h.pending, event)
+	full := len(h.pending) >= h.BatchSize
+	h.mu.Unlock()
+
+	if full {
+		h.flush()
+	}
+}
+
+// flush sends whatever is currently pending, retrying with exponential
+// backoff on failure. A failed flush drops the batch rather than
+// re-queuing it - same trade-off BeaconExporter makes - so a sustained
+// outage can't make the buffer grow without bound.
+func (h *HTTPRecorder) flush() {
+	h.mu.Lock()
+	if len(h.pending) == 0 {
+		h.mu.Unlock()
+		return
+	}
+	batch := h.pending
+	h.pending = nil
+	h.mu.Unlock()
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		fmt.Printf("⚠️  HTTPRecorder: failed to marshal batch: %v\n", err)
+		return
+	}
+
+	for attempt := 0; attempt <= h.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(h.BackoffBase * time.Duration(uint(1)<<uint(attempt-1)))
+		}
+		if err := h.post(body); err != nil {
+			fmt.Printf("⚠️  HTTPRecorder: post attempt %d/%d failed: %v\n", attempt+1, h.MaxRetries+1, err)
+			continue
+		}
+		return
+	}
+}
+
+// post sends one attempt of body to h.URL.
+func (h *HTTPRecorder) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("recorder endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// LogBid implements connectors.Recorder.
+func (h *HTTPRecorder) LogBid(req *connectors.BidRequest, resp *connectors.BidResponse, meta connectors.BidMeta) {
+	h.enqueue(jsonlEvent{
+		Type:           "bid",
+		Timestamp:      time.Now(),
+		Platform:       meta.Platform,
+		CustomerID:     req.CustomerID,
+		CampaignID:     req.CampaignID,
+		BidAmount:      resp.BidAmount,
+		Success:        resp.Success,
+		Message:        resp.Message,
+		DecisionSource: meta.DecisionSource,
+		TargetROAS:     meta.TargetROAS,
+		LatencyMS:      meta.Latency.Milliseconds(),
+	})
+}
+
+// LogBudgetVeto implements connectors.Recorder.
+func (h *HTTPRecorder) LogBudgetVeto(platform string, bidAmount, remainingBudget float64) {
+	h.enqueue(jsonlEvent{
+		Type:            "budget_veto",
+		Timestamp:       time.Now(),
+		Platform:        platform,
+		BidAmount:       bidAmount,
+		RemainingBudget: remainingBudget,
+	})
+}
+
+// LogFallback implements connectors.Recorder.
+func (h *HTTPRecorder) LogFallback(platform string, aiBidAmount, fallbackBidAmount float64) {
+	h.enqueue(jsonlEvent{
+		Type:           "fallback",
+		Timestamp:      time.Now(),
+		Platform:       platform,
+		AIBidAmount:    aiBidAmount,
+		FallbackAmount: fallbackBidAmount,
+	})
+}
+
+// LogRateLimit implements connectors.Recorder.
+func (h *HTTPRecorder) LogRateLimit(platform string) {
+	h.enqueue(jsonlEvent{
+		Type:      "rate_limit",
+		Timestamp: time.Now(),
+		Platform:  platform,
+	})
+}
+
+// LogAuctionOutcome implements connectors.Recorder.
+func (h *HTTPRecorder) LogAuctionOutcome(platform string, won bool, clearPrice float64) {
+	h.enqueue(jsonlEvent{
+		Type:       "auction_outcome",
+		Timestamp:  time.Now(),
+		Platform:   platform,
+		Won:        won,
+		ClearPrice: clearPrice,
+	})
+}
+
+// LogDecision implements connectors.Recorder.
+func (h *HTTPRecorder) LogDecision(customerID string, predictedLTV, bidAmount float64, decision, mode string, tags map[string]string) {
+	h.enqueue(jsonlEvent{
+		Type:         "decision",
+		Timestamp:    time.Now(),
+		CustomerID:   customerID,
+		PredictedLTV: predictedLTV,
+		BidAmount:    bidAmount,
+		Decision:     decision,
+		Mode:         mode,
+		Tags:         tags,
+	})
+}
@@ -0,0 +1,153 @@
+// Package analytics provides out-of-the-box connectors.Recorder
+// implementations, the same way Prebid ships a filesystem, HTTP, and
+// Prometheus analytics adapter alongside the analytics interface itself.
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+)
+
+// jsonlEvent is the line-delimited record written by JSONLRecorder. Type
+// distinguishes which Recorder hook produced the line since all six share
+// one file.
+type jsonlEvent struct {
+	Type            string            `json:"type"`
+	Timestamp       time.Time         `json:"timestamp"`
+	Platform        string            `json:"platform"`
+	CustomerID      string            `json:"customer_id,omitempty"`
+	CampaignID      string            `json:"campaign_id,omitempty"`
+	BidAmount       float64           `json:"bid_amount,omitempty"`
+	RemainingBudget float64           `json:"remaining_budget,omitempty"`
+	AIBidAmount     float64           `json:"ai_bid_amount,omitempty"`
+	FallbackAmount  float64           `json:"fallback_bid_amount,omitempty"`
+	Success         bool              `json:"success,omitempty"`
+	Message         string            `json:"message,omitempty"`
+	DecisionSource  string            `json:"decision_source,omitempty"`
+	TargetROAS      float64           `json:"target_roas,omitempty"`
+	LatencyMS       int64             `json:"latency_ms,omitempty"`
+	Won             bool              `json:"won,omitempty"`
+	ClearPrice      float64           `json:"clear_price,omitempty"`
+	PredictedLTV    float64           `json:"predicted_ltv,omitempty"`
+	Decision        string            `json:"decision,omitempty"`
+	Mode            string            `json:"mode,omitempty"`
+	Tags            map[string]string `json:"tags,omitempty"`
+}
+
+// JSONLRecorder appends every hook as one JSON object per line to a file,
+// mirroring Prebid's filesystem analytics module. Safe for concurrent use.
+type JSONLRecorder struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewJSONLRecorder opens (creating if necessary, appending if it exists)
+// path for writing and returns a Recorder backed by it. Callers should
+// Close it on shutdown to flush the underlying file handle.
+func NewJSONLRecorder(path string) (*JSONLRecorder, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JSONL recorder file: %w", err)
+	}
+	return &JSONLRecorder{file: f}, nil
+}
+
+// Close flushes and closes the underlying file.
+func (j *JSONLRecorder) Close() error {
+	return j.file.Close()
+}
+
+// write serializes event as one JSON line, guarding the shared file handle
+// so concurrent PlaceBid calls never interleave partial lines.
+func (j *JSONLRecorder) write(event jsonlEvent) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		fmt.Printf("⚠️  JSONLRecorder: failed to marshal event: %v\n", err)
+		return
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.file.Write(line); err != nil {
+		fmt.Printf("⚠️  JSONLRecorder: failed to write event: %v\n", err)
+	}
+}
+
+// LogBid implements connectors.Recorder.
+func (j *JSONLRecorder) LogBid(req *connectors.BidRequest, resp *connectors.BidResponse, meta connectors.BidMeta) {
+	j.write(jsonlEvent{
+		Type:           "bid",
+		Timestamp:      time.Now(),
+		Platform:       meta.Platform,
+		CustomerID:     req.CustomerID,
+		CampaignID:     req.CampaignID,
+		BidAmount:      resp.BidAmount,
+		Success:        resp.Success,
+		Message:        resp.Message,
+		DecisionSource: meta.DecisionSource,
+		TargetROAS:     meta.TargetROAS,
+		LatencyMS:      meta.Latency.Milliseconds(),
+	})
+}
+
+// LogBudgetVeto implements connectors.Recorder.
+func (j *JSONLRecorder) LogBudgetVeto(platform string, bidAmount, remainingBudget float64) {
+	j.write(jsonlEvent{
+		Type:            "budget_veto",
+		Timestamp:       time.Now(),
+		Platform:        platform,
+		BidAmount:       bidAmount,
+		RemainingBudget: remainingBudget,
+	})
+}
+
+// LogFallback implements connectors.Recorder.
+func (j *JSONLRecorder) LogFallback(platform string, aiBidAmount, fallbackBidAmount float64) {
+	j.write(jsonlEvent{
+		Type:           "fallback",
+		Timestamp:      time.Now(),
+		Platform:       platform,
+		AIBidAmount:    aiBidAmount,
+		FallbackAmount: fallbackBidAmount,
+	})
+}
+
+// LogRateLimit implements connectors.Recorder.
+func (j *JSONLRecorder) LogRateLimit(platform string) {
+	j.write(jsonlEvent{
+		Type:      "rate_limit",
+		Timestamp: time.Now(),
+		Platform:  platform,
+	})
+}
+
+// LogAuctionOutcome implements connectors.Recorder.
+func (j *JSONLRecorder) LogAuctionOutcome(platform string, won bool, clearPrice float64) {
+	j.write(jsonlEvent{
+		Type:       "auction_outcome",
+		Timestamp:  time.Now(),
+		Platform:   platform,
+		Won:        won,
+		ClearPrice: clearPrice,
+	})
+}
+
+// LogDecision implements connectors.Recorder.
+func (j *JSONLRecorder) LogDecision(customerID string, predictedLTV, bidAmount float64, decision, mode string, tags map[string]string) {
+	j.write(jsonlEvent{
+		Type:         "decision",
+		Timestamp:    time.Now(),
+		CustomerID:   customerID,
+		PredictedLTV: predictedLTV,
+		BidAmount:    bidAmount,
+		Decision:     decision,
+		Mode:         mode,
+		Tags:         tags,
+	})
+}
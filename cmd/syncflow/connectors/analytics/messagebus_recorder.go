@@ -0,0 +1,118 @@
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+)
+
+// Publisher is the minimal surface MessageBusRecorder needs from a message
+// bus client. Both a Kafka producer (Publish(topic, key, value []byte))
+// and a NATS connection (Publish(subject string, data []byte)) adapt to
+// this trivially, so this package depends on neither client library
+// directly - callers wire in whichever one their deployment already runs.
+type Publisher interface {
+	Publish(topic string, payload []byte) error
+}
+
+// MessageBusRecorder publishes each hook as one JSON message to Topic via
+// Publisher, for operators streaming bid events into Kafka/NATS for
+// downstream billing or BI rather than polling a file or HTTP sink.
+type MessageBusRecorder struct {
+	Publisher Publisher
+	Topic     string
+}
+
+// NewMessageBusRecorder creates a MessageBusRecorder publishing every hook
+// to topic through publisher.
+func NewMessageBusRecorder(publisher Publisher, topic string) *MessageBusRecorder {
+	return &MessageBusRecorder{Publisher: publisher, Topic: topic}
+}
+
+// publish marshals event and hands it to Publisher, logging rather than
+// returning an error since Recorder hooks are fire-and-forget.
+func (m *MessageBusRecorder) publish(event jsonlEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		fmt.Printf("⚠️  MessageBusRecorder: failed to marshal event: %v\n", err)
+		return
+	}
+	if err := m.Publisher.Publish(m.Topic, payload); err != nil {
+		fmt.Printf("⚠️  MessageBusRecorder: publish to %s failed: %v\n", m.Topic, err)
+	}
+}
+
+// LogBid implements connectors.Recorder.
+func (m *MessageBusRecorder) LogBid(req *connectors.BidRequest, resp *connectors.BidResponse, meta connectors.BidMeta) {
+	m.publish(jsonlEvent{
+		Type:           "bid",
+		Timestamp:      time.Now(),
+		Platform:       meta.Platform,
+		CustomerID:     req.CustomerID,
+		CampaignID:     req.CampaignID,
+		BidAmount:      resp.BidAmount,
+		Success:        resp.Success,
+		Message:        resp.Message,
+		DecisionSource: meta.DecisionSource,
+		TargetROAS:     meta.TargetROAS,
+		LatencyMS:      meta.Latency.Milliseconds(),
+	})
+}
+
+// LogBudgetVeto implements connectors.Recorder.
+func (m *MessageBusRecorder) LogBudgetVeto(platform string, bidAmount, remainingBudget float64) {
+	m.publish(jsonlEvent{
+		Type:            "budget_veto",
+		Timestamp:       time.Now(),
+		Platform:        platform,
+		BidAmount:       bidAmount,
+		RemainingBudget: remainingBudget,
+	})
+}
+
+// LogFallback implements connectors.Recorder.
+func (m *MessageBusRecorder) LogFallback(platform string, aiBidAmount, fallbackBidAmount float64) {
+	m.publish(jsonlEvent{
+		Type:           "fallback",
+		Timestamp:      time.Now(),
+		Platform:       platform,
+		AIBidAmount:    aiBidAmount,
+		FallbackAmount: fallbackBidAmount,
+	})
+}
+
+// LogRateLimit implements connectors.Recorder.
+func (m *MessageBusRecorder) LogRateLimit(platform string) {
+	m.publish(jsonlEvent{
+		Type:      "rate_limit",
+		Timestamp: time.Now(),
+		Platform:  platform,
+	})
+}
+
+// LogAuctionOutcome implements connectors.Recorder.
+func (m *MessageBusRecorder) LogAuctionOutcome(platform string, won bool, clearPrice float64) {
+	m.publish(jsonlEvent{
+		Type:       "auction_outcome",
+		Timestamp:  time.Now(),
+		Platform:   platform,
+		Won:        won,
+		ClearPrice: clearPrice,
+	})
+}
+
+// LogDecision implements connectors.Recorder.
+func (m *MessageBusRecorder) LogDecision(customerID string, predictedLTV, bidAmount float64, decision, mode string, tags map[string]string) {
+	m.publish(jsonlEvent{
+		Type:         "decision",
+		Timestamp:    time.Now(),
+		CustomerID:   customerID,
+		PredictedLTV: predictedLTV,
+		BidAmount:    bidAmount,
+		Decision:     decision,
+		Mode:         mode,
+		Tags:         tags,
+	})
+}
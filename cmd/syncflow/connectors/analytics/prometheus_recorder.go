@@ -0,0 +1,165 @@
+package analytics
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+)
+
+// PrometheusRecorder translates Recorder hooks into in-memory
+// counters/histograms and exposes them in Prometheus text format, the same
+// shape shield.PrometheusExporter renders for circuit breaker metrics.
+type PrometheusRecorder struct {
+	mu sync.Mutex
+
+	bidsTotal       map[string]int64 // platform -> count
+	bidSuccessTotal map[string]int64
+	budgetVetoes    map[string]int64
+	fallbacks       map[string]int64
+	rateLimits      map[string]int64
+	auctionWins     map[string]int64
+	auctionLosses   map[string]int64
+
+	latencySumMS map[string]float64
+	latencyCount map[string]int64
+
+	decisions map[string]int64 // "<decision>:<mode>" -> count
+}
+
+// NewPrometheusRecorder creates an empty PrometheusRecorder.
+func NewPrometheusRecorder() *PrometheusRecorder {
+	return &PrometheusRecorder{
+		bidsTotal:       make(map[string]int64),
+		bidSuccessTotal: make(map[string]int64),
+		budgetVetoes:    make(map[string]int64),
+		fallbacks:       make(map[string]int64),
+		rateLimits:      make(map[string]int64),
+		auctionWins:     make(map[string]int64),
+		auctionLosses:   make(map[string]int64),
+		latencySumMS:    make(map[string]float64),
+		latencyCount:    make(map[string]int64),
+		decisions:       make(map[string]int64),
+	}
+}
+
+// LogBid implements connectors.Recorder.
+func (p *PrometheusRecorder) LogBid(req *connectors.BidRequest, resp *connectors.BidResponse, meta connectors.BidMeta) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bidsTotal[meta.Platform]++
+	if resp.Success {
+		p.bidSuccessTotal[meta.Platform]++
+	}
+	p.latencySumMS[meta.Platform] += float64(meta.Latency) / float64(time.Millisecond)
+	p.latencyCount[meta.Platform]++
+}
+
+// LogBudgetVeto implements connectors.Recorder.
+func (p *PrometheusRecorder) LogBudgetVeto(platform string, bidAmount, remainingBudget float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.budgetVetoes[platform]++
+}
+
+// LogFallback implements connectors.Recorder.
+func (p *PrometheusRecorder) LogFallback(platform string, aiBidAmount, fallbackBidAmount float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fallbacks[platform]++
+}
+
+// LogRateLimit implements connectors.Recorder.
+func (p *PrometheusRecorder) LogRateLimit(platform string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rateLimits[platform]++
+}
+
+// LogAuctionOutcome implements connectors.Recorder.
+func (p *PrometheusRecorder) LogAuctionOutcome(platform string, won bool, clearPrice float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if won {
+		p.auctionWins[platform]++
+	} else {
+		p.auctionLosses[platform]++
+	}
+}
+
+// LogDecision implements connectors.Recorder.
+func (p *PrometheusRecorder) LogDecision(customerID string, predictedLTV, bidAmount float64, decision, mode string, tags map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.decisions[decision+":"+mode]++
+}
+
+// Render returns the current metrics in Prometheus text-exposition format,
+// suitable for serving at a /metrics endpoint alongside
+// shield.PrometheusExporter's own output.
+func (p *PrometheusRecorder) Render() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var builder strings.Builder
+
+	builder.WriteString("# HELP syncflow_bids_total Bids that reached the platform\n")
+	builder.WriteString("# TYPE syncflow_bids_total counter\n")
+	for platform, count := range p.bidsTotal {
+		builder.WriteString(fmt.Sprintf("syncflow_bids_total{platform=\"%s\"} %d\n", platform, count))
+	}
+
+	builder.WriteString("\n# HELP syncflow_bids_success_total Bids that succeeded\n")
+	builder.WriteString("# TYPE syncflow_bids_success_total counter\n")
+	for platform, count := range p.bidSuccessTotal {
+		builder.WriteString(fmt.Sprintf("syncflow_bids_success_total{platform=\"%s\"} %d\n", platform, count))
+	}
+
+	builder.WriteString("\n# HELP syncflow_budget_vetoes_total Bids blocked by BudgetManager before being sent\n")
+	builder.WriteString("# TYPE syncflow_budget_vetoes_total counter\n")
+	for platform, count := range p.budgetVetoes {
+		builder.WriteString(fmt.Sprintf("syncflow_budget_vetoes_total{platform=\"%s\"} %d\n", platform, count))
+	}
+
+	builder.WriteString("\n# HELP syncflow_fallback_bids_total Bids routed through HeuristicFallbackEngine\n")
+	builder.WriteString("# TYPE syncflow_fallback_bids_total counter\n")
+	for platform, count := range p.fallbacks {
+		builder.WriteString(fmt.Sprintf("syncflow_fallback_bids_total{platform=\"%s\"} %d\n", platform, count))
+	}
+
+	builder.WriteString("\n# HELP syncflow_rate_limited_total Calls throttled by RateLimiter\n")
+	builder.WriteString("# TYPE syncflow_rate_limited_total counter\n")
+	for platform, count := range p.rateLimits {
+		builder.WriteString(fmt.Sprintf("syncflow_rate_limited_total{platform=\"%s\"} %d\n", platform, count))
+	}
+
+	builder.WriteString("\n# HELP syncflow_auction_outcomes_total Auction win/loss notices\n")
+	builder.WriteString("# TYPE syncflow_auction_outcomes_total counter\n")
+	for platform, count := range p.auctionWins {
+		builder.WriteString(fmt.Sprintf("syncflow_auction_outcomes_total{platform=\"%s\",outcome=\"win\"} %d\n", platform, count))
+	}
+	for platform, count := range p.auctionLosses {
+		builder.WriteString(fmt.Sprintf("syncflow_auction_outcomes_total{platform=\"%s\",outcome=\"loss\"} %d\n", platform, count))
+	}
+
+	builder.WriteString("\n# HELP syncflow_decisions_total Bidding-loop decisions by outcome and mode\n")
+	builder.WriteString("# TYPE syncflow_decisions_total counter\n")
+	for key, count := range p.decisions {
+		decision, mode, _ := strings.Cut(key, ":")
+		builder.WriteString(fmt.Sprintf("syncflow_decisions_total{decision=\"%s\",mode=\"%s\"} %d\n", decision, mode, count))
+	}
+
+	builder.WriteString("\n# HELP syncflow_bid_latency_ms_avg Average PlaceBid latency in milliseconds\n")
+	builder.WriteString("# TYPE syncflow_bid_latency_ms_avg gauge\n")
+	for platform, count := range p.latencyCount {
+		avg := 0.0
+		if count > 0 {
+			avg = p.latencySumMS[platform] / float64(count)
+		}
+		builder.WriteString(fmt.Sprintf("syncflow_bid_latency_ms_avg{platform=\"%s\"} %.2f\n", platform, avg))
+	}
+
+	return builder.String()
+}
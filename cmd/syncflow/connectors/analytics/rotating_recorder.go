@@ -0,0 +1,142 @@
+package analytics
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+)
+
+const (
+	rotatingRecorderDefaultMaxBytes = 100 * 1024 * 1024 // 100MiB
+	rotatingRecorderDefaultMaxAge   = 24 * time.Hour
+)
+
+// RotatingRecorder wraps a JSONLRecorder and rolls it onto a fresh file
+// once the current one exceeds MaxBytes or MaxAge, the same size+time
+// trade-off logrotate makes: either bound alone lets a quiet path or a
+// busy one grow without limit. The rolled file is renamed with the
+// rotation instant so nothing already on disk is overwritten.
+type RotatingRecorder struct {
+	// Path is the active file JSONLRecorder writes to; rotation renames it
+	// to Path plus a timestamp suffix and reopens Path fresh.
+	Path     string
+	MaxBytes int64
+	MaxAge   time.Duration
+
+	mu       sync.Mutex
+	current  *JSONLRecorder
+	openedAt time.Time
+	written  int64
+}
+
+// NewRotatingRecorder opens (or creates) path and returns a Recorder that
+// rotates it once it crosses maxBytes or maxAge, whichever comes first. A
+// zero maxBytes/maxAge falls back to the package defaults.
+func NewRotatingRecorder(path string, maxBytes int64, maxAge time.Duration) (*RotatingRecorder, error) {
+	if maxBytes <= 0 {
+		maxBytes = rotatingRecorderDefaultMaxBytes
+	}
+	if maxAge <= 0 {
+		maxAge = rotatingRecorderDefaultMaxAge
+	}
+
+	r := &RotatingRecorder{Path: path, MaxBytes: maxBytes, MaxAge: maxAge}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open creates/truncates-appends r.Path into a fresh JSONLRecorder,
+// recording the current size so the next write can tell when MaxBytes is
+// crossed without stat-ing the file on every call.
+func (r *RotatingRecorder) open() error {
+	rec, err := NewJSONLRecorder(r.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open rotating recorder file: %w", err)
+	}
+
+	info, err := os.Stat(r.Path)
+	if err != nil {
+		rec.Close()
+		return fmt.Errorf("failed to stat rotating recorder file: %w", err)
+	}
+
+	r.current = rec
+	r.openedAt = time.Now()
+	r.written = info.Size()
+	return nil
+}
+
+// rotateIfNeeded renames the current file aside and opens a fresh one once
+// MaxBytes or MaxAge has been crossed. Callers must hold r.mu.
+func (r *RotatingRecorder) rotateIfNeeded(nextWriteSize int64) {
+	if r.written+nextWriteSize <= r.MaxBytes && time.Since(r.openedAt) < r.MaxAge {
+		return
+	}
+
+	r.current.Close()
+	rotated := fmt.Sprintf("%s.%s", r.Path, time.Now().Format("20060102T150405.000000000"))
+	if err := os.Rename(r.Path, rotated); err != nil {
+		fmt.Printf("⚠️  RotatingRecorder: failed to rotate %s: %v\n", r.Path, err)
+	}
+	if err := r.open(); err != nil {
+		fmt.Printf("⚠️  RotatingRecorder: failed to reopen %s after rotation: %v\n", r.Path, err)
+	}
+}
+
+// write rotates if needed, then delegates to the current JSONLRecorder and
+// tracks the appended size.
+func (r *RotatingRecorder) write(fn func(*JSONLRecorder)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rotateIfNeeded(0)
+	before := r.written
+	fn(r.current)
+	if info, err := os.Stat(r.Path); err == nil {
+		r.written = info.Size()
+	} else {
+		r.written = before
+	}
+}
+
+// LogBid implements connectors.Recorder.
+func (r *RotatingRecorder) LogBid(req *connectors.BidRequest, resp *connectors.BidResponse, meta connectors.BidMeta) {
+	r.write(func(j *JSONLRecorder) { j.LogBid(req, resp, meta) })
+}
+
+// LogBudgetVeto implements connectors.Recorder.
+func (r *RotatingRecorder) LogBudgetVeto(platform string, bidAmount, remainingBudget float64) {
+	r.write(func(j *JSONLRecorder) { j.LogBudgetVeto(platform, bidAmount, remainingBudget) })
+}
+
+// LogFallback implements connectors.Recorder.
+func (r *RotatingRecorder) LogFallback(platform string, aiBidAmount, fallbackBidAmount float64) {
+	r.write(func(j *JSONLRecorder) { j.LogFallback(platform, aiBidAmount, fallbackBidAmount) })
+}
+
+// LogRateLimit implements connectors.Recorder.
+func (r *RotatingRecorder) LogRateLimit(platform string) {
+	r.write(func(j *JSONLRecorder) { j.LogRateLimit(platform) })
+}
+
+// LogAuctionOutcome implements connectors.Recorder.
+func (r *RotatingRecorder) LogAuctionOutcome(platform string, won bool, clearPrice float64) {
+	r.write(func(j *JSONLRecorder) { j.LogAuctionOutcome(platform, won, clearPrice) })
+}
+
+// LogDecision implements connectors.Recorder.
+func (r *RotatingRecorder) LogDecision(customerID string, predictedLTV, bidAmount float64, decision, mode string, tags map[string]string) {
+	r.write(func(j *JSONLRecorder) { j.LogDecision(customerID, predictedLTV, bidAmount, decision, mode, tags) })
+}
+
+// Close flushes and closes the currently active file.
+func (r *RotatingRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current.Close()
+}
@@ -0,0 +1,142 @@
+// Package auction runs a single BidRequest against every registered
+// PlatformConnector concurrently, the way a header-bidding wrapper fans
+// one impression out to many demand partners in parallel rather than
+// calling them one at a time in sequence.
+package auction
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// CircuitBreakerAware is implemented by connectors that expose the
+// CircuitBreaker guarding their own calls. Auctioneer skips a bidder
+// whose breaker is OPEN instead of waiting out a PlaceBid call that's
+// very likely to fail.
+type CircuitBreakerAware interface {
+	GetCircuitBreaker() *shield.CircuitBreaker
+}
+
+// Bidder is one platform registered to participate in auctions.
+type Bidder struct {
+	Name      string
+	Connector connectors.PlatformConnector
+}
+
+// BidOutcome is one bidder's result for a single auction round.
+type BidOutcome struct {
+	Bidder     string
+	Response   *connectors.BidResponse
+	Err        error
+	Latency    time.Duration
+	Skipped    bool   // true if the bidder was never dialed this round
+	SkipReason string // why, when Skipped is true
+}
+
+// AuctionResult is the outcome of one RunAuction call.
+type AuctionResult struct {
+	Winner  *BidOutcome
+	Losers  []BidOutcome
+	Start   time.Time
+	Elapsed time.Duration
+}
+
+// Auctioneer fans a BidRequest out to every registered Bidder concurrently,
+// under a caller-supplied deadline, and picks a winner via WinnerPolicy. It
+// enforces a budget cap across the whole fan-out (independent of each
+// connector's own per-platform BudgetManager) and records every dialed
+// bidder's latency and success/failure through Metrics.
+type Auctioneer struct {
+	mu      sync.Mutex
+	bidders []Bidder
+
+	WinnerPolicy  WinnerPolicy
+	BudgetManager *shield.BudgetManager
+	Metrics       *shield.MetricsCollector
+}
+
+// NewAuctioneer creates an Auctioneer with the given winner-selection
+// policy and a global budget cap shared across every bidder in the
+// fan-out.
+func NewAuctioneer(policy WinnerPolicy, globalBudget float64) *Auctioneer {
+	return &Auctioneer{
+		WinnerPolicy:  policy,
+		BudgetManager: shield.NewBudgetManager(globalBudget),
+		Metrics:       shield.NewMetricsCollector(),
+	}
+}
+
+// Register adds a bidder to the auction pool under name.
+func (a *Auctioneer) Register(name string, connector connectors.PlatformConnector) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.bidders = append(a.bidders, Bidder{Name: name, Connector: connector})
+}
+
+// RunAuction fans req out to every registered bidder concurrently, bounded
+// by ctx, and returns the winner WinnerPolicy picked plus every other
+// bidder's outcome. A bidder whose CircuitBreaker (if it exposes one) is
+// OPEN is skipped without being dialed. If the global budget can't absorb
+// req.BidAmount at all, every bidder is skipped and there is no winner.
+func (a *Auctioneer) RunAuction(ctx context.Context, req *connectors.BidRequest) *AuctionResult {
+	start := time.Now()
+
+	a.mu.Lock()
+	bidders := make([]Bidder, len(a.bidders))
+	copy(bidders, a.bidders)
+	a.mu.Unlock()
+
+	globalBudgetOK := a.BudgetManager.CanSpend(req.BidAmount)
+
+	outcomes := make([]BidOutcome, len(bidders))
+	var wg sync.WaitGroup
+	for i, bidder := range bidders {
+		if !globalBudgetOK {
+			outcomes[i] = BidOutcome{Bidder: bidder.Name, Skipped: true, SkipReason: "global auction budget exceeded"}
+			continue
+		}
+		if cba, ok := bidder.Connector.(CircuitBreakerAware); ok {
+			if cb := cba.GetCircuitBreaker(); cb != nil && !cb.CanExecute() {
+				outcomes[i] = BidOutcome{Bidder: bidder.Name, Skipped: true, SkipReason: "circuit breaker open"}
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go func(i int, bidder Bidder) {
+			defer wg.Done()
+			callStart := time.Now()
+			resp, err := bidder.Connector.PlaceBid(ctx, req)
+			latency := time.Since(callStart)
+
+			a.Metrics.RecordRequest()
+			if err != nil {
+				a.Metrics.RecordFailure(latency, "bidder_error")
+			} else {
+				a.Metrics.RecordSuccess(latency)
+			}
+
+			outcomes[i] = BidOutcome{Bidder: bidder.Name, Response: resp, Err: err, Latency: latency}
+		}(i, bidder)
+	}
+	wg.Wait()
+
+	winner, clearingPrice := a.WinnerPolicy(req, outcomes)
+	if winner != nil {
+		a.BudgetManager.AddSpend(clearingPrice)
+	}
+
+	result := &AuctionResult{Start: start, Elapsed: time.Since(start)}
+	for i := range outcomes {
+		if winner == &outcomes[i] {
+			result.Winner = winner
+			continue
+		}
+		result.Losers = append(result.Losers, outcomes[i])
+	}
+	return result
+}
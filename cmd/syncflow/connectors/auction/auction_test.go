@@ -0,0 +1,170 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// stubConnector is a minimal PlatformConnector for exercising Auctioneer
+// without standing up a real platform connector.
+type stubConnector struct {
+	resp *connectors.BidResponse
+	err  error
+	cb   *shield.CircuitBreaker
+}
+
+func (s *stubConnector) Connect(ctx context.Context) error { return nil }
+func (s *stubConnector) PlaceBid(ctx context.Context, req *connectors.BidRequest) (*connectors.BidResponse, error) {
+	return s.resp, s.err
+}
+func (s *stubConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*connectors.BidResponse, error) {
+	return nil, nil
+}
+func (s *stubConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*connectors.BidResponse, error) {
+	return nil, nil
+}
+func (s *stubConnector) GetStatus() string { return "stub" }
+func (s *stubConnector) Close() error      { return nil }
+func (s *stubConnector) GetCircuitBreaker() *shield.CircuitBreaker {
+	return s.cb
+}
+
+func winningResponse(platformCode string, bidAmount float64) *connectors.BidResponse {
+	return &connectors.BidResponse{Success: true, BidID: platformCode + "_1", PlatformCode: platformCode, BidAmount: bidAmount, Timestamp: time.Now()}
+}
+
+func TestAuctioneer_HighestBidPicksBiggestSuccessfulBid(t *testing.T) {
+	a := NewAuctioneer(HighestBid, 1000)
+	a.Register("low", &stubConnector{resp: winningResponse("LOW", 1.0)})
+	a.Register("high", &stubConnector{resp: winningResponse("HIGH", 5.0)})
+	a.Register("mid", &stubConnector{resp: winningResponse("MID", 3.0)})
+
+	result := a.RunAuction(context.Background(), &connectors.BidRequest{BidAmount: 1.0})
+
+	if result.Winner == nil || result.Winner.Bidder != "high" {
+		t.Fatalf("expected high to win, got %+v", result.Winner)
+	}
+	if len(result.Losers) != 2 {
+		t.Fatalf("expected 2 losers, got %d", len(result.Losers))
+	}
+}
+
+func TestAuctioneer_SkipsBiddersWithOpenCircuit(t *testing.T) {
+	openBreaker := shield.NewCircuitBreaker()
+	openBreaker.SetThresholds(1, 1, time.Second, time.Hour)
+	openBreaker.RecordFailure(0) // one failure trips it open
+
+	a := NewAuctioneer(HighestBid, 1000)
+	a.Register("tripped", &stubConnector{resp: winningResponse("TRIPPED", 100.0), cb: openBreaker})
+	a.Register("healthy", &stubConnector{resp: winningResponse("HEALTHY", 1.0), cb: shield.NewCircuitBreaker()})
+
+	result := a.RunAuction(context.Background(), &connectors.BidRequest{BidAmount: 1.0})
+
+	if result.Winner == nil || result.Winner.Bidder != "healthy" {
+		t.Fatalf("expected healthy to win since tripped's circuit is open, got %+v", result.Winner)
+	}
+
+	for _, loser := range result.Losers {
+		if loser.Bidder == "tripped" && !loser.Skipped {
+			t.Fatalf("expected tripped to be skipped, got %+v", loser)
+		}
+	}
+}
+
+func TestAuctioneer_GlobalBudgetCapSkipsEveryBidder(t *testing.T) {
+	a := NewAuctioneer(HighestBid, 5) // cap smaller than the bid
+	a.Register("only", &stubConnector{resp: winningResponse("ONLY", 5.0)})
+
+	result := a.RunAuction(context.Background(), &connectors.BidRequest{BidAmount: 10.0})
+
+	if result.Winner != nil {
+		t.Fatalf("expected no winner when the global budget can't absorb the bid, got %+v", result.Winner)
+	}
+	if len(result.Losers) != 1 || !result.Losers[0].Skipped {
+		t.Fatalf("expected the only bidder to be skipped, got %+v", result.Losers)
+	}
+}
+
+func TestAuctioneer_NoWinnerWhenEveryBidderErrors(t *testing.T) {
+	a := NewAuctioneer(HighestBid, 1000)
+	a.Register("broken", &stubConnector{err: context.DeadlineExceeded})
+
+	result := a.RunAuction(context.Background(), &connectors.BidRequest{BidAmount: 1.0})
+
+	if result.Winner != nil {
+		t.Fatalf("expected no winner, got %+v", result.Winner)
+	}
+	if len(result.Losers) != 1 || result.Losers[0].Err == nil {
+		t.Fatalf("expected the losing outcome to carry its error, got %+v", result.Losers)
+	}
+}
+
+func TestSecondPriceHighestBid_ClearsAtRunnerUpPrice(t *testing.T) {
+	outcomes := []BidOutcome{
+		{Bidder: "low", Response: winningResponse("LOW", 1.0)},
+		{Bidder: "high", Response: winningResponse("HIGH", 5.0)},
+		{Bidder: "mid", Response: winningResponse("MID", 3.0)},
+	}
+
+	winner, price := SecondPriceHighestBid(&connectors.BidRequest{}, outcomes)
+	if winner == nil || winner.Bidder != "high" {
+		t.Fatalf("expected high to win, got %+v", winner)
+	}
+	if price != 3.0 {
+		t.Fatalf("expected second-price clearing at 3.0, got %.2f", price)
+	}
+}
+
+func TestSecondPriceHighestBid_ClearsAtOwnBidWithOnlyOneWinner(t *testing.T) {
+	outcomes := []BidOutcome{{Bidder: "solo", Response: winningResponse("SOLO", 2.5)}}
+
+	winner, price := SecondPriceHighestBid(&connectors.BidRequest{}, outcomes)
+	if winner == nil || price != 2.5 {
+		t.Fatalf("expected the lone bidder to clear at its own bid of 2.5, got winner=%+v price=%.2f", winner, price)
+	}
+}
+
+func TestHighestPredictedROAS_PrefersCheaperHighLTVBidder(t *testing.T) {
+	outcomes := []BidOutcome{
+		{Bidder: "expensive", Response: winningResponse("EXPENSIVE", 10.0)}, // ROAS 10
+		{Bidder: "cheap", Response: winningResponse("CHEAP", 1.0)},          // ROAS 100
+	}
+
+	winner, _ := HighestPredictedROAS(&connectors.BidRequest{PredictedLTV: 100}, outcomes)
+	if winner == nil || winner.Bidder != "cheap" {
+		t.Fatalf("expected cheap (higher predicted ROAS) to win, got %+v", winner)
+	}
+}
+
+func TestDealIDPriority_PrefersConfiguredPlatformOverHigherBid(t *testing.T) {
+	outcomes := []BidOutcome{
+		{Bidder: "open_market", Response: winningResponse("OPEN_MARKET", 9.0)},
+		{Bidder: "pmp_deal", Response: winningResponse("PMP_DEAL", 2.0)},
+	}
+
+	policy := DealIDPriority([]string{"PMP_DEAL"})
+	winner, price := policy(&connectors.BidRequest{}, outcomes)
+	if winner == nil || winner.Bidder != "pmp_deal" {
+		t.Fatalf("expected the PMP deal to win over a higher open-market bid, got %+v", winner)
+	}
+	if price != 2.0 {
+		t.Fatalf("expected to clear at the deal's own price of 2.0, got %.2f", price)
+	}
+}
+
+func TestDealIDPriority_FallsBackToHighestBidWithNoMatchingDeal(t *testing.T) {
+	outcomes := []BidOutcome{
+		{Bidder: "low", Response: winningResponse("LOW", 1.0)},
+		{Bidder: "high", Response: winningResponse("HIGH", 5.0)},
+	}
+
+	policy := DealIDPriority([]string{"SOME_OTHER_DEAL"})
+	winner, _ := policy(&connectors.BidRequest{}, outcomes)
+	if winner == nil || winner.Bidder != "high" {
+		t.Fatalf("expected fallback to highest bid, got %+v", winner)
+	}
+}
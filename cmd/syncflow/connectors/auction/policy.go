@@ -0,0 +1,121 @@
+package auction
+
+import "github.com/user/kiki-agent/cmd/syncflow/connectors"
+
+// WinnerPolicy picks a winner out of one auction round's outcomes and
+// reports the clearing price that should actually be charged against the
+// Auctioneer's global budget - for a second-price policy this differs
+// from the winning bid itself. Returns a nil winner if no outcome
+// qualifies (e.g. every bidder was skipped or errored).
+type WinnerPolicy func(req *connectors.BidRequest, outcomes []BidOutcome) (winner *BidOutcome, clearingPrice float64)
+
+// successfulBids returns the outcomes that actually won a bid, in the
+// order they appear in outcomes.
+func successfulBids(outcomes []BidOutcome) []*BidOutcome {
+	var won []*BidOutcome
+	for i := range outcomes {
+		o := &outcomes[i]
+		if o.Response != nil && o.Response.Success {
+			won = append(won, o)
+		}
+	}
+	return won
+}
+
+// HighestBid awards the auction to the highest BidAmount across every
+// successful response, clearing at that same price (first-price).
+func HighestBid(req *connectors.BidRequest, outcomes []BidOutcome) (*BidOutcome, float64) {
+	won := successfulBids(outcomes)
+	if len(won) == 0 {
+		return nil, 0
+	}
+	best := won[0]
+	for _, o := range won[1:] {
+		if o.Response.BidAmount > best.Response.BidAmount {
+			best = o
+		}
+	}
+	return best, best.Response.BidAmount
+}
+
+// SecondPriceHighestBid awards the auction to the highest bidder, same as
+// HighestBid, but clears at the second-highest bid amount (or the winning
+// bid itself if only one bidder won) - the Vickrey auction rule most
+// real-world exchanges actually run.
+func SecondPriceHighestBid(req *connectors.BidRequest, outcomes []BidOutcome) (*BidOutcome, float64) {
+	won := successfulBids(outcomes)
+	if len(won) == 0 {
+		return nil, 0
+	}
+
+	best, second := won[0], won[0]
+	for _, o := range won[1:] {
+		switch {
+		case o.Response.BidAmount > best.Response.BidAmount:
+			second = best
+			best = o
+		case o.Response.BidAmount > second.Response.BidAmount || second == best:
+			second = o
+		}
+	}
+	return best, second.Response.BidAmount
+}
+
+// HighestPredictedROAS awards the auction to the bidder with the highest
+// predicted return on ad spend (req.PredictedLTV / the bid it actually
+// placed), rather than the raw bid amount - useful when cheaper platforms
+// are predicted to convert disproportionately well.
+func HighestPredictedROAS(req *connectors.BidRequest, outcomes []BidOutcome) (*BidOutcome, float64) {
+	won := successfulBids(outcomes)
+	if len(won) == 0 {
+		return nil, 0
+	}
+
+	roas := func(o *BidOutcome) float64 {
+		if o.Response.BidAmount <= 0 {
+			return 0
+		}
+		return req.PredictedLTV / o.Response.BidAmount
+	}
+
+	best := won[0]
+	for _, o := range won[1:] {
+		if roas(o) > roas(best) {
+			best = o
+		}
+	}
+	return best, best.Response.BidAmount
+}
+
+// DealIDPriority awards the auction to the successful bidder whose
+// PlatformCode comes earliest in priorityPlatformCodes (ties going to the
+// bidder registered first) - the way a private marketplace deal takes
+// precedence over open auction, regardless of price. Falls back to
+// HighestBid if no successful bidder matches a priority code.
+func DealIDPriority(priorityPlatformCodes []string) WinnerPolicy {
+	priority := make(map[string]int, len(priorityPlatformCodes))
+	for i, code := range priorityPlatformCodes {
+		priority[code] = i
+	}
+
+	return func(req *connectors.BidRequest, outcomes []BidOutcome) (*BidOutcome, float64) {
+		won := successfulBids(outcomes)
+
+		var best *BidOutcome
+		bestRank := len(priority)
+		for _, o := range won {
+			rank, ok := priority[o.Response.PlatformCode]
+			if !ok {
+				continue
+			}
+			if best == nil || rank < bestRank {
+				best, bestRank = o, rank
+			}
+		}
+		if best != nil {
+			return best, best.Response.BidAmount
+		}
+
+		return HighestBid(req, outcomes)
+	}
+}
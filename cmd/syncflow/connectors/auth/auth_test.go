@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestStaticTokenAuthProvider_TokenSourceReturnsFixedToken(t *testing.T) {
+	p := StaticTokenAuthProvider{AccessToken: "fixed-token"}
+	tok, err := p.TokenSource(context.Background()).Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if tok.AccessToken != "fixed-token" {
+		t.Fatalf("got access token %q, want %q", tok.AccessToken, "fixed-token")
+	}
+}
+
+func TestUserAuthProvider_TokenSourceCarriesRefreshToken(t *testing.T) {
+	config := &oauth2.Config{
+		ClientID:     "client-1",
+		ClientSecret: "secret",
+		Endpoint:     oauth2.Endpoint{TokenURL: "https://example.com/token"},
+	}
+	p := NewUserAuthProvider(config, "refresh-token-1")
+	src := p.TokenSource(context.Background())
+	if src == nil {
+		t.Fatal("expected a non-nil TokenSource")
+	}
+}
+
+func TestServiceAccountAuthProvider_TokenSourceBuilds(t *testing.T) {
+	p := NewServiceAccountAuthProvider("svc@example.com", []byte("not-a-real-key"), []string{"scope-1"}, "https://example.com/token")
+	src := p.TokenSource(context.Background())
+	if src == nil {
+		t.Fatal("expected a non-nil TokenSource")
+	}
+}
+
+// countingProvider records how many times TokenSource was called, so
+// TokenCache tests can assert a cached key only builds its source once.
+type countingProvider struct {
+	calls int
+	token string
+}
+
+func (p *countingProvider) TokenSource(ctx context.Context) oauth2.TokenSource {
+	p.calls++
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: p.token})
+}
+
+func TestTokenCache_SharedReusesSourcePerKey(t *testing.T) {
+	cache := NewTokenCache()
+	provider := &countingProvider{token: "tok-a"}
+
+	first := cache.Shared(context.Background(), "customer-1", provider)
+	second := cache.Shared(context.Background(), "customer-1", provider)
+
+	if provider.calls != 1 {
+		t.Fatalf("expected provider.TokenSource to be called once, got %d", provider.calls)
+	}
+	tok1, err := first.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	tok2, err := second.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if tok1.AccessToken != tok2.AccessToken {
+		t.Fatalf("expected the same cached token, got %q and %q", tok1.AccessToken, tok2.AccessToken)
+	}
+}
+
+func TestTokenCache_SharedBuildsSeparateSourcesPerKey(t *testing.T) {
+	cache := NewTokenCache()
+	providerA := &countingProvider{token: "tok-a"}
+	providerB := &countingProvider{token: "tok-b"}
+
+	srcA := cache.Shared(context.Background(), "customer-a", providerA)
+	srcB := cache.Shared(context.Background(), "customer-b", providerB)
+
+	tokA, err := srcA.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	tokB, err := srcB.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if tokA.AccessToken != "tok-a" || tokB.AccessToken != "tok-b" {
+		t.Fatalf("expected distinct tokens per key, got %q and %q", tokA.AccessToken, tokB.AccessToken)
+	}
+	if providerA.calls != 1 || providerB.calls != 1 {
+		t.Fatalf("expected each provider called once, got %d and %d", providerA.calls, providerB.calls)
+	}
+}
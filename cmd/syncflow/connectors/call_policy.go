@@ -0,0 +1,206 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// CallPolicy is a per-method retry/backoff configuration, modeled on
+// gax.CallOption/gax.Backoff from Google's gax-go client libraries: rather
+// than one global shield.RetryPolicy for every outbound call, each
+// PlatformConnector method (PlaceBid, UpdateCampaignBudget,
+// UpdateTargetAudience) gets its own initial delay, growth curve, overall
+// deadline, and set of retryable statuses - a single transient 503
+// shouldn't kill a bid outright.
+type CallPolicy struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	MaxAttempts  int
+	Deadline     time.Duration // overall deadline across every attempt; 0 = no deadline
+
+	// RetryableCodes are the HTTP statuses (this module's stand-in for
+	// gRPC codes like UNAVAILABLE/DEADLINE_EXCEEDED, since no connector
+	// here actually speaks gRPC) worth retrying. A status of 0 in a
+	// CallFunc result means a network-level error, which is always
+	// considered retryable.
+	RetryableCodes map[int]bool
+
+	// IdempotentOnly requires the caller to supply req.RequestID before a
+	// retryable failure is actually retried. Set for calls that aren't
+	// safe to repeat blind - retrying a bid placement without an
+	// idempotency key risks placing it twice.
+	IdempotentOnly bool
+}
+
+// defaultRetryableStatusCodes mirrors the gRPC codes gax-go retries by
+// default (UNAVAILABLE, DEADLINE_EXCEEDED) via their closest HTTP
+// equivalents, plus 429 for rate limiting.
+func defaultRetryableStatusCodes() map[int]bool {
+	return map[int]bool{
+		http.StatusTooManyRequests:     true, // 429
+		http.StatusBadGateway:          true, // 502 ~ UNAVAILABLE
+		http.StatusServiceUnavailable:  true, // 503 ~ UNAVAILABLE
+		http.StatusGatewayTimeout:      true, // 504 ~ DEADLINE_EXCEEDED
+		http.StatusInternalServerError: true, // 500
+	}
+}
+
+// DefaultPlaceBidCallPolicy is the default retry policy for PlaceBid.
+// Placing a bid twice can double-spend budget, so it only retries when the
+// caller supplied an idempotency key via BidRequest.RequestID.
+func DefaultPlaceBidCallPolicy() *CallPolicy {
+	return &CallPolicy{
+		InitialDelay:   200 * time.Millisecond,
+		MaxDelay:       5 * time.Second,
+		Multiplier:     2.0,
+		MaxAttempts:    4,
+		Deadline:       10 * time.Second,
+		RetryableCodes: defaultRetryableStatusCodes(),
+		IdempotentOnly: true,
+	}
+}
+
+// DefaultUpdateCampaignBudgetCallPolicy is the default retry policy for
+// UpdateCampaignBudget. Setting a budget to an absolute amount is
+// idempotent, so it retries freely on a retryable status.
+func DefaultUpdateCampaignBudgetCallPolicy() *CallPolicy {
+	return &CallPolicy{
+		InitialDelay:   250 * time.Millisecond,
+		MaxDelay:       10 * time.Second,
+		Multiplier:     2.0,
+		MaxAttempts:    5,
+		Deadline:       20 * time.Second,
+		RetryableCodes: defaultRetryableStatusCodes(),
+		IdempotentOnly: false,
+	}
+}
+
+// DefaultUpdateTargetAudienceCallPolicy is the default retry policy for
+// UpdateTargetAudience. Re-pointing a campaign at an audience ID is
+// idempotent, so it retries freely on a retryable status.
+func DefaultUpdateTargetAudienceCallPolicy() *CallPolicy {
+	return &CallPolicy{
+		InitialDelay:   250 * time.Millisecond,
+		MaxDelay:       10 * time.Second,
+		Multiplier:     2.0,
+		MaxAttempts:    3,
+		Deadline:       15 * time.Second,
+		RetryableCodes: defaultRetryableStatusCodes(),
+		IdempotentOnly: false,
+	}
+}
+
+// CallFunc is one attempt a CallPolicy retries. statusCode classifies the
+// attempt for retry purposes; 0 means a network-level error rather than an
+// HTTP response, and is always treated as retryable.
+type CallFunc func(attempt int) (resp *BidResponse, statusCode int, err error)
+
+// Execute runs fn under p's retry/backoff schedule, bounded by ctx. If cb
+// is non-nil, the whole retry cycle counts as a single logical call for
+// circuit-breaker purposes (one RecordSuccess/RecordFailure at the end,
+// keyed off total cycle latency), while each individual attempt's latency
+// is still fed to cb's MetricsCollector (if enabled) so per-attempt
+// failures remain visible. idempotencyKey gates retries on policies with
+// IdempotentOnly set.
+func (p *CallPolicy) Execute(ctx context.Context, cb *shield.CircuitBreaker, idempotencyKey string, fn CallFunc) (*BidResponse, error) {
+	if cb != nil && !cb.CanExecute() {
+		return nil, shield.NewError(shield.ErrorKindCircuitOpen, "", cb.GetState(), shield.ErrCircuitOpen)
+	}
+
+	var metrics *shield.MetricsCollector
+	if cb != nil {
+		metrics = cb.GetMetricsCollector()
+	}
+
+	maxAttempts := p.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	cycleStart := time.Now()
+	var deadline time.Time
+	if p.Deadline > 0 {
+		deadline = cycleStart.Add(p.Deadline)
+	}
+
+	delay := p.InitialDelay
+	var resp *BidResponse
+	var statusCode int
+	var err error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			err = fmt.Errorf("call policy deadline exceeded after %d attempt(s)", attempt-1)
+			break
+		}
+
+		attemptStart := time.Now()
+		resp, statusCode, err = fn(attempt)
+		latency := time.Since(attemptStart)
+
+		if err == nil && statusCode < 400 {
+			break
+		}
+		if metrics != nil {
+			metrics.RecordFailure(latency, fmt.Sprintf("status_%d", statusCode))
+		}
+
+		if attempt == maxAttempts || !p.shouldRetry(statusCode, idempotencyKey) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			if cb != nil {
+				cb.RecordCancellation()
+				return resp, shield.NewError(shield.ErrorKindTimeout, "", cb.GetState(), err)
+			}
+			return resp, err
+		case <-time.After(delay):
+		}
+		delay = time.Duration(float64(delay) * p.Multiplier)
+		if delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+	}
+
+	cycleSucceeded := err == nil && statusCode < 400
+	if cb != nil {
+		cycleLatency := time.Since(cycleStart)
+		if cycleSucceeded {
+			cb.RecordSuccess(cycleLatency)
+		} else {
+			cb.RecordFailure(cycleLatency)
+		}
+	}
+
+	if cycleSucceeded {
+		return resp, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("call failed with status %d", statusCode)
+	}
+	if cb != nil {
+		return resp, shield.NewError(shield.ErrorKindUpstreamUnavailable, "", cb.GetState(), err)
+	}
+	return resp, err
+}
+
+// shouldRetry reports whether statusCode warrants another attempt under p,
+// given the idempotency key the caller supplied (if any).
+func (p *CallPolicy) shouldRetry(statusCode int, idempotencyKey string) bool {
+	retryable := statusCode == 0 || p.RetryableCodes[statusCode]
+	if !retryable {
+		return false
+	}
+	if p.IdempotentOnly && idempotencyKey == "" {
+		return false
+	}
+	return true
+}
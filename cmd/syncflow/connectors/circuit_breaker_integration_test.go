@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/auth"
 	"github.com/user/kiki-agent/cmd/syncshield/shield"
 )
 
@@ -29,7 +30,7 @@ import (
 
 // TestCircuitBreakerFailureScenario validates that the circuit breaker opens after 3 consecutive failures
 func TestCircuitBreakerFailureScenario(t *testing.T) {
-	connector := NewGoogleAdsSmartConnector("test-key", "test-customer", 1000.0)
+	connector := NewGoogleAdsSmartConnector(auth.StaticTokenAuthProvider{AccessToken: "test-token"}, "test-dev-token", "test-customer", "", 1000.0)
 	connector.MockMode = true
 	connector.Connect(context.Background())
 
@@ -61,7 +62,7 @@ func TestCircuitBreakerFailureScenario(t *testing.T) {
 
 // TestCircuitBreakerFallbackBidCalculation validates heuristic fallback when breaker is open
 func TestCircuitBreakerFallbackBidCalculation(t *testing.T) {
-	connector := NewGoogleAdsSmartConnector("test-key", "test-customer", 1000.0)
+	connector := NewGoogleAdsSmartConnector(auth.StaticTokenAuthProvider{AccessToken: "test-token"}, "test-dev-token", "test-customer", "", 1000.0)
 	connector.MockMode = true
 	connector.Connect(context.Background())
 
@@ -123,7 +124,7 @@ func TestCircuitBreakerHalfOpenRecovery(t *testing.T) {
 		t.Skip("Skipping slow test in short mode (31+ second wait)")
 	}
 
-	connector := NewGoogleAdsSmartConnector("test-key", "test-customer", 1000.0)
+	connector := NewGoogleAdsSmartConnector(auth.StaticTokenAuthProvider{AccessToken: "test-token"}, "test-dev-token", "test-customer", "", 1000.0)
 	connector.MockMode = true
 	connector.Connect(context.Background())
 
@@ -176,7 +177,7 @@ func TestCircuitBreakerHalfOpenRecovery(t *testing.T) {
 
 // TestCircuitBreakerLatencyThreshold validates that slow calls trigger OPEN state
 func TestCircuitBreakerLatencyThreshold(t *testing.T) {
-	connector := NewGoogleAdsSmartConnector("test-key", "test-customer", 1000.0)
+	connector := NewGoogleAdsSmartConnector(auth.StaticTokenAuthProvider{AccessToken: "test-token"}, "test-dev-token", "test-customer", "", 1000.0)
 	connector.MockMode = true
 	connector.Connect(context.Background())
 
@@ -206,7 +207,7 @@ func TestCircuitBreakerLatencyThreshold(t *testing.T) {
 
 // TestCircuitBreakerConcurrentFailures validates thread-safety during concurrent failures
 func TestCircuitBreakerConcurrentFailures(t *testing.T) {
-	connector := NewGoogleAdsSmartConnector("test-key", "test-customer", 5000.0)
+	connector := NewGoogleAdsSmartConnector(auth.StaticTokenAuthProvider{AccessToken: "test-token"}, "test-dev-token", "test-customer", "", 5000.0)
 	connector.MockMode = true
 	connector.Connect(context.Background())
 
@@ -247,7 +248,7 @@ func TestCircuitBreakerConcurrentFailures(t *testing.T) {
 
 // TestCircuitBreakerMultiPlatformIsolation validates that each platform has independent circuit breaker
 func TestCircuitBreakerMultiPlatformIsolation(t *testing.T) {
-	googleAds := NewGoogleAdsSmartConnector("ga-key", "ga-customer", 1000.0)
+	googleAds := NewGoogleAdsSmartConnector(auth.StaticTokenAuthProvider{AccessToken: "ga-token"}, "ga-dev-token", "ga-customer", "", 1000.0)
 	meta := NewMetaSmartConnector("meta-token", "meta-business", 1000.0)
 	tradeDesk := NewTradeDeskSmartConnector("ttd-key", "ttd-partner", 1000.0)
 
@@ -277,7 +278,7 @@ func TestCircuitBreakerMultiPlatformIsolation(t *testing.T) {
 
 // TestCircuitBreakerFallbackWithInsufficientHistory validates fallback uses default LTV when history is empty
 func TestCircuitBreakerFallbackWithInsufficientHistory(t *testing.T) {
-	connector := NewGoogleAdsSmartConnector("test-key", "test-customer", 1000.0)
+	connector := NewGoogleAdsSmartConnector(auth.StaticTokenAuthProvider{AccessToken: "test-token"}, "test-dev-token", "test-customer", "", 1000.0)
 	connector.MockMode = true
 	connector.Connect(context.Background())
 
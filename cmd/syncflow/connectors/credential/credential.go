@@ -0,0 +1,43 @@
+// Package credential supplies a connector's outbound Authorization header
+// from a source other than a bare, long-lived access token sitting in a
+// struct field: a short-lived token refreshed from an STS endpoint, or (see
+// mtls.go) a rotating mutual-TLS client certificate, for operators who need
+// to run connectors in zero-trust environments.
+package credential
+
+import (
+	"context"
+)
+
+// Credential is what a Provider yields for a connector's next call: a
+// bearer token to attach as an Authorization header. Left empty, the
+// connector sends no Authorization header for that call - the right
+// behavior for a provider that authenticates at the TLS layer instead (see
+// MutualTLSProvider).
+type Credential struct {
+	BearerToken string
+}
+
+// Provider supplies the Credential a connector attaches to its next
+// outbound request, refreshing it however its implementation sees fit
+// before it goes stale.
+type Provider interface {
+	Credential(ctx context.Context) (Credential, error)
+}
+
+// StaticProvider always returns the same fixed bearer token. It never
+// refreshes, so it's only suitable for a long-lived token or tests - the
+// direct replacement for a connector's old bare AccessToken field.
+type StaticProvider struct {
+	AccessToken string
+}
+
+// NewStaticProvider creates a StaticProvider for accessToken.
+func NewStaticProvider(accessToken string) StaticProvider {
+	return StaticProvider{AccessToken: accessToken}
+}
+
+// Credential implements Provider.
+func (s StaticProvider) Credential(ctx context.Context) (Credential, error) {
+	return Credential{BearerToken: s.AccessToken}, nil
+}
@@ -0,0 +1,92 @@
+package credential
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStaticProvider_AlwaysReturnsTheSameToken(t *testing.T) {
+	p := NewStaticProvider("fixed-token")
+
+	for i := 0; i < 3; i++ {
+		cred, err := p.Credential(context.Background())
+		if err != nil {
+			t.Fatalf("Credential: %v", err)
+		}
+		if cred.BearerToken != "fixed-token" {
+			t.Fatalf("expected fixed-token, got %q", cred.BearerToken)
+		}
+	}
+}
+
+func TestAutoRenewProvider_FetchesAndReusesUntilNearExpiry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(stsResponse{AccessToken: "token-1", ExpiresIn: 3600})
+	}))
+	defer server.Close()
+
+	now := time.Unix(1700000000, 0)
+	p := NewAutoRenewProvider(server.URL, 5*time.Minute)
+	p.Now = func() time.Time { return now }
+
+	cred, err := p.Credential(context.Background())
+	if err != nil {
+		t.Fatalf("Credential: %v", err)
+	}
+	if cred.BearerToken != "token-1" {
+		t.Fatalf("expected token-1, got %q", cred.BearerToken)
+	}
+
+	// Well inside the token's lifetime - should reuse without another fetch.
+	now = now.Add(10 * time.Minute)
+	if _, err := p.Credential(context.Background()); err != nil {
+		t.Fatalf("Credential: %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected 1 STS request while token is still fresh, got %d", requests)
+	}
+}
+
+func TestAutoRenewProvider_RenewsWithinRenewBeforeOfExpiry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(stsResponse{AccessToken: "token", ExpiresIn: 3600})
+	}))
+	defer server.Close()
+
+	now := time.Unix(1700000000, 0)
+	p := NewAutoRenewProvider(server.URL, 5*time.Minute)
+	p.Now = func() time.Time { return now }
+
+	if _, err := p.Credential(context.Background()); err != nil {
+		t.Fatalf("Credential: %v", err)
+	}
+
+	// Within RenewBefore of the token's expiry - should fetch a new one.
+	now = now.Add(56 * time.Minute)
+	if _, err := p.Credential(context.Background()); err != nil {
+		t.Fatalf("Credential: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected a renewal once inside RenewBefore of expiry, got %d requests", requests)
+	}
+}
+
+func TestAutoRenewProvider_ErrorsOnNonOKStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	p := NewAutoRenewProvider(server.URL, 5*time.Minute)
+	if _, err := p.Credential(context.Background()); err == nil {
+		t.Fatal("expected an error from a non-200 STS response")
+	}
+}
@@ -0,0 +1,148 @@
+package credential
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertKeyPair generates a self-signed leaf certificate (signed by its
+// own key, since MutualTLSProvider never validates the leaf against the CA
+// it loads - that's the server's job) and writes its cert/key PEM files
+// under dir, returning their paths.
+func writeCertKeyPair(t *testing.T, dir, prefix string) (certPath, keyPath string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: prefix},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+
+	certPath = filepath.Join(dir, prefix+".crt")
+	keyPath = filepath.Join(dir, prefix+".key")
+	writePEM(t, certPath, "CERTIFICATE", der)
+	writePEM(t, keyPath, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+	return certPath, keyPath
+}
+
+func writePEM(t *testing.T, path, blockType string, bytes []byte) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create %s: %v", path, err)
+	}
+	defer f.Close()
+	if err := pem.Encode(f, &pem.Block{Type: blockType, Bytes: bytes}); err != nil {
+		t.Fatalf("encode %s: %v", path, err)
+	}
+}
+
+func TestNewMutualTLSProvider_LoadsCertAndCABundle(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeCertKeyPair(t, dir, "client")
+	caPath, _ := writeCertKeyPair(t, dir, "ca")
+
+	p, err := NewMutualTLSProvider(caPath, certPath, keyPath)
+	if err != nil {
+		t.Fatalf("NewMutualTLSProvider: %v", err)
+	}
+	if len(p.transport.TLSClientConfig.Certificates) != 1 {
+		t.Fatalf("expected exactly one client certificate loaded")
+	}
+	if p.transport.TLSClientConfig.RootCAs == nil {
+		t.Fatal("expected a non-nil RootCAs pool")
+	}
+}
+
+func TestNewMutualTLSProvider_ErrorsOnMissingCertFile(t *testing.T) {
+	dir := t.TempDir()
+	_, keyPath := writeCertKeyPair(t, dir, "client")
+	caPath, _ := writeCertKeyPair(t, dir, "ca")
+
+	if _, err := NewMutualTLSProvider(caPath, filepath.Join(dir, "missing.crt"), keyPath); err == nil {
+		t.Fatal("expected an error for a missing cert file")
+	}
+}
+
+func TestMutualTLSProvider_WatchForRotationReloadsOnFileChange(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeCertKeyPair(t, dir, "client")
+	caPath, _ := writeCertKeyPair(t, dir, "ca")
+
+	p, err := NewMutualTLSProvider(caPath, certPath, keyPath)
+	if err != nil {
+		t.Fatalf("NewMutualTLSProvider: %v", err)
+	}
+	originalCert := p.transport.TLSClientConfig.Certificates[0]
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		p.WatchForRotation(10*time.Millisecond, stop)
+		close(done)
+	}()
+
+	// Bump the cert file's mtime past what reload last recorded, then
+	// rewrite it with a fresh certificate - simulating a rotation job.
+	time.Sleep(20 * time.Millisecond)
+	writeCertKeyPair(t, dir, "client")
+
+	deadline := time.After(2 * time.Second)
+	for {
+		p.mu.RLock()
+		rotated := !bytes.Equal(p.transport.TLSClientConfig.Certificates[0].Certificate[0], originalCert.Certificate[0])
+		p.mu.RUnlock()
+		if rotated {
+			break
+		}
+		select {
+		case <-deadline:
+			close(stop)
+			<-done
+			t.Fatal("timed out waiting for WatchForRotation to pick up the new cert")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	close(stop)
+	<-done
+}
+
+func TestMutualTLSProvider_RoundTripUsesLoadedTransport(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeCertKeyPair(t, dir, "client")
+	caPath, _ := writeCertKeyPair(t, dir, "ca")
+
+	p, err := NewMutualTLSProvider(caPath, certPath, keyPath)
+	if err != nil {
+		t.Fatalf("NewMutualTLSProvider: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://127.0.0.1:0/", nil)
+	// No server is listening; RoundTrip should still reach p.transport
+	// (proven by a connection-refused error, not a nil-pointer panic from
+	// an unset transport).
+	if _, err := p.RoundTrip(req); err == nil {
+		t.Fatal("expected a connection error with nothing listening")
+	}
+}
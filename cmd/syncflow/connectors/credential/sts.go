@@ -0,0 +1,97 @@
+package credential
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// stsResponse is the JSON body an STS endpoint returns: a short-lived
+// bearer token plus its lifetime in seconds, the same shape OAuth2's
+// client-credentials token endpoint uses.
+type stsResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// AutoRenewProvider fetches a short-lived bearer token from an STS
+// endpoint and reuses it until renewBefore of its lifetime remains, then
+// fetches a fresh one on the next Credential call - the same
+// reuse-until-near-expiry shape auth.TokenCache gets from
+// oauth2.ReuseTokenSource, for platforms whose credential is an STS token
+// rather than an OAuth2 flow.
+type AutoRenewProvider struct {
+	STSURL      string
+	HTTPClient  *http.Client
+	RenewBefore time.Duration
+
+	// Now is overridable by tests; nil uses time.Now.
+	Now func() time.Time
+
+	mu      sync.Mutex
+	current string
+	expiry  time.Time
+}
+
+// NewAutoRenewProvider creates an AutoRenewProvider that fetches from
+// stsURL, renewing renewBefore of the way to each token's reported expiry.
+func NewAutoRenewProvider(stsURL string, renewBefore time.Duration) *AutoRenewProvider {
+	return &AutoRenewProvider{
+		STSURL:      stsURL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		RenewBefore: renewBefore,
+	}
+}
+
+func (p *AutoRenewProvider) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// Credential implements Provider, renewing the token first if it's empty
+// or within RenewBefore of expiry.
+func (p *AutoRenewProvider) Credential(ctx context.Context) (Credential, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current == "" || p.now().After(p.expiry.Add(-p.RenewBefore)) {
+		if err := p.renewLocked(ctx); err != nil {
+			return Credential{}, err
+		}
+	}
+	return Credential{BearerToken: p.current}, nil
+}
+
+func (p *AutoRenewProvider) renewLocked(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.STSURL, nil)
+	if err != nil {
+		return fmt.Errorf("credential: build STS request: %w", err)
+	}
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("credential: STS request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("credential: STS endpoint returned %s", resp.Status)
+	}
+
+	var body stsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("credential: decode STS response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return fmt.Errorf("credential: STS response had no access_token")
+	}
+
+	p.current = body.AccessToken
+	p.expiry = p.now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	return nil
+}
@@ -0,0 +1,139 @@
+package connectors
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// deadlineTimer implements a resettable deadline backed by a cancel
+// channel, mirroring the pattern netstack's gonet package uses for
+// net.Conn-style deadlines: closing the channel broadcasts expiry to any
+// waiter, and a deadline that's reset after firing swaps in a fresh
+// channel so new waiters never observe an already-expired one.
+type deadlineTimer struct {
+	mu     sync.Mutex
+	timer  *time.Timer
+	cancel chan struct{}
+}
+
+func newDeadlineTimer() *deadlineTimer {
+	return &deadlineTimer{cancel: make(chan struct{})}
+}
+
+// set installs t as the new deadline. A zero Time clears it. Safe to call
+// repeatedly (e.g. once per loop iteration) without racing a timer that
+// already fired.
+func (d *deadlineTimer) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+
+	select {
+	case <-d.cancel:
+		// Already expired; give the next deadline a fresh channel.
+		d.cancel = make(chan struct{})
+	default:
+	}
+
+	if t.IsZero() {
+		return
+	}
+	if !t.After(time.Now()) {
+		close(d.cancel)
+		return
+	}
+
+	ch := d.cancel
+	d.timer = time.AfterFunc(time.Until(t), func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		select {
+		case <-ch:
+		default:
+			close(ch)
+		}
+	})
+}
+
+// done returns a channel closed once the current deadline elapses.
+func (d *deadlineTimer) done() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.cancel
+}
+
+// Deadlineable lets a long-running bid loop reset per-iteration deadlines
+// on an existing connector - e.g. tightening the timeout as a campaign's
+// window closes - without rebuilding its HTTP client.
+type Deadlineable interface {
+	SetReadDeadline(t time.Time)
+	SetWriteDeadline(t time.Time)
+}
+
+// connectorDeadline composes three timeout sources for one connector's
+// outbound calls: the caller's ctx deadline (if any), this connector's own
+// DefaultTimeout, and the read/write deadlines set via Deadlineable. The
+// earliest of the three bounds every call derived through withTimeout.
+type connectorDeadline struct {
+	DefaultTimeout time.Duration
+
+	read  *deadlineTimer
+	write *deadlineTimer
+}
+
+func newConnectorDeadline(defaultTimeout time.Duration) connectorDeadline {
+	return connectorDeadline{
+		DefaultTimeout: defaultTimeout,
+		read:           newDeadlineTimer(),
+		write:          newDeadlineTimer(),
+	}
+}
+
+// SetReadDeadline bounds how long this connector will wait on a response.
+func (d *connectorDeadline) SetReadDeadline(t time.Time) { d.read.set(t) }
+
+// SetWriteDeadline bounds how long this connector will wait for a request
+// to be sent.
+func (d *connectorDeadline) SetWriteDeadline(t time.Time) { d.write.set(t) }
+
+// withTimeout derives a context bounded by ctx's own deadline,
+// DefaultTimeout, and the current read/write deadlines, returning
+// whichever fires first along with its cancel func. The caller must
+// always call the returned cancel to release resources.
+func (d *connectorDeadline) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	derived, cancel := ctx, func() {}
+	if d.DefaultTimeout > 0 {
+		derived, cancel = context.WithTimeout(derived, d.DefaultTimeout)
+	}
+
+	watchCtx, watchCancel := context.WithCancel(derived)
+	readDone, writeDone := d.read.done(), d.write.done()
+
+	go func() {
+		select {
+		case <-watchCtx.Done():
+		case <-readDone:
+			watchCancel()
+		case <-writeDone:
+			watchCancel()
+		}
+	}()
+
+	return watchCtx, func() {
+		watchCancel()
+		cancel()
+	}
+}
+
+// wasCallerCancelled reports whether ctx (the context PlaceBid/etc. was
+// originally called with) was the one that ended the call - as opposed to
+// a genuine API failure - so the circuit breaker can record it as a
+// cancellation instead of a failure.
+func wasCallerCancelled(ctx context.Context) bool {
+	return ctx.Err() != nil
+}
@@ -0,0 +1,98 @@
+package connectors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeadlineTimer_ZeroTimeClearsDeadline(t *testing.T) {
+	dt := newDeadlineTimer()
+	dt.set(time.Now().Add(-time.Second))
+
+	dt.set(time.Time{})
+
+	select {
+	case <-dt.done():
+		t.Fatal("expected done() to stay open after clearing the deadline")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestDeadlineTimer_PastTimeClosesImmediately(t *testing.T) {
+	dt := newDeadlineTimer()
+	dt.set(time.Now().Add(-time.Second))
+
+	select {
+	case <-dt.done():
+	default:
+		t.Fatal("expected done() to be closed for a deadline already in the past")
+	}
+}
+
+func TestDeadlineTimer_FutureTimeClosesAfterElapsing(t *testing.T) {
+	dt := newDeadlineTimer()
+	dt.set(time.Now().Add(20 * time.Millisecond))
+
+	select {
+	case <-dt.done():
+		t.Fatal("expected done() to stay open before the deadline elapses")
+	default:
+	}
+
+	select {
+	case <-dt.done():
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("expected done() to close once the deadline elapsed")
+	}
+}
+
+func TestDeadlineTimer_RepeatedSetDoesNotPanic(t *testing.T) {
+	dt := newDeadlineTimer()
+	for i := 0; i < 100; i++ {
+		dt.set(time.Now().Add(time.Millisecond))
+		dt.set(time.Time{})
+	}
+}
+
+func TestConnectorDeadline_WithTimeoutHonorsShorterReadDeadline(t *testing.T) {
+	cd := newConnectorDeadline(time.Hour)
+	cd.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+
+	ctx, cancel := cd.withTimeout(context.Background())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("expected ctx to be cancelled once the read deadline elapsed")
+	}
+}
+
+func TestConnectorDeadline_WithTimeoutHonorsCallerContext(t *testing.T) {
+	cd := newConnectorDeadline(time.Hour)
+
+	callerCtx, callerCancel := context.WithCancel(context.Background())
+	ctx, cancel := cd.withTimeout(callerCtx)
+	defer cancel()
+
+	callerCancel()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("expected ctx to be cancelled once the caller's own context was cancelled")
+	}
+}
+
+func TestWasCallerCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if wasCallerCancelled(ctx) {
+		t.Fatal("expected a live context to report not cancelled")
+	}
+
+	cancel()
+	if !wasCallerCancelled(ctx) {
+		t.Fatal("expected a cancelled context to report cancelled")
+	}
+}
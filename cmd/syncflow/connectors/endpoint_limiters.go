@@ -0,0 +1,77 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// endpointLimiterDefaultQuota is the calls-per-minute a newly-seen endpoint
+// gets when NewEndpointLimiters wasn't given a quota for it, so a call site
+// added later doesn't need a quota entry before it can run.
+const endpointLimiterDefaultQuota = 60
+
+// EndpointLimiters gives each named API surface (e.g. "bids", "campaigns",
+// "audiences") its own RateLimiter, matching how ad platforms publish
+// separate quotas per endpoint rather than one blanket calls-per-minute
+// number for the whole connector.
+type EndpointLimiters struct {
+	mu       sync.Mutex
+	quotas   map[string]int
+	limiters map[string]*RateLimiter
+}
+
+// NewEndpointLimiters creates an EndpointLimiters backed by quotas, mapping
+// endpoint name to its maxCallsPerMinute. Limiters are created lazily on
+// first use so quotas only needs entries for endpoints that should deviate
+// from endpointLimiterDefaultQuota.
+func NewEndpointLimiters(quotas map[string]int) *EndpointLimiters {
+	return &EndpointLimiters{quotas: quotas, limiters: make(map[string]*RateLimiter)}
+}
+
+// Limiter returns the RateLimiter for endpoint, creating it from quotas (or
+// endpointLimiterDefaultQuota if endpoint has no entry) on first use.
+func (e *EndpointLimiters) Limiter(endpoint string) *RateLimiter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if rl, ok := e.limiters[endpoint]; ok {
+		return rl
+	}
+	quota, ok := e.quotas[endpoint]
+	if !ok || quota <= 0 {
+		quota = endpointLimiterDefaultQuota
+	}
+	rl := NewRateLimiter(quota)
+	e.limiters[endpoint] = rl
+	return rl
+}
+
+// Wait blocks until endpoint's limiter has a token available or ctx is
+// done.
+func (e *EndpointLimiters) Wait(ctx context.Context, endpoint string) error {
+	if err := e.Limiter(endpoint).Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit wait for endpoint %q: %w", endpoint, err)
+	}
+	return nil
+}
+
+// parseRateLimitHeaders extracts the X-RateLimit-Remaining/X-RateLimit-Limit
+// pair most ad platforms (Trade Desk included) use to advertise how close a
+// caller is to being throttled. ok is false if either header is missing or
+// unparseable, so callers can skip ObserveRemaining rather than act on a
+// zero value.
+func parseRateLimitHeaders(header http.Header) (remaining, limit int, ok bool) {
+	remainingStr := header.Get("X-RateLimit-Remaining")
+	limitStr := header.Get("X-RateLimit-Limit")
+	if remainingStr == "" || limitStr == "" {
+		return 0, 0, false
+	}
+	r, err1 := strconv.Atoi(remainingStr)
+	l, err2 := strconv.Atoi(limitStr)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return r, l, true
+}
@@ -0,0 +1,208 @@
+package gdpr
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+// bitWriter is the test-only mirror of bitReader, used to build synthetic
+// TCF v2 core strings with a known bit layout so the decoder can be
+// tested against values constructed the same way the spec describes,
+// rather than against one brittle hard-coded real-world string.
+type bitWriter struct {
+	bits []bool
+}
+
+func (w *bitWriter) writeUint(v uint64, nBits int) {
+	for i := nBits - 1; i >= 0; i-- {
+		w.bits = append(w.bits, (v>>uint(i))&1 == 1)
+	}
+}
+
+func (w *bitWriter) writeBit(b bool) {
+	w.bits = append(w.bits, b)
+}
+
+func (w *bitWriter) bytes() []byte {
+	out := make([]byte, (len(w.bits)+7)/8)
+	for i, b := range w.bits {
+		if b {
+			out[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+	return out
+}
+
+// tcStringOpts configures buildTCString's output.
+type tcStringOpts struct {
+	purposesGranted []int // 1-24
+	maxVendorID     int
+	isRangeEncoding bool
+	defaultConsent  bool
+	vendorRanges    []vendorRange
+	vendorBitfield  []bool // length maxVendorID
+}
+
+func buildTCString(t *testing.T, opts tcStringOpts) string {
+	t.Helper()
+	w := &bitWriter{}
+
+	w.writeUint(2, 6)  // version
+	w.writeUint(0, 36) // created
+	w.writeUint(0, 36) // last updated
+	w.writeUint(1, 12) // cmp id
+	w.writeUint(1, 12) // cmp version
+	w.writeUint(0, 6)  // consent screen
+	w.writeUint(0, 12) // consent language
+	w.writeUint(1, 12) // vendor list version
+	w.writeUint(2, 6)  // tcf policy version
+	w.writeBit(true)   // is service specific
+	w.writeBit(false)  // use non-standard stacks
+	w.writeUint(0, 12) // special feature opt-ins
+
+	var purposes uint64
+	for _, p := range opts.purposesGranted {
+		purposes |= 1 << uint(24-p)
+	}
+	w.writeUint(purposes, 24) // purposes consent
+	w.writeUint(0, 24)        // purposes LI transparency
+	w.writeBit(false)         // purpose one treatment
+	w.writeUint(0, 12)        // publisher CC
+
+	w.writeUint(uint64(opts.maxVendorID), 16)
+	w.writeBit(opts.isRangeEncoding)
+
+	if opts.isRangeEncoding {
+		w.writeBit(opts.defaultConsent)
+		w.writeUint(uint64(len(opts.vendorRanges)), 12)
+		for _, r := range opts.vendorRanges {
+			if r.start == r.end {
+				w.writeBit(false)
+				w.writeUint(uint64(r.start), 16)
+			} else {
+				w.writeBit(true)
+				w.writeUint(uint64(r.start), 16)
+				w.writeUint(uint64(r.end), 16)
+			}
+		}
+	} else {
+		for i := 0; i < opts.maxVendorID; i++ {
+			granted := i < len(opts.vendorBitfield) && opts.vendorBitfield[i]
+			w.writeBit(granted)
+		}
+	}
+
+	return base64.RawURLEncoding.EncodeToString(w.bytes())
+}
+
+func TestTCFv2Checker_AllowsVendorWithBitfieldAndPurposeConsent(t *testing.T) {
+	tcString := buildTCString(t, tcStringOpts{
+		purposesGranted: []int{1, 2, 3, 4},
+		maxVendorID:     100,
+		vendorBitfield:  bitfieldWithVendor(100, VendorMeta),
+	})
+
+	checker := NewTCFv2Checker(false)
+	allowed, purposes, reason := checker.CheckConsent(tcString, VendorMeta)
+	if !allowed {
+		t.Fatalf("expected consent to be allowed, got denied: %s", reason)
+	}
+	if !containsInt(purposes, 1) || !containsInt(purposes, 3) || !containsInt(purposes, 4) {
+		t.Errorf("expected purposes 1,3,4 to be reported granted, got %v", purposes)
+	}
+}
+
+func TestTCFv2Checker_DeniesVendorMissingFromBitfield(t *testing.T) {
+	tcString := buildTCString(t, tcStringOpts{
+		purposesGranted: []int{1, 3, 4},
+		maxVendorID:     100,
+		vendorBitfield:  make([]bool, 100), // nobody granted
+	})
+
+	checker := NewTCFv2Checker(false)
+	allowed, _, reason := checker.CheckConsent(tcString, VendorMeta)
+	if allowed {
+		t.Fatal("expected consent to be denied for a vendor absent from the bitfield")
+	}
+	if reason == "" {
+		t.Error("expected a non-empty denial reason")
+	}
+}
+
+func TestTCFv2Checker_DeniesMissingRequiredPurpose(t *testing.T) {
+	tcString := buildTCString(t, tcStringOpts{
+		purposesGranted: []int{1, 3}, // purpose 4 missing
+		maxVendorID:     100,
+		vendorBitfield:  bitfieldWithVendor(100, VendorMeta),
+	})
+
+	checker := NewTCFv2Checker(false)
+	allowed, _, reason := checker.CheckConsent(tcString, VendorMeta)
+	if allowed {
+		t.Fatal("expected consent to be denied when purpose 4 is missing")
+	}
+	if reason == "" {
+		t.Error("expected a non-empty denial reason")
+	}
+}
+
+func TestTCFv2Checker_RangeEncodingHonorsDefaultConsentAndExceptions(t *testing.T) {
+	tcString := buildTCString(t, tcStringOpts{
+		purposesGranted: []int{1, 3, 4},
+		maxVendorID:     200,
+		isRangeEncoding: true,
+		defaultConsent:  false,
+		vendorRanges:    []vendorRange{{start: 80, end: 95}},
+	})
+
+	checker := NewTCFv2Checker(false)
+
+	if allowed, _, reason := checker.CheckConsent(tcString, VendorMeta); !allowed {
+		t.Fatalf("expected vendor %d inside the exception range to be allowed, got denied: %s", VendorMeta, reason)
+	}
+	if allowed, _, _ := checker.CheckConsent(tcString, 150); allowed {
+		t.Fatal("expected a vendor outside the exception range to fall back to defaultConsent=false")
+	}
+}
+
+func TestTCFv2Checker_UsersyncIfAmbiguousFallback(t *testing.T) {
+	allowChecker := NewTCFv2Checker(true)
+	if allowed, purposes, reason := allowChecker.CheckConsent("", VendorMeta); !allowed {
+		t.Fatalf("expected empty consent string to fall back to allowed, got denied: %s", reason)
+	} else if purposes != nil {
+		t.Errorf("expected no purposes reported for an empty string, got %v", purposes)
+	}
+
+	denyChecker := NewTCFv2Checker(false)
+	if allowed, _, reason := denyChecker.CheckConsent("", VendorMeta); allowed {
+		t.Fatal("expected empty consent string to be denied when UsersyncIfAmbiguous is false")
+	} else if reason == "" {
+		t.Error("expected a non-empty reason for the ambiguous denial")
+	}
+}
+
+func TestTCFv2Checker_MalformedStringFallsBackToAmbiguousBehavior(t *testing.T) {
+	checker := NewTCFv2Checker(true)
+	allowed, _, reason := checker.CheckConsent("not-valid-base64url!!!", VendorMeta)
+	if !allowed {
+		t.Fatal("expected a malformed consent string to fall back to UsersyncIfAmbiguous")
+	}
+	if reason == "" {
+		t.Error("expected a non-empty reason explaining the fallback")
+	}
+}
+
+func bitfieldWithVendor(maxVendorID, vendorID int) []bool {
+	bitfield := make([]bool, maxVendorID)
+	bitfield[vendorID-1] = true
+	return bitfield
+}
+
+func containsInt(values []int, target int) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package connectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/openrtb"
+)
+
+// googleAdsImpExt is the Imp.Ext payload GoogleAdsAdapter reads off the
+// impression it's asked to bid - the LTV signal PlaceBid predicted,
+// carried through the OpenRTB Imp instead of threaded as a separate
+// argument.
+type googleAdsImpExt struct {
+	LTVSignal      float64 `json:"ltv_signal"`
+	LTVExplanation string  `json:"ltv_explanation,omitempty"`
+}
+
+// GoogleAdsAdapter implements openrtb.Adapter for Google Ads Smart
+// Bidding, translating a single OpenRTB impression into a typed
+// CampaignService.MutateCampaigns call carrying a TargetRoas bidding
+// strategy, and translating the mutate response back into an OpenRTB bid.
+type GoogleAdsAdapter struct {
+	DeveloperToken  string
+	LoginCustomerID string
+	CustomerID      string
+	BaseURL         string
+}
+
+// MakeRequests builds the single CampaignService.MutateCampaigns call
+// needed to place req.Imp[0] as a Target ROAS bid.
+func (a *GoogleAdsAdapter) MakeRequests(req *openrtb.OpenRTBRequest) ([]*openrtb.RequestData, []error) {
+	if len(req.Imp) == 0 {
+		return nil, []error{fmt.Errorf("google ads adapter: bid request carries no impressions")}
+	}
+	imp := req.Imp[0]
+
+	var ext googleAdsImpExt
+	if len(imp.Ext) > 0 {
+		if err := json.Unmarshal(imp.Ext, &ext); err != nil {
+			return nil, []error{fmt.Errorf("google ads adapter: decode imp.ext: %w", err)}
+		}
+	}
+
+	targetROAS := 0.0
+	if imp.BidFloor > 0 {
+		targetROAS = ext.LTVSignal / imp.BidFloor
+	}
+
+	mutateReq := &MutateCampaignsRequest{
+		CustomerID: a.CustomerID,
+		Operations: []*CampaignOperation{{
+			Create: &AdsCampaign{
+				ResourceName: fmt.Sprintf("customers/%s/campaigns/%s", a.CustomerID, imp.ID),
+				BiddingStrategy: &CampaignBiddingStrategy{
+					TargetRoas: &TargetRoas{
+						TargetRoas:          targetROAS,
+						CpcBidCeilingMicros: int64(imp.BidFloor * 1000000),
+					},
+				},
+				CustomParameters: map[string]interface{}{
+					"ltv_signal":      ext.LTVSignal,
+					"ltv_explanation": ext.LTVExplanation,
+					"kiki_timestamp":  time.Now().Unix(),
+				},
+			},
+		}},
+	}
+
+	body, err := json.Marshal(mutateReq)
+	if err != nil {
+		return nil, []error{fmt.Errorf("google ads adapter: marshal payload: %w", err)}
+	}
+
+	headers := map[string]string{
+		"Content-Type":    "application/json",
+		"developer-token": a.DeveloperToken,
+		// Authorization is attached by the caller's oauth2.Transport, not here.
+	}
+	if a.LoginCustomerID != "" {
+		headers["login-customer-id"] = a.LoginCustomerID
+	}
+
+	return []*openrtb.RequestData{{
+		Method:  http.MethodPost,
+		URI:     fmt.Sprintf("%s/customers/%s/campaigns:mutate", a.BaseURL, a.CustomerID),
+		Body:    body,
+		Headers: headers,
+	}}, nil
+}
+
+// MakeBids translates the MutateCampaigns response into a single OpenRTB
+// bid at the impression's floor - Google Ads' mutate API
+// doesn't return a clearing price, so a 200 is treated as a win at
+// BidFloor.
+func (a *GoogleAdsAdapter) MakeBids(req *openrtb.OpenRTBRequest, reqData *openrtb.RequestData, respData *openrtb.ResponseData) (*openrtb.BidderResponse, []error) {
+	if respData.StatusCode != http.StatusOK {
+		return nil, []error{fmt.Errorf("google ads adapter: mutate call returned status %d: %s", respData.StatusCode, string(respData.Body))}
+	}
+	if len(req.Imp) == 0 {
+		return nil, []error{fmt.Errorf("google ads adapter: bid request carries no impressions")}
+	}
+	imp := req.Imp[0]
+
+	return &openrtb.BidderResponse{
+		Currency: "USD",
+		Bids: []*openrtb.TypedBid{{
+			Seat: "google_ads",
+			Bid: &openrtb.Bid{
+				ID:    fmt.Sprintf("GADS_%d", time.Now().Unix()),
+				ImpID: imp.ID,
+				Price: imp.BidFloor,
+			},
+		}},
+	}, nil
+}
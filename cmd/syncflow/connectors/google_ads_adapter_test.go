@@ -0,0 +1,77 @@
+package connectors
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/openrtb"
+)
+
+func TestGoogleAdsAdapter_MakeRequestsCarriesLTVIntoTargetROAS(t *testing.T) {
+	ext, _ := json.Marshal(googleAdsImpExt{LTVSignal: 200, LTVExplanation: "high value customer"})
+	req := &openrtb.OpenRTBRequest{
+		ID:  "RTB_1",
+		Imp: []openrtb.Imp{{ID: "camp-1", BidFloor: 2.0, Ext: ext}},
+	}
+
+	adapter := &GoogleAdsAdapter{DeveloperToken: "key", CustomerID: "cust-1", BaseURL: "https://googleads.googleapis.com/v15"}
+	reqDatas, errs := adapter.MakeRequests(req)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(reqDatas) != 1 {
+		t.Fatalf("expected exactly one request, got %d", len(reqDatas))
+	}
+
+	rd := reqDatas[0]
+	if rd.URI != "https://googleads.googleapis.com/v15/customers/cust-1/campaigns:mutate" {
+		t.Errorf("unexpected URI: %s", rd.URI)
+	}
+	if rd.Headers["developer-token"] != "key" {
+		t.Errorf("expected developer-token header, got %q", rd.Headers["developer-token"])
+	}
+	if _, ok := rd.Headers["Authorization"]; ok {
+		t.Errorf("adapter should not set Authorization - that's the caller's oauth2.Transport's job")
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(rd.Body, &payload); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	op := payload["operations"].([]interface{})[0].(map[string]interface{})["create"].(map[string]interface{})
+	roas := op["biddingStrategy"].(map[string]interface{})["targetRoas"].(map[string]interface{})["targetRoas"].(float64)
+	if roas != 100.0 { // 200 LTV / 2.0 bid floor
+		t.Errorf("expected target ROAS of 100, got %.2f", roas)
+	}
+}
+
+func TestGoogleAdsAdapter_MakeRequestsRejectsEmptyImp(t *testing.T) {
+	adapter := &GoogleAdsAdapter{DeveloperToken: "key", CustomerID: "cust-1", BaseURL: "https://googleads.googleapis.com/v15"}
+	if _, errs := adapter.MakeRequests(&openrtb.OpenRTBRequest{ID: "RTB_1"}); len(errs) == 0 {
+		t.Fatal("expected an error for a bid request with no impressions")
+	}
+}
+
+func TestGoogleAdsAdapter_MakeBidsWinsAtBidFloorOnSuccess(t *testing.T) {
+	adapter := &GoogleAdsAdapter{DeveloperToken: "key", CustomerID: "cust-1", BaseURL: "https://googleads.googleapis.com/v15"}
+	req := &openrtb.OpenRTBRequest{ID: "RTB_1", Imp: []openrtb.Imp{{ID: "camp-1", BidFloor: 3.5}}}
+
+	resp, errs := adapter.MakeBids(req, &openrtb.RequestData{}, &openrtb.ResponseData{StatusCode: http.StatusOK})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(resp.Bids) != 1 || resp.Bids[0].Bid.Price != 3.5 || resp.Bids[0].Bid.ImpID != "camp-1" {
+		t.Fatalf("unexpected bidder response: %+v", resp.Bids)
+	}
+}
+
+func TestGoogleAdsAdapter_MakeBidsFailsOnNon200(t *testing.T) {
+	adapter := &GoogleAdsAdapter{DeveloperToken: "key", CustomerID: "cust-1", BaseURL: "https://googleads.googleapis.com/v15"}
+	req := &openrtb.OpenRTBRequest{ID: "RTB_1", Imp: []openrtb.Imp{{ID: "camp-1", BidFloor: 3.5}}}
+
+	_, errs := adapter.MakeBids(req, &openrtb.RequestData{}, &openrtb.ResponseData{StatusCode: http.StatusForbidden, Body: []byte("quota exceeded")})
+	if len(errs) == 0 {
+		t.Fatal("expected an error for a non-200 mutate response")
+	}
+}
@@ -0,0 +1,30 @@
+package connectors
+
+import (
+	"golang.org/x/oauth2"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/auth"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// defaultTokenCache is shared across every GoogleAdsSmartConnector built via
+// NewGoogleAdsSmartConnector, so multiple connectors authenticating as the
+// same customer don't each hit the OAuth2 token endpoint independently.
+var defaultTokenCache = auth.NewTokenCache()
+
+// breakerTokenSource wraps a shared oauth2.TokenSource so a refresh failure
+// - the token endpoint unreachable, credentials revoked - trips cb the same
+// way a failed Google Ads call would, instead of surfacing as an opaque
+// transport error the breaker never learns about.
+type breakerTokenSource struct {
+	source oauth2.TokenSource
+	cb     *shield.CircuitBreaker
+}
+
+func (b *breakerTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := b.source.Token()
+	if err != nil && b.cb != nil {
+		b.cb.RecordFailure(0)
+	}
+	return tok, err
+}
@@ -0,0 +1,160 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// The generated google.golang.org/genproto/googleapis/ads/googleads gRPC
+// stubs aren't vendored in this module, so the types below mirror the
+// field names of the real CampaignBudgetService/CampaignService protos
+// closely enough to give UpdateCampaignBudget and the bidding-strategy
+// create call typed requests and an explicit FieldMask, instead of the
+// untyped map[string]interface{} payloads they used to marshal by hand.
+// AdsClient carries them over Google Ads' REST transport rather than
+// gRPC; swapping in a real generated client later only means replacing
+// restAdsClient.
+
+// FieldMask mirrors google.protobuf.FieldMask: the dotted field paths an
+// Update operation actually intends to touch, so fields left at their Go
+// zero value aren't mistaken for an explicit clear.
+type FieldMask struct {
+	Paths []string `json:"paths"`
+}
+
+// CampaignBudget mirrors the CampaignBudget fields UpdateCampaignBudget is
+// allowed to touch.
+type CampaignBudget struct {
+	ResourceName   string `json:"resourceName"`
+	AmountMicros   int64  `json:"amountMicros"`
+	DeliveryMethod string `json:"deliveryMethod"`
+}
+
+// CampaignBudgetOperation mirrors CampaignBudgetOperation: an Update
+// carries both the new field values and UpdateMask naming which of them
+// changed.
+type CampaignBudgetOperation struct {
+	Update     *CampaignBudget `json:"update"`
+	UpdateMask *FieldMask      `json:"updateMask"`
+}
+
+// MutateCampaignBudgetsRequest mirrors
+// CampaignBudgetServiceClient.MutateCampaignBudgets's request proto.
+type MutateCampaignBudgetsRequest struct {
+	CustomerID string                     `json:"customerId"`
+	Operations []*CampaignBudgetOperation `json:"operations"`
+}
+
+// MutateCampaignBudgetsResponse carries the raw REST response back to the
+// caller; the real proto response lists resource names per operation, but
+// callers here only need to know whether the mutate succeeded.
+type MutateCampaignBudgetsResponse struct {
+	StatusCode int
+	Body       []byte
+}
+
+// TargetRoas mirrors the Google Ads TargetRoas bidding strategy proto.
+type TargetRoas struct {
+	TargetRoas          float64 `json:"targetRoas"`
+	CpcBidCeilingMicros int64   `json:"cpcBidCeilingMicros"`
+}
+
+// CampaignBiddingStrategy mirrors Campaign.bidding_strategy's oneof,
+// narrowed to the TargetRoas case this module bids with.
+type CampaignBiddingStrategy struct {
+	TargetRoas *TargetRoas `json:"targetRoas,omitempty"`
+}
+
+// AdsCampaign mirrors the Campaign proto fields a bidding-strategy create
+// touches.
+type AdsCampaign struct {
+	ResourceName     string                   `json:"resourceName"`
+	BiddingStrategy  *CampaignBiddingStrategy `json:"biddingStrategy,omitempty"`
+	CustomParameters map[string]interface{}   `json:"customParameters,omitempty"`
+}
+
+// CampaignOperation mirrors CampaignOperation, narrowed to the Create case.
+type CampaignOperation struct {
+	Create *AdsCampaign `json:"create,omitempty"`
+}
+
+// MutateCampaignsRequest mirrors CampaignServiceClient.MutateCampaigns's
+// request proto.
+type MutateCampaignsRequest struct {
+	CustomerID string               `json:"customerId"`
+	Operations []*CampaignOperation `json:"operations"`
+}
+
+// MutateCampaignsResponse carries the raw REST response back to the caller.
+type MutateCampaignsResponse struct {
+	StatusCode int
+	Body       []byte
+}
+
+// AdsClient abstracts the Google Ads mutate calls GoogleAdsSmartConnector
+// and GoogleAdsAdapter need, so tests can inject a fake instead of
+// standing up network access.
+type AdsClient interface {
+	MutateCampaignBudgets(ctx context.Context, req *MutateCampaignBudgetsRequest) (*MutateCampaignBudgetsResponse, error)
+	MutateCampaigns(ctx context.Context, req *MutateCampaignsRequest) (*MutateCampaignsResponse, error)
+}
+
+// restAdsClient is the default AdsClient: it posts the typed requests
+// above as JSON against Google Ads' REST surface.
+type restAdsClient struct {
+	BaseURL         string
+	CustomerID      string
+	DeveloperToken  string
+	LoginCustomerID string
+	HttpClient      *http.Client
+}
+
+func (c *restAdsClient) MutateCampaignBudgets(ctx context.Context, req *MutateCampaignBudgetsRequest) (*MutateCampaignBudgetsResponse, error) {
+	statusCode, body, err := c.post(ctx, fmt.Sprintf("%s/customers/%s/campaignBudgets:mutate", c.BaseURL, c.CustomerID), req)
+	if err != nil {
+		return nil, err
+	}
+	return &MutateCampaignBudgetsResponse{StatusCode: statusCode, Body: body}, nil
+}
+
+func (c *restAdsClient) MutateCampaigns(ctx context.Context, req *MutateCampaignsRequest) (*MutateCampaignsResponse, error) {
+	statusCode, body, err := c.post(ctx, fmt.Sprintf("%s/customers/%s/campaigns:mutate", c.BaseURL, c.CustomerID), req)
+	if err != nil {
+		return nil, err
+	}
+	return &MutateCampaignsResponse{StatusCode: statusCode, Body: body}, nil
+}
+
+func (c *restAdsClient) post(ctx context.Context, url string, payload interface{}) (int, []byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, nil, fmt.Errorf("marshal google ads request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, fmt.Errorf("build google ads request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("developer-token", c.DeveloperToken)
+	if c.LoginCustomerID != "" {
+		httpReq.Header.Set("login-customer-id", c.LoginCustomerID)
+	}
+	// Authorization is attached by HttpClient's oauth2.Transport, not here.
+
+	resp, err := c.HttpClient.Do(httpReq)
+	if err != nil {
+		return 0, nil, fmt.Errorf("google ads request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("read google ads response: %w", err)
+	}
+	return resp.StatusCode, respBody, nil
+}
@@ -0,0 +1,62 @@
+package connectors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/auth"
+)
+
+// fakeAdsClient is an injected AdsClient that records the last request it
+// received instead of making a network call.
+type fakeAdsClient struct {
+	lastBudgetReq *MutateCampaignBudgetsRequest
+	budgetResp    *MutateCampaignBudgetsResponse
+	budgetErr     error
+}
+
+func (f *fakeAdsClient) MutateCampaignBudgets(ctx context.Context, req *MutateCampaignBudgetsRequest) (*MutateCampaignBudgetsResponse, error) {
+	f.lastBudgetReq = req
+	return f.budgetResp, f.budgetErr
+}
+
+func (f *fakeAdsClient) MutateCampaigns(ctx context.Context, req *MutateCampaignsRequest) (*MutateCampaignsResponse, error) {
+	return &MutateCampaignsResponse{StatusCode: 200}, nil
+}
+
+func TestGoogleAdsSmartConnector_UpdateCampaignBudgetSendsFieldMaskedUpdate(t *testing.T) {
+	fake := &fakeAdsClient{budgetResp: &MutateCampaignBudgetsResponse{StatusCode: 200}}
+	g := NewGoogleAdsSmartConnector(auth.StaticTokenAuthProvider{AccessToken: "key"}, "dev-token", "cust-1", "", 1000)
+	g.AdsClient = fake
+	g.Connected = true
+
+	resp, err := g.UpdateCampaignBudget(context.Background(), "camp-1", 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got %+v", resp)
+	}
+
+	if fake.lastBudgetReq == nil || len(fake.lastBudgetReq.Operations) != 1 {
+		t.Fatalf("expected exactly one operation, got %+v", fake.lastBudgetReq)
+	}
+	op := fake.lastBudgetReq.Operations[0]
+	if op.Update.AmountMicros != 50*1000000 {
+		t.Errorf("expected amount micros of 50000000, got %d", op.Update.AmountMicros)
+	}
+	if op.UpdateMask == nil || len(op.UpdateMask.Paths) != 2 {
+		t.Fatalf("expected a field mask naming exactly the two changed fields, got %+v", op.UpdateMask)
+	}
+}
+
+func TestGoogleAdsSmartConnector_UpdateCampaignBudgetSurfacesClientError(t *testing.T) {
+	fake := &fakeAdsClient{budgetErr: context.DeadlineExceeded}
+	g := NewGoogleAdsSmartConnector(auth.StaticTokenAuthProvider{AccessToken: "key"}, "dev-token", "cust-1", "", 1000)
+	g.AdsClient = fake
+	g.Connected = true
+
+	if _, err := g.UpdateCampaignBudget(context.Background(), "camp-1", 50); err == nil {
+		t.Fatal("expected the AdsClient error to propagate")
+	}
+}
@@ -1,323 +1,728 @@
-package connectors
-
-import (
-	"bytes"
-	"context"
-	"encoding/json"
-	"fmt"
-	"log"
-	"net/http"
-	"time"
-
-	"github.com/user/kiki-agent/cmd/syncshield/shield"
-)
-
-// GoogleAdsSmartConnector implements PlatformConnector with BudgetManager integration
-// This provides production-grade budget control and API rate limiting
-type GoogleAdsSmartConnector struct {
-	APIKey         string
-	CustomerID     string
-	HttpClient     *http.Client
-	Connected      bool
-	BaseURL        string
-	BudgetManager  *shield.BudgetManager
-	RateLimiter    *RateLimiter
-	CircuitBreaker *shield.CircuitBreaker   // NEW: Resilience against SyncValue™ latency
-	FallbackEngine *HeuristicFallbackEngine // NEW: Heuristic bidding when AI unavailable
-	MockMode       bool                     // For testing without real API calls
-}
-
-// RateLimiter provides API call rate limiting to prevent platform bans
-type RateLimiter struct {
-	MaxCallsPerMinute int
-	callTimestamps    []time.Time
-}
-
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(maxCalls int) *RateLimiter {
-	return &RateLimiter{
-		MaxCallsPerMinute: maxCalls,
-		callTimestamps:    make([]time.Time, 0, maxCalls),
-	}
-}
-
-// CanMakeCall checks if we can make an API call without exceeding rate limits
-func (rl *RateLimiter) CanMakeCall() bool {
-	now := time.Now()
-	cutoff := now.Add(-1 * time.Minute)
-
-	// Remove timestamps older than 1 minute
-	i := 0
-	for i < len(rl.callTimestamps) && rl.callTimestamps[i].Before(cutoff) {
-		i++
-	}
-	rl.callTimestamps = rl.callTimestamps[i:]
-
-	return len(rl.callTimestamps) < rl.MaxCallsPerMinute
-}
-
-// RecordCall records that an API call was made
-func (rl *RateLimiter) RecordCall() {
-	rl.callTimestamps = append(rl.callTimestamps, time.Now())
-}
-
-// NewGoogleAdsSmartConnector creates a new Google Ads connector with budget management
-func NewGoogleAdsSmartConnector(apiKey, customerID string, maxBudget float64) *GoogleAdsSmartConnector {
-	return &GoogleAdsSmartConnector{
-		APIKey:         apiKey,
-		CustomerID:     customerID,
-		HttpClient:     &http.Client{Timeout: 10 * time.Second},
-		BaseURL:        "https://googleads.googleapis.com/v15",
-		BudgetManager:  shield.NewBudgetManager(maxBudget),
-		RateLimiter:    NewRateLimiter(100), // Google Ads allows ~100 calls/minute
-		CircuitBreaker: shield.NewCircuitBreaker(),
-		FallbackEngine: NewHeuristicFallbackEngine(),
-	}
-}
-
-// Connect establishes connection to Google Ads API
-func (g *GoogleAdsSmartConnector) Connect(ctx context.Context) error {
-	log.Printf("🔗 Connecting to Google Ads Smart Bidding API for customer: %s", g.CustomerID)
-
-	// In production, validate API credentials here
-	// Example: Make a test API call to verify credentials
-
-	g.Connected = true
-	stats := g.BudgetManager.GetStats()
-	log.Printf("✅ Google Ads connection established - Budget: $%.2f/$%.2f", stats.CurrentSpend, stats.MaxBudget)
-	return nil
-}
-
-// PlaceBid sends a bid to Google Ads with comprehensive safety checks
-func (g *GoogleAdsSmartConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
-	if !g.Connected {
-		return nil, fmt.Errorf("not connected to Google Ads")
-	}
-
-	// Record latest LTV to improve fallback median quality
-	g.FallbackEngine.RecordLTV("google_ads", req.PredictedLTV)
-
-	// Decide bid source via circuit breaker
-	bidAmount := req.BidAmount
-	decisionSource := "ai"
-	if !g.CircuitBreaker.CanExecute() {
-		g.CircuitBreaker.RecordFallback()
-		bidAmount = g.FallbackEngine.CalculateFallbackBid("google_ads", req.PredictedLTV)
-		decisionSource = "fallback"
-	}
-
-	// Safety Check 1: Budget validation
-	if !g.BudgetManager.CanSpend(bidAmount) {
-		stats := g.BudgetManager.GetStats()
-		log.Printf("🛡️ BUDGET VETO: Bid $%.2f exceeds remaining budget $%.2f", bidAmount, stats.RemainingBudget)
-		return &BidResponse{
-			Success:      false,
-			Message:      fmt.Sprintf("Budget exceeded: $%.2f spent of $%.2f limit", stats.CurrentSpend, stats.MaxBudget),
-			PlatformCode: "BUDGET_EXCEEDED",
-			Timestamp:    time.Now(),
-		}, fmt.Errorf("budget exceeded")
-	}
-
-	// Safety Check 2: Rate limiting
-	if !g.RateLimiter.CanMakeCall() {
-		log.Printf("⚠️ RATE LIMIT: Too many API calls, throttling")
-		return &BidResponse{
-			Success:      false,
-			Message:      "Rate limit exceeded, throttling API calls",
-			PlatformCode: "RATE_LIMITED",
-			Timestamp:    time.Now(),
-		}, fmt.Errorf("rate limited")
-	}
-
-	log.Printf("📍 PlaceBid: Customer=%s, LTV=%.2f, Bid=$%.2f (source=%s)", req.CustomerID, req.PredictedLTV, bidAmount, decisionSource)
-
-	// Format payload for Google Ads Smart Bidding API
-	// This uses Target ROAS (Return on Ad Spend) based on LTV prediction
-	targetROAS := req.PredictedLTV / bidAmount
-
-	payload := map[string]interface{}{
-		"customerId": g.CustomerID,
-		"operations": []map[string]interface{}{
-			{
-				"create": map[string]interface{}{
-					"resourceName": fmt.Sprintf("customers/%s/campaigns/%s", g.CustomerID, req.CampaignID),
-					"biddingStrategy": map[string]interface{}{
-						"targetRoas": map[string]interface{}{
-							"targetRoas":          targetROAS,
-							"cpcBidCeilingMicros": int64(bidAmount * 1000000), // Convert to micros
-						},
-					},
-					"customParameters": map[string]interface{}{
-						"ltv_signal":      req.PredictedLTV,
-						"ltv_explanation": req.Explanation,
-						"kiki_timestamp":  req.Timestamp.Unix(),
-					},
-				},
-			},
-		},
-	}
-
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload: %v", err)
-	}
-
-	// Make API call to Google Ads
-	apiURL := fmt.Sprintf("%s/customers/%s/campaignBidModifiers:mutate", g.BaseURL, g.CustomerID)
-
-	// Create request with proper headers
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.APIKey))
-	httpReq.Header.Set("developer-token", g.APIKey)
-
-	callStart := time.Now()
-
-	// Mock mode for testing
-	if g.MockMode {
-		log.Printf("🧪 MOCK MODE: Simulating Google Ads API call")
-		g.RateLimiter.RecordCall()
-		g.BudgetManager.AddSpend(bidAmount)
-		stats := g.BudgetManager.GetStats()
-		log.Printf("✅ Bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
-		g.CircuitBreaker.RecordSuccess(time.Since(callStart))
-
-		return &BidResponse{
-			Success:      true,
-			BidID:        fmt.Sprintf("MOCK_GADS_%d", time.Now().Unix()),
-			Message:      fmt.Sprintf("Smart Bid placed with Target ROAS: %.2f (MOCK)", targetROAS),
-			PlatformCode: "GOOGLE_ADS_SMART_BIDDING",
-			Timestamp:    time.Now(),
-		}, nil
-	}
-
-	resp, err := g.HttpClient.Do(httpReq)
-	if err != nil {
-		g.CircuitBreaker.RecordFailure(time.Since(callStart))
-		log.Printf("❌ Google Ads API error: %v", err)
-		return &BidResponse{
-			Success:      false,
-			Message:      fmt.Sprintf("API error: %v", err),
-			PlatformCode: "GOOGLE_ADS_ERROR",
-			Timestamp:    time.Now(),
-		}, err
-	}
-	defer resp.Body.Close()
-
-	// Record successful API call
-	g.RateLimiter.RecordCall()
-
-	// If bid was successful, record the spend
-	if resp.StatusCode == 200 {
-		g.BudgetManager.AddSpend(bidAmount)
-		stats := g.BudgetManager.GetStats()
-		log.Printf("✅ Bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
-		g.CircuitBreaker.RecordSuccess(time.Since(callStart))
-	} else {
-		g.CircuitBreaker.RecordFailure(time.Since(callStart))
-	}
-
-	return &BidResponse{
-		Success:      resp.StatusCode == 200,
-		BidID:        fmt.Sprintf("GADS_%d", time.Now().Unix()),
-		Message:      fmt.Sprintf("Smart Bid placed with Target ROAS: %.2f", targetROAS),
-		PlatformCode: "GOOGLE_ADS_SMART_BIDDING",
-		Timestamp:    time.Now(),
-	}, nil
-}
-
-// UpdateCampaignBudget adjusts campaign budget based on LTV insights
-func (g *GoogleAdsSmartConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
-	if !g.Connected {
-		return nil, fmt.Errorf("not connected to Google Ads")
-	}
-
-	// Check if this budget update would exceed our limits
-	if !g.BudgetManager.CanSpend(budgetAmount) {
-		return nil, fmt.Errorf("budget update would exceed limits")
-	}
-
-	log.Printf("💰 UpdateCampaignBudget: Campaign=%s, Budget=$%.2f", campaignID, budgetAmount)
-
-	apiURL := fmt.Sprintf("%s/customers/%s/campaignBudgets", g.BaseURL, g.CustomerID)
-
-	payload := map[string]interface{}{
-		"operations": []map[string]interface{}{
-			{
-				"update": map[string]interface{}{
-					"resourceName":     fmt.Sprintf("customers/%s/campaignBudgets/%s", g.CustomerID, campaignID),
-					"amountMicros":     int64(budgetAmount * 1000000),
-					"deliveryMethod":   "STANDARD",
-					"explicitlyShared": false,
-				},
-				"updateMask": "amountMicros",
-			},
-		},
-	}
-
-	payloadBytes, _ := json.Marshal(payload)
-
-	httpReq, _ := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(payloadBytes))
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.APIKey))
-
-	resp, err := g.HttpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	g.RateLimiter.RecordCall()
-
-	return &BidResponse{
-		Success:      resp.StatusCode == 200,
-		Message:      fmt.Sprintf("Campaign %s budget updated to $%.2f", campaignID, budgetAmount),
-		PlatformCode: "GOOGLE_ADS_BUDGET",
-		Timestamp:    time.Now(),
-	}, nil
-}
-
-// UpdateTargetAudience modifies audience targeting based on predicted LTV
-func (g *GoogleAdsSmartConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
-	if !g.Connected {
-		return nil, fmt.Errorf("not connected to Google Ads")
-	}
-
-	log.Printf("🎯 UpdateTargetAudience: Campaign=%s, Audience=%s", campaignID, audienceID)
-
-	return &BidResponse{
-		Success:      true,
-		Message:      fmt.Sprintf("Audience targeting updated for campaign %s", campaignID),
-		PlatformCode: "GOOGLE_ADS_AUDIENCE",
-		Timestamp:    time.Now(),
-	}, nil
-}
-
-// GetStatus returns the connection status with budget info
-func (g *GoogleAdsSmartConnector) GetStatus() string {
-	if g.Connected {
-		stats := g.BudgetManager.GetStats()
-		return fmt.Sprintf("Connected to Google Ads - Budget: $%.2f/$%.2f (%.1f%% used)",
-			stats.CurrentSpend,
-			stats.MaxBudget,
-			(stats.CurrentSpend/stats.MaxBudget)*100)
-	}
-	return "Disconnected"
-}
-
-// Close cleanly disconnects from the platform
-func (g *GoogleAdsSmartConnector) Close() error {
-	stats := g.BudgetManager.GetStats()
-	log.Printf("🔌 Google Ads connection closed - Final spend: $%.2f", stats.CurrentSpend)
-	g.Connected = false
-	return nil
-}
-
-// GetBudgetStats returns current budget statistics
-func (g *GoogleAdsSmartConnector) GetBudgetStats() shield.WindowStats {
-	return g.BudgetManager.GetStats()
-}
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/auth"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/openrtb"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// adaptiveLimiterAcquireTimeout bounds how long PlaceBid waits for a free
+// AdaptiveLimiter slot before shedding the bid as LIMIT_EXCEEDED.
+const adaptiveLimiterAcquireTimeout = 2 * time.Second
+
+// defaultAdaptiveLimiterMinLimit/MaxLimit seed each campaign's
+// AdaptiveLimiter; MinLimit keeps a cold campaign from being throttled to
+// zero concurrency before it has any RTT samples to grow from.
+const (
+	defaultAdaptiveLimiterMinLimit = 4
+	defaultAdaptiveLimiterMaxLimit = 64
+)
+
+// GoogleAdsSmartConnector implements PlatformConnector with BudgetManager integration
+// This provides production-grade budget control and API rate limiting
+type GoogleAdsSmartConnector struct {
+	AuthProvider    auth.AuthProvider // Issues/refreshes the OAuth2 token HttpClient's transport attaches
+	DeveloperToken  string            // Google Ads developer-token header; not a secret the OAuth2 flow covers
+	CustomerID      string
+	LoginCustomerID string // customer acting on behalf of CustomerID; sent as login-customer-id when set
+	HttpClient      *http.Client
+	Connected       bool
+	BaseURL         string
+	BudgetManager   *shield.BudgetManager
+	RateLimiter     *RateLimiter
+	CircuitBreaker  *shield.CircuitBreaker   // NEW: Resilience against SyncValue™ latency
+	FallbackEngine  *HeuristicFallbackEngine // NEW: Heuristic bidding when AI unavailable
+	MockMode        bool                     // For testing without real API calls
+	AdsClient       AdsClient                // Mutate calls against Google Ads; injectable so tests don't need network
+
+	// Limiters holds one shield.AdaptiveLimiter per campaign ID, so a burst
+	// against one campaign backs off its own concurrency budget ahead of
+	// CircuitBreaker rather than throttling every campaign sharing this
+	// connector's breaker. Lazily populated by limiterFor.
+	Limiters   map[string]*shield.AdaptiveLimiter
+	limitersMu sync.Mutex
+
+	// PlaceBidPolicy and UpdateCampaignBudgetPolicy govern per-attempt
+	// retry/backoff for their respective calls, so a single transient 503
+	// doesn't kill a bid outright. A retry cycle counts as one logical call
+	// against CircuitBreaker; see CallPolicy.Execute.
+	PlaceBidPolicy             *CallPolicy
+	UpdateCampaignBudgetPolicy *CallPolicy
+
+	// Recorders observe PlaceBid's decision points (budget veto, rate
+	// limit, fallback, success/failure) for operators who want to know why
+	// a bid was shaped the way it was, not just the circuit breaker's
+	// current state. Nil entries and a nil slice are both no-ops.
+	Recorders []Recorder
+
+	// SpendLedger, if set, records every successful bid so a crash can
+	// later be reconciled against Google Ads' reporting API via
+	// connectors.FindLCA/Rewind. Left nil, bids simply aren't ledgered.
+	SpendLedger SpendLedger
+}
+
+// RateLimiter is a token-bucket rate limiter (configurable burst + refill
+// rate) paired with an AIMD adaptive concurrency controller: RecordFailure
+// (an HTTP 429 or a CircuitBreaker trip) halves the concurrency ceiling,
+// while enough consecutive fast successes additively grows it back up to
+// MaxCallsPerMinute. CanMakeCall/RecordCall preserve the older fail-fast,
+// rate-only check older connectors still use; Acquire/Release gate on both
+// the token bucket and the concurrency ceiling for connectors that have
+// moved to blocking on a context-bounded wait instead.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	burst        float64
+	refillPerSec float64
+	tokens       float64
+	lastRefill   time.Time
+
+	minConcurrency     int
+	maxConcurrency     int
+	concurrency        int
+	inFlight           int
+	consecutiveSuccess int
+	successesToGrow    int
+	targetLatency      time.Duration
+
+	recentOutcomes []bool // ring of recent RecordSuccess/RecordFailure calls; true = failure/429
+}
+
+const (
+	rateLimiterSuccessesToGrow = 5
+	rateLimiterTargetLatency   = 300 * time.Millisecond
+	rateLimiterRecentWindow    = 50
+	rateLimiterAcquirePoll     = 10 * time.Millisecond
+
+	// rateLimiterMinRefillPerSec floors how low RecordRateLimited/
+	// ObserveRemaining can drive the refill rate - a platform reporting
+	// near-zero remaining calls should slow the bucket, not stall it.
+	rateLimiterMinRefillPerSec = 0.05
+	// rateLimiterNearExhaustionRatio is the X-RateLimit-Remaining/
+	// X-RateLimit-Limit threshold below which ObserveRemaining treats the
+	// platform as already close to throttling, ahead of an actual 429.
+	rateLimiterNearExhaustionRatio = 0.1
+)
+
+// NewRateLimiter creates a rate limiter with burst and refill rate both
+// derived from maxCallsPerMinute, and an initial concurrency ceiling equal
+// to that same burst.
+func NewRateLimiter(maxCallsPerMinute int) *RateLimiter {
+	burst := float64(maxCallsPerMinute)
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		burst:           burst,
+		refillPerSec:    burst / 60.0,
+		tokens:          burst,
+		lastRefill:      time.Now(),
+		minConcurrency:  1,
+		maxConcurrency:  maxCallsPerMinute,
+		concurrency:     maxCallsPerMinute,
+		successesToGrow: rateLimiterSuccessesToGrow,
+		targetLatency:   rateLimiterTargetLatency,
+	}
+}
+
+// refill tops up the token bucket for elapsed time. Callers must hold rl.mu.
+func (rl *RateLimiter) refill() {
+	now := time.Now()
+	elapsed := now.Sub(rl.lastRefill).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	rl.tokens += elapsed * rl.refillPerSec
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+	rl.lastRefill = now
+}
+
+// CanMakeCall reports whether the token bucket currently has a token
+// available, without consuming one or considering the concurrency ceiling.
+func (rl *RateLimiter) CanMakeCall() bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.refill()
+	return rl.tokens >= 1
+}
+
+// RecordCall consumes a token for a call that's about to go out.
+func (rl *RateLimiter) RecordCall() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.refill()
+	if rl.tokens >= 1 {
+		rl.tokens--
+	}
+}
+
+// Acquire blocks until a token is available and current in-flight calls are
+// under the AIMD concurrency ceiling, or ctx is done first. Every
+// successful Acquire must be paired with a Release once the call completes.
+func (rl *RateLimiter) Acquire(ctx context.Context) error {
+	for {
+		rl.mu.Lock()
+		rl.refill()
+		if rl.tokens >= 1 && rl.inFlight < rl.concurrency {
+			rl.tokens--
+			rl.inFlight++
+			rl.mu.Unlock()
+			return nil
+		}
+		rl.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rateLimiterAcquirePoll):
+		}
+	}
+}
+
+// Release frees the in-flight slot an Acquire call reserved.
+func (rl *RateLimiter) Release() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rl.inFlight > 0 {
+		rl.inFlight--
+	}
+}
+
+// RecordSuccess feeds a completed call's latency into the AIMD controller.
+// Once successesToGrow consecutive calls land at or under targetLatency,
+// the concurrency ceiling grows by one, up to maxConcurrency.
+func (rl *RateLimiter) RecordSuccess(latency time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.recordOutcome(false)
+
+	if latency > rl.targetLatency {
+		rl.consecutiveSuccess = 0
+		return
+	}
+	rl.consecutiveSuccess++
+	if rl.consecutiveSuccess >= rl.successesToGrow {
+		rl.consecutiveSuccess = 0
+		if rl.concurrency < rl.maxConcurrency {
+			rl.concurrency++
+		}
+	}
+}
+
+// RecordFailure feeds an HTTP 429 or a CircuitBreaker trip into the AIMD
+// controller, halving the concurrency ceiling down to minConcurrency.
+func (rl *RateLimiter) RecordFailure() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.recordOutcome(true)
+	rl.consecutiveSuccess = 0
+	rl.concurrency /= 2
+	if rl.concurrency < rl.minConcurrency {
+		rl.concurrency = rl.minConcurrency
+	}
+}
+
+// Wait blocks until a token is available or ctx is done, without touching
+// the AIMD concurrency ceiling Acquire/Release manage - for connectors that
+// rate-limit on calls-per-minute alone and don't pair every call with a
+// Release.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		rl.mu.Lock()
+		rl.refill()
+		if rl.tokens >= 1 {
+			rl.tokens--
+			rl.mu.Unlock()
+			return nil
+		}
+		rl.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rateLimiterAcquirePoll):
+		}
+	}
+}
+
+// RecordRateLimited handles an explicit platform throttling signal - an
+// HTTP 429 - by halving the refill rate in addition to the concurrency
+// ceiling RecordFailure already halves. A 429 means the platform is
+// rejecting calls at the current pace, not merely failing for an unrelated
+// reason, so the bucket itself needs to slow down, not just concurrency.
+func (rl *RateLimiter) RecordRateLimited() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.recordOutcome(true)
+	rl.consecutiveSuccess = 0
+	rl.concurrency /= 2
+	if rl.concurrency < rl.minConcurrency {
+		rl.concurrency = rl.minConcurrency
+	}
+	rl.refillPerSec /= 2
+	if rl.refillPerSec < rateLimiterMinRefillPerSec {
+		rl.refillPerSec = rateLimiterMinRefillPerSec
+	}
+}
+
+// ObserveRemaining adapts the refill rate from a platform's own
+// X-RateLimit-Remaining/X-RateLimit-Limit response headers, throttling
+// proactively once remaining drops under rateLimiterNearExhaustionRatio of
+// limit - ahead of an actual 429. limit <= 0 means the platform didn't send
+// quota headers on this response and is ignored.
+func (rl *RateLimiter) ObserveRemaining(remaining, limit int) {
+	if limit <= 0 || float64(remaining)/float64(limit) > rateLimiterNearExhaustionRatio {
+		return
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.refillPerSec /= 2
+	if rl.refillPerSec < rateLimiterMinRefillPerSec {
+		rl.refillPerSec = rateLimiterMinRefillPerSec
+	}
+}
+
+// recordOutcome must be called with rl.mu held.
+func (rl *RateLimiter) recordOutcome(failed bool) {
+	rl.recentOutcomes = append(rl.recentOutcomes, failed)
+	if len(rl.recentOutcomes) > rateLimiterRecentWindow {
+		rl.recentOutcomes = rl.recentOutcomes[len(rl.recentOutcomes)-rateLimiterRecentWindow:]
+	}
+}
+
+// RateLimiterSnapshot is a point-in-time view of a RateLimiter, suitable for
+// /health and Prometheus export.
+type RateLimiterSnapshot struct {
+	TokensAvailable float64
+	Concurrency     int
+	InFlight        int
+	Recent429Rate   float64
+}
+
+// Snapshot reports the limiter's available tokens, concurrency ceiling, and
+// the failure rate over its recent call window.
+func (rl *RateLimiter) Snapshot() RateLimiterSnapshot {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.refill()
+
+	var rate float64
+	if len(rl.recentOutcomes) > 0 {
+		var failures int
+		for _, failed := range rl.recentOutcomes {
+			if failed {
+				failures++
+			}
+		}
+		rate = float64(failures) / float64(len(rl.recentOutcomes))
+	}
+
+	return RateLimiterSnapshot{
+		TokensAvailable: rl.tokens,
+		Concurrency:     rl.concurrency,
+		InFlight:        rl.inFlight,
+		Recent429Rate:   rate,
+	}
+}
+
+// NewGoogleAdsSmartConnector creates a new Google Ads connector with budget
+// management. authProvider supplies the OAuth2 token HttpClient's transport
+// attaches to every request and refreshes automatically as it nears expiry;
+// developerToken and loginCustomerID are sent as-is on the developer-token
+// and login-customer-id headers Google Ads also requires alongside the
+// bearer token.
+func NewGoogleAdsSmartConnector(authProvider auth.AuthProvider, developerToken, customerID, loginCustomerID string, maxBudget float64) *GoogleAdsSmartConnector {
+	baseURL := "https://googleads.googleapis.com/v15"
+	cb := shield.NewCircuitBreaker()
+
+	cacheKey := loginCustomerID
+	if cacheKey == "" {
+		cacheKey = customerID
+	}
+	tokenSource := &breakerTokenSource{
+		source: defaultTokenCache.Shared(context.Background(), cacheKey, authProvider),
+		cb:     cb,
+	}
+	httpClient := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &oauth2.Transport{Source: tokenSource, Base: http.DefaultTransport},
+	}
+
+	return &GoogleAdsSmartConnector{
+		AuthProvider:    authProvider,
+		DeveloperToken:  developerToken,
+		CustomerID:      customerID,
+		LoginCustomerID: loginCustomerID,
+		HttpClient:      httpClient,
+		BaseURL:         baseURL,
+		BudgetManager:   shield.NewBudgetManager(maxBudget),
+		RateLimiter:     NewRateLimiter(100), // Google Ads allows ~100 calls/minute
+		CircuitBreaker:  cb,
+		FallbackEngine:  NewHeuristicFallbackEngine(),
+		Limiters:        make(map[string]*shield.AdaptiveLimiter),
+		AdsClient: &restAdsClient{
+			BaseURL:         baseURL,
+			CustomerID:      customerID,
+			DeveloperToken:  developerToken,
+			LoginCustomerID: loginCustomerID,
+			HttpClient:      httpClient,
+		},
+
+		PlaceBidPolicy:             DefaultPlaceBidCallPolicy(),
+		UpdateCampaignBudgetPolicy: DefaultUpdateCampaignBudgetCallPolicy(),
+	}
+}
+
+// Connect establishes connection to Google Ads API
+func (g *GoogleAdsSmartConnector) Connect(ctx context.Context) error {
+	log.Printf("🔗 Connecting to Google Ads Smart Bidding API for customer: %s", g.CustomerID)
+
+	// In production, validate API credentials here
+	// Example: Make a test API call to verify credentials
+
+	g.Connected = true
+	stats := g.BudgetManager.GetStats()
+	log.Printf("✅ Google Ads connection established - Budget: $%.2f/$%.2f", stats.CurrentSpend, stats.MaxBudget)
+	return nil
+}
+
+// PlaceBid sends a bid to Google Ads with comprehensive safety checks
+func (g *GoogleAdsSmartConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	if !g.Connected {
+		return nil, fmt.Errorf("not connected to Google Ads")
+	}
+
+	startTime := time.Now()
+
+	// Record latest LTV to improve fallback median quality
+	g.FallbackEngine.RecordLTV("google_ads", req.PredictedLTV)
+
+	// Decide bid source via circuit breaker
+	bidAmount := req.BidAmount
+	decisionSource := "ai"
+	if !g.CircuitBreaker.CanExecute() {
+		g.CircuitBreaker.RecordFallback()
+		fallbackAmount := g.FallbackEngine.CalculateFallbackBid("google_ads", req.PredictedLTV)
+		fireRecorders(g.Recorders, func(r Recorder) { r.LogFallback("google_ads", bidAmount, fallbackAmount) })
+		bidAmount = fallbackAmount
+		decisionSource = "fallback"
+	}
+
+	// Safety Check 1: Budget validation
+	if !g.BudgetManager.CanSpend(bidAmount) {
+		stats := g.BudgetManager.GetStats()
+		log.Printf("🛡️ BUDGET VETO: Bid $%.2f exceeds remaining budget $%.2f", bidAmount, stats.RemainingBudget)
+		fireRecorders(g.Recorders, func(r Recorder) { r.LogBudgetVeto("google_ads", bidAmount, stats.RemainingBudget) })
+		return &BidResponse{
+			Success:      false,
+			Message:      fmt.Sprintf("Budget exceeded: $%.2f spent of $%.2f limit", stats.CurrentSpend, stats.MaxBudget),
+			PlatformCode: "BUDGET_EXCEEDED",
+			Timestamp:    time.Now(),
+		}, shield.NewError(shield.ErrorKindBudgetExceeded, "google_ads", g.CircuitBreaker.GetState(), fmt.Errorf("budget exceeded"))
+	}
+
+	// Safety Check 2: Rate limiting
+	if !g.RateLimiter.CanMakeCall() {
+		log.Printf("⚠️ RATE LIMIT: Too many API calls, throttling")
+		fireRecorders(g.Recorders, func(r Recorder) { r.LogRateLimit("google_ads") })
+		return &BidResponse{
+			Success:      false,
+			Message:      "Rate limit exceeded, throttling API calls",
+			PlatformCode: "RATE_LIMITED",
+			Timestamp:    time.Now(),
+		}, fmt.Errorf("rate limited")
+	}
+
+	log.Printf("📍 PlaceBid: Customer=%s, LTV=%.2f, Bid=$%.2f (source=%s)", req.CustomerID, req.PredictedLTV, bidAmount, decisionSource)
+
+	// This uses Target ROAS (Return on Ad Spend) based on LTV prediction
+	targetROAS := req.PredictedLTV / bidAmount
+
+	// Build the bid as an OpenRTB impression - PredictedLTV rides in
+	// Imp.Ext so GoogleAdsAdapter can recover it on the other side of
+	// MakeRequests, the same way a real Prebid bidder would.
+	impExt, err := json.Marshal(googleAdsImpExt{LTVSignal: req.PredictedLTV, LTVExplanation: req.Explanation})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal imp ext: %v", err)
+	}
+	rtbReq := &openrtb.OpenRTBRequest{
+		ID: fmt.Sprintf("RTB_%d", req.Timestamp.UnixNano()),
+		Imp: []openrtb.Imp{{
+			ID:       req.CampaignID,
+			BidFloor: bidAmount,
+			Ext:      impExt,
+		}},
+		User: &openrtb.User{ID: req.CustomerID},
+	}
+
+	adapter := &GoogleAdsAdapter{DeveloperToken: g.DeveloperToken, LoginCustomerID: g.LoginCustomerID, CustomerID: g.CustomerID, BaseURL: g.BaseURL}
+	reqDatas, errs := adapter.MakeRequests(rtbReq)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to build OpenRTB request: %v", errs[0])
+	}
+	reqData := reqDatas[0]
+
+	// AdaptiveLimiter gates concurrency per campaign ahead of
+	// CircuitBreaker: a burst against one campaign backs off its own
+	// budget, chained before the breaker reacts to the latency it causes.
+	release, limiterErr := g.limiterFor(req.CampaignID).Acquire(ctx, adaptiveLimiterAcquireTimeout)
+	if limiterErr != nil {
+		log.Printf("⚠️ ADAPTIVE LIMIT: google_ads campaign=%s at capacity (%v)", req.CampaignID, limiterErr)
+		return &BidResponse{
+			Success:      false,
+			Message:      "Adaptive limiter at capacity, shedding load",
+			PlatformCode: "LIMIT_EXCEEDED",
+			Timestamp:    time.Now(),
+		}, limiterErr
+	}
+	callStart := time.Now()
+
+	// Mock mode for testing
+	if g.MockMode {
+		log.Printf("🧪 MOCK MODE: Simulating Google Ads API call")
+		g.RateLimiter.RecordCall()
+		g.BudgetManager.AddSpend(bidAmount)
+		stats := g.BudgetManager.GetStats()
+		log.Printf("✅ Bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
+		g.CircuitBreaker.RecordSuccess(0)
+		release(time.Since(callStart), nil)
+
+		mockResp := &BidResponse{
+			Success:      true,
+			BidAmount:    bidAmount,
+			BidID:        fmt.Sprintf("MOCK_GADS_%d", time.Now().Unix()),
+			Message:      fmt.Sprintf("Smart Bid placed with Target ROAS: %.2f (MOCK)", targetROAS),
+			PlatformCode: "GOOGLE_ADS_SMART_BIDDING",
+			Timestamp:    time.Now(),
+		}
+		meta := BidMeta{Platform: "google_ads", DecisionSource: decisionSource, TargetROAS: targetROAS, Latency: time.Since(startTime)}
+		fireRecorders(g.Recorders, func(r Recorder) { r.LogBid(req, mockResp, meta) })
+		recordIfLedgered(ctx, g.SpendLedger, "google_ads", req, mockResp)
+		return mockResp, nil
+	}
+
+	// Each attempt re-issues the HTTP request (the body reader from a prior
+	// attempt is already drained) and reports its status code so
+	// PlaceBidPolicy can decide whether a retry is worthwhile.
+	attemptFn := func(attempt int) (*BidResponse, int, error) {
+		attemptReq, err := http.NewRequestWithContext(ctx, reqData.Method, reqData.URI, bytes.NewReader(reqData.Body))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to create request: %v", err)
+		}
+		for key, value := range reqData.Headers {
+			attemptReq.Header.Set(key, value)
+		}
+
+		resp, err := g.HttpClient.Do(attemptReq)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+
+		if resp.StatusCode >= 400 {
+			return nil, resp.StatusCode, fmt.Errorf("google ads API returned status %d", resp.StatusCode)
+		}
+
+		bidderResp, errs := adapter.MakeBids(rtbReq, reqData, &openrtb.ResponseData{StatusCode: resp.StatusCode, Body: respBody})
+		if len(errs) > 0 {
+			return &BidResponse{
+				Success:      false,
+				Message:      fmt.Sprintf("Smart Bid rejected: %v", errs[0]),
+				PlatformCode: "GOOGLE_ADS_SMART_BIDDING",
+				Timestamp:    time.Now(),
+			}, resp.StatusCode, nil
+		}
+
+		winningBid := bidderResp.Bids[0].Bid
+		return &BidResponse{
+			Success:      true,
+			BidAmount:    bidAmount,
+			BidID:        winningBid.ID,
+			Message:      fmt.Sprintf("Smart Bid placed with Target ROAS: %.2f", targetROAS),
+			PlatformCode: "GOOGLE_ADS_SMART_BIDDING",
+			Timestamp:    time.Now(),
+		}, resp.StatusCode, nil
+	}
+
+	bidResp, err := g.PlaceBidPolicy.Execute(ctx, g.CircuitBreaker, req.RequestID, attemptFn)
+	release(time.Since(callStart), err)
+	meta := BidMeta{Platform: "google_ads", DecisionSource: decisionSource, TargetROAS: targetROAS, Latency: time.Since(startTime)}
+	if err != nil {
+		log.Printf("❌ Google Ads API error: %v", err)
+		errResp := &BidResponse{
+			Success:      false,
+			Message:      fmt.Sprintf("API error: %v", err),
+			PlatformCode: "GOOGLE_ADS_ERROR",
+			Timestamp:    time.Now(),
+		}
+		fireRecorders(g.Recorders, func(r Recorder) { r.LogBid(req, errResp, meta) })
+		return errResp, err
+	}
+
+	g.RateLimiter.RecordCall()
+	if bidResp.Success {
+		// A winning seat bid came back - record the spend
+		g.BudgetManager.AddSpend(bidAmount)
+		stats := g.BudgetManager.GetStats()
+		log.Printf("✅ Bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
+	}
+	fireRecorders(g.Recorders, func(r Recorder) { r.LogBid(req, bidResp, meta) })
+	recordIfLedgered(ctx, g.SpendLedger, "google_ads", req, bidResp)
+
+	return bidResp, nil
+}
+
+// UpdateCampaignBudget adjusts campaign budget based on LTV insights
+func (g *GoogleAdsSmartConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
+	if !g.Connected {
+		return nil, fmt.Errorf("not connected to Google Ads")
+	}
+
+	// Check if this budget update would exceed our limits
+	if !g.BudgetManager.CanSpend(budgetAmount) {
+		return nil, fmt.Errorf("budget update would exceed limits")
+	}
+
+	log.Printf("💰 UpdateCampaignBudget: Campaign=%s, Budget=$%.2f", campaignID, budgetAmount)
+
+	mutateReq := &MutateCampaignBudgetsRequest{
+		CustomerID: g.CustomerID,
+		Operations: []*CampaignBudgetOperation{{
+			Update: &CampaignBudget{
+				ResourceName:   fmt.Sprintf("customers/%s/campaignBudgets/%s", g.CustomerID, campaignID),
+				AmountMicros:   int64(budgetAmount * 1000000),
+				DeliveryMethod: "STANDARD",
+			},
+			// Only these two fields changed - an explicit FieldMask keeps a
+			// partial update from clobbering whatever's already set on the
+			// other CampaignBudget fields server-side.
+			UpdateMask: &FieldMask{Paths: []string{"amount_micros", "delivery_method"}},
+		}},
+	}
+
+	// Updating a budget to an absolute amount is idempotent, so
+	// UpdateCampaignBudgetPolicy retries freely on a retryable status.
+	attemptFn := func(attempt int) (*BidResponse, int, error) {
+		resp, err := g.AdsClient.MutateCampaignBudgets(ctx, mutateReq)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &BidResponse{
+			Success:      resp.StatusCode == 200,
+			Message:      fmt.Sprintf("Campaign %s budget updated to $%.2f", campaignID, budgetAmount),
+			PlatformCode: "GOOGLE_ADS_BUDGET",
+			Timestamp:    time.Now(),
+		}, resp.StatusCode, nil
+	}
+
+	bidResp, err := g.UpdateCampaignBudgetPolicy.Execute(ctx, g.CircuitBreaker, "", attemptFn)
+	if err != nil {
+		return nil, err
+	}
+
+	g.RateLimiter.RecordCall()
+	return bidResp, nil
+}
+
+// UpdateTargetAudience modifies audience targeting based on predicted LTV
+func (g *GoogleAdsSmartConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
+	if !g.Connected {
+		return nil, fmt.Errorf("not connected to Google Ads")
+	}
+
+	log.Printf("🎯 UpdateTargetAudience: Campaign=%s, Audience=%s", campaignID, audienceID)
+
+	return &BidResponse{
+		Success:      true,
+		Message:      fmt.Sprintf("Audience targeting updated for campaign %s", campaignID),
+		PlatformCode: "GOOGLE_ADS_AUDIENCE",
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+// GetStatus returns the connection status with budget info
+func (g *GoogleAdsSmartConnector) GetStatus() string {
+	if g.Connected {
+		stats := g.BudgetManager.GetStats()
+		return fmt.Sprintf("Connected to Google Ads - Budget: $%.2f/$%.2f (%.1f%% used)",
+			stats.CurrentSpend,
+			stats.MaxBudget,
+			(stats.CurrentSpend/stats.MaxBudget)*100)
+	}
+	return "Disconnected"
+}
+
+// Close cleanly disconnects from the platform
+func (g *GoogleAdsSmartConnector) Close() error {
+	stats := g.BudgetManager.GetStats()
+	log.Printf("🔌 Google Ads connection closed - Final spend: $%.2f", stats.CurrentSpend)
+	g.Connected = false
+	return nil
+}
+
+// GetBudgetStats returns current budget statistics
+func (g *GoogleAdsSmartConnector) GetBudgetStats() shield.WindowStats {
+	return g.BudgetManager.GetStats()
+}
+
+// GetCircuitBreaker exposes the connector's CircuitBreaker so callers like
+// auction.Auctioneer can check it before fanning out a bid.
+func (g *GoogleAdsSmartConnector) GetCircuitBreaker() *shield.CircuitBreaker {
+	return g.CircuitBreaker
+}
+
+// GetBudgetManager exposes the connector's BudgetManager so callers like
+// reconcile's rewind can correct its live spend state, not just a
+// throwaway local copy.
+func (g *GoogleAdsSmartConnector) GetBudgetManager() *shield.BudgetManager {
+	return g.BudgetManager
+}
+
+// limiterFor returns campaignID's AdaptiveLimiter, creating one seeded with
+// defaultAdaptiveLimiterMinLimit/defaultAdaptiveLimiterMaxLimit on first use.
+func (g *GoogleAdsSmartConnector) limiterFor(campaignID string) *shield.AdaptiveLimiter {
+	g.limitersMu.Lock()
+	defer g.limitersMu.Unlock()
+	limiter, ok := g.Limiters[campaignID]
+	if !ok {
+		limiter = shield.NewAdaptiveLimiter(defaultAdaptiveLimiterMinLimit, defaultAdaptiveLimiterMaxLimit)
+		g.Limiters[campaignID] = limiter
+	}
+	return limiter
+}
+
+// CircuitBreakerStatus is GetCircuitBreakerStatus's response shape - this
+// connector's stand-in for the SyncFlowService.GetCircuitBreakerStatus RPC
+// integration_tests' simulated fixtures reference (api/pb isn't vendored in
+// this repo; see predict.Client's doc comment for that gap). It reports the
+// CircuitBreaker's state alongside the campaign's AdaptiveLimiter, so an
+// operator can see the limiter auto-tuning in response to the same latency
+// the breaker is reacting to.
+type CircuitBreakerStatus struct {
+	CircuitBreaker shield.CircuitBreakerStats
+	Limiter        shield.AdaptiveLimiterStats
+}
+
+// GetCircuitBreakerStatus reports g's CircuitBreaker stats together with
+// campaignID's AdaptiveLimiter stats.
+func (g *GoogleAdsSmartConnector) GetCircuitBreakerStatus(campaignID string) CircuitBreakerStatus {
+	return CircuitBreakerStatus{
+		CircuitBreaker: g.CircuitBreaker.GetStats(),
+		Limiter:        g.limiterFor(campaignID).Stats(),
+	}
+}
@@ -1,176 +1,524 @@
-package connectors
-
-import (
-	"log"
-	"sync"
-)
-
-// HeuristicFallbackEngine provides safe bidding when SyncValue™ (AI brain) is unavailable
-// Uses platform-specific multipliers + historical LTV data to maintain 80% of AI optimization
-type HeuristicFallbackEngine struct {
-	mu sync.RWMutex
-
-	// LTV history tracking (for median calculation)
-	ltvHistory map[string][]float64 // platform -> list of observed LTVs
-
-	// Platform-specific multipliers (override defaults if needed)
-	platformMultipliers map[string]float64
-
-	// Configuration
-	maxHistorySize int
-	minLTVSamples  int // Minimum samples needed before using heuristic
-}
-
-// NewHeuristicFallbackEngine creates a new fallback engine with default configurations
-func NewHeuristicFallbackEngine() *HeuristicFallbackEngine {
-	return &HeuristicFallbackEngine{
-		ltvHistory: make(map[string][]float64),
-		platformMultipliers: map[string]float64{
-			"google_ads": 1.0,  // Direct LTV
-			"meta":       1.0,  // Direct LTV
-			"tradedesk":  1.0,  // Direct LTV
-			"amazon":     1.0,  // 1x (fallback is conservative, AI does 10x)
-			"x":          0.75, // 75% social discount
-			"linkedin":   1.2,  // 120% B2B premium
-			"tiktok":     1.5,  // 150% viral multiplier
-		},
-		maxHistorySize: 100,
-		minLTVSamples:  1, // Use any available history; fall back only when empty
-	}
-}
-
-// RecordLTV records an observed LTV value for a platform
-// Used to build history for median calculation
-func (hfe *HeuristicFallbackEngine) RecordLTV(platform string, ltv float64) {
-	hfe.mu.Lock()
-	defer hfe.mu.Unlock()
-
-	if _, exists := hfe.ltvHistory[platform]; !exists {
-		hfe.ltvHistory[platform] = []float64{}
-	}
-
-	history := hfe.ltvHistory[platform]
-	history = append(history, ltv)
-
-	// Keep only the most recent samples to avoid stale data
-	if len(history) > hfe.maxHistorySize {
-		history = history[len(history)-hfe.maxHistorySize:]
-	}
-
-	hfe.ltvHistory[platform] = history
-}
-
-// CalculateFallbackBid computes a safe bid when AI is unavailable
-// Formula: Bid = LTV_Median × Platform_Multiplier
-// This captures ~80% of AI optimization with zero gRPC dependency
-func (hfe *HeuristicFallbackEngine) CalculateFallbackBid(platform string, defaultLTV float64) float64 {
-	hfe.mu.RLock()
-	defer hfe.mu.RUnlock()
-
-	// Get historical median LTV for this platform
-	ltvMedian := hfe.calculateMedianLTV(platform, defaultLTV)
-
-	// Get platform multiplier
-	multiplier := hfe.platformMultipliers[platform]
-	if multiplier == 0 {
-		multiplier = 1.0 // Default to 1x if platform not configured
-	}
-
-	// Calculate fallback bid
-	fallbackBid := ltvMedian * multiplier
-
-	log.Printf(
-		"📊 Fallback Bid Calculated: Platform=%s, LTVMedian=%.2f, Multiplier=%.2f, FallbackBid=%.2f",
-		platform, ltvMedian, multiplier, fallbackBid,
-	)
-
-	return fallbackBid
-}
-
-// calculateMedianLTV computes the median LTV from historical data
-// Falls back to defaultLTV if insufficient history exists
-func (hfe *HeuristicFallbackEngine) calculateMedianLTV(platform string, defaultLTV float64) float64 {
-	history, exists := hfe.ltvHistory[platform]
-
-	// Not enough history, use default
-	if !exists || len(history) < hfe.minLTVSamples {
-		log.Printf("⚠️  Insufficient LTV history for %s (have %d, need %d), using default: %.2f",
-			platform, len(history), hfe.minLTVSamples, defaultLTV)
-		return defaultLTV
-	}
-
-	// Calculate median from history
-	return medianFloat64(history)
-}
-
-// SetPlatformMultiplier allows dynamic adjustment of platform multipliers
-func (hfe *HeuristicFallbackEngine) SetPlatformMultiplier(platform string, multiplier float64) {
-	hfe.mu.Lock()
-	defer hfe.mu.Unlock()
-	hfe.platformMultipliers[platform] = multiplier
-}
-
-// GetPlatformMultiplier returns the current multiplier for a platform
-func (hfe *HeuristicFallbackEngine) GetPlatformMultiplier(platform string) float64 {
-	hfe.mu.RLock()
-	defer hfe.mu.RUnlock()
-
-	if mult, exists := hfe.platformMultipliers[platform]; exists {
-		return mult
-	}
-	return 1.0
-}
-
-// GetLTVHistory returns a copy of the LTV history for a platform
-func (hfe *HeuristicFallbackEngine) GetLTVHistory(platform string) []float64 {
-	hfe.mu.RLock()
-	defer hfe.mu.RUnlock()
-
-	if history, exists := hfe.ltvHistory[platform]; exists {
-		// Return copy to prevent external modification
-		result := make([]float64, len(history))
-		copy(result, history)
-		return result
-	}
-
-	return []float64{}
-}
-
-// ClearHistory removes all recorded LTV history (for testing)
-func (hfe *HeuristicFallbackEngine) ClearHistory() {
-	hfe.mu.Lock()
-	defer hfe.mu.Unlock()
-	hfe.ltvHistory = make(map[string][]float64)
-}
-
-// medianFloat64 calculates the median of a float64 slice
-// Mutates the input slice (sorts it)
-func medianFloat64(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-
-	// Simple bubble sort for small datasets
-	for i := 0; i < len(values); i++ {
-		for j := i + 1; j < len(values); j++ {
-			if values[j] < values[i] {
-				values[i], values[j] = values[j], values[i]
-			}
-		}
-	}
-
-	mid := len(values) / 2
-	if len(values)%2 == 1 {
-		return values[mid]
-	}
-
-	// Even number of elements - return average of middle two
-	return (values[mid-1] + values[mid]) / 2.0
-}
-
-// EstimateAIOptimizationRecovery returns what percentage of AI optimization
-// the fallback can achieve (used for dashboard reporting)
-// Fallback captures ~80% of AI gains
-func (hfe *HeuristicFallbackEngine) EstimateAIOptimizationRecovery() float64 {
-	return 0.80 // 80% of AI optimization preserved during outage
-}
+package connectors
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// defaultTrackedQuantiles are the quantiles every platform's LTV stream
+// tracks by default: p50 drives CalculateFallbackBid, p25/p75/p90 are
+// exposed via GetQuantile for dashboards/alerting that want the spread,
+// not just the center.
+var defaultTrackedQuantiles = []float64{0.25, 0.5, 0.75, 0.9}
+
+// defaultShrinkagePrior is the default James-Stein/empirical-Bayes prior
+// weight k used by calculateMedianLTV: a platform's own median is
+// blended with the cross-platform median, weighted n-to-k where n is
+// the platform's sample count, so a platform with only a handful of
+// samples isn't trusted as much as its raw median suggests.
+const defaultShrinkagePrior = 20.0
+
+// tukeyFenceMultiplier is the standard Tukey's-fence multiplier (1.5x
+// IQR beyond p25/p75) RecordLTV uses to winsorize an incoming LTV before
+// it reaches the quantile estimator, so a single misreported value (e.g.
+// a $10k conversion on a platform that usually sees $50-100 ones) can't
+// poison CalculateFallbackBid.
+const tukeyFenceMultiplier = 1.5
+
+// tukeyFenceMinSamples is how many samples a platform's p25/p75
+// estimators need before their spread is trusted enough to fence
+// against - below that, a couple of legitimately varied early samples
+// could look like outliers of each other.
+const tukeyFenceMinSamples = 5
+
+// HeuristicFallbackEngine provides safe bidding when SyncValue™ (AI brain) is unavailable
+// Uses platform-specific multipliers + historical LTV data to maintain 80% of AI optimization
+type HeuristicFallbackEngine struct {
+	mu sync.RWMutex
+
+	// LTV history tracking, bounded to maxHistorySize. No longer read by
+	// CalculateFallbackBid (see quantiles below) - kept for GetLTVHistory,
+	// which callers use for debugging/dashboards.
+	ltvHistory map[string][]float64 // platform -> list of observed LTVs
+
+	// quantiles holds one p2Quantile per (platform, tracked p), updated in
+	// O(1) per RecordLTV call instead of re-sorting the full history on
+	// every CalculateFallbackBid like medianFloat64 used to.
+	quantiles map[string]map[float64]*p2Quantile
+
+	// rejectedCount tracks, per platform, how many incoming LTVs fell
+	// outside that platform's Tukey fences and were winsorized before
+	// being folded into its quantile estimator.
+	rejectedCount map[string]int
+
+	// shrinkagePrior is k in calculateMedianLTV's James-Stein-style blend
+	// ((n*platformMedian)+(k*globalMedian))/(n+k). See SetShrinkagePrior.
+	shrinkagePrior float64
+
+	// Platform-specific multipliers (override defaults if needed)
+	platformMultipliers map[string]float64
+
+	// Configuration
+	maxHistorySize int
+	minLTVSamples  int // Minimum samples needed before using heuristic
+}
+
+// NewHeuristicFallbackEngine creates a new fallback engine with default configurations
+func NewHeuristicFallbackEngine() *HeuristicFallbackEngine {
+	return &HeuristicFallbackEngine{
+		ltvHistory:     make(map[string][]float64),
+		quantiles:      make(map[string]map[float64]*p2Quantile),
+		rejectedCount:  make(map[string]int),
+		shrinkagePrior: defaultShrinkagePrior,
+		platformMultipliers: map[string]float64{
+			"google_ads": 1.0,  // Direct LTV
+			"meta":       1.0,  // Direct LTV
+			"tradedesk":  1.0,  // Direct LTV
+			"amazon":     1.0,  // 1x (fallback is conservative, AI does 10x)
+			"x":          0.75, // 75% social discount
+			"linkedin":   1.2,  // 120% B2B premium
+			"tiktok":     1.5,  // 150% viral multiplier
+		},
+		maxHistorySize: 100,
+		minLTVSamples:  1, // Use any available history; fall back only when empty
+	}
+}
+
+// RecordLTV records an observed LTV value for a platform
+// Used to build history for median calculation
+func (hfe *HeuristicFallbackEngine) RecordLTV(platform string, ltv float64) {
+	hfe.mu.Lock()
+	defer hfe.mu.Unlock()
+
+	if _, exists := hfe.ltvHistory[platform]; !exists {
+		hfe.ltvHistory[platform] = []float64{}
+	}
+
+	history := hfe.ltvHistory[platform]
+	history = append(history, ltv)
+
+	// Keep only the most recent samples to avoid stale data
+	if len(history) > hfe.maxHistorySize {
+		history = history[len(history)-hfe.maxHistorySize:]
+	}
+
+	hfe.ltvHistory[platform] = history
+
+	platformQuantiles, exists := hfe.quantiles[platform]
+	if !exists {
+		platformQuantiles = make(map[float64]*p2Quantile, len(defaultTrackedQuantiles))
+		for _, p := range defaultTrackedQuantiles {
+			platformQuantiles[p] = newP2Quantile(p)
+		}
+		hfe.quantiles[platform] = platformQuantiles
+	}
+
+	observed := hfe.winsorize(platform, platformQuantiles, ltv)
+	for _, q := range platformQuantiles {
+		q.observe(observed)
+	}
+}
+
+// winsorize clamps ltv to platform's Tukey fences (tukeyFenceMultiplier
+// times the IQR beyond p25/p75) before it reaches the quantile
+// estimator. The raw value passed to RecordLTV is still kept in
+// ltvHistory unchanged, since that's a debugging trail (see the
+// ltvHistory field comment), not an input to the bid math. Must be
+// called with hfe.mu held.
+func (hfe *HeuristicFallbackEngine) winsorize(platform string, platformQuantiles map[float64]*p2Quantile, ltv float64) float64 {
+	p25, ok25 := platformQuantiles[0.25].value()
+	p75, ok75 := platformQuantiles[0.75].value()
+	if !ok25 || !ok75 || platformQuantiles[0.25].count < tukeyFenceMinSamples {
+		return ltv
+	}
+
+	iqr := p75 - p25
+	if iqr <= 0 {
+		return ltv
+	}
+
+	lower := p25 - tukeyFenceMultiplier*iqr
+	upper := p75 + tukeyFenceMultiplier*iqr
+	if ltv >= lower && ltv <= upper {
+		return ltv
+	}
+
+	clamped := lower
+	if ltv > upper {
+		clamped = upper
+	}
+	hfe.rejectedCount[platform]++
+	log.Printf("⚠️  Winsorizing outlier LTV for %s: %.2f outside [%.2f, %.2f], clamped to %.2f",
+		platform, ltv, lower, upper, clamped)
+	return clamped
+}
+
+// CalculateFallbackBid computes a safe bid when AI is unavailable
+// Formula: Bid = LTV_Median × Platform_Multiplier
+// This captures ~80% of AI optimization with zero gRPC dependency
+func (hfe *HeuristicFallbackEngine) CalculateFallbackBid(platform string, defaultLTV float64) float64 {
+	hfe.mu.RLock()
+	defer hfe.mu.RUnlock()
+
+	// Get historical median LTV for this platform
+	ltvMedian := hfe.calculateMedianLTV(platform, defaultLTV)
+
+	// Get platform multiplier
+	multiplier := hfe.platformMultipliers[platform]
+	if multiplier == 0 {
+		multiplier = 1.0 // Default to 1x if platform not configured
+	}
+
+	// Calculate fallback bid
+	fallbackBid := ltvMedian * multiplier
+
+	log.Printf(
+		"📊 Fallback Bid Calculated: Platform=%s, LTVMedian=%.2f, Multiplier=%.2f, FallbackBid=%.2f",
+		platform, ltvMedian, multiplier, fallbackBid,
+	)
+
+	return fallbackBid
+}
+
+// calculateMedianLTV returns the platform's current p50 LTV estimate from
+// its p2Quantile, shrunk toward the cross-platform median via a
+// James-Stein-style blend (see shrinkagePrior) so a platform with only a
+// handful of samples isn't trusted as much as its raw median suggests.
+// Falls back to defaultLTV if insufficient history exists at all. Must
+// be called with hfe.mu held.
+func (hfe *HeuristicFallbackEngine) calculateMedianLTV(platform string, defaultLTV float64) float64 {
+	count := hfe.sampleCount(platform)
+
+	// Not enough history, use default
+	if count < hfe.minLTVSamples {
+		log.Printf("⚠️  Insufficient LTV history for %s (have %d, need %d), using default: %.2f",
+			platform, count, hfe.minLTVSamples, defaultLTV)
+		return defaultLTV
+	}
+
+	median, ok := hfe.quantiles[platform][0.5].value()
+	if !ok {
+		return defaultLTV
+	}
+
+	n := float64(count)
+	k := hfe.shrinkagePrior
+	return (n*median + k*hfe.globalMedian()) / (n + k)
+}
+
+// globalMedian returns the sample-count-weighted mean of every tracked
+// platform's current median - the cross-platform center
+// calculateMedianLTV shrinks a platform's own median toward. A platform
+// that is the only one this engine has ever seen shrinks toward itself,
+// i.e. shrinkage is a no-op. Must be called with hfe.mu held.
+func (hfe *HeuristicFallbackEngine) globalMedian() float64 {
+	var weightedSum float64
+	var totalCount int
+	for _, platformQuantiles := range hfe.quantiles {
+		median, ok := platformQuantiles[0.5].value()
+		if !ok {
+			continue
+		}
+		count := platformQuantiles[0.5].count
+		weightedSum += median * float64(count)
+		totalCount += count
+	}
+	if totalCount == 0 {
+		return 0
+	}
+	return weightedSum / float64(totalCount)
+}
+
+// SetShrinkagePrior adjusts k, the prior weight in calculateMedianLTV's
+// blend toward the cross-platform median: higher k pulls a low-sample
+// platform harder toward the global median, k=0 disables shrinkage
+// entirely. Default is defaultShrinkagePrior.
+func (hfe *HeuristicFallbackEngine) SetShrinkagePrior(k float64) {
+	hfe.mu.Lock()
+	defer hfe.mu.Unlock()
+	hfe.shrinkagePrior = k
+}
+
+// RejectedCount returns how many of platform's incoming LTVs have been
+// winsorized for falling outside its Tukey fences.
+func (hfe *HeuristicFallbackEngine) RejectedCount(platform string) int {
+	hfe.mu.RLock()
+	defer hfe.mu.RUnlock()
+	return hfe.rejectedCount[platform]
+}
+
+// sampleCount returns how many LTV samples have been observed for
+// platform, used to decide whether there's enough history to trust a
+// quantile estimate. Must be called with hfe.mu held.
+func (hfe *HeuristicFallbackEngine) sampleCount(platform string) int {
+	platformQuantiles, exists := hfe.quantiles[platform]
+	if !exists {
+		return 0
+	}
+	// Every tracked quantile for a platform observes the same samples, so
+	// any one of them reports the platform's count.
+	for _, q := range platformQuantiles {
+		return q.count
+	}
+	return 0
+}
+
+// GetQuantile returns the current streaming estimate of the p quantile
+// (e.g. 0.5 for median, 0.9 for p90) of platform's observed LTVs. p must
+// be one of defaultTrackedQuantiles; ok is false if p isn't tracked or no
+// samples have been recorded yet.
+func (hfe *HeuristicFallbackEngine) GetQuantile(platform string, p float64) (float64, bool) {
+	hfe.mu.RLock()
+	defer hfe.mu.RUnlock()
+
+	platformQuantiles, exists := hfe.quantiles[platform]
+	if !exists {
+		return 0, false
+	}
+	q, tracked := platformQuantiles[p]
+	if !tracked {
+		return 0, false
+	}
+	return q.value()
+}
+
+// SetPlatformMultiplier allows dynamic adjustment of platform multipliers
+func (hfe *HeuristicFallbackEngine) SetPlatformMultiplier(platform string, multiplier float64) {
+	hfe.mu.Lock()
+	defer hfe.mu.Unlock()
+	hfe.platformMultipliers[platform] = multiplier
+}
+
+// GetPlatformMultiplier returns the current multiplier for a platform
+func (hfe *HeuristicFallbackEngine) GetPlatformMultiplier(platform string) float64 {
+	hfe.mu.RLock()
+	defer hfe.mu.RUnlock()
+
+	if mult, exists := hfe.platformMultipliers[platform]; exists {
+		return mult
+	}
+	return 1.0
+}
+
+// GetLTVHistory returns a copy of the LTV history for a platform
+func (hfe *HeuristicFallbackEngine) GetLTVHistory(platform string) []float64 {
+	hfe.mu.RLock()
+	defer hfe.mu.RUnlock()
+
+	if history, exists := hfe.ltvHistory[platform]; exists {
+		// Return copy to prevent external modification
+		result := make([]float64, len(history))
+		copy(result, history)
+		return result
+	}
+
+	return []float64{}
+}
+
+// ClearHistory removes all recorded LTV history (for testing)
+func (hfe *HeuristicFallbackEngine) ClearHistory() {
+	hfe.mu.Lock()
+	defer hfe.mu.Unlock()
+	hfe.ltvHistory = make(map[string][]float64)
+	hfe.quantiles = make(map[string]map[float64]*p2Quantile)
+	hfe.rejectedCount = make(map[string]int)
+}
+
+// PlatformFallbackStats summarizes one platform's current fallback-engine
+// state, as returned by Stats and served by ServeStats, so operators can
+// see how much to trust the heuristic - and tune SetShrinkagePrior or a
+// platform multiplier - while SyncValue™ is down.
+type PlatformFallbackStats struct {
+	Platform        string  `json:"platform"`
+	SampleCount     int     `json:"sample_count"`
+	Median          float64 `json:"median"`
+	P25             float64 `json:"p25"`
+	P75             float64 `json:"p75"`
+	IQR             float64 `json:"iqr"`
+	RejectedSamples int     `json:"rejected_samples"`
+}
+
+// Stats returns a PlatformFallbackStats snapshot for every platform this
+// engine has recorded an LTV for, sorted by platform name.
+func (hfe *HeuristicFallbackEngine) Stats() []PlatformFallbackStats {
+	hfe.mu.RLock()
+	defer hfe.mu.RUnlock()
+
+	stats := make([]PlatformFallbackStats, 0, len(hfe.quantiles))
+	for platform, platformQuantiles := range hfe.quantiles {
+		median, _ := platformQuantiles[0.5].value()
+		p25, _ := platformQuantiles[0.25].value()
+		p75, _ := platformQuantiles[0.75].value()
+		stats = append(stats, PlatformFallbackStats{
+			Platform:        platform,
+			SampleCount:     platformQuantiles[0.5].count,
+			Median:          median,
+			P25:             p25,
+			P75:             p75,
+			IQR:             p75 - p25,
+			RejectedSamples: hfe.rejectedCount[platform],
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Platform < stats[j].Platform })
+	return stats
+}
+
+// ServeStats handles GET /connectors/fallback/stats, returning Stats() as
+// JSON.
+func (hfe *HeuristicFallbackEngine) ServeStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hfe.Stats())
+}
+
+// p2Quantile is a streaming estimator for a single quantile p using the
+// P² (piecewise-parabolic) algorithm (Jain & Chlamtac, 1985): it updates
+// in O(1) per sample and O(1) memory (five markers) rather than keeping
+// the full sample history sorted on every read, the way medianFloat64
+// used to. Until 5 samples have been observed there aren't enough
+// markers to interpolate, so value() falls back to exact linear
+// interpolation over the buffered samples.
+type p2Quantile struct {
+	p   float64
+	buf []float64 // buffered samples until the 5 markers can be seeded
+
+	heights    [5]float64 // q_i: marker heights (the estimate itself is heights[2])
+	positions  [5]float64 // n_i: current integer marker positions
+	desired    [5]float64 // n'_i: desired marker positions
+	increments [5]float64 // d_i: how much n'_i advances per sample
+
+	count int
+}
+
+func newP2Quantile(p float64) *p2Quantile {
+	return &p2Quantile{
+		p:          p,
+		increments: [5]float64{0, p / 2, p, (1 + p) / 2, 1},
+	}
+}
+
+// observe folds x into the estimator. The first 5 samples just seed the
+// markers from their sorted order; every sample after that runs the full
+// P² marker-adjustment step.
+func (q *p2Quantile) observe(x float64) {
+	q.count++
+	if q.count <= 5 {
+		q.buf = append(q.buf, x)
+		if q.count == 5 {
+			sort.Float64s(q.buf)
+			for i := 0; i < 5; i++ {
+				q.heights[i] = q.buf[i]
+				q.positions[i] = float64(i + 1)
+			}
+			q.desired = [5]float64{1, 1 + 2*q.p, 1 + 4*q.p, 3 + 2*q.p, 5}
+			q.buf = nil
+		}
+		return
+	}
+
+	k := q.findCell(x)
+
+	for i := k + 1; i < 5; i++ {
+		q.positions[i]++
+	}
+	for i := 0; i < 5; i++ {
+		q.desired[i] += q.increments[i]
+	}
+
+	for i := 1; i <= 3; i++ {
+		d := q.desired[i] - q.positions[i]
+		if (d >= 1 && q.positions[i+1]-q.positions[i] > 1) ||
+			(d <= -1 && q.positions[i-1]-q.positions[i] < -1) {
+			sign := 1.0
+			if d < 0 {
+				sign = -1.0
+			}
+			adjusted := q.parabolic(i, sign)
+			if adjusted > q.heights[i-1] && adjusted < q.heights[i+1] {
+				q.heights[i] = adjusted
+			} else {
+				q.heights[i] = q.linear(i, sign)
+			}
+			q.positions[i] += sign
+		}
+	}
+}
+
+// findCell locates the marker interval [heights[k], heights[k+1]) that x
+// falls into, widening the outer markers if x lands outside the range
+// seen so far.
+func (q *p2Quantile) findCell(x float64) int {
+	if x < q.heights[0] {
+		q.heights[0] = x
+		return 0
+	}
+	if x >= q.heights[4] {
+		q.heights[4] = x
+		return 3
+	}
+	for i := 0; i < 4; i++ {
+		if q.heights[i] <= x && x < q.heights[i+1] {
+			return i
+		}
+	}
+	return 3
+}
+
+// parabolic computes marker i's candidate new height via the P²
+// piecewise-parabolic formula.
+func (q *p2Quantile) parabolic(i int, sign float64) float64 {
+	return q.heights[i] + sign/(q.positions[i+1]-q.positions[i-1])*
+		((q.positions[i]-q.positions[i-1]+sign)*(q.heights[i+1]-q.heights[i])/(q.positions[i+1]-q.positions[i])+
+			(q.positions[i+1]-q.positions[i]-sign)*(q.heights[i]-q.heights[i-1])/(q.positions[i]-q.positions[i-1]))
+}
+
+// linear is the fallback used when the parabolic estimate would leave
+// marker i outside (heights[i-1], heights[i+1]).
+func (q *p2Quantile) linear(i int, sign float64) float64 {
+	j := i + int(sign)
+	return q.heights[i] + sign*(q.heights[j]-q.heights[i])/(q.positions[j]-q.positions[i])
+}
+
+// value returns the current estimate of the p quantile, or ok=false if no
+// samples have been observed yet.
+func (q *p2Quantile) value() (float64, bool) {
+	if q.count == 0 {
+		return 0, false
+	}
+	if q.count < 5 {
+		sorted := append([]float64(nil), q.buf...)
+		sort.Float64s(sorted)
+		return exactQuantile(sorted, q.p), true
+	}
+	return q.heights[2], true
+}
+
+// exactQuantile computes p's quantile of sorted (already ascending) via
+// linear interpolation between closest ranks, used only during a
+// p2Quantile's warmup before it has the 5 samples it needs to seed its
+// markers.
+func exactQuantile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	idx := p * float64(len(sorted)-1)
+	lower := int(idx)
+	frac := idx - float64(lower)
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + frac*(sorted[lower+1]-sorted[lower])
+}
+
+// EstimateAIOptimizationRecovery returns what percentage of AI optimization
+// the fallback can achieve (used for dashboard reporting)
+// Fallback captures ~80% of AI gains
+func (hfe *HeuristicFallbackEngine) EstimateAIOptimizationRecovery() float64 {
+	return 0.80 // 80% of AI optimization preserved during outage
+}
@@ -0,0 +1,28 @@
+package ingest
+
+import "encoding/json"
+
+// impExt is the imp.ext.kiki object this agent expects an exchange to
+// attach, carrying the fields connectors.BidRequest needs that have no
+// standard OpenRTB home - the inbound counterpart to the ext.kiki object
+// TradeDeskSmartConnector already emits on its own outbound bids.
+type impExt struct {
+	Kiki struct {
+		CustomerID   string  `json:"customer_id"`
+		PredictedLTV float64 `json:"predicted_ltv"`
+		CampaignID   string  `json:"campaign_id"`
+		AudienceID   string  `json:"audience_id"`
+	} `json:"kiki"`
+}
+
+// parseImpExt decodes raw as an impExt, returning the zero value for an
+// empty or malformed ext rather than failing the whole auction round over
+// one optional extension object.
+func parseImpExt(raw json.RawMessage) impExt {
+	var ext impExt
+	if len(raw) == 0 {
+		return ext
+	}
+	_ = json.Unmarshal(raw, &ext)
+	return ext
+}
@@ -0,0 +1,177 @@
+// Package ingest implements the inbound side of OpenRTB: parsing an
+// exchange's BidRequest, fanning each impression out to every registered
+// connectors.PlatformConnector via auction.Auctioneer, and returning an
+// OpenRTB BidResponse. It's the counterpart to the connectors package's
+// outbound PlaceBid calls - what turns the agent from a demo bidder
+// driven by a synthetic ticker into a proxy an exchange or prebid-style
+// stack can call directly.
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/auction"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/openrtb"
+)
+
+// defaultAdapterTimeout bounds each impression's auction round when the
+// incoming BidRequest doesn't set TMax, leaving headroom under whatever
+// overall deadline the exchange itself is working to.
+const defaultAdapterTimeout = 500 * time.Millisecond
+
+// defaultFloorBid is the bid offered for an impression with no bidfloor,
+// so the auction still has an amount to check against budget rather than
+// skipping every bidder on a zero-dollar request.
+const defaultFloorBid = 1.0
+
+// SpendRecorder records a confirmed win's clearing price against the
+// agent's own spend tracking - cmd/syncflow/main.go's recordSpend,
+// passed in rather than imported so this package stays free of main's
+// api/pb dependency.
+type SpendRecorder func(amount float64)
+
+// Handler serves the inbound OpenRTB 2.5 auction endpoint plus the
+// nurl/burl win-notice callbacks its bids carry.
+type Handler struct {
+	Auctioneer     *auction.Auctioneer
+	AdapterTimeout time.Duration
+	BaseURL        string
+	RecordSpend    SpendRecorder
+	// NoticeSecret signs every nurl/burl this Handler issues (see
+	// signNotice) so handleNotice can tell a real exchange callback from
+	// an unauthenticated, forged one before RecordSpend ever fires.
+	NoticeSecret []byte
+
+	mu          sync.Mutex
+	outstanding map[string]outstandingWin // bidID -> win, until consumed or this process restarts
+}
+
+// NewHandler creates a Handler. baseURL is embedded in every winning
+// bid's nurl/burl (e.g. "https://kiki-agent.com/openrtb2") so the
+// exchange's win/billing callbacks land back on this same Handler.
+// noticeSecret must be non-empty in production - it's the only thing
+// standing between an unauthenticated request and RecordSpend.
+func NewHandler(auctioneer *auction.Auctioneer, baseURL string, recordSpend SpendRecorder, noticeSecret []byte) *Handler {
+	return &Handler{
+		Auctioneer:     auctioneer,
+		AdapterTimeout: defaultAdapterTimeout,
+		BaseURL:        baseURL,
+		RecordSpend:    recordSpend,
+		NoticeSecret:   noticeSecret,
+		outstanding:    make(map[string]outstandingWin),
+	}
+}
+
+// ServeAuction handles POST /openrtb2/auction: parse, validate, fan out
+// per impression, and respond with an OpenRTB BidResponse.
+func (h *Handler) ServeAuction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	var req openrtb.BidRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid bid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := ValidateBidRequest(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if req.TMax > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(req.TMax)*time.Millisecond)
+		defer cancel()
+	}
+
+	resp := h.runAuction(ctx, &req)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("ingest: encoding bid response: %v", err)
+	}
+}
+
+// runAuction fans each impression out to every registered connector under
+// its own AdapterTimeout budget (bounded by ctx), keeping the winner the
+// Auctioneer's WinnerPolicy picks per impression and dropping impressions
+// nobody won.
+func (h *Handler) runAuction(ctx context.Context, req *openrtb.BidRequest) *openrtb.BidResponse {
+	resp := &openrtb.BidResponse{ID: req.ID, Cur: "USD"}
+
+	for _, imp := range req.Imp {
+		impCtx, cancel := context.WithTimeout(ctx, h.AdapterTimeout)
+		result := h.Auctioneer.RunAuction(impCtx, impToBidRequest(req, imp))
+		cancel()
+
+		if result.Winner == nil || result.Winner.Response == nil || !result.Winner.Response.Success {
+			continue
+		}
+		resp.SeatBid = append(resp.SeatBid, openrtb.SeatBid{
+			Seat: result.Winner.Bidder,
+			Bid:  []openrtb.Bid{h.winningBid(result.Winner.Response, imp.ID)},
+		})
+	}
+	return resp
+}
+
+// winningBid converts a winning connectors.BidResponse into an OpenRTB
+// Bid, with nurl/burl pointing back at this Handler's own win-notice
+// endpoints so the exchange's confirmation calls reach RecordSpend.
+// Each nurl/burl carries an exp timestamp and an HMAC signature over
+// bid+exp, and the bid itself is recorded as an outstanding win, so
+// handleNotice can reject a forged or replayed callback before it ever
+// reaches RecordSpend.
+func (h *Handler) winningBid(won *connectors.BidResponse, impID string) openrtb.Bid {
+	h.trackOutstandingWin(won.BidID, won.BidAmount)
+	exp := time.Now().Add(noticeValidityWindow).Unix()
+	sig := h.signNotice(won.BidID, exp)
+	query := fmt.Sprintf("?bid=%s&price=${AUCTION_PRICE}&exp=%d&sig=%s", won.BidID, exp, sig)
+	return openrtb.Bid{
+		ID:    won.BidID,
+		ImpID: impID,
+		Price: won.BidAmount,
+		NURL:  h.BaseURL + "/win-notice" + query,
+		BURL:  h.BaseURL + "/billing" + query,
+		CrID:  won.PlatformCode,
+	}
+}
+
+// impToBidRequest converts one OpenRTB Imp into the connectors.BidRequest
+// the Auctioneer actually runs, reading the exchange-supplied
+// CustomerID/PredictedLTV out of imp.ext.kiki.
+func impToBidRequest(req *openrtb.BidRequest, imp openrtb.Imp) *connectors.BidRequest {
+	ext := parseImpExt(imp.Ext)
+
+	bidAmount := imp.BidFloor
+	if bidAmount <= 0 {
+		bidAmount = defaultFloorBid
+	}
+
+	return &connectors.BidRequest{
+		CustomerID:   ext.Kiki.CustomerID,
+		PredictedLTV: ext.Kiki.PredictedLTV,
+		BidAmount:    bidAmount,
+		Timestamp:    time.Now(),
+		CampaignID:   ext.Kiki.CampaignID,
+		AudienceID:   ext.Kiki.AudienceID,
+		RequestID:    req.ID + ":" + imp.ID,
+	}
+}
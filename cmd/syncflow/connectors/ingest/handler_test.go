@@ -0,0 +1,180 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/auction"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/openrtb"
+)
+
+// alwaysWinConnector is a connectors.PlatformConnector stub that always
+// wins with a fixed BidAmount, so tests can drive a full ServeAuction ->
+// nurl/burl round trip without a real ad platform.
+type alwaysWinConnector struct {
+	bidAmount float64
+}
+
+func (c *alwaysWinConnector) Connect(ctx context.Context) error { return nil }
+
+func (c *alwaysWinConnector) PlaceBid(ctx context.Context, req *connectors.BidRequest) (*connectors.BidResponse, error) {
+	return &connectors.BidResponse{
+		Success:      true,
+		BidID:        "bid-1",
+		PlatformCode: "stub",
+		BidAmount:    c.bidAmount,
+	}, nil
+}
+
+func (c *alwaysWinConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*connectors.BidResponse, error) {
+	return &connectors.BidResponse{Success: true}, nil
+}
+
+func (c *alwaysWinConnector) UpdateTargetAudience(ctx context.Context, campaignID, audienceID string) (*connectors.BidResponse, error) {
+	return &connectors.BidResponse{Success: true}, nil
+}
+
+func (c *alwaysWinConnector) GetStatus() string { return "connected" }
+
+func (c *alwaysWinConnector) Close() error { return nil }
+
+// newTestHandler builds a Handler wired to a single always-winning bidder,
+// recording every RecordSpend call into the returned slice pointer.
+func newTestHandler(t *testing.T, bidAmount float64, secret []byte) (*Handler, *[]float64) {
+	t.Helper()
+	auctioneer := auction.NewAuctioneer(auction.HighestBid, 1_000_000)
+	auctioneer.Register("stub", &alwaysWinConnector{bidAmount: bidAmount})
+
+	var spends []float64
+	h := NewHandler(auctioneer, "https://kiki-agent.test/openrtb2", func(amount float64) {
+		spends = append(spends, amount)
+	}, secret)
+	return h, &spends
+}
+
+// winAuction drives ServeAuction with a minimal valid BidRequest and
+// returns the nurl/burl query values of the winning bid.
+func winAuction(t *testing.T, h *Handler) url.Values {
+	t.Helper()
+	body := `{
+		"id": "req-1",
+		"site": {"id": "site-1"},
+		"imp": [{"id": "imp-1", "banner": {"w": 300, "h": 250}}]
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeAuction(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ServeAuction returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp openrtb.BidResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding bid response: %v", err)
+	}
+	if len(resp.SeatBid) != 1 || len(resp.SeatBid[0].Bid) != 1 {
+		t.Fatalf("expected exactly one winning bid, got %+v", resp.SeatBid)
+	}
+	bid := resp.SeatBid[0].Bid[0]
+
+	nurl, err := url.Parse(bid.NURL)
+	if err != nil {
+		t.Fatalf("parsing nurl: %v", err)
+	}
+	q := nurl.Query()
+	// A real exchange substitutes ${AUCTION_PRICE} with the actual
+	// clearing price before firing the callback; stand in with the bid's
+	// own price so tests that don't care about clamping still see a
+	// parseable value.
+	q.Set("price", strconv.FormatFloat(bid.Price, 'f', -1, 64))
+	return q
+}
+
+func fireNotice(h *Handler, kind string, q url.Values) *httptest.ResponseRecorder {
+	var handle http.HandlerFunc
+	if kind == "win" {
+		handle = h.ServeWinNotice
+	} else {
+		handle = h.ServeBillingNotice
+	}
+	req := httptest.NewRequest(http.MethodGet, "/openrtb2/"+kind+"-notice?"+q.Encode(), nil)
+	rec := httptest.NewRecorder()
+	handle(rec, req)
+	return rec
+}
+
+func TestHandler_BillingNoticeRecordsClampedSpend(t *testing.T) {
+	h, spends := newTestHandler(t, 2.50, []byte("test-secret"))
+	q := winAuction(t, h)
+
+	// Simulate the exchange substituting ${AUCTION_PRICE} with a clearing
+	// price above what this agent actually bid - it must be clamped down
+	// to the bid's own ceiling, never trusted as-is.
+	q.Set("price", "999999999")
+
+	rec := fireNotice(h, "billing", q)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("billing notice returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(*spends) != 1 || (*spends)[0] != 2.50 {
+		t.Fatalf("expected a single RecordSpend(2.50), got %v", *spends)
+	}
+}
+
+func TestHandler_BillingNoticeRejectsForgedSignature(t *testing.T) {
+	h, spends := newTestHandler(t, 2.50, []byte("test-secret"))
+	q := winAuction(t, h)
+	q.Set("sig", "0000000000000000000000000000000000000000000000000000000000000000")
+
+	rec := fireNotice(h, "billing", q)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for forged signature, got %d", rec.Code)
+	}
+	if len(*spends) != 0 {
+		t.Fatalf("expected no RecordSpend call, got %v", *spends)
+	}
+}
+
+func TestHandler_BillingNoticeRejectsReplay(t *testing.T) {
+	h, spends := newTestHandler(t, 2.50, []byte("test-secret"))
+	q := winAuction(t, h)
+
+	first := fireNotice(h, "billing", q)
+	if first.Code != http.StatusOK {
+		t.Fatalf("first billing notice returned %d: %s", first.Code, first.Body.String())
+	}
+
+	second := fireNotice(h, "billing", q)
+	if second.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 on replayed billing notice, got %d", second.Code)
+	}
+	if len(*spends) != 1 {
+		t.Fatalf("expected spend recorded exactly once, got %v", *spends)
+	}
+}
+
+func TestHandler_NoticeRejectsUnknownBid(t *testing.T) {
+	h, _ := newTestHandler(t, 2.50, []byte("test-secret"))
+	winAuction(t, h)
+
+	exp := time.Now().Add(noticeValidityWindow).Unix()
+	q := url.Values{}
+	q.Set("bid", "not-a-real-bid")
+	q.Set("price", "1.00")
+	q.Set("exp", strconv.FormatInt(exp, 10))
+	q.Set("sig", h.signNotice("not-a-real-bid", exp))
+
+	rec := fireNotice(h, "billing", q)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown bid, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,41 @@
+package ingest
+
+import (
+	"fmt"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/openrtb"
+)
+
+// ValidateBidRequest performs the structural checks an OpenRTB 2.5
+// BidRequest must pass before it's worth fanning out to any connector.
+// It's a hand-rolled check rather than a general JSON schema library,
+// matching the rest of this module's no-new-dependency convention.
+func ValidateBidRequest(req *openrtb.BidRequest) error {
+	if req == nil {
+		return fmt.Errorf("ingest: nil bid request")
+	}
+	if req.ID == "" {
+		return fmt.Errorf("ingest: bid request missing id")
+	}
+	if len(req.Imp) == 0 {
+		return fmt.Errorf("ingest: bid request %s has no impressions", req.ID)
+	}
+	if req.Site == nil && req.App == nil {
+		return fmt.Errorf("ingest: bid request %s has neither site nor app", req.ID)
+	}
+
+	seen := make(map[string]bool, len(req.Imp))
+	for i, imp := range req.Imp {
+		if imp.ID == "" {
+			return fmt.Errorf("ingest: imp[%d] missing id", i)
+		}
+		if seen[imp.ID] {
+			return fmt.Errorf("ingest: duplicate imp id %q", imp.ID)
+		}
+		seen[imp.ID] = true
+		if imp.Banner == nil && imp.Video == nil && imp.Native == nil {
+			return fmt.Errorf("ingest: imp %q has no banner, video, or native", imp.ID)
+		}
+	}
+	return nil
+}
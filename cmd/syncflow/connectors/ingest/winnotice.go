@@ -0,0 +1,143 @@
+package ingest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// noticeValidityWindow bounds how long a signed win/billing notice URL
+// stays valid before handleNotice rejects it as expired, so a leaked or
+// logged nurl/burl isn't replayable indefinitely.
+const noticeValidityWindow = 24 * time.Hour
+
+// outstandingWin is what winningBid records for a bid it just won, so
+// handleNotice can check a notice's claims against the actual auction
+// outcome instead of trusting whatever its query string says.
+type outstandingWin struct {
+	ceiling float64 // won.BidAmount - a reported clearing price above this is clamped down
+}
+
+// ServeWinNotice handles the nurl callback an exchange hits to confirm
+// this agent won the impression. By the time it arrives, the exchange
+// has already substituted the ${AUCTION_PRICE} macro with the real
+// clearing price.
+func (h *Handler) ServeWinNotice(w http.ResponseWriter, r *http.Request) {
+	h.handleNotice(w, r, "win", false)
+}
+
+// ServeBillingNotice handles the burl callback an exchange hits once a
+// win has actually been billed. recordSpend fires here rather than on
+// the win notice, since a win can still go unbilled if the creative
+// never renders.
+func (h *Handler) ServeBillingNotice(w http.ResponseWriter, r *http.Request) {
+	h.handleNotice(w, r, "billing", true)
+}
+
+func (h *Handler) handleNotice(w http.ResponseWriter, r *http.Request, kind string, recordSpend bool) {
+	q := r.URL.Query()
+	bidID := q.Get("bid")
+	price, err := strconv.ParseFloat(q.Get("price"), 64)
+	if err != nil {
+		http.Error(w, "invalid or missing price", http.StatusBadRequest)
+		return
+	}
+	exp, err := strconv.ParseInt(q.Get("exp"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid or missing exp", http.StatusBadRequest)
+		return
+	}
+	if !h.verifyNoticeSignature(bidID, exp, q.Get("sig")) {
+		http.Error(w, "invalid notice signature", http.StatusForbidden)
+		return
+	}
+	if time.Now().After(time.Unix(exp, 0)) {
+		http.Error(w, "notice expired", http.StatusForbidden)
+		return
+	}
+
+	// A billing notice consumes the outstanding win so single-use holds
+	// even if the exchange (or an attacker replaying a logged burl) fires
+	// it twice; a win notice only confirms the win happened and doesn't
+	// affect spend, so it just looks the bid up.
+	var win outstandingWin
+	var ok bool
+	if recordSpend {
+		win, ok = h.consumeOutstandingWin(bidID)
+	} else {
+		win, ok = h.peekOutstandingWin(bidID)
+	}
+	if !ok {
+		http.Error(w, "unknown or already-confirmed bid", http.StatusNotFound)
+		return
+	}
+	// The exchange's reported clearing price is never trusted above what
+	// this agent actually bid - a second-price auction can clear lower,
+	// never higher.
+	if price > win.ceiling {
+		price = win.ceiling
+	}
+
+	log.Printf("📬 OpenRTB %s notice: bid=%s price=%.4f", kind, bidID, price)
+	if recordSpend && h.RecordSpend != nil {
+		h.RecordSpend(price)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// trackOutstandingWin records bidID as an outstanding win worth up to
+// ceiling, so a later win/billing notice claiming that bidID can be
+// checked against it. Called once, from winningBid, for every bid this
+// agent's auction actually won.
+func (h *Handler) trackOutstandingWin(bidID string, ceiling float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.outstanding == nil {
+		h.outstanding = make(map[string]outstandingWin)
+	}
+	h.outstanding[bidID] = outstandingWin{ceiling: ceiling}
+}
+
+// peekOutstandingWin reports whether bidID is a currently-outstanding win,
+// without consuming it.
+func (h *Handler) peekOutstandingWin(bidID string) (outstandingWin, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	win, ok := h.outstanding[bidID]
+	return win, ok
+}
+
+// consumeOutstandingWin atomically looks up and removes bidID, so a
+// replayed billing notice for the same bid is rejected as "already
+// confirmed" rather than recording spend a second time.
+func (h *Handler) consumeOutstandingWin(bidID string) (outstandingWin, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	win, ok := h.outstanding[bidID]
+	if ok {
+		delete(h.outstanding, bidID)
+	}
+	return win, ok
+}
+
+// signNotice computes the HMAC-SHA256 of bidID and exp (a Unix timestamp)
+// under NoticeSecret - the signature embedded in every nurl/burl, so
+// handleNotice can reject a forged or tampered bid/exp pair before it
+// ever reaches RecordSpend.
+func (h *Handler) signNotice(bidID string, exp int64) string {
+	mac := hmac.New(sha256.New, h.NoticeSecret)
+	fmt.Fprintf(mac, "%s|%d", bidID, exp)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyNoticeSignature reports whether sig is the correct signature for
+// bidID/exp under NoticeSecret.
+func (h *Handler) verifyNoticeSignature(bidID string, exp int64, sig string) bool {
+	expected := h.signNotice(bidID, exp)
+	return hmac.Equal([]byte(expected), []byte(sig))
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/auth"
 	"github.com/user/kiki-agent/cmd/syncshield/shield"
 )
 
@@ -17,10 +18,11 @@ func TestGoogleAdsSmartConnectorIntegration(t *testing.T) {
 
 	// Step 1: Initialize connector with $500 budget
 	config := ConnectorConfig{
-		Type:       GoogleAdsSmart,
-		APIKey:     "test-api-key-12345",
-		CustomerID: "1234567890",
-		MaxBudget:  500.00,
+		Type:           GoogleAdsSmart,
+		AuthProvider:   auth.StaticTokenAuthProvider{AccessToken: "test-access-token"},
+		DeveloperToken: "test-developer-token",
+		CustomerID:     "1234567890",
+		MaxBudget:      500.00,
 	}
 
 	connector, err := NewConnector(config)
@@ -243,10 +245,11 @@ func TestConnectorFactory(t *testing.T) {
 		{
 			name: "Google Ads Smart with Budget",
 			config: ConnectorConfig{
-				Type:       GoogleAdsSmart,
-				APIKey:     "test-key",
-				CustomerID: "12345",
-				MaxBudget:  500.00,
+				Type:           GoogleAdsSmart,
+				AuthProvider:   auth.StaticTokenAuthProvider{AccessToken: "test-key"},
+				DeveloperToken: "test-key",
+				CustomerID:     "12345",
+				MaxBudget:      500.00,
 			},
 			expectErr: false,
 			connType:  "*connectors.GoogleAdsSmartConnector",
@@ -254,10 +257,11 @@ func TestConnectorFactory(t *testing.T) {
 		{
 			name: "Google Ads Smart without Budget (should fail)",
 			config: ConnectorConfig{
-				Type:       GoogleAdsSmart,
-				APIKey:     "test-key",
-				CustomerID: "12345",
-				MaxBudget:  0, // Invalid
+				Type:           GoogleAdsSmart,
+				AuthProvider:   auth.StaticTokenAuthProvider{AccessToken: "test-key"},
+				DeveloperToken: "test-key",
+				CustomerID:     "12345",
+				MaxBudget:      0, // Invalid
 			},
 			expectErr: true,
 			connType:  "",
@@ -304,6 +308,28 @@ func TestConnectorFactory(t *testing.T) {
 			expectErr: false,
 			connType:  "*connectors.TradeDeskSmartConnector",
 		},
+		{
+			name: "TikTok Smart with Budget",
+			config: ConnectorConfig{
+				Type:         TikTokSmart,
+				AccessToken:  "test-key",
+				AdvertiserID: "advertiser-789",
+				MaxBudget:    500.00,
+			},
+			expectErr: false,
+			connType:  "*connectors.TikTokSmartConnector",
+		},
+		{
+			name: "TikTok Smart without Budget (should fail)",
+			config: ConnectorConfig{
+				Type:         TikTokSmart,
+				AccessToken:  "test-key",
+				AdvertiserID: "advertiser-789",
+				MaxBudget:    0, // Invalid
+			},
+			expectErr: true,
+			connType:  "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -14,6 +14,18 @@ type BidRequest struct {
 	Timestamp    time.Time
 	CampaignID   string
 	AudienceID   string
+
+	// RequestID is an optional idempotency key the caller can set so a
+	// CallPolicy is allowed to retry a non-idempotent call (e.g. PlaceBid)
+	// on a retryable failure without risking a double bid. Left empty, a
+	// retryable failure on a non-idempotent call is treated as permanent.
+	RequestID string
+
+	// ConsentString is the caller's IAB TCF v2 consent string, checked by
+	// connectors with a ConsentChecker configured (see gdpr.ConsentChecker)
+	// before PlaceBid issues any outbound request. Left empty, a connector
+	// falls back to its checker's UsersyncIfAmbiguous behavior.
+	ConsentString string
 }
 
 // BidResponse represents the result of a bid placement
@@ -23,6 +35,13 @@ type BidResponse struct {
 	Message      string
 	PlatformCode string
 	Timestamp    time.Time
+
+	// BidAmount is the amount this connector actually bid (AI or fallback
+	// decided), so callers comparing responses across connectors - e.g.
+	// auction.Auctioneer's WinnerPolicy - have a price to compare, not
+	// just a success flag. Zero on responses built before a connector
+	// knew its bid amount (vetoes, errors).
+	BidAmount float64
 }
 
 // PlatformConnector defines the interface all ad platform adapters must implement
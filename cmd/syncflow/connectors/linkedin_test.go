@@ -0,0 +1,138 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func newTestLinkedInConnector(t *testing.T, server *httptest.Server) *LinkedInConnector {
+	t.Helper()
+	l := NewLinkedInConnector("access-token", "account-123")
+	l.BaseURL = server.URL
+	l.RetryPolicy.InitialBackoff = 0
+	l.RetryPolicy.MaxBackoff = 0
+	if err := l.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	return l
+}
+
+func TestLinkedInConnector_PlaceBidSendsBearerAuthAndParsesResponse(t *testing.T) {
+	var gotAuthHeader, gotVersionHeader string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthHeader = r.Header.Get("Authorization")
+		gotVersionHeader = r.Header.Get("LinkedIn-Version")
+		if r.URL.Path != "/adCampaignsV2" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(linkedInCampaign{ID: 789})
+	}))
+	defer server.Close()
+
+	l := newTestLinkedInConnector(t, server)
+	resp, err := l.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", BidAmount: 2.50})
+	if err != nil {
+		t.Fatalf("PlaceBid failed: %v", err)
+	}
+	if !resp.Success || resp.BidID != "789" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if gotAuthHeader != "Bearer access-token" {
+		t.Errorf("expected a bearer Authorization header, got %q", gotAuthHeader)
+	}
+	if gotVersionHeader != linkedInAPIVersion {
+		t.Errorf("expected LinkedIn-Version %q, got %q", linkedInAPIVersion, gotVersionHeader)
+	}
+}
+
+func TestLinkedInConnector_RetriesOn429(t *testing.T) {
+	var attempts int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message":"rate limited","status":429}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(linkedInCampaign{ID: 999})
+	}))
+	defer server.Close()
+
+	l := newTestLinkedInConnector(t, server)
+	resp, err := l.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", BidAmount: 1.0})
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got error: %v", err)
+	}
+	if resp.BidID != "999" {
+		t.Fatalf("unexpected bid ID: %s", resp.BidID)
+	}
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf("expected exactly 2 attempts, got %d", attempts)
+	}
+}
+
+func TestLinkedInConnector_GivesUpAfterMaxRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"message":"server error","status":500}`))
+	}))
+	defer server.Close()
+
+	l := newTestLinkedInConnector(t, server)
+	l.RetryPolicy.MaxAttempts = 1
+
+	_, err := l.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", BidAmount: 1.0})
+	if err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+}
+
+func TestLinkedInConnector_UpdateCampaignBudgetPatchesAdAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/adAccountsV2/account-123" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	l := newTestLinkedInConnector(t, server)
+	resp, err := l.UpdateCampaignBudget(context.Background(), "camp-1", 500.0)
+	if err != nil {
+		t.Fatalf("UpdateCampaignBudget failed: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got %+v", resp)
+	}
+}
+
+func TestLinkedInConnector_UpdateTargetAudienceCreatesSegment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dmpSegments" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(linkedInDMPSegment{ID: "seg-1"})
+	}))
+	defer server.Close()
+
+	l := newTestLinkedInConnector(t, server)
+	resp, err := l.UpdateTargetAudience(context.Background(), "camp-1", "aud-1")
+	if err != nil {
+		t.Fatalf("UpdateTargetAudience failed: %v", err)
+	}
+	if !resp.Success || resp.BidID != "seg-1" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
@@ -0,0 +1,15 @@
+package connectors
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+// NewLogger builds a structured zerolog.Logger writing JSON lines to
+// stderr at level, tagged with component="connectors". Pass
+// zerolog.Nop() to a *SmartConnector in tests where log output isn't
+// asserted on.
+func NewLogger(level zerolog.Level) zerolog.Logger {
+	return zerolog.New(os.Stderr).Level(level).With().Timestamp().Str("component", "connectors").Logger()
+}
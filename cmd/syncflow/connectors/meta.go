@@ -8,25 +8,47 @@ import (
 	"log"
 	"net/http"
 	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/signing"
 )
 
 // MetaConnector implements PlatformConnector for Meta Marketing API
 type MetaConnector struct {
 	AccessToken string
-	BusinessID  string
-	HttpClient  *http.Client
-	Connected   bool
-	BaseURL     string
+	// AppSecret, when set, binds AccessToken to this app via an
+	// appsecret_proof header on every signed request - see AppSecret's
+	// doc comment on MetaSmartConnector.
+	AppSecret  string
+	BusinessID string
+	HttpClient *http.Client
+	Connected  bool
+	BaseURL    string
+}
+
+// metaPlainConnectorSigner adapts a MetaConnector's AccessToken/AppSecret
+// fields to the signing.Signer interface, mirroring metaConnectorSigner for
+// the simpler, non-CredentialProvider-backed connector.
+type metaPlainConnectorSigner struct {
+	connector *MetaConnector
+}
+
+func (s *metaPlainConnectorSigner) Sign(req *http.Request, body []byte) error {
+	if s.connector.AccessToken == "" {
+		return nil
+	}
+	return signing.NewMetaSigner(s.connector.AccessToken, s.connector.AppSecret).Sign(req, body)
 }
 
 // NewMetaConnector creates a new Meta connector
 func NewMetaConnector(accessToken, businessID string) *MetaConnector {
-	return &MetaConnector{
+	m := &MetaConnector{
 		AccessToken: accessToken,
 		BusinessID:  businessID,
 		HttpClient:  &http.Client{Timeout: 10 * time.Second},
 		BaseURL:     "https://graph.instagram.com/v18.0",
 	}
+	m.HttpClient.Transport = signing.NewSigningTransport(&metaPlainConnectorSigner{connector: m}, http.DefaultTransport, metaSigningSkew)
+	return m
 }
 
 // Connect establishes connection to Meta API
@@ -55,7 +77,7 @@ func (m *MetaConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResp
 	}
 
 	payloadBytes, _ := json.Marshal(payload)
-	apiURL := fmt.Sprintf("%s/%s/campaigns?access_token=%s", m.BaseURL, m.BusinessID, m.AccessToken)
+	apiURL := fmt.Sprintf("%s/%s/campaigns", m.BaseURL, m.BusinessID)
 
 	resp, err := m.HttpClient.Post(apiURL, "application/json", bytes.NewBuffer(payloadBytes))
 	if err != nil {
@@ -80,7 +102,7 @@ func (m *MetaConnector) UpdateCampaignBudget(ctx context.Context, campaignID str
 
 	log.Printf("💰 UpdateCampaignBudget (Meta): Campaign=%s, Budget=$%.2f", campaignID, budgetAmount)
 
-	apiURL := fmt.Sprintf("%s/%s?access_token=%s", m.BaseURL, campaignID, m.AccessToken)
+	apiURL := fmt.Sprintf("%s/%s", m.BaseURL, campaignID)
 
 	payload := map[string]interface{}{
 		"daily_budget": int64(budgetAmount * 100),
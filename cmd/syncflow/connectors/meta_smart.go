@@ -1,244 +1,413 @@
-package connectors
-
-import (
-	"bytes"
-	"context"
-	"encoding/json"
-	"fmt"
-	"log"
-	"net/http"
-	"time"
-
-	"github.com/user/kiki-agent/cmd/syncshield/shield"
-)
-
-// MetaSmartConnector implements PlatformConnector for Meta Marketing API with budget management
-type MetaSmartConnector struct {
-	AccessToken    string
-	BusinessID     string
-	HttpClient     *http.Client
-	Connected      bool
-	BaseURL        string
-	BudgetManager  *shield.BudgetManager
-	RateLimiter    *RateLimiter
-	CircuitBreaker *shield.CircuitBreaker
-	FallbackEngine *HeuristicFallbackEngine
-	MockMode       bool // For testing without real API calls
-}
-
-// NewMetaSmartConnector creates a new Meta connector with budget management
-func NewMetaSmartConnector(accessToken, businessID string, maxBudget float64) *MetaSmartConnector {
-	return &MetaSmartConnector{
-		AccessToken:    accessToken,
-		BusinessID:     businessID,
-		HttpClient:     &http.Client{Timeout: 10 * time.Second},
-		BaseURL:        "https://graph.facebook.com/v18.0",
-		BudgetManager:  shield.NewBudgetManager(maxBudget),
-		RateLimiter:    NewRateLimiter(200), // Meta allows ~200 calls/hour = ~3.3/min, be conservative
-		CircuitBreaker: shield.NewCircuitBreaker(),
-		FallbackEngine: NewHeuristicFallbackEngine(),
-	}
-}
-
-// Connect establishes connection to Meta API
-func (m *MetaSmartConnector) Connect(ctx context.Context) error {
-	log.Printf("🔗 Connecting to Meta Marketing API Smart Connector for business: %s", m.BusinessID)
-
-	// In production, validate API credentials here
-	m.Connected = true
-	stats := m.BudgetManager.GetStats()
-	log.Printf("✅ Meta Smart connection established - Budget: $%.2f/$%.2f", stats.CurrentSpend, stats.MaxBudget)
-	return nil
-}
-
-// PlaceBid sends a bid to Meta via campaign budget optimization with safety checks
-func (m *MetaSmartConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
-	if !m.Connected {
-		return nil, fmt.Errorf("not connected to Meta")
-	}
-
-	// Record latest LTV to improve fallback median quality
-	m.FallbackEngine.RecordLTV("meta", req.PredictedLTV)
-
-	// Decide bid source via circuit breaker
-	bidAmount := req.BidAmount
-	decisionSource := "ai"
-	if !m.CircuitBreaker.CanExecute() {
-		m.CircuitBreaker.RecordFallback()
-		bidAmount = m.FallbackEngine.CalculateFallbackBid("meta", req.PredictedLTV)
-		decisionSource = "fallback"
-	}
-
-	// Safety Check 1: Budget validation
-	if !m.BudgetManager.CanSpend(bidAmount) {
-		stats := m.BudgetManager.GetStats()
-		log.Printf("🛡️ BUDGET VETO: Meta bid $%.2f exceeds remaining budget $%.2f", bidAmount, stats.RemainingBudget)
-		return &BidResponse{
-			Success:      false,
-			Message:      fmt.Sprintf("Budget exceeded: $%.2f spent of $%.2f limit", stats.CurrentSpend, stats.MaxBudget),
-			PlatformCode: "BUDGET_EXCEEDED",
-			Timestamp:    time.Now(),
-		}, fmt.Errorf("budget exceeded")
-	}
-
-	// Safety Check 2: Rate limiting
-	if !m.RateLimiter.CanMakeCall() {
-		log.Printf("⚠️ RATE LIMIT: Too many Meta API calls, throttling")
-		return &BidResponse{
-			Success:      false,
-			Message:      "Rate limit exceeded, throttling API calls",
-			PlatformCode: "RATE_LIMITED",
-			Timestamp:    time.Now(),
-		}, fmt.Errorf("rate limited")
-	}
-
-	log.Printf("📍 PlaceBid (Meta): Customer=%s, LTV=%.2f, Bid=$%.2f (source=%s)", req.CustomerID, req.PredictedLTV, bidAmount, decisionSource)
-
-	// Meta doesn't do direct bid placement like RTB; instead, update campaign budget/audience
-	payload := map[string]interface{}{
-		"daily_budget":      int64(bidAmount * 100), // Meta uses cents
-		"ltv_signal":        req.PredictedLTV,
-		"optimization_goal": "VALUE",                    // Optimize for conversion value (LTV)
-		"bid_strategy":      "LOWEST_COST_WITH_BID_CAP", // Use bid cap based on LTV
-		"bid_amount":        int64(bidAmount * 100),
-		"custom_data": map[string]interface{}{
-			"kiki_ltv":        req.PredictedLTV,
-			"kiki_confidence": req.Explanation,
-			"kiki_timestamp":  req.Timestamp.Unix(),
-		},
-	}
-
-	payloadBytes, _ := json.Marshal(payload)
-	apiURL := fmt.Sprintf("%s/%s/campaigns?access_token=%s", m.BaseURL, m.BusinessID, m.AccessToken)
-
-	// Mock mode for testing
-	callStart := time.Now()
-
-	if m.MockMode {
-		log.Printf("🧪 MOCK MODE: Simulating Meta API call")
-		m.RateLimiter.RecordCall()
-		m.BudgetManager.AddSpend(bidAmount)
-		stats := m.BudgetManager.GetStats()
-		log.Printf("✅ Meta bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
-		m.CircuitBreaker.RecordSuccess(time.Since(callStart))
-
-		return &BidResponse{
-			Success:      true,
-			BidID:        fmt.Sprintf("MOCK_META_%d", time.Now().Unix()),
-			Message:      "Campaign budget adjusted via Meta Marketing API (MOCK)",
-			PlatformCode: "META_SMART",
-			Timestamp:    time.Now(),
-		}, nil
-	}
-
-	resp, err := m.HttpClient.Post(apiURL, "application/json", bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		m.CircuitBreaker.RecordFailure(time.Since(callStart))
-		log.Printf("❌ Meta API error: %v", err)
-		return &BidResponse{
-			Success:      false,
-			Message:      fmt.Sprintf("API error: %v", err),
-			PlatformCode: "META_ERROR",
-			Timestamp:    time.Now(),
-		}, err
-	}
-	defer resp.Body.Close()
-
-	// Record successful API call
-	m.RateLimiter.RecordCall()
-
-	// If bid was successful, record the spend
-	if resp.StatusCode == 200 {
-		m.BudgetManager.AddSpend(bidAmount)
-		stats := m.BudgetManager.GetStats()
-		log.Printf("✅ Meta bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
-		m.CircuitBreaker.RecordSuccess(time.Since(callStart))
-	} else {
-		m.CircuitBreaker.RecordFailure(time.Since(callStart))
-	}
-
-	return &BidResponse{
-		Success:      resp.StatusCode == 200,
-		BidID:        fmt.Sprintf("META_%d", time.Now().Unix()),
-		Message:      "Campaign budget adjusted via Meta Marketing API",
-		PlatformCode: "META_SMART",
-		Timestamp:    time.Now(),
-	}, nil
-}
-
-// UpdateCampaignBudget adjusts campaign budget for Meta with budget checks
-func (m *MetaSmartConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
-	if !m.Connected {
-		return nil, fmt.Errorf("not connected to Meta")
-	}
-
-	// Check if this budget update would exceed our limits
-	if !m.BudgetManager.CanSpend(budgetAmount) {
-		return nil, fmt.Errorf("budget update would exceed limits")
-	}
-
-	log.Printf("💰 UpdateCampaignBudget (Meta): Campaign=%s, Budget=$%.2f", campaignID, budgetAmount)
-
-	apiURL := fmt.Sprintf("%s/%s?access_token=%s", m.BaseURL, campaignID, m.AccessToken)
-
-	payload := map[string]interface{}{
-		"daily_budget": int64(budgetAmount * 100),
-	}
-	payloadBytes, _ := json.Marshal(payload)
-
-	resp, err := m.HttpClient.Post(apiURL, "application/json", bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	m.RateLimiter.RecordCall()
-
-	return &BidResponse{
-		Success:      resp.StatusCode == 200,
-		Message:      fmt.Sprintf("Meta campaign %s budget updated to $%.2f", campaignID, budgetAmount),
-		PlatformCode: "META_BUDGET",
-		Timestamp:    time.Now(),
-	}, nil
-}
-
-// UpdateTargetAudience updates audience for Meta campaign
-func (m *MetaSmartConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
-	if !m.Connected {
-		return nil, fmt.Errorf("not connected to Meta")
-	}
-
-	log.Printf("🎯 UpdateTargetAudience (Meta): Campaign=%s, Audience=%s", campaignID, audienceID)
-
-	return &BidResponse{
-		Success:      true,
-		Message:      fmt.Sprintf("Meta audience targeting updated for campaign %s", campaignID),
-		PlatformCode: "META_AUDIENCE",
-		Timestamp:    time.Now(),
-	}, nil
-}
-
-// GetStatus returns the connection status with budget info
-func (m *MetaSmartConnector) GetStatus() string {
-	if m.Connected {
-		stats := m.BudgetManager.GetStats()
-		return fmt.Sprintf("Connected to Meta Marketing API - Budget: $%.2f/$%.2f (%.1f%% used)",
-			stats.CurrentSpend,
-			stats.MaxBudget,
-			(stats.CurrentSpend/stats.MaxBudget)*100)
-	}
-	return "Disconnected"
-}
-
-// Close cleanly disconnects from Meta
-func (m *MetaSmartConnector) Close() error {
-	stats := m.BudgetManager.GetStats()
-	log.Printf("🔌 Meta connection closed - Final spend: $%.2f", stats.CurrentSpend)
-	m.Connected = false
-	return nil
-}
-
-// GetBudgetStats returns current budget statistics
-func (m *MetaSmartConnector) GetBudgetStats() shield.WindowStats {
-	return m.BudgetManager.GetStats()
-}
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/credential"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/gdpr"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/signing"
+	"github.com/user/kiki-agent/cmd/syncshield/compliance"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// metaSigningSkew bounds how far a response's echoed timestamp may drift
+// from now before it's rejected as a possible replay.
+const metaSigningSkew = 5 * time.Minute
+
+// MetaSmartConnector implements PlatformConnector for Meta Marketing API with budget management
+type MetaSmartConnector struct {
+	BusinessID string
+	// AppSecret, when set, binds CredentialProvider's bearer token to this
+	// app via an appsecret_proof header on every signed request - optional
+	// because not every integration registers an app secret, but
+	// recommended.
+	AppSecret string
+	// CredentialProvider supplies the bearer token attached to every
+	// signed request, in place of a bare long-lived AccessToken field.
+	// NewMetaSmartConnector defaults it to a credential.StaticProvider
+	// wrapping the accessToken argument; swap in a
+	// credential.AutoRenewProvider for STS-issued short-lived tokens, or
+	// leave its bearer token empty and instead authenticate at the
+	// transport layer with a credential.MutualTLSProvider set as
+	// HttpClient.Transport's Base, for zero-trust deployments that can't
+	// keep a long-lived Facebook token in memory.
+	CredentialProvider credential.Provider
+	HttpClient         *http.Client
+	Connected          bool
+	BaseURL            string
+	BudgetManager      *shield.BudgetManager
+	RateLimiter        *RateLimiter
+	CircuitBreaker     *shield.CircuitBreaker
+	FallbackEngine     *HeuristicFallbackEngine
+	MockMode           bool // For testing without real API calls
+
+	// ConsentChecker gates PlaceBid on IAB TCF v2 vendor/purpose consent
+	// before any outbound request is issued, the way prebid-server gates
+	// /cookie_sync. Always set by NewMetaSmartConnector; replace it (or
+	// set to nil to disable the gate) if a deployment handles consent
+	// elsewhere.
+	ConsentChecker gdpr.ConsentChecker
+	// ConsentVendorID is this connector's IAB Global Vendor List ID,
+	// looked up in req.ConsentString's vendor section. Meta's is 89.
+	ConsentVendorID int
+	// AuditLogger, when set, records every consent denial as a
+	// consent_check AuditEvent for GDPR Article 30 accountability.
+	AuditLogger *compliance.GDPRAuditLogger
+	// SpendLedger, if set, records every successful bid so a crash can
+	// later be reconciled against Meta's reporting API via
+	// connectors.FindLCA/Rewind. Left nil, bids simply aren't ledgered.
+	SpendLedger SpendLedger
+
+	deadline connectorDeadline
+}
+
+// metaConnectorSigner adapts a MetaSmartConnector's live
+// CredentialProvider/AppSecret fields to the signing.Signer interface, so
+// a rotated token (or a later AppSecret change) takes effect on the next
+// signed request without rebuilding HttpClient.
+type metaConnectorSigner struct {
+	connector *MetaSmartConnector
+}
+
+func (s *metaConnectorSigner) Sign(req *http.Request, body []byte) error {
+	if s.connector.CredentialProvider == nil {
+		return fmt.Errorf("meta: no CredentialProvider configured")
+	}
+	cred, err := s.connector.CredentialProvider.Credential(req.Context())
+	if err != nil {
+		return fmt.Errorf("meta: fetch credential: %w", err)
+	}
+	if cred.BearerToken == "" {
+		// No bearer token to attach - e.g. a MutualTLSProvider handling
+		// auth at the transport layer instead.
+		return nil
+	}
+	return signing.NewMetaSigner(cred.BearerToken, s.connector.AppSecret).Sign(req, body)
+}
+
+// NewMetaSmartConnector creates a new Meta connector with budget management
+func NewMetaSmartConnector(accessToken, businessID string, maxBudget float64) *MetaSmartConnector {
+	m := &MetaSmartConnector{
+		CredentialProvider: credential.NewStaticProvider(accessToken),
+		BusinessID:         businessID,
+		HttpClient:         &http.Client{Timeout: 10 * time.Second},
+		BaseURL:            "https://graph.facebook.com/v18.0",
+		BudgetManager:      shield.NewBudgetManager(maxBudget),
+		RateLimiter:        NewRateLimiter(200), // Meta allows ~200 calls/hour = ~3.3/min, be conservative
+		CircuitBreaker:     shield.NewCircuitBreaker(),
+		FallbackEngine:     NewHeuristicFallbackEngine(),
+		ConsentChecker:     gdpr.NewTCFv2Checker(true), // no signal yet from most existing callers, don't block them
+		ConsentVendorID:    gdpr.VendorMeta,
+		deadline:           newConnectorDeadline(10 * time.Second),
+	}
+	m.HttpClient.Transport = signing.NewSigningTransport(&metaConnectorSigner{connector: m}, http.DefaultTransport, metaSigningSkew)
+	return m
+}
+
+// SetReadDeadline bounds how long calls wait on the Meta API response,
+// letting a long-running bid loop tighten its SLA per iteration without
+// rebuilding HttpClient.
+func (m *MetaSmartConnector) SetReadDeadline(t time.Time) { m.deadline.SetReadDeadline(t) }
+
+// SetWriteDeadline bounds how long calls wait for the request to send.
+func (m *MetaSmartConnector) SetWriteDeadline(t time.Time) { m.deadline.SetWriteDeadline(t) }
+
+// Connect establishes connection to Meta API
+func (m *MetaSmartConnector) Connect(ctx context.Context) error {
+	log.Printf("🔗 Connecting to Meta Marketing API Smart Connector for business: %s", m.BusinessID)
+
+	// In production, validate API credentials here
+	m.Connected = true
+	stats := m.BudgetManager.GetStats()
+	log.Printf("✅ Meta Smart connection established - Budget: $%.2f/$%.2f", stats.CurrentSpend, stats.MaxBudget)
+	return nil
+}
+
+// PlaceBid sends a bid to Meta via campaign budget optimization with safety checks
+func (m *MetaSmartConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	if !m.Connected {
+		return nil, fmt.Errorf("not connected to Meta")
+	}
+
+	// Record latest LTV to improve fallback median quality
+	m.FallbackEngine.RecordLTV("meta", req.PredictedLTV)
+
+	// Decide bid source via circuit breaker
+	bidAmount := req.BidAmount
+	decisionSource := "ai"
+	if !m.CircuitBreaker.CanExecute() {
+		m.CircuitBreaker.RecordFallback()
+		bidAmount = m.FallbackEngine.CalculateFallbackBid("meta", req.PredictedLTV)
+		decisionSource = "fallback"
+	}
+
+	// Safety Check 1: IAB TCF v2 consent - gate before any outbound request,
+	// same as prebid-server gates /cookie_sync on vendor consent.
+	if m.ConsentChecker != nil {
+		allowed, purposes, reason := m.ConsentChecker.CheckConsent(req.ConsentString, m.ConsentVendorID)
+		if !allowed {
+			log.Printf("🚫 CONSENT BLOCKED: Meta bid for customer=%s denied: %s", req.CustomerID, reason)
+			if m.AuditLogger != nil {
+				m.AuditLogger.LogEvent(compliance.AuditEvent{
+					Level:      compliance.LevelSecurity,
+					EventType:  "consent_check",
+					CustomerID: req.CustomerID,
+					Action:     "place_bid",
+					Resource:   "meta_campaign",
+					Outcome:    "DENIED",
+					Reason:     reason,
+					Metadata: map[string]interface{}{
+						"vendor_id":        m.ConsentVendorID,
+						"purposes_granted": purposes,
+					},
+				})
+			}
+			return &BidResponse{
+				Success:      false,
+				Message:      fmt.Sprintf("Consent check failed: %s", reason),
+				PlatformCode: "GDPR_BLOCKED",
+				Timestamp:    time.Now(),
+			}, fmt.Errorf("consent denied: %s", reason)
+		}
+	}
+
+	// Safety Check 2: Budget validation
+	if !m.BudgetManager.CanSpend(bidAmount) {
+		stats := m.BudgetManager.GetStats()
+		log.Printf("🛡️ BUDGET VETO: Meta bid $%.2f exceeds remaining budget $%.2f", bidAmount, stats.RemainingBudget)
+		return &BidResponse{
+			Success:      false,
+			Message:      fmt.Sprintf("Budget exceeded: $%.2f spent of $%.2f limit", stats.CurrentSpend, stats.MaxBudget),
+			PlatformCode: "BUDGET_EXCEEDED",
+			Timestamp:    time.Now(),
+		}, shield.NewError(shield.ErrorKindBudgetExceeded, "meta", m.CircuitBreaker.GetState(), fmt.Errorf("budget exceeded"))
+	}
+
+	// Safety Check 3: Rate limiting
+	if !m.RateLimiter.CanMakeCall() {
+		log.Printf("⚠️ RATE LIMIT: Too many Meta API calls, throttling")
+		return &BidResponse{
+			Success:      false,
+			Message:      "Rate limit exceeded, throttling API calls",
+			PlatformCode: "RATE_LIMITED",
+			Timestamp:    time.Now(),
+		}, fmt.Errorf("rate limited")
+	}
+
+	log.Printf("📍 PlaceBid (Meta): Customer=%s, LTV=%.2f, Bid=$%.2f (source=%s)", req.CustomerID, req.PredictedLTV, bidAmount, decisionSource)
+
+	// Meta doesn't do direct bid placement like RTB; instead, update campaign budget/audience
+	payload := map[string]interface{}{
+		"daily_budget":      int64(bidAmount * 100), // Meta uses cents
+		"ltv_signal":        req.PredictedLTV,
+		"optimization_goal": "VALUE",                    // Optimize for conversion value (LTV)
+		"bid_strategy":      "LOWEST_COST_WITH_BID_CAP", // Use bid cap based on LTV
+		"bid_amount":        int64(bidAmount * 100),
+		"custom_data": map[string]interface{}{
+			"kiki_ltv":        req.PredictedLTV,
+			"kiki_confidence": req.Explanation,
+			"kiki_timestamp":  req.Timestamp.Unix(),
+		},
+	}
+
+	payloadBytes, _ := json.Marshal(payload)
+	// CredentialProvider's token travels as a signed Authorization header
+	// via the signing transport, not a logged/cached query param.
+	apiURL := fmt.Sprintf("%s/%s/campaigns", m.BaseURL, m.BusinessID)
+
+	// Mock mode for testing
+	callStart := time.Now()
+
+	if m.MockMode {
+		log.Printf("🧪 MOCK MODE: Simulating Meta API call")
+		m.RateLimiter.RecordCall()
+		m.BudgetManager.AddSpend(bidAmount)
+		stats := m.BudgetManager.GetStats()
+		log.Printf("✅ Meta bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
+		m.CircuitBreaker.RecordSuccess(time.Since(callStart))
+
+		resp := &BidResponse{
+			Success:      true,
+			BidAmount:    bidAmount,
+			BidID:        fmt.Sprintf("MOCK_META_%d", time.Now().Unix()),
+			Message:      "Campaign budget adjusted via Meta Marketing API (MOCK)",
+			PlatformCode: "META_SMART",
+			Timestamp:    time.Now(),
+		}
+		recordIfLedgered(ctx, m.SpendLedger, "meta", req, resp)
+		return resp, nil
+	}
+
+	callCtx, cancel := m.deadline.withTimeout(ctx)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(callCtx, http.MethodPost, apiURL, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("build Meta request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.HttpClient.Do(httpReq)
+	if err != nil {
+		if wasCallerCancelled(ctx) {
+			m.CircuitBreaker.RecordCancellation()
+			log.Printf("⏱️ Meta call cancelled by caller context: %v", err)
+			return &BidResponse{
+				Success:      false,
+				Message:      "Request cancelled by caller before completion",
+				PlatformCode: "META_CANCELLED",
+				Timestamp:    time.Now(),
+			}, err
+		}
+		m.CircuitBreaker.RecordFailure(time.Since(callStart))
+		log.Printf("❌ Meta API error: %v", err)
+		return &BidResponse{
+			Success:      false,
+			Message:      fmt.Sprintf("API error: %v", err),
+			PlatformCode: "META_ERROR",
+			Timestamp:    time.Now(),
+		}, err
+	}
+	defer resp.Body.Close()
+
+	// Record successful API call
+	m.RateLimiter.RecordCall()
+
+	// If bid was successful, record the spend
+	if resp.StatusCode == 200 {
+		m.BudgetManager.AddSpend(bidAmount)
+		stats := m.BudgetManager.GetStats()
+		log.Printf("✅ Meta bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
+		m.CircuitBreaker.RecordSuccess(time.Since(callStart))
+	} else {
+		m.CircuitBreaker.RecordFailure(time.Since(callStart))
+	}
+
+	bidResp := &BidResponse{
+		Success:      resp.StatusCode == 200,
+		BidAmount:    bidAmount,
+		BidID:        fmt.Sprintf("META_%d", time.Now().Unix()),
+		Message:      "Campaign budget adjusted via Meta Marketing API",
+		PlatformCode: "META_SMART",
+		Timestamp:    time.Now(),
+	}
+	recordIfLedgered(ctx, m.SpendLedger, "meta", req, bidResp)
+	return bidResp, nil
+}
+
+// UpdateCampaignBudget adjusts campaign budget for Meta with budget checks
+func (m *MetaSmartConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
+	if !m.Connected {
+		return nil, fmt.Errorf("not connected to Meta")
+	}
+	if err := ctx.Err(); err != nil {
+		m.CircuitBreaker.RecordCancellation()
+		return nil, err
+	}
+
+	// Check if this budget update would exceed our limits
+	if !m.BudgetManager.CanSpend(budgetAmount) {
+		return nil, fmt.Errorf("budget update would exceed limits")
+	}
+
+	log.Printf("💰 UpdateCampaignBudget (Meta): Campaign=%s, Budget=$%.2f", campaignID, budgetAmount)
+
+	apiURL := fmt.Sprintf("%s/%s", m.BaseURL, campaignID)
+
+	payload := map[string]interface{}{
+		"daily_budget": int64(budgetAmount * 100),
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	callCtx, cancel := m.deadline.withTimeout(ctx)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(callCtx, http.MethodPost, apiURL, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("build Meta request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.HttpClient.Do(httpReq)
+	if err != nil {
+		if wasCallerCancelled(ctx) {
+			m.CircuitBreaker.RecordCancellation()
+			return nil, err
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	m.RateLimiter.RecordCall()
+
+	return &BidResponse{
+		Success:      resp.StatusCode == 200,
+		Message:      fmt.Sprintf("Meta campaign %s budget updated to $%.2f", campaignID, budgetAmount),
+		PlatformCode: "META_BUDGET",
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+// UpdateTargetAudience updates audience for Meta campaign
+func (m *MetaSmartConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
+	if !m.Connected {
+		return nil, fmt.Errorf("not connected to Meta")
+	}
+	if err := ctx.Err(); err != nil {
+		m.CircuitBreaker.RecordCancellation()
+		return nil, err
+	}
+
+	log.Printf("🎯 UpdateTargetAudience (Meta): Campaign=%s, Audience=%s", campaignID, audienceID)
+
+	return &BidResponse{
+		Success:      true,
+		Message:      fmt.Sprintf("Meta audience targeting updated for campaign %s", campaignID),
+		PlatformCode: "META_AUDIENCE",
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+// GetCircuitBreaker exposes the connector's CircuitBreaker so callers like
+// auction.Auctioneer can check it before fanning out a bid.
+func (m *MetaSmartConnector) GetCircuitBreaker() *shield.CircuitBreaker {
+	return m.CircuitBreaker
+}
+
+// GetBudgetManager exposes the connector's BudgetManager so callers like
+// reconcile's rewind can correct its live spend state, not just a
+// throwaway local copy.
+func (m *MetaSmartConnector) GetBudgetManager() *shield.BudgetManager {
+	return m.BudgetManager
+}
+
+// GetStatus returns the connection status with budget info
+func (m *MetaSmartConnector) GetStatus() string {
+	if m.Connected {
+		stats := m.BudgetManager.GetStats()
+		return fmt.Sprintf("Connected to Meta Marketing API - Budget: $%.2f/$%.2f (%.1f%% used)",
+			stats.CurrentSpend,
+			stats.MaxBudget,
+			(stats.CurrentSpend/stats.MaxBudget)*100)
+	}
+	return "Disconnected"
+}
+
+// Close cleanly disconnects from Meta
+func (m *MetaSmartConnector) Close() error {
+	stats := m.BudgetManager.GetStats()
+	log.Printf("🔌 Meta connection closed - Final spend: $%.2f", stats.CurrentSpend)
+	m.Connected = false
+	return nil
+}
+
+// GetBudgetStats returns current budget statistics
+func (m *MetaSmartConnector) GetBudgetStats() shield.WindowStats {
+	return m.BudgetManager.GetStats()
+}
@@ -0,0 +1,329 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// Strategy selects how MultiConnector dispatches PlaceBid across its
+// member platforms.
+type Strategy int
+
+const (
+	// StrategyFirstSuccess fans out to every platform at once - a
+	// platform-routing analogue of shield.HedgePolicy/BackupPolicy - and
+	// returns whichever platform succeeds first, cancelling the rest.
+	StrategyFirstSuccess Strategy = iota
+
+	// StrategyAll fans out to every platform and waits for all of them,
+	// so a caller can inspect every platform's outcome via PlaceBidAll
+	// rather than only the winner's.
+	StrategyAll
+
+	// StrategyFallback tries platforms one at a time in the priority
+	// order they were registered, moving to the next only once the
+	// current platform's RetryPolicy is exhausted or its breaker is open.
+	StrategyFallback
+)
+
+// NamedConnector pairs a PlatformConnector with the name MultiConnector
+// reports it under in metrics and, for StrategyFallback, the priority
+// order platforms are tried in. PlatformConnector itself exposes no name
+// (GetStatus is a connection-status string, not an identifier), so the
+// name has to be supplied alongside the connector.
+type NamedConnector struct {
+	Name      string
+	Connector PlatformConnector
+
+	// RetryPolicy overrides the shield.DefaultRetryPolicy() MultiConnector
+	// otherwise guards this platform's calls with. Nil uses the default.
+	RetryPolicy *shield.RetryPolicy
+}
+
+// platformEntry is a NamedConnector plus the CircuitBreaker MultiConnector
+// tracks its health with - one pair per platform, so a single down
+// platform's open breaker or exhausted retries can never stall dispatch
+// to the others.
+type platformEntry struct {
+	NamedConnector
+	breaker *shield.CircuitBreaker
+}
+
+// PlatformMetrics summarizes one platform's dispatch history within a
+// MultiConnector, as returned by Metrics.
+type PlatformMetrics struct {
+	Platform     string
+	Attempts     int64
+	Wins         int64
+	TotalLatency time.Duration
+}
+
+// AverageLatency returns TotalLatency/Attempts, or 0 if no attempts have
+// been recorded yet.
+func (m PlatformMetrics) AverageLatency() time.Duration {
+	if m.Attempts == 0 {
+		return 0
+	}
+	return m.TotalLatency / time.Duration(m.Attempts)
+}
+
+// PlatformResult is one platform's outcome within a StrategyAll dispatch.
+type PlatformResult struct {
+	Platform string
+	Response *BidResponse
+	Err      error
+	Latency  time.Duration
+}
+
+// MultiConnector routes PlaceBid across a set of PlatformConnectors under
+// a pluggable Strategy. Each platform is guarded by its own RetryPolicy
+// and CircuitBreaker - mirroring connectors.CallPolicy's direct-wiring
+// convention rather than shield.Policy/Compose - so a single down
+// platform retries and trips its own breaker without blocking or counting
+// against any other platform's dispatch.
+type MultiConnector struct {
+	Strategy Strategy
+
+	mu      sync.Mutex
+	entries []*platformEntry
+	metrics map[string]*PlatformMetrics
+}
+
+// NewMultiConnector builds a MultiConnector over platforms under the
+// given strategy. StrategyFallback tries platforms in the order given;
+// StrategyFirstSuccess and StrategyAll dispatch to all of them regardless
+// of order.
+func NewMultiConnector(strategy Strategy, platforms []NamedConnector) *MultiConnector {
+	mc := &MultiConnector{
+		Strategy: strategy,
+		metrics:  make(map[string]*PlatformMetrics, len(platforms)),
+	}
+	for _, p := range platforms {
+		rp := p.RetryPolicy
+		if rp == nil {
+			rp = shield.DefaultRetryPolicy()
+		}
+		mc.entries = append(mc.entries, &platformEntry{
+			NamedConnector: NamedConnector{Name: p.Name, Connector: p.Connector, RetryPolicy: rp},
+			breaker:        shield.NewCircuitBreaker(),
+		})
+		mc.metrics[p.Name] = &PlatformMetrics{Platform: p.Name}
+	}
+	return mc
+}
+
+// PlaceBid dispatches req according to mc.Strategy. For StrategyAll it
+// returns the first successful platform's response (use PlaceBidAll
+// directly for the full per-platform breakdown).
+func (mc *MultiConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	switch mc.Strategy {
+	case StrategyFirstSuccess:
+		return mc.placeBidFirstSuccess(ctx, req)
+	case StrategyFallback:
+		return mc.placeBidFallback(ctx, req)
+	case StrategyAll:
+		results, err := mc.PlaceBidAll(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			if r.Err == nil {
+				return r.Response, nil
+			}
+		}
+		return nil, fmt.Errorf("multiconnector: all platforms failed")
+	default:
+		return nil, fmt.Errorf("multiconnector: unknown strategy %v", mc.Strategy)
+	}
+}
+
+// placeBidFirstSuccess fans out to every platform at once and returns
+// whichever succeeds first, cancelling the context so the remaining
+// in-flight calls stop once a winner is found.
+func (mc *MultiConnector) placeBidFirstSuccess(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		name string
+		resp *BidResponse
+		err  error
+	}
+
+	mc.mu.Lock()
+	entries := append([]*platformEntry(nil), mc.entries...)
+	mc.mu.Unlock()
+
+	results := make(chan outcome, len(entries))
+	for _, e := range entries {
+		e := e
+		go func() {
+			resp, err := mc.callPlatform(ctx, e, req)
+			results <- outcome{e.Name, resp, err}
+		}()
+	}
+
+	var lastErr error
+	for range entries {
+		o := <-results
+		if o.err == nil {
+			mc.recordWin(o.name)
+			cancel()
+			return o.resp, nil
+		}
+		lastErr = o.err
+	}
+	return nil, mc.allFailedErr(lastErr)
+}
+
+// placeBidFallback tries each platform in registration order, moving to
+// the next only once the current one's RetryPolicy is exhausted or its
+// breaker refuses the call.
+func (mc *MultiConnector) placeBidFallback(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	mc.mu.Lock()
+	entries := append([]*platformEntry(nil), mc.entries...)
+	mc.mu.Unlock()
+
+	var lastErr error
+	for _, e := range entries {
+		resp, err := mc.callPlatform(ctx, e, req)
+		if err == nil {
+			mc.recordWin(e.Name)
+			return resp, nil
+		}
+		lastErr = err
+	}
+	return nil, mc.allFailedErr(lastErr)
+}
+
+// PlaceBidAll fans out req to every platform and waits for all of them,
+// returning one PlatformResult per platform (in the order they were
+// registered) regardless of individual success or failure. It returns a
+// non-nil error only when every platform failed.
+func (mc *MultiConnector) PlaceBidAll(ctx context.Context, req *BidRequest) ([]PlatformResult, error) {
+	mc.mu.Lock()
+	entries := append([]*platformEntry(nil), mc.entries...)
+	mc.mu.Unlock()
+
+	results := make([]PlatformResult, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		i, e := i, e
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			resp, err := mc.callPlatform(ctx, e, req)
+			results[i] = PlatformResult{Platform: e.Name, Response: resp, Err: err, Latency: time.Since(start)}
+			if err == nil {
+				mc.recordWin(e.Name)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Err == nil {
+			return results, nil
+		}
+	}
+	return results, fmt.Errorf("multiconnector: all platforms failed")
+}
+
+// callPlatform runs one platform's PlaceBid under its RetryPolicy,
+// guarded by its CircuitBreaker the way connectors.CallPolicy.Execute
+// guards a single outbound call: one CanExecute check up front, one
+// RecordSuccess/RecordFailure for the whole retry cycle at the end.
+// Attempts and per-attempt latency are recorded into mc.metrics as they
+// happen, independent of whether the cycle as a whole succeeds.
+func (mc *MultiConnector) callPlatform(ctx context.Context, e *platformEntry, req *BidRequest) (*BidResponse, error) {
+	if !e.breaker.CanExecute() {
+		return nil, fmt.Errorf("multiconnector: %s circuit breaker open", e.Name)
+	}
+
+	var resp *BidResponse
+	cycleStart := time.Now()
+	err := e.RetryPolicy.Exec(ctx, func() error {
+		mc.recordAttempt(e.Name)
+		attemptStart := time.Now()
+		r, err := e.Connector.PlaceBid(ctx, req)
+		mc.recordLatency(e.Name, time.Since(attemptStart))
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+	cycleLatency := time.Since(cycleStart)
+	if err != nil {
+		e.breaker.RecordFailure(cycleLatency)
+		return nil, fmt.Errorf("multiconnector: %s: %w", e.Name, err)
+	}
+	e.breaker.RecordSuccess(cycleLatency)
+	return resp, nil
+}
+
+// allFailedErr wraps lastErr (nil if mc has no platforms at all) into the
+// error PlaceBid/placeBidFallback/placeBidFirstSuccess return once every
+// platform has failed.
+func (mc *MultiConnector) allFailedErr(lastErr error) error {
+	if lastErr == nil {
+		return fmt.Errorf("multiconnector: no platforms configured")
+	}
+	return fmt.Errorf("multiconnector: all platforms failed: %w", lastErr)
+}
+
+func (mc *MultiConnector) recordAttempt(name string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.metrics[name].Attempts++
+}
+
+func (mc *MultiConnector) recordLatency(name string, d time.Duration) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.metrics[name].TotalLatency += d
+}
+
+func (mc *MultiConnector) recordWin(name string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.metrics[name].Wins++
+}
+
+// Metrics returns a snapshot of every platform's dispatch metrics
+// recorded so far, sorted by platform name.
+func (mc *MultiConnector) Metrics() []PlatformMetrics {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	out := make([]PlatformMetrics, 0, len(mc.metrics))
+	for _, m := range mc.metrics {
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Platform < out[j].Platform })
+	return out
+}
+
+// Close closes every registered platform connector, collecting every
+// error encountered rather than stopping at the first one.
+func (mc *MultiConnector) Close() error {
+	mc.mu.Lock()
+	entries := append([]*platformEntry(nil), mc.entries...)
+	mc.mu.Unlock()
+
+	var errs []error
+	for _, e := range entries {
+		if err := e.Connector.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Name, err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("multiconnector: %d platform(s) failed to close: %v", len(errs), errs)
+}
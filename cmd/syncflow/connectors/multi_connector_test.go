@@ -0,0 +1,262 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// fakePlatformConnector is a minimal PlatformConnector test double whose
+// PlaceBid behavior is entirely driven by placeBid, so tests can simulate
+// timeouts, 5xx storms, and partial successes without a real HTTP server.
+type fakePlatformConnector struct {
+	placeBid func(ctx context.Context, req *BidRequest) (*BidResponse, error)
+	calls    int32
+}
+
+func (f *fakePlatformConnector) Connect(ctx context.Context) error { return nil }
+
+func (f *fakePlatformConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	atomic.AddInt32(&f.calls, 1)
+	return f.placeBid(ctx, req)
+}
+
+func (f *fakePlatformConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakePlatformConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakePlatformConnector) GetStatus() string { return "fake" }
+
+func (f *fakePlatformConnector) Close() error { return nil }
+
+// alwaysFails returns a fake connector whose every call fails with msg,
+// simulating a platform stuck in a 5xx storm.
+func alwaysFails(msg string) *fakePlatformConnector {
+	return &fakePlatformConnector{
+		placeBid: func(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+			return nil, fmt.Errorf("%s", msg)
+		},
+	}
+}
+
+// alwaysSucceeds returns a fake connector whose every call succeeds
+// instantly, tagging the response with platform so a test can tell which
+// platform actually won.
+func alwaysSucceeds(platform string) *fakePlatformConnector {
+	return &fakePlatformConnector{
+		placeBid: func(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+			return &BidResponse{Success: true, PlatformCode: platform}, nil
+		},
+	}
+}
+
+func TestMultiConnector_FirstSuccessReturnsFastestWinnerAndCancelsTheRest(t *testing.T) {
+	slow := &fakePlatformConnector{
+		placeBid: func(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(200 * time.Millisecond):
+				return &BidResponse{Success: true, PlatformCode: "slow"}, nil
+			}
+		},
+	}
+	fast := alwaysSucceeds("fast")
+
+	mc := NewMultiConnector(StrategyFirstSuccess, []NamedConnector{
+		{Name: "slow", Connector: slow},
+		{Name: "fast", Connector: fast},
+	})
+
+	resp, err := mc.PlaceBid(context.Background(), &BidRequest{CustomerID: "c1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.PlatformCode != "fast" {
+		t.Errorf("expected the fast platform to win, got %s", resp.PlatformCode)
+	}
+
+	// Let the slow platform's goroutine observe the cancellation before
+	// the test exits, so -race doesn't flag it racing past teardown.
+	time.Sleep(10 * time.Millisecond)
+
+	metrics := mc.Metrics()
+	for _, m := range metrics {
+		if m.Platform == "fast" && m.Wins != 1 {
+			t.Errorf("expected fast to be recorded as the winner, got %d wins", m.Wins)
+		}
+	}
+}
+
+func TestMultiConnector_FallbackTriesPlatformsInPriorityOrder(t *testing.T) {
+	primary := alwaysFails("503 service unavailable")
+	secondary := alwaysSucceeds("secondary")
+
+	mc := NewMultiConnector(StrategyFallback, []NamedConnector{
+		{Name: "primary", Connector: primary, RetryPolicy: quickRetryPolicy()},
+		{Name: "secondary", Connector: secondary},
+	})
+
+	resp, err := mc.PlaceBid(context.Background(), &BidRequest{CustomerID: "c1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.PlatformCode != "secondary" {
+		t.Errorf("expected fallback to secondary once primary exhausts retries, got %s", resp.PlatformCode)
+	}
+	if atomic.LoadInt32(&secondary.calls) != 1 {
+		t.Errorf("expected secondary to be called exactly once, got %d", secondary.calls)
+	}
+}
+
+func TestMultiConnector_FallbackReturnsErrorWhenEveryPlatformFails(t *testing.T) {
+	a := alwaysFails("timeout")
+	b := alwaysFails("connection refused")
+
+	mc := NewMultiConnector(StrategyFallback, []NamedConnector{
+		{Name: "a", Connector: a, RetryPolicy: quickRetryPolicy()},
+		{Name: "b", Connector: b, RetryPolicy: quickRetryPolicy()},
+	})
+
+	_, err := mc.PlaceBid(context.Background(), &BidRequest{CustomerID: "c1"})
+	if err == nil {
+		t.Fatal("expected an error once every platform fails")
+	}
+}
+
+func TestMultiConnector_AllAggregatesPartialSuccess(t *testing.T) {
+	good := alwaysSucceeds("good")
+	bad := alwaysFails("500 internal server error")
+
+	mc := NewMultiConnector(StrategyAll, []NamedConnector{
+		{Name: "good", Connector: good},
+		{Name: "bad", Connector: bad, RetryPolicy: quickRetryPolicy()},
+	})
+
+	results, err := mc.PlaceBidAll(context.Background(), &BidRequest{CustomerID: "c1"})
+	if err != nil {
+		t.Fatalf("expected no error with a partial success, got %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected one result per platform, got %d", len(results))
+	}
+
+	var sawGood, sawBad bool
+	for _, r := range results {
+		switch r.Platform {
+		case "good":
+			sawGood = true
+			if r.Err != nil || r.Response == nil || !r.Response.Success {
+				t.Errorf("expected good to succeed, got %+v err=%v", r.Response, r.Err)
+			}
+		case "bad":
+			sawBad = true
+			if r.Err == nil {
+				t.Error("expected bad to report an error")
+			}
+		}
+	}
+	if !sawGood || !sawBad {
+		t.Fatalf("expected results for both platforms, got %+v", results)
+	}
+}
+
+func TestMultiConnector_AllReturnsErrorWhenEveryPlatformFails(t *testing.T) {
+	a := alwaysFails("5xx storm")
+	b := alwaysFails("5xx storm")
+
+	mc := NewMultiConnector(StrategyAll, []NamedConnector{
+		{Name: "a", Connector: a, RetryPolicy: quickRetryPolicy()},
+		{Name: "b", Connector: b, RetryPolicy: quickRetryPolicy()},
+	})
+
+	_, err := mc.PlaceBidAll(context.Background(), &BidRequest{CustomerID: "c1"})
+	if err == nil {
+		t.Fatal("expected an error when every platform fails")
+	}
+}
+
+func TestMultiConnector_CircuitBreakerOpensIndependentlyPerPlatform(t *testing.T) {
+	bad := alwaysFails("boom")
+	good := alwaysSucceeds("good")
+
+	mc := NewMultiConnector(StrategyFallback, []NamedConnector{
+		{Name: "bad", Connector: bad, RetryPolicy: quickRetryPolicy()},
+		{Name: "good", Connector: good},
+	})
+
+	// Drive "bad" into an open circuit breaker via repeated failed cycles.
+	for i := 0; i < 5; i++ {
+		mc.PlaceBid(context.Background(), &BidRequest{CustomerID: "c1"})
+	}
+
+	mc.mu.Lock()
+	badBreakerOpen := !mc.entries[0].breaker.CanExecute()
+	goodBreakerOpen := !mc.entries[1].breaker.CanExecute()
+	mc.mu.Unlock()
+
+	if !badBreakerOpen {
+		t.Error("expected the repeatedly-failing platform's breaker to open")
+	}
+	if goodBreakerOpen {
+		t.Error("expected the healthy platform's breaker to remain closed")
+	}
+}
+
+func TestMultiConnector_MetricsTrackAttemptsAndWins(t *testing.T) {
+	flaky := &fakePlatformConnector{
+		placeBid: func(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+			if atomic.LoadInt32(&flakyCalls) == 0 {
+				atomic.AddInt32(&flakyCalls, 1)
+				return nil, fmt.Errorf("503 service unavailable")
+			}
+			return &BidResponse{Success: true, PlatformCode: "flaky"}, nil
+		},
+	}
+
+	mc := NewMultiConnector(StrategyFallback, []NamedConnector{
+		{Name: "flaky", Connector: flaky, RetryPolicy: quickRetryPolicy()},
+	})
+
+	resp, err := mc.PlaceBid(context.Background(), &BidRequest{CustomerID: "c1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.PlatformCode != "flaky" {
+		t.Fatalf("expected flaky's eventual success, got %s", resp.PlatformCode)
+	}
+
+	metrics := mc.Metrics()
+	if len(metrics) != 1 {
+		t.Fatalf("expected 1 platform's metrics, got %d", len(metrics))
+	}
+	if metrics[0].Attempts < 2 {
+		t.Errorf("expected at least 2 attempts (1 failure + 1 success), got %d", metrics[0].Attempts)
+	}
+	if metrics[0].Wins != 1 {
+		t.Errorf("expected exactly 1 win, got %d", metrics[0].Wins)
+	}
+}
+
+// flakyCalls is shared state for TestMultiConnector_MetricsTrackAttemptsAndWins's
+// fake connector closure.
+var flakyCalls int32
+
+// quickRetryPolicy returns a RetryPolicy tuned for tests: a couple of
+// near-zero-delay attempts so "all retries exhausted" paths run in
+// milliseconds instead of DefaultRetryPolicy's real backoff schedule.
+func quickRetryPolicy() *shield.RetryPolicy {
+	rp := shield.DefaultRetryPolicy()
+	rp.MaxAttempts = 2
+	rp.InitialBackoff = time.Millisecond
+	rp.MaxBackoff = time.Millisecond
+	return rp
+}
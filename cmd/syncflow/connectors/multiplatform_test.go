@@ -5,6 +5,8 @@ import (
 	"log"
 	"testing"
 	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/auth"
 )
 
 // TestMetaSmartConnectorIntegration tests Meta Smart Connector with budget management
@@ -132,6 +134,57 @@ func TestMetaSmartConnectorIntegration(t *testing.T) {
 	log.Printf("\n✅ Meta integration test complete")
 }
 
+// TestMetaSmartConnector_ConsentGate verifies PlaceBid rejects bids whose
+// IAB TCF v2 consent string denies Meta's vendor or a required purpose,
+// and that it still honors UsersyncIfAmbiguous for requests carrying no
+// consent string at all.
+func TestMetaSmartConnector_ConsentGate(t *testing.T) {
+	smartConnector := NewMetaSmartConnector("test-access-token", "meta-business-123", 400.00)
+	smartConnector.MockMode = true
+
+	ctx := context.Background()
+	if err := smartConnector.Connect(ctx); err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+
+	baseReq := &BidRequest{
+		CustomerID:   "META_CUST_CONSENT",
+		PredictedLTV: 500,
+		BidAmount:    50,
+		Timestamp:    time.Now(),
+	}
+
+	// No consent string: falls back to the connector's default
+	// UsersyncIfAmbiguous=true, so the bid still goes through.
+	resp, err := smartConnector.PlaceBid(ctx, baseReq)
+	if err != nil {
+		t.Fatalf("expected ambiguous (empty) consent string to fall back to allowed, got error: %v", err)
+	}
+	if resp.PlatformCode == "GDPR_BLOCKED" {
+		t.Fatalf("expected ambiguous consent to be allowed, got %s", resp.PlatformCode)
+	}
+
+	// A denying checker must block PlaceBid before any outbound call.
+	smartConnector.ConsentChecker = denyingConsentChecker{reason: "vendor 89 lacks consent"}
+	resp, err = smartConnector.PlaceBid(ctx, baseReq)
+	if err == nil {
+		t.Fatal("expected PlaceBid to return an error when consent is denied")
+	}
+	if resp == nil || resp.PlatformCode != "GDPR_BLOCKED" {
+		t.Fatalf("expected PlatformCode GDPR_BLOCKED, got %+v", resp)
+	}
+}
+
+// denyingConsentChecker is a test double that always denies, so the
+// consent gate can be exercised without hand-building a TCF string.
+type denyingConsentChecker struct {
+	reason string
+}
+
+func (d denyingConsentChecker) CheckConsent(tcString string, vendorID int) (bool, []int, string) {
+	return false, nil, d.reason
+}
+
 // TestTradeDeskSmartConnectorIntegration tests Trade Desk Smart Connector with budget management
 func TestTradeDeskSmartConnectorIntegration(t *testing.T) {
 	log.Println("🧪 Starting Trade Desk Smart Connector Integration Test")
@@ -274,10 +327,11 @@ func TestMultiPlatformBudgetManagement(t *testing.T) {
 
 	// Create all three smart connectors with separate budgets
 	googleConfig := ConnectorConfig{
-		Type:       GoogleAdsSmart,
-		APIKey:     "google-test-key",
-		CustomerID: "google-123",
-		MaxBudget:  300.00,
+		Type:           GoogleAdsSmart,
+		AuthProvider:   auth.StaticTokenAuthProvider{AccessToken: "google-test-key"},
+		DeveloperToken: "google-test-key",
+		CustomerID:     "google-123",
+		MaxBudget:      300.00,
 	}
 
 	metaConfig := ConnectorConfig{
@@ -0,0 +1,42 @@
+package openrtb
+
+// RequestData is one HTTP call an Adapter wants made, decoupled from
+// actually sending it - the same separation prebid-server's adapters use
+// so MakeRequests can be unit tested without a live HTTP client.
+type RequestData struct {
+	Method  string
+	URI     string
+	Body    []byte
+	Headers map[string]string
+}
+
+// ResponseData is the raw result of sending a RequestData, handed to
+// MakeBids for parsing.
+type ResponseData struct {
+	StatusCode int
+	Body       []byte
+}
+
+// TypedBid pairs a winning Bid with the seat that offered it.
+type TypedBid struct {
+	Bid  *Bid
+	Seat string
+}
+
+// BidderResponse is what MakeBids returns: the bids parsed out of a
+// ResponseData, in the currency the bidder priced them in.
+type BidderResponse struct {
+	Bids     []*TypedBid
+	Currency string
+}
+
+// Adapter decouples building a bid request from parsing its response,
+// mirroring how Prebid Server's per-bidder adapters separate MakeRequests
+// (pure request construction - can fan one OpenRTBRequest out to several
+// HTTP calls) from MakeBids (pure response parsing, no I/O), so a bidder's
+// request-building and response-parsing logic can each be tested without a
+// live endpoint.
+type Adapter interface {
+	MakeRequests(req *OpenRTBRequest) ([]*RequestData, []error)
+	MakeBids(req *OpenRTBRequest, reqData *RequestData, respData *ResponseData) (*BidderResponse, []error)
+}
@@ -0,0 +1,125 @@
+// Package openrtb defines the wire types for the OpenRTB 2.5/2.6 protocol
+// (https://iabtechlab.com/standards/openrtb/), scoped to the fields the
+// connectors package needs to run a header-bidding auction against an
+// arbitrary exchange or SSP.
+package openrtb
+
+import "encoding/json"
+
+// BidRequest is the top-level OpenRTB bid request object.
+type BidRequest struct {
+	ID     string          `json:"id"`
+	Imp    []Imp           `json:"imp"`
+	Site   *Site           `json:"site,omitempty"`
+	App    *App            `json:"app,omitempty"`
+	TMax   int             `json:"tmax,omitempty"`
+	Cur    []string        `json:"cur,omitempty"`
+	User   *User           `json:"user,omitempty"`
+	Device *Device         `json:"device,omitempty"`
+	Regs   *Regs           `json:"regs,omitempty"`
+	Ext    json.RawMessage `json:"ext,omitempty"`
+}
+
+// OpenRTBRequest and OpenRTBResponse alias BidRequest/BidResponse - the
+// names Adapter implementations use when they're talking about the wire
+// objects they build and parse, as opposed to the module's own
+// proprietary BidRequest/BidResponse in the connectors package.
+type OpenRTBRequest = BidRequest
+type OpenRTBResponse = BidResponse
+
+// Imp is a single impression opportunity within a BidRequest.
+type Imp struct {
+	ID          string          `json:"id"`
+	Banner      *Banner         `json:"banner,omitempty"`
+	Video       *Video          `json:"video,omitempty"`
+	Native      *Native         `json:"native,omitempty"`
+	BidFloor    float64         `json:"bidfloor,omitempty"`
+	BidFloorCur string          `json:"bidfloorcur,omitempty"`
+	Ext         json.RawMessage `json:"ext,omitempty"`
+}
+
+// Site describes the web property serving the impression; mutually
+// exclusive with App.
+type Site struct {
+	ID     string `json:"id,omitempty"`
+	Domain string `json:"domain,omitempty"`
+	Page   string `json:"page,omitempty"`
+}
+
+// App describes the mobile or CTV app serving the impression; mutually
+// exclusive with Site.
+type App struct {
+	ID     string `json:"id,omitempty"`
+	Bundle string `json:"bundle,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// Banner describes a display creative slot.
+type Banner struct {
+	W int `json:"w,omitempty"`
+	H int `json:"h,omitempty"`
+}
+
+// Video describes a video creative slot.
+type Video struct {
+	MIMEs       []string `json:"mimes,omitempty"`
+	MinDuration int      `json:"minduration,omitempty"`
+	MaxDuration int      `json:"maxduration,omitempty"`
+}
+
+// Native describes a native ad slot, carrying its asset request as a
+// pre-encoded JSON string per the OpenRTB Native spec.
+type Native struct {
+	Request string `json:"request,omitempty"`
+}
+
+// User carries the buy-side identifier for frequency capping and targeting.
+type User struct {
+	ID  string          `json:"id,omitempty"`
+	Ext json.RawMessage `json:"ext,omitempty"`
+}
+
+// Device carries the requesting device's user agent and IP.
+type Device struct {
+	UA  string `json:"ua,omitempty"`
+	IP  string `json:"ip,omitempty"`
+	IFA string `json:"ifa,omitempty"`
+}
+
+// Regs carries regulatory signals, namely GDPR and US privacy (CCPA) opt-outs.
+type Regs struct {
+	Ext *RegsExt `json:"ext,omitempty"`
+}
+
+// RegsExt is the regs.ext object OpenRTB uses for GDPR/CCPA extensions.
+type RegsExt struct {
+	GDPR      int    `json:"gdpr"`
+	USPrivacy string `json:"us_privacy,omitempty"`
+}
+
+// BidResponse is the top-level OpenRTB bid response object.
+type BidResponse struct {
+	ID      string    `json:"id"`
+	SeatBid []SeatBid `json:"seatbid,omitempty"`
+	Cur     string    `json:"cur,omitempty"`
+}
+
+// SeatBid groups the bids a single seat/bidder returned.
+type SeatBid struct {
+	Seat string `json:"seat,omitempty"`
+	Bid  []Bid  `json:"bid"`
+}
+
+// Bid is a single winning (or candidate) bid for an impression.
+type Bid struct {
+	ID     string  `json:"id"`
+	ImpID  string  `json:"impid"`
+	Price  float64 `json:"price"`
+	AdM    string  `json:"adm,omitempty"`
+	NURL   string  `json:"nurl,omitempty"`
+	BURL   string  `json:"burl,omitempty"`
+	CrID   string  `json:"crid,omitempty"`
+	W      int     `json:"w,omitempty"`
+	H      int     `json:"h,omitempty"`
+	DealID string  `json:"dealid,omitempty"`
+}
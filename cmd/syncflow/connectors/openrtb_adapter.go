@@ -0,0 +1,197 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/openrtb"
+)
+
+// OpenRTBConnector implements PlatformConnector against any exchange or SSP
+// that speaks OpenRTB 2.5/2.6, so the module isn't limited to the hardcoded
+// platform list — it can act as a header-bidding aggregator the same way
+// prebid-server fans a single impression out to many demand partners.
+type OpenRTBConnector struct {
+	Endpoint string
+	Seat     string
+	TMax     int // milliseconds; defaults to 150 if unset
+
+	// GDPR and USPrivacy populate regs.ext so downstream bidders can honor
+	// the same consent signals the module's own consent subsystem enforces.
+	GDPR      bool
+	USPrivacy string
+
+	HttpClient *http.Client
+	Connected  bool
+}
+
+// NewOpenRTBConnector creates a new OpenRTB connector targeting endpoint,
+// bidding under the given seat name.
+func NewOpenRTBConnector(endpoint, seat string) *OpenRTBConnector {
+	return &OpenRTBConnector{
+		Endpoint:   endpoint,
+		Seat:       seat,
+		TMax:       150,
+		HttpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Connect validates the connector has an endpoint to bid against.
+func (o *OpenRTBConnector) Connect(ctx context.Context) error {
+	log.Printf("🔗 Connecting to OpenRTB endpoint %s (seat=%s)", o.Endpoint, o.Seat)
+	if o.Endpoint == "" {
+		return fmt.Errorf("openrtb connector requires an endpoint URL")
+	}
+	o.Connected = true
+	log.Printf("✅ OpenRTB connector ready")
+	return nil
+}
+
+// PlaceBid marshals req into an OpenRTB BidRequest and submits it to Endpoint,
+// returning the top-priced bid from the response seatbids.
+func (o *OpenRTBConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	if !o.Connected {
+		return nil, fmt.Errorf("not connected to OpenRTB endpoint %s", o.Endpoint)
+	}
+
+	rtbReq := o.buildBidRequest(req)
+	body, err := json.Marshal(rtbReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenRTB bid request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenRTB request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-openrtb-version", "2.5")
+
+	resp, err := o.HttpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openrtb request to %s failed: %w", o.Endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return &BidResponse{
+			Success:      false,
+			Message:      "no bid from OpenRTB endpoint",
+			PlatformCode: "OPENRTB_NOBID",
+			Timestamp:    time.Now(),
+		}, nil
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenRTB response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("openrtb endpoint %s returned status %d: %s", o.Endpoint, resp.StatusCode, string(respBody))
+	}
+
+	var rtbResp openrtb.BidResponse
+	if err := json.Unmarshal(respBody, &rtbResp); err != nil {
+		return nil, fmt.Errorf("failed to decode OpenRTB bid response: %w", err)
+	}
+
+	best, ok := highestBid(rtbResp)
+	if !ok {
+		return &BidResponse{
+			Success:      false,
+			Message:      "OpenRTB response carried no bids",
+			PlatformCode: "OPENRTB_NOBID",
+			Timestamp:    time.Now(),
+		}, nil
+	}
+
+	log.Printf("✅ OpenRTB bid %s won at $%.2f (seat=%s)", best.ID, best.Price, o.Seat)
+
+	return &BidResponse{
+		Success:      true,
+		BidID:        best.ID,
+		Message:      fmt.Sprintf("Bid placed via OpenRTB to %s", o.Endpoint),
+		PlatformCode: "OPENRTB",
+		BidAmount:    best.Price,
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+// buildBidRequest converts a module BidRequest into an OpenRTB BidRequest.
+func (o *OpenRTBConnector) buildBidRequest(req *BidRequest) openrtb.BidRequest {
+	tmax := o.TMax
+	if tmax <= 0 {
+		tmax = 150
+	}
+
+	var regs *openrtb.Regs
+	if o.GDPR || o.USPrivacy != "" {
+		gdpr := 0
+		if o.GDPR {
+			gdpr = 1
+		}
+		regs = &openrtb.Regs{Ext: &openrtb.RegsExt{GDPR: gdpr, USPrivacy: o.USPrivacy}}
+	}
+
+	return openrtb.BidRequest{
+		ID:   fmt.Sprintf("RTB_%d", time.Now().UnixNano()),
+		Cur:  []string{"USD"},
+		TMax: tmax,
+		Imp: []openrtb.Imp{{
+			ID:       req.CampaignID,
+			Banner:   &openrtb.Banner{W: 300, H: 250},
+			BidFloor: req.BidAmount,
+		}},
+		User: &openrtb.User{ID: req.CustomerID},
+		Regs: regs,
+	}
+}
+
+// highestBid returns the highest-priced bid across every seatbid, or false
+// if the response carried no bids at all.
+func highestBid(resp openrtb.BidResponse) (openrtb.Bid, bool) {
+	var best openrtb.Bid
+	found := false
+	for _, seatBid := range resp.SeatBid {
+		for _, bid := range seatBid.Bid {
+			if !found || bid.Price > best.Price {
+				best = bid
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+// UpdateCampaignBudget always returns an error for OpenRTB: the protocol has no concept
+// of a standing campaign budget, only per-auction bid floors.
+func (o *OpenRTBConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
+	return nil, fmt.Errorf("openrtb connector does not support campaign budgets; set bid floors per auction instead")
+}
+
+// UpdateTargetAudience always returns an error for OpenRTB: audience targeting is carried
+// per-request in BidRequest.User/Device, not configured out-of-band.
+func (o *OpenRTBConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
+	return nil, fmt.Errorf("openrtb connector does not support out-of-band audience targeting; set BidRequest.AudienceID per auction instead")
+}
+
+// GetStatus returns connection status.
+func (o *OpenRTBConnector) GetStatus() string {
+	if o.Connected {
+		return fmt.Sprintf("Connected to OpenRTB endpoint %s (seat=%s)", o.Endpoint, o.Seat)
+	}
+	return "Disconnected from OpenRTB endpoint"
+}
+
+// Close cleanly disconnects from the endpoint.
+func (o *OpenRTBConnector) Close() error {
+	log.Println("🔌 OpenRTB connection closed")
+	o.Connected = false
+	return nil
+}
@@ -0,0 +1,142 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/openrtb"
+)
+
+func TestOpenRTBConnector_PlaceBidPicksHighestSeatBid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openrtb.BidRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode bid request: %v", err)
+		}
+		if len(req.Imp) != 1 || req.Imp[0].ID != "camp-1" {
+			t.Errorf("unexpected imp in bid request: %+v", req.Imp)
+		}
+
+		json.NewEncoder(w).Encode(openrtb.BidResponse{
+			ID: req.ID,
+			SeatBid: []openrtb.SeatBid{
+				{Seat: "ssp-a", Bid: []openrtb.Bid{{ID: "bid-low", ImpID: "camp-1", Price: 1.0}}},
+				{Seat: "ssp-b", Bid: []openrtb.Bid{{ID: "bid-high", ImpID: "camp-1", Price: 3.5}}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	c := NewOpenRTBConnector(server.URL, "test-seat")
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	resp, err := c.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", CustomerID: "cust-1", BidAmount: 0.5})
+	if err != nil {
+		t.Fatalf("PlaceBid failed: %v", err)
+	}
+	if !resp.Success || resp.BidID != "bid-high" {
+		t.Fatalf("expected the highest-priced bid to win, got %+v", resp)
+	}
+}
+
+func TestOpenRTBConnector_PlaceBidNoBidReturnsUnsuccessful(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := NewOpenRTBConnector(server.URL, "test-seat")
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	resp, err := c.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", BidAmount: 0.5})
+	if err != nil {
+		t.Fatalf("expected a no-bid response, not an error: %v", err)
+	}
+	if resp.Success {
+		t.Fatalf("expected Success=false for a no-bid response, got %+v", resp)
+	}
+}
+
+func TestOpenRTBConnector_PlaceBidRequiresConnect(t *testing.T) {
+	c := NewOpenRTBConnector("https://example.invalid/rtb", "test-seat")
+
+	_, err := c.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", BidAmount: 0.5})
+	if err == nil {
+		t.Fatal("expected an error placing a bid before Connect")
+	}
+}
+
+func TestOpenRTBConnector_PlaceBidErrorStatusReturnsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("upstream exploded"))
+	}))
+	defer server.Close()
+
+	c := NewOpenRTBConnector(server.URL, "test-seat")
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	_, err := c.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", BidAmount: 0.5})
+	if err == nil {
+		t.Fatal("expected an error for a non-2xx/204 response")
+	}
+}
+
+func TestOpenRTBConnector_UpdateCampaignBudgetUnsupported(t *testing.T) {
+	c := NewOpenRTBConnector("https://example.invalid/rtb", "test-seat")
+	if _, err := c.UpdateCampaignBudget(context.Background(), "camp-1", 10); err == nil {
+		t.Fatal("expected OpenRTB to reject campaign budget updates")
+	}
+}
+
+func TestOpenRTBConnector_UpdateTargetAudienceUnsupported(t *testing.T) {
+	c := NewOpenRTBConnector("https://example.invalid/rtb", "test-seat")
+	if _, err := c.UpdateTargetAudience(context.Background(), "camp-1", "aud-1"); err == nil {
+		t.Fatal("expected OpenRTB to reject out-of-band audience targeting")
+	}
+}
+
+func TestOpenRTBConnector_GetStatusReflectsConnection(t *testing.T) {
+	c := NewOpenRTBConnector("https://example.invalid/rtb", "test-seat")
+	if got := c.GetStatus(); got != "Disconnected from OpenRTB endpoint" {
+		t.Fatalf("expected disconnected status before Connect, got %q", got)
+	}
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if got := c.GetStatus(); got == "Disconnected from OpenRTB endpoint" {
+		t.Fatalf("expected a connected status after Connect, got %q", got)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := c.GetStatus(); got != "Disconnected from OpenRTB endpoint" {
+		t.Fatalf("expected disconnected status after Close, got %q", got)
+	}
+}
+
+func TestOpenRTBConnector_BuildBidRequestSetsRegsExt(t *testing.T) {
+	c := NewOpenRTBConnector("https://example.invalid/rtb", "test-seat")
+	c.GDPR = true
+	c.USPrivacy = "1YNN"
+
+	req := c.buildBidRequest(&BidRequest{CampaignID: "camp-1", CustomerID: "cust-1", BidAmount: 2.0})
+	if req.Regs == nil || req.Regs.Ext == nil {
+		t.Fatal("expected regs.ext to be set")
+	}
+	if req.Regs.Ext.GDPR != 1 {
+		t.Errorf("expected gdpr=1, got %d", req.Regs.Ext.GDPR)
+	}
+	if req.Regs.Ext.USPrivacy != "1YNN" {
+		t.Errorf("expected us_privacy=1YNN, got %q", req.Regs.Ext.USPrivacy)
+	}
+}
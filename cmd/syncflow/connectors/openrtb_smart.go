@@ -0,0 +1,124 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// OpenRTBSmartConnector implements PlatformConnector for an OpenRTB
+// exchange/SSP with budget management, matching the other platforms' smart
+// connectors.
+type OpenRTBSmartConnector struct {
+	*OpenRTBConnector
+	BudgetManager  *shield.BudgetManager
+	RateLimiter    *RateLimiter
+	CircuitBreaker *shield.CircuitBreaker
+	FallbackEngine *HeuristicFallbackEngine
+	// SpendLedger, if set, records every successful bid so a crash can
+	// later be reconciled against this exchange's reporting API via
+	// connectors.FindLCA/Rewind. Left nil, bids simply aren't ledgered.
+	SpendLedger SpendLedger
+}
+
+// NewOpenRTBSmartConnector creates a new OpenRTB connector with budget management.
+func NewOpenRTBSmartConnector(endpoint, seat string, maxBudget float64) *OpenRTBSmartConnector {
+	return &OpenRTBSmartConnector{
+		OpenRTBConnector: NewOpenRTBConnector(endpoint, seat),
+		BudgetManager:    shield.NewBudgetManager(maxBudget),
+		RateLimiter:      NewRateLimiter(50),
+		CircuitBreaker:   shield.NewCircuitBreaker(),
+		FallbackEngine:   NewHeuristicFallbackEngine(),
+	}
+}
+
+// PlaceBid sends a bid to the OpenRTB endpoint with the same budget, rate
+// limit, and circuit-breaker fallback safety checks the other smart
+// connectors apply.
+func (o *OpenRTBSmartConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	if !o.Connected {
+		return nil, fmt.Errorf("not connected to OpenRTB endpoint %s", o.Endpoint)
+	}
+
+	o.FallbackEngine.RecordLTV("openrtb", req.PredictedLTV)
+
+	bidAmount := req.BidAmount
+	decisionSource := "ai"
+	if !o.CircuitBreaker.CanExecute() {
+		o.CircuitBreaker.RecordFallback()
+		bidAmount = o.FallbackEngine.CalculateFallbackBid("openrtb", req.PredictedLTV)
+		decisionSource = "fallback"
+	}
+
+	if !o.RateLimiter.CanMakeCall() {
+		return nil, fmt.Errorf("rate limit exceeded for OpenRTB endpoint %s", o.Endpoint)
+	}
+
+	if !o.BudgetManager.CanSpend(bidAmount) {
+		stats := o.BudgetManager.GetStats()
+		log.Printf("🛡️ BUDGET VETO: OpenRTB bid $%.2f exceeds remaining budget $%.2f", bidAmount, stats.RemainingBudget)
+		return &BidResponse{
+			Success:      false,
+			Message:      fmt.Sprintf("Budget exceeded: $%.2f spent of $%.2f limit", stats.CurrentSpend, stats.MaxBudget),
+			PlatformCode: "BUDGET_EXCEEDED",
+			Timestamp:    time.Now(),
+		}, shield.NewError(shield.ErrorKindBudgetExceeded, "openrtb", o.CircuitBreaker.GetState(), fmt.Errorf("budget exceeded"))
+	}
+
+	log.Printf("📍 PlaceBid (OpenRTB): Customer=%s, LTV=%.2f, Bid=$%.2f (source=%s)", req.CustomerID, req.PredictedLTV, bidAmount, decisionSource)
+
+	callStart := time.Now()
+	bidReq := *req
+	bidReq.BidAmount = bidAmount
+	resp, err := o.OpenRTBConnector.PlaceBid(ctx, &bidReq)
+	if err != nil {
+		o.CircuitBreaker.RecordFailure(time.Since(callStart))
+		return resp, err
+	}
+
+	o.RateLimiter.RecordCall()
+	if resp.Success {
+		o.BudgetManager.AddSpend(bidAmount)
+		recordIfLedgered(ctx, o.SpendLedger, "openrtb", req, resp)
+	}
+	o.CircuitBreaker.RecordSuccess(time.Since(callStart))
+
+	return resp, nil
+}
+
+// GetBudgetStats returns budget statistics.
+func (o *OpenRTBSmartConnector) GetBudgetStats() shield.WindowStats {
+	return o.BudgetManager.GetStats()
+}
+
+// GetCircuitBreaker exposes the connector's CircuitBreaker so callers like
+// auction.Auctioneer can check it before fanning out a bid.
+func (o *OpenRTBSmartConnector) GetCircuitBreaker() *shield.CircuitBreaker {
+	return o.CircuitBreaker
+}
+
+// GetBudgetManager exposes the connector's BudgetManager so callers like
+// reconcile's rewind can correct its live spend state, not just a
+// throwaway local copy.
+func (o *OpenRTBSmartConnector) GetBudgetManager() *shield.BudgetManager {
+	return o.BudgetManager
+}
+
+// GetStatus returns connection status.
+func (o *OpenRTBSmartConnector) GetStatus() string {
+	if o.Connected {
+		stats := o.BudgetManager.GetStats()
+		return fmt.Sprintf("Connected to OpenRTB endpoint %s - Budget: $%.2f/$%.2f", o.Endpoint, stats.CurrentSpend, stats.MaxBudget)
+	}
+	return "Disconnected from OpenRTB endpoint"
+}
+
+// Close closes the connection.
+func (o *OpenRTBSmartConnector) Close() error {
+	log.Printf("🔌 OpenRTB connection closed - Final spend: $%.2f", o.BudgetManager.GetStats().CurrentSpend)
+	o.Connected = false
+	return nil
+}
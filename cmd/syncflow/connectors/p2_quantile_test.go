@@ -0,0 +1,123 @@
+package connectors
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// exactQuantileOf is the naive O(n log n) reference implementation
+// (sort + linear-interpolate) that p2Quantile's O(1)-per-sample estimate
+// is checked against.
+func exactQuantileOf(values []float64, p float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return exactQuantile(sorted, p)
+}
+
+func TestP2QuantileMatchesExactMedianUnderFiveSamples(t *testing.T) {
+	for n := 1; n < 5; n++ {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = float64((i*37 + 11) % 97)
+		}
+
+		q := newP2Quantile(0.5)
+		for _, v := range values {
+			q.observe(v)
+		}
+
+		got, ok := q.value()
+		if !ok {
+			t.Fatalf("n=%d: expected a value once at least one sample is observed", n)
+		}
+		want := exactQuantileOf(values, 0.5)
+		if got != want {
+			t.Errorf("n=%d: got %.4f, want exact %.4f", n, got, want)
+		}
+	}
+}
+
+func TestP2QuantileWithinOnePercentOnUniformDistribution(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	const n = 5000
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = rng.Float64() * 1000
+	}
+
+	for _, p := range []float64{0.25, 0.5, 0.75, 0.9} {
+		q := newP2Quantile(p)
+		for _, v := range values {
+			q.observe(v)
+		}
+		got, ok := q.value()
+		if !ok {
+			t.Fatalf("p=%.2f: expected a value", p)
+		}
+		want := exactQuantileOf(values, p)
+		if errPct := math.Abs(got-want) / want * 100; errPct > 1.0 {
+			t.Errorf("p=%.2f: estimate %.4f vs exact %.4f, error %.2f%% exceeds 1%%", p, got, want, errPct)
+		}
+	}
+}
+
+func TestP2QuantileWithinToleranceOnSkewedDistribution(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	const n = 5000
+	values := make([]float64, n)
+	for i := range values {
+		// Exponential-ish skew: small values common, occasional large spikes.
+		values[i] = -math.Log(1-rng.Float64()) * 200
+	}
+
+	// P² trades some accuracy in the far tail of a heavily skewed
+	// distribution for its O(1) memory; p90 gets a looser bound here than
+	// the body-of-the-distribution quantiles.
+	tolerances := map[float64]float64{0.25: 1.0, 0.5: 1.0, 0.75: 1.0, 0.9: 2.0}
+	for _, p := range []float64{0.25, 0.5, 0.75, 0.9} {
+		q := newP2Quantile(p)
+		for _, v := range values {
+			q.observe(v)
+		}
+		got, ok := q.value()
+		if !ok {
+			t.Fatalf("p=%.2f: expected a value", p)
+		}
+		want := exactQuantileOf(values, p)
+		tolerance := tolerances[p]
+		if errPct := math.Abs(got-want) / want * 100; errPct > tolerance {
+			t.Errorf("p=%.2f: estimate %.4f vs exact %.4f, error %.2f%% exceeds %.1f%%", p, got, want, errPct, tolerance)
+		}
+	}
+}
+
+func TestHeuristicFallbackEngineGetQuantileTracksP90(t *testing.T) {
+	hfe := NewHeuristicFallbackEngine()
+
+	rng := rand.New(rand.NewSource(99))
+	values := make([]float64, 500)
+	for i := range values {
+		values[i] = rng.Float64() * 500
+	}
+	for _, v := range values {
+		hfe.RecordLTV("meta", v)
+	}
+
+	got, ok := hfe.GetQuantile("meta", 0.9)
+	if !ok {
+		t.Fatal("expected p90 to be tracked by default")
+	}
+	want := exactQuantileOf(values, 0.9)
+	if errPct := math.Abs(got-want) / want * 100; errPct > 1.0 {
+		t.Errorf("p90 estimate %.4f vs exact %.4f, error %.2f%% exceeds 1%%", got, want, errPct)
+	}
+
+	if _, ok := hfe.GetQuantile("meta", 0.42); ok {
+		t.Error("expected an untracked quantile to return ok=false")
+	}
+	if _, ok := hfe.GetQuantile("unknown_platform", 0.5); ok {
+		t.Error("expected a platform with no recorded LTVs to return ok=false")
+	}
+}
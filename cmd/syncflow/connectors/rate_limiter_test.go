@@ -0,0 +1,92 @@
+package connectors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_CanMakeCallStaysBackwardCompatible(t *testing.T) {
+	rl := NewRateLimiter(5)
+
+	admitted := 0
+	for i := 0; i < 10; i++ {
+		if !rl.CanMakeCall() {
+			break
+		}
+		rl.RecordCall()
+		admitted++
+	}
+
+	if admitted != 5 {
+		t.Fatalf("expected exactly 5 of 10 rapid calls to be admitted by a burst-5 limiter, got %d", admitted)
+	}
+}
+
+func TestRateLimiter_AcquireBlocksOnConcurrencyCeilingUntilReleased(t *testing.T) {
+	rl := NewRateLimiter(100)
+	rl.concurrency = 1
+
+	ctx := context.Background()
+	if err := rl.Acquire(ctx); err != nil {
+		t.Fatalf("first Acquire failed: %v", err)
+	}
+
+	blockedCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := rl.Acquire(blockedCtx); err == nil {
+		t.Fatal("expected second Acquire to block until the context deadline since concurrency ceiling is 1")
+	}
+
+	rl.Release()
+	if err := rl.Acquire(context.Background()); err != nil {
+		t.Fatalf("expected Acquire to succeed once the slot was released: %v", err)
+	}
+}
+
+func TestRateLimiter_RecordFailureHalvesConcurrency(t *testing.T) {
+	rl := NewRateLimiter(100)
+
+	rl.RecordFailure()
+	snap := rl.Snapshot()
+	if snap.Concurrency != 50 {
+		t.Errorf("expected concurrency to halve to 50, got %d", snap.Concurrency)
+	}
+
+	rl.RecordFailure()
+	snap = rl.Snapshot()
+	if snap.Concurrency != 25 {
+		t.Errorf("expected concurrency to halve again to 25, got %d", snap.Concurrency)
+	}
+}
+
+func TestRateLimiter_RecordSuccessGrowsConcurrencyAfterThreshold(t *testing.T) {
+	rl := NewRateLimiter(100)
+	rl.concurrency = 10
+
+	for i := 0; i < rateLimiterSuccessesToGrow-1; i++ {
+		rl.RecordSuccess(10 * time.Millisecond)
+	}
+	if got := rl.Snapshot().Concurrency; got != 10 {
+		t.Fatalf("expected concurrency to stay at 10 before the growth threshold, got %d", got)
+	}
+
+	rl.RecordSuccess(10 * time.Millisecond)
+	if got := rl.Snapshot().Concurrency; got != 11 {
+		t.Fatalf("expected concurrency to grow to 11 once the growth threshold is reached, got %d", got)
+	}
+}
+
+func TestRateLimiter_SnapshotReportsRecent429Rate(t *testing.T) {
+	rl := NewRateLimiter(100)
+
+	rl.RecordSuccess(10 * time.Millisecond)
+	rl.RecordFailure()
+	rl.RecordSuccess(10 * time.Millisecond)
+	rl.RecordFailure()
+
+	snap := rl.Snapshot()
+	if snap.Recent429Rate != 0.5 {
+		t.Errorf("expected a recent 429 rate of 0.5 for 2 failures out of 4 calls, got %.2f", snap.Recent429Rate)
+	}
+}
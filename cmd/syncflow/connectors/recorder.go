@@ -0,0 +1,55 @@
+package connectors
+
+import "time"
+
+// BidMeta carries the context around a PlaceBid call that BidRequest and
+// BidResponse alone don't capture - which side of the circuit breaker
+// decided the bid, and how the decision compared to the AI signal - so a
+// Recorder can explain *why* a bid was shaped the way it was, not just
+// that it happened.
+type BidMeta struct {
+	Platform       string
+	DecisionSource string // "ai" or "fallback"
+	TargetROAS     float64
+	Latency        time.Duration
+}
+
+// Recorder observes bid outcomes and the safety-check decision points that
+// can shape or short-circuit a bid before it ever reaches the platform,
+// modeled on the way Prebid fires an analytics adapter per auction event.
+// Recorders are fire-and-forget: a connector calls every configured
+// Recorder at each hook but never lets a Recorder's behavior change the
+// bid outcome.
+type Recorder interface {
+	// LogBid fires once per PlaceBid call that actually reaches the
+	// platform, successful or not.
+	LogBid(req *BidRequest, resp *BidResponse, meta BidMeta)
+	// LogBudgetVeto fires when BudgetManager rejects a bid before it's sent.
+	LogBudgetVeto(platform string, bidAmount, remainingBudget float64)
+	// LogFallback fires when the circuit breaker routes a bid through
+	// HeuristicFallbackEngine instead of the AI-predicted amount.
+	LogFallback(platform string, aiBidAmount, fallbackBidAmount float64)
+	// LogRateLimit fires when RateLimiter throttles a call before it's sent.
+	LogRateLimit(platform string)
+	// LogAuctionOutcome fires when a caller (e.g. auction.Auctioneer) learns
+	// whether a bid won or lost, and at what clearing price.
+	LogAuctionOutcome(platform string, won bool, clearPrice float64)
+	// LogDecision fires once per tick of a bidding loop (e.g. cmd/syncflow's
+	// main), independent of whether the decision actually reached PlaceBid -
+	// the audit trail a CSV-row LogDecision used to write ad hoc. tags
+	// carries whatever a hooks.Executor's stages attached along the way
+	// (e.g. which hook vetoed or altered the bid); nil means none did.
+	LogDecision(customerID string, predictedLTV, bidAmount float64, decision, mode string, tags map[string]string)
+}
+
+// fireRecorders runs fn against every non-nil entry in recorders. Recorder
+// calls are best-effort observability rather than part of a bid's control
+// flow, so a nil or empty slice is simply a no-op instead of a special
+// case every call site needs to guard against.
+func fireRecorders(recorders []Recorder, fn func(Recorder)) {
+	for _, r := range recorders {
+		if r != nil {
+			fn(r)
+		}
+	}
+}
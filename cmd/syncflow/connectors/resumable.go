@@ -0,0 +1,304 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// SmartConnector is the set of methods every *_smart.go connector
+// (XSmartConnector, MetaSmartConnector, ...) already implements structurally
+// - there's no shared base type, just the same two extra methods bolted onto
+// each one alongside PlatformConnector. ResumableBidder only needs those two
+// plus PlaceBid, so it depends on this narrower interface instead of forcing
+// every connector to start embedding a common struct.
+type SmartConnector interface {
+	PlatformConnector
+	GetCircuitBreaker() *shield.CircuitBreaker
+	GetBudgetStats() shield.WindowStats
+}
+
+// ErrDeferred is returned by ResumableBidder.PlaceBid when the connector's
+// circuit is OPEN and the caller supplied BidOptions.OnRecovery: the bid has
+// been queued rather than placed, and the caller's callback will fire later
+// with the eventual result.
+var ErrDeferred = errors.New("connectors: bid deferred pending circuit recovery")
+
+// ErrDeferredTimeout is passed to a deferred bid's OnRecovery callback when
+// Drain finds it past BidOptions.Deadline before the circuit recovered far
+// enough to place it.
+var ErrDeferredTimeout = errors.New("connectors: deferred bid timed out waiting for circuit recovery")
+
+// BidOptions configures ResumableBidder.PlaceBid's behavior when a
+// connector's circuit is OPEN, modeled on chainlink's txmgr.Broadcaster,
+// whose resumeCallback(ctx, taskRunID, value, err) fires once a queued
+// transaction finally resolves.
+type BidOptions struct {
+	// OnRecovery, if set, lets PlaceBid queue the bid instead of failing
+	// outright when the circuit is OPEN. It fires exactly once, from
+	// Drain, with either the eventual BidResponse or an error (ErrDeferredTimeout
+	// if Deadline passed first). Left nil, an OPEN circuit fails PlaceBid
+	// immediately with shield.ErrCircuitOpen, same as calling the connector
+	// directly.
+	OnRecovery func(*BidResponse, error)
+
+	// Deadline bounds how long a deferred bid is allowed to wait in the
+	// queue. Zero means it waits indefinitely for the circuit to recover.
+	Deadline time.Time
+}
+
+// deferredBid is the JSON-serializable record PlaceBid persists to Redis for
+// a bid it couldn't place immediately. OnRecovery itself can't be
+// serialized, so it stays in ResumableBidder.callbacks, keyed by ID - a bid
+// drained by a different process than the one that enqueued it runs with no
+// callback, which is an accepted limitation of a single in-process callback
+// map.
+type deferredBid struct {
+	ID         string
+	Platform   string
+	Request    *BidRequest
+	Deadline   time.Time
+	EnqueuedAt time.Time
+}
+
+// ResumableBidder wraps a SmartConnector so a bid placed while its circuit
+// is OPEN can be deferred to Redis instead of dropped, then drained and
+// retried once the circuit closes again - the syncflow analogue of
+// chainlink txmgr.Broadcaster's resumeCallback. Each platform gets its own
+// queue (queueKey is namespaced per ResumableBidder, not a single global
+// list) because bids from different platforms recover on unrelated
+// schedules - their own circuit breakers trip and close independently - and
+// sharing one Redis list would interleave them out of per-platform FIFO
+// order.
+type ResumableBidder struct {
+	Platform  string
+	connector SmartConnector
+	redis     *redis.Client
+	queueKey  string
+
+	mu        sync.Mutex
+	callbacks map[string]func(*BidResponse, error)
+
+	stopCh chan struct{}
+}
+
+// NewResumableBidder wraps connector for platform, queueing deferred bids
+// under queueKey on rdb. queueKey is typically DefaultQueueKey scoped per
+// platform, e.g. QueueKeyFor(platform).
+func NewResumableBidder(platform string, connector SmartConnector, rdb *redis.Client, queueKey string) *ResumableBidder {
+	return &ResumableBidder{
+		Platform:  platform,
+		connector: connector,
+		redis:     rdb,
+		queueKey:  queueKey,
+		callbacks: make(map[string]func(*BidResponse, error)),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// DefaultQueueKey is the Redis key prefix deferred bids are queued under,
+// matching the "syncflow-queue" name main.go's Redis worker already polls.
+const DefaultQueueKey = "syncflow-queue"
+
+// QueueKeyFor returns the per-platform queue key a ResumableBidder for
+// platform should use, so each platform's deferred bids drain in their own
+// FIFO order instead of interleaving on one shared list.
+func QueueKeyFor(platform string) string {
+	return fmt.Sprintf("%s:%s", DefaultQueueKey, platform)
+}
+
+// PlaceBid places req via the wrapped connector, or - if the circuit is OPEN
+// and opts.OnRecovery is set - enqueues it to run later and returns
+// ErrDeferred immediately. req.RequestID is populated with a ulid if empty,
+// so it always carries the idempotency key Drain's claim check needs to
+// avoid double-spending the budget GetBudgetStats reports on.
+func (r *ResumableBidder) PlaceBid(ctx context.Context, req *BidRequest, opts BidOptions) (*BidResponse, error) {
+	if req.RequestID == "" {
+		req.RequestID = ulid.Make().String()
+	}
+
+	breaker := r.connector.GetCircuitBreaker()
+	if breaker.CanExecute() {
+		return r.connector.PlaceBid(ctx, req)
+	}
+
+	if opts.OnRecovery == nil {
+		return nil, shield.ErrCircuitOpen
+	}
+
+	if err := r.enqueue(ctx, req, opts); err != nil {
+		return nil, fmt.Errorf("connectors: failed to defer bid %s: %w", req.RequestID, err)
+	}
+	return nil, ErrDeferred
+}
+
+// enqueue persists req to the Redis queue and registers callback locally
+// under req.RequestID, so Drain can find it again once the circuit closes.
+func (r *ResumableBidder) enqueue(ctx context.Context, req *BidRequest, opts BidOptions) error {
+	bid := deferredBid{
+		ID:         req.RequestID,
+		Platform:   r.Platform,
+		Request:    req,
+		Deadline:   opts.Deadline,
+		EnqueuedAt: time.Now(),
+	}
+	payload, err := json.Marshal(bid)
+	if err != nil {
+		return fmt.Errorf("marshal deferred bid: %w", err)
+	}
+
+	r.mu.Lock()
+	r.callbacks[bid.ID] = opts.OnRecovery
+	r.mu.Unlock()
+
+	if err := r.redis.RPush(ctx, r.queueKey, payload).Err(); err != nil {
+		r.mu.Lock()
+		delete(r.callbacks, bid.ID)
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// EnqueueForRecovery pushes req directly onto the Redis queue a
+// ResumableBidder for platform drains (see QueueKeyFor), without needing a
+// live ResumableBidder/SmartConnector instance - for callers like the
+// reconcile CLI's rewind subcommand that already know a bid needs to be
+// retried (its fate after a crash is uncertain) but have no connected
+// connector on hand to construct one. Drain resolves it exactly like a bid
+// PlaceBid deferred itself, just with no OnRecovery callback registered.
+func EnqueueForRecovery(ctx context.Context, rdb *redis.Client, platform string, req *BidRequest, opts BidOptions) error {
+	if req.RequestID == "" {
+		req.RequestID = ulid.Make().String()
+	}
+	bid := deferredBid{
+		ID:         req.RequestID,
+		Platform:   platform,
+		Request:    req,
+		Deadline:   opts.Deadline,
+		EnqueuedAt: time.Now(),
+	}
+	payload, err := json.Marshal(bid)
+	if err != nil {
+		return fmt.Errorf("marshal deferred bid: %w", err)
+	}
+	return rdb.RPush(ctx, QueueKeyFor(platform), payload).Err()
+}
+
+// Drain pops every bid currently queued and, once the circuit has fully
+// re-entered CLOSED (not merely CanExecute's HALF_OPEN probe, which a
+// backlog of queued bids could overwhelm), resolves each one in FIFO order.
+// Called on its own it's a no-op while the circuit is anything but CLOSED;
+// Start calls it on a ticker so deferred bids drain automatically as soon as
+// the platform recovers.
+func (r *ResumableBidder) Drain(ctx context.Context) error {
+	if r.connector.GetCircuitBreaker().GetState() != shield.CLOSED {
+		return nil
+	}
+
+	for {
+		result, err := r.redis.LPop(ctx, r.queueKey).Result()
+		if err == redis.Nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("connectors: drain %s failed: %w", r.queueKey, err)
+		}
+
+		var bid deferredBid
+		if err := json.Unmarshal([]byte(result), &bid); err != nil {
+			log.Printf("⚠️ ResumableBidder(%s): dropping unparseable queued bid: %v", r.Platform, err)
+			continue
+		}
+		r.resolve(ctx, &bid)
+	}
+}
+
+// resolve settles one deferred bid: it times out past its deadline, skips if
+// another worker already claimed it, or else places it via the wrapped
+// connector and fires the registered OnRecovery callback (if any) with the
+// result.
+func (r *ResumableBidder) resolve(ctx context.Context, bid *deferredBid) {
+	callback := r.takeCallback(bid.ID)
+
+	if !bid.Deadline.IsZero() && time.Now().After(bid.Deadline) {
+		if callback != nil {
+			callback(nil, ErrDeferredTimeout)
+		}
+		return
+	}
+
+	claimed, err := r.redis.SetNX(ctx, r.claimKey(bid.ID), time.Now().Format(time.RFC3339), 24*time.Hour).Result()
+	if err != nil {
+		log.Printf("⚠️ ResumableBidder(%s): claim check for %s failed, skipping this round: %v", r.Platform, bid.ID, err)
+		return
+	}
+	if !claimed {
+		// Another worker already placed (or is placing) this bid - skip it
+		// rather than risk double-spending the budget GetBudgetStats guards.
+		return
+	}
+
+	resp, err := r.connector.PlaceBid(ctx, bid.Request)
+	if callback != nil {
+		callback(resp, err)
+	}
+}
+
+// claimKey is the per-bid idempotency claim SetNX guards resolve with, so a
+// bid drained twice - by this worker or a duplicate one - only ever places
+// once.
+func (r *ResumableBidder) claimKey(bidID string) string {
+	return r.queueKey + ":claimed:" + bidID
+}
+
+// takeCallback removes and returns the OnRecovery callback registered for
+// id, or nil if this process never registered one - the case when a
+// different process's PlaceBid call enqueued it.
+func (r *ResumableBidder) takeCallback(id string) func(*BidResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cb := r.callbacks[id]
+	delete(r.callbacks, id)
+	return cb
+}
+
+// Start launches a background goroutine that calls Drain on every tick,
+// mirroring shield.MultiEndpointClient's Start(ctx)/ticker-driven run loop.
+// A Drain error is logged and swallowed - a transient Redis hiccup just
+// means the next tick retries. Stop ends it.
+func (r *ResumableBidder) Start(ctx context.Context, pollInterval time.Duration) {
+	go r.run(ctx, pollInterval)
+}
+
+// Stop ends the background goroutine started by Start.
+func (r *ResumableBidder) Stop() {
+	close(r.stopCh)
+}
+
+func (r *ResumableBidder) run(ctx context.Context, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			if err := r.Drain(ctx); err != nil {
+				log.Printf("⚠️ ResumableBidder(%s): drain failed: %v", r.Platform, err)
+			}
+		}
+	}
+}
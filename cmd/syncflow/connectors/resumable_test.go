@@ -0,0 +1,266 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+func newResumableTestRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// fakeSmartConnector is a minimal SmartConnector test double that records
+// every PlaceBid call and returns a canned response/error.
+type fakeSmartConnector struct {
+	breaker *shield.CircuitBreaker
+	budget  *shield.BudgetManager
+
+	calls []*BidRequest
+	resp  *BidResponse
+	err   error
+}
+
+func newFakeSmartConnector() *fakeSmartConnector {
+	return &fakeSmartConnector{
+		breaker: shield.NewCircuitBreaker(),
+		budget:  shield.NewBudgetManagerWithLimits(80, 100, 0),
+		resp:    &BidResponse{Success: true, BidID: "BID_1"},
+	}
+}
+
+func (f *fakeSmartConnector) Connect(ctx context.Context) error { return nil }
+
+func (f *fakeSmartConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	f.calls = append(f.calls, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.budget.AddSpendForBid(req.RequestID, req.BidAmount)
+	return f.resp, nil
+}
+
+func (f *fakeSmartConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeSmartConnector) UpdateTargetAudience(ctx context.Context, campaignID, audienceID string) (*BidResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeSmartConnector) GetStatus() string { return "ok" }
+func (f *fakeSmartConnector) Close() error      { return nil }
+
+func (f *fakeSmartConnector) GetCircuitBreaker() *shield.CircuitBreaker { return f.breaker }
+func (f *fakeSmartConnector) GetBudgetStats() shield.WindowStats        { return f.budget.GetStats() }
+
+func TestResumableBidder_PlacesImmediatelyWhenCircuitClosed(t *testing.T) {
+	rdb := newResumableTestRedisClient(t)
+	conn := newFakeSmartConnector()
+	rb := NewResumableBidder("x", conn, rdb, QueueKeyFor("x"))
+
+	resp, err := rb.PlaceBid(context.Background(), &BidRequest{CustomerID: "c1", BidAmount: 5}, BidOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.BidID != "BID_1" {
+		t.Errorf("expected the connector's response to pass through, got %+v", resp)
+	}
+	if len(conn.calls) != 1 {
+		t.Errorf("expected exactly one PlaceBid call, got %d", len(conn.calls))
+	}
+}
+
+func TestResumableBidder_DefersAndReturnsErrDeferredWhenCircuitOpen(t *testing.T) {
+	rdb := newResumableTestRedisClient(t)
+	conn := newFakeSmartConnector()
+	conn.breaker.SetThresholds(1, 1, 500*time.Millisecond, time.Minute)
+	conn.breaker.RecordFailure(10 * time.Millisecond)
+
+	rb := NewResumableBidder("x", conn, rdb, QueueKeyFor("x"))
+
+	recovered := make(chan struct{}, 1)
+	_, err := rb.PlaceBid(context.Background(), &BidRequest{CustomerID: "c1", BidAmount: 5}, BidOptions{
+		OnRecovery: func(resp *BidResponse, err error) { recovered <- struct{}{} },
+	})
+	if err != ErrDeferred {
+		t.Fatalf("expected ErrDeferred, got %v", err)
+	}
+	if len(conn.calls) != 0 {
+		t.Errorf("expected no PlaceBid call while deferred, got %d", len(conn.calls))
+	}
+
+	qLen, err := rdb.LLen(context.Background(), QueueKeyFor("x")).Result()
+	if err != nil || qLen != 1 {
+		t.Fatalf("expected one queued bid, got len=%d err=%v", qLen, err)
+	}
+}
+
+func TestResumableBidder_FailsImmediatelyWhenOpenWithoutOnRecovery(t *testing.T) {
+	rdb := newResumableTestRedisClient(t)
+	conn := newFakeSmartConnector()
+	conn.breaker.SetThresholds(1, 1, 500*time.Millisecond, time.Minute)
+	conn.breaker.RecordFailure(10 * time.Millisecond)
+
+	rb := NewResumableBidder("x", conn, rdb, QueueKeyFor("x"))
+
+	_, err := rb.PlaceBid(context.Background(), &BidRequest{CustomerID: "c1", BidAmount: 5}, BidOptions{})
+	if err != shield.ErrCircuitOpen {
+		t.Fatalf("expected shield.ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestResumableBidder_DrainNoOpsUntilCircuitFullyCloses(t *testing.T) {
+	rdb := newResumableTestRedisClient(t)
+	conn := newFakeSmartConnector()
+	conn.breaker.SetThresholds(1, 1, 500*time.Millisecond, time.Minute)
+	conn.breaker.RecordFailure(10 * time.Millisecond) // OPEN
+
+	rb := NewResumableBidder("x", conn, rdb, QueueKeyFor("x"))
+	ctx := context.Background()
+	if _, err := rb.PlaceBid(ctx, &BidRequest{CustomerID: "c1", BidAmount: 5}, BidOptions{
+		OnRecovery: func(*BidResponse, error) {},
+	}); err != ErrDeferred {
+		t.Fatalf("expected ErrDeferred, got %v", err)
+	}
+
+	if err := rb.Drain(ctx); err != nil {
+		t.Fatalf("unexpected Drain error: %v", err)
+	}
+	qLen, _ := rdb.LLen(ctx, QueueKeyFor("x")).Result()
+	if qLen != 1 {
+		t.Errorf("expected Drain to leave the queued bid alone while circuit isn't CLOSED, got len=%d", qLen)
+	}
+}
+
+func TestResumableBidder_DrainPlacesQueuedBidOnceCircuitCloses(t *testing.T) {
+	rdb := newResumableTestRedisClient(t)
+	conn := newFakeSmartConnector()
+	conn.breaker.SetThresholds(1, 1, 500*time.Millisecond, 10*time.Millisecond)
+	conn.breaker.RecordFailure(10 * time.Millisecond) // OPEN
+
+	rb := NewResumableBidder("x", conn, rdb, QueueKeyFor("x"))
+	ctx := context.Background()
+
+	var gotResp *BidResponse
+	var gotErr error
+	done := make(chan struct{})
+	if _, err := rb.PlaceBid(ctx, &BidRequest{CustomerID: "c1", BidAmount: 5}, BidOptions{
+		OnRecovery: func(resp *BidResponse, err error) {
+			gotResp, gotErr = resp, err
+			close(done)
+		},
+	}); err != ErrDeferred {
+		t.Fatalf("expected ErrDeferred, got %v", err)
+	}
+
+	// Let the breaker's reset timeout elapse, then probe it back to CLOSED.
+	time.Sleep(15 * time.Millisecond)
+	if !conn.breaker.CanExecute() {
+		t.Fatalf("expected breaker to allow a HALF_OPEN probe after resetTimeout")
+	}
+	conn.breaker.RecordSuccess(5 * time.Millisecond)
+	if conn.breaker.GetState() != shield.CLOSED {
+		t.Fatalf("expected breaker to be CLOSED after a successful probe, got %v", conn.breaker.GetState())
+	}
+
+	if err := rb.Drain(ctx); err != nil {
+		t.Fatalf("unexpected Drain error: %v", err)
+	}
+	<-done
+
+	if gotErr != nil {
+		t.Fatalf("expected the deferred bid to succeed, got err=%v", gotErr)
+	}
+	if gotResp == nil || gotResp.BidID != "BID_1" {
+		t.Errorf("expected OnRecovery to receive the connector's response, got %+v", gotResp)
+	}
+	if len(conn.calls) != 1 {
+		t.Errorf("expected exactly one PlaceBid call from Drain, got %d", len(conn.calls))
+	}
+}
+
+func TestResumableBidder_DeadlineExpiredBidSkipsPlacementAndTimesOut(t *testing.T) {
+	rdb := newResumableTestRedisClient(t)
+	conn := newFakeSmartConnector()
+	conn.breaker.SetThresholds(1, 1, 500*time.Millisecond, 10*time.Millisecond)
+	conn.breaker.RecordFailure(10 * time.Millisecond) // OPEN
+
+	rb := NewResumableBidder("x", conn, rdb, QueueKeyFor("x"))
+	ctx := context.Background()
+
+	var gotErr error
+	done := make(chan struct{})
+	if _, err := rb.PlaceBid(ctx, &BidRequest{CustomerID: "c1", BidAmount: 5}, BidOptions{
+		Deadline: time.Now().Add(-time.Second), // already expired
+		OnRecovery: func(resp *BidResponse, err error) {
+			gotErr = err
+			close(done)
+		},
+	}); err != ErrDeferred {
+		t.Fatalf("expected ErrDeferred, got %v", err)
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	conn.breaker.CanExecute()
+	conn.breaker.RecordSuccess(5 * time.Millisecond)
+
+	if err := rb.Drain(ctx); err != nil {
+		t.Fatalf("unexpected Drain error: %v", err)
+	}
+	<-done
+
+	if gotErr != ErrDeferredTimeout {
+		t.Fatalf("expected ErrDeferredTimeout, got %v", gotErr)
+	}
+	if len(conn.calls) != 0 {
+		t.Errorf("expected a timed-out bid to never reach PlaceBid, got %d calls", len(conn.calls))
+	}
+}
+
+func TestResumableBidder_DuplicateDrainDoesNotDoubleSpend(t *testing.T) {
+	rdb := newResumableTestRedisClient(t)
+	conn := newFakeSmartConnector()
+
+	rb := NewResumableBidder("x", conn, rdb, QueueKeyFor("x"))
+	ctx := context.Background()
+
+	req := &BidRequest{CustomerID: "c1", BidAmount: 5, RequestID: "fixed-id"}
+	if err := rb.enqueue(ctx, req, BidOptions{OnRecovery: func(*BidResponse, error) {}}); err != nil {
+		t.Fatalf("enqueue failed: %v", err)
+	}
+	// Simulate a second worker re-enqueuing the exact same bid (e.g. a
+	// crash-and-retry), so the same idempotency key is drained twice.
+	if err := rb.enqueue(ctx, req, BidOptions{OnRecovery: func(*BidResponse, error) {}}); err != nil {
+		t.Fatalf("enqueue failed: %v", err)
+	}
+
+	if err := rb.Drain(ctx); err != nil {
+		t.Fatalf("unexpected Drain error: %v", err)
+	}
+
+	if len(conn.calls) != 1 {
+		t.Errorf("expected the claim key to prevent a second PlaceBid for the same idempotency key, got %d calls", len(conn.calls))
+	}
+}
+
+func TestQueueKeyFor_ScopesKeyPerPlatform(t *testing.T) {
+	if got, want := QueueKeyFor("x"), fmt.Sprintf("%s:x", DefaultQueueKey); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
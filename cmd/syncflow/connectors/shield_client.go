@@ -0,0 +1,33 @@
+package connectors
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// defaultConsentPurpose is the compliance.ConsentType smart connectors check
+// against when placing a programmatic bid, unless the caller supplies one.
+const defaultConsentPurpose = "targeting"
+
+// checkShieldConsent asks SyncShield's /check endpoint whether customerID has
+// an active consent grant for purpose before a smart connector places a bid.
+// It reuses the same Governor endpoint the legacy budget check calls, so a
+// non-2xx response can also mean an LTV/budget veto, not only missing
+// consent - either way the bid must not go out.
+func checkShieldConsent(shieldURL, customerID, purpose string, predictedLTV float64) (bool, error) {
+	q := url.Values{}
+	q.Set("customer_id", customerID)
+	q.Set("purpose", purpose)
+	q.Set("ltv", fmt.Sprintf("%.2f", predictedLTV))
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get(shieldURL + "/check?" + q.Encode())
+	if err != nil {
+		return false, fmt.Errorf("shield consent check: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK, nil
+}
@@ -0,0 +1,328 @@
+// Package signing replaces ad-platform credential smuggling (?api_key=...,
+// ?access_token=... query params, logged by proxies, cached by CDNs, and
+// leaked via the Referer header) with per-vendor request signing applied
+// through a shared http.RoundTripper.
+package signing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signer computes whatever headers (or, where a vendor's wire format
+// demands it, query parameters) a request needs to authenticate, and sets
+// them directly on req.
+type Signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+// SigningTransport wraps a base http.RoundTripper, signing every outbound
+// request with Signer before it's sent. If ResponseTimestampHeader is set
+// and the vendor echoes a timestamp back in the response, requests whose
+// response timestamp falls outside SkewWindow of now are rejected as
+// potential replays.
+type SigningTransport struct {
+	Signer                  Signer
+	Base                    http.RoundTripper
+	SkewWindow              time.Duration
+	ResponseTimestampHeader string
+}
+
+// NewSigningTransport creates a SigningTransport. A nil base falls back to
+// http.DefaultTransport.
+func NewSigningTransport(signer Signer, base http.RoundTripper, skewWindow time.Duration) *SigningTransport {
+	return &SigningTransport{
+		Signer:                  signer,
+		Base:                    base,
+		SkewWindow:              skewWindow,
+		ResponseTimestampHeader: "X-Timestamp",
+	}
+}
+
+func (t *SigningTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip signs req, sends it, then checks the response for a replayed
+// timestamp before returning it to the caller.
+func (t *SigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readAndRestoreBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("signing: read request body: %w", err)
+	}
+
+	if err := t.Signer.Sign(req, body); err != nil {
+		return nil, fmt.Errorf("signing: %w", err)
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := t.checkReplayWindow(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// checkReplayWindow rejects a response whose echoed timestamp falls
+// outside SkewWindow of now - guarding against a captured response being
+// replayed well after it was originally served.
+func (t *SigningTransport) checkReplayWindow(resp *http.Response) error {
+	if t.SkewWindow <= 0 || t.ResponseTimestampHeader == "" {
+		return nil
+	}
+	raw := resp.Header.Get(t.ResponseTimestampHeader)
+	if raw == "" {
+		return nil
+	}
+	unix, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return fmt.Errorf("signing: invalid response timestamp %q: %w", raw, err)
+	}
+	skew := time.Since(time.Unix(unix, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > t.SkewWindow {
+		return fmt.Errorf("signing: response timestamp %d outside %s skew window, possible replay", unix, t.SkewWindow)
+	}
+	return nil
+}
+
+func readAndRestoreBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	return body, nil
+}
+
+// HMACSigner implements the Bybit/AWS-style canonical-string HMAC-SHA256
+// scheme: timestamp + method + path + sortedQuery + sha256(body), signed
+// with HMAC-SHA256 and injected as X-Signature/X-Timestamp/X-Api-Key.
+type HMACSigner struct {
+	APIKey string
+	Secret string
+
+	// Now is overridable by tests; nil uses time.Now.
+	Now func() time.Time
+}
+
+// NewHMACSigner creates an HMACSigner keyed by apiKey/secret.
+func NewHMACSigner(apiKey, secret string) *HMACSigner {
+	return &HMACSigner{APIKey: apiKey, Secret: secret}
+}
+
+// NewTradeDeskSigner builds the canonical HMAC-SHA256 signer The Trade
+// Desk's partner API expects, with the partner ID standing in for the API
+// key in the X-Api-Key header.
+func NewTradeDeskSigner(partnerID, secret string) *HMACSigner {
+	return NewHMACSigner(partnerID, secret)
+}
+
+func (s *HMACSigner) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// Sign computes the canonical string and HMAC-SHA256 signature for req.
+func (s *HMACSigner) Sign(req *http.Request, body []byte) error {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	canonical := canonicalString(timestamp, req.Method, req.URL.Path, req.URL.Query(), body)
+
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write([]byte(canonical))
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	req.Header.Set("X-Signature", signature)
+	req.Header.Set("X-Timestamp", timestamp)
+	req.Header.Set("X-Api-Key", s.APIKey)
+	return nil
+}
+
+func canonicalString(timestamp, method, path string, query map[string][]string, body []byte) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sortedQuery strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sortedQuery.WriteByte('&')
+		}
+		sortedQuery.WriteString(k)
+		sortedQuery.WriteByte('=')
+		sortedQuery.WriteString(query[k][0])
+	}
+
+	bodyHash := sha256.Sum256(body)
+	return timestamp + method + path + sortedQuery.String() + hex.EncodeToString(bodyHash[:])
+}
+
+// MetaSigner computes Meta Graph API's appsecret_proof: an HMAC-SHA256 of
+// the access token keyed by the app secret. It carries the access token
+// as an Authorization: Bearer header rather than a query parameter, so
+// neither credential is logged or leaked via the Referer header.
+type MetaSigner struct {
+	AccessToken string
+	AppSecret   string
+}
+
+// NewMetaSigner creates a MetaSigner. An empty AppSecret still signs the
+// bearer header but omits the appsecret_proof binding.
+func NewMetaSigner(accessToken, appSecret string) *MetaSigner {
+	return &MetaSigner{AccessToken: accessToken, AppSecret: appSecret}
+}
+
+// Sign sets the Authorization header and, when AppSecret is configured,
+// the X-AppSecret-Proof header binding the token to this app.
+func (s *MetaSigner) Sign(req *http.Request, body []byte) error {
+	req.Header.Set("Authorization", "Bearer "+s.AccessToken)
+	if s.AppSecret == "" {
+		return nil
+	}
+	mac := hmac.New(sha256.New, []byte(s.AppSecret))
+	mac.Write([]byte(s.AccessToken))
+	req.Header.Set("X-AppSecret-Proof", hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
+
+// TikTokSigner attaches the Access-Token header every TikTok Business API
+// call requires and, when Secret is set, binds it to the request body via
+// an HMAC-SHA256 X-Signature header - mirroring MetaSigner's
+// appsecret_proof binding, adapted to TikTok's header names.
+type TikTokSigner struct {
+	AccessToken string
+	Secret      string
+}
+
+// NewTikTokSigner creates a TikTokSigner. An empty Secret still sets
+// Access-Token but omits the X-Signature binding.
+func NewTikTokSigner(accessToken, secret string) *TikTokSigner {
+	return &TikTokSigner{AccessToken: accessToken, Secret: secret}
+}
+
+// Sign sets the Access-Token header and, when Secret is configured, the
+// X-Signature header binding this request's body to it.
+func (s *TikTokSigner) Sign(req *http.Request, body []byte) error {
+	req.Header.Set("Access-Token", s.AccessToken)
+	if s.Secret == "" {
+		return nil
+	}
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write(body)
+	req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
+
+// ShopifySigner verifies the X-Shopify-Hmac-Sha256 header Shopify attaches
+// to inbound webhooks. Shopify's Admin API already authenticates outbound
+// calls via the X-Shopify-Access-Token header, so ShopifySigner covers the
+// other direction - confirming a webhook body actually came from Shopify -
+// and doesn't implement Signer.
+type ShopifySigner struct {
+	WebhookSecret string
+}
+
+// NewShopifySigner creates a ShopifySigner for the shop's webhook secret.
+func NewShopifySigner(webhookSecret string) *ShopifySigner {
+	return &ShopifySigner{WebhookSecret: webhookSecret}
+}
+
+// VerifyWebhook reports whether signatureHeader (the raw value of the
+// X-Shopify-Hmac-Sha256 header) matches the base64 HMAC-SHA256 of body
+// under WebhookSecret.
+func (s *ShopifySigner) VerifyWebhook(body []byte, signatureHeader string) bool {
+	mac := hmac.New(sha256.New, []byte(s.WebhookSecret))
+	mac.Write(body)
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signatureHeader))
+}
+
+// HubSpotWebhookSigner verifies the X-HubSpot-Signature-v3 header HubSpot
+// attaches to inbound webhooks: HMAC-SHA256 over method+URI+body+timestamp,
+// base64-encoded, keyed by the app's client secret. Like ShopifySigner, it
+// covers the inbound direction and doesn't implement Signer.
+type HubSpotWebhookSigner struct {
+	ClientSecret string
+}
+
+// NewHubSpotWebhookSigner creates a HubSpotWebhookSigner for the app's
+// client secret.
+func NewHubSpotWebhookSigner(clientSecret string) *HubSpotWebhookSigner {
+	return &HubSpotWebhookSigner{ClientSecret: clientSecret}
+}
+
+// VerifyWebhook reports whether signatureHeader (the raw value of the
+// X-HubSpot-Signature-v3 header) matches the base64 HMAC-SHA256 of
+// method+uri+body+timestamp under ClientSecret.
+func (s *HubSpotWebhookSigner) VerifyWebhook(method, uri string, body []byte, timestamp, signatureHeader string) bool {
+	mac := hmac.New(sha256.New, []byte(s.ClientSecret))
+	mac.Write([]byte(method + uri + string(body) + timestamp))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signatureHeader))
+}
+
+// GenericWebhookSigner verifies a hex HMAC-SHA256 signature over the raw
+// body under a shared secret - the fallback scheme for providers (e.g. a
+// Salesforce Outbound Message relay) without a documented webhook signature
+// format of their own.
+type GenericWebhookSigner struct {
+	Secret string
+}
+
+// NewGenericWebhookSigner creates a GenericWebhookSigner for the shared
+// secret.
+func NewGenericWebhookSigner(secret string) *GenericWebhookSigner {
+	return &GenericWebhookSigner{Secret: secret}
+}
+
+// VerifyWebhook reports whether signatureHeader matches the hex
+// HMAC-SHA256 of body under Secret.
+func (s *GenericWebhookSigner) VerifyWebhook(body []byte, signatureHeader string) bool {
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signatureHeader))
+}
+
+// MockSigner sets deterministic headers for tests, recording every request
+// it signs so assertions can count calls without inspecting HTTP traffic.
+type MockSigner struct {
+	Calls int
+}
+
+// Sign implements Signer with fixed, inspectable header values.
+func (m *MockSigner) Sign(req *http.Request, body []byte) error {
+	m.Calls++
+	req.Header.Set("X-Signature", "mock-signature")
+	req.Header.Set("X-Timestamp", "0")
+	req.Header.Set("X-Api-Key", "mock-key")
+	return nil
+}
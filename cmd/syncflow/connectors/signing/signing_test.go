@@ -0,0 +1,186 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHMACSigner_InjectsSignatureTimestampAndAPIKeyHeaders(t *testing.T) {
+	fixed := time.Unix(1700000000, 0)
+	signer := &HMACSigner{APIKey: "partner-1", Secret: "shh", Now: func() time.Time { return fixed }}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example.com/v3/bids?b=2&a=1", bytes.NewReader([]byte(`{"x":1}`)))
+	if err := signer.Sign(req, []byte(`{"x":1}`)); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	if req.Header.Get("X-Api-Key") != "partner-1" {
+		t.Fatalf("expected X-Api-Key to carry the configured key, got %q", req.Header.Get("X-Api-Key"))
+	}
+	if req.Header.Get("X-Timestamp") != "1700000000" {
+		t.Fatalf("expected X-Timestamp %q, got %q", "1700000000", req.Header.Get("X-Timestamp"))
+	}
+
+	bodyHash := sha256.Sum256([]byte(`{"x":1}`))
+	wantCanonical := "1700000000" + "POST" + "/v3/bids" + "a=1&b=2" + hex.EncodeToString(bodyHash[:])
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write([]byte(wantCanonical))
+	wantSig := hex.EncodeToString(mac.Sum(nil))
+
+	if req.Header.Get("X-Signature") != wantSig {
+		t.Fatalf("expected X-Signature %q, got %q", wantSig, req.Header.Get("X-Signature"))
+	}
+}
+
+func TestHMACSigner_SortsQueryParamsIntoCanonicalString(t *testing.T) {
+	fixed := time.Unix(1, 0)
+	signerA := &HMACSigner{APIKey: "k", Secret: "s", Now: func() time.Time { return fixed }}
+	signerB := &HMACSigner{APIKey: "k", Secret: "s", Now: func() time.Time { return fixed }}
+
+	reqA, _ := http.NewRequest(http.MethodGet, "https://x.test/path?z=1&a=2", nil)
+	reqB, _ := http.NewRequest(http.MethodGet, "https://x.test/path?a=2&z=1", nil)
+
+	signerA.Sign(reqA, nil)
+	signerB.Sign(reqB, nil)
+
+	if reqA.Header.Get("X-Signature") != reqB.Header.Get("X-Signature") {
+		t.Fatal("expected query param order not to affect the canonical signature")
+	}
+}
+
+func TestSigningTransport_RoundTripSignsAndRejectsStaleTimestamp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") == "" {
+			t.Errorf("expected the request to arrive signed")
+		}
+		w.Header().Set("X-Timestamp", strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewSigningTransport(NewHMACSigner("key", "secret"), http.DefaultTransport, time.Minute)
+	client := &http.Client{Transport: transport}
+
+	_, err := client.Get(server.URL)
+	if err == nil {
+		t.Fatal("expected a stale echoed timestamp outside the skew window to be rejected")
+	}
+}
+
+func TestSigningTransport_AllowsResponseWithFreshTimestamp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewSigningTransport(NewHMACSigner("key", "secret"), http.DefaultTransport, time.Minute)
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected a fresh echoed timestamp to be accepted, got: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestMetaSigner_SetsBearerHeaderAndAppSecretProof(t *testing.T) {
+	signer := NewMetaSigner("tok123", "secret")
+	req, _ := http.NewRequest(http.MethodPost, "https://graph.example.com/campaigns", nil)
+
+	if err := signer.Sign(req, nil); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if req.Header.Get("Authorization") != "Bearer tok123" {
+		t.Fatalf("expected Authorization bearer header, got %q", req.Header.Get("Authorization"))
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("tok123"))
+	want := hex.EncodeToString(mac.Sum(nil))
+	if req.Header.Get("X-AppSecret-Proof") != want {
+		t.Fatalf("expected X-AppSecret-Proof %q, got %q", want, req.Header.Get("X-AppSecret-Proof"))
+	}
+}
+
+func TestMetaSigner_OmitsProofWhenAppSecretUnset(t *testing.T) {
+	signer := NewMetaSigner("tok123", "")
+	req, _ := http.NewRequest(http.MethodPost, "https://graph.example.com/campaigns", nil)
+
+	signer.Sign(req, nil)
+	if req.Header.Get("X-AppSecret-Proof") != "" {
+		t.Fatal("expected no appsecret_proof header when AppSecret is unset")
+	}
+}
+
+func TestTikTokSigner_SetsAccessTokenAndSignatureHeaders(t *testing.T) {
+	signer := NewTikTokSigner("tok123", "secret")
+	req, _ := http.NewRequest(http.MethodPost, "https://business-api.tiktok.com/open_api/v1.3/campaign/update/", nil)
+	body := []byte(`{"campaign_id":"c1"}`)
+
+	if err := signer.Sign(req, body); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if req.Header.Get("Access-Token") != "tok123" {
+		t.Fatalf("expected Access-Token header, got %q", req.Header.Get("Access-Token"))
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	want := hex.EncodeToString(mac.Sum(nil))
+	if req.Header.Get("X-Signature") != want {
+		t.Fatalf("expected X-Signature %q, got %q", want, req.Header.Get("X-Signature"))
+	}
+}
+
+func TestTikTokSigner_OmitsSignatureWhenSecretUnset(t *testing.T) {
+	signer := NewTikTokSigner("tok123", "")
+	req, _ := http.NewRequest(http.MethodPost, "https://business-api.tiktok.com/open_api/v1.3/campaign/update/", nil)
+
+	signer.Sign(req, nil)
+	if req.Header.Get("X-Signature") != "" {
+		t.Fatal("expected no X-Signature header when Secret is unset")
+	}
+	if req.Header.Get("Access-Token") != "tok123" {
+		t.Fatalf("expected Access-Token header, got %q", req.Header.Get("Access-Token"))
+	}
+}
+
+func TestShopifySigner_VerifiesWebhookHMAC(t *testing.T) {
+	signer := NewShopifySigner("webhook-secret")
+	body := []byte(`{"id":123}`)
+
+	mac := hmac.New(sha256.New, []byte("webhook-secret"))
+	mac.Write(body)
+	validSig := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	if !signer.VerifyWebhook(body, validSig) {
+		t.Fatal("expected a correctly signed webhook body to verify")
+	}
+	if signer.VerifyWebhook(body, "tampered") {
+		t.Fatal("expected a mismatched signature to fail verification")
+	}
+}
+
+func TestMockSigner_CountsCallsAndSetsFixedHeaders(t *testing.T) {
+	mock := &MockSigner{}
+	req, _ := http.NewRequest(http.MethodGet, "https://x.test/", nil)
+
+	mock.Sign(req, nil)
+	mock.Sign(req, nil)
+
+	if mock.Calls != 2 {
+		t.Fatalf("expected 2 recorded calls, got %d", mock.Calls)
+	}
+	if req.Header.Get("X-Api-Key") != "mock-key" {
+		t.Fatalf("expected mock X-Api-Key header, got %q", req.Header.Get("X-Api-Key"))
+	}
+}
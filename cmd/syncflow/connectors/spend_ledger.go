@@ -0,0 +1,137 @@
+package connectors
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// LedgerEntry is one bid a SpendLedger has recorded, enough to both match
+// it against a platform's reporting API (BidID) and, if it turns out to
+// postdate a crash's last confirmed point, reconstruct the BidRequest
+// needed to requeue it through a ResumableBidder.
+type LedgerEntry struct {
+	BidID      string
+	Platform   string
+	CustomerID string
+	CampaignID string
+	AudienceID string
+	Amount     float64
+	PlacedAt   time.Time
+}
+
+// ReportingFetcher asks a platform's own reporting/billing API whether it
+// has a given bid on record - the other half of the reconciliation FindLCA
+// performs between "what we think we spent" and "what the platform
+// actually charged".
+type ReportingFetcher interface {
+	// Confirmed reports whether platform's reporting API has bidID as a
+	// charged/acknowledged bid.
+	Confirmed(ctx context.Context, platform, bidID string) (bool, error)
+}
+
+// SpendLedger durably records every bid a *SmartConnector's PlaceBid sends
+// to a platform, in placement order, so a crash that leaves local
+// BudgetManager spend diverged from what the platform actually charged can
+// be reconciled after the fact - modeled on chainlink's `blocks
+// find-lca`/`node remove-blocks` pair, but walking a spend ledger instead
+// of a chain of block headers. Any *SmartConnector can plug one in via its
+// SpendLedger field; a nil ledger (the default) simply skips recording,
+// same as a nil Recorder.
+type SpendLedger interface {
+	// Record appends entry to the ledger. Recording a BidID that's already
+	// present is a no-op - callers don't need to check first.
+	Record(ctx context.Context, entry LedgerEntry) error
+
+	// Recent returns up to limit of the most recently recorded entries for
+	// platform, newest first.
+	Recent(ctx context.Context, platform string, limit int) ([]LedgerEntry, error)
+
+	// After returns every entry recorded for platform strictly after
+	// afterBidID (by placement order), oldest first - the bids FindLCA
+	// couldn't confirm the platform charged, and Rewind requeues.
+	After(ctx context.Context, platform string, afterBidID string) ([]LedgerEntry, error)
+
+	// Forget removes every entry recorded for platform strictly after
+	// afterBidID, once Rewind has requeued them, so a repeated find-lca/
+	// rewind pass doesn't see them as still outstanding.
+	Forget(ctx context.Context, platform string, afterBidID string) error
+
+	Close() error
+}
+
+// FindLCA walks ledger's Recent entries for platform newest-first, asking
+// fetcher to confirm each one against the platform's own reporting API,
+// and returns the first (i.e. most recent) BidID both sides agree on - the
+// point a crash recovery can safely rewind local spend back to. found is
+// false if none of the last limit entries are confirmed; the platform's
+// reporting API may simply not have caught up yet, so callers should widen
+// limit and retry rather than assume nothing is reconcilable.
+func FindLCA(ctx context.Context, ledger SpendLedger, fetcher ReportingFetcher, platform string, limit int) (bidID string, found bool, err error) {
+	entries, err := ledger.Recent(ctx, platform, limit)
+	if err != nil {
+		return "", false, err
+	}
+	for _, e := range entries {
+		ok, err := fetcher.Confirmed(ctx, platform, e.BidID)
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			return e.BidID, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// Rewind rolls bm's CurrentSpend back to the state just after afterBidID:
+// every entry ledger recorded for platform strictly after it is subtracted
+// from bm and returned as diverged, for the caller to requeue through a
+// ResumableBidder - their fate is uncertain (the platform may or may not
+// have actually charged them), so they're retried rather than assumed
+// lost. The entries are then removed from ledger so repeating this pass
+// doesn't double-requeue them.
+func Rewind(ctx context.Context, ledger SpendLedger, bm *shield.BudgetManager, platform, afterBidID string) (diverged []LedgerEntry, err error) {
+	diverged, err = ledger.After(ctx, platform, afterBidID)
+	if err != nil {
+		return nil, err
+	}
+
+	var total float64
+	for _, e := range diverged {
+		total += e.Amount
+	}
+	if total > 0 {
+		bm.AdjustSpend(-total)
+	}
+
+	if err := ledger.Forget(ctx, platform, afterBidID); err != nil {
+		return diverged, err
+	}
+	return diverged, nil
+}
+
+// recordIfLedgered best-effort records resp in ledger for platform. A nil
+// ledger (the common case - not every connector needs reconciliation this
+// granular) or an unsuccessful bid is a no-op. A Record failure is logged
+// and swallowed, the same fire-and-forget treatment Recorder hooks get -
+// losing one ledger row shouldn't fail the bid that already succeeded.
+func recordIfLedgered(ctx context.Context, ledger SpendLedger, platform string, req *BidRequest, resp *BidResponse) {
+	if ledger == nil || resp == nil || !resp.Success {
+		return
+	}
+	entry := LedgerEntry{
+		BidID:      resp.BidID,
+		Platform:   platform,
+		CustomerID: req.CustomerID,
+		CampaignID: req.CampaignID,
+		AudienceID: req.AudienceID,
+		Amount:     resp.BidAmount,
+		PlacedAt:   resp.Timestamp,
+	}
+	if err := ledger.Record(ctx, entry); err != nil {
+		log.Printf("⚠️ SpendLedger(%s): failed to record bid %s: %v", platform, resp.BidID, err)
+	}
+}
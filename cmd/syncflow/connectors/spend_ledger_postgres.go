@@ -0,0 +1,139 @@
+package connectors
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq" // PostgreSQL driver, already used by crm.PostgreSQLConnector, auditsink.PostgresSink, and reconcile.PostgresStore
+)
+
+// spendLedgerSchema mirrors reconcile.PostgresStore's migrate-on-
+// construction pattern: no separate migration step is needed before
+// syncflow can start. seq, a separate identity column, is the primary key
+// rather than bid_id, because Recent/After/Forget all need to order and
+// slice by placement order, and bid_id alone (a platform-assigned string)
+// carries no ordering guarantee.
+const spendLedgerSchema = `
+CREATE TABLE IF NOT EXISTS spend_ledger (
+	seq         BIGSERIAL PRIMARY KEY,
+	bid_id      TEXT NOT NULL,
+	platform    TEXT NOT NULL,
+	customer_id TEXT NOT NULL,
+	campaign_id TEXT NOT NULL,
+	audience_id TEXT NOT NULL,
+	amount      DOUBLE PRECISION NOT NULL,
+	placed_at   TIMESTAMPTZ NOT NULL,
+	UNIQUE (platform, bid_id)
+);
+CREATE INDEX IF NOT EXISTS spend_ledger_platform_seq_idx ON spend_ledger (platform, seq DESC);
+`
+
+// PostgresSpendLedger persists a SpendLedger's bid history in the same
+// Postgres database as reconcile.PostgresStore - pass it the same DSN so a
+// bid's ledger row lives alongside the rest of syncflow's durable state
+// rather than in a separate store.
+type PostgresSpendLedger struct {
+	db *sql.DB
+}
+
+// NewPostgresSpendLedger opens dsn and ensures the spend_ledger table exists.
+func NewPostgresSpendLedger(dsn string) (*PostgresSpendLedger, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("connectors: opening postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connectors: pinging postgres: %w", err)
+	}
+	if _, err := db.Exec(spendLedgerSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connectors: migrating spend_ledger: %w", err)
+	}
+	return &PostgresSpendLedger{db: db}, nil
+}
+
+// Record implements SpendLedger.
+func (l *PostgresSpendLedger) Record(ctx context.Context, entry LedgerEntry) error {
+	_, err := l.db.ExecContext(ctx, `
+		INSERT INTO spend_ledger (bid_id, platform, customer_id, campaign_id, audience_id, amount, placed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (platform, bid_id) DO NOTHING`,
+		entry.BidID, entry.Platform, entry.CustomerID, entry.CampaignID, entry.AudienceID, entry.Amount, entry.PlacedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("connectors: recording bid %s: %w", entry.BidID, err)
+	}
+	return nil
+}
+
+// Recent implements SpendLedger.
+func (l *PostgresSpendLedger) Recent(ctx context.Context, platform string, limit int) ([]LedgerEntry, error) {
+	rows, err := l.db.QueryContext(ctx, `
+		SELECT bid_id, platform, customer_id, campaign_id, audience_id, amount, placed_at
+		FROM spend_ledger
+		WHERE platform = $1
+		ORDER BY seq DESC
+		LIMIT $2`,
+		platform, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connectors: listing recent bids for %s: %w", platform, err)
+	}
+	defer rows.Close()
+	return scanLedgerEntries(rows)
+}
+
+// After implements SpendLedger.
+func (l *PostgresSpendLedger) After(ctx context.Context, platform string, afterBidID string) ([]LedgerEntry, error) {
+	rows, err := l.db.QueryContext(ctx, `
+		SELECT bid_id, platform, customer_id, campaign_id, audience_id, amount, placed_at
+		FROM spend_ledger
+		WHERE platform = $1 AND seq > (
+			SELECT seq FROM spend_ledger WHERE platform = $1 AND bid_id = $2
+		)
+		ORDER BY seq ASC`,
+		platform, afterBidID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connectors: listing bids after %s for %s: %w", afterBidID, platform, err)
+	}
+	defer rows.Close()
+	return scanLedgerEntries(rows)
+}
+
+// Forget implements SpendLedger.
+func (l *PostgresSpendLedger) Forget(ctx context.Context, platform string, afterBidID string) error {
+	_, err := l.db.ExecContext(ctx, `
+		DELETE FROM spend_ledger
+		WHERE platform = $1 AND seq > (
+			SELECT seq FROM spend_ledger WHERE platform = $1 AND bid_id = $2
+		)`,
+		platform, afterBidID,
+	)
+	if err != nil {
+		return fmt.Errorf("connectors: forgetting bids after %s for %s: %w", afterBidID, platform, err)
+	}
+	return nil
+}
+
+// Close implements SpendLedger.
+func (l *PostgresSpendLedger) Close() error {
+	return l.db.Close()
+}
+
+// scanLedgerEntries drains rows of (bid_id, platform, customer_id,
+// campaign_id, audience_id, amount, placed_at) columns, shared by Recent
+// and After.
+func scanLedgerEntries(rows *sql.Rows) ([]LedgerEntry, error) {
+	var entries []LedgerEntry
+	for rows.Next() {
+		var e LedgerEntry
+		if err := rows.Scan(&e.BidID, &e.Platform, &e.CustomerID, &e.CampaignID, &e.AudienceID, &e.Amount, &e.PlacedAt); err != nil {
+			return nil, fmt.Errorf("connectors: scanning ledger entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,147 @@
+package connectors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// fakeSpendLedger is an in-memory SpendLedger test double, ordered by
+// insertion (Record append order), matching how PostgresSpendLedger orders
+// by its seq column.
+type fakeSpendLedger struct {
+	entries []LedgerEntry
+}
+
+func (f *fakeSpendLedger) Record(ctx context.Context, entry LedgerEntry) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeSpendLedger) Recent(ctx context.Context, platform string, limit int) ([]LedgerEntry, error) {
+	var matched []LedgerEntry
+	for i := len(f.entries) - 1; i >= 0; i-- {
+		if f.entries[i].Platform == platform {
+			matched = append(matched, f.entries[i])
+			if len(matched) == limit {
+				break
+			}
+		}
+	}
+	return matched, nil
+}
+
+func (f *fakeSpendLedger) After(ctx context.Context, platform string, afterBidID string) ([]LedgerEntry, error) {
+	var out []LedgerEntry
+	seenAfter := false
+	for _, e := range f.entries {
+		if e.Platform != platform {
+			continue
+		}
+		if seenAfter {
+			out = append(out, e)
+			continue
+		}
+		if e.BidID == afterBidID {
+			seenAfter = true
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSpendLedger) Forget(ctx context.Context, platform string, afterBidID string) error {
+	kept := f.entries[:0]
+	seenAfter := false
+	for _, e := range f.entries {
+		if e.Platform == platform {
+			if seenAfter {
+				continue
+			}
+			if e.BidID == afterBidID {
+				seenAfter = true
+			}
+		}
+		kept = append(kept, e)
+	}
+	f.entries = kept
+	return nil
+}
+
+func (f *fakeSpendLedger) Close() error { return nil }
+
+// fakeReportingFetcher confirms whatever BidIDs are listed in confirmed.
+type fakeReportingFetcher struct {
+	confirmed map[string]bool
+}
+
+func (f *fakeReportingFetcher) Confirmed(ctx context.Context, platform, bidID string) (bool, error) {
+	return f.confirmed[bidID], nil
+}
+
+func TestFindLCAReturnsNewestConfirmedBid(t *testing.T) {
+	ledger := &fakeSpendLedger{}
+	for _, id := range []string{"BID_1", "BID_2", "BID_3"} {
+		ledger.Record(context.Background(), LedgerEntry{BidID: id, Platform: "meta"})
+	}
+	fetcher := &fakeReportingFetcher{confirmed: map[string]bool{"BID_1": true, "BID_2": true}}
+
+	bidID, found, err := FindLCA(context.Background(), ledger, fetcher, "meta", 10)
+	if err != nil {
+		t.Fatalf("FindLCA returned error: %v", err)
+	}
+	if !found {
+		t.Fatal("expected a confirmed bid to be found")
+	}
+	if bidID != "BID_2" {
+		t.Errorf("expected newest confirmed bid BID_2, got %s", bidID)
+	}
+}
+
+func TestFindLCANotFoundWhenNoneConfirmed(t *testing.T) {
+	ledger := &fakeSpendLedger{}
+	ledger.Record(context.Background(), LedgerEntry{BidID: "BID_1", Platform: "meta"})
+	fetcher := &fakeReportingFetcher{confirmed: map[string]bool{}}
+
+	_, found, err := FindLCA(context.Background(), ledger, fetcher, "meta", 10)
+	if err != nil {
+		t.Fatalf("FindLCA returned error: %v", err)
+	}
+	if found {
+		t.Error("expected no confirmed bid to be found")
+	}
+}
+
+func TestRewindCorrectsSpendAndForgetsDivergedEntries(t *testing.T) {
+	ledger := &fakeSpendLedger{}
+	ctx := context.Background()
+	ledger.Record(ctx, LedgerEntry{BidID: "BID_1", Platform: "meta", Amount: 10})
+	ledger.Record(ctx, LedgerEntry{BidID: "BID_2", Platform: "meta", Amount: 15})
+	ledger.Record(ctx, LedgerEntry{BidID: "BID_3", Platform: "meta", Amount: 5})
+
+	bm := shield.NewBudgetManagerWithConfig(100.0, time.Minute, time.Second)
+	bm.AddSpend(30.0)
+
+	diverged, err := Rewind(ctx, ledger, bm, "meta", "BID_1")
+	if err != nil {
+		t.Fatalf("Rewind returned error: %v", err)
+	}
+	if len(diverged) != 2 {
+		t.Fatalf("expected 2 diverged entries, got %d", len(diverged))
+	}
+	if diverged[0].BidID != "BID_2" || diverged[1].BidID != "BID_3" {
+		t.Errorf("expected diverged entries in placement order [BID_2 BID_3], got %v", diverged)
+	}
+	if got := bm.GetCurrentSpend(); got != 10.0 {
+		t.Errorf("expected spend rewound to 10.0, got %.2f", got)
+	}
+
+	remaining, err := ledger.After(ctx, "meta", "BID_1")
+	if err != nil {
+		t.Fatalf("After returned error: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("expected Forget to remove diverged entries, %d remain", len(remaining))
+	}
+}
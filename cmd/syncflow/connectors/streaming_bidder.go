@@ -0,0 +1,426 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// StreamEventType identifies which OpenRTB-over-websocket event a decoded
+// frame represents.
+type StreamEventType string
+
+const (
+	StreamEventBidRequest StreamEventType = "bid_request"
+	StreamEventWin        StreamEventType = "win"
+	StreamEventLoss       StreamEventType = "loss"
+	StreamEventBilling    StreamEventType = "billing"
+)
+
+// StreamEvent is the parsed result of one inbound frame, produced by a
+// Stream's parser callback and handed to its dispatcher and typed
+// callbacks.
+type StreamEvent struct {
+	Type       StreamEventType
+	CampaignID string
+	BidRequest *BidRequest // set when Type == StreamEventBidRequest
+	BidID      string      // set for win/loss/billing events
+	Price      float64     // set for win/billing events
+}
+
+// StreamParser decodes one raw inbound websocket frame into a StreamEvent.
+type StreamParser func(frame []byte) (*StreamEvent, error)
+
+// StreamDispatcher handles one parsed StreamEvent - the streaming
+// counterpart of PlaceBid, applying the same safety checks before the
+// exchange is acknowledged.
+type StreamDispatcher func(ctx context.Context, event *StreamEvent) error
+
+const (
+	streamDefaultReadTimeout = 30 * time.Second
+	streamDefaultBackoffBase = 500 * time.Millisecond
+	streamDefaultBackoffMax  = 30 * time.Second
+	streamDefaultBufferDepth = 50
+	streamDefaultDialTimeout = 10 * time.Second
+)
+
+// Stream holds one persistent bid-stream connection to an RTB exchange,
+// modeled on the reconnect-with-replay design streaming market-data
+// clients use: a dedicated read loop, exponential-backoff reconnects,
+// heartbeat/ping supervision via ReadTimeout, and a per-campaign
+// depth-style buffer so bid requests queued while the socket is down are
+// replayed in order once it's healthy again instead of being lost.
+type Stream struct {
+	URL string
+
+	// ReadTimeout bounds how long the read loop waits for a frame
+	// (including a ping) before declaring the connection dead.
+	ReadTimeout time.Duration
+	// ReconnectBackoffBase/Max bound the exponential backoff between
+	// reconnect attempts.
+	ReconnectBackoffBase time.Duration
+	ReconnectBackoffMax  time.Duration
+	// BufferDepth caps how many queued bid requests each campaign's
+	// replay buffer holds before the oldest is dropped.
+	BufferDepth int
+
+	connLock   sync.Mutex
+	conn       *streamConn
+	connCtx    context.Context
+	connCancel context.CancelFunc
+
+	parser     StreamParser
+	dispatcher StreamDispatcher
+
+	onBidRequest func(event *StreamEvent)
+	onWin        func(event *StreamEvent)
+	onLoss       func(event *StreamEvent)
+	onBilling    func(event *StreamEvent)
+
+	bufferMu sync.Mutex
+	buffers  map[string][]*BidRequest // campaignID -> queued requests awaiting replay
+
+	wg sync.WaitGroup
+}
+
+// NewStream creates a Stream targeting a ws:// or wss:// URL, with
+// reconnect/heartbeat/buffer defaults suitable for a single busy exchange.
+func NewStream(streamURL string) *Stream {
+	return &Stream{
+		URL:                  streamURL,
+		ReadTimeout:          streamDefaultReadTimeout,
+		ReconnectBackoffBase: streamDefaultBackoffBase,
+		ReconnectBackoffMax:  streamDefaultBackoffMax,
+		BufferDepth:          streamDefaultBufferDepth,
+		buffers:              make(map[string][]*BidRequest),
+	}
+}
+
+func (s *Stream) SetParser(p StreamParser)                { s.parser = p }
+func (s *Stream) SetDispatcher(d StreamDispatcher)        { s.dispatcher = d }
+func (s *Stream) OnBidRequest(f func(event *StreamEvent)) { s.onBidRequest = f }
+func (s *Stream) OnWin(f func(event *StreamEvent))        { s.onWin = f }
+func (s *Stream) OnLoss(f func(event *StreamEvent))       { s.onLoss = f }
+func (s *Stream) OnBilling(f func(event *StreamEvent))    { s.onBilling = f }
+
+// Start dials the stream and runs its read/reconnect loop in the
+// background until Close is called.
+func (s *Stream) Start(ctx context.Context) {
+	s.connCtx, s.connCancel = context.WithCancel(ctx)
+	s.wg.Add(1)
+	go s.runLoop()
+}
+
+// Close stops the read loop and closes the underlying connection, if any.
+func (s *Stream) Close() error {
+	if s.connCancel != nil {
+		s.connCancel()
+	}
+
+	s.connLock.Lock()
+	conn := s.conn
+	s.connLock.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+
+	s.wg.Wait()
+	return nil
+}
+
+// Enqueue buffers req for campaignID's replay queue - for producers that
+// generate bid requests (or acknowledgements that failed to send) while
+// the stream is reconnecting. Once the socket is healthy again, queued
+// requests are dispatched in FIFO order. The buffer is bounded; past
+// BufferDepth, the oldest queued request is dropped.
+func (s *Stream) Enqueue(campaignID string, req *BidRequest) {
+	s.bufferMu.Lock()
+	defer s.bufferMu.Unlock()
+
+	buf := s.buffers[campaignID]
+	buf = append(buf, req)
+	if len(buf) > s.BufferDepth {
+		buf = buf[len(buf)-s.BufferDepth:]
+	}
+	s.buffers[campaignID] = buf
+}
+
+// IsConnected reports whether the stream currently holds a live socket.
+func (s *Stream) IsConnected() bool {
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
+	return s.conn != nil
+}
+
+// send writes payload to the live socket, if any.
+func (s *Stream) send(payload []byte) error {
+	s.connLock.Lock()
+	conn := s.conn
+	s.connLock.Unlock()
+	if conn == nil {
+		return fmt.Errorf("stream: not connected")
+	}
+	return conn.writeStreamMessage(payload)
+}
+
+func (s *Stream) runLoop() {
+	defer s.wg.Done()
+	backoff := s.ReconnectBackoffBase
+
+	for {
+		select {
+		case <-s.connCtx.Done():
+			return
+		default:
+		}
+
+		conn, err := dialStreamConn(s.URL, streamDefaultDialTimeout)
+		if err != nil {
+			log.Printf("⚠️  stream dial failed: %v (retrying in %s)", err, backoff)
+			if !s.sleepOrStop(backoff) {
+				return
+			}
+			backoff = nextStreamBackoff(backoff, s.ReconnectBackoffMax)
+			continue
+		}
+
+		s.connLock.Lock()
+		s.conn = conn
+		s.connLock.Unlock()
+		backoff = s.ReconnectBackoffBase
+
+		s.replayBuffered()
+
+		stopped := s.readUntilError(conn)
+
+		s.connLock.Lock()
+		s.conn = nil
+		s.connLock.Unlock()
+
+		if stopped {
+			return
+		}
+	}
+}
+
+func (s *Stream) sleepOrStop(d time.Duration) bool {
+	select {
+	case <-s.connCtx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+func nextStreamBackoff(current, max time.Duration) time.Duration {
+	next := current * 2
+	if next > max {
+		next = max
+	}
+	return next
+}
+
+// readUntilError drives conn's read loop, dispatching every parsed event,
+// until the socket errors/times out (heartbeat failure) or the stream is
+// asked to stop. It returns true only when the caller should stop
+// reconnecting entirely (Close was called).
+func (s *Stream) readUntilError(conn *streamConn) (stop bool) {
+	for {
+		select {
+		case <-s.connCtx.Done():
+			conn.Close()
+			return true
+		default:
+		}
+
+		if err := conn.SetReadDeadline(time.Now().Add(s.ReadTimeout)); err != nil {
+			return false
+		}
+		frame, err := conn.readStreamMessage()
+		if err != nil {
+			return false
+		}
+		if s.parser == nil {
+			continue
+		}
+		event, err := s.parser(frame)
+		if err != nil {
+			log.Printf("⚠️  stream parse error: %v", err)
+			continue
+		}
+		s.handleEvent(event)
+	}
+}
+
+func (s *Stream) handleEvent(event *StreamEvent) {
+	s.dispatch(event)
+
+	switch event.Type {
+	case StreamEventBidRequest:
+		if s.onBidRequest != nil {
+			s.onBidRequest(event)
+		}
+	case StreamEventWin:
+		if s.onWin != nil {
+			s.onWin(event)
+		}
+	case StreamEventLoss:
+		if s.onLoss != nil {
+			s.onLoss(event)
+		}
+	case StreamEventBilling:
+		if s.onBilling != nil {
+			s.onBilling(event)
+		}
+	}
+}
+
+func (s *Stream) dispatch(event *StreamEvent) {
+	if s.dispatcher == nil {
+		return
+	}
+	if err := s.dispatcher(s.connCtx, event); err != nil {
+		log.Printf("⚠️  stream dispatch error (campaign=%s): %v", event.CampaignID, err)
+	}
+}
+
+// replayBuffered flushes every campaign's queued bid requests through the
+// dispatcher/OnBidRequest callback, in FIFO order, once the socket has
+// just (re)connected.
+func (s *Stream) replayBuffered() {
+	s.bufferMu.Lock()
+	pending := s.buffers
+	s.buffers = make(map[string][]*BidRequest)
+	s.bufferMu.Unlock()
+
+	for campaignID, reqs := range pending {
+		for _, req := range reqs {
+			s.handleEvent(&StreamEvent{
+				Type:       StreamEventBidRequest,
+				CampaignID: campaignID,
+				BidRequest: req,
+			})
+		}
+	}
+}
+
+// StreamingBidder implements push-model OpenRTB bidding over a persistent
+// Stream, wiring in the same BudgetManager/RateLimiter/CircuitBreaker/
+// FallbackEngine safety stack TradeDeskSmartConnector applies to each
+// outbound POST, so budget vetoes and fallback decisions apply to
+// streamed impressions exactly as they do to request/response ones.
+type StreamingBidder struct {
+	Stream         *Stream
+	BudgetManager  *shield.BudgetManager
+	RateLimiter    *RateLimiter
+	CircuitBreaker *shield.CircuitBreaker
+	FallbackEngine *HeuristicFallbackEngine
+	Connected      bool
+
+	// SupportsCallbackURLs indicates the exchange accepts nurl/burl/lurl
+	// acknowledgements pushed back inline over the same socket, instead
+	// of being fetched separately.
+	SupportsCallbackURLs bool
+}
+
+// NewStreamingBidder creates a StreamingBidder dialing streamURL once
+// Connect is called.
+func NewStreamingBidder(streamURL string, maxBudget float64) *StreamingBidder {
+	sb := &StreamingBidder{
+		Stream:         NewStream(streamURL),
+		BudgetManager:  shield.NewBudgetManager(maxBudget),
+		RateLimiter:    NewRateLimiter(300),
+		CircuitBreaker: shield.NewCircuitBreaker(),
+		FallbackEngine: NewHeuristicFallbackEngine(),
+	}
+	sb.Stream.SetDispatcher(sb.dispatchBid)
+	return sb
+}
+
+// Connect starts the underlying Stream's reconnect loop.
+func (sb *StreamingBidder) Connect(ctx context.Context) error {
+	sb.Stream.Start(ctx)
+	sb.Connected = true
+	return nil
+}
+
+// Close stops the stream and tears down the connection.
+func (sb *StreamingBidder) Close() error {
+	sb.Connected = false
+	return sb.Stream.Close()
+}
+
+// GetStatus returns the connection status.
+func (sb *StreamingBidder) GetStatus() string {
+	if sb.Connected && sb.Stream.IsConnected() {
+		return "Connected to streaming bid exchange"
+	}
+	if sb.Connected {
+		return "Connected (reconnecting)"
+	}
+	return "Disconnected"
+}
+
+// dispatchBid is the Stream's dispatcher: it runs bid requests through
+// the same circuit-breaker/fallback/budget/rate-limit checks PlaceBid
+// does, then (for exchanges that support it) acknowledges the bid inline
+// with a nurl/burl/lurl callback over the same socket.
+func (sb *StreamingBidder) dispatchBid(ctx context.Context, event *StreamEvent) error {
+	if event.Type != StreamEventBidRequest || event.BidRequest == nil {
+		return nil
+	}
+	req := event.BidRequest
+
+	sb.FallbackEngine.RecordLTV("streaming", req.PredictedLTV)
+
+	bidAmount := req.BidAmount
+	decisionSource := "ai"
+	callStart := time.Now()
+	if !sb.CircuitBreaker.CanExecute() {
+		sb.CircuitBreaker.RecordFallback()
+		bidAmount = sb.FallbackEngine.CalculateFallbackBid("streaming", req.PredictedLTV)
+		decisionSource = "fallback"
+	}
+
+	if !sb.BudgetManager.CanSpend(bidAmount) {
+		sb.CircuitBreaker.RecordFailure(time.Since(callStart))
+		return fmt.Errorf("budget exceeded for campaign %s", event.CampaignID)
+	}
+	if !sb.RateLimiter.CanMakeCall() {
+		sb.CircuitBreaker.RecordFailure(time.Since(callStart))
+		return fmt.Errorf("rate limited for campaign %s", event.CampaignID)
+	}
+
+	sb.RateLimiter.RecordCall()
+	sb.BudgetManager.AddSpend(bidAmount)
+	sb.CircuitBreaker.RecordSuccess(time.Since(callStart))
+
+	if !sb.SupportsCallbackURLs {
+		return nil
+	}
+	return sb.sendCallback(event.CampaignID, bidAmount, decisionSource)
+}
+
+// sendCallback writes an OpenRTB-style nurl/burl/lurl acknowledgement
+// back over the stream's socket, for exchanges that accept it inline
+// instead of a separate HTTP fetch.
+func (sb *StreamingBidder) sendCallback(campaignID string, bidAmount float64, decisionSource string) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":        "ack",
+		"campaign_id": campaignID,
+		"price":       bidAmount,
+		"source":      decisionSource,
+		"nurl":        fmt.Sprintf("https://kiki-agent.com/win-notice?campaign=%s", campaignID),
+		"burl":        fmt.Sprintf("https://kiki-agent.com/billing?campaign=%s", campaignID),
+		"lurl":        fmt.Sprintf("https://kiki-agent.com/loss-notice?campaign=%s", campaignID),
+	})
+	if err != nil {
+		return err
+	}
+	return sb.Stream.send(payload)
+}
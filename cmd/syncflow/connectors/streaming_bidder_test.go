@@ -0,0 +1,107 @@
+package connectors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStream_ReplayBufferedFlushesAllQueuedRequests(t *testing.T) {
+	s := NewStream("ws://example.invalid/bids")
+
+	var dispatched []string
+	s.connCtx = context.Background()
+	s.SetDispatcher(func(ctx context.Context, event *StreamEvent) error {
+		dispatched = append(dispatched, event.CampaignID)
+		return nil
+	})
+
+	s.Enqueue("camp-a", &BidRequest{CampaignID: "camp-a", BidAmount: 1})
+	s.Enqueue("camp-b", &BidRequest{CampaignID: "camp-b", BidAmount: 2})
+	s.Enqueue("camp-a", &BidRequest{CampaignID: "camp-a", BidAmount: 3})
+
+	s.replayBuffered()
+
+	if len(dispatched) != 3 {
+		t.Fatalf("expected 3 replayed events, got %d", len(dispatched))
+	}
+
+	s.bufferMu.Lock()
+	remaining := len(s.buffers)
+	s.bufferMu.Unlock()
+	if remaining != 0 {
+		t.Fatalf("expected replay to drain the buffer, got %d campaigns still queued", remaining)
+	}
+}
+
+func TestStream_EnqueueDropsOldestPastBufferDepth(t *testing.T) {
+	s := NewStream("ws://example.invalid/bids")
+	s.BufferDepth = 2
+
+	s.Enqueue("camp-a", &BidRequest{BidAmount: 1})
+	s.Enqueue("camp-a", &BidRequest{BidAmount: 2})
+	s.Enqueue("camp-a", &BidRequest{BidAmount: 3})
+
+	s.bufferMu.Lock()
+	buf := s.buffers["camp-a"]
+	s.bufferMu.Unlock()
+
+	if len(buf) != 2 {
+		t.Fatalf("expected buffer capped at depth 2, got %d", len(buf))
+	}
+	if buf[0].BidAmount != 2 || buf[1].BidAmount != 3 {
+		t.Fatalf("expected the oldest entry to be dropped, got bid amounts %.0f, %.0f", buf[0].BidAmount, buf[1].BidAmount)
+	}
+}
+
+func TestStreamingBidder_DispatchBidVetoesWhenBudgetExceeded(t *testing.T) {
+	sb := NewStreamingBidder("ws://example.invalid/bids", 10.0)
+	sb.Stream.connCtx = context.Background()
+
+	err := sb.dispatchBid(context.Background(), &StreamEvent{
+		Type:       StreamEventBidRequest,
+		CampaignID: "camp-a",
+		BidRequest: &BidRequest{CampaignID: "camp-a", BidAmount: 100, PredictedLTV: 50},
+	})
+	if err == nil {
+		t.Fatal("expected dispatchBid to veto a bid that exceeds the remaining budget")
+	}
+}
+
+func TestStreamingBidder_DispatchBidFallsBackWhenCircuitOpen(t *testing.T) {
+	sb := NewStreamingBidder("ws://example.invalid/bids", 10000.0)
+	sb.Stream.connCtx = context.Background()
+
+	// Trip the circuit breaker open (default failureThreshold is 3).
+	for i := 0; i < 3; i++ {
+		sb.CircuitBreaker.RecordFailure(time.Second)
+	}
+
+	for i := 0; i < 10; i++ {
+		sb.FallbackEngine.RecordLTV("streaming", 40+float64(i))
+	}
+
+	err := sb.dispatchBid(context.Background(), &StreamEvent{
+		Type:       StreamEventBidRequest,
+		CampaignID: "camp-a",
+		BidRequest: &BidRequest{CampaignID: "camp-a", BidAmount: 9999, PredictedLTV: 45},
+	})
+	if err != nil {
+		t.Fatalf("expected the fallback bid to clear the budget check, got error: %v", err)
+	}
+	if sb.BudgetManager.GetCurrentSpend() >= 9999 {
+		t.Fatalf("expected a fallback bid well under the AI bid amount, got spend %.2f", sb.BudgetManager.GetCurrentSpend())
+	}
+}
+
+func TestStreamingBidder_GetStatusReflectsConnectionState(t *testing.T) {
+	sb := NewStreamingBidder("ws://example.invalid/bids", 100.0)
+	if got := sb.GetStatus(); got != "Disconnected" {
+		t.Fatalf("expected a fresh StreamingBidder to report Disconnected, got %q", got)
+	}
+
+	sb.Connected = true
+	if got := sb.GetStatus(); got != "Connected (reconnecting)" {
+		t.Fatalf("expected Connected without a live socket to report reconnecting, got %q", got)
+	}
+}
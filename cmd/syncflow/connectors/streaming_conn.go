@@ -0,0 +1,253 @@
+package connectors
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// streamWebsocketGUID is the RFC 6455 handshake magic value.
+const streamWebsocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	streamOpcodeContinuation = 0x0
+	streamOpcodeText         = 0x1
+	streamOpcodeBinary       = 0x2
+	streamOpcodeClose        = 0x8
+	streamOpcodePing         = 0x9
+	streamOpcodePong         = 0xA
+)
+
+// streamConn is a minimal RFC 6455 websocket client connection: just
+// enough handshake, framing, and ping/pong handling for a bid-stream
+// client to dial out to an RTB exchange, without depending on a
+// third-party websocket library.
+type streamConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// dialStreamConn performs the opening HTTP handshake against a ws:// or
+// wss:// URL and returns a connection ready for readStreamFrame/
+// writeStreamFrame.
+func dialStreamConn(rawURL string, handshakeTimeout time.Duration) (*streamConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stream URL: %w", err)
+	}
+
+	var conn net.Conn
+	host := u.Host
+	dialer := net.Dialer{Timeout: handshakeTimeout}
+	switch u.Scheme {
+	case "ws":
+		conn, err = dialer.Dial("tcp", host)
+	case "wss":
+		conn, err = tls.DialWithDialer(&dialer, "tcp", host, nil)
+	default:
+		return nil, fmt.Errorf("unsupported stream scheme: %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dial stream: %w", err)
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("generate websocket key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	path := u.RequestURI()
+	if path == "" {
+		path = "/"
+	}
+	req := "GET " + path + " HTTP/1.1\r\n" +
+		"Host: " + host + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if _, err := conn.Write([]byte(req)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("send handshake: %w", err)
+	}
+
+	r := bufio.NewReader(conn)
+	statusLine, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("read handshake status: %w", err)
+	}
+	if !strings.Contains(statusLine, "101") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected handshake response: %q", strings.TrimSpace(statusLine))
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("read handshake headers: %w", err)
+		}
+		if line == "\r\n" {
+			break
+		}
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &streamConn{conn: conn, r: r}, nil
+}
+
+// streamWebsocketAccept computes the Sec-WebSocket-Accept value for key,
+// used by tests exercising the handshake directly.
+func streamWebsocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + streamWebsocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func (c *streamConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+func (c *streamConn) Close() error {
+	writeStreamFrame(c.conn, streamOpcodeClose, nil)
+	return c.conn.Close()
+}
+
+// writeStreamMessage sends payload as a single masked text frame, as
+// RFC 6455 requires of every client->server frame.
+func (c *streamConn) writeStreamMessage(payload []byte) error {
+	return writeStreamFrame(c.conn, streamOpcodeText, payload)
+}
+
+func writeStreamFrame(w io.Writer, opcode byte, payload []byte) error {
+	var header []byte
+	length := len(payload)
+
+	b0 := byte(0x80) | opcode // FIN + opcode
+	switch {
+	case length <= 125:
+		header = []byte{b0, 0x80 | byte(length)}
+	case length <= 0xFFFF:
+		header = make([]byte, 4)
+		header[0] = b0
+		header[1] = 0x80 | 126
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header = make([]byte, 10)
+		header[0] = b0
+		header[1] = 0x80 | 127
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	maskKey := make([]byte, 4)
+	if _, err := rand.Read(maskKey); err != nil {
+		return err
+	}
+	header = append(header, maskKey...)
+
+	masked := make([]byte, length)
+	for i, b := range payload {
+		masked[i] = b ^ maskKey[i%4]
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if length > 0 {
+		if _, err := w.Write(masked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readStreamMessage reads frames from the server until a data frame
+// arrives. Server->client frames are never masked per the spec. Ping
+// frames are answered with a pong and skipped transparently, pong frames
+// are skipped, and a close frame is returned as io.EOF; the caller only
+// sees data frame payloads.
+func (c *streamConn) readStreamMessage() ([]byte, error) {
+	for {
+		opcode, payload, err := readStreamFrame(c.r)
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case streamOpcodePing:
+			if err := writeStreamFrame(c.conn, streamOpcodePong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case streamOpcodePong:
+			continue
+		case streamOpcodeClose:
+			return nil, io.EOF
+		default:
+			return payload, nil
+		}
+	}
+}
+
+func readStreamFrame(r *bufio.Reader) (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := int(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return 0, nil, err
+		}
+		length = int(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			return 0, nil, err
+		}
+		length = int(binary.BigEndian.Uint64(ext))
+	}
+
+	var maskKey []byte
+	if masked {
+		maskKey = make([]byte, 4)
+		if _, err := io.ReadFull(r, maskKey); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+
+	return opcode, payload, nil
+}
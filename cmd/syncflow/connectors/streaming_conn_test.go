@@ -0,0 +1,214 @@
+package connectors
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// acceptStreamHandshake performs just enough of the server side of the
+// RFC 6455 handshake for dialStreamConn's client to succeed, returning the
+// raw connection for the test to drive frames over.
+func acceptStreamHandshake(t *testing.T, conn net.Conn) {
+	t.Helper()
+	r := bufio.NewReader(conn)
+	if _, err := r.ReadString('\n'); err != nil {
+		t.Fatalf("read request line: %v", err)
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read headers: %v", err)
+		}
+		if line == "\r\n" {
+			break
+		}
+	}
+	if _, err := conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")); err != nil {
+		t.Fatalf("write handshake response: %v", err)
+	}
+}
+
+func TestDialStreamConn_CompletesHandshakeAndExchangesFrames(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	serverDone := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		acceptStreamHandshake(t, conn)
+
+		// Read the client's masked frame and reply with an unmasked one,
+		// as RFC 6455 requires of server->client frames.
+		r := bufio.NewReader(conn)
+		_, payload, err := readStreamFrame(r)
+		if err != nil {
+			t.Errorf("server read client frame: %v", err)
+			return
+		}
+		if string(payload) != "hello" {
+			t.Errorf("expected client payload %q, got %q", "hello", payload)
+		}
+		if err := writeServerFrame(conn, streamOpcodeText, []byte("world")); err != nil {
+			t.Errorf("server write reply: %v", err)
+		}
+	}()
+
+	conn, err := dialStreamConn("ws://"+ln.Addr().String()+"/bids", time.Second)
+	if err != nil {
+		t.Fatalf("dialStreamConn: %v", err)
+	}
+	defer conn.Close()
+
+	if err := conn.writeStreamMessage([]byte("hello")); err != nil {
+		t.Fatalf("writeStreamMessage: %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	reply, err := conn.readStreamMessage()
+	if err != nil {
+		t.Fatalf("readStreamMessage: %v", err)
+	}
+	if string(reply) != "world" {
+		t.Fatalf("expected reply %q, got %q", "world", reply)
+	}
+
+	<-serverDone
+}
+
+func TestDialStreamConn_RejectsNonUpgradeResponse(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		r.ReadString('\n')
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil || line == "\r\n" {
+				break
+			}
+		}
+		conn.Write([]byte("HTTP/1.1 404 Not Found\r\n\r\n"))
+	}()
+
+	if _, err := dialStreamConn("ws://"+ln.Addr().String()+"/bids", time.Second); err == nil {
+		t.Fatal("expected dialStreamConn to reject a non-101 response")
+	}
+}
+
+func TestReadStreamMessage_AutoReplysToPing(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	pongReceived := make(chan struct{})
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		acceptStreamHandshake(t, conn)
+
+		if err := writeServerFrame(conn, streamOpcodePing, []byte("ping-me")); err != nil {
+			t.Errorf("server write ping: %v", err)
+			return
+		}
+
+		r := bufio.NewReader(conn)
+		opcode, _, err := readStreamFrame(r)
+		if err != nil {
+			t.Errorf("server read pong: %v", err)
+			return
+		}
+		if opcode != streamOpcodePong {
+			t.Errorf("expected client to auto-reply with a pong frame, got opcode %d", opcode)
+		}
+		close(pongReceived)
+
+		writeServerFrame(conn, streamOpcodeText, []byte("after-ping"))
+	}()
+
+	conn, err := dialStreamConn("ws://"+ln.Addr().String()+"/bids", time.Second)
+	if err != nil {
+		t.Fatalf("dialStreamConn: %v", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	payload, err := conn.readStreamMessage()
+	if err != nil {
+		t.Fatalf("readStreamMessage: %v", err)
+	}
+	if string(payload) != "after-ping" {
+		t.Fatalf("expected the ping frame to be skipped and the next data frame returned, got %q", payload)
+	}
+
+	select {
+	case <-pongReceived:
+	case <-time.After(time.Second):
+		t.Fatal("expected server to receive a pong in response to its ping")
+	}
+}
+
+// writeServerFrame writes an unmasked frame, as a real exchange's server
+// would send to a client.
+func writeServerFrame(conn net.Conn, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode, byte(len(payload))}
+	if _, err := conn.Write(header); err != nil {
+		return err
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+	_, err := conn.Write(payload)
+	return err
+}
+
+func TestStreamWebsocketAccept_MatchesRFC6455Example(t *testing.T) {
+	// Example key/accept pair straight from RFC 6455 section 1.3.
+	got := streamWebsocketAccept("dGhlIHNhbXBsZSBub25jZQ==")
+	want := "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+	if got != want {
+		t.Fatalf("expected accept value %q, got %q", want, got)
+	}
+}
+
+func TestWriteStreamFrame_MasksClientPayload(t *testing.T) {
+	var buf strings.Builder
+	if err := writeStreamFrame(&buf, streamOpcodeText, []byte("secret")); err != nil {
+		t.Fatalf("writeStreamFrame: %v", err)
+	}
+	raw := []byte(buf.String())
+	if raw[1]&0x80 == 0 {
+		t.Fatal("expected the mask bit to be set on every client frame")
+	}
+	maskKey := raw[2:6]
+	masked := raw[6:]
+	for i, b := range masked {
+		if b^maskKey[i%4] != "secret"[i] {
+			t.Fatalf("unmasking byte %d did not recover the original payload", i)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// TikTokAPIResponse mirrors the code/message/request_id/data envelope every
+// TikTok Business API response shares, success or failure alike - Code is 0
+// on success, non-zero otherwise, with Message carrying the human-readable
+// reason. tiktokStatusForCode classifies Code into the HTTP-equivalent
+// status CallPolicy already knows how to retry on.
+type TikTokAPIResponse struct {
+	Code      int             `json:"code"`
+	Message   string          `json:"message"`
+	RequestID string          `json:"request_id"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// tiktokStatusForCode maps a TikTok response Code onto the HTTP status
+// CallPolicy's RetryableCodes already classifies, so PlaceBid/
+// UpdateCampaignBudget/UpdateTargetAudience don't need a second retry table
+// just because TikTok reports errors via a 200-wrapped code instead of the
+// HTTP status line.
+func tiktokStatusForCode(code int) int {
+	switch {
+	case code == 0:
+		return http.StatusOK
+	case code == tiktokCodeRateLimited:
+		return http.StatusTooManyRequests
+	case code >= tiktokCodeServerErrorFloor:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+const (
+	// tiktokCodeRateLimited is the code TikTok's Business API returns when
+	// an advertiser has exceeded its QPS allowance.
+	tiktokCodeRateLimited = 40100
+	// tiktokCodeServerErrorFloor is the lowest code TikTok reserves for its
+	// own internal errors; everything below it (other than 0) is a
+	// terminal, caller-side validation problem not worth retrying.
+	tiktokCodeServerErrorFloor = 50000
+)
+
+// CampaignUpdateRequest mirrors TikTok Business API's POST
+// /campaign/update/ body for the fields PlaceBid and UpdateCampaignBudget
+// touch.
+type CampaignUpdateRequest struct {
+	AdvertiserID string  `json:"advertiser_id"`
+	CampaignID   string  `json:"campaign_id"`
+	Budget       float64 `json:"budget"`
+	BudgetMode   string  `json:"budget_mode"`
+}
+
+// AdGroupUpdateRequest mirrors TikTok Business API's POST
+// /adgroup/update/ body for the fields UpdateTargetAudience touches.
+type AdGroupUpdateRequest struct {
+	AdvertiserID string   `json:"advertiser_id"`
+	AdgroupID    string   `json:"adgroup_id"`
+	AudienceIDs  []string `json:"audience_ids"`
+}
+
+// TikTokClient abstracts the TikTok Business API calls TikTokSmartConnector
+// needs, so tests can inject a fake instead of standing up network access -
+// the same role AdsClient plays for GoogleAdsSmartConnector.
+type TikTokClient interface {
+	UpdateCampaign(ctx context.Context, req *CampaignUpdateRequest) (*TikTokAPIResponse, int, error)
+	UpdateAdGroup(ctx context.Context, req *AdGroupUpdateRequest) (*TikTokAPIResponse, int, error)
+}
+
+// restTikTokClient is the default TikTokClient: it posts the typed requests
+// above as JSON against TikTok's Business API, with HttpClient's transport
+// responsible for the Access-Token header and request signing.
+type restTikTokClient struct {
+	BaseURL    string
+	HttpClient *http.Client
+}
+
+func (c *restTikTokClient) UpdateCampaign(ctx context.Context, req *CampaignUpdateRequest) (*TikTokAPIResponse, int, error) {
+	return c.post(ctx, c.BaseURL+"/campaign/update/", req)
+}
+
+func (c *restTikTokClient) UpdateAdGroup(ctx context.Context, req *AdGroupUpdateRequest) (*TikTokAPIResponse, int, error) {
+	return c.post(ctx, c.BaseURL+"/adgroup/update/", req)
+}
+
+func (c *restTikTokClient) post(ctx context.Context, url string, payload interface{}) (*TikTokAPIResponse, int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, 0, fmt.Errorf("marshal tiktok request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, fmt.Errorf("build tiktok request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	// Access-Token and any request-signing headers are attached by
+	// HttpClient's signing.SigningTransport, not here.
+
+	resp, err := c.HttpClient.Do(httpReq)
+	if err != nil {
+		return nil, 0, fmt.Errorf("tiktok request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read tiktok response: %w", err)
+	}
+
+	var envelope TikTokAPIResponse
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("decode tiktok response: %w", err)
+	}
+
+	status := resp.StatusCode
+	if status < 400 {
+		// TikTok reports its own errors through Code inside a 200, so the
+		// envelope's classification takes precedence over the transport
+		// status whenever the transport itself didn't already fail the call.
+		status = tiktokStatusForCode(envelope.Code)
+	}
+	return &envelope, status, nil
+}
@@ -0,0 +1,110 @@
+package connectors
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeTikTokClient is an injected TikTokClient that records the last
+// request it received instead of making a network call.
+type fakeTikTokClient struct {
+	lastCampaignReq *CampaignUpdateRequest
+	campaignResp    *TikTokAPIResponse
+	campaignStatus  int
+	campaignErr     error
+
+	lastAdGroupReq *AdGroupUpdateRequest
+	adGroupResp    *TikTokAPIResponse
+	adGroupStatus  int
+	adGroupErr     error
+}
+
+func (f *fakeTikTokClient) UpdateCampaign(ctx context.Context, req *CampaignUpdateRequest) (*TikTokAPIResponse, int, error) {
+	f.lastCampaignReq = req
+	return f.campaignResp, f.campaignStatus, f.campaignErr
+}
+
+func (f *fakeTikTokClient) UpdateAdGroup(ctx context.Context, req *AdGroupUpdateRequest) (*TikTokAPIResponse, int, error) {
+	f.lastAdGroupReq = req
+	return f.adGroupResp, f.adGroupStatus, f.adGroupErr
+}
+
+func TestTikTokSmartConnector_UpdateCampaignBudgetSendsBudgetUpdate(t *testing.T) {
+	fake := &fakeTikTokClient{campaignResp: &TikTokAPIResponse{Code: 0, RequestID: "req-1"}, campaignStatus: 200}
+	tt := NewTikTokSmartConnector("token", "advertiser-1", 1000)
+	tt.Client = fake
+	tt.Connected = true
+
+	resp, err := tt.UpdateCampaignBudget(context.Background(), "camp-1", 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got %+v", resp)
+	}
+
+	if fake.lastCampaignReq == nil || fake.lastCampaignReq.Budget != 50 {
+		t.Fatalf("expected a budget update request for 50, got %+v", fake.lastCampaignReq)
+	}
+}
+
+func TestTikTokSmartConnector_UpdateCampaignBudgetSurfacesClientError(t *testing.T) {
+	fake := &fakeTikTokClient{campaignErr: context.DeadlineExceeded}
+	tt := NewTikTokSmartConnector("token", "advertiser-1", 1000)
+	tt.Client = fake
+	tt.Connected = true
+
+	if _, err := tt.UpdateCampaignBudget(context.Background(), "camp-1", 50); err == nil {
+		t.Fatal("expected the TikTokClient error to propagate")
+	}
+}
+
+func TestTikTokSmartConnector_UpdateTargetAudienceSendsAudienceIDs(t *testing.T) {
+	fake := &fakeTikTokClient{adGroupResp: &TikTokAPIResponse{Code: 0, RequestID: "req-2"}, adGroupStatus: 200}
+	tt := NewTikTokSmartConnector("token", "advertiser-1", 1000)
+	tt.Client = fake
+	tt.Connected = true
+
+	resp, err := tt.UpdateTargetAudience(context.Background(), "adgroup-1", "audience-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got %+v", resp)
+	}
+	if fake.lastAdGroupReq == nil || len(fake.lastAdGroupReq.AudienceIDs) != 1 || fake.lastAdGroupReq.AudienceIDs[0] != "audience-1" {
+		t.Fatalf("expected audience IDs [audience-1], got %+v", fake.lastAdGroupReq)
+	}
+}
+
+func TestTikTokSmartConnector_PlaceBidTreatsNonZeroCodeAsFailure(t *testing.T) {
+	fake := &fakeTikTokClient{campaignResp: &TikTokAPIResponse{Code: 40001, Message: "invalid budget"}, campaignStatus: 400}
+	tt := NewTikTokSmartConnector("token", "advertiser-1", 1000)
+	tt.Client = fake
+	tt.Connected = true
+
+	resp, err := tt.PlaceBid(context.Background(), &BidRequest{CustomerID: "cust-1", CampaignID: "camp-1", BidAmount: 10, PredictedLTV: 100})
+	if err == nil {
+		t.Fatal("expected an error for a non-zero TikTok response code")
+	}
+	if resp.Success {
+		t.Fatalf("expected a failed BidResponse, got %+v", resp)
+	}
+}
+
+func TestTiktokStatusForCode(t *testing.T) {
+	cases := []struct {
+		code int
+		want int
+	}{
+		{0, 200},
+		{tiktokCodeRateLimited, 429},
+		{tiktokCodeServerErrorFloor, 500},
+		{40001, 400},
+	}
+	for _, c := range cases {
+		if got := tiktokStatusForCode(c.code); got != c.want {
+			t.Errorf("tiktokStatusForCode(%d): expected %d, got %d", c.code, c.want, got)
+		}
+	}
+}
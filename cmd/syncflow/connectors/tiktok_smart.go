@@ -1,148 +1,426 @@
-package connectors
-
-import (
-	"context"
-	"fmt"
-	"log"
-	"net/http"
-	"time"
-
-	"github.com/user/kiki-agent/cmd/syncshield/shield"
-)
-
-// TikTokSmartConnector implements PlatformConnector for TikTok Ads with budget management
-type TikTokSmartConnector struct {
-	AccessToken    string
-	AdvertiserID   string
-	HttpClient     *http.Client
-	Connected      bool
-	BaseURL        string
-	BudgetManager  *shield.BudgetManager
-	RateLimiter    *RateLimiter
-	CircuitBreaker *shield.CircuitBreaker
-	FallbackEngine *HeuristicFallbackEngine
-	MockMode       bool
-}
-
-// NewTikTokSmartConnector creates a new TikTok connector with budget management
-func NewTikTokSmartConnector(accessToken, advertiserID string, maxBudget float64) *TikTokSmartConnector {
-	return &TikTokSmartConnector{
-		AccessToken:    accessToken,
-		AdvertiserID:   advertiserID,
-		HttpClient:     &http.Client{Timeout: 10 * time.Second},
-		BaseURL:        "https://business-api.tiktok.com/open_api/v1.3",
-		BudgetManager:  shield.NewBudgetManager(maxBudget),
-		RateLimiter:    NewRateLimiter(1000),
-		CircuitBreaker: shield.NewCircuitBreaker(),
-		FallbackEngine: NewHeuristicFallbackEngine(),
-	}
-}
-
-// Connect establishes connection to TikTok Ads API
-func (t *TikTokSmartConnector) Connect(ctx context.Context) error {
-	log.Printf("🔗 Connecting to TikTok Ads Smart Connector for advertiser: %s", t.AdvertiserID)
-	t.Connected = true
-	stats := t.BudgetManager.GetStats()
-	log.Printf("✅ TikTok Smart connection established - Budget: $%.2f/$%.2f", stats.CurrentSpend, stats.MaxBudget)
-	return nil
-}
-
-// PlaceBid sends a bid to TikTok with safety checks
-func (t *TikTokSmartConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
-	if !t.Connected {
-		return nil, fmt.Errorf("not connected to TikTok Ads")
-	}
-
-	// Record latest LTV to improve fallback median quality
-	t.FallbackEngine.RecordLTV("tiktok", req.PredictedLTV)
-
-	// Decide bid source via circuit breaker
-	bidAmount := req.BidAmount
-	decisionSource := "ai"
-	if !t.CircuitBreaker.CanExecute() {
-		t.CircuitBreaker.RecordFallback()
-		bidAmount = t.FallbackEngine.CalculateFallbackBid("tiktok", req.PredictedLTV)
-		decisionSource = "fallback"
-	}
-
-	if !t.RateLimiter.CanMakeCall() {
-		return nil, fmt.Errorf("rate limit exceeded for TikTok Ads")
-	}
-
-	if !t.BudgetManager.CanSpend(bidAmount) {
-		stats := t.BudgetManager.GetStats()
-		log.Printf("🛡️ BUDGET VETO: TikTok bid $%.2f exceeds remaining budget $%.2f", bidAmount, stats.RemainingBudget)
-		return &BidResponse{
-			Success:      false,
-			Message:      fmt.Sprintf("Budget exceeded: $%.2f spent of $%.2f limit", stats.CurrentSpend, stats.MaxBudget),
-			PlatformCode: "BUDGET_EXCEEDED",
-			Timestamp:    time.Now(),
-		}, fmt.Errorf("budget exceeded")
-	}
-
-	log.Printf("📍 PlaceBid (TikTok): Customer=%s, LTV=%.2f, Bid=$%.2f (source=%s)", req.CustomerID, req.PredictedLTV, bidAmount, decisionSource)
-
-	callStart := time.Now()
-
-	if t.MockMode {
-		log.Printf("🧪 MOCK MODE: Simulating TikTok Ads API call")
-		t.RateLimiter.RecordCall()
-		t.BudgetManager.AddSpend(bidAmount)
-		stats := t.BudgetManager.GetStats()
-		log.Printf("✅ TikTok bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
-		t.CircuitBreaker.RecordSuccess(time.Since(callStart))
-
-		return &BidResponse{
-			Success:      true,
-			BidID:        fmt.Sprintf("MOCK_TIKTOK_%d", time.Now().Unix()),
-			Message:      "Dynamic creative campaign created via TikTok Ads API (MOCK)",
-			PlatformCode: "TIKTOK_ADS_SMART",
-			Timestamp:    time.Now(),
-		}, nil
-	}
-
-	t.RateLimiter.RecordCall()
-	t.BudgetManager.AddSpend(bidAmount)
-	stats := t.BudgetManager.GetStats()
-	log.Printf("✅ TikTok bid placed - Budget: $%.2f/$%.2f remaining", stats.RemainingBudget, stats.MaxBudget)
-	t.CircuitBreaker.RecordSuccess(time.Since(callStart))
-
-	return &BidResponse{
-		Success:      true,
-		BidID:        fmt.Sprintf("TIKTOK_%d", time.Now().Unix()),
-		Message:      "Bid sent to TikTok Ads API",
-		PlatformCode: "TIKTOK_ADS_SMART",
-		Timestamp:    time.Now(),
-	}, nil
-}
-
-// GetBudgetStats returns budget statistics
-func (t *TikTokSmartConnector) GetBudgetStats() shield.WindowStats {
-	return t.BudgetManager.GetStats()
-}
-
-// GetStatus returns connection status
-func (t *TikTokSmartConnector) GetStatus() string {
-	if t.Connected {
-		stats := t.BudgetManager.GetStats()
-		return fmt.Sprintf("Connected to TikTok Ads - Budget: $%.2f/$%.2f", stats.CurrentSpend, stats.MaxBudget)
-	}
-	return "Disconnected from TikTok Ads"
-}
-
-// Close closes the connection
-func (t *TikTokSmartConnector) Close() error {
-	log.Printf("🔌 TikTok connection closed - Final spend: $%.2f", t.BudgetManager.GetStats().CurrentSpend)
-	t.Connected = false
-	return nil
-}
-
-// UpdateCampaignBudget updates campaign budget
-func (t *TikTokSmartConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
-// UpdateTargetAudience updates target audience
-func (t *TikTokSmartConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
-	return nil, fmt.Errorf("not implemented")
-}
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/signing"
+	"github.com/user/kiki-agent/cmd/syncshield/observability"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// tiktokRateLimitQPS is the ~10 QPS per advertiser TikTok's Business API
+// documents; NewRateLimiter takes calls/minute, hence the *60.
+const tiktokRateLimitQPS = 10
+
+// TikTokSmartConnector implements PlatformConnector for TikTok Ads with budget management
+type TikTokSmartConnector struct {
+	AccessToken  string
+	AdvertiserID string
+	// Secret, when set, binds every signed request's body to AccessToken
+	// via an X-Signature header - see signing.TikTokSigner.
+	Secret         string
+	HttpClient     *http.Client
+	Connected      bool
+	BaseURL        string
+	BudgetManager  *shield.BudgetManager
+	RateLimiter    *RateLimiter
+	CircuitBreaker *shield.CircuitBreaker
+	FallbackEngine *HeuristicFallbackEngine
+	MockMode       bool
+	// Client issues the signed calls against TikTok's Business API;
+	// injectable so tests don't need network, the same role AdsClient
+	// plays for GoogleAdsSmartConnector.
+	Client TikTokClient
+
+	// PlaceBidPolicy, UpdateCampaignBudgetPolicy and
+	// UpdateTargetAudiencePolicy govern per-attempt retry/backoff for
+	// their respective calls, so a single transient TikTok server error
+	// doesn't kill a bid outright. A retry cycle counts as one logical
+	// call against CircuitBreaker; see CallPolicy.Execute.
+	PlaceBidPolicy             *CallPolicy
+	UpdateCampaignBudgetPolicy *CallPolicy
+	UpdateTargetAudiencePolicy *CallPolicy
+
+	// Recorders observe PlaceBid's decision points (budget veto, rate
+	// limit, fallback, success/failure) for operators who want to know why
+	// a bid was shaped the way it was, not just the circuit breaker's
+	// current state. Nil entries and a nil slice are both no-ops.
+	Recorders []Recorder
+
+	// Metrics is the CircuitBreaker's MetricsCollector, enabled by default
+	// so CallPolicy.Execute's per-attempt RecordFailure calls (and the
+	// cycle-level RecordSuccess/RecordFailure CircuitBreaker itself makes)
+	// surface TikTok latency and error-type counters without callers
+	// having to remember to opt in.
+	Metrics *shield.MetricsCollector
+
+	// ZLog receives structured diagnostics (customer_id, request_id,
+	// connector, budget_remaining, trace_id fields) in place of the
+	// package's historical log.Printf calls. Defaults to zerolog.Nop();
+	// override with NewLogger for production wiring.
+	ZLog zerolog.Logger
+
+	// SpendLedger, if set, records every successful bid so a crash can
+	// later be reconciled against TikTok's reporting API via
+	// connectors.FindLCA/Rewind. Left nil, bids simply aren't ledgered.
+	SpendLedger SpendLedger
+}
+
+// NewTikTokSmartConnector creates a new TikTok connector with budget management
+func NewTikTokSmartConnector(accessToken, advertiserID string, maxBudget float64) *TikTokSmartConnector {
+	baseURL := "https://business-api.tiktok.com/open_api/v1.3"
+	cb := shield.NewCircuitBreaker()
+
+	t := &TikTokSmartConnector{
+		AccessToken:    accessToken,
+		AdvertiserID:   advertiserID,
+		HttpClient:     &http.Client{Timeout: 10 * time.Second},
+		BaseURL:        baseURL,
+		BudgetManager:  shield.NewBudgetManager(maxBudget),
+		RateLimiter:    NewRateLimiter(tiktokRateLimitQPS * 60),
+		CircuitBreaker: cb,
+		FallbackEngine: NewHeuristicFallbackEngine(),
+		Metrics:        cb.EnableMetrics(),
+		ZLog:           zerolog.Nop(),
+
+		PlaceBidPolicy:             DefaultPlaceBidCallPolicy(),
+		UpdateCampaignBudgetPolicy: DefaultUpdateCampaignBudgetCallPolicy(),
+		UpdateTargetAudiencePolicy: DefaultUpdateTargetAudienceCallPolicy(),
+	}
+	t.HttpClient.Transport = signing.NewSigningTransport(&tiktokConnectorSigner{connector: t}, http.DefaultTransport, 0)
+	t.Client = &restTikTokClient{BaseURL: baseURL, HttpClient: t.HttpClient}
+	return t
+}
+
+// tiktokConnectorSigner adapts a TikTokSmartConnector's live
+// AccessToken/Secret fields to the signing.Signer interface, so a rotated
+// token takes effect on the next signed request without rebuilding
+// HttpClient.
+type tiktokConnectorSigner struct {
+	connector *TikTokSmartConnector
+}
+
+func (s *tiktokConnectorSigner) Sign(req *http.Request, body []byte) error {
+	return signing.NewTikTokSigner(s.connector.AccessToken, s.connector.Secret).Sign(req, body)
+}
+
+// Connect establishes connection to TikTok Ads API
+func (t *TikTokSmartConnector) Connect(ctx context.Context) error {
+	traceID := observability.SpanFromContext(ctx, "TikTokSmartConnector.Connect").TraceID
+	t.ZLog.Info().
+		Str("connector", "tiktok").
+		Str("trace_id", traceID).
+		Msg("connecting to TikTok Ads Smart Connector")
+	t.Connected = true
+	stats := t.BudgetManager.GetStats()
+	t.ZLog.Info().
+		Str("connector", "tiktok").
+		Str("trace_id", traceID).
+		Float64("budget_remaining", stats.RemainingBudget).
+		Msg("TikTok Smart connection established")
+	return nil
+}
+
+// PlaceBid sends a bid to TikTok with safety checks
+func (t *TikTokSmartConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	traceID := observability.SpanFromContext(ctx, "TikTokSmartConnector.PlaceBid").TraceID
+	if !t.Connected {
+		return nil, fmt.Errorf("not connected to TikTok Ads")
+	}
+
+	// Record latest LTV to improve fallback median quality
+	t.FallbackEngine.RecordLTV("tiktok", req.PredictedLTV)
+
+	// Decide bid source via circuit breaker
+	bidAmount := req.BidAmount
+	decisionSource := "ai"
+	if !t.CircuitBreaker.CanExecute() {
+		t.CircuitBreaker.RecordFallback()
+		fallbackAmount := t.FallbackEngine.CalculateFallbackBid("tiktok", req.PredictedLTV)
+		fireRecorders(t.Recorders, func(r Recorder) { r.LogFallback("tiktok", bidAmount, fallbackAmount) })
+		bidAmount = fallbackAmount
+		decisionSource = "fallback"
+	}
+
+	if !t.BudgetManager.CanSpend(bidAmount) {
+		stats := t.BudgetManager.GetStats()
+		t.ZLog.Warn().
+			Str("connector", "tiktok").
+			Str("customer_id", req.CustomerID).
+			Str("request_id", req.RequestID).
+			Str("trace_id", traceID).
+			Float64("budget_remaining", stats.RemainingBudget).
+			Msg("budget veto: bid exceeds remaining budget")
+		fireRecorders(t.Recorders, func(r Recorder) { r.LogBudgetVeto("tiktok", bidAmount, stats.RemainingBudget) })
+		return &BidResponse{
+			Success:      false,
+			Message:      fmt.Sprintf("Budget exceeded: $%.2f spent of $%.2f limit", stats.CurrentSpend, stats.MaxBudget),
+			PlatformCode: "BUDGET_EXCEEDED",
+			Timestamp:    time.Now(),
+		}, shield.NewError(shield.ErrorKindBudgetExceeded, "tiktok", t.CircuitBreaker.GetState(), fmt.Errorf("budget exceeded"))
+	}
+
+	if !t.RateLimiter.CanMakeCall() {
+		t.ZLog.Warn().
+			Str("connector", "tiktok").
+			Str("customer_id", req.CustomerID).
+			Str("request_id", req.RequestID).
+			Str("trace_id", traceID).
+			Msg("rate limit exceeded, throttling TikTok API calls")
+		fireRecorders(t.Recorders, func(r Recorder) { r.LogRateLimit("tiktok") })
+		return &BidResponse{
+			Success:      false,
+			Message:      "Rate limit exceeded, throttling API calls",
+			PlatformCode: "RATE_LIMITED",
+			Timestamp:    time.Now(),
+		}, fmt.Errorf("rate limited")
+	}
+
+	t.ZLog.Info().
+		Str("connector", "tiktok").
+		Str("customer_id", req.CustomerID).
+		Str("request_id", req.RequestID).
+		Str("trace_id", traceID).
+		Float64("bid_amount", bidAmount).
+		Str("decision_source", decisionSource).
+		Msg("placing bid")
+
+	callStart := time.Now()
+
+	if t.MockMode {
+		t.ZLog.Debug().
+			Str("connector", "tiktok").
+			Str("trace_id", traceID).
+			Msg("mock mode: simulating TikTok Ads API call")
+		t.RateLimiter.RecordCall()
+		t.BudgetManager.AddSpend(bidAmount)
+		stats := t.BudgetManager.GetStats()
+		t.ZLog.Info().
+			Str("connector", "tiktok").
+			Str("customer_id", req.CustomerID).
+			Str("request_id", req.RequestID).
+			Str("trace_id", traceID).
+			Float64("budget_remaining", stats.RemainingBudget).
+			Msg("TikTok bid placed")
+		t.CircuitBreaker.RecordSuccess(time.Since(callStart))
+
+		mockResp := &BidResponse{
+			Success:      true,
+			BidAmount:    bidAmount,
+			BidID:        fmt.Sprintf("MOCK_TIKTOK_%d", time.Now().Unix()),
+			Message:      "Dynamic creative campaign created via TikTok Ads API (MOCK)",
+			PlatformCode: "TIKTOK_ADS_SMART",
+			Timestamp:    time.Now(),
+		}
+		meta := BidMeta{Platform: "tiktok", DecisionSource: decisionSource, Latency: time.Since(callStart)}
+		fireRecorders(t.Recorders, func(r Recorder) { r.LogBid(req, mockResp, meta) })
+		recordIfLedgered(ctx, t.SpendLedger, "tiktok", req, mockResp)
+		return mockResp, nil
+	}
+
+	// TikTok has no direct bid-placement endpoint; like Meta, a bid is
+	// expressed as a campaign budget update carrying the AI-predicted
+	// amount.
+	updateReq := &CampaignUpdateRequest{
+		AdvertiserID: t.AdvertiserID,
+		CampaignID:   req.CampaignID,
+		Budget:       bidAmount,
+		BudgetMode:   "BUDGET_MODE_TOTAL",
+	}
+
+	// Each attempt re-issues the call so PlaceBidPolicy can decide whether
+	// a retry is worthwhile from TikTok's code/message envelope.
+	attemptFn := func(attempt int) (*BidResponse, int, error) {
+		envelope, statusCode, err := t.Client.UpdateCampaign(ctx, updateReq)
+		if err != nil {
+			return nil, 0, err
+		}
+		if envelope.Code != 0 {
+			return &BidResponse{
+				Success:      false,
+				Message:      fmt.Sprintf("TikTok rejected bid: %s", envelope.Message),
+				PlatformCode: "TIKTOK_ADS_SMART",
+				Timestamp:    time.Now(),
+			}, statusCode, nil
+		}
+		return &BidResponse{
+			Success:      true,
+			BidAmount:    bidAmount,
+			BidID:        envelope.RequestID,
+			Message:      "Bid sent to TikTok Ads API",
+			PlatformCode: "TIKTOK_ADS_SMART",
+			Timestamp:    time.Now(),
+		}, statusCode, nil
+	}
+
+	bidResp, err := t.PlaceBidPolicy.Execute(ctx, t.CircuitBreaker, req.RequestID, attemptFn)
+	meta := BidMeta{Platform: "tiktok", DecisionSource: decisionSource, Latency: time.Since(callStart)}
+	if err != nil {
+		t.ZLog.Error().
+			Str("connector", "tiktok").
+			Str("customer_id", req.CustomerID).
+			Str("request_id", req.RequestID).
+			Str("trace_id", traceID).
+			Err(err).
+			Msg("TikTok API error")
+		errResp := &BidResponse{
+			Success:      false,
+			Message:      fmt.Sprintf("API error: %v", err),
+			PlatformCode: "TIKTOK_ERROR",
+			Timestamp:    time.Now(),
+		}
+		fireRecorders(t.Recorders, func(r Recorder) { r.LogBid(req, errResp, meta) })
+		return errResp, err
+	}
+
+	t.RateLimiter.RecordCall()
+	if bidResp.Success {
+		t.BudgetManager.AddSpend(bidAmount)
+		stats := t.BudgetManager.GetStats()
+		t.ZLog.Info().
+			Str("connector", "tiktok").
+			Str("customer_id", req.CustomerID).
+			Str("request_id", req.RequestID).
+			Str("trace_id", traceID).
+			Float64("budget_remaining", stats.RemainingBudget).
+			Msg("TikTok bid placed")
+	}
+	fireRecorders(t.Recorders, func(r Recorder) { r.LogBid(req, bidResp, meta) })
+	recordIfLedgered(ctx, t.SpendLedger, "tiktok", req, bidResp)
+
+	return bidResp, nil
+}
+
+// GetBudgetStats returns budget statistics
+func (t *TikTokSmartConnector) GetBudgetStats() shield.WindowStats {
+	return t.BudgetManager.GetStats()
+}
+
+// GetCircuitBreaker exposes the connector's CircuitBreaker so callers like
+// auction.Auctioneer can check it before fanning out a bid.
+func (t *TikTokSmartConnector) GetCircuitBreaker() *shield.CircuitBreaker {
+	return t.CircuitBreaker
+}
+
+// GetBudgetManager exposes the connector's BudgetManager so callers like
+// reconcile's rewind can correct its live spend state, not just a
+// throwaway local copy.
+func (t *TikTokSmartConnector) GetBudgetManager() *shield.BudgetManager {
+	return t.BudgetManager
+}
+
+// GetStatus returns connection status
+func (t *TikTokSmartConnector) GetStatus() string {
+	if t.Connected {
+		stats := t.BudgetManager.GetStats()
+		return fmt.Sprintf("Connected to TikTok Ads - Budget: $%.2f/$%.2f", stats.CurrentSpend, stats.MaxBudget)
+	}
+	return "Disconnected from TikTok Ads"
+}
+
+// Close closes the connection
+func (t *TikTokSmartConnector) Close() error {
+	t.ZLog.Info().
+		Str("connector", "tiktok").
+		Float64("budget_remaining", t.BudgetManager.GetStats().RemainingBudget).
+		Msg("TikTok connection closed")
+	t.Connected = false
+	return nil
+}
+
+// UpdateCampaignBudget adjusts campaign budget via a signed POST to
+// TikTok's /campaign/update/ endpoint.
+func (t *TikTokSmartConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
+	if !t.Connected {
+		return nil, fmt.Errorf("not connected to TikTok Ads")
+	}
+	if !t.BudgetManager.CanSpend(budgetAmount) {
+		return nil, fmt.Errorf("budget update would exceed limits")
+	}
+
+	traceID := observability.SpanFromContext(ctx, "TikTokSmartConnector.UpdateCampaignBudget").TraceID
+	t.ZLog.Info().
+		Str("connector", "tiktok").
+		Str("trace_id", traceID).
+		Str("campaign_id", campaignID).
+		Float64("bid_amount", budgetAmount).
+		Msg("updating campaign budget")
+
+	updateReq := &CampaignUpdateRequest{
+		AdvertiserID: t.AdvertiserID,
+		CampaignID:   campaignID,
+		Budget:       budgetAmount,
+		BudgetMode:   "BUDGET_MODE_TOTAL",
+	}
+
+	// Updating a budget to an absolute amount is idempotent, so
+	// UpdateCampaignBudgetPolicy retries freely on a retryable status.
+	attemptFn := func(attempt int) (*BidResponse, int, error) {
+		envelope, statusCode, err := t.Client.UpdateCampaign(ctx, updateReq)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &BidResponse{
+			Success:      envelope.Code == 0,
+			Message:      fmt.Sprintf("TikTok campaign %s budget updated to $%.2f", campaignID, budgetAmount),
+			PlatformCode: "TIKTOK_BUDGET",
+			Timestamp:    time.Now(),
+		}, statusCode, nil
+	}
+
+	bidResp, err := t.UpdateCampaignBudgetPolicy.Execute(ctx, t.CircuitBreaker, "", attemptFn)
+	if err != nil {
+		return nil, err
+	}
+
+	t.RateLimiter.RecordCall()
+	return bidResp, nil
+}
+
+// UpdateTargetAudience retargets a TikTok ad group via a signed POST to
+// /adgroup/update/, swapping in audienceID as its sole custom audience.
+func (t *TikTokSmartConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
+	if !t.Connected {
+		return nil, fmt.Errorf("not connected to TikTok Ads")
+	}
+
+	traceID := observability.SpanFromContext(ctx, "TikTokSmartConnector.UpdateTargetAudience").TraceID
+	t.ZLog.Info().
+		Str("connector", "tiktok").
+		Str("trace_id", traceID).
+		Str("campaign_id", campaignID).
+		Msg("updating target audience")
+
+	updateReq := &AdGroupUpdateRequest{
+		AdvertiserID: t.AdvertiserID,
+		AdgroupID:    campaignID,
+		AudienceIDs:  []string{audienceID},
+	}
+
+	// Re-pointing an ad group at an audience ID is idempotent, so
+	// UpdateTargetAudiencePolicy retries freely on a retryable status.
+	attemptFn := func(attempt int) (*BidResponse, int, error) {
+		envelope, statusCode, err := t.Client.UpdateAdGroup(ctx, updateReq)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &BidResponse{
+			Success:      envelope.Code == 0,
+			Message:      fmt.Sprintf("TikTok audience targeting updated for campaign %s", campaignID),
+			PlatformCode: "TIKTOK_AUDIENCE",
+			Timestamp:    time.Now(),
+		}, statusCode, nil
+	}
+
+	bidResp, err := t.UpdateTargetAudiencePolicy.Execute(ctx, t.CircuitBreaker, "", attemptFn)
+	if err != nil {
+		return nil, err
+	}
+
+	t.RateLimiter.RecordCall()
+	return bidResp, nil
+}
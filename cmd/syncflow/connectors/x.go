@@ -1,76 +1,316 @@
-package connectors
-
-import (
-	"context"
-	"fmt"
-	"log"
-	"net/http"
-	"time"
-)
-
-// XConnector implements PlatformConnector for basic X (Twitter) Ads
-type XConnector struct {
-	APIKey     string
-	AccountID  string
-	HttpClient *http.Client
-	Connected  bool
-	BaseURL    string
-}
-
-// NewXConnector creates a new basic X connector
-func NewXConnector(apiKey, accountID string) *XConnector {
-	return &XConnector{
-		APIKey:     apiKey,
-		AccountID:  accountID,
-		HttpClient: &http.Client{Timeout: 10 * time.Second},
-		BaseURL:    "https://ads-api.twitter.com/12",
-	}
-}
-
-// Connect establishes connection to X Ads API
-func (x *XConnector) Connect(ctx context.Context) error {
-	log.Printf("🔗 Connecting to X Ads API for account: %s", x.AccountID)
-	x.Connected = true
-	log.Printf("✅ Connected to X Ads API")
-	return nil
-}
-
-// PlaceBid sends a bid to X Ads
-func (x *XConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
-	if !x.Connected {
-		return nil, fmt.Errorf("not connected to X Ads")
-	}
-
-	return &BidResponse{
-		Success:      true,
-		BidID:        fmt.Sprintf("X_%d", time.Now().Unix()),
-		Message:      "Bid placed on X Ads",
-		PlatformCode: "X_ADS",
-		Timestamp:    time.Now(),
-	}, nil
-}
-
-// GetStatus returns connection status
-func (x *XConnector) GetStatus() string {
-	if x.Connected {
-		return "Connected to X Ads"
-	}
-	return "Disconnected from X Ads"
-}
-
-// Close closes the connection
-func (x *XConnector) Close() error {
-	log.Printf("🔌 X connection closed")
-	x.Connected = false
-	return nil
-}
-
-// UpdateCampaignBudget updates campaign budget
-func (x *XConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
-// UpdateTargetAudience updates target audience
-func (x *XConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
-	return nil, fmt.Errorf("not implemented")
-}
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/budget"
+)
+
+// Campaign is the subset of a X Ads campaign this connector reads and writes.
+type Campaign struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name,omitempty"`
+	DailyBudget float64 `json:"daily_budget_amount_local_micro,omitempty"`
+}
+
+// LineItem is a X Ads line item, the unit a bid amount attaches to.
+type LineItem struct {
+	ID          string  `json:"id,omitempty"`
+	CampaignID  string  `json:"campaign_id"`
+	BidAmount   float64 `json:"bid_amount_local_micro"`
+	ProductType string  `json:"product_type,omitempty"`
+}
+
+// PromotedTweet attaches a tweet to a line item so it can be bid on.
+type PromotedTweet struct {
+	ID         string `json:"id,omitempty"`
+	LineItemID string `json:"line_item_id"`
+	TweetID    string `json:"tweet_id"`
+}
+
+// xAPIEnvelope mirrors the {"data": ...} wrapper ads-api.twitter.com/12 responses use.
+type xAPIEnvelope struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"errors"`
+}
+
+// XConnector implements PlatformConnector for X (Twitter) Ads v12, signing
+// every request with OAuth 1.0a HMAC-SHA1 as ads-api.twitter.com/12 requires.
+type XConnector struct {
+	// OAuth 1.0a credentials. APIKey/APISecret are the consumer key/secret;
+	// AccessToken/AccessTokenSecret are the account-level token pair.
+	APIKey            string
+	APISecret         string
+	AccessToken       string
+	AccessTokenSecret string
+
+	AccountID  string
+	HttpClient *http.Client
+	Connected  bool
+	BaseURL    string
+
+	// Budget is optional. When set, PlaceBid checks CanSpend before calling
+	// the API and only RecordSpend on success, so a failed or rejected bid
+	// never consumes budget headroom.
+	Budget *budget.SlidingWindowBudget
+
+	// MaxRetries bounds the exponential-backoff retry loop on 429/5xx.
+	MaxRetries int
+}
+
+// NewXConnector creates a new X Ads connector.
+func NewXConnector(apiKey, accountID string) *XConnector {
+	return &XConnector{
+		APIKey:     apiKey,
+		AccountID:  accountID,
+		HttpClient: &http.Client{Timeout: 10 * time.Second},
+		BaseURL:    "https://ads-api.twitter.com/12",
+		MaxRetries: 3,
+	}
+}
+
+// Connect verifies OAuth credentials are configured. X Ads has no dedicated
+// handshake endpoint, so this just gates calls on having a signable identity.
+func (x *XConnector) Connect(ctx context.Context) error {
+	log.Printf("🔗 Connecting to X Ads API for account: %s", x.AccountID)
+	if x.APIKey == "" || x.APISecret == "" || x.AccessToken == "" || x.AccessTokenSecret == "" {
+		return fmt.Errorf("X Ads connector requires APIKey, APISecret, AccessToken, and AccessTokenSecret")
+	}
+	x.Connected = true
+	log.Printf("✅ Connected to X Ads API")
+	return nil
+}
+
+// PlaceBid creates a line item on X Ads carrying req.BidAmount.
+func (x *XConnector) PlaceBid(ctx context.Context, req *BidRequest) (*BidResponse, error) {
+	if !x.Connected {
+		return nil, fmt.Errorf("not connected to X Ads")
+	}
+
+	if x.Budget != nil && !x.Budget.CanSpend(req.BidAmount) {
+		return &BidResponse{
+			Success:      false,
+			Message:      "X Ads bid rejected: budget exceeded",
+			PlatformCode: "BUDGET_EXCEEDED",
+			Timestamp:    time.Now(),
+		}, fmt.Errorf("budget exceeded")
+	}
+
+	lineItem := LineItem{
+		CampaignID:  req.CampaignID,
+		BidAmount:   req.BidAmount * 1_000_000, // X Ads bids are denominated in micro-currency units
+		ProductType: "PROMOTED_TWEETS",
+	}
+	body, err := json.Marshal(lineItem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode X Ads line item: %w", err)
+	}
+
+	path := fmt.Sprintf("/accounts/%s/line_items", x.AccountID)
+	var created LineItem
+	if err := x.doJSON(ctx, http.MethodPost, path, nil, body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create X Ads line item: %w", err)
+	}
+
+	// Only record spend once the platform has actually accepted the bid, so
+	// a failed request never leaves budget reserved against it.
+	if x.Budget != nil {
+		if err := x.Budget.RecordSpend(req.BidAmount, "x", created.ID); err != nil {
+			return &BidResponse{
+				Success:      false,
+				Message:      fmt.Sprintf("X Ads bid placed but budget rejected it: %v", err),
+				PlatformCode: "BUDGET_EXCEEDED",
+				Timestamp:    time.Now(),
+			}, err
+		}
+	}
+
+	log.Printf("✅ X Ads line item %s created for campaign %s at bid $%.2f", created.ID, req.CampaignID, req.BidAmount)
+
+	return &BidResponse{
+		Success:      true,
+		BidID:        created.ID,
+		Message:      "Bid placed on X Ads",
+		PlatformCode: "X_ADS",
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+// UpdateCampaignBudget sets a campaign's daily budget.
+func (x *XConnector) UpdateCampaignBudget(ctx context.Context, campaignID string, budgetAmount float64) (*BidResponse, error) {
+	if !x.Connected {
+		return nil, fmt.Errorf("not connected to X Ads")
+	}
+
+	campaign := Campaign{DailyBudget: budgetAmount * 1_000_000}
+	body, err := json.Marshal(campaign)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode X Ads campaign update: %w", err)
+	}
+
+	path := fmt.Sprintf("/accounts/%s/campaigns/%s", x.AccountID, campaignID)
+	var updated Campaign
+	if err := x.doJSON(ctx, http.MethodPut, path, nil, body, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update X Ads campaign budget: %w", err)
+	}
+
+	return &BidResponse{
+		Success:      true,
+		BidID:        updated.ID,
+		Message:      fmt.Sprintf("Campaign %s budget updated to $%.2f", campaignID, budgetAmount),
+		PlatformCode: "X_ADS",
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+// UpdateTargetAudience attaches a tailored audience to a campaign.
+func (x *XConnector) UpdateTargetAudience(ctx context.Context, campaignID string, audienceID string) (*BidResponse, error) {
+	if !x.Connected {
+		return nil, fmt.Errorf("not connected to X Ads")
+	}
+
+	form := url.Values{
+		"tailored_audience_id": {audienceID},
+		"campaign_id":          {campaignID},
+	}
+
+	path := fmt.Sprintf("/accounts/%s/tailored_audience_memberships", x.AccountID)
+	var membership struct {
+		ID string `json:"id"`
+	}
+	if err := x.doJSON(ctx, http.MethodPost, path, form, nil, &membership); err != nil {
+		return nil, fmt.Errorf("failed to update X Ads target audience: %w", err)
+	}
+
+	return &BidResponse{
+		Success:      true,
+		BidID:        membership.ID,
+		Message:      fmt.Sprintf("Campaign %s audience updated to %s", campaignID, audienceID),
+		PlatformCode: "X_ADS",
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+// GetStatus returns connection status.
+func (x *XConnector) GetStatus() string {
+	if x.Connected {
+		return "Connected to X Ads"
+	}
+	return "Disconnected from X Ads"
+}
+
+// Close closes the connection.
+func (x *XConnector) Close() error {
+	log.Printf("🔌 X connection closed")
+	x.Connected = false
+	return nil
+}
+
+// doJSON signs and sends a request to X Ads, retrying 429/5xx with
+// exponential backoff (honoring x-rate-limit-reset when present), and
+// unmarshals the response's "data" envelope into out.
+func (x *XConnector) doJSON(ctx context.Context, method, path string, form url.Values, jsonBody []byte, out interface{}) error {
+	fullURL := x.BaseURL + path
+
+	var lastErr error
+	backoff := 500 * time.Millisecond
+	for attempt := 0; attempt <= x.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			backoff *= 2
+		}
+
+		resp, err := x.sendOnce(ctx, method, fullURL, form, jsonBody)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("failed to read X Ads response body: %w", err)
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("X Ads API returned status %d: %s", resp.StatusCode, string(body))
+			if reset := resp.Header.Get("x-rate-limit-reset"); reset != "" {
+				if resetUnix, err := strconv.ParseInt(reset, 10, 64); err == nil {
+					if wait := time.Until(time.Unix(resetUnix, 0)); wait > 0 {
+						backoff = wait
+					}
+				}
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("X Ads API returned status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var envelope xAPIEnvelope
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			return fmt.Errorf("failed to decode X Ads response: %w", err)
+		}
+		if len(envelope.Errors) > 0 {
+			return fmt.Errorf("X Ads API error: %s", envelope.Errors[0].Message)
+		}
+		if out != nil && len(envelope.Data) > 0 {
+			if err := json.Unmarshal(envelope.Data, out); err != nil {
+				return fmt.Errorf("failed to decode X Ads response data: %w", err)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("X Ads API request failed after %d attempts: %w", x.MaxRetries+1, lastErr)
+}
+
+// sendOnce builds, signs, and sends a single HTTP request.
+func (x *XConnector) sendOnce(ctx context.Context, method, fullURL string, form url.Values, jsonBody []byte) (*http.Response, error) {
+	var bodyReader io.Reader
+	signingParams := url.Values{}
+
+	if form != nil {
+		bodyReader = bytes.NewBufferString(form.Encode())
+		signingParams = form
+	} else if jsonBody != nil {
+		bodyReader = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build X Ads request: %w", err)
+	}
+
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else if jsonBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	authHeader, err := signOAuth1(method, fullURL, signingParams, x.APIKey, x.APISecret, x.AccessToken, x.AccessTokenSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign X Ads request: %w", err)
+	}
+	req.Header.Set("Authorization", authHeader)
+
+	return x.HttpClient.Do(req)
+}
@@ -0,0 +1,120 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestXSmartConnector(t *testing.T, server *httptest.Server, maxBudget float64) *XSmartConnector {
+	t.Helper()
+	x := NewXSmartConnector("api-key", "account-123", maxBudget)
+	x.BaseURL = server.URL
+	x.Connected = true
+	return x
+}
+
+func TestXSmartConnector_PlaceBidRetriesTransientFailureThenCharges(t *testing.T) {
+	var attempts int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"errors":"server error"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]string{"id": "li-after-retry"},
+		})
+	}))
+	defer server.Close()
+
+	x := newTestXSmartConnector(t, server, 1000.0)
+	x.RetryPolicy.InitialBackoff = time.Millisecond
+	x.RetryPolicy.MaxBackoff = time.Millisecond
+
+	resp, err := x.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", BidAmount: 10.0, PredictedLTV: 100})
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got error: %v", err)
+	}
+	if resp.BidID != "li-after-retry" {
+		t.Fatalf("unexpected bid ID: %s", resp.BidID)
+	}
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf("expected exactly 2 attempts, got %d", attempts)
+	}
+
+	stats := x.GetBudgetStats()
+	if stats.CurrentSpend != 10.0 {
+		t.Errorf("expected a single $10.00 charge after the retry succeeded, got $%.2f", stats.CurrentSpend)
+	}
+}
+
+func TestXSmartConnector_PlaceBidDoesNotChargeBudgetOnFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"errors":"server error"}`))
+	}))
+	defer server.Close()
+
+	x := newTestXSmartConnector(t, server, 1000.0)
+	x.RetryPolicy.InitialBackoff = time.Millisecond
+	x.RetryPolicy.MaxBackoff = time.Millisecond
+
+	_, err := x.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", BidAmount: 10.0, PredictedLTV: 100})
+	if err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+
+	stats := x.GetBudgetStats()
+	if stats.CurrentSpend != 0 {
+		t.Errorf("expected no budget charge on a failed bid, got $%.2f spent", stats.CurrentSpend)
+	}
+}
+
+func TestXSmartConnector_PlaceBidDegradesToFallbackOverSoftBudgetLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]string{"id": "li-degraded"},
+		})
+	}))
+	defer server.Close()
+
+	x := newTestXSmartConnector(t, server, 100.0) // soft limit: 80.0
+	x.BudgetManager.AddSpend(75.0)                // a $10 bid projects to 85: over the soft limit, under the hard limit
+
+	resp, err := x.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", BidAmount: 10.0, PredictedLTV: 100})
+	if err != nil {
+		t.Fatalf("expected the bid to still succeed via fallback, got error: %v", err)
+	}
+	if resp.BidAmount == 10.0 {
+		t.Error("expected the bid to be degraded to a heuristic fallback amount, not the original AI bid")
+	}
+}
+
+func TestXSmartConnector_UpdateCampaignBudgetSendsPUT(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{}})
+	}))
+	defer server.Close()
+
+	x := newTestXSmartConnector(t, server, 1000.0)
+	resp, err := x.UpdateCampaignBudget(context.Background(), "camp-1", 500.0)
+	if err != nil {
+		t.Fatalf("UpdateCampaignBudget failed: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got %+v", resp)
+	}
+}
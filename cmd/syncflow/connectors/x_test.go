@@ -0,0 +1,130 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestXConnector(t *testing.T, server *httptest.Server) *XConnector {
+	t.Helper()
+	x := NewXConnector("consumer-key", "account-123")
+	x.APISecret = "consumer-secret"
+	x.AccessToken = "access-token"
+	x.AccessTokenSecret = "access-token-secret"
+	x.BaseURL = server.URL
+	x.MaxRetries = 3
+	if err := x.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	return x
+}
+
+func TestXConnector_PlaceBidSignsAndParsesResponse(t *testing.T) {
+	var gotAuthHeader string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthHeader = r.Header.Get("Authorization")
+		if r.URL.Path != "/accounts/account-123/line_items" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": LineItem{ID: "li-789", CampaignID: "camp-1"},
+		})
+	}))
+	defer server.Close()
+
+	x := newTestXConnector(t, server)
+	resp, err := x.PlaceBid(context.Background(), &BidRequest{
+		CampaignID: "camp-1",
+		BidAmount:  2.50,
+	})
+	if err != nil {
+		t.Fatalf("PlaceBid failed: %v", err)
+	}
+	if !resp.Success || resp.BidID != "li-789" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if gotAuthHeader == "" || !containsOAuthPrefix(gotAuthHeader) {
+		t.Errorf("expected a signed OAuth 1.0a Authorization header, got %q", gotAuthHeader)
+	}
+}
+
+func containsOAuthPrefix(header string) bool {
+	return len(header) > 6 && header[:6] == "OAuth "
+}
+
+func TestXConnector_RetriesOn429HonoringRateLimitReset(t *testing.T) {
+	var attempts int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n == 1 {
+			w.Header().Set("x-rate-limit-reset", strconv.FormatInt(time.Now().Add(50*time.Millisecond).Unix(), 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"errors":[{"message":"rate limited"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": LineItem{ID: "li-after-retry", CampaignID: "camp-1"},
+		})
+	}))
+	defer server.Close()
+
+	x := newTestXConnector(t, server)
+	resp, err := x.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", BidAmount: 1.0})
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got error: %v", err)
+	}
+	if resp.BidID != "li-after-retry" {
+		t.Fatalf("unexpected bid ID: %s", resp.BidID)
+	}
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf("expected exactly 2 attempts, got %d", attempts)
+	}
+}
+
+func TestXConnector_GivesUpAfterMaxRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"errors":[{"message":"server error"}]}`))
+	}))
+	defer server.Close()
+
+	x := newTestXConnector(t, server)
+	x.MaxRetries = 1
+
+	_, err := x.PlaceBid(context.Background(), &BidRequest{CampaignID: "camp-1", BidAmount: 1.0})
+	if err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+}
+
+func TestXConnector_UpdateCampaignBudget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": Campaign{ID: "camp-1"},
+		})
+	}))
+	defer server.Close()
+
+	x := newTestXConnector(t, server)
+	resp, err := x.UpdateCampaignBudget(context.Background(), "camp-1", 500.0)
+	if err != nil {
+		t.Fatalf("UpdateCampaignBudget failed: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got %+v", resp)
+	}
+}
@@ -0,0 +1,76 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+)
+
+// FuncHook adapts a plain function to the Hook interface, the same way
+// http.HandlerFunc adapts a function to http.Handler, so a one-off hook
+// doesn't need its own named type.
+type FuncHook struct {
+	HookName string
+	Fn       func(ctx context.Context, hc *HookContext) Result
+}
+
+// Name implements Hook.
+func (f FuncHook) Name() string { return f.HookName }
+
+// Execute implements Hook.
+func (f FuncHook) Execute(ctx context.Context, hc *HookContext) Result { return f.Fn(ctx, hc) }
+
+// NewBudgetVetoHook builds a StageRawBidderRequest hook that rejects the
+// bid when checkBudget reports the rolling burst-budget window is
+// exhausted - the same veto cmd/syncflow/main.go's CheckBudget already
+// enforces, surfaced here as a hook. A veto sets HookContext.RejectReason
+// and main.go logs the tick with the decision "Rejected"; the name of the
+// vetoing hook is not written to the audit log.
+func NewBudgetVetoHook(checkBudget func() bool) Hook {
+	return FuncHook{
+		HookName: "budget-veto",
+		Fn: func(ctx context.Context, hc *HookContext) Result {
+			if checkBudget() {
+				return Result{Action: ActionContinue}
+			}
+			return Result{Action: ActionReject, RejectReason: "burst budget exhausted"}
+		},
+	}
+}
+
+// NewAnomalyTagHook builds a hook that tags the tick "mode=anomaly" when
+// PredictedLTV exceeds threshold - the heuristic main.go's ticker loop
+// used to apply inline. main.go's initHooks registers it at
+// auction-response with its anomalyLTVThreshold constant.
+func NewAnomalyTagHook(threshold float64) Hook {
+	return FuncHook{
+		HookName: "anomaly-tag",
+		Fn: func(ctx context.Context, hc *HookContext) Result {
+			if hc.PredictedLTV <= threshold {
+				return Result{Action: ActionContinue}
+			}
+			if hc.Tags == nil {
+				hc.Tags = make(map[string]string)
+			}
+			hc.Tags["anomaly_threshold"] = fmt.Sprintf("%.2f", threshold)
+			hc.Mode = "anomaly"
+			return Result{Action: ActionUpdate}
+		},
+	}
+}
+
+// NewMinBidFloorHook builds a StageRawBidderRequest hook that raises
+// BidAmount up to floor if the computed bid would otherwise fall under it,
+// rather than letting a below-floor bid reach the platform and lose the
+// auction outright.
+func NewMinBidFloorHook(floor float64) Hook {
+	return FuncHook{
+		HookName: "min-bid-floor",
+		Fn: func(ctx context.Context, hc *HookContext) Result {
+			if hc.BidAmount >= floor {
+				return Result{Action: ActionContinue}
+			}
+			hc.BidAmount = floor
+			return Result{Action: ActionUpdate}
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBudgetVetoHook_RejectsWhenBudgetExhausted(t *testing.T) {
+	hook := NewBudgetVetoHook(func() bool { return false })
+	result := hook.Execute(context.Background(), &HookContext{})
+	if result.Action != ActionReject {
+		t.Fatalf("expected ActionReject, got %v", result.Action)
+	}
+	if result.RejectReason == "" {
+		t.Fatal("expected a RejectReason")
+	}
+}
+
+func TestBudgetVetoHook_ContinuesWhenBudgetAvailable(t *testing.T) {
+	hook := NewBudgetVetoHook(func() bool { return true })
+	result := hook.Execute(context.Background(), &HookContext{})
+	if result.Action != ActionContinue {
+		t.Fatalf("expected ActionContinue, got %v", result.Action)
+	}
+}
+
+func TestAnomalyTagHook_TagsWhenOverThreshold(t *testing.T) {
+	hook := NewAnomalyTagHook(100)
+	hc := &HookContext{PredictedLTV: 150}
+	result := hook.Execute(context.Background(), hc)
+
+	if result.Action != ActionUpdate {
+		t.Fatalf("expected ActionUpdate, got %v", result.Action)
+	}
+	if hc.Mode != "anomaly" {
+		t.Fatalf("expected Mode=anomaly, got %q", hc.Mode)
+	}
+	if hc.Tags["anomaly_threshold"] != "100.00" {
+		t.Fatalf("expected anomaly_threshold tag, got %+v", hc.Tags)
+	}
+}
+
+func TestAnomalyTagHook_ContinuesUnderThreshold(t *testing.T) {
+	hook := NewAnomalyTagHook(100)
+	hc := &HookContext{PredictedLTV: 50}
+	result := hook.Execute(context.Background(), hc)
+
+	if result.Action != ActionContinue {
+		t.Fatalf("expected ActionContinue, got %v", result.Action)
+	}
+	if hc.Mode == "anomaly" {
+		t.Fatal("expected Mode to be left untouched under threshold")
+	}
+}
+
+func TestMinBidFloorHook_RaisesBidUnderFloor(t *testing.T) {
+	hook := NewMinBidFloorHook(5.0)
+	hc := &HookContext{BidAmount: 2.0}
+	result := hook.Execute(context.Background(), hc)
+
+	if result.Action != ActionUpdate {
+		t.Fatalf("expected ActionUpdate, got %v", result.Action)
+	}
+	if hc.BidAmount != 5.0 {
+		t.Fatalf("expected BidAmount raised to floor 5.0, got %v", hc.BidAmount)
+	}
+}
+
+func TestMinBidFloorHook_LeavesBidAtOrAboveFloor(t *testing.T) {
+	hook := NewMinBidFloorHook(5.0)
+	hc := &HookContext{BidAmount: 10.0}
+	result := hook.Execute(context.Background(), hc)
+
+	if result.Action != ActionContinue {
+		t.Fatalf("expected ActionContinue, got %v", result.Action)
+	}
+	if hc.BidAmount != 10.0 {
+		t.Fatalf("expected BidAmount untouched, got %v", hc.BidAmount)
+	}
+}
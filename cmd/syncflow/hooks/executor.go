@@ -0,0 +1,90 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const defaultHookTimeout = 50 * time.Millisecond
+
+// Executor runs the hooks a Registry has registered for each Stage,
+// enforcing Timeout per hook so one slow module can't stall the whole
+// bidding-loop tick.
+type Executor struct {
+	Registry *Registry
+	// Timeout bounds each individual hook's Execute call; defaults to
+	// defaultHookTimeout when zero.
+	Timeout time.Duration
+}
+
+// NewExecutor creates an Executor bound to registry, using timeout per
+// hook (or defaultHookTimeout if timeout is zero).
+func NewExecutor(registry *Registry, timeout time.Duration) *Executor {
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	return &Executor{Registry: registry, Timeout: timeout}
+}
+
+// ExecuteStage runs every hook registered at stage, in order, against hc.
+// It stops at the first ActionReject - the remaining hooks in this stage
+// don't run - and always returns one Outcome per hook it actually ran.
+func (e *Executor) ExecuteStage(ctx context.Context, stage Stage, hc *HookContext) []Outcome {
+	hooksAtStage := e.Registry.Hooks(stage)
+	outcomes := make([]Outcome, 0, len(hooksAtStage))
+
+	for _, hook := range hooksAtStage {
+		outcome := e.runOne(ctx, stage, hook, hc)
+		outcomes = append(outcomes, outcome)
+		if outcome.Action == ActionReject {
+			hc.RejectReason = outcome.RejectReason
+			break
+		}
+	}
+	return outcomes
+}
+
+// runOne executes a single hook under e.Timeout, recovering a panic into
+// an Outcome.Err instead of letting a misbehaving module crash the
+// bidding loop.
+func (e *Executor) runOne(ctx context.Context, stage Stage, hook Hook, hc *HookContext) Outcome {
+	hookCtx, cancel := context.WithTimeout(ctx, e.Timeout)
+	defer cancel()
+
+	type execOutput struct {
+		result Result
+		err    error
+	}
+	done := make(chan execOutput, 1)
+	start := time.Now()
+
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				done <- execOutput{err: fmt.Errorf("hook %s panicked: %v", hook.Name(), rec)}
+			}
+		}()
+		done <- execOutput{result: hook.Execute(hookCtx, hc)}
+	}()
+
+	select {
+	case out := <-done:
+		return Outcome{
+			HookName:     hook.Name(),
+			Stage:        stage,
+			Action:       out.result.Action,
+			RejectReason: out.result.RejectReason,
+			Duration:     time.Since(start),
+			Err:          out.err,
+		}
+	case <-hookCtx.Done():
+		return Outcome{
+			HookName: hook.Name(),
+			Stage:    stage,
+			Action:   ActionContinue,
+			Duration: time.Since(start),
+			Err:      fmt.Errorf("hook %s timed out after %s", hook.Name(), e.Timeout),
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func continueHook(name string) Hook {
+	return FuncHook{HookName: name, Fn: func(ctx context.Context, hc *HookContext) Result { return Result{Action: ActionContinue} }}
+}
+
+func TestExecutor_ExecuteStageRunsHooksInOrder(t *testing.T) {
+	var ran []string
+	r := NewRegistry()
+	r.Register(StageEntrypoint, FuncHook{HookName: "a", Fn: func(ctx context.Context, hc *HookContext) Result {
+		ran = append(ran, "a")
+		return Result{Action: ActionContinue}
+	}})
+	r.Register(StageEntrypoint, FuncHook{HookName: "b", Fn: func(ctx context.Context, hc *HookContext) Result {
+		ran = append(ran, "b")
+		return Result{Action: ActionContinue}
+	}})
+
+	e := NewExecutor(r, time.Second)
+	outcomes := e.ExecuteStage(context.Background(), StageEntrypoint, &HookContext{})
+
+	if len(outcomes) != 2 {
+		t.Fatalf("expected 2 outcomes, got %d", len(outcomes))
+	}
+	if ran[0] != "a" || ran[1] != "b" {
+		t.Fatalf("expected hooks to run in registration order, got %v", ran)
+	}
+}
+
+func TestExecutor_ExecuteStageStopsAtFirstReject(t *testing.T) {
+	var ran []string
+	r := NewRegistry()
+	r.Register(StageEntrypoint, FuncHook{HookName: "veto", Fn: func(ctx context.Context, hc *HookContext) Result {
+		ran = append(ran, "veto")
+		return Result{Action: ActionReject, RejectReason: "nope"}
+	}})
+	r.Register(StageEntrypoint, FuncHook{HookName: "never", Fn: func(ctx context.Context, hc *HookContext) Result {
+		ran = append(ran, "never")
+		return Result{Action: ActionContinue}
+	}})
+
+	e := NewExecutor(r, time.Second)
+	hc := &HookContext{}
+	outcomes := e.ExecuteStage(context.Background(), StageEntrypoint, hc)
+
+	if len(outcomes) != 1 {
+		t.Fatalf("expected execution to stop after the reject, got %d outcomes", len(outcomes))
+	}
+	if len(ran) != 1 || ran[0] != "veto" {
+		t.Fatalf("expected only the rejecting hook to run, got %v", ran)
+	}
+	if hc.RejectReason != "nope" {
+		t.Fatalf("expected HookContext.RejectReason to be set, got %q", hc.RejectReason)
+	}
+}
+
+func TestExecutor_RunOneTimesOutSlowHook(t *testing.T) {
+	r := NewRegistry()
+	r.Register(StageEntrypoint, FuncHook{HookName: "slow", Fn: func(ctx context.Context, hc *HookContext) Result {
+		<-ctx.Done()
+		return Result{Action: ActionContinue}
+	}})
+
+	e := NewExecutor(r, 5*time.Millisecond)
+	outcomes := e.ExecuteStage(context.Background(), StageEntrypoint, &HookContext{})
+
+	if len(outcomes) != 1 {
+		t.Fatalf("expected 1 outcome, got %d", len(outcomes))
+	}
+	if outcomes[0].Err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	if outcomes[0].Action != ActionContinue {
+		t.Fatalf("expected a timed-out hook to degrade to ActionContinue, got %v", outcomes[0].Action)
+	}
+}
+
+func TestExecutor_RunOneRecoversPanic(t *testing.T) {
+	r := NewRegistry()
+	r.Register(StageEntrypoint, FuncHook{HookName: "panics", Fn: func(ctx context.Context, hc *HookContext) Result {
+		panic("boom")
+	}})
+
+	e := NewExecutor(r, time.Second)
+	outcomes := e.ExecuteStage(context.Background(), StageEntrypoint, &HookContext{})
+
+	if len(outcomes) != 1 {
+		t.Fatalf("expected 1 outcome, got %d", len(outcomes))
+	}
+	if outcomes[0].Err == nil {
+		t.Fatal("expected the panic to be recovered into Outcome.Err")
+	}
+	if outcomes[0].Action != ActionContinue {
+		t.Fatalf("expected a panicking hook to degrade to ActionContinue, got %v", outcomes[0].Action)
+	}
+}
+
+func TestNewExecutor_DefaultsTimeoutWhenZero(t *testing.T) {
+	e := NewExecutor(NewRegistry(), 0)
+	if e.Timeout != defaultHookTimeout {
+		t.Fatalf("expected default timeout %s, got %s", defaultHookTimeout, e.Timeout)
+	}
+}
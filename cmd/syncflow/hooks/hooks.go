@@ -0,0 +1,131 @@
+// Package hooks implements an ordered, per-stage hook execution framework
+// around cmd/syncflow's fetchLTV -> checkBudget -> PlaceBid flow, modeled
+// on prebid-server's module hooks: operators register stages at startup
+// and can veto or reshape a bid, or attach analytics tags, without
+// recompiling the agent.
+package hooks
+
+import (
+	"context"
+	"time"
+)
+
+// Stage names one of the points in the bidding flow a Hook can attach to,
+// named after prebid-server's module-hook stages even though this agent's
+// single-bidder ticker loop is simpler than a real multi-bidder auction.
+type Stage string
+
+const (
+	// StageEntrypoint runs first, before fetchLTV is even called - the
+	// place to veto a customer/request outright.
+	StageEntrypoint Stage = "entrypoint"
+	// StageRawBidderRequest runs after a bid has cleared checkBudget and
+	// the LTV threshold, immediately before connector.PlaceBid - the last
+	// point a hook can still adjust BidAmount or reject before it reaches
+	// the platform.
+	StageRawBidderRequest Stage = "raw-bidder-request"
+	// StageRawBidderResponse runs immediately after connector.PlaceBid
+	// returns, before spend is recorded.
+	StageRawBidderResponse Stage = "raw-bidder-response"
+	// StageAuctionResponse runs once Decision has been set for this tick;
+	// hooks at this stage may still change Mode.
+	StageAuctionResponse Stage = "auction-response"
+	// StageAllProcessedBidResponses runs last, immediately before the tick
+	// is handed to the audit log - the place to attach final tags.
+	StageAllProcessedBidResponses Stage = "all-processed-bid-responses"
+)
+
+// Action is what a Hook's Execute asks the Executor to do with the bid
+// after it returns.
+type Action int
+
+const (
+	// ActionContinue leaves HookContext as-is (aside from any Tags set).
+	ActionContinue Action = iota
+	// ActionUpdate signals Execute mutated HookContext (e.g. BidAmount) in
+	// place; propagation of the mutation is implicit, this just marks the
+	// Outcome so operators can see which hook changed something.
+	ActionUpdate
+	// ActionReject stops the remaining hooks in the current stage and
+	// tells the caller to abandon this tick's bid.
+	ActionReject
+)
+
+// HookContext is threaded through every stage of a single bidding-loop
+// tick. Hooks read and, where permitted, mutate it directly - Go's pointer
+// semantics stand in for prebid-server's per-stage mutation payloads.
+type HookContext struct {
+	CustomerID   string
+	Spend        float64
+	Score        float64
+	PredictedLTV float64
+	Explanation  string
+	BidAmount    float64
+	Decision     string
+	Mode         string
+
+	// RawResponse is set once StageRawBidderResponse runs, carrying
+	// whatever connector.PlaceBid returned (kept as interface{} so this
+	// package doesn't import cmd/syncflow/connectors just for one field).
+	RawResponse interface{}
+
+	// Tags accumulates analytics tags hooks attach along the way; flows
+	// into the audit log's LogDecision call as-is.
+	Tags map[string]string
+
+	// RejectReason is set by whichever hook returned ActionReject.
+	RejectReason string
+}
+
+// Result is what a Hook's Execute returns.
+type Result struct {
+	Action       Action
+	RejectReason string // required when Action is ActionReject
+}
+
+// Hook is one module an operator registers against one or more Stages.
+type Hook interface {
+	// Name identifies this hook in Outcome and log output.
+	Name() string
+	// Execute runs the hook's logic against hc, mutating it in place for
+	// an ActionUpdate. Implementations should respect ctx's deadline
+	// rather than relying solely on the Executor's enforcement.
+	Execute(ctx context.Context, hc *HookContext) Result
+}
+
+// Outcome records what happened when the Executor ran one Hook at one
+// Stage. ExecuteStage returns them to its caller; main.go's ticker loop
+// does not pass them on, so only HookContext's Decision, Mode and Tags
+// reach the audit log, not which hook vetoed or altered a bid.
+type Outcome struct {
+	HookName     string
+	Stage        Stage
+	Action       Action
+	RejectReason string
+	Duration     time.Duration
+	// Err is set if the hook timed out or panicked; Action is always
+	// ActionContinue in that case so a misbehaving hook degrades to a
+	// no-op rather than blocking the bid.
+	Err error
+}
+
+// Registry holds the hooks registered at each Stage, run in registration
+// order.
+type Registry struct {
+	stages map[Stage][]Hook
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{stages: make(map[Stage][]Hook)}
+}
+
+// Register appends hook to the ordered list run at stage.
+func (r *Registry) Register(stage Stage, hook Hook) {
+	r.stages[stage] = append(r.stages[stage], hook)
+}
+
+// Hooks returns the hooks registered at stage, in registration order.
+func (r *Registry) Hooks(stage Stage) []Hook {
+	return r.stages[stage]
+}
@@ -0,0 +1,26 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRegistry_HooksReturnsRegistrationOrder(t *testing.T) {
+	r := NewRegistry()
+	first := FuncHook{HookName: "first", Fn: func(ctx context.Context, hc *HookContext) Result { return Result{Action: ActionContinue} }}
+	second := FuncHook{HookName: "second", Fn: func(ctx context.Context, hc *HookContext) Result { return Result{Action: ActionContinue} }}
+	r.Register(StageEntrypoint, first)
+	r.Register(StageEntrypoint, second)
+
+	got := r.Hooks(StageEntrypoint)
+	if len(got) != 2 || got[0].Name() != "first" || got[1].Name() != "second" {
+		t.Fatalf("expected [first second], got %+v", got)
+	}
+}
+
+func TestRegistry_HooksEmptyForUnregisteredStage(t *testing.T) {
+	r := NewRegistry()
+	if got := r.Hooks(StageAuctionResponse); len(got) != 0 {
+		t.Fatalf("expected no hooks registered, got %+v", got)
+	}
+}
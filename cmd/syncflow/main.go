@@ -3,59 +3,327 @@ package main
 import (
 	"bytes"
 	"context"
-	"encoding/csv"
+	"crypto/rand"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
-	"strconv"
-	"sync"
 	"time"
 
 	pb "github.com/user/kiki-agent/api/pb"
+	"github.com/user/kiki-agent/cmd/syncflow/alerts"
+	"github.com/user/kiki-agent/cmd/syncflow/audit"
 	"github.com/user/kiki-agent/cmd/syncflow/connectors"
-
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/analytics"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/auction"
+	"github.com/user/kiki-agent/cmd/syncflow/connectors/ingest"
+	"github.com/user/kiki-agent/cmd/syncflow/hooks"
+	"github.com/user/kiki-agent/cmd/syncflow/predict"
+	"github.com/user/kiki-agent/cmd/syncflow/reconcile"
+	"github.com/user/kiki-agent/cmd/syncflow/store"
+
+	"github.com/go-redis/redis/v8"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-// Data Structures
-type SpendRecord struct {
-	Timestamp time.Time
-	Amount    float64
+// openrtbGlobalBudget caps total spend across every impression the
+// /openrtb2/auction endpoint fans out, independent of each connector's
+// own BudgetManager.
+const openrtbGlobalBudget = 1000.0
+
+// openrtbBaseURL is embedded in every winning bid's nurl/burl so an
+// exchange's win/billing callbacks land back on this agent.
+const openrtbBaseURL = "https://kiki-agent.com/openrtb2"
+
+// rtbNoticeSecret returns the key ingest.Handler signs every nurl/burl
+// with, from RTB_NOTICE_SECRET. If it's unset, a random secret is
+// generated for this process only - notices signed before a restart
+// simply stop validating, which is the safe failure mode for a secret
+// that guards RecordSpend.
+func rtbNoticeSecret() []byte {
+	if secret := os.Getenv("RTB_NOTICE_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalf("Failed to generate RTB notice signing secret: %v", err)
+	}
+	log.Println("⚠️ RTB_NOTICE_SECRET not set - generated an ephemeral one; win/billing notices won't validate across a restart")
+	return secret
 }
 
+// ltvCacheTTL bounds how long a cached LTV prediction is trusted before
+// fetchLTV calls back into the prediction brain.
+const ltvCacheTTL = 5 * time.Minute
+
 // Global Safety State
 var (
-	spendHistory   []SpendRecord
-	mu             sync.Mutex
 	maxBurstBudget = 500.0
-	cache          map[string]PredictionResult
-	cacheMu        sync.Mutex
+
+	// appStore replaces the old process-local cache map and spendHistory
+	// slice, so every syncflow replica shares one semantic cache and one
+	// burst-budget window instead of each tracking its own. Set by
+	// initStore before main's ticker loop starts.
+	appStore store.Store
+
+	// recorders replaces LogDecision's ad-hoc CSV append with the same
+	// analytics.Recorder fan-out google_ads_smart.go's PlaceBid already
+	// fires. Set by initRecorders before main's ticker loop starts.
+	recorders []connectors.Recorder
+
+	// hookExecutor runs the builtin hooks registered in initHooks against
+	// every tick of the bidding loop. Set by initHooks before main's
+	// ticker loop starts.
+	hookExecutor *hooks.Executor
+
+	// alertDispatcher routes anomaly/safety/brain-offline notifications to
+	// whichever Messenger sinks initAlerts configured, replacing the old
+	// log-only "ANOMALY ALERT"/"SAFETY ALERT" lines. Set by initAlerts
+	// before main's ticker loop starts; nil is a safe no-op.
+	alertDispatcher *alerts.Dispatcher
+
+	// resumableBidder replays bids deferred while the platform connector's
+	// circuit was OPEN (see connectors.ResumableBidder), once it recovers.
+	// Set by initResumableBidder; nil unless REDIS_ADDR is configured and
+	// connector happens to implement connectors.SmartConnector - the same
+	// feature-gate-by-type-assertion the /connectors/fallback/stats route
+	// below already uses.
+	resumableBidder *connectors.ResumableBidder
 )
 
-type PredictionResult struct {
-	LTV         float64
-	Explanation string
-	Timestamp   time.Time
+// resumableBidderPollInterval is how often resumableBidder checks whether
+// its connector's circuit has recovered enough to replay deferred bids.
+const resumableBidderPollInterval = 2 * time.Second
+
+// anomalyLTVThreshold is the predicted-LTV value above which a tick is
+// tagged "anomaly" - the same threshold main's ticker loop checked inline
+// before the anomaly-tag hook took over.
+const anomalyLTVThreshold = 500.0
+
+// minBidFloor is the smallest bid raw-bidder-request hooks will let
+// through; anything the caller computed below it is raised to this floor
+// rather than losing the auction outright on an under-floor bid.
+const minBidFloor = 1.0
+
+// initHooks registers the builtin hooks around fetchLTV -> checkBudget ->
+// PlaceBid: a budget veto and a bid floor at raw-bidder-request, and an
+// anomaly tag at auction-response. Operators can add more via
+// hookExecutor.Registry.Register without recompiling this function.
+func initHooks() {
+	registry := hooks.NewRegistry()
+	registry.Register(hooks.StageRawBidderRequest, hooks.NewBudgetVetoHook(CheckBudget))
+	registry.Register(hooks.StageRawBidderRequest, hooks.NewMinBidFloorHook(minBidFloor))
+	registry.Register(hooks.StageAuctionResponse, hooks.NewAnomalyTagHook(anomalyLTVThreshold))
+	hookExecutor = hooks.NewExecutor(registry, 50*time.Millisecond)
 }
 
-func fetchLTV(client pb.LTVServiceClient, customerID string, spend, score float64) (float64, string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+// initAlerts sets alertDispatcher to fan out to whichever Messenger sinks
+// are configured via environment variables, falling back to a
+// NullMessenger (i.e. alerts go nowhere but still run through the same
+// dedup/cooldown path) when none are set.
+func initAlerts() {
+	var messengers []alerts.Messenger
+
+	if url := os.Getenv("ALERTS_WEBHOOK_URL"); url != "" {
+		messengers = append(messengers, alerts.NewWebhookMessenger(url))
+	}
+	if routingKey := os.Getenv("ALERTS_PAGERDUTY_ROUTING_KEY"); routingKey != "" {
+		messengers = append(messengers, alerts.NewPagerDutyMessenger(routingKey))
+	}
+	if host := os.Getenv("ALERTS_SMTP_HOST"); host != "" {
+		to := os.Getenv("ALERTS_SMTP_TO")
+		messengers = append(messengers, alerts.NewEmailMessenger(
+			host,
+			os.Getenv("ALERTS_SMTP_USERNAME"),
+			os.Getenv("ALERTS_SMTP_PASSWORD"),
+			os.Getenv("ALERTS_SMTP_FROM"),
+			[]string{to},
+		))
+	}
+	if len(messengers) == 0 {
+		messengers = append(messengers, alerts.NullMessenger{})
+	}
+
+	alertDispatcher = alerts.NewDispatcher(0, messengers...)
+}
+
+// auditLogPath is where the rotating JSONL sink writes decision/bid
+// events, replacing the old fixed ../../audit_log.csv.
+const auditLogPath = "../../audit_log.jsonl"
+
+// initRecorders sets recorders to a rotating JSONL file sink, plus an HTTP
+// sink when ANALYTICS_HTTP_URL is configured so events can also stream to
+// an external billing/BI system.
+func initRecorders() {
+	fileRecorder, err := analytics.NewRotatingRecorder(auditLogPath, 0, 0)
+	if err != nil {
+		log.Printf("⚠️ Could not open audit log %s: %v", auditLogPath, err)
+	} else {
+		recorders = append(recorders, fileRecorder)
+	}
+
+	if url := os.Getenv("ANALYTICS_HTTP_URL"); url != "" {
+		httpRecorder := analytics.NewHTTPRecorder(url)
+		if token := os.Getenv("ANALYTICS_HTTP_AUTH_TOKEN"); token != "" {
+			httpRecorder.Headers = map[string]string{"Authorization": "Bearer " + token}
+		}
+		recorders = append(recorders, httpRecorder)
+	}
+}
+
+// logDecision fires LogDecision against every configured recorder,
+// replacing the old LogDecision function's direct CSV write. tags is
+// whatever the hook pipeline attached to this tick, if anything.
+func logDecision(customerID string, predictedLTV, bidAmount float64, decision, mode string, tags map[string]string) {
+	for _, r := range recorders {
+		r.LogDecision(customerID, predictedLTV, bidAmount, decision, mode, tags)
+	}
+}
+
+// reconcileWorker asynchronously matches predicted LTV against ground
+// truth arriving days or weeks after a bid, backfilling
+// AuditEntry.ActualLTV once a platform's revenue posts. Nil when neither
+// RECONCILE_BOLT_PATH nor AUDIT_POSTGRES_DSN is configured - reconciliation
+// is opt-in, not a hard startup dependency.
+var reconcileWorker *reconcile.Worker
+
+// reconcileStore is kept alongside reconcileWorker only so closeReconcile
+// can release it; Worker itself just holds the Store interface.
+var reconcileStore reconcile.Store
+
+// initReconcile wires up the reconcile package: a Store (BoltDB if
+// RECONCILE_BOLT_PATH is set, otherwise Postgres if AUDIT_POSTGRES_DSN is
+// set - the same DSN auditsink.PostgresSink and PostgresCursorStore
+// share), an AuditLogger for writeback, and an ActualsFetcher per platform
+// with credentials configured. Leaves reconcileWorker nil (and logs why)
+// if no Store backend is configured.
+func initReconcile() {
+	var store reconcile.Store
+	var err error
+	switch {
+	case os.Getenv("RECONCILE_BOLT_PATH") != "":
+		store, err = reconcile.NewBoltStore(os.Getenv("RECONCILE_BOLT_PATH"))
+	case os.Getenv("AUDIT_POSTGRES_DSN") != "":
+		store, err = reconcile.NewPostgresStore(os.Getenv("AUDIT_POSTGRES_DSN"))
+	default:
+		log.Println("ℹ️ Reconciliation disabled: set RECONCILE_BOLT_PATH or AUDIT_POSTGRES_DSN to enable")
+		return
+	}
+	if err != nil {
+		log.Printf("⚠️ Reconciliation disabled: %v", err)
+		return
+	}
+	reconcileStore = store
+
+	auditLogger, err := audit.NewAuditLogger(os.Getenv("AUDIT_POSTGRES_DSN"), 0, 0)
+	if err != nil {
+		log.Printf("⚠️ Reconciliation disabled: opening audit logger: %v", err)
+		return
+	}
+
+	worker := reconcile.NewWorker(store, auditLogger, reconcile.NewMetrics())
+	if url := os.Getenv("SALESFORCE_INSTANCE_URL"); url != "" {
+		worker.Fetchers["salesforce"] = reconcile.NewSalesforceActualsFetcher(url, os.Getenv("SALESFORCE_ACCESS_TOKEN"))
+	}
+	if key := os.Getenv("STRIPE_API_KEY"); key != "" {
+		worker.Fetchers["stripe"] = reconcile.NewStripeActualsFetcher(key)
+	}
+	if project := os.Getenv("BIGQUERY_PROJECT_ID"); project != "" {
+		worker.Fetchers["bigquery"] = reconcile.NewBigQueryActualsFetcher(
+			project, os.Getenv("BIGQUERY_DATASET"), os.Getenv("BIGQUERY_TABLE"), os.Getenv("BIGQUERY_ACCESS_TOKEN"))
+	}
+
+	reconcileWorker = worker
+	go worker.Run(context.Background())
+}
+
+// closeReconcile releases reconcileStore's underlying resource, if
+// reconciliation was enabled.
+func closeReconcile() {
+	if reconcileStore != nil {
+		if err := reconcileStore.Close(); err != nil {
+			log.Printf("⚠️ Reconcile store close failed: %v", err)
+		}
+	}
+}
+
+// closeRecorders flushes and releases every recorder that holds a
+// resource (a file handle, a background flush timer); recorders that
+// don't (e.g. MessageBusRecorder, whose Publisher is caller-owned) are
+// simply skipped.
+func closeRecorders() {
+	for _, r := range recorders {
+		if c, ok := r.(interface{ Close() error }); ok {
+			if err := c.Close(); err != nil {
+				log.Printf("⚠️ Recorder close failed: %v", err)
+			}
+		}
+	}
+}
+
+// initStore sets appStore to a RedisStore when REDIS_ADDR is configured,
+// falling back to an in-memory store (the original single-replica
+// behavior) otherwise.
+func initStore() {
+	campaigns := []string{"campaign_123"}
+
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		appStore = store.NewMemoryStore(campaigns)
+		return
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+	redisStore, err := store.NewRedisStore(context.Background(), rdb, "syncflow", campaigns)
+	if err != nil {
+		log.Printf("⚠️ Could not initialize Redis store, falling back to in-memory: %v", err)
+		rdb.Close()
+		appStore = store.NewMemoryStore(campaigns)
+		return
+	}
+	appStore = redisStore
+}
+
+// initResumableBidder wires up resumableBidder to replay bids deferred on
+// connector while its circuit was OPEN, queued under platform's scoped
+// syncflow-queue key (see connectors.QueueKeyFor). A no-op unless REDIS_ADDR
+// is configured and connector implements connectors.SmartConnector - so
+// calling this with any PlatformConnector is always safe.
+func initResumableBidder(ctx context.Context, connector connectors.PlatformConnector, platform string) {
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		return
+	}
+	smart, ok := connector.(connectors.SmartConnector)
+	if !ok {
+		return
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+	resumableBidder = connectors.NewResumableBidder(platform, smart, rdb, connectors.QueueKeyFor(platform))
+	resumableBidder.Start(ctx, resumableBidderPollInterval)
+}
+
+func fetchLTV(client *predict.Client, customerID string, spend, score float64) (float64, string) {
+	// 250ms covers both the appStore.GetLTV round trip (now network-bound
+	// when Redis-backed) and the predict.Client call (hedge + retries) on
+	// a cache miss; predict.Client inherits this deadline rather than
+	// setting its own.
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
 	defer cancel()
 
 	// Check semantic cache first
-	key := "ltv:" + customerID
-	cacheMu.Lock()
-	if result, ok := cache[key]; ok && time.Since(result.Timestamp) < 5*time.Minute {
-		cacheMu.Unlock()
-		log.Printf(" CACHE HIT: Using cached LTV %.2f for %s", result.LTV, customerID)
-		return result.LTV, result.Explanation
+	if cached, ok, err := appStore.GetLTV(ctx, customerID); err != nil {
+		log.Printf("⚠️ LTV cache read failed for %s: %v", customerID, err)
+	} else if ok {
+		log.Printf(" CACHE HIT: Using cached LTV %.2f for %s", cached.LTV, customerID)
+		return cached.LTV, cached.Explanation
 	}
-	cacheMu.Unlock()
 
-	// 1. Attempt High-Performance gRPC Call
+	// 1. Attempt High-Performance gRPC Call (hedged, retried, bounded by
+	// predict.Client)
 	resp, err := client.PredictLTV(ctx, &pb.LTVRequest{
 		CustomerId:      customerID,
 		RecentSpend:     spend,
@@ -65,21 +333,28 @@ func fetchLTV(client pb.LTVServiceClient, customerID string, spend, score float6
 	if err != nil {
 		// 2. DEGRADED MODE: Fallback to Heuristic
 		log.Printf(" BRAIN OFFLINE: Using Safety Heuristic for %s", customerID)
+		alertDispatcher.Push(alerts.Message{
+			Fingerprint: "fetchLTV:brain_offline",
+			Severity:    alerts.SeverityWarning,
+			Title:       "LTV prediction brain offline",
+			Body:        fmt.Sprintf("PredictLTV call failed for %s, using degraded heuristic: %v", customerID, err),
+			Source:      "syncflow.fetchLTV",
+		})
 		ltv := spend * 1.1
 		explanation := fmt.Sprintf("Degraded mode: %.2f * 1.1 = %.2f", spend, ltv)
 		// Cache even degraded results
-		cacheMu.Lock()
-		cache[key] = PredictionResult{LTV: ltv, Explanation: explanation, Timestamp: time.Now()}
-		cacheMu.Unlock()
+		if err := appStore.PutLTV(ctx, customerID, store.Prediction{LTV: ltv, Explanation: explanation}, ltvCacheTTL); err != nil {
+			log.Printf("⚠️ LTV cache write failed for %s: %v", customerID, err)
+		}
 		return ltv, explanation
 	}
 
 	ltv := resp.PredictedLtv
 	explanation := resp.Explanation
 	// Cache the result
-	cacheMu.Lock()
-	cache[key] = PredictionResult{LTV: ltv, Explanation: explanation, Timestamp: time.Now()}
-	cacheMu.Unlock()
+	if err := appStore.PutLTV(ctx, customerID, store.Prediction{LTV: ltv, Explanation: explanation}, ltvCacheTTL); err != nil {
+		log.Printf("⚠️ LTV cache write failed for %s: %v", customerID, err)
+	}
 	log.Printf(" CACHE MISS: Computed LTV %.2f for %s", ltv, customerID)
 
 	return ltv, explanation
@@ -97,6 +372,12 @@ func checkBudget(ltv float64) bool {
 }
 
 func recordSpend(amount float64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := appStore.RecordSpend(ctx, amount); err != nil {
+		log.Printf("⚠️ Recording spend in store failed: %v", err)
+	}
+
 	url := fmt.Sprintf("http://localhost:8081/spend?amount=%.2f", amount)
 	resp, err := http.Post(url, "application/json", bytes.NewBuffer(nil))
 	if err != nil {
@@ -112,34 +393,14 @@ func recordSpend(amount float64) {
 }
 
 func CheckBudget() bool {
-	mu.Lock()
-	defer mu.Unlock()
-	total := 0.0
-	for _, r := range spendHistory {
-		if time.Since(r.Timestamp) < 10*time.Minute {
-			total += r.Amount
-		}
-	}
-	return total < maxBurstBudget
-}
-
-// LogDecision writes a bidding decision to audit_log.csv
-func LogDecision(customerID string, predictedLTV, bidAmount float64, decision, mode string) {
-	file, err := os.OpenFile("../../audit_log.csv", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	total, err := appStore.WindowSpend(ctx, 10*time.Minute)
 	if err != nil {
-		log.Printf("Error opening audit log: %v", err)
-		return
-	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	timestamp := time.Now().Format(time.RFC3339)
-	record := []string{timestamp, customerID, strconv.FormatFloat(predictedLTV, 'f', 2, 64), strconv.FormatFloat(bidAmount, 'f', 2, 64), decision, mode}
-	if err := writer.Write(record); err != nil {
-		log.Printf("Error writing to audit log: %v", err)
+		log.Printf("⚠️ Budget window check failed: %v", err)
+		return false // Fail safe: deny if the store can't be reached
 	}
+	return total < maxBurstBudget
 }
 
 func main() {
@@ -149,12 +410,32 @@ func main() {
 		log.Fatalf("did not connect: %v", err)
 	}
 	defer conn.Close()
-	client := pb.NewLTVServiceClient(conn)
+	client := predict.NewClient(pb.NewLTVServiceClient(conn), predict.DefaultSyncValueClientConfig())
 
 	log.Println(" KIKI SyncFlow gRPC Agent Online...")
 
-	// Initialize in-memory semantic cache (for demo; use Redis in production)
-	cache = make(map[string]PredictionResult)
+	// Initialize the shared semantic cache / spend window / campaign
+	// rotation (in-memory by default, Redis-backed when REDIS_ADDR is set)
+	initStore()
+	defer appStore.Close()
+
+	// Initialize the audit trail (rotating JSONL file, plus HTTP if
+	// configured), replacing the old fixed-path CSV append.
+	initRecorders()
+	defer closeRecorders()
+
+	// Register the builtin hooks around fetchLTV -> checkBudget -> PlaceBid.
+	initHooks()
+
+	// Wire alert routing for anomaly/safety/brain-offline events, falling
+	// back to a NullMessenger sink when no ALERTS_* env vars are set.
+	initAlerts()
+
+	// Start the LTV reconciliation worker, if a Store backend is
+	// configured - backfills AuditEntry.ActualLTV once ground-truth
+	// revenue posts days or weeks after a bid.
+	initReconcile()
+	defer closeReconcile()
 
 	// Start health check server in background
 	go func() {
@@ -189,6 +470,54 @@ func main() {
 
 	log.Printf("Platform Status: %s", connector.GetStatus())
 
+	// Start replaying any bids deferred while this connector's circuit was
+	// OPEN, once it recovers.
+	initResumableBidder(ctx, connector, string(connectorConfig.Type))
+
+	// Register the connector with an Auctioneer and serve the inbound
+	// OpenRTB 2.5 endpoint, so the agent can also respond to bid requests
+	// an exchange sends it, not just the ticker-driven simulation below.
+	auctioneer := auction.NewAuctioneer(auction.HighestBid, openrtbGlobalBudget)
+	auctioneer.Register(string(connectorConfig.Type), connector)
+	rtbHandler := ingest.NewHandler(auctioneer, openrtbBaseURL, recordSpend, rtbNoticeSecret())
+
+	go func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/openrtb2/auction", rtbHandler.ServeAuction)
+		mux.HandleFunc("/openrtb2/win-notice", rtbHandler.ServeWinNotice)
+		mux.HandleFunc("/openrtb2/billing", rtbHandler.ServeBillingNotice)
+		// Only the hardcoded GoogleAds connector above has a
+		// FallbackEngine to report on; this no-ops if that type ever
+		// changes without this route being updated too.
+		if gac, ok := connector.(*connectors.GoogleAdsSmartConnector); ok {
+			mux.HandleFunc("/connectors/fallback/stats", gac.FallbackEngine.ServeStats)
+		}
+		// Stands in for the SyncFlowService admin RPC until api/pb grows a
+		// real ForceReconcile method - see admin_reconcile.go.
+		if reconcileWorker != nil {
+			mux.HandleFunc("/admin/reconcile/force", serveForceReconcile(reconcileWorker))
+			mux.HandleFunc("/reconcile/metrics", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+				w.Write([]byte(reconcileWorker.Metrics.Render()))
+			})
+		}
+		// Lets reconcile_example.go's rewind subcommand correct this
+		// process's actual live BudgetManager instead of a throwaway one
+		// it builds itself - see admin_reconcile.go.
+		if dsn := os.Getenv("AUDIT_POSTGRES_DSN"); dsn != "" {
+			ledger, err := connectors.NewPostgresSpendLedger(dsn)
+			if err != nil {
+				log.Printf("⚠️ /admin/reconcile/rewind disabled: opening spend ledger: %v", err)
+			} else {
+				mux.HandleFunc("/admin/reconcile/rewind", serveRewind(connector, ledger))
+			}
+		}
+		log.Println("OpenRTB ingestion server starting on :8084")
+		if err := http.ListenAndServe(":8084", mux); err != nil {
+			log.Printf("OpenRTB ingestion server error: %v", err)
+		}
+	}()
+
 	ticker := time.NewTicker(2 * time.Second)
 
 	for range ticker.C {
@@ -197,55 +526,96 @@ func main() {
 		spend := 50.0
 		score := 0.8
 
+		hc := &hooks.HookContext{CustomerID: customerID, Spend: spend, Score: score, Mode: "normal"}
+		hookExecutor.ExecuteStage(ctx, hooks.StageEntrypoint, hc)
+		if hc.RejectReason != "" {
+			log.Printf("🚫 Hook rejected tick at entrypoint: %s", hc.RejectReason)
+			logDecision(customerID, 0, 0, "Rejected", hc.Mode, hc.Tags)
+			continue
+		}
+
 		ltv, explanation := fetchLTV(client, customerID, spend, score)
+		hc.PredictedLTV = ltv
+		hc.Explanation = explanation
 
 		if !checkBudget(ltv) {
 			log.Println("  SAFETY ALERT: Bid validation failed. Bidding paused.")
+			alertDispatcher.Push(alerts.Message{
+				Fingerprint: "syncflow:safety_budget_check",
+				Severity:    alerts.SeverityCritical,
+				Title:       "Bidding paused: budget check failed",
+				Body:        fmt.Sprintf("checkBudget rejected customer %s at predicted LTV %.2f", customerID, ltv),
+				Source:      "syncflow.main",
+			})
 			continue
 		}
 
 		// Log AI explanation for transparency
 		log.Printf(" AI Explanation: %s", explanation)
 
-		// Anomaly detection: flag unusually high LTV predictions
-		mode := "normal"
-		if ltv > 500 {
-			log.Printf(" ANOMALY ALERT: Unusual LTV prediction of %.2f detected", ltv)
-			mode = "anomaly"
-		}
-
 		var decision string
 		var bidAmount float64
 		if ltv > 100 && checkBudget(ltv) {
-			decision = "Placed"
-			bidAmount = 10.0
-
-			// Place bid via connector
-			bidReq := &connectors.BidRequest{
-				CustomerID:   customerID,
-				PredictedLTV: ltv,
-				BidAmount:    bidAmount,
-				Explanation:  explanation,
-				Timestamp:    time.Now(),
-				CampaignID:   "campaign_123",
-				AudienceID:   "audience_456",
-			}
+			hc.BidAmount = 10.0
+			hookExecutor.ExecuteStage(ctx, hooks.StageRawBidderRequest, hc)
 
-			bidResp, err := connector.PlaceBid(ctx, bidReq)
-			if err != nil {
-				log.Printf("❌ Bid placement failed: %v", err)
+			if hc.RejectReason != "" {
+				decision = "Rejected"
+				bidAmount = 0.0
+				log.Printf("🚫 Hook rejected bid at raw-bidder-request: %s", hc.RejectReason)
 			} else {
-				log.Printf("✅ Bid placed: %s (ID: %s)", bidResp.Message, bidResp.BidID)
+				decision = "Placed"
+				bidAmount = hc.BidAmount
+
+				campaignID := "campaign_123"
+				if next, err := appStore.NextCampaigns(ctx, 1); err != nil {
+					log.Printf("⚠️ Campaign rotation failed, reusing default: %v", err)
+				} else if len(next) > 0 {
+					campaignID = next[0]
+				}
+
+				// Place bid via connector
+				bidReq := &connectors.BidRequest{
+					CustomerID:   customerID,
+					PredictedLTV: ltv,
+					BidAmount:    bidAmount,
+					Explanation:  explanation,
+					Timestamp:    time.Now(),
+					CampaignID:   campaignID,
+					AudienceID:   "audience_456",
+				}
+
+				bidResp, err := connector.PlaceBid(ctx, bidReq)
+				hc.RawResponse = bidResp
+				hookExecutor.ExecuteStage(ctx, hooks.StageRawBidderResponse, hc)
+				if err != nil {
+					log.Printf("❌ Bid placement failed: %v", err)
+				} else {
+					log.Printf("✅ Bid placed: %s (ID: %s)", bidResp.Message, bidResp.BidID)
+				}
+
+				recordSpend(bidAmount)
 			}
-
-			recordSpend(bidAmount)
 		} else {
 			decision = "Skipped"
 			bidAmount = 0.0
 			log.Println("Low Value - Skipping")
 		}
 
+		hc.Decision = decision
+		hookExecutor.ExecuteStage(ctx, hooks.StageAuctionResponse, hc)
+		if hc.Mode == "anomaly" {
+			alertDispatcher.Push(alerts.Message{
+				Fingerprint: "syncflow:anomaly_ltv",
+				Severity:    alerts.SeverityWarning,
+				Title:       "Anomalous predicted LTV",
+				Body:        fmt.Sprintf("Customer %s predicted LTV %.2f exceeded anomaly threshold %.2f", customerID, ltv, anomalyLTVThreshold),
+				Source:      "syncflow.anomaly",
+			})
+		}
+		hookExecutor.ExecuteStage(ctx, hooks.StageAllProcessedBidResponses, hc)
+
 		// Audit Logic: Log Decision
-		LogDecision(customerID, ltv, bidAmount, decision, mode)
+		logDecision(customerID, ltv, bidAmount, hc.Decision, hc.Mode, hc.Tags)
 	}
 }
@@ -0,0 +1,235 @@
+// Package predict wraps pb.LTVServiceClient with the resilience behavior a
+// raw gRPC stub doesn't give you for free: deadline inheritance from the
+// caller's context, hedged requests against tail latency, jittered
+// exponential backoff on transient codes, and a bounded in-flight
+// semaphore so a traffic spike can't overwhelm the LTV prediction brain.
+package predict
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/user/kiki-agent/api/pb"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SyncValueClientConfig controls the shield.Policy chain (Retry → Timeout →
+// CircuitBreaker → Fallback) Client composes around the SyncValue gRPC
+// stub, plus the hedging/concurrency behavior layered inside it.
+type SyncValueClientConfig struct {
+	// HedgeDelay is how long the first attempt gets before Client fires a
+	// second, parallel PredictLTV call and takes whichever returns first.
+	// 0 disables hedging. Hedging happens innermost, inside the policy
+	// chain below, since it's an attempt-level optimization rather than a
+	// whole-call resilience layer.
+	HedgeDelay time.Duration
+
+	// RetryPolicy governs per-attempt jittered exponential backoff on
+	// RetryableCodes. Shared with shield's HTTP-facing retry logic so
+	// operators tune one mental model for both. Nil disables the Retry
+	// layer.
+	RetryPolicy *shield.RetryPolicy
+
+	// Timeout bounds each Retry attempt, cancelling the inner context if
+	// exceeded. Zero disables the Timeout layer.
+	Timeout time.Duration
+
+	// CircuitBreaker trips the chain open after repeated failures/latency
+	// spikes, short-circuiting with shield.ErrCircuitOpen until it resets.
+	// Nil disables the CircuitBreaker layer.
+	CircuitBreaker *shield.CircuitBreaker
+
+	// Fallback, if set, is called with the chain's final error and may
+	// synthesize a degraded-mode response instead of propagating it. Left
+	// nil by default: fetchLTV already layers its own heuristic fallback
+	// and alerting on a PredictLTV error, so this is an extension point
+	// for callers who want one baked into the client itself rather than a
+	// second one.
+	Fallback func(ctx context.Context, req *pb.LTVRequest, err error) (*pb.LTVResponse, error)
+
+	// MaxInFlight bounds how many PredictLTV calls (including hedges) can
+	// be outstanding at once; additional callers block until a slot frees
+	// up or their context is done.
+	MaxInFlight int
+}
+
+// DefaultSyncValueClientConfig is tuned for fetchLTV's existing 250ms
+// cache+gRPC budget: a hedge fires after a fixed 80ms HedgeDelay (not
+// derived from the latency Client.metrics records), each call makes at
+// most two attempts with a short jittered backoff between them, a per-attempt
+// timeout leaves room for a retry within that budget, a breaker trips after
+// repeated failures, and at most 20 predictions are in flight across the
+// process at once. Fallback is left nil; see SyncValueClientConfig.Fallback.
+func DefaultSyncValueClientConfig() SyncValueClientConfig {
+	return SyncValueClientConfig{
+		HedgeDelay: 80 * time.Millisecond,
+		RetryPolicy: &shield.RetryPolicy{
+			MaxAttempts:       2,
+			InitialBackoff:    20 * time.Millisecond,
+			MaxBackoff:        100 * time.Millisecond,
+			BackoffMultiplier: 2.0,
+			JitterFraction:    0.25,
+			IsRetryable: func(err error) bool {
+				return isRetryableCode(status.Code(err))
+			},
+		},
+		Timeout:        150 * time.Millisecond,
+		CircuitBreaker: shield.NewCircuitBreaker(),
+		MaxInFlight:    20,
+	}
+}
+
+// Client is a resilient front for pb.LTVServiceClient.
+type Client struct {
+	stub    pb.LTVServiceClient
+	cfg     SyncValueClientConfig
+	chain   shield.Policy
+	sem     chan struct{}
+	metrics *Metrics
+}
+
+// NewClient wraps stub with cfg's policy chain and hedging/concurrency
+// behavior.
+func NewClient(stub pb.LTVServiceClient, cfg SyncValueClientConfig) *Client {
+	maxInFlight := cfg.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+	return &Client{
+		stub:    stub,
+		cfg:     cfg,
+		chain:   buildChain(cfg),
+		sem:     make(chan struct{}, maxInFlight),
+		metrics: NewMetrics(),
+	}
+}
+
+// buildChain composes cfg's Retry/Timeout/CircuitBreaker layers outermost
+// first, skipping any layer left nil/zero so callers can opt out of part of
+// the default chain.
+func buildChain(cfg SyncValueClientConfig) shield.Policy {
+	var policies []shield.Policy
+	if cfg.RetryPolicy != nil {
+		policies = append(policies, cfg.RetryPolicy)
+	}
+	if cfg.Timeout > 0 {
+		policies = append(policies, shield.TimeoutPolicy{Timeout: cfg.Timeout})
+	}
+	if cfg.CircuitBreaker != nil {
+		policies = append(policies, cfg.CircuitBreaker)
+	}
+	return shield.Compose(policies...)
+}
+
+// Metrics exposes the client's Prometheus-renderable counters/histograms.
+func (c *Client) Metrics() *Metrics { return c.metrics }
+
+// isRetryableCode reports whether code is worth a jittered retry - the two
+// gRPC statuses that mean "the server was momentarily unable to answer",
+// as opposed to a permanent rejection of the request itself.
+func isRetryableCode(code codes.Code) bool {
+	return code == codes.Unavailable || code == codes.DeadlineExceeded
+}
+
+// PredictLTV calls the wrapped stub under ctx's deadline, running it through
+// c.chain (Retry → Timeout → CircuitBreaker, plus Fallback if cfg.Fallback
+// is set) with hedging per cfg.HedgeDelay as the innermost attempt. It
+// returns the first successful response; if the chain is exhausted, it
+// returns the last error seen.
+func (c *Client) PredictLTV(ctx context.Context, req *pb.LTVRequest) (*pb.LTVResponse, error) {
+	attempt := func(ctx context.Context) (*pb.LTVResponse, error) {
+		select {
+		case c.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		defer func() { <-c.sem }()
+
+		start := time.Now()
+		resp, err := c.stub.PredictLTV(ctx, req)
+		c.metrics.RecordAttempt(time.Since(start), err == nil)
+		return resp, err
+	}
+
+	chain := c.chain
+	if c.cfg.Fallback != nil {
+		chain = shield.Compose(c.chain, &shield.FallbackPolicy{
+			Fallback: func(ctx context.Context, err error) (interface{}, error) {
+				return c.cfg.Fallback(ctx, req, err)
+			},
+		})
+	}
+
+	result, err := chain.Execute(ctx, func(ctx context.Context) (interface{}, error) {
+		resp, err := c.hedgedAttempt(ctx, attempt)
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	})
+	if err != nil {
+		c.metrics.RecordFallback()
+		return nil, fmt.Errorf("predict: PredictLTV failed: %w", err)
+	}
+	return result.(*pb.LTVResponse), nil
+}
+
+// hedgedAttempt runs attempt once, and again after cfg.HedgeDelay if the
+// first hasn't returned yet, taking whichever finishes first and
+// cancelling the loser.
+func (c *Client) hedgedAttempt(ctx context.Context, attempt func(context.Context) (*pb.LTVResponse, error)) (*pb.LTVResponse, error) {
+	type outcome struct {
+		resp    *pb.LTVResponse
+		err     error
+		hedge   bool
+		primary bool
+	}
+
+	primaryCtx, cancelPrimary := context.WithCancel(ctx)
+	defer cancelPrimary()
+	results := make(chan outcome, 2)
+
+	go func() {
+		resp, err := attempt(primaryCtx)
+		results <- outcome{resp: resp, err: err, primary: true}
+	}()
+
+	if c.cfg.HedgeDelay <= 0 {
+		out := <-results
+		return out.resp, out.err
+	}
+
+	hedgeTimer := time.NewTimer(c.cfg.HedgeDelay)
+	defer hedgeTimer.Stop()
+
+	select {
+	case out := <-results:
+		return out.resp, out.err
+	case <-hedgeTimer.C:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	hedgeCtx, cancelHedge := context.WithCancel(ctx)
+	defer cancelHedge()
+	go func() {
+		resp, err := attempt(hedgeCtx)
+		results <- outcome{resp: resp, err: err, hedge: true}
+	}()
+
+	first := <-results
+	if first.hedge {
+		c.metrics.RecordHedgeWin()
+	}
+	if first.err != nil {
+		// The other attempt may still succeed before ctx expires; give it
+		// the rest of the deadline rather than surfacing the first error.
+		second := <-results
+		return second.resp, second.err
+	}
+	return first.resp, nil
+}
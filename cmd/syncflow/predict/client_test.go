@@ -0,0 +1,175 @@
+package predict
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/user/kiki-agent/api/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stubLTVServiceClient implements pb.LTVServiceClient by calling fn, so
+// tests can drive Client.PredictLTV without a real gRPC server.
+type stubLTVServiceClient struct {
+	fn func(ctx context.Context) (*pb.LTVResponse, error)
+}
+
+func (s *stubLTVServiceClient) PredictLTV(ctx context.Context, in *pb.LTVRequest, opts ...grpc.CallOption) (*pb.LTVResponse, error) {
+	return s.fn(ctx)
+}
+
+func TestClient_PredictLTVReturnsStubResponse(t *testing.T) {
+	want := &pb.LTVResponse{PredictedLtv: 42.5}
+	stub := &stubLTVServiceClient{fn: func(ctx context.Context) (*pb.LTVResponse, error) {
+		return want, nil
+	}}
+	c := NewClient(stub, SyncValueClientConfig{})
+
+	got, err := c.PredictLTV(context.Background(), &pb.LTVRequest{CustomerId: "cust-1"})
+	if err != nil {
+		t.Fatalf("PredictLTV failed: %v", err)
+	}
+	if got.PredictedLtv != want.PredictedLtv {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_PredictLTVRetriesRetryableCode(t *testing.T) {
+	var calls int32
+	stub := &stubLTVServiceClient{fn: func(ctx context.Context) (*pb.LTVResponse, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return nil, status.Error(codes.Unavailable, "momentarily down")
+		}
+		return &pb.LTVResponse{PredictedLtv: 1}, nil
+	}}
+	cfg := DefaultSyncValueClientConfig()
+	cfg.HedgeDelay = 0
+	c := NewClient(stub, cfg)
+
+	got, err := c.PredictLTV(context.Background(), &pb.LTVRequest{CustomerId: "cust-1"})
+	if err != nil {
+		t.Fatalf("PredictLTV failed: %v", err)
+	}
+	if got.PredictedLtv != 1 {
+		t.Fatalf("expected the retried response, got %+v", got)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("expected exactly 2 calls, got %d", calls)
+	}
+}
+
+func TestClient_PredictLTVReturnsWrappedErrorOnPermanentFailure(t *testing.T) {
+	permanent := status.Error(codes.InvalidArgument, "bad request")
+	stub := &stubLTVServiceClient{fn: func(ctx context.Context) (*pb.LTVResponse, error) {
+		return nil, permanent
+	}}
+	c := NewClient(stub, SyncValueClientConfig{})
+
+	_, err := c.PredictLTV(context.Background(), &pb.LTVRequest{CustomerId: "cust-1"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !errors.Is(err, permanent) {
+		t.Fatalf("expected the wrapped error to unwrap to the stub's error, got %v", err)
+	}
+}
+
+func TestClient_PredictLTVUsesFallbackOnError(t *testing.T) {
+	stub := &stubLTVServiceClient{fn: func(ctx context.Context) (*pb.LTVResponse, error) {
+		return nil, status.Error(codes.InvalidArgument, "bad request")
+	}}
+	cfg := SyncValueClientConfig{
+		Fallback: func(ctx context.Context, req *pb.LTVRequest, err error) (*pb.LTVResponse, error) {
+			return &pb.LTVResponse{PredictedLtv: -1}, nil
+		},
+	}
+	c := NewClient(stub, cfg)
+
+	got, err := c.PredictLTV(context.Background(), &pb.LTVRequest{CustomerId: "cust-1"})
+	if err != nil {
+		t.Fatalf("expected the fallback to absorb the error, got %v", err)
+	}
+	if got.PredictedLtv != -1 {
+		t.Fatalf("expected the fallback's response, got %+v", got)
+	}
+}
+
+func TestClient_PredictLTVHedgeWinsWhenPrimarySlow(t *testing.T) {
+	var calls int32
+	stub := &stubLTVServiceClient{fn: func(ctx context.Context) (*pb.LTVResponse, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			// Primary: blocks past the hedge delay, then gets cancelled by
+			// the hedge winning.
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &pb.LTVResponse{PredictedLtv: 2}, nil
+	}}
+	cfg := SyncValueClientConfig{HedgeDelay: 5 * time.Millisecond, MaxInFlight: 2}
+	c := NewClient(stub, cfg)
+
+	got, err := c.PredictLTV(context.Background(), &pb.LTVRequest{CustomerId: "cust-1"})
+	if err != nil {
+		t.Fatalf("PredictLTV failed: %v", err)
+	}
+	if got.PredictedLtv != 2 {
+		t.Fatalf("expected the hedge's response to win, got %+v", got)
+	}
+	if c.Metrics().hedgeWins != 1 {
+		t.Fatalf("expected RecordHedgeWin to have fired once, got %d", c.Metrics().hedgeWins)
+	}
+}
+
+func TestClient_PredictLTVRespectsMaxInFlight(t *testing.T) {
+	release := make(chan struct{})
+	inFlight := make(chan struct{}, 10)
+	stub := &stubLTVServiceClient{fn: func(ctx context.Context) (*pb.LTVResponse, error) {
+		inFlight <- struct{}{}
+		<-release
+		return &pb.LTVResponse{PredictedLtv: 1}, nil
+	}}
+	c := NewClient(stub, SyncValueClientConfig{MaxInFlight: 1})
+
+	done := make(chan struct{})
+	go func() {
+		c.PredictLTV(context.Background(), &pb.LTVRequest{CustomerId: "cust-1"})
+		done <- struct{}{}
+	}()
+
+	select {
+	case <-inFlight:
+	case <-time.After(time.Second):
+		t.Fatal("first call never reached the stub")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := c.PredictLTV(ctx, &pb.LTVRequest{CustomerId: "cust-2"})
+	if err == nil {
+		t.Fatal("expected the second call to block on the in-flight semaphore and time out")
+	}
+
+	close(release)
+	<-done
+}
+
+func TestIsRetryableCode(t *testing.T) {
+	cases := map[codes.Code]bool{
+		codes.Unavailable:      true,
+		codes.DeadlineExceeded: true,
+		codes.InvalidArgument:  false,
+		codes.OK:               false,
+	}
+	for code, want := range cases {
+		if got := isRetryableCode(code); got != want {
+			t.Errorf("isRetryableCode(%s) = %v, want %v", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package predict
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// attemptLatencyBucketsMS are cumulative "<=" cutoffs in milliseconds,
+// dense below 250ms since that's fetchLTV's whole cache+gRPC budget.
+var attemptLatencyBucketsMS = []float64{5, 10, 25, 50, 100, 150, 250, 500, 1000}
+
+// Metrics tracks Client's attempt latency, hedge-win ratio, and fallback
+// rate, rendered in the same cumulative-bucket style
+// shield.RTBMetricsCollector uses for bid price.
+type Metrics struct {
+	mu sync.Mutex
+
+	attemptCount   int64
+	attemptSuccess int64
+	latencyBuckets map[float64]int64 // cumulative count with latency <= bucket
+	latencySumMS   float64
+
+	hedgedAttempts int64
+	hedgeWins      int64
+
+	fallbacks int64 // every attempt exhausted retries
+}
+
+// NewMetrics creates an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{latencyBuckets: make(map[float64]int64)}
+}
+
+// RecordAttempt adds one PredictLTV attempt's latency and outcome to the
+// histogram - every attempt counts, including hedges and retries.
+func (m *Metrics) RecordAttempt(latency time.Duration, success bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.attemptCount++
+	if success {
+		m.attemptSuccess++
+	}
+	ms := float64(latency) / float64(time.Millisecond)
+	m.latencySumMS += ms
+	for _, bucket := range attemptLatencyBucketsMS {
+		if ms <= bucket {
+			m.latencyBuckets[bucket]++
+		}
+	}
+}
+
+// RecordHedgeWin counts one call where the hedged (second) attempt
+// returned before the primary.
+func (m *Metrics) RecordHedgeWin() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hedgedAttempts++
+	m.hedgeWins++
+}
+
+// RecordFallback counts one PredictLTV call that exhausted every retry -
+// the caller falls back to the degraded heuristic in fetchLTV.
+func (m *Metrics) RecordFallback() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fallbacks++
+}
+
+// Render returns the current metrics in Prometheus text-exposition format.
+func (m *Metrics) Render() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var builder strings.Builder
+
+	builder.WriteString("# HELP syncflow_predict_attempts_total PredictLTV attempts (including hedges and retries)\n")
+	builder.WriteString("# TYPE syncflow_predict_attempts_total counter\n")
+	builder.WriteString(fmt.Sprintf("syncflow_predict_attempts_total %d\n", m.attemptCount))
+
+	builder.WriteString("\n# HELP syncflow_predict_attempt_success_total PredictLTV attempts that succeeded\n")
+	builder.WriteString("# TYPE syncflow_predict_attempt_success_total counter\n")
+	builder.WriteString(fmt.Sprintf("syncflow_predict_attempt_success_total %d\n", m.attemptSuccess))
+
+	builder.WriteString("\n# HELP syncflow_predict_attempt_latency_ms PredictLTV per-attempt latency\n")
+	builder.WriteString("# TYPE syncflow_predict_attempt_latency_ms histogram\n")
+	for _, bucket := range attemptLatencyBucketsMS {
+		builder.WriteString(fmt.Sprintf("syncflow_predict_attempt_latency_ms_bucket{le=\"%g\"} %d\n", bucket, m.latencyBuckets[bucket]))
+	}
+	builder.WriteString(fmt.Sprintf("syncflow_predict_attempt_latency_ms_bucket{le=\"+Inf\"} %d\n", m.attemptCount))
+	builder.WriteString(fmt.Sprintf("syncflow_predict_attempt_latency_ms_sum %.2f\n", m.latencySumMS))
+	builder.WriteString(fmt.Sprintf("syncflow_predict_attempt_latency_ms_count %d\n", m.attemptCount))
+
+	hedgeWinRatio := 0.0
+	if m.hedgedAttempts > 0 {
+		hedgeWinRatio = float64(m.hedgeWins) / float64(m.hedgedAttempts)
+	}
+	builder.WriteString("\n# HELP syncflow_predict_hedge_win_ratio Fraction of hedged calls the hedge (not the primary) won\n")
+	builder.WriteString("# TYPE syncflow_predict_hedge_win_ratio gauge\n")
+	builder.WriteString(fmt.Sprintf("syncflow_predict_hedge_win_ratio %.4f\n", hedgeWinRatio))
+
+	fallbackRate := 0.0
+	var calls int64
+	if m.attemptCount > 0 {
+		// attemptCount overcounts calls (hedges/retries multiply per
+		// call), but fallbacks is already per-call, so approximate calls
+		// as fallbacks + attemptSuccess for a rate that stays in [0,1].
+		calls = m.fallbacks + m.attemptSuccess
+		if calls > 0 {
+			fallbackRate = float64(m.fallbacks) / float64(calls)
+		}
+	}
+	builder.WriteString("\n# HELP syncflow_predict_fallback_rate Fraction of PredictLTV calls that exhausted retries and fell back to the degraded heuristic\n")
+	builder.WriteString("# TYPE syncflow_predict_fallback_rate gauge\n")
+	builder.WriteString(fmt.Sprintf("syncflow_predict_fallback_rate %.4f\n", fallbackRate))
+
+	return builder.String()
+}
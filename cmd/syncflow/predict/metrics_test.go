@@ -0,0 +1,63 @@
+package predict
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_RecordAttemptTracksCountAndSuccess(t *testing.T) {
+	m := NewMetrics()
+	m.RecordAttempt(10*time.Millisecond, true)
+	m.RecordAttempt(300*time.Millisecond, false)
+
+	if m.attemptCount != 2 {
+		t.Fatalf("expected attemptCount=2, got %d", m.attemptCount)
+	}
+	if m.attemptSuccess != 1 {
+		t.Fatalf("expected attemptSuccess=1, got %d", m.attemptSuccess)
+	}
+	if m.latencyBuckets[25] != 1 {
+		t.Fatalf("expected the 10ms attempt in the <=25ms bucket, got %d", m.latencyBuckets[25])
+	}
+	if m.latencyBuckets[500] != 2 {
+		t.Fatalf("expected both attempts in the <=500ms bucket, got %d", m.latencyBuckets[500])
+	}
+}
+
+func TestMetrics_RecordHedgeWinTracksRatio(t *testing.T) {
+	m := NewMetrics()
+	m.RecordHedgeWin()
+	if m.hedgedAttempts != 1 || m.hedgeWins != 1 {
+		t.Fatalf("expected hedgedAttempts=1 hedgeWins=1, got %d/%d", m.hedgedAttempts, m.hedgeWins)
+	}
+}
+
+func TestMetrics_RenderIncludesAllSeries(t *testing.T) {
+	m := NewMetrics()
+	m.RecordAttempt(5*time.Millisecond, true)
+	m.RecordHedgeWin()
+	m.RecordFallback()
+
+	out := m.Render()
+	for _, want := range []string{
+		"syncflow_predict_attempts_total 1",
+		"syncflow_predict_attempt_success_total 1",
+		"syncflow_predict_hedge_win_ratio 1.0000",
+		"syncflow_predict_fallback_rate",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected Render output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetrics_RenderZeroValueDoesNotDivideByZero(t *testing.T) {
+	out := NewMetrics().Render()
+	if !strings.Contains(out, "syncflow_predict_hedge_win_ratio 0.0000") {
+		t.Errorf("expected a zero hedge win ratio with no data, got:\n%s", out)
+	}
+	if !strings.Contains(out, "syncflow_predict_fallback_rate 0.0000") {
+		t.Errorf("expected a zero fallback rate with no data, got:\n%s", out)
+	}
+}
@@ -0,0 +1,228 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// salesforceAPIVersion mirrors crmsource.SalesforceSource's own constant -
+// duplicated here since this package doesn't otherwise depend on
+// crmsource and the two APIs can be versioned independently.
+const salesforceAPIVersion = "v58.0"
+
+// SalesforceActualsFetcher looks up a customer's realized revenue via a
+// SOQL query against Opportunity, the same Bulk-API-backed instance
+// crmsource.SalesforceSource already polls Contacts from.
+type SalesforceActualsFetcher struct {
+	InstanceURL string
+	AccessToken string
+	httpClient  *http.Client
+}
+
+// NewSalesforceActualsFetcher creates a fetcher against instanceURL,
+// authenticated with accessToken.
+func NewSalesforceActualsFetcher(instanceURL, accessToken string) *SalesforceActualsFetcher {
+	return &SalesforceActualsFetcher{
+		InstanceURL: instanceURL,
+		AccessToken: accessToken,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name implements ActualsFetcher.
+func (f *SalesforceActualsFetcher) Name() string { return "salesforce" }
+
+// FetchActual sums Amount across closed-won Opportunities for job's
+// customer. found is false (no error) if the customer has no closed-won
+// Opportunity yet - that's "not posted", not a failure.
+func (f *SalesforceActualsFetcher) FetchActual(ctx context.Context, job Job) (float64, bool, error) {
+	query := fmt.Sprintf(
+		"SELECT SUM(Amount) total FROM Opportunity WHERE ContactId = '%s' AND StageName = 'Closed Won'",
+		strings.ReplaceAll(job.CustomerID, "'", ""),
+	)
+	req, err := http.NewRequestWithContext(ctx, "GET",
+		fmt.Sprintf("%s/services/data/%s/query?q=%s", f.InstanceURL, salesforceAPIVersion, query), nil)
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+f.AccessToken)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("reconcile: querying salesforce for %s: %w", job.CustomerID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, false, fmt.Errorf("reconcile: salesforce query failed: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Records []struct {
+			Total *float64 `json:"total"`
+		} `json:"records"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, false, fmt.Errorf("reconcile: decoding salesforce response: %w", err)
+	}
+	if len(result.Records) == 0 || result.Records[0].Total == nil {
+		return 0, false, nil
+	}
+	return *result.Records[0].Total, true, nil
+}
+
+// StripeActualsFetcher looks up a customer's realized revenue from Stripe's
+// charges API - there's no Stripe connector precedent elsewhere in this
+// repo, so this fetcher talks to Stripe's plain REST API directly rather
+// than through a shared client.
+type StripeActualsFetcher struct {
+	APIKey     string
+	httpClient *http.Client
+}
+
+// NewStripeActualsFetcher creates a fetcher authenticated with apiKey.
+func NewStripeActualsFetcher(apiKey string) *StripeActualsFetcher {
+	return &StripeActualsFetcher{
+		APIKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name implements ActualsFetcher.
+func (f *StripeActualsFetcher) Name() string { return "stripe" }
+
+// FetchActual sums succeeded charges for job's customer (a Stripe customer
+// ID). found is false if Stripe has no succeeded charge yet.
+func (f *StripeActualsFetcher) FetchActual(ctx context.Context, job Job) (float64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET",
+		fmt.Sprintf("https://api.stripe.com/v1/charges?customer=%s&limit=100", job.CustomerID), nil)
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+f.APIKey)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("reconcile: querying stripe for %s: %w", job.CustomerID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, false, fmt.Errorf("reconcile: stripe query failed: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data []struct {
+			Amount int64  `json:"amount"` // smallest currency unit (cents)
+			Status string `json:"status"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, false, fmt.Errorf("reconcile: decoding stripe response: %w", err)
+	}
+
+	var total int64
+	var found bool
+	for _, charge := range result.Data {
+		if charge.Status == "succeeded" {
+			total += charge.Amount
+			found = true
+		}
+	}
+	if !found {
+		return 0, false, nil
+	}
+	return float64(total) / 100, true, nil
+}
+
+// BigQueryActualsFetcher looks up a customer's realized revenue from a
+// BigQuery table via the REST jobs.query API - no BigQuery client is
+// vendored in this repo, so this issues the REST call directly, the same
+// stand-in approach StripeActualsFetcher takes.
+type BigQueryActualsFetcher struct {
+	ProjectID   string
+	Dataset     string
+	Table       string
+	AccessToken string
+	httpClient  *http.Client
+}
+
+// NewBigQueryActualsFetcher creates a fetcher that queries
+// projectID.dataset.table, authenticated with accessToken (a short-lived
+// OAuth2 token - refreshing it is the caller's responsibility).
+func NewBigQueryActualsFetcher(projectID, dataset, table, accessToken string) *BigQueryActualsFetcher {
+	return &BigQueryActualsFetcher{
+		ProjectID:   projectID,
+		Dataset:     dataset,
+		Table:       table,
+		AccessToken: accessToken,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name implements ActualsFetcher.
+func (f *BigQueryActualsFetcher) Name() string { return "bigquery" }
+
+// FetchActual runs a parameterized SQL query against f.Table for job's
+// customer's realized revenue. found is false if the query returns no row.
+func (f *BigQueryActualsFetcher) FetchActual(ctx context.Context, job Job) (float64, bool, error) {
+	query := fmt.Sprintf("SELECT SUM(revenue) AS total FROM `%s.%s.%s` WHERE customer_id = @customer_id",
+		f.ProjectID, f.Dataset, f.Table)
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":        query,
+		"useLegacySql": false,
+		"queryParameters": []map[string]interface{}{
+			{
+				"name":           "customer_id",
+				"parameterType":  map[string]string{"type": "STRING"},
+				"parameterValue": map[string]string{"value": job.CustomerID},
+			},
+		},
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST",
+		fmt.Sprintf("https://bigquery.googleapis.com/bigquery/v2/projects/%s/queries", f.ProjectID),
+		strings.NewReader(string(payload)))
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+f.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("reconcile: querying bigquery for %s: %w", job.CustomerID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, false, fmt.Errorf("reconcile: bigquery query failed: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Rows []struct {
+			F []struct {
+				V *string `json:"v"`
+			} `json:"f"`
+		} `json:"rows"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, false, fmt.Errorf("reconcile: decoding bigquery response: %w", err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0].F) == 0 || result.Rows[0].F[0].V == nil {
+		return 0, false, nil
+	}
+
+	var total float64
+	if _, err := fmt.Sscanf(*result.Rows[0].F[0].V, "%g", &total); err != nil {
+		return 0, false, fmt.Errorf("reconcile: parsing bigquery total: %w", err)
+	}
+	return total, true, nil
+}
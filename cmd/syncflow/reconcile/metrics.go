@@ -0,0 +1,103 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Metrics tracks Prometheus counters for reconciliation job outcomes - the
+// signals an operator needs to notice the backfill loop falling behind or
+// a platform's ActualsFetcher failing outright.
+type Metrics struct {
+	mu sync.Mutex
+
+	reconciled int64
+	retried    int64
+	failed     int64
+	pending    int
+
+	reconciledByPlatform      map[string]int64
+	withinToleranceByPlatform map[string]int64
+}
+
+// NewMetrics creates an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		reconciledByPlatform:      make(map[string]int64),
+		withinToleranceByPlatform: make(map[string]int64),
+	}
+}
+
+// RecordReconciled records a successfully written-back AccuracyRecord.
+func (m *Metrics) RecordReconciled(record AccuracyRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reconciled++
+	m.reconciledByPlatform[record.Platform]++
+	if record.WithinTolerance {
+		m.withinToleranceByPlatform[record.Platform]++
+	}
+}
+
+// RecordRetried records a job rescheduled after a transient fetcher error
+// - not a not-yet-posted recheck, which doesn't indicate anything wrong.
+func (m *Metrics) RecordRetried() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retried++
+}
+
+// RecordFailed records a job Store.Fail gave up on.
+func (m *Metrics) RecordFailed() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failed++
+}
+
+// SetPending overwrites the pending-job gauge, read back from
+// Store.Pending on every poll rather than tracked incrementally.
+func (m *Metrics) SetPending(pending int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending = pending
+}
+
+// Render writes Prometheus text-format output, matching
+// crmsource.Metrics.Render's HELP/TYPE-comment convention.
+func (m *Metrics) Render() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP syncflow_reconcile_jobs_reconciled_total Jobs successfully reconciled against ground truth\n")
+	b.WriteString("# TYPE syncflow_reconcile_jobs_reconciled_total counter\n")
+	fmt.Fprintf(&b, "syncflow_reconcile_jobs_reconciled_total %d\n", m.reconciled)
+
+	b.WriteString("\n# HELP syncflow_reconcile_jobs_retried_total Jobs rescheduled after a transient fetcher error\n")
+	b.WriteString("# TYPE syncflow_reconcile_jobs_retried_total counter\n")
+	fmt.Fprintf(&b, "syncflow_reconcile_jobs_retried_total %d\n", m.retried)
+
+	b.WriteString("\n# HELP syncflow_reconcile_jobs_failed_total Jobs permanently given up on after exhausting their attempts or for lack of a fetcher for their platform\n")
+	b.WriteString("# TYPE syncflow_reconcile_jobs_failed_total counter\n")
+	fmt.Fprintf(&b, "syncflow_reconcile_jobs_failed_total %d\n", m.failed)
+
+	b.WriteString("\n# HELP syncflow_reconcile_jobs_pending Jobs awaiting reconciliation\n")
+	b.WriteString("# TYPE syncflow_reconcile_jobs_pending gauge\n")
+	fmt.Fprintf(&b, "syncflow_reconcile_jobs_pending %d\n", m.pending)
+
+	b.WriteString("\n# HELP syncflow_reconcile_reconciled_by_platform_total Reconciled predictions, by platform\n")
+	b.WriteString("# TYPE syncflow_reconcile_reconciled_by_platform_total counter\n")
+	for platform, count := range m.reconciledByPlatform {
+		fmt.Fprintf(&b, "syncflow_reconcile_reconciled_by_platform_total{platform=%q} %d\n", platform, count)
+	}
+
+	b.WriteString("\n# HELP syncflow_reconcile_within_tolerance_total Reconciled predictions within tolerance, by platform\n")
+	b.WriteString("# TYPE syncflow_reconcile_within_tolerance_total counter\n")
+	for platform, count := range m.withinToleranceByPlatform {
+		fmt.Fprintf(&b, "syncflow_reconcile_within_tolerance_total{platform=%q} %d\n", platform, count)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,149 @@
+// Package reconcile closes the loop TestLTVAccuracyTracking only
+// simulates with hard-coded prediction/actual pairs: a prediction lands in
+// the audit trail immediately, but the customer's actual lifetime value
+// only becomes knowable days or weeks later, once revenue has posted in a
+// CRM or analytics platform. Enqueue schedules a Job for that future
+// check; Worker polls Store for jobs whose DueAt has arrived, asks the
+// right ActualsFetcher whether the actual has posted yet, and - once it
+// has - writes the result back through audit.AuditLogger.UpdateActualLTV
+// so GetAccuracyMetrics and the rolling Subscribe windows pick it up the
+// same way a live reconciliation always has.
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// Job is one prediction awaiting ground-truth reconciliation.
+type Job struct {
+	RequestID    string
+	CustomerID   string
+	PredictedLTV float64
+	PredictedAt  time.Time
+
+	// Platform and ModelVersion are carried along from the original
+	// audit.AuditEntry so the eventual AccuracyRecord doesn't need a
+	// second lookup, and so Worker can route this job to the
+	// ActualsFetcher that knows this platform.
+	Platform     string
+	ModelVersion string
+
+	// DueAt is when Worker should next ask an ActualsFetcher about this
+	// job - initially the prediction time plus however long this
+	// platform's revenue typically takes to post, pushed further out on
+	// every Reschedule.
+	DueAt time.Time
+
+	// Attempts counts Reschedule calls caused by a transient fetcher
+	// error, NOT rechecks caused by the actual simply not having posted
+	// yet - those don't count against Worker.MaxAttempts.
+	Attempts int
+
+	// LastError is the most recent fetcher error, if any - kept for
+	// debugging. DueJobs and Get return it with the job; Worker does not
+	// act on it.
+	LastError string
+}
+
+// AccuracyRecord is the outcome of successfully reconciling one Job,
+// derived once ActualsFetcher reports an actual LTV and written back to
+// the audit trail via audit.AuditLogger.UpdateActualLTV.
+type AccuracyRecord struct {
+	RequestID       string    `json:"request_id"`
+	CustomerID      string    `json:"customer_id"`
+	Platform        string    `json:"platform"`
+	ModelVersion    string    `json:"model_version"`
+	PredictedLTV    float64   `json:"predicted_ltv"`
+	ActualLTV       float64   `json:"actual_ltv"`
+	AbsErrorPct     float64   `json:"abs_error_pct"`
+	WithinTolerance bool      `json:"within_tolerance"`
+	ReconciledAt    time.Time `json:"reconciled_at"`
+}
+
+// accuracyTolerancePct mirrors audit's own unexported accuracyTolerancePct
+// - "within tolerance" has to mean the same 10% both here and in
+// GetAccuracyMetrics, or an operator comparing a Worker-produced
+// AccuracyRecord against the audit trail's own numbers would see two
+// different definitions of "accurate".
+const accuracyTolerancePct = 10.0
+
+// buildAccuracyRecord computes AbsErrorPct/WithinTolerance for a job whose
+// actual LTV just came back from an ActualsFetcher.
+func buildAccuracyRecord(job Job, actualLTV float64, now time.Time) AccuracyRecord {
+	record := AccuracyRecord{
+		RequestID:    job.RequestID,
+		CustomerID:   job.CustomerID,
+		Platform:     job.Platform,
+		ModelVersion: job.ModelVersion,
+		PredictedLTV: job.PredictedLTV,
+		ActualLTV:    actualLTV,
+		ReconciledAt: now,
+	}
+	if job.PredictedLTV != 0 {
+		record.AbsErrorPct = math.Abs((actualLTV-job.PredictedLTV)/job.PredictedLTV) * 100
+	}
+	record.WithinTolerance = record.AbsErrorPct <= accuracyTolerancePct
+	return record
+}
+
+// ActualsFetcher looks up the ground-truth actual lifetime value for a
+// job's customer from one revenue source (Salesforce, Stripe, BigQuery,
+// ...). found is false - not an error - when the actual simply hasn't
+// posted yet; Worker reschedules the job for a later recheck without
+// counting that against Job.Attempts.
+type ActualsFetcher interface {
+	FetchActual(ctx context.Context, job Job) (actualLTV float64, found bool, err error)
+
+	// Name identifies this fetcher's platform, matching Job.Platform, so
+	// Worker.Fetchers can route each job to the fetcher that knows it.
+	Name() string
+}
+
+// Store durably persists reconciliation Jobs, so a restart resumes
+// tracking every outstanding prediction instead of losing it.
+type Store interface {
+	// Enqueue durably records job, to be reconciled once job.DueAt
+	// arrives. Enqueuing a RequestID that's already present (pending,
+	// completed, or failed) is a no-op - callers don't need to check
+	// first.
+	Enqueue(ctx context.Context, job Job) error
+
+	// DueJobs returns up to limit non-terminal jobs whose DueAt has
+	// passed, ordered oldest DueAt first.
+	DueJobs(ctx context.Context, now time.Time, limit int) ([]Job, error)
+
+	// Reschedule adds attemptsDelta to a job's Attempts (0 for an
+	// actual-not-posted-yet recheck, 1 for a transient fetcher error),
+	// sets its next DueAt, and records lastErr ("" clears it).
+	Reschedule(ctx context.Context, requestID string, nextDueAt time.Time, attemptsDelta int, lastErr string) error
+
+	// Complete marks requestID reconciled, removing it from DueJobs.
+	Complete(ctx context.Context, requestID string) error
+
+	// Fail marks requestID permanently unreconcilable (Attempts
+	// exhausted, or no ActualsFetcher registered for its platform),
+	// removing it from DueJobs.
+	Fail(ctx context.Context, requestID string, reason string) error
+
+	// Get returns the pending job for requestID, for
+	// Worker.ForceReconcile. ok is false if requestID was never enqueued,
+	// or is already Complete/Fail.
+	Get(ctx context.Context, requestID string) (job Job, ok bool, err error)
+
+	// SealedHeight returns the PredictedAt of the oldest non-terminal
+	// job - every prediction older than this is fully reconciled. The
+	// zero time means nothing is outstanding.
+	SealedHeight(ctx context.Context) (time.Time, error)
+
+	// Pending returns how many jobs are neither Complete nor Fail.
+	Pending(ctx context.Context) (int, error)
+
+	Close() error
+}
+
+// ErrFetcherNotFound is returned when a Job's Platform has no registered
+// ActualsFetcher in Worker.Fetchers.
+var ErrFetcherNotFound = errors.New("reconcile: no ActualsFetcher registered for this platform")
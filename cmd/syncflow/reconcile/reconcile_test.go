@@ -0,0 +1,42 @@
+package reconcile
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildAccuracyRecord_ComputesAbsErrorPct(t *testing.T) {
+	job := Job{RequestID: "req-1", CustomerID: "cust-1", PredictedLTV: 100}
+	record := buildAccuracyRecord(job, 108, time.Now())
+
+	if record.AbsErrorPct != 8 {
+		t.Fatalf("expected AbsErrorPct=8, got %v", record.AbsErrorPct)
+	}
+	if !record.WithinTolerance {
+		t.Fatalf("expected 8%% error to be within the %v%% tolerance", accuracyTolerancePct)
+	}
+}
+
+func TestBuildAccuracyRecord_OutsideTolerance(t *testing.T) {
+	job := Job{RequestID: "req-1", PredictedLTV: 100}
+	record := buildAccuracyRecord(job, 50, time.Now())
+
+	if record.AbsErrorPct != 50 {
+		t.Fatalf("expected AbsErrorPct=50, got %v", record.AbsErrorPct)
+	}
+	if record.WithinTolerance {
+		t.Fatal("expected a 50% error to fall outside tolerance")
+	}
+}
+
+func TestBuildAccuracyRecord_ZeroPredictedLTVAvoidsDivideByZero(t *testing.T) {
+	job := Job{RequestID: "req-1", PredictedLTV: 0}
+	record := buildAccuracyRecord(job, 10, time.Now())
+
+	if record.AbsErrorPct != 0 {
+		t.Fatalf("expected AbsErrorPct=0 when PredictedLTV is 0, got %v", record.AbsErrorPct)
+	}
+	if !record.WithinTolerance {
+		t.Fatal("expected the zero-error-pct case to report within tolerance")
+	}
+}
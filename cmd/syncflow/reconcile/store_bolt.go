@@ -0,0 +1,248 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// reconcileJobsBucket is the single bbolt bucket holding every Job, keyed
+// by RequestID - Bolt has no secondary indexes, so DueJobs/SealedHeight/
+// Pending all do a full bucket scan-and-filter. That's an acceptable
+// tradeoff at BoltStore's expected embedded, single-process scale.
+var reconcileJobsBucket = []byte("reconcile_jobs")
+
+// boltJobStatus distinguishes a pending job from one DueJobs should never
+// surface again.
+type boltJobStatus string
+
+const (
+	boltJobPending boltJobStatus = "pending"
+	boltJobDone    boltJobStatus = "done"
+	boltJobFailed  boltJobStatus = "failed"
+)
+
+// boltJobRecord is the JSON-encoded value stored per key in
+// reconcileJobsBucket.
+type boltJobRecord struct {
+	Job    Job
+	Status boltJobStatus
+	Reason string
+}
+
+// BoltStore persists reconciliation Jobs in a local bbolt file - the
+// durable option for a single-process deployment that doesn't want a
+// Postgres dependency just to track a backfill queue. See PostgresStore
+// for the shared-database alternative.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a bbolt database at path and
+// ensures reconcileJobsBucket exists.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("reconcile: opening bolt store %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(reconcileJobsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("reconcile: creating bucket in %s: %w", path, err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Enqueue implements Store.
+func (s *BoltStore) Enqueue(ctx context.Context, job Job) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(reconcileJobsBucket)
+		if bucket.Get([]byte(job.RequestID)) != nil {
+			return nil
+		}
+		return putBoltRecord(bucket, job.RequestID, boltJobRecord{Job: job, Status: boltJobPending})
+	})
+}
+
+// DueJobs implements Store.
+func (s *BoltStore) DueJobs(ctx context.Context, now time.Time, limit int) ([]Job, error) {
+	var jobs []Job
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(reconcileJobsBucket)
+		return bucket.ForEach(func(key, value []byte) error {
+			if limit > 0 && len(jobs) >= limit {
+				return nil
+			}
+			var record boltJobRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return err
+			}
+			if record.Status == boltJobPending && !record.Job.DueAt.After(now) {
+				jobs = append(jobs, record.Job)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reconcile: listing due jobs: %w", err)
+	}
+	sortJobsByDueAt(jobs)
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// Reschedule implements Store.
+func (s *BoltStore) Reschedule(ctx context.Context, requestID string, nextDueAt time.Time, attemptsDelta int, lastErr string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(reconcileJobsBucket)
+		record, ok, err := getBoltRecord(bucket, requestID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("reconcile: %s not found", requestID)
+		}
+		record.Job.DueAt = nextDueAt
+		record.Job.Attempts += attemptsDelta
+		record.Job.LastError = lastErr
+		return putBoltRecord(bucket, requestID, record)
+	})
+}
+
+// Complete implements Store.
+func (s *BoltStore) Complete(ctx context.Context, requestID string) error {
+	return s.setStatus(requestID, boltJobDone, "")
+}
+
+// Fail implements Store.
+func (s *BoltStore) Fail(ctx context.Context, requestID string, reason string) error {
+	return s.setStatus(requestID, boltJobFailed, reason)
+}
+
+func (s *BoltStore) setStatus(requestID string, status boltJobStatus, reason string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(reconcileJobsBucket)
+		record, ok, err := getBoltRecord(bucket, requestID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("reconcile: %s not found", requestID)
+		}
+		record.Status = status
+		record.Reason = reason
+		return putBoltRecord(bucket, requestID, record)
+	})
+}
+
+// Get implements Store.
+func (s *BoltStore) Get(ctx context.Context, requestID string) (Job, bool, error) {
+	var record boltJobRecord
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(reconcileJobsBucket)
+		var ok bool
+		var err error
+		record, ok, err = getBoltRecord(bucket, requestID)
+		if err != nil {
+			return err
+		}
+		found = ok && record.Status == boltJobPending
+		return nil
+	})
+	if err != nil {
+		return Job{}, false, fmt.Errorf("reconcile: getting %s: %w", requestID, err)
+	}
+	return record.Job, found, nil
+}
+
+// SealedHeight implements Store.
+func (s *BoltStore) SealedHeight(ctx context.Context) (time.Time, error) {
+	var oldest time.Time
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(reconcileJobsBucket)
+		return bucket.ForEach(func(key, value []byte) error {
+			var record boltJobRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return err
+			}
+			if record.Status != boltJobPending {
+				return nil
+			}
+			if oldest.IsZero() || record.Job.PredictedAt.Before(oldest) {
+				oldest = record.Job.PredictedAt
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reconcile: computing sealed height: %w", err)
+	}
+	return oldest, nil
+}
+
+// Pending implements Store.
+func (s *BoltStore) Pending(ctx context.Context) (int, error) {
+	count := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(reconcileJobsBucket)
+		return bucket.ForEach(func(key, value []byte) error {
+			var record boltJobRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return err
+			}
+			if record.Status == boltJobPending {
+				count++
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("reconcile: counting pending jobs: %w", err)
+	}
+	return count, nil
+}
+
+// Close implements Store.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func getBoltRecord(bucket *bolt.Bucket, requestID string) (boltJobRecord, bool, error) {
+	value := bucket.Get([]byte(requestID))
+	if value == nil {
+		return boltJobRecord{}, false, nil
+	}
+	var record boltJobRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return boltJobRecord{}, false, err
+	}
+	return record, true, nil
+}
+
+func putBoltRecord(bucket *bolt.Bucket, requestID string, record boltJobRecord) error {
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(requestID), encoded)
+}
+
+// sortJobsByDueAt orders jobs oldest-DueAt-first, matching Store.DueJobs'
+// documented contract - a plain insertion sort is fine given BatchSize
+// caps this slice at a few dozen entries.
+func sortJobsByDueAt(jobs []Job) {
+	for i := 1; i < len(jobs); i++ {
+		for j := i; j > 0 && jobs[j].DueAt.Before(jobs[j-1].DueAt); j-- {
+			jobs[j], jobs[j-1] = jobs[j-1], jobs[j]
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package reconcile
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestBoltStore(t *testing.T) *BoltStore {
+	t.Helper()
+	s, err := NewBoltStore(filepath.Join(t.TempDir(), "reconcile.db"))
+	if err != nil {
+		t.Fatalf("NewBoltStore failed: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestBoltStore_EnqueueIsIdempotent(t *testing.T) {
+	s := newTestBoltStore(t)
+	ctx := context.Background()
+	job := Job{RequestID: "req-1", CustomerID: "cust-1", DueAt: time.Now()}
+
+	if err := s.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	// Re-enqueuing the same RequestID with different data must not
+	// overwrite the original record.
+	if err := s.Enqueue(ctx, Job{RequestID: "req-1", CustomerID: "cust-2", DueAt: time.Now()}); err != nil {
+		t.Fatalf("second Enqueue failed: %v", err)
+	}
+
+	got, ok, err := s.Get(ctx, "req-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected the job to be present")
+	}
+	if got.CustomerID != "cust-1" {
+		t.Fatalf("expected the first enqueue to win, got CustomerID=%q", got.CustomerID)
+	}
+}
+
+func TestBoltStore_DueJobsFiltersAndOrders(t *testing.T) {
+	s := newTestBoltStore(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	mustEnqueue := func(id string, dueAt time.Time) {
+		if err := s.Enqueue(ctx, Job{RequestID: id, CustomerID: "cust-1", DueAt: dueAt}); err != nil {
+			t.Fatalf("Enqueue(%s) failed: %v", id, err)
+		}
+	}
+	mustEnqueue("due-later", now.Add(-time.Minute))
+	mustEnqueue("due-earlier", now.Add(-time.Hour))
+	mustEnqueue("not-due-yet", now.Add(time.Hour))
+
+	jobs, err := s.DueJobs(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("DueJobs failed: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 due jobs, got %d: %+v", len(jobs), jobs)
+	}
+	if jobs[0].RequestID != "due-earlier" || jobs[1].RequestID != "due-later" {
+		t.Fatalf("expected oldest-DueAt-first ordering, got %+v", jobs)
+	}
+}
+
+func TestBoltStore_DueJobsRespectsLimit(t *testing.T) {
+	s := newTestBoltStore(t)
+	ctx := context.Background()
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := s.Enqueue(ctx, Job{RequestID: string(rune('a' + i)), DueAt: now.Add(-time.Minute)}); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+	jobs, err := s.DueJobs(ctx, now, 2)
+	if err != nil {
+		t.Fatalf("DueJobs failed: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected limit of 2, got %d", len(jobs))
+	}
+}
+
+func TestBoltStore_RescheduleUpdatesDueAtAndAttempts(t *testing.T) {
+	s := newTestBoltStore(t)
+	ctx := context.Background()
+	if err := s.Enqueue(ctx, Job{RequestID: "req-1", DueAt: time.Now()}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	nextDue := time.Now().Add(time.Hour)
+	if err := s.Reschedule(ctx, "req-1", nextDue, 1, "transient error"); err != nil {
+		t.Fatalf("Reschedule failed: %v", err)
+	}
+
+	got, ok, err := s.Get(ctx, "req-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected the job to still be pending")
+	}
+	if got.Attempts != 1 {
+		t.Fatalf("expected Attempts=1, got %d", got.Attempts)
+	}
+	if got.LastError != "transient error" {
+		t.Fatalf("expected LastError recorded, got %q", got.LastError)
+	}
+	if !got.DueAt.Equal(nextDue) {
+		t.Fatalf("expected DueAt updated to %v, got %v", nextDue, got.DueAt)
+	}
+}
+
+func TestBoltStore_CompleteRemovesFromDueJobsAndGet(t *testing.T) {
+	s := newTestBoltStore(t)
+	ctx := context.Background()
+	now := time.Now()
+	if err := s.Enqueue(ctx, Job{RequestID: "req-1", DueAt: now.Add(-time.Minute)}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := s.Complete(ctx, "req-1"); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	if _, ok, err := s.Get(ctx, "req-1"); err != nil || ok {
+		t.Fatalf("expected Get to report the job gone, got ok=%v err=%v", ok, err)
+	}
+	jobs, err := s.DueJobs(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("DueJobs failed: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Fatalf("expected no due jobs after Complete, got %+v", jobs)
+	}
+}
+
+func TestBoltStore_FailRemovesFromDueJobs(t *testing.T) {
+	s := newTestBoltStore(t)
+	ctx := context.Background()
+	now := time.Now()
+	if err := s.Enqueue(ctx, Job{RequestID: "req-1", DueAt: now.Add(-time.Minute)}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := s.Fail(ctx, "req-1", "gave up"); err != nil {
+		t.Fatalf("Fail failed: %v", err)
+	}
+
+	jobs, err := s.DueJobs(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("DueJobs failed: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Fatalf("expected no due jobs after Fail, got %+v", jobs)
+	}
+}
+
+func TestBoltStore_SealedHeightTracksOldestPending(t *testing.T) {
+	s := newTestBoltStore(t)
+	ctx := context.Background()
+	older := time.Now().Add(-2 * time.Hour)
+	newer := time.Now().Add(-time.Hour)
+
+	if err := s.Enqueue(ctx, Job{RequestID: "req-old", PredictedAt: older, DueAt: time.Now()}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := s.Enqueue(ctx, Job{RequestID: "req-new", PredictedAt: newer, DueAt: time.Now()}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	height, err := s.SealedHeight(ctx)
+	if err != nil {
+		t.Fatalf("SealedHeight failed: %v", err)
+	}
+	if !height.Equal(older) {
+		t.Fatalf("expected sealed height %v, got %v", older, height)
+	}
+
+	if err := s.Complete(ctx, "req-old"); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	height, err = s.SealedHeight(ctx)
+	if err != nil {
+		t.Fatalf("SealedHeight failed: %v", err)
+	}
+	if !height.Equal(newer) {
+		t.Fatalf("expected sealed height to advance to %v, got %v", newer, height)
+	}
+}
+
+func TestBoltStore_PendingCountsOnlyPendingJobs(t *testing.T) {
+	s := newTestBoltStore(t)
+	ctx := context.Background()
+	if err := s.Enqueue(ctx, Job{RequestID: "req-1", DueAt: time.Now()}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := s.Enqueue(ctx, Job{RequestID: "req-2", DueAt: time.Now()}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if err := s.Complete(ctx, "req-2"); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	pending, err := s.Pending(ctx)
+	if err != nil {
+		t.Fatalf("Pending failed: %v", err)
+	}
+	if pending != 1 {
+		t.Fatalf("expected 1 pending job, got %d", pending)
+	}
+}
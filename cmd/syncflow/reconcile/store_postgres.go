@@ -0,0 +1,178 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq" // PostgreSQL driver, already used by crm.PostgreSQLConnector and auditsink.PostgresSink
+)
+
+// reconcileJobsSchema mirrors crmsource.CursorStore's migrate-on-
+// construction pattern: no separate migration step is needed before
+// syncflow can start. The partial index only covers pending jobs since
+// DueJobs never looks at done/failed ones.
+const reconcileJobsSchema = `
+CREATE TABLE IF NOT EXISTS reconcile_jobs (
+	request_id    TEXT PRIMARY KEY,
+	customer_id   TEXT NOT NULL,
+	predicted_ltv DOUBLE PRECISION NOT NULL,
+	predicted_at  TIMESTAMPTZ NOT NULL,
+	platform      TEXT NOT NULL,
+	model_version TEXT NOT NULL,
+	due_at        TIMESTAMPTZ NOT NULL,
+	attempts      INT NOT NULL DEFAULT 0,
+	last_error    TEXT NOT NULL DEFAULT '',
+	status        TEXT NOT NULL DEFAULT 'pending',
+	reason        TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS reconcile_jobs_due_at_idx ON reconcile_jobs (due_at) WHERE status = 'pending';
+`
+
+// PostgresStore persists reconciliation Jobs in the same Postgres database
+// as auditsink.PostgresSink - pass it the same DSN so a job's fate lives
+// alongside the audit entry it reconciles rather than in a separate store.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore opens dsn and ensures the reconcile_jobs table exists.
+func NewPostgresStore(dsn string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile: opening postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("reconcile: pinging postgres: %w", err)
+	}
+	if _, err := db.Exec(reconcileJobsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("reconcile: migrating reconcile_jobs: %w", err)
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+// Enqueue implements Store.
+func (s *PostgresStore) Enqueue(ctx context.Context, job Job) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO reconcile_jobs
+			(request_id, customer_id, predicted_ltv, predicted_at, platform, model_version, due_at, attempts, last_error, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 0, '', 'pending')
+		ON CONFLICT (request_id) DO NOTHING`,
+		job.RequestID, job.CustomerID, job.PredictedLTV, job.PredictedAt, job.Platform, job.ModelVersion, job.DueAt,
+	)
+	if err != nil {
+		return fmt.Errorf("reconcile: enqueuing %s: %w", job.RequestID, err)
+	}
+	return nil
+}
+
+// DueJobs implements Store.
+func (s *PostgresStore) DueJobs(ctx context.Context, now time.Time, limit int) ([]Job, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT request_id, customer_id, predicted_ltv, predicted_at, platform, model_version, due_at, attempts, last_error
+		FROM reconcile_jobs
+		WHERE status = 'pending' AND due_at <= $1
+		ORDER BY due_at ASC
+		LIMIT $2`,
+		now, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile: listing due jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		var job Job
+		if err := rows.Scan(&job.RequestID, &job.CustomerID, &job.PredictedLTV, &job.PredictedAt,
+			&job.Platform, &job.ModelVersion, &job.DueAt, &job.Attempts, &job.LastError); err != nil {
+			return nil, fmt.Errorf("reconcile: scanning due job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// Reschedule implements Store.
+func (s *PostgresStore) Reschedule(ctx context.Context, requestID string, nextDueAt time.Time, attemptsDelta int, lastErr string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE reconcile_jobs
+		SET due_at = $2, attempts = attempts + $3, last_error = $4
+		WHERE request_id = $1 AND status = 'pending'`,
+		requestID, nextDueAt, attemptsDelta, lastErr,
+	)
+	if err != nil {
+		return fmt.Errorf("reconcile: rescheduling %s: %w", requestID, err)
+	}
+	return nil
+}
+
+// Complete implements Store.
+func (s *PostgresStore) Complete(ctx context.Context, requestID string) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE reconcile_jobs SET status = 'done' WHERE request_id = $1`, requestID)
+	if err != nil {
+		return fmt.Errorf("reconcile: completing %s: %w", requestID, err)
+	}
+	return nil
+}
+
+// Fail implements Store.
+func (s *PostgresStore) Fail(ctx context.Context, requestID string, reason string) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE reconcile_jobs SET status = 'failed', reason = $2 WHERE request_id = $1`, requestID, reason)
+	if err != nil {
+		return fmt.Errorf("reconcile: failing %s: %w", requestID, err)
+	}
+	return nil
+}
+
+// Get implements Store.
+func (s *PostgresStore) Get(ctx context.Context, requestID string) (Job, bool, error) {
+	var job Job
+	err := s.db.QueryRowContext(ctx, `
+		SELECT request_id, customer_id, predicted_ltv, predicted_at, platform, model_version, due_at, attempts, last_error
+		FROM reconcile_jobs
+		WHERE request_id = $1 AND status = 'pending'`,
+		requestID,
+	).Scan(&job.RequestID, &job.CustomerID, &job.PredictedLTV, &job.PredictedAt,
+		&job.Platform, &job.ModelVersion, &job.DueAt, &job.Attempts, &job.LastError)
+	if err == sql.ErrNoRows {
+		return Job{}, false, nil
+	}
+	if err != nil {
+		return Job{}, false, fmt.Errorf("reconcile: getting %s: %w", requestID, err)
+	}
+	return job, true, nil
+}
+
+// SealedHeight implements Store.
+func (s *PostgresStore) SealedHeight(ctx context.Context) (time.Time, error) {
+	var oldest sql.NullTime
+	err := s.db.QueryRowContext(ctx, `
+		SELECT MIN(predicted_at) FROM reconcile_jobs WHERE status = 'pending'`,
+	).Scan(&oldest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reconcile: computing sealed height: %w", err)
+	}
+	if !oldest.Valid {
+		return time.Time{}, nil
+	}
+	return oldest.Time, nil
+}
+
+// Pending implements Store.
+func (s *PostgresStore) Pending(ctx context.Context) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM reconcile_jobs WHERE status = 'pending'`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("reconcile: counting pending jobs: %w", err)
+	}
+	return count, nil
+}
+
+// Close implements Store.
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
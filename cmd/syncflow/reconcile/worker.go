@@ -0,0 +1,341 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncflow/audit"
+)
+
+// defaultPollInterval is how often Worker checks Store for due jobs when
+// nothing has woken it early via NotifyNewEntry.
+const defaultPollInterval = 30 * time.Second
+
+// defaultBatchSize caps how many due jobs one poll pulls from Store at
+// once, so a large backlog doesn't spike CRM/analytics call volume in a
+// single burst.
+const defaultBatchSize = 50
+
+// defaultRecheckInterval is how far out Worker reschedules a job whose
+// actual simply hasn't posted yet (found == false) - as opposed to a
+// transient fetcher error, which backs off exponentially instead.
+const defaultRecheckInterval = 6 * time.Hour
+
+// defaultMaxAttempts is how many transient fetcher errors a job tolerates
+// before Worker gives up on it via Store.Fail.
+const defaultMaxAttempts = 8
+
+// defaultBackoffMultiplier/JitterFraction match shield.DefaultRetryPolicy's
+// (2.0, ±25%) - same jittered-exponential mental model, applied across
+// poll cycles instead of within one call, so defaultInitialBackoff and
+// defaultMaxBackoff are minutes and hours rather than shield's 100ms/30s.
+const (
+	defaultInitialBackoff    = 5 * time.Minute
+	defaultMaxBackoff        = 6 * time.Hour
+	defaultBackoffMultiplier = 2.0
+	defaultJitterFraction    = 0.25
+)
+
+// Worker pulls due jobs from a Store, asks the right ActualsFetcher
+// whether each one's ground truth has posted, and writes successes back
+// through Audit.
+type Worker struct {
+	Store   Store
+	Audit   *audit.AuditLogger
+	Metrics *Metrics
+
+	// Fetchers maps Job.Platform to the ActualsFetcher that knows how to
+	// look up that platform's actuals.
+	Fetchers map[string]ActualsFetcher
+
+	// MaxInFlightPerCustomer bounds how many jobs for the same customer
+	// Worker reconciles concurrently, so one customer's long prediction
+	// history can't alone exhaust a CRM's rate limit. <= 0 defaults to 1.
+	MaxInFlightPerCustomer int
+
+	PollInterval      time.Duration
+	BatchSize         int
+	RecheckInterval   time.Duration
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+	JitterFraction    float64
+
+	wake chan struct{}
+
+	mu             sync.Mutex
+	inFlightByCust map[string]int
+}
+
+// NewWorker creates a Worker with the package's default pacing (see the
+// default* constants), ready to have Fetchers populated before Run.
+func NewWorker(store Store, auditLogger *audit.AuditLogger, metrics *Metrics) *Worker {
+	return &Worker{
+		Store:             store,
+		Audit:             auditLogger,
+		Metrics:           metrics,
+		Fetchers:          make(map[string]ActualsFetcher),
+		PollInterval:      defaultPollInterval,
+		BatchSize:         defaultBatchSize,
+		RecheckInterval:   defaultRecheckInterval,
+		MaxAttempts:       defaultMaxAttempts,
+		InitialBackoff:    defaultInitialBackoff,
+		MaxBackoff:        defaultMaxBackoff,
+		BackoffMultiplier: defaultBackoffMultiplier,
+		JitterFraction:    defaultJitterFraction,
+		wake:              make(chan struct{}, 1),
+		inFlightByCust:    make(map[string]int),
+	}
+}
+
+// NotifyNewEntry wakes Run's poll loop immediately instead of waiting for
+// the next PollInterval tick - call this whenever a new audit entry (and
+// therefore a new reconciliation Job) has just been enqueued, so a fresh
+// prediction with an already-elapsed DueAt doesn't sit idle for a full
+// tick.
+func (w *Worker) NotifyNewEntry() {
+	select {
+	case w.wake <- struct{}{}:
+	default: // a wake-up is already pending
+	}
+}
+
+// Run polls Store for due jobs until ctx is done, reconciling each one it
+// finds. It blocks, so callers run it in its own goroutine.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		w.pollOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+func (w *Worker) pollInterval() time.Duration {
+	if w.PollInterval > 0 {
+		return w.PollInterval
+	}
+	return defaultPollInterval
+}
+
+func (w *Worker) batchSize() int {
+	if w.BatchSize > 0 {
+		return w.BatchSize
+	}
+	return defaultBatchSize
+}
+
+// pollOnce pulls one batch of due jobs and reconciles every one whose
+// customer is under MaxInFlightPerCustomer, concurrently.
+func (w *Worker) pollOnce(ctx context.Context) {
+	jobs, err := w.Store.DueJobs(ctx, time.Now(), w.batchSize())
+	if err != nil {
+		return
+	}
+	if w.Metrics != nil {
+		if pending, err := w.Store.Pending(ctx); err == nil {
+			w.Metrics.SetPending(pending)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		if !w.tryAcquire(job.CustomerID) {
+			continue
+		}
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			defer w.release(job.CustomerID)
+			w.reconcileOne(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+func (w *Worker) maxInFlightPerCustomer() int {
+	if w.MaxInFlightPerCustomer > 0 {
+		return w.MaxInFlightPerCustomer
+	}
+	return 1
+}
+
+func (w *Worker) tryAcquire(customerID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.inFlightByCust[customerID] >= w.maxInFlightPerCustomer() {
+		return false
+	}
+	w.inFlightByCust[customerID]++
+	return true
+}
+
+func (w *Worker) release(customerID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.inFlightByCust[customerID]--
+	if w.inFlightByCust[customerID] <= 0 {
+		delete(w.inFlightByCust, customerID)
+	}
+}
+
+// reconcileOne fetches job's actual LTV and folds the outcome back into
+// Store and, on success, the audit trail.
+func (w *Worker) reconcileOne(ctx context.Context, job Job) {
+	fetcher, ok := w.Fetchers[job.Platform]
+	if !ok {
+		w.fail(ctx, job, ErrFetcherNotFound.Error())
+		return
+	}
+
+	actualLTV, found, err := fetcher.FetchActual(ctx, job)
+	if err != nil {
+		w.backoff(ctx, job, err.Error())
+		return
+	}
+	if !found {
+		nextDueAt := time.Now().Add(w.recheckInterval())
+		_ = w.Store.Reschedule(ctx, job.RequestID, nextDueAt, 0, "")
+		return
+	}
+
+	w.complete(ctx, job, actualLTV)
+}
+
+// complete writes actualLTV back through Audit (if configured), marks job
+// Complete in Store, and records the resulting AccuracyRecord in Metrics.
+func (w *Worker) complete(ctx context.Context, job Job, actualLTV float64) {
+	if w.Audit != nil {
+		if err := w.Audit.UpdateActualLTV(ctx, job.RequestID, actualLTV, time.Now()); err != nil {
+			w.backoff(ctx, job, err.Error())
+			return
+		}
+	}
+
+	if err := w.Store.Complete(ctx, job.RequestID); err != nil {
+		return
+	}
+	if w.Metrics != nil {
+		w.Metrics.RecordReconciled(buildAccuracyRecord(job, actualLTV, time.Now()))
+	}
+}
+
+func (w *Worker) recheckInterval() time.Duration {
+	if w.RecheckInterval > 0 {
+		return w.RecheckInterval
+	}
+	return defaultRecheckInterval
+}
+
+func (w *Worker) maxAttempts() int {
+	if w.MaxAttempts > 0 {
+		return w.MaxAttempts
+	}
+	return defaultMaxAttempts
+}
+
+// backoff reschedules job after a transient fetcher error, or gives up via
+// Store.Fail once maxAttempts is exhausted.
+func (w *Worker) backoff(ctx context.Context, job Job, lastErr string) {
+	attempts := job.Attempts + 1
+	if attempts >= w.maxAttempts() {
+		w.fail(ctx, job, lastErr)
+		return
+	}
+	nextDueAt := time.Now().Add(w.calculateBackoff(attempts))
+	_ = w.Store.Reschedule(ctx, job.RequestID, nextDueAt, 1, lastErr)
+	if w.Metrics != nil {
+		w.Metrics.RecordRetried()
+	}
+}
+
+func (w *Worker) fail(ctx context.Context, job Job, reason string) {
+	_ = w.Store.Fail(ctx, job.RequestID, reason)
+	if w.Metrics != nil {
+		w.Metrics.RecordFailed()
+	}
+}
+
+// calculateBackoff mirrors shield.RetryPolicy.calculateBackoff's formula
+// (that method is unexported, and operates within one call rather than
+// across poll cycles, so it isn't directly reusable here): exponential
+// growth off InitialBackoff, capped at MaxBackoff, with up to
+// ±JitterFraction jitter.
+func (w *Worker) calculateBackoff(attempt int) time.Duration {
+	initial := w.InitialBackoff
+	if initial <= 0 {
+		initial = defaultInitialBackoff
+	}
+	max := w.MaxBackoff
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+	multiplier := w.BackoffMultiplier
+	if multiplier <= 0 {
+		multiplier = defaultBackoffMultiplier
+	}
+
+	backoff := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if backoff > float64(max) {
+		backoff = float64(max)
+	}
+	if w.JitterFraction > 0 {
+		jitter := backoff * w.JitterFraction * (2.0*rand.Float64() - 1.0)
+		backoff += jitter
+	}
+	if backoff < 0 {
+		backoff = 0
+	}
+	return time.Duration(backoff)
+}
+
+// ForceReconcile immediately reconciles one job by RequestID, bypassing
+// DueAt and MaxInFlightPerCustomer - the admin RPC's entry point. See
+// cmd/syncflow's serveForceReconcile.
+func (w *Worker) ForceReconcile(ctx context.Context, requestID string) (AccuracyRecord, error) {
+	job, ok, err := w.Store.Get(ctx, requestID)
+	if err != nil {
+		return AccuracyRecord{}, fmt.Errorf("reconcile: force-reconciling %s: %w", requestID, err)
+	}
+	if !ok {
+		return AccuracyRecord{}, fmt.Errorf("reconcile: %s is not a pending reconciliation job", requestID)
+	}
+
+	fetcher, ok := w.Fetchers[job.Platform]
+	if !ok {
+		return AccuracyRecord{}, ErrFetcherNotFound
+	}
+	actualLTV, found, err := fetcher.FetchActual(ctx, job)
+	if err != nil {
+		return AccuracyRecord{}, fmt.Errorf("reconcile: force-reconciling %s: %w", requestID, err)
+	}
+	if !found {
+		return AccuracyRecord{}, fmt.Errorf("reconcile: actual LTV for %s has not posted yet", requestID)
+	}
+
+	if w.Audit != nil {
+		if err := w.Audit.UpdateActualLTV(ctx, requestID, actualLTV, time.Now()); err != nil {
+			return AccuracyRecord{}, fmt.Errorf("reconcile: force-reconciling %s: %w", requestID, err)
+		}
+	}
+
+	record := buildAccuracyRecord(job, actualLTV, time.Now())
+	if err := w.Store.Complete(ctx, requestID); err != nil {
+		return AccuracyRecord{}, fmt.Errorf("reconcile: force-reconciling %s: %w", requestID, err)
+	}
+	if w.Metrics != nil {
+		w.Metrics.RecordReconciled(record)
+	}
+	return record, nil
+}
@@ -0,0 +1,228 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeFetcher is an ActualsFetcher stub driven entirely by test-supplied
+// behavior, so Worker tests don't need a real CRM/analytics endpoint.
+type fakeFetcher struct {
+	name string
+	fn   func(job Job) (float64, bool, error)
+}
+
+func (f *fakeFetcher) Name() string { return f.name }
+
+func (f *fakeFetcher) FetchActual(ctx context.Context, job Job) (float64, bool, error) {
+	return f.fn(job)
+}
+
+func newTestWorker(t *testing.T, fetchers ...*fakeFetcher) (*Worker, *BoltStore) {
+	t.Helper()
+	store := newTestBoltStore(t)
+	w := NewWorker(store, nil, NewMetrics())
+	for _, f := range fetchers {
+		w.Fetchers[f.name] = f
+	}
+	return w, store
+}
+
+func TestWorker_ReconcileOneCompletesOnFoundActual(t *testing.T) {
+	fetcher := &fakeFetcher{name: "stripe", fn: func(job Job) (float64, bool, error) { return 120, true, nil }}
+	w, store := newTestWorker(t, fetcher)
+	ctx := context.Background()
+	job := Job{RequestID: "req-1", CustomerID: "cust-1", Platform: "stripe", PredictedLTV: 100, DueAt: time.Now()}
+	if err := store.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	w.reconcileOne(ctx, job)
+
+	if _, ok, err := store.Get(ctx, "req-1"); err != nil || ok {
+		t.Fatalf("expected the job to be Complete and gone, got ok=%v err=%v", ok, err)
+	}
+	if w.Metrics.reconciled != 1 {
+		t.Fatalf("expected 1 reconciled job recorded, got %d", w.Metrics.reconciled)
+	}
+}
+
+func TestWorker_ReconcileOneReschedulesWhenNotFound(t *testing.T) {
+	fetcher := &fakeFetcher{name: "stripe", fn: func(job Job) (float64, bool, error) { return 0, false, nil }}
+	w, store := newTestWorker(t, fetcher)
+	ctx := context.Background()
+	job := Job{RequestID: "req-1", Platform: "stripe", DueAt: time.Now()}
+	if err := store.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	w.reconcileOne(ctx, job)
+
+	got, ok, err := store.Get(ctx, "req-1")
+	if err != nil || !ok {
+		t.Fatalf("expected the job to remain pending, got ok=%v err=%v", ok, err)
+	}
+	if !got.DueAt.After(time.Now().Add(time.Hour)) {
+		t.Fatalf("expected DueAt pushed out by the recheck interval, got %v", got.DueAt)
+	}
+	if got.Attempts != 0 {
+		t.Fatalf("expected a not-found recheck to NOT count as an attempt, got %d", got.Attempts)
+	}
+}
+
+func TestWorker_ReconcileOneBacksOffOnFetcherErrorThenFails(t *testing.T) {
+	fetcher := &fakeFetcher{name: "stripe", fn: func(job Job) (float64, bool, error) {
+		return 0, false, errors.New("upstream exploded")
+	}}
+	w, store := newTestWorker(t, fetcher)
+	w.MaxAttempts = 2
+	ctx := context.Background()
+	job := Job{RequestID: "req-1", Platform: "stripe", DueAt: time.Now()}
+	if err := store.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	w.reconcileOne(ctx, job)
+	got, ok, err := store.Get(ctx, "req-1")
+	if err != nil || !ok {
+		t.Fatalf("expected the job to still be pending after 1 transient error, got ok=%v err=%v", ok, err)
+	}
+	if got.Attempts != 1 {
+		t.Fatalf("expected Attempts=1, got %d", got.Attempts)
+	}
+	if w.Metrics.retried != 1 {
+		t.Fatalf("expected 1 retry recorded, got %d", w.Metrics.retried)
+	}
+
+	w.reconcileOne(ctx, got)
+	if _, ok, err := store.Get(ctx, "req-1"); err != nil || ok {
+		t.Fatalf("expected the job to be Failed and gone after exhausting MaxAttempts, got ok=%v err=%v", ok, err)
+	}
+	if w.Metrics.failed != 1 {
+		t.Fatalf("expected 1 failure recorded, got %d", w.Metrics.failed)
+	}
+}
+
+func TestWorker_ReconcileOneFailsImmediatelyWithNoFetcher(t *testing.T) {
+	w, store := newTestWorker(t)
+	ctx := context.Background()
+	job := Job{RequestID: "req-1", Platform: "unknown-platform", DueAt: time.Now()}
+	if err := store.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	w.reconcileOne(ctx, job)
+
+	if _, ok, err := store.Get(ctx, "req-1"); err != nil || ok {
+		t.Fatalf("expected the job to be Failed when no fetcher is registered, got ok=%v err=%v", ok, err)
+	}
+	if w.Metrics.failed != 1 {
+		t.Fatalf("expected 1 failure recorded, got %d", w.Metrics.failed)
+	}
+}
+
+func TestWorker_PollOnceLimitsConcurrencyPerCustomer(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+	fetcher := &fakeFetcher{name: "stripe", fn: func(job Job) (float64, bool, error) {
+		started <- struct{}{}
+		<-release
+		return 1, true, nil
+	}}
+	w, store := newTestWorker(t, fetcher)
+	w.MaxInFlightPerCustomer = 1
+	ctx := context.Background()
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		job := Job{RequestID: string(rune('a' + i)), CustomerID: "cust-1", Platform: "stripe", DueAt: now.Add(-time.Minute)}
+		if err := store.Enqueue(ctx, job); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		w.pollOnce(ctx)
+		done <- struct{}{}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("expected at least one job to start reconciling")
+	}
+	select {
+	case <-started:
+		t.Fatal("expected only 1 in-flight reconciliation for this customer, got a second")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("pollOnce never returned")
+	}
+}
+
+func TestWorker_ForceReconcileSuccess(t *testing.T) {
+	fetcher := &fakeFetcher{name: "stripe", fn: func(job Job) (float64, bool, error) { return 90, true, nil }}
+	w, store := newTestWorker(t, fetcher)
+	ctx := context.Background()
+	job := Job{RequestID: "req-1", Platform: "stripe", PredictedLTV: 100, DueAt: time.Now().Add(time.Hour)}
+	if err := store.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	record, err := w.ForceReconcile(ctx, "req-1")
+	if err != nil {
+		t.Fatalf("ForceReconcile failed: %v", err)
+	}
+	if record.ActualLTV != 90 {
+		t.Fatalf("expected ActualLTV=90, got %v", record.ActualLTV)
+	}
+	if _, ok, err := store.Get(ctx, "req-1"); err != nil || ok {
+		t.Fatalf("expected ForceReconcile to Complete the job, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestWorker_ForceReconcileErrorsWhenNotFound(t *testing.T) {
+	w, _ := newTestWorker(t)
+	if _, err := w.ForceReconcile(context.Background(), "does-not-exist"); err == nil {
+		t.Fatal("expected an error for an unknown RequestID")
+	}
+}
+
+func TestWorker_ForceReconcileErrorsWhenNotYetPosted(t *testing.T) {
+	fetcher := &fakeFetcher{name: "stripe", fn: func(job Job) (float64, bool, error) { return 0, false, nil }}
+	w, store := newTestWorker(t, fetcher)
+	ctx := context.Background()
+	job := Job{RequestID: "req-1", Platform: "stripe", DueAt: time.Now().Add(time.Hour)}
+	if err := store.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	if _, err := w.ForceReconcile(ctx, "req-1"); err == nil {
+		t.Fatal("expected an error when the actual hasn't posted yet")
+	}
+}
+
+func TestWorker_CalculateBackoffGrowsAndCaps(t *testing.T) {
+	w := NewWorker(nil, nil, nil)
+	w.InitialBackoff = time.Second
+	w.MaxBackoff = 10 * time.Second
+	w.BackoffMultiplier = 2.0
+	w.JitterFraction = 0
+
+	if got := w.calculateBackoff(1); got != time.Second {
+		t.Fatalf("expected attempt 1 backoff of 1s, got %v", got)
+	}
+	if got := w.calculateBackoff(3); got != 4*time.Second {
+		t.Fatalf("expected attempt 3 backoff of 4s, got %v", got)
+	}
+	if got := w.calculateBackoff(10); got != w.MaxBackoff {
+		t.Fatalf("expected backoff capped at MaxBackoff, got %v", got)
+	}
+}
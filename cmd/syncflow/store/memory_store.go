@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type cachedPrediction struct {
+	Prediction
+	expiresAt time.Time
+}
+
+type spendRecord struct {
+	amount    float64
+	timestamp time.Time
+}
+
+// MemoryStore is the in-memory Store - preserving the original
+// process-local behavior cmd/syncflow/main.go's cache map and
+// spendHistory slice had before being cut over to Store.
+type MemoryStore struct {
+	mu          sync.Mutex
+	predictions map[string]cachedPrediction
+	spend       []spendRecord
+	campaigns   []string
+	nextIdx     int
+}
+
+// NewMemoryStore creates an empty store. campaigns seeds the rotation
+// NextCampaigns hands out; a nil/empty list means NextCampaigns always
+// returns none.
+func NewMemoryStore(campaigns []string) *MemoryStore {
+	return &MemoryStore{
+		predictions: make(map[string]cachedPrediction),
+		campaigns:   campaigns,
+	}
+}
+
+// GetLTV implements Store.
+func (s *MemoryStore) GetLTV(ctx context.Context, customerID string) (Prediction, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cached, ok := s.predictions[customerID]
+	if !ok || time.Now().After(cached.expiresAt) {
+		return Prediction{}, false, nil
+	}
+	return cached.Prediction, true, nil
+}
+
+// PutLTV implements Store.
+func (s *MemoryStore) PutLTV(ctx context.Context, customerID string, prediction Prediction, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.predictions[customerID] = cachedPrediction{Prediction: prediction, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// RecordSpend implements Store.
+func (s *MemoryStore) RecordSpend(ctx context.Context, amount float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spend = append(s.spend, spendRecord{amount: amount, timestamp: time.Now()})
+	return nil
+}
+
+// WindowSpend implements Store, trimming anything older than window out
+// of spend as a side effect so the slice doesn't grow without bound.
+func (s *MemoryStore) WindowSpend(ctx context.Context, window time.Duration) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-window)
+	var total float64
+	kept := s.spend[:0]
+	for _, r := range s.spend {
+		if r.timestamp.After(cutoff) {
+			total += r.amount
+			kept = append(kept, r)
+		}
+	}
+	s.spend = kept
+	return total, nil
+}
+
+// NextCampaigns implements Store, round-robining through campaigns.
+func (s *MemoryStore) NextCampaigns(ctx context.Context, n int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.campaigns) == 0 || n <= 0 {
+		return nil, nil
+	}
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		out = append(out, s.campaigns[s.nextIdx%len(s.campaigns)])
+		s.nextIdx++
+	}
+	return out, nil
+}
+
+// Close implements Store. MemoryStore holds no resources to release.
+func (s *MemoryStore) Close() error { return nil }
@@ -0,0 +1,233 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// defaultPipePeriod batches PutLTV/RecordSpend writes into one pipelined
+// round trip at most this often, trading a small amount of staleness on
+// concurrent writers for fewer RTTs under load.
+const defaultPipePeriod = 50 * time.Millisecond
+
+// RedisStore implements Store on go-redis, the same client library
+// budget.RedisSpendStore and cmd/syncengage/coordinator already use for
+// cross-replica state - so every syncflow replica shares one semantic
+// cache, one spend window, and one campaign rotation instead of each
+// tracking its own.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+
+	// PipePeriod controls how often buffered writes are flushed as one
+	// pipeline; NewRedisStore sets it to defaultPipePeriod and starts the
+	// flush loop, which reads it once, so changing it after NewRedisStore
+	// returns does not reliably change the flush interval.
+	PipePeriod time.Duration
+
+	mu      sync.Mutex
+	pending []func(redis.Pipeliner)
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewRedisStore creates a RedisStore keyed under prefix (e.g.
+// "syncflow") and seeds the campaign rotation with campaigns if the
+// queue isn't already populated from a prior run.
+func NewRedisStore(ctx context.Context, client *redis.Client, prefix string, campaigns []string) (*RedisStore, error) {
+	s := &RedisStore{
+		client:     client,
+		prefix:     prefix,
+		PipePeriod: defaultPipePeriod,
+		stop:       make(chan struct{}),
+	}
+
+	key := s.campaignsKey()
+	n, err := client.LLen(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("store: checking campaign queue: %w", err)
+	}
+	if n == 0 && len(campaigns) > 0 {
+		members := make([]interface{}, len(campaigns))
+		for i, c := range campaigns {
+			members[i] = c
+		}
+		if err := client.RPush(ctx, key, members...).Err(); err != nil {
+			return nil, fmt.Errorf("store: seeding campaign queue: %w", err)
+		}
+	}
+
+	s.wg.Add(1)
+	go s.flushLoop()
+	return s, nil
+}
+
+func (s *RedisStore) ltvKey(customerID string) string {
+	return fmt.Sprintf("%s:ltv:%s", s.prefix, customerID)
+}
+func (s *RedisStore) spendKey() string     { return s.prefix + ":spend" }
+func (s *RedisStore) campaignsKey() string { return s.prefix + ":campaigns" }
+
+func (s *RedisStore) flushLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.PipePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.flush(context.Background())
+		case <-s.stop:
+			s.flush(context.Background())
+			return
+		}
+	}
+}
+
+// flush executes every buffered write as a single pipeline. Reads call
+// this first so they see their own writes immediately rather than
+// waiting for the next tick.
+func (s *RedisStore) flush(ctx context.Context) error {
+	s.mu.Lock()
+	ops := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	if len(ops) == 0 {
+		return nil
+	}
+	pipe := s.client.Pipeline()
+	for _, op := range ops {
+		op(pipe)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("store: flushing pipeline: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) enqueue(op func(redis.Pipeliner)) {
+	s.mu.Lock()
+	s.pending = append(s.pending, op)
+	s.mu.Unlock()
+}
+
+// GetLTV implements Store.
+func (s *RedisStore) GetLTV(ctx context.Context, customerID string) (Prediction, bool, error) {
+	if err := s.flush(ctx); err != nil {
+		return Prediction{}, false, err
+	}
+
+	raw, err := s.client.Get(ctx, s.ltvKey(customerID)).Bytes()
+	if err == redis.Nil {
+		return Prediction{}, false, nil
+	}
+	if err != nil {
+		return Prediction{}, false, fmt.Errorf("store: reading cached LTV for %s: %w", customerID, err)
+	}
+
+	var prediction Prediction
+	if err := json.Unmarshal(raw, &prediction); err != nil {
+		return Prediction{}, false, fmt.Errorf("store: decoding cached LTV for %s: %w", customerID, err)
+	}
+	return prediction, true, nil
+}
+
+// PutLTV implements Store, batching the write into the next pipeline
+// flush rather than a dedicated round trip.
+func (s *RedisStore) PutLTV(ctx context.Context, customerID string, prediction Prediction, ttl time.Duration) error {
+	payload, err := json.Marshal(prediction)
+	if err != nil {
+		return fmt.Errorf("store: encoding LTV for %s: %w", customerID, err)
+	}
+
+	key := s.ltvKey(customerID)
+	s.enqueue(func(pipe redis.Pipeliner) {
+		pipe.Set(ctx, key, payload, ttl)
+	})
+	return nil
+}
+
+// RecordSpend implements Store, batching the append into the spend
+// window's sorted set, scored by timestamp so WindowSpend/trimming can
+// range over it cheaply.
+func (s *RedisStore) RecordSpend(ctx context.Context, amount float64) error {
+	now := time.Now()
+	member := fmt.Sprintf("%d:%f", now.UnixNano(), amount)
+	key := s.spendKey()
+	s.enqueue(func(pipe redis.Pipeliner) {
+		pipe.ZAdd(ctx, key, &redis.Z{Score: float64(now.UnixNano()), Member: member})
+	})
+	return nil
+}
+
+// WindowSpend implements Store, summing every spend recorded within
+// window and trimming anything older so the sorted set doesn't grow
+// without bound.
+func (s *RedisStore) WindowSpend(ctx context.Context, window time.Duration) (float64, error) {
+	if err := s.flush(ctx); err != nil {
+		return 0, err
+	}
+
+	key := s.spendKey()
+	cutoff := time.Now().Add(-window)
+
+	members, err := s.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: fmt.Sprintf("%d", cutoff.UnixNano()),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("store: reading spend window: %w", err)
+	}
+
+	var total float64
+	for _, member := range members {
+		var nanos int64
+		var amount float64
+		if _, err := fmt.Sscanf(member, "%d:%f", &nanos, &amount); err != nil {
+			continue
+		}
+		total += amount
+	}
+
+	if err := s.client.ZRemRangeByScore(ctx, key, "-inf", fmt.Sprintf("(%d", cutoff.UnixNano())).Err(); err != nil {
+		return 0, fmt.Errorf("store: trimming spend window: %w", err)
+	}
+	return total, nil
+}
+
+// NextCampaigns implements Store, atomically rotating up to n campaigns
+// off the shared queue via RPOPLPUSH so concurrent replicas each see the
+// next campaign in line rather than racing for the same one.
+func (s *RedisStore) NextCampaigns(ctx context.Context, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	key := s.campaignsKey()
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		campaign, err := s.client.RPopLPush(ctx, key, key).Result()
+		if err == redis.Nil {
+			break
+		}
+		if err != nil {
+			return out, fmt.Errorf("store: rotating campaign queue: %w", err)
+		}
+		out = append(out, campaign)
+	}
+	return out, nil
+}
+
+// Close stops the background flush loop, flushing any pending writes
+// first. The underlying *redis.Client is owned by the caller.
+func (s *RedisStore) Close() error {
+	close(s.stop)
+	s.wg.Wait()
+	return nil
+}
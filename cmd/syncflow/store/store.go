@@ -0,0 +1,45 @@
+// Package store replaces cmd/syncflow/main.go's process-local semantic
+// cache and spend history with a pluggable Store, so a fleet of syncflow
+// replicas behind a load balancer can share one view instead of each
+// tracking its own - the same motivation behind budget.RedisSpendStore
+// and cmd/syncengage/coordinator, applied to the bidding loop's own state.
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Prediction is a cached LTV prediction - the GetLTV/PutLTV payload, kept
+// free of a Timestamp field since Store itself owns expiry via ttl.
+type Prediction struct {
+	LTV         float64
+	Explanation string
+}
+
+// Store is implemented by MemoryStore (a single process, lost on
+// restart) and RedisStore (shared across replicas, survives a restart).
+type Store interface {
+	// GetLTV returns the cached prediction for customerID, with ok false
+	// if nothing is cached or it has expired.
+	GetLTV(ctx context.Context, customerID string) (prediction Prediction, ok bool, err error)
+
+	// PutLTV caches prediction for customerID until ttl elapses.
+	PutLTV(ctx context.Context, customerID string, prediction Prediction, ttl time.Duration) error
+
+	// RecordSpend records amount spent just now against the rolling
+	// burst-budget window CheckBudget enforces.
+	RecordSpend(ctx context.Context, amount float64) error
+
+	// WindowSpend returns the total amount RecordSpend has recorded
+	// within the last window.
+	WindowSpend(ctx context.Context, window time.Duration) (float64, error)
+
+	// NextCampaigns returns up to n campaign IDs due to be bid on next,
+	// rotating across every replica sharing this Store so the same
+	// campaign isn't picked up twice in parallel.
+	NextCampaigns(ctx context.Context, n int) ([]string, error)
+
+	// Close releases any resources held by the store.
+	Close() error
+}
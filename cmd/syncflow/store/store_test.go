@@ -0,0 +1,212 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// newTestRedisStore starts a miniredis instance and returns a RedisStore
+// backed by it, cleaned up automatically.
+func newTestRedisStore(t *testing.T, campaigns []string) *RedisStore {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	s, err := NewRedisStore(context.Background(), client, "test", campaigns)
+	if err != nil {
+		t.Fatalf("NewRedisStore failed: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// testStores runs every Store behavior test against both implementations,
+// so MemoryStore and RedisStore are held to the same contract.
+func testStores(t *testing.T) map[string]Store {
+	return map[string]Store{
+		"MemoryStore": NewMemoryStore(nil),
+		"RedisStore":  newTestRedisStore(t, nil),
+	}
+}
+
+func TestStore_GetLTVMissOnEmpty(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			_, ok, err := s.GetLTV(context.Background(), "cust-1")
+			if err != nil {
+				t.Fatalf("GetLTV failed: %v", err)
+			}
+			if ok {
+				t.Fatal("expected a cache miss on an empty store")
+			}
+		})
+	}
+}
+
+func TestStore_PutThenGetLTV(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			want := Prediction{LTV: 42.5, Explanation: "high spend"}
+			if err := s.PutLTV(ctx, "cust-1", want, time.Minute); err != nil {
+				t.Fatalf("PutLTV failed: %v", err)
+			}
+			got, ok, err := s.GetLTV(ctx, "cust-1")
+			if err != nil {
+				t.Fatalf("GetLTV failed: %v", err)
+			}
+			if !ok {
+				t.Fatal("expected a cache hit after PutLTV")
+			}
+			if got != want {
+				t.Fatalf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_GetLTVExpires(t *testing.T) {
+	s := NewMemoryStore(nil)
+	ctx := context.Background()
+	if err := s.PutLTV(ctx, "cust-1", Prediction{LTV: 1}, time.Millisecond); err != nil {
+		t.Fatalf("PutLTV failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	_, ok, err := s.GetLTV(ctx, "cust-1")
+	if err != nil {
+		t.Fatalf("GetLTV failed: %v", err)
+	}
+	if ok {
+		t.Fatal("expected the cached prediction to have expired")
+	}
+}
+
+func TestRedisStore_GetLTVExpires(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	s, err := NewRedisStore(context.Background(), client, "test", nil)
+	if err != nil {
+		t.Fatalf("NewRedisStore failed: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	ctx := context.Background()
+	if err := s.PutLTV(ctx, "cust-1", Prediction{LTV: 1}, time.Second); err != nil {
+		t.Fatalf("PutLTV failed: %v", err)
+	}
+	// Force the buffered write to actually reach miniredis before
+	// advancing its clock - PutLTV only enqueues it for the next
+	// pipeline flush.
+	if _, _, err := s.GetLTV(ctx, "cust-1"); err != nil {
+		t.Fatalf("GetLTV (pre-expiry) failed: %v", err)
+	}
+	mr.FastForward(2 * time.Second)
+
+	_, ok, err := s.GetLTV(ctx, "cust-1")
+	if err != nil {
+		t.Fatalf("GetLTV failed: %v", err)
+	}
+	if ok {
+		t.Fatal("expected the cached prediction to have expired")
+	}
+}
+
+func TestStore_WindowSpendSumsRecentAndTrimsOld(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := s.RecordSpend(ctx, 10); err != nil {
+				t.Fatalf("RecordSpend failed: %v", err)
+			}
+			if err := s.RecordSpend(ctx, 5); err != nil {
+				t.Fatalf("RecordSpend failed: %v", err)
+			}
+
+			total, err := s.WindowSpend(ctx, time.Hour)
+			if err != nil {
+				t.Fatalf("WindowSpend failed: %v", err)
+			}
+			if total != 15 {
+				t.Fatalf("expected window spend 15, got %v", total)
+			}
+
+			// A zero-width window in the past trims everything recorded
+			// so far without counting it.
+			total, err = s.WindowSpend(ctx, -time.Hour)
+			if err != nil {
+				t.Fatalf("WindowSpend failed: %v", err)
+			}
+			if total != 0 {
+				t.Fatalf("expected a stale window to report 0, got %v", total)
+			}
+
+			total, err = s.WindowSpend(ctx, time.Hour)
+			if err != nil {
+				t.Fatalf("WindowSpend failed: %v", err)
+			}
+			if total != 0 {
+				t.Fatalf("expected trimmed spend to stay gone, got %v", total)
+			}
+		})
+	}
+}
+
+func TestStore_NextCampaignsRotates(t *testing.T) {
+	campaigns := []string{"camp-a", "camp-b"}
+	// MemoryStore and RedisStore rotate through the same set in different
+	// orders (a plain index vs. Redis's RPOPLPUSH) - both are valid
+	// round-robins, so each gets its own expected sequence rather than
+	// asserting they agree.
+	cases := map[string]struct {
+		store Store
+		want  []string
+	}{
+		"MemoryStore": {NewMemoryStore(campaigns), []string{"camp-a", "camp-b", "camp-a"}},
+		"RedisStore":  {newTestRedisStore(t, campaigns), []string{"camp-b", "camp-a", "camp-b"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.store.NextCampaigns(context.Background(), 3)
+			if err != nil {
+				t.Fatalf("NextCampaigns failed: %v", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range tc.want {
+				if got[i] != tc.want[i] {
+					t.Fatalf("got %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_NextCampaignsEmptyWhenUnseeded(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			got, err := s.NextCampaigns(context.Background(), 2)
+			if err != nil {
+				t.Fatalf("NextCampaigns failed: %v", err)
+			}
+			if len(got) != 0 {
+				t.Fatalf("expected no campaigns, got %v", got)
+			}
+		})
+	}
+}
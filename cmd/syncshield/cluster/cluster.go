@@ -0,0 +1,46 @@
+// Package cluster lets a fleet of kiki-agent nodes answer compliance and
+// budget questions about each other instead of only their own in-memory
+// state, the way Mattermost's einterfaces.ClusterInterface lets one node
+// ask another "what's in your log" or "broadcast this to everyone".
+// GetAuditEvents and GetBudgetStats fan a query out to every known peer
+// and merge the answers; BroadcastBudgetVeto pushes a budget-exceeded
+// decision to every peer in real time, so a customer a budget veto has
+// already blocked on one node doesn't keep bidding through the others
+// while they independently discover the same overspend.
+package cluster
+
+import (
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/compliance"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// AuditFilter narrows a GetAuditEvents query. A zero Since or Until leaves
+// that bound open; an empty CustomerID matches every customer.
+type AuditFilter struct {
+	CustomerID string
+	Since      time.Time
+	Until      time.Time
+}
+
+// ClusterInterface is what a node exposes to the rest of the fleet:
+// cross-node audit queries, cross-node budget visibility, and a way to
+// push a budget veto out to every peer the moment it fires locally.
+type ClusterInterface interface {
+	// GetAuditEvents returns every event matching filter across this
+	// node and every peer it knows about, merged and sorted by
+	// Timestamp.
+	GetAuditEvents(filter AuditFilter) ([]compliance.AuditEvent, error)
+
+	// GetBudgetStats returns each known node's WindowStats, keyed by
+	// node ID, so an operator can see the whole fleet's spend at once
+	// instead of polling each node separately.
+	GetBudgetStats() (map[string]shield.WindowStats, error)
+
+	// BroadcastBudgetVeto records customerID as vetoed on this node and
+	// pushes that decision to every peer, so they stop bidding for the
+	// same customer instead of each independently blowing through their
+	// own slice of the budget before noticing.
+	BroadcastBudgetVeto(customerID string) error
+}
@@ -0,0 +1,148 @@
+package cluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/compliance"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+func newTestNode(t *testing.T, id string) *Node {
+	t.Helper()
+	dir := t.TempDir()
+	logger, err := compliance.NewGDPRAuditLogger(
+		filepath.Join(dir, "audit.csv"),
+		filepath.Join(dir, "audit.json"),
+		90,
+	)
+	if err != nil {
+		t.Fatalf("NewGDPRAuditLogger failed: %v", err)
+	}
+	t.Cleanup(func() { logger.Close() })
+
+	return NewNode(id, logger, map[string]*shield.BudgetManager{
+		"meta": shield.NewBudgetManager(1000),
+	})
+}
+
+func newTestServer(t *testing.T, n *Node) string {
+	t.Helper()
+	mux := http.NewServeMux()
+	RegisterHandlers(mux, n)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestNode_GetAuditEventsMergesPeerResults(t *testing.T) {
+	local := newTestNode(t, "local")
+	if err := local.AuditLogger.LogBidValidation("cust-1", 42, "APPROVED", "ok"); err != nil {
+		t.Fatalf("LogBidValidation failed: %v", err)
+	}
+
+	peer := newTestNode(t, "peer")
+	if err := peer.AuditLogger.LogBidValidation("cust-2", 17, "APPROVED", "ok"); err != nil {
+		t.Fatalf("LogBidValidation failed: %v", err)
+	}
+	peerURL := newTestServer(t, peer)
+	local.Peers.Add(peerURL)
+
+	events, err := local.GetAuditEvents(AuditFilter{})
+	if err != nil {
+		t.Fatalf("GetAuditEvents failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 merged events, got %d", len(events))
+	}
+}
+
+func TestNode_GetAuditEventsSkipsUnreachablePeer(t *testing.T) {
+	local := newTestNode(t, "local")
+	if err := local.AuditLogger.LogBidValidation("cust-1", 42, "APPROVED", "ok"); err != nil {
+		t.Fatalf("LogBidValidation failed: %v", err)
+	}
+	local.Peers.Add("http://127.0.0.1:0")
+
+	events, err := local.GetAuditEvents(AuditFilter{})
+	if err != nil {
+		t.Fatalf("expected an unreachable peer to be skipped, not fail the query: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected the 1 local event, got %d", len(events))
+	}
+}
+
+func TestNode_GetBudgetStatsKeyedByNodeID(t *testing.T) {
+	local := newTestNode(t, "local")
+	peer := newTestNode(t, "peer")
+	peerURL := newTestServer(t, peer)
+	local.Peers.Add(peerURL)
+
+	stats, err := local.GetBudgetStats()
+	if err != nil {
+		t.Fatalf("GetBudgetStats failed: %v", err)
+	}
+	if _, ok := stats["local"]; !ok {
+		t.Fatalf("expected local node's own stats under its ID, got %+v", stats)
+	}
+	if _, ok := stats["peer"]; !ok {
+		t.Fatalf("expected peer's stats under its reported ID, got %+v", stats)
+	}
+}
+
+func TestNode_BroadcastBudgetVetoPushesToPeers(t *testing.T) {
+	local := newTestNode(t, "local")
+	peer := newTestNode(t, "peer")
+	peerURL := newTestServer(t, peer)
+	local.Peers.Add(peerURL)
+
+	var notified string
+	local.OnVeto = append(local.OnVeto, func(customerID string) { notified = customerID })
+
+	if err := local.BroadcastBudgetVeto("cust-1"); err != nil {
+		t.Fatalf("BroadcastBudgetVeto failed: %v", err)
+	}
+	if notified != "cust-1" {
+		t.Fatalf("expected OnVeto to fire with cust-1, got %q", notified)
+	}
+	if !local.IsVetoed("cust-1") {
+		t.Fatalf("expected local node to record the veto")
+	}
+
+	// Give the peer's handler a moment to process the push.
+	deadline := time.Now().Add(time.Second)
+	for !peer.IsVetoed("cust-1") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !peer.IsVetoed("cust-1") {
+		t.Fatalf("expected the veto to be pushed to the peer")
+	}
+}
+
+func TestNode_BroadcastBudgetVetoRejectsEmptyCustomerID(t *testing.T) {
+	local := newTestNode(t, "local")
+	if err := local.BroadcastBudgetVeto(""); err == nil {
+		t.Fatal("expected an error for an empty customerID")
+	}
+}
+
+func TestPeerList_AddRemoveURLs(t *testing.T) {
+	p := NewPeerList("http://seed:9090")
+	p.Add("http://a:9090")
+	p.Add("http://a:9090") // no-op on repeat add
+
+	urls := p.URLs()
+	if len(urls) != 2 {
+		t.Fatalf("expected 2 known peers, got %d: %v", len(urls), urls)
+	}
+
+	p.Remove("http://seed:9090")
+	urls = p.URLs()
+	if len(urls) != 1 || urls[0] != "http://a:9090" {
+		t.Fatalf("expected only http://a:9090 to remain, got %v", urls)
+	}
+}
@@ -0,0 +1,138 @@
+package cluster
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const adminAuditsPath = "/admin/audits"
+
+// RegisterHandlers wires Node's admin and peer-to-peer endpoints onto mux:
+//
+//   - GET  /admin/audits        - fanned-out audit query, for operators
+//   - GET  /cluster/peers       - this node's known peer URLs, for Heartbeat
+//   - GET  /cluster/audits      - this node's LOCAL audit events only
+//   - GET  /cluster/budget-stats - this node's LOCAL budget stats only
+//   - POST /cluster/veto        - record a peer-pushed budget veto
+//
+// The /cluster/* endpoints answer from local state only, never fanning
+// out themselves - GetAuditEvents and GetBudgetStats already fan out to
+// every peer, so a peer answering with its own fan-out too would turn
+// one admin query into an exponential blow-up across the mesh.
+func RegisterHandlers(mux *http.ServeMux, n *Node) {
+	mux.HandleFunc(adminAuditsPath, n.handleAdminAudits)
+	mux.HandleFunc(peersPath, n.handlePeers)
+	mux.HandleFunc(auditsPath, n.handleLocalAudits)
+	mux.HandleFunc(budgetStatsPath, n.handleLocalBudgetStats)
+	mux.HandleFunc(vetoPath, n.handleVeto)
+}
+
+func (n *Node) handleAdminAudits(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, err := filterFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events, err := n.GetAuditEvents(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, events)
+}
+
+func (n *Node) handlePeers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, n.Peers.URLs())
+}
+
+func (n *Node) handleLocalAudits(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, err := filterFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events, err := n.AuditLogger.QueryEvents(filter.CustomerID, filter.Since, filter.Until)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, events)
+}
+
+func (n *Node) handleLocalBudgetStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, struct {
+		NodeID string      `json:"node_id"`
+		Stats  interface{} `json:"stats"`
+	}{NodeID: n.ID, Stats: n.localBudgetStats()})
+}
+
+func (n *Node) handleVeto(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		CustomerID string `json:"customer_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if body.CustomerID == "" {
+		http.Error(w, "customer_id is required", http.StatusBadRequest)
+		return
+	}
+
+	n.recordVetoLocally(body.CustomerID)
+	w.WriteHeader(http.StatusOK)
+}
+
+func filterFromQuery(r *http.Request) (AuditFilter, error) {
+	q := r.URL.Query()
+
+	since, err := parseTimeParam(q.Get("since"))
+	if err != nil {
+		return AuditFilter{}, err
+	}
+	until, err := parseTimeParam(q.Get("until"))
+	if err != nil {
+		return AuditFilter{}, err
+	}
+
+	return AuditFilter{
+		CustomerID: q.Get("customer_id"),
+		Since:      since,
+		Until:      until,
+	}, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,252 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/compliance"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+const (
+	auditsPath      = "/cluster/audits"
+	budgetStatsPath = "/cluster/budget-stats"
+	vetoPath        = "/cluster/veto"
+	peersPath       = "/cluster/peers"
+
+	peerRequestTimeout = 5 * time.Second
+)
+
+// Node is one kiki-agent instance's ClusterInterface implementation. It
+// answers peer queries from its own AuditLogger and BudgetManagers, and
+// answers its own queries by fanning out to every peer in Peers and
+// merging the results in with its local state.
+type Node struct {
+	// ID identifies this node in GetBudgetStats' returned map and in
+	// veto log lines - an address or hostname, whatever's stable enough
+	// for an operator to recognize it by.
+	ID string
+
+	AuditLogger    *compliance.GDPRAuditLogger
+	BudgetManagers map[string]*shield.BudgetManager // keyed by platform, e.g. "meta", "google_ads"
+	Peers          *PeerList
+
+	// HTTPClient is used for every peer call. Set its Transport to a
+	// credential.MutualTLSProvider (or any other http.RoundTripper) for
+	// mTLS between nodes; nil uses http.DefaultClient.
+	HTTPClient *http.Client
+
+	mu              sync.Mutex
+	vetoedCustomers map[string]time.Time
+
+	// OnVeto, when set, is called for every customerID this node learns
+	// is vetoed - from its own BroadcastBudgetVeto call or from a peer's
+	// push to this node's veto handler - so callers (e.g. the Governor
+	// in cmd/syncshield/main.go) can fold it into their own bid checks.
+	OnVeto []func(customerID string)
+}
+
+// NewNode creates a Node with an empty PeerList and the default
+// http.Client.
+func NewNode(id string, auditLogger *compliance.GDPRAuditLogger, budgetManagers map[string]*shield.BudgetManager) *Node {
+	return &Node{
+		ID:              id,
+		AuditLogger:     auditLogger,
+		BudgetManagers:  budgetManagers,
+		Peers:           NewPeerList(),
+		HTTPClient:      &http.Client{Timeout: peerRequestTimeout},
+		vetoedCustomers: make(map[string]time.Time),
+	}
+}
+
+func (n *Node) httpClient() *http.Client {
+	if n.HTTPClient != nil {
+		return n.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// GetAuditEvents implements ClusterInterface: it queries this node's own
+// AuditLogger, asks every peer for theirs, and returns the union sorted
+// by Timestamp. One unreachable peer logs a warning and is otherwise
+// skipped rather than failing the whole query.
+func (n *Node) GetAuditEvents(filter AuditFilter) ([]compliance.AuditEvent, error) {
+	events, err := n.AuditLogger.QueryEvents(filter.CustomerID, filter.Since, filter.Until)
+	if err != nil {
+		return nil, fmt.Errorf("cluster: local audit query: %w", err)
+	}
+
+	for _, peer := range n.Peers.URLs() {
+		peerEvents, err := n.fetchPeerAudits(peer, filter)
+		if err != nil {
+			log.Printf("⚠️ cluster: GetAuditEvents from peer %s failed: %v", peer, err)
+			continue
+		}
+		events = append(events, peerEvents...)
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+	return events, nil
+}
+
+func (n *Node) fetchPeerAudits(peer string, filter AuditFilter) ([]compliance.AuditEvent, error) {
+	q := url.Values{}
+	if filter.CustomerID != "" {
+		q.Set("customer_id", filter.CustomerID)
+	}
+	if !filter.Since.IsZero() {
+		q.Set("since", filter.Since.Format(time.RFC3339))
+	}
+	if !filter.Until.IsZero() {
+		q.Set("until", filter.Until.Format(time.RFC3339))
+	}
+
+	resp, err := n.httpClient().Get(peer + auditsPath + "?" + q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("peer returned %s", resp.Status)
+	}
+
+	var events []compliance.AuditEvent
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("decode peer response: %w", err)
+	}
+	return events, nil
+}
+
+// GetBudgetStats implements ClusterInterface: it reports this node's own
+// BudgetManagers (one WindowStats summed per platform) under its own ID,
+// plus every peer's, keyed by the peer's own reported ID.
+func (n *Node) GetBudgetStats() (map[string]shield.WindowStats, error) {
+	stats := map[string]shield.WindowStats{n.ID: n.localBudgetStats()}
+
+	for _, peer := range n.Peers.URLs() {
+		peerID, peerStats, err := n.fetchPeerBudgetStats(peer)
+		if err != nil {
+			log.Printf("⚠️ cluster: GetBudgetStats from peer %s failed: %v", peer, err)
+			continue
+		}
+		stats[peerID] = peerStats
+	}
+	return stats, nil
+}
+
+// localBudgetStats sums every platform's WindowStats into one, so
+// GetBudgetStats reports a single figure per node rather than one per
+// platform - platform-level detail is still available locally via each
+// BudgetManager directly.
+func (n *Node) localBudgetStats() shield.WindowStats {
+	var combined shield.WindowStats
+	for _, bm := range n.BudgetManagers {
+		s := bm.GetStats()
+		combined.CurrentSpend += s.CurrentSpend
+		combined.MaxBudget += s.MaxBudget
+		combined.RemainingBudget += s.RemainingBudget
+		combined.RecordCount += s.RecordCount
+		if combined.OldestRecord.IsZero() || (!s.OldestRecord.IsZero() && s.OldestRecord.Before(combined.OldestRecord)) {
+			combined.OldestRecord = s.OldestRecord
+		}
+	}
+	return combined
+}
+
+func (n *Node) fetchPeerBudgetStats(peer string) (string, shield.WindowStats, error) {
+	resp, err := n.httpClient().Get(peer + budgetStatsPath)
+	if err != nil {
+		return "", shield.WindowStats{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", shield.WindowStats{}, fmt.Errorf("peer returned %s", resp.Status)
+	}
+
+	var body struct {
+		NodeID string             `json:"node_id"`
+		Stats  shield.WindowStats `json:"stats"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", shield.WindowStats{}, fmt.Errorf("decode peer response: %w", err)
+	}
+	return body.NodeID, body.Stats, nil
+}
+
+// BroadcastBudgetVeto implements ClusterInterface: it records customerID
+// as vetoed locally, invokes OnVeto, then best-effort pushes the veto to
+// every peer. A peer that can't be reached is logged and skipped - the
+// local veto has already taken effect, and the next Heartbeat-driven
+// retry of a bid will be blocked on that node's own budget check anyway.
+func (n *Node) BroadcastBudgetVeto(customerID string) error {
+	if customerID == "" {
+		return fmt.Errorf("cluster: BroadcastBudgetVeto requires a non-empty customerID")
+	}
+	n.recordVetoLocally(customerID)
+
+	for _, peer := range n.Peers.URLs() {
+		if err := n.pushVetoToPeer(peer, customerID); err != nil {
+			log.Printf("⚠️ cluster: BroadcastBudgetVeto to peer %s failed: %v", peer, err)
+		}
+	}
+	return nil
+}
+
+func (n *Node) recordVetoLocally(customerID string) {
+	n.mu.Lock()
+	n.vetoedCustomers[customerID] = time.Now()
+	n.mu.Unlock()
+
+	for _, handler := range n.OnVeto {
+		if handler != nil {
+			handler(customerID)
+		}
+	}
+}
+
+// IsVetoed reports whether customerID has been vetoed on this node,
+// whether from a local BroadcastBudgetVeto call or a peer's push.
+func (n *Node) IsVetoed(customerID string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, vetoed := n.vetoedCustomers[customerID]
+	return vetoed
+}
+
+func (n *Node) pushVetoToPeer(peer, customerID string) error {
+	body, _ := json.Marshal(map[string]string{"customer_id": customerID})
+	req, err := http.NewRequest(http.MethodPost, peer+vetoPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("peer returned %s", resp.Status)
+	}
+	return nil
+}
+
+// parseTimeParam parses an RFC3339 query parameter, returning the zero
+// Time (an open bound) for an empty string.
+func parseTimeParam(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
@@ -0,0 +1,110 @@
+package cluster
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// PeerList is a node's view of the rest of the fleet: a set of base URLs
+// it fans queries out to. Membership spreads the way a gossip protocol's
+// anti-entropy pass does, just over plain HTTP+JSON instead of a
+// dedicated wire protocol: each heartbeat a node asks one peer for its
+// peer list and merges in anything it hadn't heard of yet, so a new node
+// only needs one seed peer to eventually learn about the whole fleet.
+type PeerList struct {
+	mu    sync.RWMutex
+	peers map[string]struct{}
+}
+
+// NewPeerList creates a PeerList seeded with the given peer base URLs
+// (e.g. "https://node-2.kiki.internal:9090").
+func NewPeerList(seeds ...string) *PeerList {
+	p := &PeerList{peers: make(map[string]struct{}, len(seeds))}
+	for _, s := range seeds {
+		p.peers[s] = struct{}{}
+	}
+	return p
+}
+
+// Add registers url as a known peer. Safe to call with a URL already
+// known; it's a no-op.
+func (p *PeerList) Add(url string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.peers[url] = struct{}{}
+}
+
+// Remove drops url from the known peers, e.g. after repeated heartbeat
+// failures convince a node it's left the fleet.
+func (p *PeerList) Remove(url string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.peers, url)
+}
+
+// URLs returns a snapshot of every known peer's base URL.
+func (p *PeerList) URLs() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	urls := make([]string, 0, len(p.peers))
+	for u := range p.peers {
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+// Heartbeat polls every known peer's /cluster/peers endpoint every
+// interval, merging in any peer URL it hadn't seen before. It blocks the
+// calling goroutine - like credential.MutualTLSProvider.WatchForRotation,
+// callers invoke it with `go` - and returns once stop is closed.
+func (p *PeerList) Heartbeat(httpClient *http.Client, interval time.Duration, stop <-chan struct{}) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			for _, url := range p.URLs() {
+				p.mergeFrom(httpClient, url)
+			}
+		}
+	}
+}
+
+// mergeFrom fetches url's known peers and adds any this PeerList doesn't
+// already have. A failure to reach url is logged and otherwise ignored -
+// one unreachable peer shouldn't stop the heartbeat from reaching the
+// rest of the fleet.
+func (p *PeerList) mergeFrom(httpClient *http.Client, url string) {
+	resp, err := httpClient.Get(url + peersPath)
+	if err != nil {
+		log.Printf("⚠️ cluster: heartbeat to %s failed: %v", url, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("⚠️ cluster: heartbeat to %s returned %s", url, resp.Status)
+		return
+	}
+
+	var peerURLs []string
+	if err := json.NewDecoder(resp.Body).Decode(&peerURLs); err != nil {
+		log.Printf("⚠️ cluster: decoding peer list from %s: %v", url, err)
+		return
+	}
+
+	p.Add(url)
+	for _, u := range peerURLs {
+		p.Add(u)
+	}
+}
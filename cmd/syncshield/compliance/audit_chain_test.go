@@ -0,0 +1,133 @@
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLogger(t *testing.T) *GDPRAuditLogger {
+	t.Helper()
+	dir := t.TempDir()
+	logger, err := NewGDPRAuditLogger(
+		filepath.Join(dir, "audit.csv"),
+		filepath.Join(dir, "audit.json"),
+		90,
+	)
+	if err != nil {
+		t.Fatalf("NewGDPRAuditLogger failed: %v", err)
+	}
+	t.Cleanup(func() { logger.Close() })
+	return logger
+}
+
+func TestGDPRAuditLogger_ChainsHashesAcrossEvents(t *testing.T) {
+	logger := newTestLogger(t)
+
+	if err := logger.LogSecurityEvent("security_scan", "user-1", "scan", "SUCCESS", "routine", nil); err != nil {
+		t.Fatalf("LogSecurityEvent failed: %v", err)
+	}
+	if err := logger.LogDataAccess("user-1", "cust-1", []string{"email"}, "support"); err != nil {
+		t.Fatalf("LogDataAccess failed: %v", err)
+	}
+
+	tamper, err := logger.VerifyLogIntegrity(time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("VerifyLogIntegrity failed: %v", err)
+	}
+	if tamper != nil {
+		t.Fatalf("expected no tamper, got %+v", tamper)
+	}
+}
+
+func TestGDPRAuditLogger_VerifyLogIntegrityDetectsTamper(t *testing.T) {
+	logger := newTestLogger(t)
+
+	if err := logger.LogSecurityEvent("security_scan", "user-1", "scan", "SUCCESS", "routine", nil); err != nil {
+		t.Fatalf("LogSecurityEvent failed: %v", err)
+	}
+	logger.csvWriter.Flush()
+
+	raw, err := os.ReadFile(logger.jsonPath)
+	if err != nil {
+		t.Fatalf("failed to read JSON audit log: %v", err)
+	}
+	// Flip the outcome so the persisted record no longer matches its own hash,
+	// without corrupting the JSON syntax itself.
+	tampered := []byte(strings.Replace(string(raw), `"SUCCESS"`, `"DENIED"`, 1))
+	if err := os.WriteFile(logger.jsonPath, tampered, 0600); err != nil {
+		t.Fatalf("failed to write tampered log: %v", err)
+	}
+
+	tamper, err := logger.VerifyLogIntegrity(time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("VerifyLogIntegrity failed: %v", err)
+	}
+	if tamper == nil {
+		t.Fatal("expected tamper to be detected, got nil")
+	}
+}
+
+func TestGDPRAuditLogger_VerifyLogIntegrityMidChainRangeOnUntamperedLog(t *testing.T) {
+	logger := newTestLogger(t)
+
+	for i := 0; i < 10; i++ {
+		if err := logger.LogSecurityEvent("security_scan", "user-1", "scan", "SUCCESS", "routine", nil); err != nil {
+			t.Fatalf("LogSecurityEvent #%d failed: %v", i, err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	events, err := logger.readPersistedEvents()
+	if err != nil {
+		t.Fatalf("readPersistedEvents failed: %v", err)
+	}
+	if len(events) != 10 {
+		t.Fatalf("expected 10 persisted events, got %d", len(events))
+	}
+
+	// Verifying only the second half of an untampered chain must not report
+	// a spurious tamper at the first event in range: that event's real
+	// PrevHash points at its true (out-of-range) predecessor, not "".
+	midpoint := events[5].Timestamp
+	tamper, err := logger.VerifyLogIntegrity(midpoint, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("VerifyLogIntegrity failed: %v", err)
+	}
+	if tamper != nil {
+		t.Fatalf("expected no tamper verifying an untampered mid-chain range, got %+v", tamper)
+	}
+}
+
+func TestGDPRAuditLogger_GenerateInclusionProof(t *testing.T) {
+	logger := newTestLogger(t)
+	logger.chain.batchSize = 2
+
+	if err := logger.LogSecurityEvent("security_scan", "user-1", "scan", "SUCCESS", "routine", nil); err != nil {
+		t.Fatalf("LogSecurityEvent failed: %v", err)
+	}
+	if err := logger.LogDataAccess("user-1", "cust-1", []string{"email"}, "support"); err != nil {
+		t.Fatalf("LogDataAccess failed: %v", err)
+	}
+
+	events, err := logger.readPersistedEvents()
+	if err != nil {
+		t.Fatalf("readPersistedEvents failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 persisted events, got %d", len(events))
+	}
+
+	proof, err := logger.GenerateInclusionProof(events[0].EventID)
+	if err != nil {
+		t.Fatalf("GenerateInclusionProof failed: %v", err)
+	}
+	if proof.TreeHead.Size != 2 {
+		t.Errorf("expected tree head size 2, got %d", proof.TreeHead.Size)
+	}
+	if len(proof.AuditPath) == 0 {
+		t.Error("expected a non-empty audit path")
+	}
+}
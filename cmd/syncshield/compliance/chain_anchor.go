@@ -0,0 +1,56 @@
+package compliance
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// loadOrCreateChainKey loads the Ed25519 private key persisted at
+// "<anchorBase>.key", generating and persisting a fresh keypair (plus its
+// public half at "<anchorBase>.pub", which VerifyChain reads) the first
+// time a given anchorBase is used. The key must outlive any single
+// process: an anchor signed before a restart can only be verified against
+// the same key afterward.
+func loadOrCreateChainKey(anchorBase string) (ed25519.PrivateKey, error) {
+	keyPath := anchorBase + ".key"
+
+	if raw, err := os.ReadFile(keyPath); err == nil {
+		if len(raw) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("audit chain key at %s has an unexpected length (%d bytes)", keyPath, len(raw))
+		}
+		return ed25519.PrivateKey(raw), nil
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read audit chain key: %w", err)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate audit chain signing key: %w", err)
+	}
+	if err := os.WriteFile(keyPath, priv, 0600); err != nil {
+		return nil, fmt.Errorf("failed to persist audit chain key: %w", err)
+	}
+	if err := os.WriteFile(anchorBase+".pub", []byte(fmt.Sprintf("%x", pub)), 0644); err != nil {
+		return nil, fmt.Errorf("failed to persist audit chain public key: %w", err)
+	}
+	return priv, nil
+}
+
+// persistAnchor writes head as the JSON sidecar file
+// "<anchorBase>.anchor.NNNNN", where seq is the zero-based index of this
+// checkpoint among anchorBase's history. Zero-padding keeps a directory
+// listing in chronological order.
+func persistAnchor(anchorBase string, seq int, head SignedTreeHead) error {
+	data, err := json.MarshalIndent(head, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal anchor: %w", err)
+	}
+	path := fmt.Sprintf("%s.anchor.%05d", anchorBase, seq)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write anchor %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package compliance
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// VerificationError describes a single defect VerifyChain found while
+// rewalking a hash-chained audit log. VerifyChain collects every defect it
+// finds rather than stopping at the first, so an auditor sees the full
+// extent of any tampering in one pass.
+type VerificationError struct {
+	Kind    string `json:"kind"` // hash_mismatch, chain_gap, anchor_root_mismatch, anchor_signature_invalid, anchor_coverage_gap
+	EventID string `json:"event_id,omitempty"`
+	Index   int    `json:"index,omitempty"`
+	Detail  string `json:"detail"`
+}
+
+// VerifyChain rewalks the JSON audit log at jsonPath - recomputing every
+// event's PrevHash/Hash and validating every "<jsonPath>.anchor.NNNNN"
+// sidecar's Merkle root and Ed25519 signature against the public key
+// persisted at "<jsonPath>.pub" - and reports every gap or mismatch it
+// finds. A nil/empty result means the log is intact; a non-nil function
+// error means VerifyChain itself couldn't complete (missing files, I/O
+// failure), as distinct from the log being tampered with.
+func VerifyChain(jsonPath string) ([]VerificationError, error) {
+	events, err := readPersistedEventsAt(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+	indexByEventID := make(map[string]int, len(events))
+	for i, e := range events {
+		indexByEventID[e.EventID] = i
+	}
+
+	var problems []VerificationError
+
+	prevHash := ""
+	for i, event := range events {
+		if event.PrevHash != prevHash {
+			problems = append(problems, VerificationError{
+				Kind:    "chain_gap",
+				EventID: event.EventID,
+				Index:   i,
+				Detail:  fmt.Sprintf("prev_hash %q does not match the preceding event's hash %q", event.PrevHash, prevHash),
+			})
+		}
+
+		recomputed, err := hashAuditEvent(&event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to recompute hash for event %q: %w", event.EventID, err)
+		}
+		if recomputed != event.Hash {
+			problems = append(problems, VerificationError{
+				Kind:    "hash_mismatch",
+				EventID: event.EventID,
+				Index:   i,
+				Detail:  fmt.Sprintf("recomputed hash %q does not match persisted hash %q", recomputed, event.Hash),
+			})
+		}
+
+		prevHash = event.Hash
+	}
+
+	publicKey, err := readChainPublicKey(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	anchors, err := readAnchors(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, verifyAnchors(anchors, publicKey, events, indexByEventID)...)
+
+	return problems, nil
+}
+
+// readChainPublicKey reads the Ed25519 public key persisted at
+// "<jsonPath>.pub" by loadOrCreateChainKey.
+func readChainPublicKey(jsonPath string) (ed25519.PublicKey, error) {
+	raw, err := os.ReadFile(jsonPath + ".pub")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit chain public key: %w", err)
+	}
+	decoded, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audit chain public key: %w", err)
+	}
+	return ed25519.PublicKey(decoded), nil
+}
+
+// readAnchors reads back every "<jsonPath>.anchor.NNNNN" sidecar, in the
+// numeric order persistAnchor wrote them.
+func readAnchors(jsonPath string) ([]SignedTreeHead, error) {
+	matches, err := filepath.Glob(jsonPath + ".anchor.*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list anchor sidecars: %w", err)
+	}
+	sort.Strings(matches)
+
+	anchors := make([]SignedTreeHead, 0, len(matches))
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read anchor %s: %w", path, err)
+		}
+		var head SignedTreeHead
+		if err := json.Unmarshal(data, &head); err != nil {
+			return nil, fmt.Errorf("failed to parse anchor %s: %w", path, err)
+		}
+		anchors = append(anchors, head)
+	}
+	return anchors, nil
+}
+
+// verifyAnchors checks each anchor's signature and recomputes its Merkle
+// root over the events it claims to cover, and flags any gap between one
+// anchor's EndEventID and the next anchor's StartEventID.
+func verifyAnchors(anchors []SignedTreeHead, publicKey ed25519.PublicKey, events []AuditEvent, indexByEventID map[string]int) []VerificationError {
+	var problems []VerificationError
+
+	prevEnd := -1
+	for _, head := range anchors {
+		if !ed25519.Verify(publicKey, treeHeadSigningBytes(head), mustHexDecode(head.Signature)) {
+			problems = append(problems, VerificationError{
+				Kind:    "anchor_signature_invalid",
+				EventID: head.EndEventID,
+				Detail:  fmt.Sprintf("signature does not verify for anchor covering %s..%s", head.StartEventID, head.EndEventID),
+			})
+			continue
+		}
+
+		start, startOK := indexByEventID[head.StartEventID]
+		end, endOK := indexByEventID[head.EndEventID]
+		if !startOK || !endOK || end < start {
+			problems = append(problems, VerificationError{
+				Kind:   "anchor_coverage_gap",
+				Detail: fmt.Sprintf("anchor covering %s..%s references events missing from the log", head.StartEventID, head.EndEventID),
+			})
+			continue
+		}
+		if start != prevEnd+1 {
+			problems = append(problems, VerificationError{
+				Kind:    "anchor_coverage_gap",
+				EventID: head.StartEventID,
+				Index:   start,
+				Detail:  fmt.Sprintf("anchor starting at index %d does not pick up immediately after the previous anchor's last covered index %d", start, prevEnd),
+			})
+		}
+		prevEnd = end
+
+		leafHashes := make([]string, end-start+1)
+		for i, e := range events[start : end+1] {
+			leafHashes[i] = e.Hash
+		}
+		if root := merkleRoot(leafHashes); root != head.RootHash {
+			problems = append(problems, VerificationError{
+				Kind:    "anchor_root_mismatch",
+				EventID: head.EndEventID,
+				Detail:  fmt.Sprintf("recomputed Merkle root %q does not match anchor's signed root %q", root, head.RootHash),
+			})
+		}
+	}
+
+	return problems
+}
+
+// mustHexDecode decodes a hex string, returning nil on malformed input so
+// a corrupted signature field fails ed25519.Verify rather than panicking.
+func mustHexDecode(s string) []byte {
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return nil
+	}
+	return decoded
+}
@@ -0,0 +1,218 @@
+package compliance
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyChain_CleanLogHasNoProblems(t *testing.T) {
+	logger := newTestLogger(t)
+	logger.chain.batchSize = 2
+
+	if err := logger.LogSecurityEvent("security_scan", "user-1", "scan", "SUCCESS", "routine", nil); err != nil {
+		t.Fatalf("LogSecurityEvent failed: %v", err)
+	}
+	if err := logger.LogDataAccess("user-1", "cust-1", []string{"email"}, "support"); err != nil {
+		t.Fatalf("LogDataAccess failed: %v", err)
+	}
+
+	problems, err := VerifyChain(logger.jsonPath)
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected no problems, got %+v", problems)
+	}
+}
+
+func TestVerifyChain_DetectsTamperedEvent(t *testing.T) {
+	logger := newTestLogger(t)
+	logger.chain.batchSize = 2
+
+	if err := logger.LogSecurityEvent("security_scan", "user-1", "scan", "SUCCESS", "routine", nil); err != nil {
+		t.Fatalf("LogSecurityEvent failed: %v", err)
+	}
+	if err := logger.LogDataAccess("user-1", "cust-1", []string{"email"}, "support"); err != nil {
+		t.Fatalf("LogDataAccess failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(logger.jsonPath)
+	if err != nil {
+		t.Fatalf("failed to read JSON audit log: %v", err)
+	}
+	tampered := []byte(strings.Replace(string(raw), `"SUCCESS"`, `"DENIED"`, 1))
+	if err := os.WriteFile(logger.jsonPath, tampered, 0600); err != nil {
+		t.Fatalf("failed to write tampered log: %v", err)
+	}
+
+	problems, err := VerifyChain(logger.jsonPath)
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	if len(problems) == 0 {
+		t.Fatal("expected problems to be reported, got none")
+	}
+	found := false
+	for _, p := range problems {
+		if p.Kind == "hash_mismatch" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a hash_mismatch problem, got %+v", problems)
+	}
+}
+
+func TestVerifyChain_DetectsForgedAnchorRoot(t *testing.T) {
+	logger := newTestLogger(t)
+	logger.chain.batchSize = 2
+
+	if err := logger.LogSecurityEvent("security_scan", "user-1", "scan", "SUCCESS", "routine", nil); err != nil {
+		t.Fatalf("LogSecurityEvent failed: %v", err)
+	}
+	if err := logger.LogDataAccess("user-1", "cust-1", []string{"email"}, "support"); err != nil {
+		t.Fatalf("LogDataAccess failed: %v", err)
+	}
+
+	anchorPath := logger.jsonPath + ".anchor.00000"
+	raw, err := os.ReadFile(anchorPath)
+	if err != nil {
+		t.Fatalf("failed to read anchor sidecar: %v", err)
+	}
+	var head SignedTreeHead
+	if err := json.Unmarshal(raw, &head); err != nil {
+		t.Fatalf("failed to parse anchor sidecar: %v", err)
+	}
+	head.RootHash = strings.Repeat("f", len(head.RootHash))
+	forged, err := json.MarshalIndent(head, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal forged anchor: %v", err)
+	}
+	if err := os.WriteFile(anchorPath, forged, 0644); err != nil {
+		t.Fatalf("failed to write forged anchor: %v", err)
+	}
+
+	problems, err := VerifyChain(logger.jsonPath)
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	found := false
+	for _, p := range problems {
+		if p.Kind == "anchor_signature_invalid" || p.Kind == "anchor_root_mismatch" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected an anchor_signature_invalid or anchor_root_mismatch problem, got %+v", problems)
+	}
+}
+
+func TestVerifyChain_DetectsGapBetweenAnchors(t *testing.T) {
+	logger := newTestLogger(t)
+	logger.chain.batchSize = 2
+
+	for i := 0; i < 4; i++ {
+		if err := logger.LogSecurityEvent("security_scan", "user-1", "scan", "SUCCESS", "routine", nil); err != nil {
+			t.Fatalf("LogSecurityEvent failed: %v", err)
+		}
+	}
+
+	secondAnchorPath := logger.jsonPath + ".anchor.00001"
+	raw, err := os.ReadFile(secondAnchorPath)
+	if err != nil {
+		t.Fatalf("failed to read second anchor sidecar: %v", err)
+	}
+	var head SignedTreeHead
+	if err := json.Unmarshal(raw, &head); err != nil {
+		t.Fatalf("failed to parse second anchor sidecar: %v", err)
+	}
+	head.StartEventID = head.EndEventID
+	head.Signature = ""
+	forged, err := json.MarshalIndent(head, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal forged anchor: %v", err)
+	}
+	if err := os.WriteFile(secondAnchorPath, forged, 0644); err != nil {
+		t.Fatalf("failed to write forged anchor: %v", err)
+	}
+
+	problems, err := VerifyChain(logger.jsonPath)
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	found := false
+	for _, p := range problems {
+		if p.Kind == "anchor_coverage_gap" || p.Kind == "anchor_signature_invalid" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected an anchor_coverage_gap or anchor_signature_invalid problem, got %+v", problems)
+	}
+}
+
+func TestNewAuditChain_RestoresStateAcrossRestart(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "audit.csv")
+	jsonPath := filepath.Join(dir, "audit.json")
+
+	first, err := NewGDPRAuditLogger(csvPath, jsonPath, 90)
+	if err != nil {
+		t.Fatalf("NewGDPRAuditLogger failed: %v", err)
+	}
+	first.chain.batchSize = 2
+	if err := first.LogSecurityEvent("security_scan", "user-1", "scan", "SUCCESS", "routine", nil); err != nil {
+		t.Fatalf("LogSecurityEvent failed: %v", err)
+	}
+	if err := first.LogDataAccess("user-1", "cust-1", []string{"email"}, "support"); err != nil {
+		t.Fatalf("LogDataAccess failed: %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	second, err := NewGDPRAuditLogger(csvPath, jsonPath, 90)
+	if err != nil {
+		t.Fatalf("NewGDPRAuditLogger (restart) failed: %v", err)
+	}
+	defer second.Close()
+
+	if err := second.LogSecurityEvent("security_scan", "user-1", "scan", "SUCCESS", "routine", nil); err != nil {
+		t.Fatalf("LogSecurityEvent after restart failed: %v", err)
+	}
+
+	problems, err := VerifyChain(jsonPath)
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected no problems across a restart, got %+v", problems)
+	}
+}
+
+func TestVerifyChain_MissingPublicKeyReturnsError(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := NewGDPRAuditLogger(
+		filepath.Join(dir, "audit.csv"),
+		filepath.Join(dir, "audit.json"),
+		90,
+	)
+	if err != nil {
+		t.Fatalf("NewGDPRAuditLogger failed: %v", err)
+	}
+	if err := logger.LogSecurityEvent("security_scan", "user-1", "scan", "SUCCESS", "routine", nil); err != nil {
+		t.Fatalf("LogSecurityEvent failed: %v", err)
+	}
+	logger.Close()
+
+	if err := os.Remove(logger.jsonPath + ".pub"); err != nil {
+		t.Fatalf("failed to remove public key: %v", err)
+	}
+
+	if _, err := VerifyChain(logger.jsonPath); err == nil {
+		t.Fatal("expected an error when the public key is missing, got nil")
+	}
+}
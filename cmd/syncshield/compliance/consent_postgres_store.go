@@ -0,0 +1,430 @@
+package compliance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq" // PostgreSQL driver, already used by crm.PostgreSQLConnector and auditsink.PostgresSink
+)
+
+// consentHistorySchema mirrors auditsink's migrate-on-construction
+// pattern: no separate migration step is needed before SyncShield can
+// start. Rows are append-only - a grant and a later revocation are two
+// separate rows keyed by (customer_id, consent_type, granted_at) - so
+// revoking consent never loses the record that it was once granted.
+const consentHistorySchema = `
+CREATE TABLE IF NOT EXISTS consent_history (
+	id           BIGSERIAL PRIMARY KEY,
+	customer_id  TEXT NOT NULL,
+	consent_type TEXT NOT NULL,
+	status       TEXT NOT NULL,
+	granted_at   TIMESTAMPTZ NOT NULL,
+	revoked_at   TIMESTAMPTZ,
+	expires_at   TIMESTAMPTZ,
+	ip_address   TEXT NOT NULL,
+	user_agent   TEXT NOT NULL,
+	version      TEXT NOT NULL,
+	legal_basis  TEXT NOT NULL,
+	UNIQUE (customer_id, consent_type, granted_at)
+)`
+
+// PostgresConsentStore persists consent history in the same Postgres
+// database as auditsink.PostgresSink - pass it the same AUDIT_POSTGRES_DSN
+// so both land in one durable store rather than introducing a second one.
+type PostgresConsentStore struct {
+	db *sql.DB
+}
+
+// NewPostgresConsentStore opens dsn and ensures the consent_history table
+// exists.
+func NewPostgresConsentStore(dsn string) (*PostgresConsentStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: opening postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: pinging postgres: %w", err)
+	}
+	if _, err := db.Exec(consentHistorySchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: migrating consent_history: %w", err)
+	}
+	return &PostgresConsentStore{db: db}, nil
+}
+
+// PutConsent implements ConsentStore.
+func (s *PostgresConsentStore) PutConsent(ctx context.Context, consent UserConsent) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO consent_history
+			(customer_id, consent_type, status, granted_at, revoked_at, expires_at, ip_address, user_agent, version, legal_basis)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		consent.CustomerID, consent.ConsentType, consent.Status, consent.GrantedAt,
+		consent.RevokedAt, consent.ExpiresAt, consent.IPAddress, consent.UserAgent,
+		consent.Version, consent.LegalBasis,
+	)
+	if err != nil {
+		return fmt.Errorf("compliance: appending consent for %s: %w", consent.CustomerID, err)
+	}
+	return nil
+}
+
+// GetLatestConsent implements ConsentStore.
+func (s *PostgresConsentStore) GetLatestConsent(ctx context.Context, customerID string, consentType ConsentType) (*UserConsent, error) {
+	var c UserConsent
+	err := s.db.QueryRowContext(ctx, `
+		SELECT customer_id, consent_type, status, granted_at, revoked_at, expires_at, ip_address, user_agent, version, legal_basis
+		FROM consent_history
+		WHERE customer_id = $1 AND consent_type = $2
+		ORDER BY granted_at DESC
+		LIMIT 1`,
+		customerID, consentType,
+	).Scan(&c.CustomerID, &c.ConsentType, &c.Status, &c.GrantedAt, &c.RevokedAt, &c.ExpiresAt, &c.IPAddress, &c.UserAgent, &c.Version, &c.LegalBasis)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compliance: reading latest consent for %s: %w", customerID, err)
+	}
+	return &c, nil
+}
+
+// GetConsentHistory implements ConsentStore.
+func (s *PostgresConsentStore) GetConsentHistory(ctx context.Context, customerID string) ([]UserConsent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT customer_id, consent_type, status, granted_at, revoked_at, expires_at, ip_address, user_agent, version, legal_basis
+		FROM consent_history
+		WHERE customer_id = $1
+		ORDER BY granted_at ASC`,
+		customerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: reading consent history for %s: %w", customerID, err)
+	}
+	defer rows.Close()
+
+	var history []UserConsent
+	for rows.Next() {
+		var c UserConsent
+		if err := rows.Scan(&c.CustomerID, &c.ConsentType, &c.Status, &c.GrantedAt, &c.RevokedAt, &c.ExpiresAt, &c.IPAddress, &c.UserAgent, &c.Version, &c.LegalBasis); err != nil {
+			return nil, fmt.Errorf("compliance: scanning consent history for %s: %w", customerID, err)
+		}
+		history = append(history, c)
+	}
+	return history, rows.Err()
+}
+
+// Close closes the underlying database handle.
+func (s *PostgresConsentStore) Close() error {
+	return s.db.Close()
+}
+
+// dataSubjectRequestsSchema mirrors consentHistorySchema's
+// migrate-on-construction pattern.
+const dataSubjectRequestsSchema = `
+CREATE TABLE IF NOT EXISTS data_subject_requests (
+	request_id   TEXT PRIMARY KEY,
+	customer_id  TEXT NOT NULL,
+	request_type TEXT NOT NULL,
+	status       TEXT NOT NULL,
+	requested_at TIMESTAMPTZ NOT NULL,
+	deadline_at  TIMESTAMPTZ NOT NULL DEFAULT (NOW() + INTERVAL '30 days'),
+	completed_at TIMESTAMPTZ,
+	requested_by TEXT NOT NULL,
+	processed_by TEXT NOT NULL DEFAULT '',
+	notes        TEXT NOT NULL DEFAULT ''
+)`
+
+// PostgresDSRStore persists data subject requests in the same Postgres
+// database as PostgresConsentStore.
+type PostgresDSRStore struct {
+	db *sql.DB
+}
+
+// NewPostgresDSRStore opens dsn and ensures the data_subject_requests
+// table exists.
+func NewPostgresDSRStore(dsn string) (*PostgresDSRStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: opening postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: pinging postgres: %w", err)
+	}
+	if _, err := db.Exec(dataSubjectRequestsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: migrating data_subject_requests: %w", err)
+	}
+	return &PostgresDSRStore{db: db}, nil
+}
+
+// PutRequest implements DSRStore.
+func (s *PostgresDSRStore) PutRequest(ctx context.Context, request DataSubjectRequest) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO data_subject_requests
+			(request_id, customer_id, request_type, status, requested_at, deadline_at, completed_at, requested_by, processed_by, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		request.RequestID, request.CustomerID, request.RequestType, request.Status,
+		request.RequestedAt, request.DeadlineAt, request.CompletedAt, request.RequestedBy, request.ProcessedBy, request.Notes,
+	)
+	if err != nil {
+		return fmt.Errorf("compliance: inserting DSR %s: %w", request.RequestID, err)
+	}
+	return nil
+}
+
+// GetRequest implements DSRStore.
+func (s *PostgresDSRStore) GetRequest(ctx context.Context, requestID string) (*DataSubjectRequest, error) {
+	var r DataSubjectRequest
+	err := s.db.QueryRowContext(ctx, `
+		SELECT request_id, customer_id, request_type, status, requested_at, deadline_at, completed_at, requested_by, processed_by, notes
+		FROM data_subject_requests
+		WHERE request_id = $1`,
+		requestID,
+	).Scan(&r.RequestID, &r.CustomerID, &r.RequestType, &r.Status, &r.RequestedAt, &r.DeadlineAt, &r.CompletedAt, &r.RequestedBy, &r.ProcessedBy, &r.Notes)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compliance: reading DSR %s: %w", requestID, err)
+	}
+	return &r, nil
+}
+
+// ListPendingDSRs implements DSRStore.
+func (s *PostgresDSRStore) ListPendingDSRs(ctx context.Context) ([]DataSubjectRequest, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT request_id, customer_id, request_type, status, requested_at, deadline_at, completed_at, requested_by, processed_by, notes
+		FROM data_subject_requests
+		WHERE status = 'PENDING'
+		ORDER BY requested_at ASC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: listing pending DSRs: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []DataSubjectRequest
+	for rows.Next() {
+		var r DataSubjectRequest
+		if err := rows.Scan(&r.RequestID, &r.CustomerID, &r.RequestType, &r.Status, &r.RequestedAt, &r.DeadlineAt, &r.CompletedAt, &r.RequestedBy, &r.ProcessedBy, &r.Notes); err != nil {
+			return nil, fmt.Errorf("compliance: scanning pending DSRs: %w", err)
+		}
+		pending = append(pending, r)
+	}
+	return pending, rows.Err()
+}
+
+// MarkDSRCompleted implements DSRStore.
+func (s *PostgresDSRStore) MarkDSRCompleted(ctx context.Context, request DataSubjectRequest) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE data_subject_requests
+		SET status = $2, completed_at = $3, processed_by = $4, notes = $5
+		WHERE request_id = $1`,
+		request.RequestID, request.Status, request.CompletedAt, request.ProcessedBy, request.Notes,
+	)
+	if err != nil {
+		return fmt.Errorf("compliance: completing DSR %s: %w", request.RequestID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("request not found: %s", request.RequestID)
+	}
+	return nil
+}
+
+// Close closes the underlying database handle.
+func (s *PostgresDSRStore) Close() error {
+	return s.db.Close()
+}
+
+// pendingConsentSchema mirrors consentHistorySchema's
+// migrate-on-construction pattern. Rows are deleted on confirmation or
+// expiry, unlike consent_history which is append-only - a pending
+// confirmation isn't itself part of the Article 7(1) proof record.
+const pendingConsentSchema = `
+CREATE TABLE IF NOT EXISTS pending_consents (
+	token_hash   TEXT PRIMARY KEY,
+	customer_id  TEXT NOT NULL,
+	consent_type TEXT NOT NULL,
+	channel      TEXT NOT NULL,
+	destination  TEXT NOT NULL,
+	legal_basis  TEXT NOT NULL,
+	created_at   TIMESTAMPTZ NOT NULL,
+	expires_at   TIMESTAMPTZ NOT NULL
+)`
+
+// PostgresPendingConsentStore persists double opt-in confirmations in the
+// same Postgres database as PostgresConsentStore.
+type PostgresPendingConsentStore struct {
+	db *sql.DB
+}
+
+// NewPostgresPendingConsentStore opens dsn and ensures the
+// pending_consents table exists.
+func NewPostgresPendingConsentStore(dsn string) (*PostgresPendingConsentStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: opening postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: pinging postgres: %w", err)
+	}
+	if _, err := db.Exec(pendingConsentSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: migrating pending_consents: %w", err)
+	}
+	return &PostgresPendingConsentStore{db: db}, nil
+}
+
+// CreatePending implements PendingConsentStore.
+func (s *PostgresPendingConsentStore) CreatePending(ctx context.Context, pending PendingConsent) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO pending_consents
+			(token_hash, customer_id, consent_type, channel, destination, legal_basis, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		pending.TokenHash, pending.CustomerID, pending.ConsentType, pending.Channel,
+		pending.Destination, pending.LegalBasis, pending.CreatedAt, pending.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("compliance: recording pending consent for %s: %w", pending.CustomerID, err)
+	}
+	return nil
+}
+
+// GetPending implements PendingConsentStore.
+func (s *PostgresPendingConsentStore) GetPending(ctx context.Context, tokenHash string) (*PendingConsent, error) {
+	var p PendingConsent
+	err := s.db.QueryRowContext(ctx, `
+		SELECT token_hash, customer_id, consent_type, channel, destination, legal_basis, created_at, expires_at
+		FROM pending_consents
+		WHERE token_hash = $1`,
+		tokenHash,
+	).Scan(&p.TokenHash, &p.CustomerID, &p.ConsentType, &p.Channel, &p.Destination, &p.LegalBasis, &p.CreatedAt, &p.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compliance: reading pending consent: %w", err)
+	}
+	return &p, nil
+}
+
+// DeletePending implements PendingConsentStore.
+func (s *PostgresPendingConsentStore) DeletePending(ctx context.Context, tokenHash string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM pending_consents WHERE token_hash = $1`, tokenHash)
+	if err != nil {
+		return fmt.Errorf("compliance: consuming pending consent: %w", err)
+	}
+	return nil
+}
+
+// ConsumePending implements PendingConsentStore.
+func (s *PostgresPendingConsentStore) ConsumePending(ctx context.Context, tokenHash string) (*PendingConsent, error) {
+	var p PendingConsent
+	err := s.db.QueryRowContext(ctx, `
+		DELETE FROM pending_consents
+		WHERE token_hash = $1
+		RETURNING token_hash, customer_id, consent_type, channel, destination, legal_basis, created_at, expires_at`,
+		tokenHash,
+	).Scan(&p.TokenHash, &p.CustomerID, &p.ConsentType, &p.Channel, &p.Destination, &p.LegalBasis, &p.CreatedAt, &p.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compliance: consuming pending consent: %w", err)
+	}
+	return &p, nil
+}
+
+// Close closes the underlying database handle.
+func (s *PostgresPendingConsentStore) Close() error {
+	return s.db.Close()
+}
+
+// dsrHandlerProgressSchema backs DSRProgressStore: one row per
+// (request_id, domain), upserted as a handler (re)runs so DSRProcessor
+// can resume a crashed run.
+const dsrHandlerProgressSchema = `
+CREATE TABLE IF NOT EXISTS dsr_handler_progress (
+	request_id    TEXT NOT NULL,
+	domain        TEXT NOT NULL,
+	status        TEXT NOT NULL,
+	bytes_handled INTEGER NOT NULL DEFAULT 0,
+	error         TEXT NOT NULL DEFAULT '',
+	updated_at    TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (request_id, domain)
+)`
+
+// PostgresDSRProgressStore persists DSRHandlerProgress rows in the same
+// Postgres database as PostgresDSRStore.
+type PostgresDSRProgressStore struct {
+	db *sql.DB
+}
+
+// NewPostgresDSRProgressStore opens dsn and ensures the
+// dsr_handler_progress table exists.
+func NewPostgresDSRProgressStore(dsn string) (*PostgresDSRProgressStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: opening postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: pinging postgres: %w", err)
+	}
+	if _, err := db.Exec(dsrHandlerProgressSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: migrating dsr_handler_progress: %w", err)
+	}
+	return &PostgresDSRProgressStore{db: db}, nil
+}
+
+// PutProgress implements DSRProgressStore.
+func (s *PostgresDSRProgressStore) PutProgress(ctx context.Context, progress DSRHandlerProgress) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO dsr_handler_progress (request_id, domain, status, bytes_handled, error, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (request_id, domain) DO UPDATE SET
+			status = EXCLUDED.status,
+			bytes_handled = EXCLUDED.bytes_handled,
+			error = EXCLUDED.error,
+			updated_at = EXCLUDED.updated_at`,
+		progress.RequestID, progress.Domain, progress.Status, progress.BytesHandled, progress.Error, progress.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("compliance: recording DSR progress for %s/%s: %w", progress.RequestID, progress.Domain, err)
+	}
+	return nil
+}
+
+// GetProgress implements DSRProgressStore.
+func (s *PostgresDSRProgressStore) GetProgress(ctx context.Context, requestID string) ([]DSRHandlerProgress, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT request_id, domain, status, bytes_handled, error, updated_at
+		FROM dsr_handler_progress
+		WHERE request_id = $1`,
+		requestID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: reading DSR progress for %s: %w", requestID, err)
+	}
+	defer rows.Close()
+
+	var progress []DSRHandlerProgress
+	for rows.Next() {
+		var p DSRHandlerProgress
+		if err := rows.Scan(&p.RequestID, &p.Domain, &p.Status, &p.BytesHandled, &p.Error, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("compliance: scanning DSR progress for %s: %w", requestID, err)
+		}
+		progress = append(progress, p)
+	}
+	return progress, rows.Err()
+}
+
+// Close closes the underlying database handle.
+func (s *PostgresDSRProgressStore) Close() error {
+	return s.db.Close()
+}
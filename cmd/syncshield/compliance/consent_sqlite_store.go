@@ -0,0 +1,407 @@
+package compliance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite" // pure-Go SQLite driver for local dev, no cgo required
+)
+
+// sqliteConsentHistorySchema is SQLite's equivalent of consentHistorySchema
+// - same append-only shape, SQLite column types.
+const sqliteConsentHistorySchema = `
+CREATE TABLE IF NOT EXISTS consent_history (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	customer_id  TEXT NOT NULL,
+	consent_type TEXT NOT NULL,
+	status       TEXT NOT NULL,
+	granted_at   DATETIME NOT NULL,
+	revoked_at   DATETIME,
+	expires_at   DATETIME,
+	ip_address   TEXT NOT NULL,
+	user_agent   TEXT NOT NULL,
+	version      TEXT NOT NULL,
+	legal_basis  TEXT NOT NULL,
+	UNIQUE (customer_id, consent_type, granted_at)
+)`
+
+// SQLiteConsentStore is the local-dev equivalent of PostgresConsentStore -
+// a single file at path, good enough to survive a restart without running
+// a Postgres instance.
+type SQLiteConsentStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteConsentStore opens (or creates) the SQLite database at path and
+// ensures the consent_history table exists.
+func NewSQLiteConsentStore(path string) (*SQLiteConsentStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: opening sqlite: %w", err)
+	}
+	if _, err := db.Exec(sqliteConsentHistorySchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: migrating consent_history: %w", err)
+	}
+	return &SQLiteConsentStore{db: db}, nil
+}
+
+// PutConsent implements ConsentStore.
+func (s *SQLiteConsentStore) PutConsent(ctx context.Context, consent UserConsent) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO consent_history
+			(customer_id, consent_type, status, granted_at, revoked_at, expires_at, ip_address, user_agent, version, legal_basis)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		consent.CustomerID, consent.ConsentType, consent.Status, consent.GrantedAt,
+		consent.RevokedAt, consent.ExpiresAt, consent.IPAddress, consent.UserAgent,
+		consent.Version, consent.LegalBasis,
+	)
+	if err != nil {
+		return fmt.Errorf("compliance: appending consent for %s: %w", consent.CustomerID, err)
+	}
+	return nil
+}
+
+// GetLatestConsent implements ConsentStore.
+func (s *SQLiteConsentStore) GetLatestConsent(ctx context.Context, customerID string, consentType ConsentType) (*UserConsent, error) {
+	var c UserConsent
+	err := s.db.QueryRowContext(ctx, `
+		SELECT customer_id, consent_type, status, granted_at, revoked_at, expires_at, ip_address, user_agent, version, legal_basis
+		FROM consent_history
+		WHERE customer_id = ? AND consent_type = ?
+		ORDER BY granted_at DESC
+		LIMIT 1`,
+		customerID, consentType,
+	).Scan(&c.CustomerID, &c.ConsentType, &c.Status, &c.GrantedAt, &c.RevokedAt, &c.ExpiresAt, &c.IPAddress, &c.UserAgent, &c.Version, &c.LegalBasis)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compliance: reading latest consent for %s: %w", customerID, err)
+	}
+	return &c, nil
+}
+
+// GetConsentHistory implements ConsentStore.
+func (s *SQLiteConsentStore) GetConsentHistory(ctx context.Context, customerID string) ([]UserConsent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT customer_id, consent_type, status, granted_at, revoked_at, expires_at, ip_address, user_agent, version, legal_basis
+		FROM consent_history
+		WHERE customer_id = ?
+		ORDER BY granted_at ASC`,
+		customerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: reading consent history for %s: %w", customerID, err)
+	}
+	defer rows.Close()
+
+	var history []UserConsent
+	for rows.Next() {
+		var c UserConsent
+		if err := rows.Scan(&c.CustomerID, &c.ConsentType, &c.Status, &c.GrantedAt, &c.RevokedAt, &c.ExpiresAt, &c.IPAddress, &c.UserAgent, &c.Version, &c.LegalBasis); err != nil {
+			return nil, fmt.Errorf("compliance: scanning consent history for %s: %w", customerID, err)
+		}
+		history = append(history, c)
+	}
+	return history, rows.Err()
+}
+
+// Close closes the underlying database handle.
+func (s *SQLiteConsentStore) Close() error {
+	return s.db.Close()
+}
+
+// sqliteDataSubjectRequestsSchema is SQLite's equivalent of
+// dataSubjectRequestsSchema.
+const sqliteDataSubjectRequestsSchema = `
+CREATE TABLE IF NOT EXISTS data_subject_requests (
+	request_id   TEXT PRIMARY KEY,
+	customer_id  TEXT NOT NULL,
+	request_type TEXT NOT NULL,
+	status       TEXT NOT NULL,
+	requested_at DATETIME NOT NULL,
+	deadline_at  DATETIME NOT NULL,
+	completed_at DATETIME,
+	requested_by TEXT NOT NULL,
+	processed_by TEXT NOT NULL DEFAULT '',
+	notes        TEXT NOT NULL DEFAULT ''
+)`
+
+// SQLiteDSRStore is the local-dev equivalent of PostgresDSRStore.
+type SQLiteDSRStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteDSRStore opens (or creates) the SQLite database at path and
+// ensures the data_subject_requests table exists.
+func NewSQLiteDSRStore(path string) (*SQLiteDSRStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: opening sqlite: %w", err)
+	}
+	if _, err := db.Exec(sqliteDataSubjectRequestsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: migrating data_subject_requests: %w", err)
+	}
+	return &SQLiteDSRStore{db: db}, nil
+}
+
+// PutRequest implements DSRStore.
+func (s *SQLiteDSRStore) PutRequest(ctx context.Context, request DataSubjectRequest) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO data_subject_requests
+			(request_id, customer_id, request_type, status, requested_at, deadline_at, completed_at, requested_by, processed_by, notes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		request.RequestID, request.CustomerID, request.RequestType, request.Status,
+		request.RequestedAt, request.DeadlineAt, request.CompletedAt, request.RequestedBy, request.ProcessedBy, request.Notes,
+	)
+	if err != nil {
+		return fmt.Errorf("compliance: inserting DSR %s: %w", request.RequestID, err)
+	}
+	return nil
+}
+
+// GetRequest implements DSRStore.
+func (s *SQLiteDSRStore) GetRequest(ctx context.Context, requestID string) (*DataSubjectRequest, error) {
+	var r DataSubjectRequest
+	err := s.db.QueryRowContext(ctx, `
+		SELECT request_id, customer_id, request_type, status, requested_at, deadline_at, completed_at, requested_by, processed_by, notes
+		FROM data_subject_requests
+		WHERE request_id = ?`,
+		requestID,
+	).Scan(&r.RequestID, &r.CustomerID, &r.RequestType, &r.Status, &r.RequestedAt, &r.DeadlineAt, &r.CompletedAt, &r.RequestedBy, &r.ProcessedBy, &r.Notes)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compliance: reading DSR %s: %w", requestID, err)
+	}
+	return &r, nil
+}
+
+// ListPendingDSRs implements DSRStore.
+func (s *SQLiteDSRStore) ListPendingDSRs(ctx context.Context) ([]DataSubjectRequest, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT request_id, customer_id, request_type, status, requested_at, deadline_at, completed_at, requested_by, processed_by, notes
+		FROM data_subject_requests
+		WHERE status = 'PENDING'
+		ORDER BY requested_at ASC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: listing pending DSRs: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []DataSubjectRequest
+	for rows.Next() {
+		var r DataSubjectRequest
+		if err := rows.Scan(&r.RequestID, &r.CustomerID, &r.RequestType, &r.Status, &r.RequestedAt, &r.DeadlineAt, &r.CompletedAt, &r.RequestedBy, &r.ProcessedBy, &r.Notes); err != nil {
+			return nil, fmt.Errorf("compliance: scanning pending DSRs: %w", err)
+		}
+		pending = append(pending, r)
+	}
+	return pending, rows.Err()
+}
+
+// MarkDSRCompleted implements DSRStore.
+func (s *SQLiteDSRStore) MarkDSRCompleted(ctx context.Context, request DataSubjectRequest) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE data_subject_requests
+		SET status = ?, completed_at = ?, processed_by = ?, notes = ?
+		WHERE request_id = ?`,
+		request.Status, request.CompletedAt, request.ProcessedBy, request.Notes, request.RequestID,
+	)
+	if err != nil {
+		return fmt.Errorf("compliance: completing DSR %s: %w", request.RequestID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("request not found: %s", request.RequestID)
+	}
+	return nil
+}
+
+// Close closes the underlying database handle.
+func (s *SQLiteDSRStore) Close() error {
+	return s.db.Close()
+}
+
+// sqlitePendingConsentSchema is SQLite's equivalent of
+// pendingConsentSchema.
+const sqlitePendingConsentSchema = `
+CREATE TABLE IF NOT EXISTS pending_consents (
+	token_hash   TEXT PRIMARY KEY,
+	customer_id  TEXT NOT NULL,
+	consent_type TEXT NOT NULL,
+	channel      TEXT NOT NULL,
+	destination  TEXT NOT NULL,
+	legal_basis  TEXT NOT NULL,
+	created_at   DATETIME NOT NULL,
+	expires_at   DATETIME NOT NULL
+)`
+
+// SQLitePendingConsentStore is the local-dev equivalent of
+// PostgresPendingConsentStore.
+type SQLitePendingConsentStore struct {
+	db *sql.DB
+}
+
+// NewSQLitePendingConsentStore opens (or creates) the SQLite database at
+// path and ensures the pending_consents table exists.
+func NewSQLitePendingConsentStore(path string) (*SQLitePendingConsentStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: opening sqlite: %w", err)
+	}
+	if _, err := db.Exec(sqlitePendingConsentSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: migrating pending_consents: %w", err)
+	}
+	return &SQLitePendingConsentStore{db: db}, nil
+}
+
+// CreatePending implements PendingConsentStore.
+func (s *SQLitePendingConsentStore) CreatePending(ctx context.Context, pending PendingConsent) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO pending_consents
+			(token_hash, customer_id, consent_type, channel, destination, legal_basis, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		pending.TokenHash, pending.CustomerID, pending.ConsentType, pending.Channel,
+		pending.Destination, pending.LegalBasis, pending.CreatedAt, pending.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("compliance: recording pending consent for %s: %w", pending.CustomerID, err)
+	}
+	return nil
+}
+
+// GetPending implements PendingConsentStore.
+func (s *SQLitePendingConsentStore) GetPending(ctx context.Context, tokenHash string) (*PendingConsent, error) {
+	var p PendingConsent
+	err := s.db.QueryRowContext(ctx, `
+		SELECT token_hash, customer_id, consent_type, channel, destination, legal_basis, created_at, expires_at
+		FROM pending_consents
+		WHERE token_hash = ?`,
+		tokenHash,
+	).Scan(&p.TokenHash, &p.CustomerID, &p.ConsentType, &p.Channel, &p.Destination, &p.LegalBasis, &p.CreatedAt, &p.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compliance: reading pending consent: %w", err)
+	}
+	return &p, nil
+}
+
+// DeletePending implements PendingConsentStore.
+func (s *SQLitePendingConsentStore) DeletePending(ctx context.Context, tokenHash string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM pending_consents WHERE token_hash = ?`, tokenHash)
+	if err != nil {
+		return fmt.Errorf("compliance: consuming pending consent: %w", err)
+	}
+	return nil
+}
+
+// ConsumePending implements PendingConsentStore.
+func (s *SQLitePendingConsentStore) ConsumePending(ctx context.Context, tokenHash string) (*PendingConsent, error) {
+	var p PendingConsent
+	err := s.db.QueryRowContext(ctx, `
+		DELETE FROM pending_consents
+		WHERE token_hash = ?
+		RETURNING token_hash, customer_id, consent_type, channel, destination, legal_basis, created_at, expires_at`,
+		tokenHash,
+	).Scan(&p.TokenHash, &p.CustomerID, &p.ConsentType, &p.Channel, &p.Destination, &p.LegalBasis, &p.CreatedAt, &p.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compliance: consuming pending consent: %w", err)
+	}
+	return &p, nil
+}
+
+// Close closes the underlying database handle.
+func (s *SQLitePendingConsentStore) Close() error {
+	return s.db.Close()
+}
+
+// sqliteDSRHandlerProgressSchema is SQLite's equivalent of
+// dsrHandlerProgressSchema.
+const sqliteDSRHandlerProgressSchema = `
+CREATE TABLE IF NOT EXISTS dsr_handler_progress (
+	request_id    TEXT NOT NULL,
+	domain        TEXT NOT NULL,
+	status        TEXT NOT NULL,
+	bytes_handled INTEGER NOT NULL DEFAULT 0,
+	error         TEXT NOT NULL DEFAULT '',
+	updated_at    DATETIME NOT NULL,
+	PRIMARY KEY (request_id, domain)
+)`
+
+// SQLiteDSRProgressStore is the local-dev equivalent of
+// PostgresDSRProgressStore.
+type SQLiteDSRProgressStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteDSRProgressStore opens (or creates) the SQLite database at
+// path and ensures the dsr_handler_progress table exists.
+func NewSQLiteDSRProgressStore(path string) (*SQLiteDSRProgressStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: opening sqlite: %w", err)
+	}
+	if _, err := db.Exec(sqliteDSRHandlerProgressSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("compliance: migrating dsr_handler_progress: %w", err)
+	}
+	return &SQLiteDSRProgressStore{db: db}, nil
+}
+
+// PutProgress implements DSRProgressStore.
+func (s *SQLiteDSRProgressStore) PutProgress(ctx context.Context, progress DSRHandlerProgress) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO dsr_handler_progress (request_id, domain, status, bytes_handled, error, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (request_id, domain) DO UPDATE SET
+			status = excluded.status,
+			bytes_handled = excluded.bytes_handled,
+			error = excluded.error,
+			updated_at = excluded.updated_at`,
+		progress.RequestID, progress.Domain, progress.Status, progress.BytesHandled, progress.Error, progress.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("compliance: recording DSR progress for %s/%s: %w", progress.RequestID, progress.Domain, err)
+	}
+	return nil
+}
+
+// GetProgress implements DSRProgressStore.
+func (s *SQLiteDSRProgressStore) GetProgress(ctx context.Context, requestID string) ([]DSRHandlerProgress, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT request_id, domain, status, bytes_handled, error, updated_at
+		FROM dsr_handler_progress
+		WHERE request_id = ?`,
+		requestID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("compliance: reading DSR progress for %s: %w", requestID, err)
+	}
+	defer rows.Close()
+
+	var progress []DSRHandlerProgress
+	for rows.Next() {
+		var p DSRHandlerProgress
+		if err := rows.Scan(&p.RequestID, &p.Domain, &p.Status, &p.BytesHandled, &p.Error, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("compliance: scanning DSR progress for %s: %w", requestID, err)
+		}
+		progress = append(progress, p)
+	}
+	return progress, rows.Err()
+}
+
+// Close closes the underlying database handle.
+func (s *SQLiteDSRProgressStore) Close() error {
+	return s.db.Close()
+}
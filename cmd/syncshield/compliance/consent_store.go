@@ -0,0 +1,316 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ConsentStore persists UserConsent history so consent records and the
+// GDPR Article 7(1) "burden of proof" they support survive a restart.
+// Every grant or revocation is appended as a new row rather than
+// overwritten, so PutConsent never destroys an earlier version - see
+// GetConsentHistory.
+type ConsentStore interface {
+	// PutConsent appends consent as a new version for its
+	// (CustomerID, ConsentType) pair.
+	PutConsent(ctx context.Context, consent UserConsent) error
+
+	// GetLatestConsent returns the most recently appended version for
+	// customerID/consentType, or nil if none exists.
+	GetLatestConsent(ctx context.Context, customerID string, consentType ConsentType) (*UserConsent, error)
+
+	// GetConsentHistory returns every version ever recorded for
+	// customerID, across all consent types, oldest first.
+	GetConsentHistory(ctx context.Context, customerID string) ([]UserConsent, error)
+}
+
+// DSRStore persists DataSubjectRequest records so the 30-day GDPR/CCPA
+// fulfillment clock survives a restart.
+type DSRStore interface {
+	// PutRequest inserts a new data subject request.
+	PutRequest(ctx context.Context, request DataSubjectRequest) error
+
+	// GetRequest retrieves a single request by ID.
+	GetRequest(ctx context.Context, requestID string) (*DataSubjectRequest, error)
+
+	// ListPendingDSRs returns every request still awaiting fulfillment.
+	ListPendingDSRs(ctx context.Context) ([]DataSubjectRequest, error)
+
+	// MarkDSRCompleted updates a request's status and completion metadata.
+	MarkDSRCompleted(ctx context.Context, request DataSubjectRequest) error
+}
+
+// InMemoryConsentStore is the non-durable fallback used in tests and local
+// dev when no Postgres/SQLite DSN is configured - history resets on
+// restart.
+type InMemoryConsentStore struct {
+	mu      sync.Mutex
+	history map[string][]UserConsent // keyed by CustomerID
+}
+
+// NewInMemoryConsentStore creates an empty store.
+func NewInMemoryConsentStore() *InMemoryConsentStore {
+	return &InMemoryConsentStore{history: make(map[string][]UserConsent)}
+}
+
+// PutConsent implements ConsentStore.
+func (s *InMemoryConsentStore) PutConsent(ctx context.Context, consent UserConsent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history[consent.CustomerID] = append(s.history[consent.CustomerID], consent)
+	return nil
+}
+
+// GetLatestConsent implements ConsentStore.
+func (s *InMemoryConsentStore) GetLatestConsent(ctx context.Context, customerID string, consentType ConsentType) (*UserConsent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest *UserConsent
+	for i, c := range s.history[customerID] {
+		if c.ConsentType != consentType {
+			continue
+		}
+		if latest == nil || c.GrantedAt.After(latest.GrantedAt) {
+			latest = &s.history[customerID][i]
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	copied := *latest
+	return &copied, nil
+}
+
+// GetConsentHistory implements ConsentStore.
+func (s *InMemoryConsentStore) GetConsentHistory(ctx context.Context, customerID string) ([]UserConsent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := make([]UserConsent, len(s.history[customerID]))
+	copy(history, s.history[customerID])
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].GrantedAt.Before(history[j].GrantedAt)
+	})
+	return history, nil
+}
+
+// InMemoryDSRStore is the non-durable fallback used in tests and local dev
+// when no Postgres/SQLite DSN is configured.
+type InMemoryDSRStore struct {
+	mu       sync.Mutex
+	requests map[string]DataSubjectRequest
+}
+
+// NewInMemoryDSRStore creates an empty store.
+func NewInMemoryDSRStore() *InMemoryDSRStore {
+	return &InMemoryDSRStore{requests: make(map[string]DataSubjectRequest)}
+}
+
+// PutRequest implements DSRStore.
+func (s *InMemoryDSRStore) PutRequest(ctx context.Context, request DataSubjectRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[request.RequestID] = request
+	return nil
+}
+
+// GetRequest implements DSRStore.
+func (s *InMemoryDSRStore) GetRequest(ctx context.Context, requestID string) (*DataSubjectRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	request, ok := s.requests[requestID]
+	if !ok {
+		return nil, nil
+	}
+	return &request, nil
+}
+
+// ListPendingDSRs implements DSRStore.
+func (s *InMemoryDSRStore) ListPendingDSRs(ctx context.Context) ([]DataSubjectRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []DataSubjectRequest
+	for _, request := range s.requests {
+		if request.Status == "PENDING" {
+			pending = append(pending, request)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RequestedAt.Before(pending[j].RequestedAt)
+	})
+	return pending, nil
+}
+
+// MarkDSRCompleted implements DSRStore.
+func (s *InMemoryDSRStore) MarkDSRCompleted(ctx context.Context, request DataSubjectRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.requests[request.RequestID]; !ok {
+		return fmt.Errorf("request not found: %s", request.RequestID)
+	}
+	s.requests[request.RequestID] = request
+	return nil
+}
+
+// PendingConsent is an in-flight double opt-in confirmation (see
+// DoubleOptInManager): a customer asked for a consent type to be granted,
+// but it isn't GRANTED until ConfirmConsent consumes the single-use token
+// this record was created with.
+type PendingConsent struct {
+	TokenHash   string
+	CustomerID  string
+	ConsentType ConsentType
+	Channel     string // "email" or "sms" - which transport carried the confirmation link
+	Destination string // email address or phone number the link was sent to
+	LegalBasis  string
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// PendingConsentStore persists PendingConsent records across the window
+// between InitiateConsent and ConfirmConsent, so a confirmation link keeps
+// working even if the process restarts before the recipient clicks it.
+type PendingConsentStore interface {
+	// CreatePending inserts a new pending confirmation. TokenHash must be
+	// unique; the raw token is never stored.
+	CreatePending(ctx context.Context, pending PendingConsent) error
+
+	// GetPending looks up a pending confirmation by its token hash, or
+	// returns nil if the token is unknown or already consumed.
+	GetPending(ctx context.Context, tokenHash string) (*PendingConsent, error)
+
+	// DeletePending consumes a pending confirmation so it can never be
+	// confirmed (or expired) a second time.
+	DeletePending(ctx context.Context, tokenHash string) error
+
+	// ConsumePending atomically deletes and returns the pending
+	// confirmation for tokenHash, or nil if it's unknown or was already
+	// consumed - a single round trip so two concurrent or replayed
+	// confirmations of the same token can't both observe it pending.
+	ConsumePending(ctx context.Context, tokenHash string) (*PendingConsent, error)
+}
+
+// InMemoryPendingConsentStore is the non-durable fallback used in tests and
+// local dev when no Postgres/SQLite DSN is configured.
+type InMemoryPendingConsentStore struct {
+	mu      sync.Mutex
+	pending map[string]PendingConsent // keyed by TokenHash
+}
+
+// NewInMemoryPendingConsentStore creates an empty store.
+func NewInMemoryPendingConsentStore() *InMemoryPendingConsentStore {
+	return &InMemoryPendingConsentStore{pending: make(map[string]PendingConsent)}
+}
+
+// CreatePending implements PendingConsentStore.
+func (s *InMemoryPendingConsentStore) CreatePending(ctx context.Context, pending PendingConsent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[pending.TokenHash] = pending
+	return nil
+}
+
+// GetPending implements PendingConsentStore.
+func (s *InMemoryPendingConsentStore) GetPending(ctx context.Context, tokenHash string) (*PendingConsent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending, ok := s.pending[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	return &pending, nil
+}
+
+// DeletePending implements PendingConsentStore.
+func (s *InMemoryPendingConsentStore) DeletePending(ctx context.Context, tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, tokenHash)
+	return nil
+}
+
+// ConsumePending implements PendingConsentStore.
+func (s *InMemoryPendingConsentStore) ConsumePending(ctx context.Context, tokenHash string) (*PendingConsent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending, ok := s.pending[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.pending, tokenHash)
+	return &pending, nil
+}
+
+// DSRHandlerStatus is the outcome of one DSRHandler's work against one
+// DataSubjectRequest.
+type DSRHandlerStatus string
+
+const (
+	DSRHandlerPending DSRHandlerStatus = "PENDING"
+	DSRHandlerDone    DSRHandlerStatus = "DONE"
+	DSRHandlerFailed  DSRHandlerStatus = "FAILED"
+)
+
+// DSRHandlerProgress records one DSRHandler's outcome for one
+// DataSubjectRequest, so DSRProcessor can resume a crashed or restarted
+// run instead of re-invoking handlers that already finished.
+type DSRHandlerProgress struct {
+	RequestID    string
+	Domain       string
+	Status       DSRHandlerStatus
+	BytesHandled int
+	Error        string
+	UpdatedAt    time.Time
+}
+
+// DSRProgressStore persists DSRHandlerProgress rows so a DSRProcessor
+// crash mid-request resumes from the last completed handler rather than
+// re-running every domain from scratch.
+type DSRProgressStore interface {
+	// PutProgress upserts the progress row for (RequestID, Domain).
+	PutProgress(ctx context.Context, progress DSRHandlerProgress) error
+
+	// GetProgress returns every handler's progress recorded so far for
+	// requestID.
+	GetProgress(ctx context.Context, requestID string) ([]DSRHandlerProgress, error)
+}
+
+// InMemoryDSRProgressStore is the non-durable fallback used in tests and
+// local dev when no Postgres/SQLite DSN is configured.
+type InMemoryDSRProgressStore struct {
+	mu       sync.Mutex
+	progress map[string]map[string]DSRHandlerProgress // RequestID -> Domain -> progress
+}
+
+// NewInMemoryDSRProgressStore creates an empty store.
+func NewInMemoryDSRProgressStore() *InMemoryDSRProgressStore {
+	return &InMemoryDSRProgressStore{progress: make(map[string]map[string]DSRHandlerProgress)}
+}
+
+// PutProgress implements DSRProgressStore.
+func (s *InMemoryDSRProgressStore) PutProgress(ctx context.Context, progress DSRHandlerProgress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.progress[progress.RequestID] == nil {
+		s.progress[progress.RequestID] = make(map[string]DSRHandlerProgress)
+	}
+	s.progress[progress.RequestID][progress.Domain] = progress
+	return nil
+}
+
+// GetProgress implements DSRProgressStore.
+func (s *InMemoryDSRProgressStore) GetProgress(ctx context.Context, requestID string) ([]DSRHandlerProgress, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	byDomain := s.progress[requestID]
+	progress := make([]DSRHandlerProgress, 0, len(byDomain))
+	for _, p := range byDomain {
+		progress = append(progress, p)
+	}
+	return progress, nil
+}
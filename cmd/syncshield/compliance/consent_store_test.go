@@ -0,0 +1,116 @@
+package compliance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestConsentManagerRevokeConsentAppendsRowRetainingPriorGrant(t *testing.T) {
+	store := NewInMemoryConsentStore()
+	cm := NewConsentManager(nil, store, zerolog.Nop())
+
+	if err := cm.GrantConsent("cust-1", ConsentMarketing, "127.0.0.1", "test-agent", "Consent"); err != nil {
+		t.Fatalf("GrantConsent failed: %v", err)
+	}
+	if !cm.HasConsent("cust-1", ConsentMarketing) {
+		t.Fatal("expected consent to be granted")
+	}
+
+	if err := cm.RevokeConsent("cust-1", ConsentMarketing, "127.0.0.1"); err != nil {
+		t.Fatalf("RevokeConsent failed: %v", err)
+	}
+	if cm.HasConsent("cust-1", ConsentMarketing) {
+		t.Fatal("expected consent to be revoked")
+	}
+
+	history, err := store.GetConsentHistory(context.Background(), "cust-1")
+	if err != nil {
+		t.Fatalf("GetConsentHistory failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected the grant and the revocation to both be retained, got %d rows", len(history))
+	}
+	if history[0].Status != ConsentGranted || history[1].Status != ConsentRevoked {
+		t.Errorf("expected [GRANTED, REVOKED] in grant order, got [%s, %s]", history[0].Status, history[1].Status)
+	}
+}
+
+func TestConsentManagerRevokeConsentWithoutPriorGrantFails(t *testing.T) {
+	cm := NewConsentManager(nil, NewInMemoryConsentStore(), zerolog.Nop())
+
+	if err := cm.RevokeConsent("cust-2", ConsentMarketing, "127.0.0.1"); err == nil {
+		t.Fatal("expected an error revoking consent that was never granted")
+	}
+}
+
+func TestConsentManagerGetConsentsReturnsLatestPerType(t *testing.T) {
+	cm := NewConsentManager(nil, NewInMemoryConsentStore(), zerolog.Nop())
+
+	if err := cm.GrantConsent("cust-3", ConsentMarketing, "127.0.0.1", "test-agent", "Consent"); err != nil {
+		t.Fatalf("GrantConsent failed: %v", err)
+	}
+	if err := cm.GrantConsent("cust-3", ConsentAnalytics, "127.0.0.1", "test-agent", "Consent"); err != nil {
+		t.Fatalf("GrantConsent failed: %v", err)
+	}
+	if err := cm.RevokeConsent("cust-3", ConsentMarketing, "127.0.0.1"); err != nil {
+		t.Fatalf("RevokeConsent failed: %v", err)
+	}
+
+	consents := cm.GetConsents("cust-3")
+	if len(consents) != 2 {
+		t.Fatalf("expected one entry per consent type, got %d", len(consents))
+	}
+	if consents[ConsentMarketing].Status != ConsentRevoked {
+		t.Errorf("expected the latest marketing consent to be the revocation, got %s", consents[ConsentMarketing].Status)
+	}
+	if consents[ConsentAnalytics].Status != ConsentGranted {
+		t.Errorf("expected analytics consent to still be granted, got %s", consents[ConsentAnalytics].Status)
+	}
+}
+
+func TestDataSubjectRequestManagerCompleteRequestPersistsThroughStore(t *testing.T) {
+	dsm := NewDataSubjectRequestManager(nil, NewInMemoryDSRStore(), zerolog.Nop())
+
+	request, err := dsm.CreateRequest("cust-00004", "DELETION", "admin@example.com")
+	if err != nil {
+		t.Fatalf("CreateRequest failed: %v", err)
+	}
+
+	pending, err := dsm.ListPendingDSRs()
+	if err != nil {
+		t.Fatalf("ListPendingDSRs failed: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending request, got %d", len(pending))
+	}
+
+	if err := dsm.CompleteRequest(request.RequestID, "admin@example.com", "done"); err != nil {
+		t.Fatalf("CompleteRequest failed: %v", err)
+	}
+
+	completed, err := dsm.GetRequest(request.RequestID)
+	if err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	if completed.Status != "COMPLETED" {
+		t.Errorf("expected status COMPLETED, got %s", completed.Status)
+	}
+
+	pending, err = dsm.ListPendingDSRs()
+	if err != nil {
+		t.Fatalf("ListPendingDSRs failed: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no pending requests after completion, got %d", len(pending))
+	}
+}
+
+func TestDataSubjectRequestManagerCompleteRequestUnknownIDFails(t *testing.T) {
+	dsm := NewDataSubjectRequestManager(nil, NewInMemoryDSRStore(), zerolog.Nop())
+
+	if err := dsm.CompleteRequest("never-existed", "admin@example.com", "done"); err == nil {
+		t.Fatal("expected an error completing a request that was never created")
+	}
+}
@@ -0,0 +1,62 @@
+package compliance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileDeadLetterSink is a DeadLetterSink appending undeliverable events to
+// a JSON-lines file, so an operator can inspect or replay them later
+// instead of losing them when a remote sink is down.
+type FileDeadLetterSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// deadLetterRecord is one line FileDeadLetterSink appends.
+type deadLetterRecord struct {
+	SinkName   string     `json:"sink_name"`
+	Event      AuditEvent `json:"event"`
+	Error      string     `json:"error"`
+	RecordedAt time.Time  `json:"recorded_at"`
+}
+
+// NewFileDeadLetterSink opens (or creates) path and returns a
+// FileDeadLetterSink appending to it.
+func NewFileDeadLetterSink(path string) (*FileDeadLetterSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dead-letter file: %w", err)
+	}
+	return &FileDeadLetterSink{file: file}, nil
+}
+
+// Record implements DeadLetterSink.
+func (d *FileDeadLetterSink) Record(sinkName string, event AuditEvent, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data, marshalErr := json.Marshal(deadLetterRecord{
+		SinkName:   sinkName,
+		Event:      event,
+		Error:      err.Error(),
+		RecordedAt: time.Now(),
+	})
+	if marshalErr != nil {
+		fmt.Printf("⚠️  failed to marshal dead-letter record for sink %q: %v\n", sinkName, marshalErr)
+		return
+	}
+	if _, writeErr := d.file.Write(append(data, '\n')); writeErr != nil {
+		fmt.Printf("⚠️  failed to write dead-letter record for sink %q: %v\n", sinkName, writeErr)
+	}
+}
+
+// Close flushes and closes the dead-letter file.
+func (d *FileDeadLetterSink) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.file.Close()
+}
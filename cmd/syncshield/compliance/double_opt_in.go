@@ -0,0 +1,207 @@
+package compliance
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultConsentConfirmationTTL bounds how long an unconfirmed double
+// opt-in link stays valid before ConfirmConsent rejects it as expired.
+const defaultConsentConfirmationTTL = 24 * time.Hour
+
+// ConsentVerificationSender dispatches the confirmation link that proves
+// the person behind customerID/destination actually initiated this
+// consent request. Defined here instead of taking a crm/notify.Dispatcher
+// directly, since crm/notify already imports compliance and Go doesn't
+// allow the cycle - wrap a *notify.Dispatcher in an adapter satisfying
+// this interface at the call site.
+type ConsentVerificationSender interface {
+	SendConsentVerification(ctx context.Context, customerID, channel, destination, confirmationURL string) error
+}
+
+// DoubleOptInManager implements the GDPR double opt-in flow:
+// InitiateConsent records a PENDING confirmation and dispatches a
+// single-use link through sender; only ConfirmConsent, called from that
+// link with the confirming request's own IP/UA, transitions it to
+// GRANTED via the underlying ConsentManager. A bare GrantConsent call
+// can't prove the email/phone owner actually agreed - this can.
+type DoubleOptInManager struct {
+	consent        *ConsentManager
+	store          PendingConsentStore
+	sender         ConsentVerificationSender
+	logger         *GDPRAuditLogger
+	ttl            time.Duration
+	confirmBaseURL string
+}
+
+// NewDoubleOptInManager creates a manager that grants consent through
+// consent once a link is confirmed, dispatches links through sender, and
+// builds confirmation URLs by appending "?token=..." to confirmBaseURL
+// (e.g. "https://app.example.com/consent/confirm"). ttl <= 0 defaults to
+// 24 hours.
+func NewDoubleOptInManager(consent *ConsentManager, store PendingConsentStore, sender ConsentVerificationSender, logger *GDPRAuditLogger, confirmBaseURL string, ttl time.Duration) *DoubleOptInManager {
+	if ttl <= 0 {
+		ttl = defaultConsentConfirmationTTL
+	}
+	return &DoubleOptInManager{
+		consent:        consent,
+		store:          store,
+		sender:         sender,
+		logger:         logger,
+		ttl:            ttl,
+		confirmBaseURL: confirmBaseURL,
+	}
+}
+
+// InitiateConsent records a pending confirmation for customerID/consentType
+// and dispatches a single-use confirmation link to destination over
+// channel ("email" or "sms"). Consent is not granted until the recipient
+// follows the link and ConfirmConsent consumes its token.
+func (d *DoubleOptInManager) InitiateConsent(ctx context.Context, customerID string, consentType ConsentType, channel, destination, legalBasis, ipAddress, userAgent string) error {
+	token, tokenHash, err := generateConsentToken()
+	if err != nil {
+		return fmt.Errorf("generating confirmation token: %w", err)
+	}
+
+	now := time.Now()
+	pending := PendingConsent{
+		TokenHash:   tokenHash,
+		CustomerID:  customerID,
+		ConsentType: consentType,
+		Channel:     channel,
+		Destination: destination,
+		LegalBasis:  legalBasis,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(d.ttl),
+	}
+	if err := d.store.CreatePending(ctx, pending); err != nil {
+		return fmt.Errorf("recording pending consent for %s: %w", customerID, err)
+	}
+
+	if d.logger != nil {
+		d.logger.LogEvent(AuditEvent{
+			Level:      LevelCritical,
+			EventType:  "consent_double_opt_in_initiated",
+			CustomerID: customerID,
+			Action:     "initiate_consent",
+			Resource:   string(consentType),
+			Outcome:    "PENDING",
+			Reason:     "Double opt-in confirmation dispatched",
+			IPAddress:  ipAddress,
+			UserAgent:  userAgent,
+			Metadata: map[string]interface{}{
+				"consent_type": consentType,
+				"channel":      channel,
+			},
+		})
+	}
+
+	confirmationURL := fmt.Sprintf("%s?token=%s", d.confirmBaseURL, token)
+	if err := d.sender.SendConsentVerification(ctx, customerID, channel, destination, confirmationURL); err != nil {
+		return fmt.Errorf("dispatching consent verification to %s: %w", customerID, err)
+	}
+	return nil
+}
+
+// ConfirmConsent consumes token and, if it is unexpired and unused, grants
+// the pending consent - recording ipAddress/userAgent from this
+// confirming request, not the InitiateConsent call that started the flow.
+func (d *DoubleOptInManager) ConfirmConsent(ctx context.Context, token, ipAddress, userAgent string) error {
+	tokenHash := hashConsentToken(token)
+
+	// ConsumePending deletes and returns the pending confirmation in one
+	// step, so of two concurrent or replayed confirmations of the same
+	// token, only one can ever observe a non-nil result - the other gets
+	// the same "not found" outcome a third, later replay would.
+	pending, err := d.store.ConsumePending(ctx, tokenHash)
+	if err != nil {
+		return fmt.Errorf("consuming pending consent: %w", err)
+	}
+	if pending == nil {
+		return fmt.Errorf("confirmation token not found or already used")
+	}
+
+	if time.Now().After(pending.ExpiresAt) {
+		if d.logger != nil {
+			d.logger.LogEvent(AuditEvent{
+				Level:      LevelWarning,
+				EventType:  "consent_double_opt_in_expired",
+				CustomerID: pending.CustomerID,
+				Action:     "confirm_consent",
+				Resource:   string(pending.ConsentType),
+				Outcome:    "EXPIRED",
+				Reason:     "Confirmation link expired before use",
+				IPAddress:  ipAddress,
+				UserAgent:  userAgent,
+			})
+		}
+		return fmt.Errorf("confirmation token expired")
+	}
+
+	if err := d.consent.GrantConsent(pending.CustomerID, pending.ConsentType, ipAddress, userAgent, pending.LegalBasis); err != nil {
+		return fmt.Errorf("granting consent for %s: %w", pending.CustomerID, err)
+	}
+
+	if d.logger != nil {
+		d.logger.LogEvent(AuditEvent{
+			Level:      LevelCritical,
+			EventType:  "consent_double_opt_in_confirmed",
+			CustomerID: pending.CustomerID,
+			Action:     "confirm_consent",
+			Resource:   string(pending.ConsentType),
+			Outcome:    "GRANTED",
+			Reason:     "Double opt-in confirmed by recipient",
+			IPAddress:  ipAddress,
+			UserAgent:  userAgent,
+		})
+	}
+	return nil
+}
+
+// ConfirmationHandler returns an http.HandlerFunc consumers can mount on
+// their router, e.g. http.HandleFunc("/consent/confirm",
+// doubleOptIn.ConfirmationHandler()), to serve the link InitiateConsent
+// dispatched.
+func (d *DoubleOptInManager) ConfirmationHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			http.Error(w, "Missing token", http.StatusBadRequest)
+			return
+		}
+
+		if err := d.ConfirmConsent(r.Context(), token, r.RemoteAddr, r.UserAgent()); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"status":  "granted",
+			"message": "Consent confirmed",
+		})
+	}
+}
+
+// generateConsentToken returns a cryptographically random single-use
+// token plus the hash that is actually persisted - the raw token is
+// handed to the recipient and never stored.
+func generateConsentToken() (token, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(raw)
+	return token, hashConsentToken(token), nil
+}
+
+func hashConsentToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
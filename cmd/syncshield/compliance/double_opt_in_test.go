@@ -0,0 +1,142 @@
+package compliance
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type fakeConsentVerificationSender struct {
+	lastDestination string
+	lastURL         string
+	calls           int
+}
+
+func (f *fakeConsentVerificationSender) SendConsentVerification(ctx context.Context, customerID, channel, destination, confirmationURL string) error {
+	f.calls++
+	f.lastDestination = destination
+	f.lastURL = confirmationURL
+	return nil
+}
+
+func newTestDoubleOptInManager(sender *fakeConsentVerificationSender) (*DoubleOptInManager, *ConsentManager) {
+	cm := NewConsentManager(nil, NewInMemoryConsentStore(), zerolog.Nop())
+	d := NewDoubleOptInManager(cm, NewInMemoryPendingConsentStore(), sender, nil, "https://app.example.com/consent/confirm", 0)
+	return d, cm
+}
+
+func TestDoubleOptInManagerConfirmConsentGrantsAfterInitiate(t *testing.T) {
+	sender := &fakeConsentVerificationSender{}
+	d, cm := newTestDoubleOptInManager(sender)
+
+	if err := d.InitiateConsent(context.Background(), "cust-1", ConsentMarketing, "email", "cust-1@example.com", "Consent", "127.0.0.1", "initiator-agent"); err != nil {
+		t.Fatalf("InitiateConsent failed: %v", err)
+	}
+	if sender.calls != 1 {
+		t.Fatalf("expected exactly 1 verification dispatch, got %d", sender.calls)
+	}
+	if cm.HasConsent("cust-1", ConsentMarketing) {
+		t.Fatal("expected consent to still be pending before confirmation")
+	}
+
+	token := strings.TrimPrefix(sender.lastURL, "https://app.example.com/consent/confirm?token=")
+	if err := d.ConfirmConsent(context.Background(), token, "203.0.113.5", "confirmer-agent"); err != nil {
+		t.Fatalf("ConfirmConsent failed: %v", err)
+	}
+	if !cm.HasConsent("cust-1", ConsentMarketing) {
+		t.Fatal("expected consent to be granted after confirmation")
+	}
+}
+
+func TestDoubleOptInManagerConfirmConsentTokenIsSingleUse(t *testing.T) {
+	sender := &fakeConsentVerificationSender{}
+	d, _ := newTestDoubleOptInManager(sender)
+
+	if err := d.InitiateConsent(context.Background(), "cust-2", ConsentMarketing, "email", "cust-2@example.com", "Consent", "127.0.0.1", "initiator-agent"); err != nil {
+		t.Fatalf("InitiateConsent failed: %v", err)
+	}
+	token := strings.TrimPrefix(sender.lastURL, "https://app.example.com/consent/confirm?token=")
+
+	if err := d.ConfirmConsent(context.Background(), token, "203.0.113.5", "confirmer-agent"); err != nil {
+		t.Fatalf("first ConfirmConsent failed: %v", err)
+	}
+	if err := d.ConfirmConsent(context.Background(), token, "203.0.113.5", "confirmer-agent"); err == nil {
+		t.Fatal("expected the second confirmation of the same token to fail")
+	}
+}
+
+func TestDoubleOptInManagerConfirmConsentConcurrentConfirmationsOnlyOneSucceeds(t *testing.T) {
+	sender := &fakeConsentVerificationSender{}
+	d, _ := newTestDoubleOptInManager(sender)
+
+	if err := d.InitiateConsent(context.Background(), "cust-4", ConsentMarketing, "email", "cust-4@example.com", "Consent", "127.0.0.1", "initiator-agent"); err != nil {
+		t.Fatalf("InitiateConsent failed: %v", err)
+	}
+	token := strings.TrimPrefix(sender.lastURL, "https://app.example.com/consent/confirm?token=")
+
+	const attempts = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	successes := 0
+	wg.Add(attempts)
+	for i := 0; i < attempts; i++ {
+		go func() {
+			defer wg.Done()
+			if err := d.ConfirmConsent(context.Background(), token, "203.0.113.5", "confirmer-agent"); err == nil {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if successes != 1 {
+		t.Fatalf("expected exactly 1 of %d concurrent confirmations to succeed, got %d", attempts, successes)
+	}
+}
+
+func TestDoubleOptInManagerConfirmConsentRejectsUnknownToken(t *testing.T) {
+	sender := &fakeConsentVerificationSender{}
+	d, _ := newTestDoubleOptInManager(sender)
+
+	if err := d.ConfirmConsent(context.Background(), "never-issued", "203.0.113.5", "confirmer-agent"); err == nil {
+		t.Fatal("expected an error confirming a token that was never issued")
+	}
+}
+
+func TestDoubleOptInManagerConfirmConsentRejectsExpiredToken(t *testing.T) {
+	sender := &fakeConsentVerificationSender{}
+	cm := NewConsentManager(nil, NewInMemoryConsentStore(), zerolog.Nop())
+	store := NewInMemoryPendingConsentStore()
+	d := NewDoubleOptInManager(cm, store, sender, nil, "https://app.example.com/consent/confirm", 0)
+
+	token, tokenHash, err := generateConsentToken()
+	if err != nil {
+		t.Fatalf("generateConsentToken failed: %v", err)
+	}
+	now := time.Now()
+	if err := store.CreatePending(context.Background(), PendingConsent{
+		TokenHash:   tokenHash,
+		CustomerID:  "cust-3",
+		ConsentType: ConsentMarketing,
+		Channel:     "sms",
+		Destination: "+15555550100",
+		LegalBasis:  "Consent",
+		CreatedAt:   now.Add(-48 * time.Hour),
+		ExpiresAt:   now.Add(-24 * time.Hour),
+	}); err != nil {
+		t.Fatalf("CreatePending failed: %v", err)
+	}
+
+	if err := d.ConfirmConsent(context.Background(), token, "203.0.113.5", "confirmer-agent"); err == nil {
+		t.Fatal("expected confirmation of an already-expired token to fail")
+	}
+	if cm.HasConsent("cust-3", ConsentMarketing) {
+		t.Fatal("expected consent to remain ungranted after an expired confirmation")
+	}
+}
@@ -0,0 +1,438 @@
+package compliance
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// dsrDeadlineWarningWindow is how close to DeadlineAt a request has to be
+// before DSRProcessor starts logging a warning on every poll.
+const dsrDeadlineWarningWindow = 7 * 24 * time.Hour
+
+// dsrPollInterval is how often DSRProcessor checks DataSubjectRequestManager
+// for newly-pending requests.
+const dsrPollInterval = 30 * time.Second
+
+// DSRHandler exports or deletes one subsystem's data for a customer as
+// part of a DataSubjectRequest - CRM records, ad-connector spend history,
+// consent history, audit logs, and so on. Each data domain registers its
+// own handler with DSRProcessor via RegisterHandler.
+type DSRHandler interface {
+	// Domain names the data domain this handler owns (e.g. "crm",
+	// "ad_connectors", "consent", "audit_log"). It becomes both the ZIP
+	// manifest's section name and the DSRHandlerProgress key, so it must
+	// be stable across process restarts.
+	Domain() string
+
+	// Export returns this domain's data for customerID, serialized
+	// however the domain prefers (JSON, CSV, ...). It becomes one
+	// attachment in the ZIP bundle produced for ACCESS/PORTABILITY
+	// requests.
+	Export(ctx context.Context, customerID string) ([]byte, error)
+
+	// Delete erases this domain's data for customerID, for DELETION
+	// requests.
+	Delete(ctx context.Context, customerID string) error
+}
+
+// DSRExportSink persists the finished ZIP bundle built for an
+// ACCESS/PORTABILITY request. Left nil on a DSRProcessor, the bundle is
+// discarded after its manifest hash is recorded in the audit log - set a
+// sink in production to ship bundles to wherever they're served from
+// (S3, a signed download URL, ...).
+type DSRExportSink interface {
+	StoreExport(ctx context.Context, requestID string, zipBytes []byte) error
+}
+
+// DSRProcessor is the queued worker pool backing real GDPR fulfillment:
+// it polls DataSubjectRequestManager for PENDING requests, dispatches
+// each registered domain's DSRHandler, aggregates ACCESS/PORTABILITY
+// exports into a hash-sealed ZIP bundle, and only marks a request
+// COMPLETED once every handler has acknowledged. Per-handler progress is
+// persisted through a DSRProgressStore, so a crash mid-processing resumes
+// from the last completed handler rather than restarting the request.
+type DSRProcessor struct {
+	dsr      *DataSubjectRequestManager
+	progress DSRProgressStore
+	logger   *GDPRAuditLogger
+	sink     DSRExportSink
+
+	handlersMu sync.RWMutex
+	handlers   map[string]DSRHandler
+
+	workers int
+	queue   chan DataSubjectRequest
+	wg      sync.WaitGroup
+	once    sync.Once
+
+	inFlightMu sync.Mutex
+	inFlight   map[string]bool
+}
+
+// NewDSRProcessor creates a processor with workers worker goroutines,
+// fulfilling requests tracked by dsr, persisting handler progress through
+// progress, and auditing through logger. sink may be nil.
+func NewDSRProcessor(workers int, dsr *DataSubjectRequestManager, progress DSRProgressStore, logger *GDPRAuditLogger, sink DSRExportSink) *DSRProcessor {
+	if workers < 1 {
+		workers = 1
+	}
+	return &DSRProcessor{
+		dsr:      dsr,
+		progress: progress,
+		logger:   logger,
+		sink:     sink,
+		handlers: make(map[string]DSRHandler),
+		workers:  workers,
+		queue:    make(chan DataSubjectRequest, workers*4),
+		inFlight: make(map[string]bool),
+	}
+}
+
+// RegisterHandler adds h as the owner of its Domain(). Registering a
+// second handler for the same domain replaces the first.
+func (p *DSRProcessor) RegisterHandler(h DSRHandler) {
+	p.handlersMu.Lock()
+	defer p.handlersMu.Unlock()
+	p.handlers[h.Domain()] = h
+}
+
+// Start spawns the worker pool and a poll loop that feeds it from
+// dsr.ListPendingDSRs every dsrPollInterval, until ctx is canceled.
+// Calling Start more than once is a no-op.
+func (p *DSRProcessor) Start(ctx context.Context) {
+	p.once.Do(func() {
+		for i := 0; i < p.workers; i++ {
+			p.wg.Add(1)
+			go p.worker(ctx)
+		}
+		p.wg.Add(1)
+		go p.pollLoop(ctx)
+	})
+}
+
+// Stop waits for the poll loop and every in-flight handler invocation to
+// finish. Callers should cancel the context passed to Start before
+// calling Stop.
+func (p *DSRProcessor) Stop() {
+	p.wg.Wait()
+}
+
+func (p *DSRProcessor) pollLoop(ctx context.Context) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(dsrPollInterval)
+	defer ticker.Stop()
+
+	p.pollOnce(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			close(p.queue)
+			return
+		case <-ticker.C:
+			p.pollOnce(ctx)
+		}
+	}
+}
+
+func (p *DSRProcessor) pollOnce(ctx context.Context) {
+	pending, err := p.dsr.ListPendingDSRs()
+	if err != nil {
+		return
+	}
+
+	p.inFlightMu.Lock()
+	defer p.inFlightMu.Unlock()
+	for _, request := range pending {
+		if p.inFlight[request.RequestID] {
+			continue
+		}
+		p.alertIfDeadlineApproaching(request)
+
+		select {
+		case p.queue <- request:
+			p.inFlight[request.RequestID] = true
+		case <-ctx.Done():
+			return
+		default:
+			// Queue is full; this request is picked up on the next poll.
+		}
+	}
+}
+
+func (p *DSRProcessor) alertIfDeadlineApproaching(request DataSubjectRequest) {
+	remaining := time.Until(request.DeadlineAt)
+	if remaining > dsrDeadlineWarningWindow {
+		return
+	}
+	if p.logger == nil {
+		return
+	}
+	p.logger.LogEvent(AuditEvent{
+		Level:      LevelWarning,
+		EventType:  "dsr_deadline_approaching",
+		CustomerID: request.CustomerID,
+		Action:     "fulfillment_deadline_check",
+		Resource:   request.RequestType,
+		Outcome:    "PENDING",
+		Reason:     fmt.Sprintf("DSR %s has %s remaining before its GDPR/CCPA deadline", request.RequestID, remaining.Round(time.Hour)),
+		Metadata: map[string]interface{}{
+			"request_id":  request.RequestID,
+			"deadline_at": request.DeadlineAt,
+		},
+	})
+}
+
+func (p *DSRProcessor) worker(ctx context.Context) {
+	defer p.wg.Done()
+	for request := range p.queue {
+		p.process(ctx, request)
+		p.inFlightMu.Lock()
+		delete(p.inFlight, request.RequestID)
+		p.inFlightMu.Unlock()
+	}
+}
+
+// process runs every registered handler against request, resuming from
+// whatever DSRHandlerProgress already shows DONE, and marks the request
+// COMPLETED only once all handlers succeed.
+func (p *DSRProcessor) process(ctx context.Context, request DataSubjectRequest) {
+	p.handlersMu.RLock()
+	domains := make([]string, 0, len(p.handlers))
+	for domain := range p.handlers {
+		domains = append(domains, domain)
+	}
+	p.handlersMu.RUnlock()
+	sort.Strings(domains)
+
+	existing, err := p.progress.GetProgress(ctx, request.RequestID)
+	if err != nil {
+		return
+	}
+	done := make(map[string]bool, len(existing))
+	for _, pr := range existing {
+		if pr.Status == DSRHandlerDone {
+			done[pr.Domain] = true
+		}
+	}
+
+	exports := make(map[string][]byte)
+	allSucceeded := true
+	for _, domain := range domains {
+		if done[domain] {
+			continue
+		}
+
+		p.handlersMu.RLock()
+		handler := p.handlers[domain]
+		p.handlersMu.RUnlock()
+
+		if !p.invokeHandler(ctx, request, handler, exports) {
+			allSucceeded = false
+		}
+	}
+	if !allSucceeded {
+		return
+	}
+
+	if request.RequestType == "ACCESS" || request.RequestType == "PORTABILITY" {
+		bundle, manifestHash, err := buildExportBundle(request.CustomerID, exports)
+		if err != nil {
+			p.logHandlerFailure(request, "export_bundle", err)
+			return
+		}
+		if p.sink != nil {
+			if err := p.sink.StoreExport(ctx, request.RequestID, bundle); err != nil {
+				p.logHandlerFailure(request, "export_bundle", err)
+				return
+			}
+		}
+		if p.logger != nil {
+			p.logger.LogEvent(AuditEvent{
+				Level:      LevelCritical,
+				EventType:  "dsr_export_bundle_built",
+				CustomerID: request.CustomerID,
+				Action:     "build_export_bundle",
+				Resource:   request.RequestType,
+				Outcome:    "SUCCESS",
+				Reason:     "Export bundle assembled and sealed with a manifest hash",
+				Metadata: map[string]interface{}{
+					"request_id":    request.RequestID,
+					"manifest_hash": manifestHash,
+					"bundle_bytes":  len(bundle),
+				},
+			})
+		}
+	}
+
+	_ = p.dsr.CompleteRequest(request.RequestID, "dsr-processor", "Fulfilled by DSRProcessor")
+}
+
+// invokeHandler runs handler's Export or Delete (per request.RequestType)
+// against request, records its outcome to progress, audits duration and
+// byte count, and returns whether it succeeded.
+func (p *DSRProcessor) invokeHandler(ctx context.Context, request DataSubjectRequest, handler DSRHandler, exports map[string][]byte) bool {
+	started := time.Now()
+	var bytesHandled int
+	var handlerErr error
+
+	switch request.RequestType {
+	case "DELETION":
+		handlerErr = handler.Delete(ctx, request.CustomerID)
+	default:
+		var data []byte
+		data, handlerErr = handler.Export(ctx, request.CustomerID)
+		if handlerErr == nil {
+			exports[handler.Domain()] = data
+			bytesHandled = len(data)
+		}
+	}
+	duration := time.Since(started)
+
+	status := DSRHandlerDone
+	errText := ""
+	if handlerErr != nil {
+		status = DSRHandlerFailed
+		errText = handlerErr.Error()
+	}
+	_ = p.progress.PutProgress(ctx, DSRHandlerProgress{
+		RequestID:    request.RequestID,
+		Domain:       handler.Domain(),
+		Status:       status,
+		BytesHandled: bytesHandled,
+		Error:        errText,
+		UpdatedAt:    time.Now(),
+	})
+
+	if p.logger != nil {
+		outcome := "SUCCESS"
+		reason := fmt.Sprintf("%s handler completed in %s", handler.Domain(), duration)
+		level := LevelInfo
+		if handlerErr != nil {
+			outcome = "FAILED"
+			reason = fmt.Sprintf("%s handler failed after %s: %v", handler.Domain(), duration, handlerErr)
+			level = LevelWarning
+		}
+		p.logger.LogEvent(AuditEvent{
+			Level:      level,
+			EventType:  "dsr_handler_invoked",
+			CustomerID: request.CustomerID,
+			Action:     "invoke_dsr_handler",
+			Resource:   handler.Domain(),
+			Outcome:    outcome,
+			Reason:     reason,
+			Metadata: map[string]interface{}{
+				"request_id":    request.RequestID,
+				"domain":        handler.Domain(),
+				"duration_ms":   duration.Milliseconds(),
+				"bytes_handled": bytesHandled,
+			},
+		})
+	}
+
+	return handlerErr == nil
+}
+
+func (p *DSRProcessor) logHandlerFailure(request DataSubjectRequest, action string, err error) {
+	if p.logger == nil {
+		return
+	}
+	p.logger.LogEvent(AuditEvent{
+		Level:      LevelWarning,
+		EventType:  "dsr_processing_failed",
+		CustomerID: request.CustomerID,
+		Action:     action,
+		Resource:   request.RequestType,
+		Outcome:    "FAILED",
+		Reason:     err.Error(),
+		Metadata: map[string]interface{}{
+			"request_id": request.RequestID,
+		},
+	})
+}
+
+// exportManifestEntry is one domain's entry in manifest.json.
+type exportManifestEntry struct {
+	Domain string `json:"domain"`
+	SHA256 string `json:"sha256"`
+	Bytes  int    `json:"bytes"`
+}
+
+// exportManifest describes every domain bundled into a DSR export ZIP and
+// seals the set with ManifestHash, the SHA-256 of the manifest's own
+// entries - proof the bundle wasn't tampered with after assembly, in the
+// same spirit as GDPRAuditLogger's hash-chain anchoring.
+type exportManifest struct {
+	CustomerID   string                `json:"customer_id"`
+	GeneratedAt  time.Time             `json:"generated_at"`
+	Domains      []exportManifestEntry `json:"domains"`
+	ManifestHash string                `json:"manifest_hash"`
+}
+
+// buildExportBundle packages each domain's exported bytes into a ZIP
+// (one file per domain, plus manifest.json) and returns the archive and
+// its manifest hash.
+func buildExportBundle(customerID string, exports map[string][]byte) (bundle []byte, manifestHash string, err error) {
+	domains := make([]string, 0, len(exports))
+	for domain := range exports {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	manifest := exportManifest{
+		CustomerID:  customerID,
+		GeneratedAt: time.Now(),
+	}
+	for _, domain := range domains {
+		sum := sha256.Sum256(exports[domain])
+		manifest.Domains = append(manifest.Domains, exportManifestEntry{
+			Domain: domain,
+			SHA256: hex.EncodeToString(sum[:]),
+			Bytes:  len(exports[domain]),
+		})
+	}
+
+	unsealed, err := json.Marshal(manifest.Domains)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshaling export manifest: %w", err)
+	}
+	sealHash := sha256.Sum256(unsealed)
+	manifest.ManifestHash = hex.EncodeToString(sealHash[:])
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, domain := range domains {
+		w, err := zw.Create(domain + ".json")
+		if err != nil {
+			return nil, "", fmt.Errorf("creating zip entry %s: %w", domain, err)
+		}
+		if _, err := w.Write(exports[domain]); err != nil {
+			return nil, "", fmt.Errorf("writing zip entry %s: %w", domain, err)
+		}
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, "", fmt.Errorf("marshaling sealed manifest: %w", err)
+	}
+	w, err := zw.Create("manifest.json")
+	if err != nil {
+		return nil, "", fmt.Errorf("creating manifest.json: %w", err)
+	}
+	if _, err := w.Write(manifestJSON); err != nil {
+		return nil, "", fmt.Errorf("writing manifest.json: %w", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, "", fmt.Errorf("closing export bundle: %w", err)
+	}
+	return buf.Bytes(), manifest.ManifestHash, nil
+}
@@ -0,0 +1,184 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type fakeDSRHandler struct {
+	domain      string
+	exportData  []byte
+	exportErr   error
+	deleteErr   error
+	exportCalls int
+	deleteCalls int
+}
+
+func (h *fakeDSRHandler) Domain() string { return h.domain }
+
+func (h *fakeDSRHandler) Export(ctx context.Context, customerID string) ([]byte, error) {
+	h.exportCalls++
+	if h.exportErr != nil {
+		return nil, h.exportErr
+	}
+	return h.exportData, nil
+}
+
+func (h *fakeDSRHandler) Delete(ctx context.Context, customerID string) error {
+	h.deleteCalls++
+	return h.deleteErr
+}
+
+type fakeDSRExportSink struct {
+	requestID string
+	bundle    []byte
+	calls     int
+}
+
+func (s *fakeDSRExportSink) StoreExport(ctx context.Context, requestID string, zipBytes []byte) error {
+	s.calls++
+	s.requestID = requestID
+	s.bundle = zipBytes
+	return nil
+}
+
+func TestDSRProcessorProcessCompletesAccessRequestWithAllHandlers(t *testing.T) {
+	dsm := NewDataSubjectRequestManager(nil, NewInMemoryDSRStore(), zerolog.Nop())
+	request, err := dsm.CreateRequest("cust-000001", "ACCESS", "cust-000001@example.com")
+	if err != nil {
+		t.Fatalf("CreateRequest failed: %v", err)
+	}
+
+	progress := NewInMemoryDSRProgressStore()
+	sink := &fakeDSRExportSink{}
+	p := NewDSRProcessor(2, dsm, progress, nil, sink)
+
+	crm := &fakeDSRHandler{domain: "crm", exportData: []byte(`{"orders":[]}`)}
+	audit := &fakeDSRHandler{domain: "audit_log", exportData: []byte(`{"events":[]}`)}
+	p.RegisterHandler(crm)
+	p.RegisterHandler(audit)
+
+	p.process(context.Background(), *request)
+
+	if crm.exportCalls != 1 || audit.exportCalls != 1 {
+		t.Fatalf("expected both handlers to be invoked exactly once, got crm=%d audit=%d", crm.exportCalls, audit.exportCalls)
+	}
+	if sink.calls != 1 {
+		t.Fatalf("expected the export bundle to be stored exactly once, got %d", sink.calls)
+	}
+
+	completed, err := dsm.GetRequest(request.RequestID)
+	if err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	if completed.Status != "COMPLETED" {
+		t.Errorf("expected status COMPLETED, got %s", completed.Status)
+	}
+}
+
+func TestDSRProcessorProcessResumesFromPriorProgressAfterFailure(t *testing.T) {
+	dsm := NewDataSubjectRequestManager(nil, NewInMemoryDSRStore(), zerolog.Nop())
+	request, err := dsm.CreateRequest("cust-000002", "ACCESS", "cust-000002@example.com")
+	if err != nil {
+		t.Fatalf("CreateRequest failed: %v", err)
+	}
+
+	progress := NewInMemoryDSRProgressStore()
+	p := NewDSRProcessor(1, dsm, progress, nil, nil)
+
+	crm := &fakeDSRHandler{domain: "crm", exportData: []byte(`{}`)}
+	flaky := &fakeDSRHandler{domain: "flaky", exportErr: fmt.Errorf("simulated export failure")}
+	p.RegisterHandler(crm)
+	p.RegisterHandler(flaky)
+
+	p.process(context.Background(), *request)
+
+	completed, err := dsm.GetRequest(request.RequestID)
+	if err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	if completed.Status != "PENDING" {
+		t.Fatalf("expected the request to remain PENDING after a handler failure, got %s", completed.Status)
+	}
+	if crm.exportCalls != 1 {
+		t.Fatalf("expected crm to have run once, got %d", crm.exportCalls)
+	}
+
+	flaky.exportErr = nil
+	p.process(context.Background(), *request)
+
+	if crm.exportCalls != 1 {
+		t.Errorf("expected crm NOT to be re-invoked on resume (already DONE), got %d calls", crm.exportCalls)
+	}
+	if flaky.exportCalls != 2 {
+		t.Errorf("expected flaky to be retried exactly once more, got %d calls", flaky.exportCalls)
+	}
+
+	completed, err = dsm.GetRequest(request.RequestID)
+	if err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	if completed.Status != "COMPLETED" {
+		t.Errorf("expected the request to be COMPLETED after the retry succeeds, got %s", completed.Status)
+	}
+}
+
+func TestDSRProcessorProcessDeletionRequestCallsDeleteNotExport(t *testing.T) {
+	dsm := NewDataSubjectRequestManager(nil, NewInMemoryDSRStore(), zerolog.Nop())
+	request, err := dsm.CreateRequest("cust-000003", "DELETION", "cust-000003@example.com")
+	if err != nil {
+		t.Fatalf("CreateRequest failed: %v", err)
+	}
+
+	p := NewDSRProcessor(1, dsm, NewInMemoryDSRProgressStore(), nil, nil)
+	crm := &fakeDSRHandler{domain: "crm"}
+	p.RegisterHandler(crm)
+
+	p.process(context.Background(), *request)
+
+	if crm.deleteCalls != 1 {
+		t.Errorf("expected Delete to be called once, got %d", crm.deleteCalls)
+	}
+	if crm.exportCalls != 0 {
+		t.Errorf("expected Export not to be called for a DELETION request, got %d", crm.exportCalls)
+	}
+}
+
+func TestBuildExportBundleProducesStableManifestHash(t *testing.T) {
+	exports := map[string][]byte{
+		"crm":   []byte(`{"a":1}`),
+		"audit": []byte(`{"b":2}`),
+	}
+
+	bundle1, hash1, err := buildExportBundle("cust-000004", exports)
+	if err != nil {
+		t.Fatalf("buildExportBundle failed: %v", err)
+	}
+	bundle2, hash2, err := buildExportBundle("cust-000004", exports)
+	if err != nil {
+		t.Fatalf("buildExportBundle failed: %v", err)
+	}
+
+	if hash1 != hash2 {
+		t.Errorf("expected the manifest hash to be stable across identical inputs, got %s vs %s", hash1, hash2)
+	}
+	if len(bundle1) == 0 || len(bundle2) == 0 {
+		t.Error("expected a non-empty ZIP bundle")
+	}
+}
+
+func TestDSRProcessorAlertIfDeadlineApproachingLogsWithinWindow(t *testing.T) {
+	p := NewDSRProcessor(1, nil, nil, nil, nil)
+	// No logger configured: this just exercises the early-return path
+	// rather than asserting on log content, since GDPRAuditLogger needs
+	// real file handles to construct.
+	p.alertIfDeadlineApproaching(DataSubjectRequest{
+		RequestID:  "DSR-test",
+		CustomerID: "cust-000005",
+		DeadlineAt: time.Now().Add(24 * time.Hour),
+	})
+}
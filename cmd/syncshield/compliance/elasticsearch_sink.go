@@ -0,0 +1,77 @@
+package compliance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// elasticsearchTimeout bounds one _bulk request.
+const elasticsearchTimeout = 5 * time.Second
+
+// ElasticsearchSink indexes each event via Elasticsearch's _bulk API - a
+// single raw HTTP request per Write rather than client-side batching,
+// since Write is already called from a per-sink worker goroutine that
+// naturally paces how often it fires; go.mod carries no Elasticsearch
+// client dependency.
+type ElasticsearchSink struct {
+	url        string // e.g. https://es.internal:9200
+	index      string
+	httpClient *http.Client
+}
+
+// NewElasticsearchSink creates an ElasticsearchSink indexing into index at
+// url.
+func NewElasticsearchSink(url, index string) *ElasticsearchSink {
+	return &ElasticsearchSink{url: url, index: index, httpClient: &http.Client{Timeout: elasticsearchTimeout}}
+}
+
+// Write implements AuditSink, indexing event with its EventID as the
+// document ID so a redelivered event after a retry overwrites rather than
+// duplicates.
+func (e *ElasticsearchSink) Write(event AuditEvent) error {
+	action, err := json.Marshal(map[string]interface{}{
+		"index": map[string]string{"_index": e.index, "_id": event.EventID},
+	})
+	if err != nil {
+		return err
+	}
+	doc, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+
+	var body bytes.Buffer
+	body.Write(action)
+	body.WriteByte('\n')
+	body.Write(doc)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequest(http.MethodPost, e.url+"/_bulk", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to elasticsearch _bulk: %w", err)
+	}
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body) // drain so the connection can be reused
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("elasticsearch _bulk returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Flush implements AuditSink. Each Write is already its own _bulk request.
+func (e *ElasticsearchSink) Flush() error { return nil }
+
+// Close implements AuditSink. The REST endpoint holds no per-sink
+// connection state to release.
+func (e *ElasticsearchSink) Close() error { return nil }
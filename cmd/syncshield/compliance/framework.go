@@ -0,0 +1,126 @@
+package compliance
+
+import "sync"
+
+// Control is a single control-catalog entry within a compliance Framework,
+// e.g. SOC 2 "CC6.1 - Logical Access Controls".
+type Control struct {
+	ID         string
+	Name       string
+	Required   bool     // counts toward the framework's compliance score
+	EventTypes []string // AuditEvent.EventType values that count as evidence
+}
+
+// ControlHit records that an AuditEvent was observed as evidence for a
+// specific control.
+type ControlHit struct {
+	ControlID string
+	EventID   string
+}
+
+// Framework is a pluggable compliance framework (GDPR, HIPAA, SOC 2, ...).
+// Frameworks register themselves into the Registry at init time so
+// GenerateComplianceReport never hardcodes which frameworks a deployment
+// enables.
+type Framework interface {
+	// ID is the framework's short, stable identifier, e.g. "SOC2".
+	ID() string
+	// Controls returns the framework's control catalog.
+	Controls() []Control
+	// Map returns the control hits an AuditEvent provides evidence for.
+	Map(event AuditEvent) []ControlHit
+	// ValidPurposes returns the PII-access purposes this framework allows,
+	// so ValidatePIIAccess isn't hardcoded to any one framework's policy.
+	ValidPurposes() map[string]bool
+}
+
+// Registry holds the set of compliance frameworks enabled for a deployment.
+type Registry struct {
+	mu         sync.RWMutex
+	frameworks map[string]Framework
+}
+
+// NewRegistry creates an empty framework registry.
+func NewRegistry() *Registry {
+	return &Registry{frameworks: make(map[string]Framework)}
+}
+
+// Register adds f to the registry. A later Register call with the same
+// Framework.ID() replaces the earlier one.
+func (r *Registry) Register(f Framework) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.frameworks[f.ID()] = f
+}
+
+// Get returns the framework registered under id, if any.
+func (r *Registry) Get(id string) (Framework, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.frameworks[id]
+	return f, ok
+}
+
+// Frameworks returns every registered framework, in no particular order.
+func (r *Registry) Frameworks() []Framework {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Framework, 0, len(r.frameworks))
+	for _, f := range r.frameworks {
+		out = append(out, f)
+	}
+	return out
+}
+
+// defaultRegistry is the process-wide registry built-in frameworks register
+// into. Deployments that want a custom set can build their own Registry and
+// ignore this one.
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry returns the process-wide compliance framework registry.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// RegisterFramework registers f into the default registry. Built-in
+// frameworks call this from their package init().
+func RegisterFramework(f Framework) {
+	defaultRegistry.Register(f)
+}
+
+// catalogFramework implements the common parts of Framework for a framework
+// whose controls are mapped purely from AuditEvent.EventType (or an explicit
+// Metadata["control"] tag, as ISO27001Controls already sets).
+type catalogFramework struct {
+	id            string
+	controls      []Control
+	validPurposes map[string]bool
+}
+
+func (c *catalogFramework) ID() string          { return c.id }
+func (c *catalogFramework) Controls() []Control { return c.controls }
+func (c *catalogFramework) ValidPurposes() map[string]bool {
+	return c.validPurposes
+}
+
+func (c *catalogFramework) Map(event AuditEvent) []ControlHit {
+	var hits []ControlHit
+	taggedControl, _ := event.Metadata["control"].(string)
+
+	for _, control := range c.controls {
+		if taggedControl == control.ID || containsString(control.EventTypes, event.EventType) {
+			hits = append(hits, ControlHit{ControlID: control.ID, EventID: event.EventID})
+		}
+	}
+	return hits
+}
+
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
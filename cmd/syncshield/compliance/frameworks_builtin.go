@@ -0,0 +1,120 @@
+package compliance
+
+// Built-in compliance frameworks. Each registers its control catalog into
+// the default Registry at init time, so GenerateComplianceReport and
+// ValidatePIIAccess never hardcode which frameworks a deployment enables.
+
+func init() {
+	RegisterFramework(&catalogFramework{
+		id: "GDPR",
+		controls: []Control{
+			{ID: "Art.7", Name: "Conditions for consent", Required: true, EventTypes: []string{"consent_change"}},
+			{ID: "Art.5", Name: "Data minimisation & retention", Required: true, EventTypes: []string{"data_deletion"}},
+			{ID: "Art.15", Name: "Right of access", Required: true, EventTypes: []string{"data_access"}},
+			{ID: "Art.17", Name: "Right to erasure", Required: true, EventTypes: []string{"data_deletion"}},
+			{ID: "Art.30", Name: "Records of processing activities", Required: true, EventTypes: []string{"data_access", "pii_access_validation"}},
+		},
+		validPurposes: map[string]bool{
+			"customer_support": true,
+			"fraud_detection":  true,
+			"compliance_audit": true,
+			"marketing":        false,
+		},
+	})
+
+	RegisterFramework(&catalogFramework{
+		id: "ISO27001",
+		controls: []Control{
+			{ID: "A.9.2.1", Name: "User registration and de-registration", Required: true, EventTypes: []string{"user_access"}},
+			{ID: "A.9.4.1", Name: "Information access restriction", Required: true, EventTypes: []string{"data_access"}},
+			{ID: "A.12.4.1", Name: "Event logging", Required: true, EventTypes: []string{"security_event"}},
+			{ID: "A.12.4.3", Name: "Administrator and operator logs", Required: true, EventTypes: []string{"admin_action"}},
+			{ID: "A.18.1.4", Name: "Privacy and protection of PII", Required: true, EventTypes: []string{"pii_access_validation"}},
+		},
+		validPurposes: map[string]bool{
+			"customer_support": true,
+			"fraud_detection":  true,
+			"compliance_audit": true,
+			"marketing":        false,
+		},
+	})
+
+	RegisterFramework(&catalogFramework{
+		id: "CCPA",
+		controls: []Control{
+			{ID: "1798.100", Name: "Consumer right to know", Required: true, EventTypes: []string{"data_access"}},
+			{ID: "1798.105", Name: "Consumer right to delete", Required: true, EventTypes: []string{"data_deletion"}},
+			{ID: "1798.120", Name: "Right to opt out of sale", Required: true, EventTypes: []string{"do_not_sell_request", "data_sale"}},
+		},
+		validPurposes: map[string]bool{
+			"customer_support": true,
+			"fraud_detection":  true,
+			"compliance_audit": true,
+			"marketing":        false,
+		},
+	})
+
+	RegisterFramework(&catalogFramework{
+		id: "HIPAA",
+		controls: []Control{
+			{ID: "164.312(a)(1)", Name: "Access control", Required: true, EventTypes: []string{"user_access", "pii_access_validation"}},
+			{ID: "164.312(b)", Name: "Audit controls", Required: true, EventTypes: []string{"security_event"}},
+			{ID: "164.312(c)(1)", Name: "Integrity", Required: true, EventTypes: []string{"data_access"}},
+			{ID: "164.308(a)(1)", Name: "Security management process", Required: true, EventTypes: []string{"admin_action"}},
+		},
+		validPurposes: map[string]bool{
+			"customer_support": true,
+			"fraud_detection":  true,
+			"compliance_audit": true,
+			"marketing":        false,
+			"treatment":        true,
+		},
+	})
+
+	RegisterFramework(&catalogFramework{
+		id: "SOC2",
+		controls: []Control{
+			{ID: "CC6.1", Name: "Logical access controls", Required: true, EventTypes: []string{"user_access", "pii_access_validation"}},
+			{ID: "CC6.6", Name: "Protection against external threats", Required: true, EventTypes: []string{"security_event"}},
+			{ID: "CC7.2", Name: "Monitors for anomalies", Required: true, EventTypes: []string{"admin_action"}},
+			{ID: "CC8.1", Name: "Change management", Required: false, EventTypes: []string{"admin_action"}},
+		},
+		validPurposes: map[string]bool{
+			"customer_support": true,
+			"fraud_detection":  true,
+			"compliance_audit": true,
+			"marketing":        false,
+		},
+	})
+
+	RegisterFramework(&catalogFramework{
+		id: "PCI-DSS",
+		controls: []Control{
+			{ID: "Req.7", Name: "Restrict access to cardholder data by business need to know", Required: true, EventTypes: []string{"data_access", "pii_access_validation"}},
+			{ID: "Req.10", Name: "Track and monitor all access to network resources and cardholder data", Required: true, EventTypes: []string{"security_event", "admin_action"}},
+			{ID: "Req.12", Name: "Maintain an information security policy", Required: false, EventTypes: []string{}},
+		},
+		validPurposes: map[string]bool{
+			"fraud_detection":  true,
+			"compliance_audit": true,
+			"customer_support": false,
+			"marketing":        false,
+		},
+	})
+
+	RegisterFramework(&catalogFramework{
+		id: "NIST800-53",
+		controls: []Control{
+			{ID: "AC-2", Name: "Account management", Required: true, EventTypes: []string{"user_access"}},
+			{ID: "AU-2", Name: "Audit events", Required: true, EventTypes: []string{"security_event"}},
+			{ID: "AU-6", Name: "Audit record review, analysis, and reporting", Required: true, EventTypes: []string{"admin_action"}},
+			{ID: "PM-20", Name: "Privacy impact and risk assessment", Required: false, EventTypes: []string{"pii_access_validation"}},
+		},
+		validPurposes: map[string]bool{
+			"customer_support": true,
+			"fraud_detection":  true,
+			"compliance_audit": true,
+			"marketing":        false,
+		},
+	})
+}
@@ -0,0 +1,176 @@
+package compliance
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ChainedAuditRecord is one newline-delimited JSON entry written by a
+// HashChainedAuditWriter. Hash covers PrevHash plus the canonical JSON of
+// every other field, so any edit or reordering of a past record - or a
+// truncated tail - breaks the chain at the point of tampering.
+type ChainedAuditRecord struct {
+	Timestamp time.Time `json:"ts"`
+	Decision  string    `json:"decision"`
+	LTV       float64   `json:"ltv"`
+	Reason    string    `json:"reason"`
+	PrevHash  string    `json:"prev_hash"`
+	Hash      string    `json:"hash"`
+}
+
+// HashChainedAuditWriter appends hash-chained bid-validation records to a
+// JSONL file. It's a lighter-weight sibling of the Merkle-batched auditChain
+// GDPRAuditLogger uses: just a flat SHA-256 chain, enough to make tampering
+// or truncation of shield_audit detectable without the signing/batching
+// machinery a regulator inclusion proof needs.
+type HashChainedAuditWriter struct {
+	mu       sync.Mutex
+	path     string
+	lastHash string
+}
+
+// NewHashChainedAuditWriter opens (creating if necessary) the JSONL file at
+// path and primes the chain from its last record, so a restarted process
+// keeps appending to the same chain instead of starting a new one.
+func NewHashChainedAuditWriter(path string) (*HashChainedAuditWriter, error) {
+	last, err := readLastChainedRecord(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &HashChainedAuditWriter{path: path}
+	if last != nil {
+		w.lastHash = last.Hash
+	}
+	return w, nil
+}
+
+// Append writes one hash-chained record linking it to the previous record's
+// hash.
+func (w *HashChainedAuditWriter) Append(decision string, ltv float64, reason string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	rec := ChainedAuditRecord{
+		Timestamp: time.Now(),
+		Decision:  decision,
+		LTV:       ltv,
+		Reason:    reason,
+		PrevHash:  w.lastHash,
+	}
+	hash, err := hashChainedRecord(rec)
+	if err != nil {
+		return err
+	}
+	rec.Hash = hash
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hash-chained audit record: %w", err)
+	}
+
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open hash-chained audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write hash-chained audit record: %w", err)
+	}
+
+	w.lastHash = hash
+	return nil
+}
+
+// hashChainedRecord computes sha256(prevHash || canonicalJSON(record with
+// Hash cleared)), matching the layout Verify recomputes against.
+func hashChainedRecord(rec ChainedAuditRecord) (string, error) {
+	rec.Hash = ""
+	canonical, err := json.Marshal(rec)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize hash-chained audit record: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(rec.PrevHash), canonical...))
+	return fmt.Sprintf("%x", sum), nil
+}
+
+// Verify replays the hash-chained audit log at path and returns the
+// 1-indexed line number of the first record that fails to verify - either
+// its prev_hash doesn't match the preceding record's hash, or its hash
+// doesn't match its own recomputed content - or 0 if every record in the
+// file is intact. A truncated or corrupt final line counts as the first bad
+// line rather than an error.
+func Verify(path string) (firstBadLine int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open hash-chained audit log: %w", err)
+	}
+	defer f.Close()
+
+	prevHash := ""
+	lineNo := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec ChainedAuditRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return lineNo, nil
+		}
+		if rec.PrevHash != prevHash {
+			return lineNo, nil
+		}
+		want, err := hashChainedRecord(rec)
+		if err != nil {
+			return 0, err
+		}
+		if want != rec.Hash {
+			return lineNo, nil
+		}
+		prevHash = rec.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read hash-chained audit log: %w", err)
+	}
+	return 0, nil
+}
+
+func readLastChainedRecord(path string) (*ChainedAuditRecord, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hash-chained audit log: %w", err)
+	}
+	defer f.Close()
+
+	var last *ChainedAuditRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec ChainedAuditRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("failed to parse hash-chained audit log entry: %w", err)
+		}
+		r := rec
+		last = &r
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read hash-chained audit log: %w", err)
+	}
+	return last, nil
+}
@@ -0,0 +1,120 @@
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHashChainedAuditWriter_VerifyDetectsNoTamperOnCleanLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit_chain.jsonl")
+	w, err := NewHashChainedAuditWriter(path)
+	if err != nil {
+		t.Fatalf("NewHashChainedAuditWriter failed: %v", err)
+	}
+
+	if err := w.Append("VETO", 5.0, "Below minimum threshold"); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := w.Append("APPROVED", 50.0, "Within safe parameters"); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	firstBadLine, err := Verify(path)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if firstBadLine != 0 {
+		t.Fatalf("expected no tamper, got firstBadLine=%d", firstBadLine)
+	}
+}
+
+func TestHashChainedAuditWriter_VerifyDetectsTamperedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit_chain.jsonl")
+	w, err := NewHashChainedAuditWriter(path)
+	if err != nil {
+		t.Fatalf("NewHashChainedAuditWriter failed: %v", err)
+	}
+	if err := w.Append("VETO", 5.0, "Below minimum threshold"); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := w.Append("APPROVED", 50.0, "Within safe parameters"); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	tampered := []byte(strings.Replace(string(raw), `"VETO"`, `"APPROVED"`, 1))
+	if err := os.WriteFile(path, tampered, 0600); err != nil {
+		t.Fatalf("failed to write tampered log: %v", err)
+	}
+
+	firstBadLine, err := Verify(path)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if firstBadLine != 1 {
+		t.Fatalf("expected tamper detected at line 1, got %d", firstBadLine)
+	}
+}
+
+func TestHashChainedAuditWriter_VerifyDetectsTruncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit_chain.jsonl")
+	w, err := NewHashChainedAuditWriter(path)
+	if err != nil {
+		t.Fatalf("NewHashChainedAuditWriter failed: %v", err)
+	}
+	if err := w.Append("VETO", 5.0, "Below minimum threshold"); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := w.Append("APPROVED", 50.0, "Within safe parameters"); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	lines := strings.SplitN(string(raw), "\n", 2)
+	if err := os.WriteFile(path, []byte(lines[1]), 0600); err != nil {
+		t.Fatalf("failed to write truncated log: %v", err)
+	}
+
+	firstBadLine, err := Verify(path)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if firstBadLine != 1 {
+		t.Fatalf("expected tamper detected at line 1, got %d", firstBadLine)
+	}
+}
+
+func TestHashChainedAuditWriter_ResumesChainAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit_chain.jsonl")
+	w1, err := NewHashChainedAuditWriter(path)
+	if err != nil {
+		t.Fatalf("NewHashChainedAuditWriter failed: %v", err)
+	}
+	if err := w1.Append("VETO", 5.0, "Below minimum threshold"); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	w2, err := NewHashChainedAuditWriter(path)
+	if err != nil {
+		t.Fatalf("NewHashChainedAuditWriter (resume) failed: %v", err)
+	}
+	if err := w2.Append("APPROVED", 50.0, "Within safe parameters"); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	firstBadLine, err := Verify(path)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if firstBadLine != 0 {
+		t.Fatalf("expected no tamper across a restarted writer, got firstBadLine=%d", firstBadLine)
+	}
+}
@@ -0,0 +1,75 @@
+package compliance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// kafkaRESTTimeout bounds one produce request.
+const kafkaRESTTimeout = 5 * time.Second
+
+// KafkaSink publishes each event as its own Kafka message, keyed by
+// EventID so downstream consumers can look up a single event directly
+// rather than scanning a partition. Like audit.KafkaSink, it talks to a
+// Confluent REST Proxy instead of a native client, since go.mod carries no
+// Kafka client dependency and the repo's established convention is to
+// avoid adding a heavyweight one when an HTTP-based integration covers the
+// same need.
+type KafkaSink struct {
+	proxyURL   string
+	topic      string
+	httpClient *http.Client
+}
+
+// NewKafkaSink creates a KafkaSink producing to <proxyURL>/topics/<topic>.
+func NewKafkaSink(proxyURL, topic string) *KafkaSink {
+	return &KafkaSink{proxyURL: proxyURL, topic: topic, httpClient: &http.Client{Timeout: kafkaRESTTimeout}}
+}
+
+type kafkaEventRecord struct {
+	Key   string     `json:"key"`
+	Value AuditEvent `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaEventRecord `json:"records"`
+}
+
+// Write implements AuditSink.
+func (k *KafkaSink) Write(event AuditEvent) error {
+	body, err := json.Marshal(kafkaProduceRequest{Records: []kafkaEventRecord{{Key: event.EventID, Value: event}}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal kafka record: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/topics/%s", k.proxyURL, k.topic)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to kafka rest proxy: %w", err)
+	}
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body) // drain so the connection can be reused
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("kafka rest proxy returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Flush implements AuditSink. Each Write is already its own request.
+func (k *KafkaSink) Flush() error { return nil }
+
+// Close implements AuditSink. The REST proxy holds no per-sink connection
+// state to release.
+func (k *KafkaSink) Close() error { return nil }
@@ -0,0 +1,15 @@
+package compliance
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+// NewLogger builds a structured zerolog.Logger writing JSON lines to
+// stderr at level, tagged with component="compliance". Pass
+// zerolog.Nop() to ConsentManager/DataSubjectRequestManager in tests
+// where log output isn't asserted on.
+func NewLogger(level zerolog.Level) zerolog.Logger {
+	return zerolog.New(os.Stderr).Level(level).With().Timestamp().Str("component", "compliance").Logger()
+}
@@ -0,0 +1,212 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// AuditSink is an additional destination LogEvent fans events out to,
+// alongside the CSV/JSON/hash-chain log every GDPRAuditLogger always
+// writes. A sink is best-effort from LogEvent's point of view: a slow or
+// unreachable sink never blocks LogEvent beyond the queueing described on
+// RegisterSink, and never makes LogEvent itself return an error.
+type AuditSink interface {
+	// Write persists a single event. LogEvent has already set defaults and
+	// hashed PII fields by the time a sink sees the event.
+	Write(event AuditEvent) error
+
+	// Flush forces any buffering inside the sink out to its destination.
+	Flush() error
+
+	// Close releases the sink's resources. Called once its queue has
+	// drained, during GDPRAuditLogger.Close.
+	Close() error
+}
+
+// auditLevelRank orders AuditLevel from least to most severe, so a
+// SinkRegistration.MinLevel can filter routine INFO/WARNING traffic out of
+// an expensive SIEM sink while still streaming SECURITY/CRITICAL events to
+// it.
+func auditLevelRank(level AuditLevel) int {
+	switch level {
+	case LevelInfo:
+		return 0
+	case LevelWarning:
+		return 1
+	case LevelSecurity:
+		return 2
+	case LevelCritical:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// sinkQueueDepth bounds each sink's pending-event channel. enqueueToSinks
+// blocks the caller once a sink falls this far behind rather than dropping
+// events, unlike StreamSink's drop-oldest subscribers - a compliance event
+// silently lost under load defeats the point of an audit log.
+const sinkQueueDepth = 256
+
+// DeadLetterSink records an event a sink could not deliver after
+// exhausting its retries, so operators can inspect or replay it instead of
+// it simply vanishing.
+type DeadLetterSink interface {
+	Record(sinkName string, event AuditEvent, err error)
+
+	// Close releases the dead letter's resources. Called once during
+	// GDPRAuditLogger.Close, after every sink worker has drained.
+	Close() error
+}
+
+// SinkRegistration wires one AuditSink into a GDPRAuditLogger's fan-out.
+type SinkRegistration struct {
+	// Name identifies the sink in dead-letter records and log lines.
+	Name string
+	Sink AuditSink
+
+	// MinLevel filters events below this severity out before they reach
+	// Sink; the zero value (LevelInfo) streams everything.
+	MinLevel AuditLevel
+
+	// RetryPolicy governs Write retries; nil falls back to
+	// shield.DefaultRetryPolicy().
+	RetryPolicy *shield.RetryPolicy
+}
+
+// sinkWorker owns one registered sink's queue and retry loop. mu guards
+// closed so enqueue and closeQueue can never race: a send that has
+// already passed the closed check is guaranteed to land before
+// closeQueue closes the channel, instead of panicking on a send to a
+// closed channel.
+type sinkWorker struct {
+	reg   SinkRegistration
+	queue chan AuditEvent
+	done  chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// enqueue sends event to w.queue, blocking if it's full, unless the worker
+// has already been closed (in which case event is silently dropped - the
+// logger is shutting down and there is no one left to deliver it to).
+func (w *sinkWorker) enqueue(event AuditEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	w.queue <- event
+}
+
+// closeQueue marks w closed and closes its queue, waking runSinkWorker's
+// range loop. Must only be called once per worker.
+func (w *sinkWorker) closeQueue() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	close(w.queue)
+}
+
+// RegisterSink starts reg's async worker and returns immediately. Events
+// matching reg.MinLevel are queued to it as LogEvent fans them out via
+// enqueueToSinks.
+func (g *GDPRAuditLogger) RegisterSink(reg SinkRegistration) {
+	w := &sinkWorker{
+		reg:   reg,
+		queue: make(chan AuditEvent, sinkQueueDepth),
+		done:  make(chan struct{}),
+	}
+
+	g.sinkMu.Lock()
+	g.sinkWorkers = append(g.sinkWorkers, w)
+	g.sinkMu.Unlock()
+
+	go g.runSinkWorker(w)
+}
+
+// runSinkWorker drains w.queue until it's closed, writing each event to
+// w.reg.Sink under w.reg.RetryPolicy (or shield.DefaultRetryPolicy) and
+// routing exhausted retries to g.DeadLetter rather than dropping the
+// event.
+func (g *GDPRAuditLogger) runSinkWorker(w *sinkWorker) {
+	defer close(w.done)
+
+	policy := w.reg.RetryPolicy
+	if policy == nil {
+		policy = shield.DefaultRetryPolicy()
+	}
+
+	for event := range w.queue {
+		_, _, err := policy.ExecuteWithRetry(context.Background(), func(ctx context.Context, attempt int) (interface{}, error) {
+			return nil, w.reg.Sink.Write(event)
+		}, shield.DefaultIsRetryable)
+
+		if err != nil {
+			fmt.Printf("⚠️  audit sink %q exhausted retries, routing to dead letter: %v\n", w.reg.Name, err)
+			if g.DeadLetter != nil {
+				g.DeadLetter.Record(w.reg.Name, event, err)
+			}
+		}
+	}
+
+	if err := w.reg.Sink.Flush(); err != nil {
+		fmt.Printf("⚠️  audit sink %q failed final flush: %v\n", w.reg.Name, err)
+	}
+	if err := w.reg.Sink.Close(); err != nil {
+		fmt.Printf("⚠️  audit sink %q failed to close: %v\n", w.reg.Name, err)
+	}
+}
+
+// enqueueToSinks fans event out to every registered sink whose MinLevel
+// admits it, blocking until each sink's queue has room. Sinks are
+// delivered to concurrently, so one sink stalled on a full queue never
+// head-of-line-blocks delivery to the others; enqueueToSinks itself still
+// blocks until every eligible sink has accepted the event. LogEvent calls
+// this after releasing g.mu, so a backed-up sink slows new events but
+// never holds the CSV/JSON/hash-chain write hostage.
+func (g *GDPRAuditLogger) enqueueToSinks(event AuditEvent) {
+	g.sinkMu.Lock()
+	workers := make([]*sinkWorker, len(g.sinkWorkers))
+	copy(workers, g.sinkWorkers)
+	g.sinkMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		if auditLevelRank(event.Level) < auditLevelRank(w.reg.MinLevel) {
+			continue
+		}
+		wg.Add(1)
+		go func(w *sinkWorker) {
+			defer wg.Done()
+			w.enqueue(event)
+		}(w)
+	}
+	wg.Wait()
+}
+
+// closeSinks closes every registered sink's queue and waits for it to
+// drain, flush, and close the underlying sink, running all sinks'
+// shutdowns concurrently so Close's latency is the slowest sink's, not
+// their sum.
+func (g *GDPRAuditLogger) closeSinks() {
+	g.sinkMu.Lock()
+	workers := make([]*sinkWorker, len(g.sinkWorkers))
+	copy(workers, g.sinkWorkers)
+	g.sinkMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w *sinkWorker) {
+			defer wg.Done()
+			w.closeQueue()
+			<-w.done
+		}(w)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,119 @@
+package compliance
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CSVSink is an AuditSink writing events as CSV rows, using the same
+// column layout GDPRAuditLogger's built-in CSV writer uses. Registering
+// one alongside the logger's always-on CSV file is only useful for
+// mirroring events to a second location (e.g. a shared volume a SIEM
+// tails); it duplicates, rather than replaces, LogEvent's own write.
+type CSVSink struct {
+	mu     sync.Mutex
+	file   *os.File
+	writer *csv.Writer
+}
+
+// NewCSVSink opens (or creates) path and returns a CSVSink appending to it.
+func NewCSVSink(path string) (*CSVSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV sink file: %w", err)
+	}
+	writer := csv.NewWriter(file)
+
+	if info, _ := file.Stat(); info != nil && info.Size() == 0 {
+		writer.Write(auditCSVColumns)
+		writer.Flush()
+	}
+
+	return &CSVSink{file: file, writer: writer}, nil
+}
+
+// Write implements AuditSink.
+func (s *CSVSink) Write(event AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dataAccessed := ""
+	if len(event.DataAccessed) > 0 {
+		dataAccessed = fmt.Sprintf("%v", event.DataAccessed)
+	}
+	return s.writer.Write([]string{
+		event.Timestamp.Format(time.RFC3339),
+		event.EventID,
+		string(event.Level),
+		event.EventType,
+		event.UserID,
+		event.CustomerID,
+		event.Action,
+		event.Resource,
+		event.Outcome,
+		event.Reason,
+		event.IPAddress,
+		dataAccessed,
+		fmt.Sprintf("%d", event.RetentionDays),
+	})
+}
+
+// Flush implements AuditSink.
+func (s *CSVSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writer.Flush()
+	return s.writer.Error()
+}
+
+// Close implements AuditSink.
+func (s *CSVSink) Close() error {
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	return s.file.Close()
+}
+
+// JSONLinesSink is an AuditSink writing one JSON object per line, matching
+// GDPRAuditLogger's own built-in JSON archive format.
+type JSONLinesSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewJSONLinesSink opens (or creates) path and returns a JSONLinesSink
+// appending to it.
+func NewJSONLinesSink(path string) (*JSONLinesSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JSON lines sink file: %w", err)
+	}
+	return &JSONLinesSink{file: file}, nil
+}
+
+// Write implements AuditSink.
+func (s *JSONLinesSink) Write(event AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+// Flush implements AuditSink. Each Write is already an unbuffered append.
+func (s *JSONLinesSink) Flush() error { return nil }
+
+// Close implements AuditSink.
+func (s *JSONLinesSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
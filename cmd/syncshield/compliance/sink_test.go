@@ -0,0 +1,142 @@
+package compliance
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// fakeSink is an in-memory AuditSink recording every event it receives,
+// optionally failing every Write so tests can exercise retry/dead-letter
+// behavior without a real network dependency.
+type fakeSink struct {
+	mu       sync.Mutex
+	events   []AuditEvent
+	failWith error
+}
+
+func (f *fakeSink) Write(event AuditEvent) error {
+	if f.failWith != nil {
+		return f.failWith
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeSink) Flush() error { return nil }
+func (f *fakeSink) Close() error { return nil }
+
+func (f *fakeSink) recorded() []AuditEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]AuditEvent, len(f.events))
+	copy(out, f.events)
+	return out
+}
+
+// fakeDeadLetter is an in-memory DeadLetterSink for assertions.
+type fakeDeadLetter struct {
+	mu      sync.Mutex
+	records []string
+}
+
+func (d *fakeDeadLetter) Record(sinkName string, event AuditEvent, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.records = append(d.records, sinkName)
+}
+
+func (d *fakeDeadLetter) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.records)
+}
+
+func (d *fakeDeadLetter) Close() error { return nil }
+
+func TestGDPRAuditLogger_RegisterSinkReceivesMatchingEvents(t *testing.T) {
+	logger := newTestLogger(t)
+	sink := &fakeSink{}
+	logger.RegisterSink(SinkRegistration{Name: "test-sink", Sink: sink})
+
+	if err := logger.LogBidValidation("cust-1", 42, "APPROVED", "ok"); err != nil {
+		t.Fatalf("LogBidValidation failed: %v", err)
+	}
+	logger.Close()
+
+	events := sink.recorded()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event delivered to the sink, got %d", len(events))
+	}
+	if events[0].EventType != "bid_validation" {
+		t.Fatalf("expected the bid_validation event, got %+v", events[0])
+	}
+}
+
+func TestGDPRAuditLogger_RegisterSinkFiltersBelowMinLevel(t *testing.T) {
+	logger := newTestLogger(t)
+	sink := &fakeSink{}
+	logger.RegisterSink(SinkRegistration{Name: "security-only", Sink: sink, MinLevel: LevelSecurity})
+
+	if err := logger.LogBidValidation("cust-1", 42, "APPROVED", "ok"); err != nil { // LevelInfo
+		t.Fatalf("LogBidValidation failed: %v", err)
+	}
+	if err := logger.LogDataAccess("user-1", "cust-1", []string{"email"}, "support"); err != nil { // LevelSecurity
+		t.Fatalf("LogDataAccess failed: %v", err)
+	}
+	logger.Close()
+
+	events := sink.recorded()
+	if len(events) != 1 || events[0].EventType != "data_access" {
+		t.Fatalf("expected only the data_access event past the MinLevel filter, got %+v", events)
+	}
+}
+
+func TestGDPRAuditLogger_RegisterSinkRoutesExhaustedRetriesToDeadLetter(t *testing.T) {
+	logger := newTestLogger(t)
+	deadLetter := &fakeDeadLetter{}
+	logger.DeadLetter = deadLetter
+
+	sink := &fakeSink{failWith: fmt.Errorf("endpoint unreachable")}
+	logger.RegisterSink(SinkRegistration{
+		Name: "down-sink",
+		Sink: sink,
+		RetryPolicy: &shield.RetryPolicy{
+			MaxAttempts:    1,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+		},
+	})
+
+	if err := logger.LogBidValidation("cust-1", 42, "APPROVED", "ok"); err != nil {
+		t.Fatalf("LogBidValidation failed: %v", err)
+	}
+	logger.Close()
+
+	if deadLetter.count() != 1 {
+		t.Fatalf("expected the exhausted-retry event to reach the dead letter, got %d records", deadLetter.count())
+	}
+}
+
+func TestAuditLevelRank_OrdersBySeverity(t *testing.T) {
+	if !(auditLevelRank(LevelInfo) < auditLevelRank(LevelWarning) &&
+		auditLevelRank(LevelWarning) < auditLevelRank(LevelSecurity) &&
+		auditLevelRank(LevelSecurity) < auditLevelRank(LevelCritical)) {
+		t.Fatalf("expected INFO < WARNING < SECURITY < CRITICAL, got %d/%d/%d/%d",
+			auditLevelRank(LevelInfo), auditLevelRank(LevelWarning),
+			auditLevelRank(LevelSecurity), auditLevelRank(LevelCritical))
+	}
+}
+
+func TestCEFEscape_EscapesReservedDelimiters(t *testing.T) {
+	got := cefEscape(`a|b=c\d`)
+	want := `a\|b\=c\\d`
+	if got != want {
+		t.Fatalf("cefEscape(%q) = %q, want %q", `a|b=c\d`, got, want)
+	}
+}
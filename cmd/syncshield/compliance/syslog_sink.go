@@ -0,0 +1,111 @@
+package compliance
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// syslogFacilityLocal4 is the syslog facility (local4, 20) most SIEM
+// connectors (ArcSight, Splunk, QRadar) expect a CEF feed to arrive on.
+const syslogFacilityLocal4 = 20
+
+// SyslogCEFSink streams events as RFC 5424 syslog messages carrying a CEF
+// (Common Event Format) payload - the format most SIEMs parse out of the
+// box, avoiding a bespoke ingestion pipeline on the SIEM side.
+type SyslogCEFSink struct {
+	appName string
+	conn    net.Conn
+}
+
+// NewSyslogCEFSink dials a syslog collector at addr (network is "udp" or
+// "tcp") and streams every Write as a CEF-formatted syslog message tagged
+// with appName.
+func NewSyslogCEFSink(network, addr, appName string) (*SyslogCEFSink, error) {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial syslog collector: %w", err)
+	}
+	return &SyslogCEFSink{appName: appName, conn: conn}, nil
+}
+
+// Write implements AuditSink, sending one RFC 5424 message per event.
+func (s *SyslogCEFSink) Write(event AuditEvent) error {
+	msg := fmt.Sprintf("<%d>1 %s %s %s - %s - %s\n",
+		syslogFacilityLocal4*8+syslogSeverity(event.Level),
+		event.Timestamp.UTC().Format(time.RFC3339),
+		hostnameOrUnknown(),
+		s.appName,
+		event.EventID,
+		cefPayload(event),
+	)
+	_, err := s.conn.Write([]byte(msg))
+	return err
+}
+
+// Flush implements AuditSink. Each Write is already an unbuffered send.
+func (s *SyslogCEFSink) Flush() error { return nil }
+
+// Close implements AuditSink.
+func (s *SyslogCEFSink) Close() error { return s.conn.Close() }
+
+// syslogSeverity maps an AuditLevel to its closest RFC 5424 severity.
+func syslogSeverity(level AuditLevel) int {
+	switch level {
+	case LevelCritical:
+		return 2 // Critical
+	case LevelSecurity:
+		return 3 // Error
+	case LevelWarning:
+		return 4 // Warning
+	default:
+		return 6 // Informational
+	}
+}
+
+// cefSeverity maps an AuditLevel onto CEF's 0-10 severity scale.
+func cefSeverity(level AuditLevel) int {
+	switch level {
+	case LevelCritical:
+		return 10
+	case LevelSecurity:
+		return 7
+	case LevelWarning:
+		return 5
+	default:
+		return 3
+	}
+}
+
+// cefPayload renders event as a CEF:0 record.
+func cefPayload(event AuditEvent) string {
+	extension := fmt.Sprintf(
+		"rt=%d suser=%s duser=%s act=%s outcome=%s reason=%s src=%s cs1Label=Resource cs1=%s",
+		event.Timestamp.UnixMilli(),
+		cefEscape(event.UserID),
+		cefEscape(event.CustomerID),
+		cefEscape(event.Action),
+		cefEscape(event.Outcome),
+		cefEscape(event.Reason),
+		cefEscape(event.IPAddress),
+		cefEscape(event.Resource),
+	)
+	return fmt.Sprintf("CEF:0|kiki-agent|SyncShield|1.0|%s|%s|%d|%s",
+		cefEscape(event.EventType), cefEscape(event.EventType), cefSeverity(event.Level), extension)
+}
+
+// cefEscape escapes the pipe and equals characters CEF reserves as field
+// and key/value delimiters, per the CEF specification.
+func cefEscape(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "|", "\\|", "=", "\\=").Replace(s)
+}
+
+func hostnameOrUnknown() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return host
+}
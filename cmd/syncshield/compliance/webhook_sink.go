@@ -0,0 +1,60 @@
+package compliance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds one POST - the same conservative per-call budget
+// the audit package's HTTP-based sinks use.
+const webhookTimeout = 5 * time.Second
+
+// WebhookSink posts each event as a JSON body to an HTTP endpoint - the
+// simplest integration path for a downstream consumer that doesn't speak
+// syslog, Elasticsearch, or Kafka.
+type WebhookSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookSink creates a WebhookSink posting to url.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, httpClient: &http.Client{Timeout: webhookTimeout}}
+}
+
+// Write implements AuditSink.
+func (w *WebhookSink) Write(event AuditEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post audit event to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body) // drain so the connection can be reused
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Flush implements AuditSink. Each Write is already its own request.
+func (w *WebhookSink) Flush() error { return nil }
+
+// Close implements AuditSink. The endpoint holds no per-sink connection
+// state to release.
+func (w *WebhookSink) Close() error { return nil }
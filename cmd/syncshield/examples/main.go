@@ -25,6 +25,11 @@ func main() {
 		DemoBudgeter()
 		fmt.Println()
 		DemoLTVMomentum()
+	case "reconcile":
+		if err := runReconcile(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "reconcile: %v\n", err)
+			os.Exit(1)
+		}
 	default:
 		fmt.Printf("Unknown demo: %s\n", demo)
 		printUsage()
@@ -37,13 +42,16 @@ func printUsage() {
 	fmt.Println("Usage: go run . [demo]")
 	fmt.Println()
 	fmt.Println("Demos:")
-	fmt.Println("  budget   - Sliding Window Budgeter (burst protection)")
-	fmt.Println("  metrics  - LTV Momentum Tracking (real-time dashboard)")
-	fmt.Println("  all      - Run all demos")
+	fmt.Println("  budget    - Sliding Window Budgeter (burst protection)")
+	fmt.Println("  metrics   - LTV Momentum Tracking (real-time dashboard)")
+	fmt.Println("  all       - Run all demos")
+	fmt.Println("  reconcile - Spend ledger reconciliation (find-lca, rewind)")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  go run . budget")
 	fmt.Println("  go run . metrics")
 	fmt.Println("  go run . all")
+	fmt.Println("  go run . reconcile find-lca --platform=meta --reporting-url=https://reporting.example.com")
+	fmt.Println("  go run . reconcile rewind --platform=meta --after=BID_123 --max-budget=1000")
 	fmt.Println()
 }
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/user/kiki-agent/cmd/syncflow/connectors"
+	"github.com/user/kiki-agent/cmd/syncshield/shield"
+)
+
+// runReconcile dispatches the "reconcile" demo's own subcommands, mirroring
+// kiki-ca's flag.NewFlagSet-per-subcommand pattern rather than the
+// budget/metrics demos' single Demo* entry point - find-lca and rewind take
+// enough distinct flags that folding them into one case statement would be
+// unreadable.
+func runReconcile(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: reconcile find-lca|rewind [flags]")
+	}
+	switch args[0] {
+	case "find-lca":
+		return runReconcileFindLCA(args[1:])
+	case "rewind":
+		return runReconcileRewind(args[1:])
+	default:
+		return fmt.Errorf("unknown reconcile subcommand: %s", args[0])
+	}
+}
+
+// openSpendLedger opens the Postgres-backed SpendLedger every syncflow
+// connector shares, using the same AUDIT_POSTGRES_DSN env var
+// cmd/syncflow/main.go's initReconcile already reads for reconcile.PostgresStore
+// and audit.NewAuditLogger - a bid's ledger row lives in the same database as
+// the rest of syncflow's durable state.
+func openSpendLedger() (*connectors.PostgresSpendLedger, error) {
+	dsn := os.Getenv("AUDIT_POSTGRES_DSN")
+	if dsn == "" {
+		return nil, fmt.Errorf("AUDIT_POSTGRES_DSN is not set - reconcile needs it to reach the spend ledger")
+	}
+	return connectors.NewPostgresSpendLedger(dsn)
+}
+
+// httpReportingFetcher confirms a bid against a platform's reporting API over
+// HTTP: GET baseURL/platform/bids/bidID, expecting a JSON body of
+// {"confirmed": true|false}. It's deliberately generic rather than
+// per-platform - no *_smart.go connector exposes a reporting-read client of
+// its own yet, so reconcile talks to whatever reporting endpoint the
+// operator points --reporting-url at.
+type httpReportingFetcher struct {
+	baseURL string
+	client  *http.Client
+}
+
+// Confirmed implements connectors.ReportingFetcher.
+func (f *httpReportingFetcher) Confirmed(ctx context.Context, platform, bidID string) (bool, error) {
+	url := fmt.Sprintf("%s/%s/bids/%s", f.baseURL, platform, bidID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("reporting API returned status %d for bid %s", resp.StatusCode, bidID)
+	}
+	var body struct {
+		Confirmed bool `json:"confirmed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, fmt.Errorf("decoding reporting API response for bid %s: %w", bidID, err)
+	}
+	return body.Confirmed, nil
+}
+
+// runReconcileFindLCA walks platform's spend ledger back from the most
+// recent entry, asking --reporting-url to confirm each one, and prints the
+// most recent bid ID both sides agree on - the point a later "rewind" should
+// pass as --after.
+func runReconcileFindLCA(args []string) error {
+	fs := flag.NewFlagSet("find-lca", flag.ExitOnError)
+	platform := fs.String("platform", "", "platform whose spend ledger to reconcile (required)")
+	reportingURL := fs.String("reporting-url", "", "base URL of the platform's reporting API, e.g. https://reporting.example.com (required)")
+	limit := fs.Int("limit", 50, "how many of the most recent ledger entries to check against the reporting API")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *platform == "" || *reportingURL == "" {
+		return fmt.Errorf("--platform and --reporting-url are required")
+	}
+
+	ledger, err := openSpendLedger()
+	if err != nil {
+		return err
+	}
+	defer ledger.Close()
+
+	fetcher := &httpReportingFetcher{baseURL: *reportingURL, client: &http.Client{Timeout: 10 * time.Second}}
+
+	bidID, found, err := connectors.FindLCA(context.Background(), ledger, fetcher, *platform, *limit)
+	if err != nil {
+		return fmt.Errorf("find-lca: %w", err)
+	}
+	if !found {
+		fmt.Printf("⚠️ No confirmed bid among the last %d %s ledger entries - widen --limit and retry\n", *limit, *platform)
+		return nil
+	}
+	fmt.Printf("✅ Last confirmed %s bid: %s\n", *platform, bidID)
+	fmt.Printf("   go run . reconcile rewind --platform=%s --after=%s\n", *platform, bidID)
+	return nil
+}
+
+// runReconcileRewind requeues every ledgered bid newer than --after onto
+// the platform's ResumableBidder queue via connectors.EnqueueForRecovery,
+// so Drain retries them once that platform's circuit is back CLOSED, and
+// corrects spend tracking for those diverged bids.
+//
+// With --admin-url set, correction happens for real: it POSTs to the
+// running syncflow instance's /admin/reconcile/rewind (see
+// cmd/syncflow/admin_reconcile.go's serveRewind), which rolls back that
+// process's actual live BudgetManager. Without it, rewind only computes
+// what the corrected spend *would* be against a BudgetManager built just
+// for this invocation - nothing a real bidding process reads from, so its
+// printed CurrentSpend is informational only.
+func runReconcileRewind(args []string) error {
+	fs := flag.NewFlagSet("rewind", flag.ExitOnError)
+	platform := fs.String("platform", "", "platform to rewind (required)")
+	after := fs.String("after", "", "bid ID both sides agree on, from reconcile find-lca (required)")
+	maxBudget := fs.Float64("max-budget", 0, "platform's BudgetManager max burst budget - only used without --admin-url, to get a meaningful local GetCurrentSpend")
+	adminURL := fs.String("admin-url", "", "base URL of the running syncflow instance, e.g. http://localhost:8084 - if set, rewinds that process's live BudgetManager instead of only computing locally what rewinding it would do")
+	redisAddr := fs.String("redis-addr", os.Getenv("REDIS_ADDR"), "Redis address to requeue diverged bids onto, defaults to $REDIS_ADDR")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *platform == "" || *after == "" {
+		return fmt.Errorf("--platform and --after are required")
+	}
+
+	ctx := context.Background()
+	var diverged []connectors.LedgerEntry
+	if *adminURL != "" {
+		var err error
+		diverged, err = remoteRewind(ctx, *adminURL, *platform, *after)
+		if err != nil {
+			return fmt.Errorf("rewind: %w", err)
+		}
+	} else {
+		ledger, err := openSpendLedger()
+		if err != nil {
+			return err
+		}
+		defer ledger.Close()
+
+		bm := shield.NewBudgetManager(*maxBudget)
+		diverged, err = connectors.Rewind(ctx, ledger, bm, *platform, *after)
+		if err != nil {
+			return fmt.Errorf("rewind: %w", err)
+		}
+		fmt.Printf("🔁 Computed %s rewind to just after bid %s (no --admin-url - this did NOT touch any running process's live spend)\n", *platform, *after)
+		fmt.Printf("   %d diverged bid(s), locally-computed CurrentSpend: $%.2f\n", len(diverged), bm.GetCurrentSpend())
+	}
+
+	if len(diverged) == 0 {
+		return nil
+	}
+	if *redisAddr == "" {
+		fmt.Println("   ⚠️ REDIS_ADDR not set - diverged bids were NOT requeued, requeue them by hand")
+		return nil
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: *redisAddr})
+	defer rdb.Close()
+	for _, entry := range diverged {
+		req := &connectors.BidRequest{
+			CustomerID: entry.CustomerID,
+			CampaignID: entry.CampaignID,
+			AudienceID: entry.AudienceID,
+			BidAmount:  entry.Amount,
+			Timestamp:  entry.PlacedAt,
+		}
+		if err := connectors.EnqueueForRecovery(ctx, rdb, *platform, req, connectors.BidOptions{}); err != nil {
+			return fmt.Errorf("requeue diverged bid %s: %w", entry.BidID, err)
+		}
+	}
+	fmt.Printf("   requeued %d diverged bid(s) onto %s for that platform's ResumableBidder.Drain to retry\n", len(diverged), connectors.QueueKeyFor(*platform))
+	return nil
+}
+
+// remoteRewind POSTs to a running syncflow instance's
+// /admin/reconcile/rewind, which rewinds its connector's actual live
+// BudgetManager - see cmd/syncflow/admin_reconcile.go's serveRewind - and
+// reports the result back the same way runReconcileFindLCA's
+// httpReportingFetcher talks to a platform's reporting API.
+func remoteRewind(ctx context.Context, adminURL, platform, after string) ([]connectors.LedgerEntry, error) {
+	url := fmt.Sprintf("%s/admin/reconcile/rewind?platform=%s&after=%s", adminURL, platform, after)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("admin endpoint returned status %d: %s", resp.StatusCode, body)
+	}
+
+	var result struct {
+		Diverged     []connectors.LedgerEntry `json:"diverged"`
+		CurrentSpend float64                  `json:"current_spend"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding admin endpoint response: %w", err)
+	}
+	fmt.Printf("🔁 Rewound %s's live spend to just after bid %s\n", platform, after)
+	fmt.Printf("   %d diverged bid(s), corrected CurrentSpend: $%.2f\n", len(result.Diverged), result.CurrentSpend)
+	return result.Diverged, nil
+}
@@ -1,482 +1,716 @@
-package main
-
-import (
-	"context"
-	"encoding/json"
-	"fmt"
-	"log"
-	"net/http"
-	"os"
-	"strconv"
-	"time"
-
-	"sync"
-
-	"github.com/go-redis/redis/v8"
-	"github.com/user/kiki-agent/cmd/syncshield/compliance"
-)
-
-var rdb *redis.Client
-var maxBurstBudget = 500.0
-var windowSeconds = 60.0
-var gdprLogger *compliance.GDPRAuditLogger
-var consentManager *compliance.ConsentManager
-var iso27001 *compliance.ISO27001Controls
-var ccpa *compliance.CCPACompliance
-
-// In-memory fallback for budget window when Redis is unavailable
-var (
-	memMu        sync.Mutex
-	memSpendWind []struct {
-		ts  float64
-		amt float64
-	}
-)
-
-func checkBudget() bool {
-	ctx := context.Background()
-	now := float64(time.Now().Unix())
-
-	// Remove old entries
-	rdb.ZRemRangeByScore(ctx, "spend_window", "-inf", strconv.FormatFloat(now-windowSeconds, 'f', 0, 64))
-
-	// Get current spend
-	spendData, err := rdb.ZRangeWithScores(ctx, "spend_window", 0, -1).Result()
-	if err != nil {
-		log.Printf("Redis error: %v", err)
-		// Use in-memory fallback window instead of permissive allow
-		memMu.Lock()
-		defer memMu.Unlock()
-		// Prune old
-		cutoff := now - windowSeconds
-		pruned := memSpendWind[:0]
-		total := 0.0
-		for _, e := range memSpendWind {
-			if e.ts >= cutoff {
-				pruned = append(pruned, e)
-				total += e.amt
-			}
-		}
-		memSpendWind = pruned
-		return total < maxBurstBudget
-	}
-
-	total := 0.0
-	for _, z := range spendData {
-		amount, _ := strconv.ParseFloat(z.Member.(string), 64)
-		total += amount
-	}
-
-	return total < maxBurstBudget
-}
-
-// ValidateBid implements the Governor: checks LTV outliers and budget
-func ValidateBid(predictedLTV float64) bool {
-	// Rule 1: Safety Ceiling - Never bid if LTV is nonsensical
-	if predictedLTV > 10000 {
-		log.Printf("🛡️ GOVERNOR VETO: LTV %.2f exceeds safety ceiling", predictedLTV)
-		logValidation("VETO", predictedLTV, "Safety ceiling exceeded")
-		if gdprLogger != nil {
-			gdprLogger.LogBidValidation("system", predictedLTV, "DENIED", "Safety ceiling exceeded")
-		}
-		return false
-	}
-
-	// Rule 2: Minimum threshold - Don't bid on very low LTV
-	if predictedLTV < 10 {
-		log.Printf("🛡️ GOVERNOR VETO: LTV %.2f below minimum threshold", predictedLTV)
-		logValidation("VETO", predictedLTV, "Below minimum threshold")
-		if gdprLogger != nil {
-			gdprLogger.LogBidValidation("system", predictedLTV, "DENIED", "Below minimum threshold")
-		}
-		return false
-	}
-
-	// Rule 3: Budget check - Sliding window validation
-	if !checkBudget() {
-		log.Printf("🛡️ GOVERNOR VETO: Budget limit exceeded")
-		logValidation("VETO", predictedLTV, "Budget limit exceeded")
-		if gdprLogger != nil {
-			gdprLogger.LogBidValidation("system", predictedLTV, "DENIED", "Budget limit exceeded")
-		}
-		return false
-	}
-
-	log.Printf("✅ GOVERNOR APPROVED: LTV %.2f within safe parameters", predictedLTV)
-	logValidation("APPROVED", predictedLTV, "Within safe parameters")
-	if gdprLogger != nil {
-		gdprLogger.LogBidValidation("system", predictedLTV, "APPROVED", "Within safe parameters")
-	}
-	return true
-}
-
-// logValidation writes compliance decisions to audit log
-func logValidation(decision string, ltv float64, reason string) {
-	file, err := os.OpenFile("shield_audit.csv", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		log.Printf("Error opening shield audit log: %v", err)
-		return
-	}
-	defer file.Close()
-
-	timestamp := time.Now().Format(time.RFC3339)
-	record := fmt.Sprintf("%s,%s,%.2f,%s\n", timestamp, decision, ltv, reason)
-	if _, err := file.WriteString(record); err != nil {
-		log.Printf("Error writing to shield audit log: %v", err)
-	}
-}
-
-func addSpend(amount float64) {
-	ctx := context.Background()
-	now := float64(time.Now().Unix())
-	rdb.ZAdd(ctx, "spend_window", &redis.Z{
-		Score:  now,
-		Member: strconv.FormatFloat(amount, 'f', 2, 64),
-	})
-	// Always mirror to in-memory window for fallback
-	memMu.Lock()
-	memSpendWind = append(memSpendWind, struct {
-		ts  float64
-		amt float64
-	}{ts: now, amt: amount})
-	memMu.Unlock()
-}
-
-func main() {
-	// Load environment configuration
-	redisAddr := os.Getenv("REDIS_ADDR")
-	if redisAddr == "" {
-		redisAddr = "localhost:6379"
-	}
-	if v := os.Getenv("MAX_BURST_BUDGET"); v != "" {
-		if f, err := strconv.ParseFloat(v, 64); err == nil {
-			maxBurstBudget = f
-		}
-	}
-	if v := os.Getenv("WINDOW_SECONDS"); v != "" {
-		if f, err := strconv.ParseFloat(v, 64); err == nil {
-			windowSeconds = f
-		}
-	}
-	retentionDays := 2555
-	if v := os.Getenv("RETENTION_DAYS"); v != "" {
-		if i, err := strconv.Atoi(v); err == nil {
-			retentionDays = i
-		}
-	}
-
-	// Initialize GDPR-compliant audit logging
-	var err error
-	gdprLogger, err = compliance.NewGDPRAuditLogger(
-		"shield_audit_gdpr.csv",
-		"shield_audit_gdpr.json",
-		retentionDays,
-	)
-	if err != nil {
-		log.Fatalf("Failed to initialize GDPR audit logger: %v", err)
-	}
-	defer gdprLogger.Close()
-
-	// Initialize compliance managers
-	consentManager = compliance.NewConsentManager(gdprLogger)
-	iso27001 = compliance.NewISO27001Controls(gdprLogger)
-	ccpa = compliance.NewCCPACompliance(gdprLogger)
-
-	log.Println("🛡️ SyncShield™ - Regulatory Guardrail Agent")
-	log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	log.Println("✅ GDPR-compliant audit logging enabled")
-	log.Println("✅ CCPA compliance framework active")
-	log.Println("✅ ISO 27001 security controls operational")
-	log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-	rdb = redis.NewClient(&redis.Options{
-		Addr: redisAddr,
-	})
-	defer rdb.Close()
-
-	// HTTP endpoints
-	http.HandleFunc("/check", complianceHandler)
-	http.HandleFunc("/spend", spendHandler)
-	http.HandleFunc("/health", healthHandler)
-	http.HandleFunc("/consent/grant", grantConsentHandler)
-	http.HandleFunc("/consent/revoke", revokeConsentHandler)
-	http.HandleFunc("/consent/status", consentStatusHandler)
-	http.HandleFunc("/dsr/create", createDSRHandler)
-	http.HandleFunc("/compliance/report", complianceReportHandler)
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8081"
-	}
-	log.Printf("🌐 SyncShield API starting on :%s", port)
-	log.Printf("   Compliance: http://localhost:%s/check", port)
-	log.Printf("   Health: http://localhost:%s/health", port)
-	log.Printf("   Consent: http://localhost:%s/consent/*", port)
-	log.Printf("   DSR: http://localhost:%s/dsr/*", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
-}
-
-func spendHandler(w http.ResponseWriter, r *http.Request) {
-	amountStr := r.URL.Query().Get("amount")
-	amount, err := strconv.ParseFloat(amountStr, 64)
-	if err != nil {
-		http.Error(w, "Invalid amount", http.StatusBadRequest)
-		return
-	}
-	addSpend(amount)
-	fmt.Fprintf(w, "Spend recorded: %.2f", amount)
-}
-
-func complianceHandler(w http.ResponseWriter, r *http.Request) {
-	ltvStr := r.URL.Query().Get("ltv")
-	if ltvStr == "" {
-		http.Error(w, "Missing LTV parameter", http.StatusBadRequest)
-		return
-	}
-
-	ltv, err := strconv.ParseFloat(ltvStr, 64)
-	if err != nil {
-		http.Error(w, "Invalid LTV value", http.StatusBadRequest)
-		return
-	}
-
-	// Log the compliance check attempt
-	if iso27001 != nil {
-		iso27001.LogUserAccess("system", "validate_bid", "ltv_prediction", "PROCESSING")
-	}
-
-	if ValidateBid(ltv) {
-		fmt.Fprintf(w, "Compliance check passed")
-	} else {
-		http.Error(w, "Bid validation failed", http.StatusForbidden)
-	}
-}
-
-// Health check endpoint
-func healthHandler(w http.ResponseWriter, r *http.Request) {
-	status := map[string]interface{}{
-		"status":          "healthy",
-		"service":         "SyncShield™ Regulatory Guardrail",
-		"gdpr_compliant":  gdprLogger != nil,
-		"iso27001_active": iso27001 != nil,
-		"ccpa_compliant":  ccpa != nil,
-		"redis_connected": rdb.Ping(context.Background()).Err() == nil,
-		"timestamp":       time.Now().Format(time.RFC3339),
-	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(status)
-}
-
-// Grant consent endpoint
-func grantConsentHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	// Accept either JSON body or query params
-	var payload struct {
-		CustomerID  string `json:"customer_id"`
-		ConsentType string `json:"consent_type"`
-		Type        string `json:"type"`
-		IPAddress   string `json:"ip_address"`
-		UserAgent   string `json:"user_agent"`
-		LegalBasis  string `json:"legal_basis"`
-	}
-
-	contentType := r.Header.Get("Content-Type")
-	if contentType == "application/json" {
-		_ = json.NewDecoder(r.Body).Decode(&payload)
-	}
-
-	customerID := payload.CustomerID
-	if customerID == "" {
-		customerID = r.URL.Query().Get("customer_id")
-	}
-	consentType := payload.ConsentType
-	if consentType == "" {
-		consentType = payload.Type
-	}
-	if consentType == "" {
-		consentType = r.URL.Query().Get("type")
-	}
-	ipAddress := payload.IPAddress
-	if ipAddress == "" {
-		ipAddress = r.RemoteAddr
-	}
-	userAgent := payload.UserAgent
-	if userAgent == "" {
-		userAgent = r.UserAgent()
-	}
-	legalBasis := payload.LegalBasis
-	if legalBasis == "" {
-		legalBasis = "Consent"
-	}
-
-	if customerID == "" || consentType == "" {
-		http.Error(w, "Missing required parameters", http.StatusBadRequest)
-		return
-	}
-
-	err := consentManager.GrantConsent(
-		customerID,
-		compliance.ConsentType(consentType),
-		ipAddress,
-		userAgent,
-		legalBasis,
-	)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"status":  "granted",
-		"message": "Consent successfully recorded",
-	})
-}
-
-// Revoke consent endpoint
-func revokeConsentHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	// Accept either JSON body or query params
-	var payload struct {
-		CustomerID  string `json:"customer_id"`
-		ConsentType string `json:"consent_type"`
-		Type        string `json:"type"`
-		IPAddress   string `json:"ip_address"`
-	}
-
-	contentType := r.Header.Get("Content-Type")
-	if contentType == "application/json" {
-		_ = json.NewDecoder(r.Body).Decode(&payload)
-	}
-
-	customerID := payload.CustomerID
-	if customerID == "" {
-		customerID = r.URL.Query().Get("customer_id")
-	}
-	consentType := payload.ConsentType
-	if consentType == "" {
-		consentType = payload.Type
-	}
-	if consentType == "" {
-		consentType = r.URL.Query().Get("type")
-	}
-	ipAddress := payload.IPAddress
-	if ipAddress == "" {
-		ipAddress = r.RemoteAddr
-	}
-
-	if customerID == "" || consentType == "" {
-		http.Error(w, "Missing required parameters", http.StatusBadRequest)
-		return
-	}
-
-	err := consentManager.RevokeConsent(
-		customerID,
-		compliance.ConsentType(consentType),
-		ipAddress,
-	)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"status":  "revoked",
-		"message": "Consent successfully revoked",
-	})
-}
-
-// Check consent status endpoint
-func consentStatusHandler(w http.ResponseWriter, r *http.Request) {
-	// Accept either JSON body or query params
-	var payload struct {
-		CustomerID string `json:"customer_id"`
-	}
-	if r.Header.Get("Content-Type") == "application/json" {
-		_ = json.NewDecoder(r.Body).Decode(&payload)
-	}
-
-	customerID := payload.CustomerID
-	if customerID == "" {
-		customerID = r.URL.Query().Get("customer_id")
-	}
-	if customerID == "" {
-		http.Error(w, "Missing customer_id parameter", http.StatusBadRequest)
-		return
-	}
-
-	consents := consentManager.GetConsents(customerID)
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(consents)
-}
-
-// Create Data Subject Request endpoint
-func createDSRHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	// Accept either JSON body or query params
-	var payload struct {
-		CustomerID  string `json:"customer_id"`
-		RequestType string `json:"request_type"`
-		Type        string `json:"type"`
-		RequestedBy string `json:"requested_by"`
-		Details     string `json:"details"`
-	}
-	if r.Header.Get("Content-Type") == "application/json" {
-		_ = json.NewDecoder(r.Body).Decode(&payload)
-	}
-
-	customerID := payload.CustomerID
-	if customerID == "" {
-		customerID = r.URL.Query().Get("customer_id")
-	}
-	requestType := payload.RequestType
-	if requestType == "" {
-		requestType = payload.Type
-	}
-	if requestType == "" {
-		requestType = r.URL.Query().Get("type")
-	}
-	requestedBy := payload.RequestedBy
-	if requestedBy == "" {
-		requestedBy = r.URL.Query().Get("requested_by")
-	}
-
-	if customerID == "" || requestType == "" {
-		http.Error(w, "Missing required parameters", http.StatusBadRequest)
-		return
-	}
-
-	dsrManager := compliance.NewDataSubjectRequestManager(gdprLogger)
-	request, err := dsrManager.CreateRequest(customerID, requestType, requestedBy)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(request)
-}
-
-// Compliance report endpoint
-func complianceReportHandler(w http.ResponseWriter, r *http.Request) {
-	period := r.URL.Query().Get("period")
-	if period == "" {
-		period = "monthly"
-	}
-
-	report := compliance.GenerateComplianceReport(period)
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(report)
-}
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog"
+	"github.com/user/kiki-agent/cmd/syncflow/audit"
+	"github.com/user/kiki-agent/cmd/syncshield/compliance"
+	"github.com/user/kiki-agent/cmd/syncshield/observability"
+)
+
+var rdb *redis.Client
+var maxBurstBudget = 500.0
+var windowSeconds = 60.0
+var gdprLogger *compliance.GDPRAuditLogger
+var consentManager *compliance.ConsentManager
+var dsrManager *compliance.DataSubjectRequestManager
+var iso27001 *compliance.ISO27001Controls
+var ccpa *compliance.CCPACompliance
+var chainedAuditWriter *compliance.HashChainedAuditWriter
+
+const chainedAuditLogPath = "shield_audit_chain.jsonl"
+
+// auditStreamSink fans out AuditEntry events to real-time SSE/websocket
+// subscribers (see /stream/audit/sse and /stream/audit/ws below).
+// streamAuthToken gates subscription access; an unconfigured (empty) token
+// fails every subscription closed rather than allowing anonymous access.
+var auditStreamSink = audit.NewStreamSink(256)
+var streamAuthToken = os.Getenv("STREAM_AUTH_TOKEN")
+
+// In-memory fallback for budget window when Redis is unavailable
+var (
+	memMu        sync.Mutex
+	memSpendWind []struct {
+		ts  float64
+		amt float64
+	}
+)
+
+const (
+	spendWindowKey  = "spend_window"
+	spendAmountsKey = "spend_amounts"
+)
+
+// spendMemberSeq hands out unique sorted-set member ids so two spends of the
+// same amount never collide under the same ZSET member (the old code keyed
+// members by the formatted amount itself, which merged duplicate spends).
+var spendMemberSeq int64
+
+func nextSpendMemberID() string {
+	return fmt.Sprintf("%d-%d", time.Now().UnixNano(), atomic.AddInt64(&spendMemberSeq, 1))
+}
+
+// budgetCheckScript atomically prunes expired entries from the sliding
+// window, sums what remains via the companion spend_amounts hash, and admits
+// the candidate spend only if it keeps the window under maxBudget. Doing
+// this as one EVAL closes the race where two concurrent callers each read a
+// total under the cap and then both commit, jointly exceeding it.
+var budgetCheckScript = redis.NewScript(`
+local key = KEYS[1]
+local hashKey = KEYS[2]
+local now = tonumber(ARGV[1])
+local cutoff = tonumber(ARGV[2])
+local amount = tonumber(ARGV[3])
+local maxBudget = tonumber(ARGV[4])
+local member = ARGV[5]
+
+local expired = redis.call('ZRANGEBYSCORE', key, '-inf', cutoff)
+if #expired > 0 then
+	redis.call('ZREMRANGEBYSCORE', key, '-inf', cutoff)
+	redis.call('HDEL', hashKey, unpack(expired))
+end
+
+local members = redis.call('ZRANGE', key, 0, -1)
+local total = 0
+if #members > 0 then
+	local amounts = redis.call('HMGET', hashKey, unpack(members))
+	for i, a in ipairs(amounts) do
+		if a then
+			total = total + tonumber(a)
+		end
+	end
+end
+
+if total + amount < maxBudget then
+	redis.call('ZADD', key, now, member)
+	redis.call('HSET', hashKey, member, tostring(amount))
+	return {1, tostring(total + amount), tostring(maxBudget - total - amount)}
+end
+return {0, tostring(total), tostring(maxBudget - total)}
+`)
+
+// checkBudget atomically checks whether amount fits within the sliding
+// window budget and, if so, reserves it in the same round trip.
+func checkBudget(amount float64) bool {
+	ctx := context.Background()
+	now := float64(time.Now().Unix())
+	cutoff := now - windowSeconds
+
+	res, err := budgetCheckScript.Run(ctx, rdb, []string{spendWindowKey, spendAmountsKey}, now, cutoff, amount, maxBurstBudget, nextSpendMemberID()).Result()
+	if err != nil {
+		log.Printf("Redis error: %v", err)
+		return checkAndReserveInMemory(amount, now, cutoff)
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 3 {
+		log.Printf("unexpected budget script result: %#v", res)
+		return checkAndReserveInMemory(amount, now, cutoff)
+	}
+
+	allowed := fmt.Sprint(values[0]) == "1"
+	if remaining, err := strconv.ParseFloat(fmt.Sprint(values[2]), 64); err == nil {
+		observability.Default.SetBudgetRemaining("shield", remaining)
+	}
+	if allowed {
+		// Mirror into the in-memory fallback window so a later Redis outage
+		// degrades gracefully instead of forgetting everything spent so far.
+		memMu.Lock()
+		memSpendWind = append(memSpendWind, struct {
+			ts  float64
+			amt float64
+		}{ts: now, amt: amount})
+		memMu.Unlock()
+	}
+	return allowed
+}
+
+// checkAndReserveInMemory mirrors budgetCheckScript's check-then-reserve
+// semantics against the in-memory window, used only when Redis is down.
+func checkAndReserveInMemory(amount, now, cutoff float64) bool {
+	memMu.Lock()
+	defer memMu.Unlock()
+
+	pruned := memSpendWind[:0]
+	total := 0.0
+	for _, e := range memSpendWind {
+		if e.ts >= cutoff {
+			pruned = append(pruned, e)
+			total += e.amt
+		}
+	}
+	memSpendWind = pruned
+
+	if total+amount >= maxBurstBudget {
+		return false
+	}
+	memSpendWind = append(memSpendWind, struct {
+		ts  float64
+		amt float64
+	}{ts: now, amt: amount})
+	return true
+}
+
+// ValidateBid implements the Governor: checks consent, LTV outliers, and
+// budget. customerID is the subject of the bid and purpose is the consent
+// purpose it's being bid under (see compliance.ConsentType); an empty
+// customerID is treated as having no consent on file. The returned trace id
+// follows this decision into shield_audit.csv and the GDPR audit log so a
+// single bid can be correlated across connector, shield, and compliance
+// logger.
+func ValidateBid(customerID string, predictedLTV float64, purpose compliance.ConsentType) (bool, string) {
+	span := observability.StartSpan("ValidateBid")
+	defer span.Finish()
+	traceID := span.String()
+
+	// Rule 1: Consent - Never bid on a customer without an active grant for
+	// purpose (GDPR Article 6/7)
+	if consentManager != nil {
+		consents := consentManager.GetConsents(customerID)
+		consent, ok := consents[purpose]
+		if !ok || consent.Status != compliance.ConsentGranted || (consent.ExpiresAt != nil && consent.ExpiresAt.Before(time.Now())) {
+			log.Printf("🛡️ GOVERNOR VETO: missing/revoked consent for customer=%s purpose=%s [trace=%s]", customerID, purpose, traceID)
+			observability.Default.RecordVeto("consent_missing")
+			logValidation("VETO", predictedLTV, "CONSENT_MISSING", traceID)
+			if gdprLogger != nil {
+				gdprLogger.LogBidValidation(customerID, predictedLTV, "DENIED", "CONSENT_MISSING")
+			}
+			return false, traceID
+		}
+	}
+
+	// Rule 2: Safety Ceiling - Never bid if LTV is nonsensical
+	if predictedLTV > 10000 {
+		log.Printf("🛡️ GOVERNOR VETO: LTV %.2f exceeds safety ceiling [trace=%s]", predictedLTV, traceID)
+		observability.Default.RecordVeto("safety_ceiling")
+		logValidation("VETO", predictedLTV, "Safety ceiling exceeded", traceID)
+		if gdprLogger != nil {
+			gdprLogger.LogBidValidation(customerID, predictedLTV, "DENIED", "Safety ceiling exceeded")
+		}
+		return false, traceID
+	}
+
+	// Rule 3: Minimum threshold - Don't bid on very low LTV
+	if predictedLTV < 10 {
+		log.Printf("🛡️ GOVERNOR VETO: LTV %.2f below minimum threshold [trace=%s]", predictedLTV, traceID)
+		observability.Default.RecordVeto("below_minimum_threshold")
+		logValidation("VETO", predictedLTV, "Below minimum threshold", traceID)
+		if gdprLogger != nil {
+			gdprLogger.LogBidValidation(customerID, predictedLTV, "DENIED", "Below minimum threshold")
+		}
+		return false, traceID
+	}
+
+	// Rule 4: Budget check - Sliding window validation
+	if !checkBudget(predictedLTV) {
+		log.Printf("🛡️ GOVERNOR VETO: Budget limit exceeded [trace=%s]", traceID)
+		observability.Default.RecordVeto("budget_exceeded")
+		logValidation("VETO", predictedLTV, "Budget limit exceeded", traceID)
+		if gdprLogger != nil {
+			gdprLogger.LogBidValidation(customerID, predictedLTV, "DENIED", "Budget limit exceeded")
+		}
+		return false, traceID
+	}
+
+	log.Printf("✅ GOVERNOR APPROVED: LTV %.2f within safe parameters [trace=%s]", predictedLTV, traceID)
+	logValidation("APPROVED", predictedLTV, "Within safe parameters", traceID)
+	if gdprLogger != nil {
+		gdprLogger.LogBidValidation(customerID, predictedLTV, "APPROVED", "Within safe parameters")
+	}
+	return true, traceID
+}
+
+// logValidation writes compliance decisions to the CSV audit log and, in
+// parallel, the hash-chained JSONL log so tampering or truncation of either
+// file can be detected independently of the other.
+func logValidation(decision string, ltv float64, reason string, traceID string) {
+	file, err := os.OpenFile("shield_audit.csv", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("Error opening shield audit log: %v", err)
+		return
+	}
+	defer file.Close()
+
+	timestamp := time.Now().Format(time.RFC3339)
+	record := fmt.Sprintf("%s,%s,%.2f,%s,%s\n", timestamp, decision, ltv, reason, traceID)
+	if _, err := file.WriteString(record); err != nil {
+		log.Printf("Error writing to shield audit log: %v", err)
+	}
+
+	if chainedAuditWriter != nil {
+		if err := chainedAuditWriter.Append(decision, ltv, reason); err != nil {
+			log.Printf("Error writing to hash-chained audit log: %v", err)
+		}
+	}
+}
+
+func addSpend(amount float64) {
+	ctx := context.Background()
+	now := float64(time.Now().Unix())
+	member := nextSpendMemberID()
+	rdb.ZAdd(ctx, spendWindowKey, &redis.Z{
+		Score:  now,
+		Member: member,
+	})
+	rdb.HSet(ctx, spendAmountsKey, member, amount)
+	// Always mirror to in-memory window for fallback
+	memMu.Lock()
+	memSpendWind = append(memSpendWind, struct {
+		ts  float64
+		amt float64
+	}{ts: now, amt: amount})
+	memMu.Unlock()
+}
+
+// initComplianceStores builds the consent/DSR stores from whichever of
+// GDPR_POSTGRES_DSN or GDPR_SQLITE_PATH is configured, preferring Postgres,
+// falling back to a non-durable in-memory store (consent/DSR history resets
+// on restart) if neither is set or the configured backend fails to open -
+// mirrors syncengage's initCRMSources fallback-with-warning pattern.
+func initComplianceStores() (compliance.ConsentStore, compliance.DSRStore) {
+	if dsn := os.Getenv("GDPR_POSTGRES_DSN"); dsn != "" {
+		consentStore, err := compliance.NewPostgresConsentStore(dsn)
+		if err != nil {
+			log.Printf("⚠️ Could not initialize Postgres consent store, falling back to in-memory: %v", err)
+			return compliance.NewInMemoryConsentStore(), compliance.NewInMemoryDSRStore()
+		}
+		dsrStore, err := compliance.NewPostgresDSRStore(dsn)
+		if err != nil {
+			log.Printf("⚠️ Could not initialize Postgres DSR store, falling back to in-memory: %v", err)
+			return consentStore, compliance.NewInMemoryDSRStore()
+		}
+		return consentStore, dsrStore
+	}
+
+	if path := os.Getenv("GDPR_SQLITE_PATH"); path != "" {
+		consentStore, err := compliance.NewSQLiteConsentStore(path)
+		if err != nil {
+			log.Printf("⚠️ Could not initialize SQLite consent store, falling back to in-memory: %v", err)
+			return compliance.NewInMemoryConsentStore(), compliance.NewInMemoryDSRStore()
+		}
+		dsrStore, err := compliance.NewSQLiteDSRStore(path)
+		if err != nil {
+			log.Printf("⚠️ Could not initialize SQLite DSR store, falling back to in-memory: %v", err)
+			return consentStore, compliance.NewInMemoryDSRStore()
+		}
+		return consentStore, dsrStore
+	}
+
+	return compliance.NewInMemoryConsentStore(), compliance.NewInMemoryDSRStore()
+}
+
+func main() {
+	// Load environment configuration
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "localhost:6379"
+	}
+	if v := os.Getenv("MAX_BURST_BUDGET"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			maxBurstBudget = f
+		}
+	}
+	if v := os.Getenv("WINDOW_SECONDS"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			windowSeconds = f
+		}
+	}
+	retentionDays := 2555
+	if v := os.Getenv("RETENTION_DAYS"); v != "" {
+		if i, err := strconv.Atoi(v); err == nil {
+			retentionDays = i
+		}
+	}
+
+	// Initialize GDPR-compliant audit logging
+	var err error
+	gdprLogger, err = compliance.NewGDPRAuditLogger(
+		"shield_audit_gdpr.csv",
+		"shield_audit_gdpr.json",
+		retentionDays,
+	)
+	if err != nil {
+		log.Fatalf("Failed to initialize GDPR audit logger: %v", err)
+	}
+	defer gdprLogger.Close()
+
+	chainedAuditWriter, err = compliance.NewHashChainedAuditWriter(chainedAuditLogPath)
+	if err != nil {
+		log.Fatalf("Failed to initialize hash-chained audit log: %v", err)
+	}
+
+	// Initialize compliance managers. Consent and DSR history persist to
+	// GDPR_POSTGRES_DSN/GDPR_SQLITE_PATH if configured, the same
+	// fallback-to-in-memory pattern syncengage uses for its CRM cursors.
+	logLevel := zerolog.InfoLevel
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		if l, err := zerolog.ParseLevel(v); err == nil {
+			logLevel = l
+		}
+	}
+	zlog := compliance.NewLogger(logLevel)
+
+	consentStore, dsrStore := initComplianceStores()
+	consentManager = compliance.NewConsentManager(gdprLogger, consentStore, zlog)
+	dsrManager = compliance.NewDataSubjectRequestManager(gdprLogger, dsrStore, zlog)
+	iso27001 = compliance.NewISO27001Controls(gdprLogger)
+	ccpa = compliance.NewCCPACompliance(gdprLogger)
+
+	log.Println("🛡️ SyncShield™ - Regulatory Guardrail Agent")
+	log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	log.Println("✅ GDPR-compliant audit logging enabled")
+	log.Println("✅ CCPA compliance framework active")
+	log.Println("✅ ISO 27001 security controls operational")
+	log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	rdb = redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	defer rdb.Close()
+
+	// HTTP endpoints
+	http.HandleFunc("/check", complianceHandler)
+	http.HandleFunc("/spend", spendHandler)
+	http.HandleFunc("/health", healthHandler)
+	http.HandleFunc("/consent/grant", grantConsentHandler)
+	http.HandleFunc("/consent/revoke", revokeConsentHandler)
+	http.HandleFunc("/consent/status", consentStatusHandler)
+	http.HandleFunc("/dsr/create", createDSRHandler)
+	http.HandleFunc("/compliance/report", complianceReportHandler)
+	http.HandleFunc("/compliance/audit/verify", auditVerifyHandler)
+	http.HandleFunc("/stream/audit/sse", func(w http.ResponseWriter, r *http.Request) {
+		auditStreamSink.ServeSSE(w, r, streamAuthToken)
+	})
+	http.HandleFunc("/stream/audit/ws", func(w http.ResponseWriter, r *http.Request) {
+		auditStreamSink.ServeWebSocket(w, r, streamAuthToken)
+	})
+	http.HandleFunc("/metrics", metricsHandler)
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8081"
+	}
+	log.Printf("🌐 SyncShield API starting on :%s", port)
+	log.Printf("   Compliance: http://localhost:%s/check", port)
+	log.Printf("   Health: http://localhost:%s/health", port)
+	log.Printf("   Consent: http://localhost:%s/consent/*", port)
+	log.Printf("   DSR: http://localhost:%s/dsr/*", port)
+	log.Fatal(http.ListenAndServe(":"+port, nil))
+}
+
+func spendHandler(w http.ResponseWriter, r *http.Request) {
+	amountStr := r.URL.Query().Get("amount")
+	amount, err := strconv.ParseFloat(amountStr, 64)
+	if err != nil {
+		http.Error(w, "Invalid amount", http.StatusBadRequest)
+		return
+	}
+	addSpend(amount)
+	fmt.Fprintf(w, "Spend recorded: %.2f", amount)
+}
+
+func complianceHandler(w http.ResponseWriter, r *http.Request) {
+	ltvStr := r.URL.Query().Get("ltv")
+	if ltvStr == "" {
+		http.Error(w, "Missing LTV parameter", http.StatusBadRequest)
+		return
+	}
+
+	ltv, err := strconv.ParseFloat(ltvStr, 64)
+	if err != nil {
+		http.Error(w, "Invalid LTV value", http.StatusBadRequest)
+		return
+	}
+
+	customerID := r.URL.Query().Get("customer_id")
+	purpose := r.URL.Query().Get("purpose")
+	if purpose == "" {
+		purpose = string(compliance.ConsentTargeting)
+	}
+
+	// Log the compliance check attempt
+	if iso27001 != nil {
+		iso27001.LogUserAccess(customerID, "validate_bid", "ltv_prediction", "PROCESSING")
+	}
+
+	if approved, traceID := ValidateBid(customerID, ltv, compliance.ConsentType(purpose)); approved {
+		fmt.Fprintf(w, "Compliance check passed [trace=%s]", traceID)
+	} else {
+		w.Header().Set("X-Trace-Id", traceID)
+		http.Error(w, "Bid validation failed", http.StatusForbidden)
+	}
+}
+
+// metricsHandler serves Prometheus-formatted metrics for bids, budget, and
+// shield decisions.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	observability.Default.WriteTo(w)
+}
+
+// auditVerifyHandler replays the hash-chained audit log on demand and
+// reports whether it's still intact.
+func auditVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	firstBadLine, err := compliance.Verify(chainedAuditLogPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"valid":          firstBadLine == 0,
+		"first_bad_line": firstBadLine,
+	})
+}
+
+// Health check endpoint
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	status := map[string]interface{}{
+		"status":          "healthy",
+		"service":         "SyncShield™ Regulatory Guardrail",
+		"gdpr_compliant":  gdprLogger != nil,
+		"iso27001_active": iso27001 != nil,
+		"ccpa_compliant":  ccpa != nil,
+		"redis_connected": rdb.Ping(context.Background()).Err() == nil,
+		"timestamp":       time.Now().Format(time.RFC3339),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// Grant consent endpoint
+func grantConsentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// Accept either JSON body or query params
+	var payload struct {
+		CustomerID  string `json:"customer_id"`
+		ConsentType string `json:"consent_type"`
+		Type        string `json:"type"`
+		IPAddress   string `json:"ip_address"`
+		UserAgent   string `json:"user_agent"`
+		LegalBasis  string `json:"legal_basis"`
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "application/json" {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}
+
+	customerID := payload.CustomerID
+	if customerID == "" {
+		customerID = r.URL.Query().Get("customer_id")
+	}
+	consentType := payload.ConsentType
+	if consentType == "" {
+		consentType = payload.Type
+	}
+	if consentType == "" {
+		consentType = r.URL.Query().Get("type")
+	}
+	ipAddress := payload.IPAddress
+	if ipAddress == "" {
+		ipAddress = r.RemoteAddr
+	}
+	userAgent := payload.UserAgent
+	if userAgent == "" {
+		userAgent = r.UserAgent()
+	}
+	legalBasis := payload.LegalBasis
+	if legalBasis == "" {
+		legalBasis = "Consent"
+	}
+
+	if customerID == "" || consentType == "" {
+		http.Error(w, "Missing required parameters", http.StatusBadRequest)
+		return
+	}
+
+	err := consentManager.GrantConsent(
+		customerID,
+		compliance.ConsentType(consentType),
+		ipAddress,
+		userAgent,
+		legalBasis,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"status":  "granted",
+		"message": "Consent successfully recorded",
+	})
+}
+
+// Revoke consent endpoint
+func revokeConsentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// Accept either JSON body or query params
+	var payload struct {
+		CustomerID  string `json:"customer_id"`
+		ConsentType string `json:"consent_type"`
+		Type        string `json:"type"`
+		IPAddress   string `json:"ip_address"`
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "application/json" {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}
+
+	customerID := payload.CustomerID
+	if customerID == "" {
+		customerID = r.URL.Query().Get("customer_id")
+	}
+	consentType := payload.ConsentType
+	if consentType == "" {
+		consentType = payload.Type
+	}
+	if consentType == "" {
+		consentType = r.URL.Query().Get("type")
+	}
+	ipAddress := payload.IPAddress
+	if ipAddress == "" {
+		ipAddress = r.RemoteAddr
+	}
+
+	if customerID == "" || consentType == "" {
+		http.Error(w, "Missing required parameters", http.StatusBadRequest)
+		return
+	}
+
+	err := consentManager.RevokeConsent(
+		customerID,
+		compliance.ConsentType(consentType),
+		ipAddress,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"status":  "revoked",
+		"message": "Consent successfully revoked",
+	})
+}
+
+// Check consent status endpoint
+func consentStatusHandler(w http.ResponseWriter, r *http.Request) {
+	// Accept either JSON body or query params
+	var payload struct {
+		CustomerID string `json:"customer_id"`
+	}
+	if r.Header.Get("Content-Type") == "application/json" {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}
+
+	customerID := payload.CustomerID
+	if customerID == "" {
+		customerID = r.URL.Query().Get("customer_id")
+	}
+	if customerID == "" {
+		http.Error(w, "Missing customer_id parameter", http.StatusBadRequest)
+		return
+	}
+
+	consents := consentManager.GetConsents(customerID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(consents)
+}
+
+// Create Data Subject Request endpoint
+func createDSRHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// Accept either JSON body or query params
+	var payload struct {
+		CustomerID  string `json:"customer_id"`
+		RequestType string `json:"request_type"`
+		Type        string `json:"type"`
+		RequestedBy string `json:"requested_by"`
+		Details     string `json:"details"`
+	}
+	if r.Header.Get("Content-Type") == "application/json" {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}
+
+	customerID := payload.CustomerID
+	if customerID == "" {
+		customerID = r.URL.Query().Get("customer_id")
+	}
+	requestType := payload.RequestType
+	if requestType == "" {
+		requestType = payload.Type
+	}
+	if requestType == "" {
+		requestType = r.URL.Query().Get("type")
+	}
+	requestedBy := payload.RequestedBy
+	if requestedBy == "" {
+		requestedBy = r.URL.Query().Get("requested_by")
+	}
+
+	if customerID == "" || requestType == "" {
+		http.Error(w, "Missing required parameters", http.StatusBadRequest)
+		return
+	}
+
+	request, err := dsrManager.CreateRequest(customerID, requestType, requestedBy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(request)
+}
+
+// Compliance report endpoint
+func complianceReportHandler(w http.ResponseWriter, r *http.Request) {
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = "monthly"
+	}
+
+	periodDuration := 30 * 24 * time.Hour
+	if period == "weekly" {
+		periodDuration = 7 * 24 * time.Hour
+	}
+	to := time.Now()
+	from := to.Add(-periodDuration)
+
+	report, err := compliance.GenerateComplianceReport(gdprLogger, compliance.DefaultRegistry(), period, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog"
+	"github.com/user/kiki-agent/cmd/syncshield/compliance"
+)
+
+func newTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+	return mr
+}
+
+func TestCheckBudget_ConcurrentCallsNeverExceedCap(t *testing.T) {
+	mr := newTestRedis(t)
+	rdb = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	maxBurstBudget = 500.0
+	windowSeconds = 60.0
+	memSpendWind = nil
+
+	const goroutines = 1000
+	const spendPerCall = 1.0
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var admitted int
+
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			if checkBudget(spendPerCall) {
+				mu.Lock()
+				admitted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	totalAdmitted := float64(admitted) * spendPerCall
+	if totalAdmitted > maxBurstBudget {
+		t.Fatalf("budget cap breached: admitted $%.2f against a $%.2f cap", totalAdmitted, maxBurstBudget)
+	}
+	// The check is strictly-less-than the cap, so the 500th $1 spend (which
+	// would bring the window to exactly $500) is rejected along with the cap.
+	if admitted != 499 {
+		t.Errorf("expected exactly 499 of 1000 concurrent $1 spends to be admitted, got %d", admitted)
+	}
+}
+
+func TestCheckBudget_RejectsOnceCapReached(t *testing.T) {
+	mr := newTestRedis(t)
+	rdb = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	maxBurstBudget = 10.0
+	windowSeconds = 60.0
+	memSpendWind = nil
+
+	if !checkBudget(9.0) {
+		t.Fatal("expected a $9 spend against a $10 cap to be admitted")
+	}
+	if checkBudget(2.0) {
+		t.Fatal("expected a $2 spend on top of $9 already spent to be rejected")
+	}
+}
+
+func TestValidateBid_VetoesWithoutConsent(t *testing.T) {
+	mr := newTestRedis(t)
+	rdb = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	maxBurstBudget = 500.0
+	windowSeconds = 60.0
+	memSpendWind = nil
+	consentManager = compliance.NewConsentManager(nil, compliance.NewInMemoryConsentStore(), zerolog.Nop())
+
+	approved, traceID := ValidateBid("cust-1", 50.0, compliance.ConsentTargeting)
+	if approved {
+		t.Fatal("expected bid to be vetoed for a customer with no consent on file")
+	}
+	if traceID == "" {
+		t.Error("expected a non-empty trace id even on veto")
+	}
+}
+
+func TestValidateBid_ApprovesWithGrantedConsent(t *testing.T) {
+	mr := newTestRedis(t)
+	rdb = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	maxBurstBudget = 500.0
+	windowSeconds = 60.0
+	memSpendWind = nil
+	consentManager = compliance.NewConsentManager(nil, compliance.NewInMemoryConsentStore(), zerolog.Nop())
+	if err := consentManager.GrantConsent("cust-2", compliance.ConsentTargeting, "127.0.0.1", "test-agent", "Consent"); err != nil {
+		t.Fatalf("failed to grant consent: %v", err)
+	}
+
+	approved, _ := ValidateBid("cust-2", 50.0, compliance.ConsentTargeting)
+	if !approved {
+		t.Fatal("expected bid to be approved for a customer with granted consent and valid LTV")
+	}
+}
@@ -0,0 +1,397 @@
+// Package observability provides cross-cutting Prometheus-style metrics and
+// lightweight distributed-tracing spans shared by SyncShield and the
+// connectors it fronts, so a single bid can be followed from the connector
+// that placed it, through the shield's validation, to the compliance
+// logger that recorded the outcome.
+package observability
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// bidKey identifies one combination of platform/decision_source/outcome for
+// the kiki_bids_total counter.
+type bidKey struct {
+	platform       string
+	decisionSource string
+	outcome        string
+}
+
+// Metrics collects the counters and gauges this package exposes, mirroring
+// the hand-rolled collector shield.MetricsCollector uses for circuit
+// breaker stats.
+type Metrics struct {
+	mu sync.RWMutex
+
+	bidsTotal           map[bidKey]int64
+	bidAmountSamples    map[string][]float64 // by platform
+	budgetRemainingUSD  map[string]float64   // gauge, by platform
+	circuitState        map[string]float64   // gauge, by platform (0=closed, 1=half_open, 2=open)
+	fallbackInvocations map[string]int64     // by platform
+	shieldVetoTotal     map[string]int64     // by reason
+
+	rateLimiterTokens      map[string]float64 // gauge, by platform
+	rateLimiterConcurrency map[string]float64 // gauge, by platform
+	rateLimiterRecent429   map[string]float64 // gauge, by platform
+
+	ltvReconciledTotal map[string]int64     // by platform
+	ltvErrorPctSamples map[string][]float64 // by platform
+
+	streamDroppedTotal int64 // counter, process-wide
+	streamSubscribers  int64 // gauge, process-wide
+
+	auditBufferDropped map[string]int64   // by platform
+	auditBufferDepth   map[string]float64 // gauge, by platform
+
+	analyticsSinkDropped map[string]int64 // by fan-out name
+}
+
+// NewMetrics creates an empty metrics collector.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		bidsTotal:              make(map[bidKey]int64),
+		bidAmountSamples:       make(map[string][]float64),
+		budgetRemainingUSD:     make(map[string]float64),
+		circuitState:           make(map[string]float64),
+		fallbackInvocations:    make(map[string]int64),
+		shieldVetoTotal:        make(map[string]int64),
+		rateLimiterTokens:      make(map[string]float64),
+		rateLimiterConcurrency: make(map[string]float64),
+		rateLimiterRecent429:   make(map[string]float64),
+		ltvReconciledTotal:     make(map[string]int64),
+		ltvErrorPctSamples:     make(map[string][]float64),
+		auditBufferDropped:     make(map[string]int64),
+		auditBufferDepth:       make(map[string]float64),
+		analyticsSinkDropped:   make(map[string]int64),
+	}
+}
+
+// RecordBid increments kiki_bids_total for the given platform/decision
+// source/outcome and records amountUSD as a kiki_bid_amount_usd sample.
+func (m *Metrics) RecordBid(platform, decisionSource, outcome string, amountUSD float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bidsTotal[bidKey{platform, decisionSource, outcome}]++
+	m.bidAmountSamples[platform] = append(m.bidAmountSamples[platform], amountUSD)
+}
+
+// SetBudgetRemaining sets the kiki_budget_remaining_usd gauge for platform.
+func (m *Metrics) SetBudgetRemaining(platform string, remainingUSD float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.budgetRemainingUSD[platform] = remainingUSD
+}
+
+// CircuitBreakerState names the kiki_circuit_state gauge values.
+type CircuitBreakerState float64
+
+const (
+	CircuitClosed   CircuitBreakerState = 0
+	CircuitHalfOpen CircuitBreakerState = 1
+	CircuitOpen     CircuitBreakerState = 2
+)
+
+// SetCircuitState sets the kiki_circuit_state gauge for platform.
+func (m *Metrics) SetCircuitState(platform string, state CircuitBreakerState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.circuitState[platform] = float64(state)
+}
+
+// RecordFallback increments kiki_fallback_invocations_total for platform.
+func (m *Metrics) RecordFallback(platform string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fallbackInvocations[platform]++
+}
+
+// RecordVeto increments kiki_shield_veto_total for reason.
+func (m *Metrics) RecordVeto(reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.shieldVetoTotal[reason]++
+}
+
+// SetRateLimiterState sets the kiki_ratelimiter_* gauges for platform from a
+// connector's RateLimiter snapshot.
+func (m *Metrics) SetRateLimiterState(platform string, tokensAvailable float64, concurrency int, recent429Rate float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rateLimiterTokens[platform] = tokensAvailable
+	m.rateLimiterConcurrency[platform] = float64(concurrency)
+	m.rateLimiterRecent429[platform] = recent429Rate
+}
+
+// RecordLTVReconciliation records that an audit entry's PredictedLTV was
+// checked against CRM ground truth for platform, with errorPct being the
+// signed percentage error ((actual-predicted)/predicted * 100).
+func (m *Metrics) RecordLTVReconciliation(platform string, errorPct float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ltvReconciledTotal[platform]++
+	m.ltvErrorPctSamples[platform] = append(m.ltvErrorPctSamples[platform], errorPct)
+}
+
+// RecordStreamDrop increments kiki_stream_dropped_total when a slow audit
+// stream subscriber's ring buffer was full and the oldest queued event had
+// to be dropped to make room for a new one.
+func (m *Metrics) RecordStreamDrop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.streamDroppedTotal++
+}
+
+// SetStreamSubscribers sets the kiki_stream_subscribers gauge to the
+// current number of live audit stream subscribers (SSE and websocket
+// combined).
+func (m *Metrics) SetStreamSubscribers(count int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.streamSubscribers = int64(count)
+}
+
+// RecordAuditBufferDrop increments kiki_audit_buffer_dropped_total when
+// BufferedLogger had to drop an entry (full partition or the in-flight
+// byte cap) for platform.
+func (m *Metrics) RecordAuditBufferDrop(platform string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auditBufferDropped[platform]++
+}
+
+// SetAuditBufferDepth sets the kiki_audit_buffer_depth gauge for platform
+// to the number of entries currently queued in BufferedLogger's partition.
+func (m *Metrics) SetAuditBufferDepth(platform string, depth int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auditBufferDepth[platform] = float64(depth)
+}
+
+// RecordAnalyticsSinkDrop increments kiki_analytics_sink_dropped_total when
+// a FanoutRecorder's queue was full and the oldest queued hook had to be
+// dropped to make room for a new one, for the fan-out identified by name.
+func (m *Metrics) RecordAnalyticsSinkDrop(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.analyticsSinkDropped[name]++
+}
+
+// WriteTo renders every metric in Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var written int64
+	write := func(format string, args ...interface{}) error {
+		n, err := fmt.Fprintf(w, format, args...)
+		written += int64(n)
+		return err
+	}
+
+	if err := write("# HELP kiki_bids_total Bids placed, by platform/decision_source/outcome\n# TYPE kiki_bids_total counter\n"); err != nil {
+		return written, err
+	}
+	keys := make([]bidKey, 0, len(m.bidsTotal))
+	for k := range m.bidsTotal {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].platform != keys[j].platform {
+			return keys[i].platform < keys[j].platform
+		}
+		if keys[i].decisionSource != keys[j].decisionSource {
+			return keys[i].decisionSource < keys[j].decisionSource
+		}
+		return keys[i].outcome < keys[j].outcome
+	})
+	for _, k := range keys {
+		if err := write("kiki_bids_total{platform=%q,decision_source=%q,outcome=%q} %d\n", k.platform, k.decisionSource, k.outcome, m.bidsTotal[k]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_bid_amount_usd Observed bid amounts in USD, by platform\n# TYPE kiki_bid_amount_usd histogram\n"); err != nil {
+		return written, err
+	}
+	for _, platform := range sortedKeys(m.bidAmountSamples) {
+		samples := m.bidAmountSamples[platform]
+		var sum float64
+		for _, s := range samples {
+			sum += s
+		}
+		if err := write("kiki_bid_amount_usd_sum{platform=%q} %.2f\n", platform, sum); err != nil {
+			return written, err
+		}
+		if err := write("kiki_bid_amount_usd_count{platform=%q} %d\n", platform, len(samples)); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_budget_remaining_usd Remaining sliding-window budget in USD, by platform\n# TYPE kiki_budget_remaining_usd gauge\n"); err != nil {
+		return written, err
+	}
+	for _, platform := range sortedFloatKeys(m.budgetRemainingUSD) {
+		if err := write("kiki_budget_remaining_usd{platform=%q} %.2f\n", platform, m.budgetRemainingUSD[platform]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_circuit_state Circuit breaker state (0=closed,1=half_open,2=open), by platform\n# TYPE kiki_circuit_state gauge\n"); err != nil {
+		return written, err
+	}
+	for _, platform := range sortedFloatKeys(m.circuitState) {
+		if err := write("kiki_circuit_state{platform=%q} %.0f\n", platform, m.circuitState[platform]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_fallback_invocations_total Fallback heuristic invocations, by platform\n# TYPE kiki_fallback_invocations_total counter\n"); err != nil {
+		return written, err
+	}
+	for _, platform := range sortedIntKeys(m.fallbackInvocations) {
+		if err := write("kiki_fallback_invocations_total{platform=%q} %d\n", platform, m.fallbackInvocations[platform]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_shield_veto_total Bids vetoed by the shield governor, by reason\n# TYPE kiki_shield_veto_total counter\n"); err != nil {
+		return written, err
+	}
+	for _, reason := range sortedIntKeys(m.shieldVetoTotal) {
+		if err := write("kiki_shield_veto_total{reason=%q} %d\n", reason, m.shieldVetoTotal[reason]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_ratelimiter_tokens_available Token-bucket tokens currently available, by platform\n# TYPE kiki_ratelimiter_tokens_available gauge\n"); err != nil {
+		return written, err
+	}
+	for _, platform := range sortedFloatKeys(m.rateLimiterTokens) {
+		if err := write("kiki_ratelimiter_tokens_available{platform=%q} %.2f\n", platform, m.rateLimiterTokens[platform]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_ratelimiter_concurrency AIMD adaptive concurrency ceiling, by platform\n# TYPE kiki_ratelimiter_concurrency gauge\n"); err != nil {
+		return written, err
+	}
+	for _, platform := range sortedFloatKeys(m.rateLimiterConcurrency) {
+		if err := write("kiki_ratelimiter_concurrency{platform=%q} %.0f\n", platform, m.rateLimiterConcurrency[platform]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_ratelimiter_recent_429_rate Fraction of recent calls that were rate-limited or failed, by platform\n# TYPE kiki_ratelimiter_recent_429_rate gauge\n"); err != nil {
+		return written, err
+	}
+	for _, platform := range sortedFloatKeys(m.rateLimiterRecent429) {
+		if err := write("kiki_ratelimiter_recent_429_rate{platform=%q} %.2f\n", platform, m.rateLimiterRecent429[platform]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_ltv_reconciled_total LTV predictions checked against CRM ground truth, by platform\n# TYPE kiki_ltv_reconciled_total counter\n"); err != nil {
+		return written, err
+	}
+	for _, platform := range sortedIntKeys(m.ltvReconciledTotal) {
+		if err := write("kiki_ltv_reconciled_total{platform=%q} %d\n", platform, m.ltvReconciledTotal[platform]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_ltv_error_pct Signed percentage error between predicted and reconciled LTV, by platform\n# TYPE kiki_ltv_error_pct histogram\n"); err != nil {
+		return written, err
+	}
+	for _, platform := range sortedKeys(m.ltvErrorPctSamples) {
+		samples := m.ltvErrorPctSamples[platform]
+		var sum float64
+		for _, s := range samples {
+			sum += s
+		}
+		if err := write("kiki_ltv_error_pct_sum{platform=%q} %.2f\n", platform, sum); err != nil {
+			return written, err
+		}
+		if err := write("kiki_ltv_error_pct_count{platform=%q} %d\n", platform, len(samples)); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_stream_dropped_total Audit stream events dropped due to a full subscriber buffer\n# TYPE kiki_stream_dropped_total counter\n"); err != nil {
+		return written, err
+	}
+	if err := write("kiki_stream_dropped_total %d\n", m.streamDroppedTotal); err != nil {
+		return written, err
+	}
+
+	if err := write("\n# HELP kiki_stream_subscribers Live audit stream subscribers (SSE and websocket combined)\n# TYPE kiki_stream_subscribers gauge\n"); err != nil {
+		return written, err
+	}
+	if err := write("kiki_stream_subscribers %d\n", m.streamSubscribers); err != nil {
+		return written, err
+	}
+
+	if err := write("\n# HELP kiki_audit_buffer_dropped_total Audit entries dropped by BufferedLogger, by platform\n# TYPE kiki_audit_buffer_dropped_total counter\n"); err != nil {
+		return written, err
+	}
+	for _, platform := range sortedIntKeys(m.auditBufferDropped) {
+		if err := write("kiki_audit_buffer_dropped_total{platform=%q} %d\n", platform, m.auditBufferDropped[platform]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_audit_buffer_depth Entries currently queued in BufferedLogger's partition, by platform\n# TYPE kiki_audit_buffer_depth gauge\n"); err != nil {
+		return written, err
+	}
+	for _, platform := range sortedFloatKeys(m.auditBufferDepth) {
+		if err := write("kiki_audit_buffer_depth{platform=%q} %.0f\n", platform, m.auditBufferDepth[platform]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("\n# HELP kiki_analytics_sink_dropped_total Analytics hooks dropped by a FanoutRecorder's full queue, by fan-out name\n# TYPE kiki_analytics_sink_dropped_total counter\n"); err != nil {
+		return written, err
+	}
+	for _, name := range sortedIntKeys(m.analyticsSinkDropped) {
+		if err := write("kiki_analytics_sink_dropped_total{name=%q} %d\n", name, m.analyticsSinkDropped[name]); err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+func sortedKeys(m map[string][]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedFloatKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedIntKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Default is the process-wide metrics collector, analogous to how
+// shield.MetricsCollector is attached to a single CircuitBreaker; bids and
+// shield decisions across the module share one registry so /metrics can
+// report on all of them.
+var Default = NewMetrics()
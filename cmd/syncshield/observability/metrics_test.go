@@ -0,0 +1,57 @@
+package observability
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetricsWriteTo(t *testing.T) {
+	m := NewMetrics()
+	m.RecordBid("amazon", "ai", "success", 4.20)
+	m.RecordBid("amazon", "fallback", "budget_exceeded", 1.00)
+	m.SetBudgetRemaining("amazon", 95.80)
+	m.SetCircuitState("amazon", CircuitHalfOpen)
+	m.RecordFallback("amazon")
+	m.RecordVeto("budget_exceeded")
+	m.SetRateLimiterState("amazon", 42.5, 25, 0.1)
+
+	var buf strings.Builder
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`kiki_bids_total{platform="amazon",decision_source="ai",outcome="success"} 1`,
+		`kiki_bid_amount_usd_sum{platform="amazon"} 5.20`,
+		`kiki_budget_remaining_usd{platform="amazon"} 95.80`,
+		`kiki_circuit_state{platform="amazon"} 1`,
+		`kiki_fallback_invocations_total{platform="amazon"} 1`,
+		`kiki_shield_veto_total{reason="budget_exceeded"} 1`,
+		`kiki_ratelimiter_tokens_available{platform="amazon"} 42.50`,
+		`kiki_ratelimiter_concurrency{platform="amazon"} 25`,
+		`kiki_ratelimiter_recent_429_rate{platform="amazon"} 0.10`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestSpanString(t *testing.T) {
+	span := StartSpan("test")
+	if span.TraceID == "" || span.SpanID == "" {
+		t.Fatal("expected non-empty trace and span ids")
+	}
+	if !strings.Contains(span.String(), ":") {
+		t.Errorf("expected String() to join trace and span id, got %q", span.String())
+	}
+
+	child := StartChildSpan(span.TraceID, "child")
+	if child.TraceID != span.TraceID {
+		t.Errorf("expected child span to inherit trace id %q, got %q", span.TraceID, child.TraceID)
+	}
+	if child.SpanID == span.SpanID {
+		t.Error("expected child span to get its own span id")
+	}
+}
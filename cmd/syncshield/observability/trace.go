@@ -0,0 +1,104 @@
+package observability
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Span is a minimal OpenTelemetry-style span: a TraceID that follows a bid
+// across process boundaries (connector -> shield -> compliance logger) and
+// a SpanID scoped to this one operation. It intentionally doesn't carry a
+// full OTEL SDK dependency, the same way shield.MetricsCollector exposes
+// Prometheus-shaped metrics without the client_golang library.
+type Span struct {
+	TraceID string
+	SpanID  string
+	Name    string
+	Start   time.Time
+}
+
+// StartSpan begins a new root span named name.
+func StartSpan(name string) *Span {
+	return &Span{
+		TraceID: randomHex(16),
+		SpanID:  randomHex(8),
+		Name:    name,
+		Start:   time.Now(),
+	}
+}
+
+// StartChildSpan begins a span under an existing traceID, e.g. one carried
+// across an HTTP call via a trace-id header or query parameter.
+func StartChildSpan(traceID, name string) *Span {
+	if traceID == "" {
+		return StartSpan(name)
+	}
+	return &Span{
+		TraceID: traceID,
+		SpanID:  randomHex(8),
+		Name:    name,
+		Start:   time.Now(),
+	}
+}
+
+// Finish returns the span's elapsed duration.
+func (s *Span) Finish() time.Duration {
+	return time.Since(s.Start)
+}
+
+// String renders a short "trace_id:span_id" form suitable for embedding in
+// a BidResponse.Message or an audit log line.
+func (s *Span) String() string {
+	return fmt.Sprintf("%s:%s", s.TraceID, s.SpanID)
+}
+
+// Traceparent renders s as a W3C traceparent header value
+// ("00-<trace-id>-<span-id>-01") so it can cross an HTTP hop and let a
+// downstream service's StartChildSpan pick up the same trace.
+func (s *Span) Traceparent() string {
+	traceID := s.TraceID
+	if len(traceID) != 32 {
+		// W3C trace-id is 16 bytes (32 hex chars); pad or truncate rather
+		// than emit a malformed header a collector would reject outright.
+		traceID = (traceID + "00000000000000000000000000000000")[:32]
+	}
+	spanID := s.SpanID
+	if len(spanID) != 16 {
+		spanID = (spanID + "0000000000000000")[:16]
+	}
+	return fmt.Sprintf("00-%s-%s-01", traceID, spanID)
+}
+
+// spanContextKey is the unexported key ContextWithSpan/SpanFromContext use
+// so callers thread a *Span through context.Context the same way the
+// standard library threads a deadline or cancellation signal.
+type spanContextKey struct{}
+
+// ContextWithSpan returns a copy of ctx carrying span, so a function several
+// calls deep (an LTV gRPC call, a SyncShield HTTP call, an audit write) can
+// recover it via SpanFromContext and continue the same trace.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext recovers the *Span stored by ContextWithSpan, or starts a
+// fresh root span named name if ctx doesn't carry one.
+func SpanFromContext(ctx context.Context, name string) *Span {
+	if span, ok := ctx.Value(spanContextKey{}).(*Span); ok && span != nil {
+		return span
+	}
+	return StartSpan(name)
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand failing is effectively unrecoverable for the process;
+		// fall back to a timestamp so tracing degrades instead of panicking.
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,236 @@
+package shield
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrLimitExceeded is returned by AdaptiveLimiter.Acquire when no slot
+// opened up within the caller's timeout.
+var ErrLimitExceeded = errors.New("shield: adaptive limit exceeded")
+
+// adaptiveLimiterPollInterval bounds how often a blocked Acquire rechecks
+// for a free slot. Polling (rather than a sync.Cond wired to ctx.Done)
+// keeps Acquire's control flow as a plain loop, at the cost of up to this
+// much extra latency admitting a caller the instant one frees up.
+const adaptiveLimiterPollInterval = 2 * time.Millisecond
+
+// AdaptiveLimiter is a concurrency limiter from the Vegas/Gradient2 family
+// (see Netflix's concurrency-limits and failsafe-go's adaptivelimiter):
+// rather than a fixed ceiling an operator has to guess at, it estimates how
+// much concurrency the downstream call can sustain without queueing, from
+// the RTTs it observes, and adjusts that estimate every Window instead of
+// needing a restart to retune. It exists because CircuitBreaker only reacts
+// after latency has already crossed its threshold - AdaptiveLimiter is
+// meant to smooth a burst before it gets that far, by capping how much of
+// it a platform takes on concurrently in the first place.
+type AdaptiveLimiter struct {
+	MinLimit float64
+	MaxLimit float64
+
+	// Alpha is the additive growth applied to the limit each Window when
+	// queueSize stays under Threshold. Beta is the decrease applied when
+	// queueSize exceeds it.
+	Alpha float64
+	Beta  float64
+
+	// Threshold is the queueSize a Window's samples must exceed before the
+	// limit is reduced; staying at or under it grows the limit instead.
+	Threshold float64
+
+	// Window is how often accumulated RTT samples are folded into a new
+	// limit.
+	Window time.Duration
+
+	// AcquireTimeout is the timeout Execute passes to Acquire. Direct
+	// Acquire callers supply their own timeout instead.
+	AcquireTimeout time.Duration
+
+	mu          sync.Mutex
+	limit       float64
+	inflight    int
+	rttNoLoad   time.Duration // minimum observed RTT, the "no queueing" baseline
+	windowRTTs  []time.Duration
+	windowStart time.Time
+
+	eventBus    *EventBus
+	eventSource string
+}
+
+// NewAdaptiveLimiter returns an AdaptiveLimiter seeded at minLimit, free to
+// grow up to maxLimit as observed RTTs allow.
+func NewAdaptiveLimiter(minLimit, maxLimit float64) *AdaptiveLimiter {
+	return &AdaptiveLimiter{
+		MinLimit:       minLimit,
+		MaxLimit:       maxLimit,
+		Alpha:          1,
+		Beta:           1,
+		Threshold:      1,
+		Window:         time.Second,
+		AcquireTimeout: 2 * time.Second,
+		limit:          minLimit,
+	}
+}
+
+// SetEventBus attaches bus so a denied Acquire publishes
+// EventPolicyShortCircuited. source identifies this limiter in published
+// events. A nil bus disables publishing.
+func (al *AdaptiveLimiter) SetEventBus(bus *EventBus, source string) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.eventBus = bus
+	al.eventSource = source
+}
+
+// Acquire blocks up to timeout for a free slot under the current limit. On
+// success it returns a release func the caller must invoke exactly once
+// with the call's observed latency and error (nil on success): latency
+// feeds the estimator, and a non-nil err shrinks this instance's limit
+// immediately rather than waiting for the next Window, so one failing
+// platform backs off on its own without starving the others sharing this
+// package's CircuitBreaker-level protection. On failure it returns
+// ErrLimitExceeded, or ctx.Err() if ctx was canceled first.
+func (al *AdaptiveLimiter) Acquire(ctx context.Context, timeout time.Duration) (func(latency time.Duration, err error), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		al.mu.Lock()
+		if al.inflight < al.limitLocked() {
+			al.inflight++
+			al.mu.Unlock()
+			var once sync.Once
+			return func(latency time.Duration, err error) {
+				once.Do(func() { al.release(latency, err) })
+			}, nil
+		}
+		al.mu.Unlock()
+
+		wait := adaptiveLimiterPollInterval
+		if remaining := time.Until(deadline); remaining < wait {
+			if remaining <= 0 {
+				al.publishDenied()
+				return nil, ErrLimitExceeded
+			}
+			wait = remaining
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Execute implements Policy: it acquires a slot (bounded by
+// AcquireTimeout), runs inner, and feeds inner's latency and error back
+// into the estimator.
+func (al *AdaptiveLimiter) Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	release, err := al.Acquire(ctx, al.AcquireTimeout)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	result, err := inner(ctx)
+	release(time.Since(start), err)
+	return result, err
+}
+
+func (al *AdaptiveLimiter) publishDenied() {
+	al.mu.Lock()
+	bus, source := al.eventBus, al.eventSource
+	al.mu.Unlock()
+	publishShortCircuit(bus, source, "adaptive_limiter", "no capacity available")
+}
+
+// limitLocked floors the current limit at 1 slot, so a limit between
+// MinLimit and 1 (possible right after a steep Beta decrease) never wedges
+// every caller out.
+func (al *AdaptiveLimiter) limitLocked() int {
+	if al.limit < 1 {
+		return 1
+	}
+	return int(al.limit)
+}
+
+func (al *AdaptiveLimiter) release(latency time.Duration, err error) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.inflight--
+
+	if err != nil {
+		// Immediate drop: a failure shrinks this instance's budget right
+		// away instead of waiting for the window to close.
+		al.limit = math.Max(al.MinLimit, al.limit-al.Beta)
+		return
+	}
+
+	if al.rttNoLoad == 0 || latency < al.rttNoLoad {
+		al.rttNoLoad = latency
+	}
+	al.windowRTTs = append(al.windowRTTs, latency)
+	if al.windowStart.IsZero() {
+		al.windowStart = time.Now()
+	}
+	if time.Since(al.windowStart) >= al.Window {
+		al.recomputeLimitLocked()
+	}
+}
+
+// recomputeLimitLocked folds the window's accumulated RTT samples into a
+// new limit: queueSize = inflight - (limit * rttNoLoad/rttCurrent)
+// estimates how many of the inflight calls are queued rather than actively
+// being served, given how much slower rttCurrent is than the no-load
+// baseline. A small queueSize means there's slack to grow into; a large one
+// means the platform is falling behind and the limit backs off.
+func (al *AdaptiveLimiter) recomputeLimitLocked() {
+	defer func() {
+		al.windowRTTs = al.windowRTTs[:0]
+		al.windowStart = time.Now()
+	}()
+
+	if len(al.windowRTTs) == 0 || al.rttNoLoad <= 0 {
+		return
+	}
+
+	var sum time.Duration
+	for _, d := range al.windowRTTs {
+		sum += d
+	}
+	rttCurrent := sum / time.Duration(len(al.windowRTTs))
+	if rttCurrent <= 0 {
+		return
+	}
+
+	queueSize := float64(al.inflight) - al.limit*(float64(al.rttNoLoad)/float64(rttCurrent))
+
+	if queueSize < al.Threshold {
+		al.limit += al.Alpha
+	} else if queueSize > al.Threshold {
+		al.limit -= al.Beta
+	}
+
+	if al.limit < al.MinLimit {
+		al.limit = al.MinLimit
+	}
+	if al.limit > al.MaxLimit {
+		al.limit = al.MaxLimit
+	}
+}
+
+// AdaptiveLimiterStats is AdaptiveLimiter's point-in-time snapshot, for
+// operator-facing status endpoints like GetCircuitBreakerStatus.
+type AdaptiveLimiterStats struct {
+	Limit     float64
+	Inflight  int
+	RTTNoLoad time.Duration
+}
+
+// Stats returns al's current limit, inflight count, and RTT baseline.
+func (al *AdaptiveLimiter) Stats() AdaptiveLimiterStats {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	return AdaptiveLimiterStats{Limit: al.limit, Inflight: al.inflight, RTTNoLoad: al.rttNoLoad}
+}
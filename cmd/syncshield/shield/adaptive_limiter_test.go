@@ -0,0 +1,112 @@
+package shield
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestAdaptiveLimiterAcquireRelease tests the basic slot lifecycle.
+func TestAdaptiveLimiterAcquireRelease(t *testing.T) {
+	al := NewAdaptiveLimiter(1, 4)
+
+	release, err := al.Acquire(context.Background(), time.Second)
+	if err != nil {
+		t.Fatalf("expected to acquire a free slot, got %v", err)
+	}
+	if stats := al.Stats(); stats.Inflight != 1 {
+		t.Errorf("expected Inflight 1 after Acquire, got %d", stats.Inflight)
+	}
+
+	release(10*time.Millisecond, nil)
+	if stats := al.Stats(); stats.Inflight != 0 {
+		t.Errorf("expected Inflight 0 after release, got %d", stats.Inflight)
+	}
+}
+
+// TestAdaptiveLimiterExceedsLimit tests that Acquire returns
+// ErrLimitExceeded once every slot is in use and the timeout elapses.
+func TestAdaptiveLimiterExceedsLimit(t *testing.T) {
+	al := NewAdaptiveLimiter(1, 1)
+
+	release, err := al.Acquire(context.Background(), time.Second)
+	if err != nil {
+		t.Fatalf("expected to acquire the only slot, got %v", err)
+	}
+	defer release(0, nil)
+
+	_, err = al.Acquire(context.Background(), 20*time.Millisecond)
+	if err != ErrLimitExceeded {
+		t.Errorf("expected ErrLimitExceeded while the only slot is held, got %v", err)
+	}
+}
+
+// TestAdaptiveLimiterReleaseUnblocksWaiter tests that releasing a slot
+// admits a caller blocked in Acquire.
+func TestAdaptiveLimiterReleaseUnblocksWaiter(t *testing.T) {
+	al := NewAdaptiveLimiter(1, 1)
+
+	release, err := al.Acquire(context.Background(), time.Second)
+	if err != nil {
+		t.Fatalf("expected to acquire the only slot, got %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		r, err := al.Acquire(context.Background(), time.Second)
+		if err == nil {
+			r(0, nil)
+		}
+		done <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	release(0, nil)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected waiter to acquire once released, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter never unblocked after release")
+	}
+}
+
+// TestAdaptiveLimiterImmediateDropOnFailure tests that release(_, err)
+// shrinks the limit right away instead of waiting for the window to close.
+func TestAdaptiveLimiterImmediateDropOnFailure(t *testing.T) {
+	al := NewAdaptiveLimiter(1, 10)
+	al.limit = 5
+	al.Beta = 2
+
+	release, err := al.Acquire(context.Background(), time.Second)
+	if err != nil {
+		t.Fatalf("expected to acquire a slot, got %v", err)
+	}
+	release(0, errors.New("platform returned an error"))
+
+	if stats := al.Stats(); stats.Limit != 3 {
+		t.Errorf("expected limit to drop by Beta to 3, got %v", stats.Limit)
+	}
+}
+
+// TestAdaptiveLimiterGrowsWithinWindow tests that a window of fast,
+// successful calls grows the limit toward MaxLimit.
+func TestAdaptiveLimiterGrowsWithinWindow(t *testing.T) {
+	al := NewAdaptiveLimiter(1, 10)
+	al.Window = 0 // recompute on every release, instead of waiting for real time to pass
+
+	for i := 0; i < 3; i++ {
+		release, err := al.Acquire(context.Background(), time.Second)
+		if err != nil {
+			t.Fatalf("expected to acquire a slot, got %v", err)
+		}
+		release(5*time.Millisecond, nil)
+	}
+
+	if stats := al.Stats(); stats.Limit <= al.MinLimit {
+		t.Errorf("expected limit to grow above MinLimit after a window of fast calls, got %v", stats.Limit)
+	}
+}
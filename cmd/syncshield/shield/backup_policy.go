@@ -0,0 +1,131 @@
+package shield
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BackupPolicy implements the "backup request" (a.k.a. hedged request)
+// pattern from Kitex and, originally, Dean & Barroso's "The Tail at Scale":
+// it fires the primary call, and - if no response has arrived within Delay
+// - fires one or more backup copies concurrently, returning whichever
+// completes successfully first and cancelling the rest via a shared ctx.
+// Unlike RetryPolicy, which only starts its next attempt after the
+// previous one has already failed, BackupPolicy races the next attempt
+// against the current one, trading extra load for tail-latency reduction
+// instead of RetryPolicy's all-or-nothing "eventually succeed or give up".
+//
+// BackupPolicy deliberately doesn't implement Policy: ExecuteWithBackup
+// takes a *CircuitBreaker directly, the same way connectors.CallPolicy.Execute
+// does, so every in-flight copy records its own success/failure against the
+// breaker independently rather than only the winning copy's outcome
+// counting.
+type BackupPolicy struct {
+	// Delay is how long ExecuteWithBackup waits for the primary attempt
+	// before firing a backup copy.
+	Delay time.Duration
+	// MaxAttempts is the maximum number of concurrent copies, including the
+	// primary. Must be >= 1.
+	MaxAttempts int
+
+	mu          sync.Mutex
+	eventBus    *EventBus
+	eventSource string
+}
+
+// SetEventBus attaches bus so an exhausted backup request (every copy
+// failed) publishes EventPolicyShortCircuited. source identifies this
+// policy in published events. A nil bus disables publishing.
+func (bp *BackupPolicy) SetEventBus(bus *EventBus, source string) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.eventBus = bus
+	bp.eventSource = source
+}
+
+type backupResult struct {
+	result interface{}
+	err    error
+}
+
+// ExecuteWithBackup fires fn once immediately, and - while no copy has yet
+// succeeded - fires another copy each time Delay elapses, up to
+// bp.MaxAttempts total. cb, if non-nil, records every copy's latency and
+// outcome against it independently, even the losing copies, so a slow
+// platform trips the breaker just as it would under plain retries. The
+// first successful copy's result is returned immediately and every other
+// in-flight copy is canceled through ctx; if every copy fails, the last
+// error is returned.
+func (bp *BackupPolicy) ExecuteWithBackup(ctx context.Context, cb *CircuitBreaker, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	maxAttempts := bp.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan backupResult, maxAttempts)
+	launch := func() {
+		go func() {
+			start := time.Now()
+			result, err := fn(ctx)
+			if cb != nil {
+				if err != nil {
+					cb.RecordFailure(time.Since(start))
+				} else {
+					cb.RecordSuccess(time.Since(start))
+				}
+			}
+			results <- backupResult{result: result, err: err}
+		}()
+	}
+
+	launch()
+	launched := 1
+	pending := 1
+	var lastErr error
+
+	for launched < maxAttempts {
+		timer := time.NewTimer(bp.Delay)
+		select {
+		case res := <-results:
+			timer.Stop()
+			pending--
+			if res.err == nil {
+				return res.result, nil
+			}
+			lastErr = res.err
+		case <-timer.C:
+			launch()
+			launched++
+			pending++
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	// Every copy is launched; drain remaining results until one succeeds or
+	// all are exhausted.
+	for ; pending > 0; pending-- {
+		select {
+		case res := <-results:
+			if res.err == nil {
+				return res.result, nil
+			}
+			lastErr = res.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	bp.mu.Lock()
+	bus, source := bp.eventBus, bp.eventSource
+	bp.mu.Unlock()
+	publishShortCircuit(bus, source, "backup_request", "all copies failed")
+
+	return nil, fmt.Errorf("shield: all %d backup request copies failed: %w", launched, lastErr)
+}
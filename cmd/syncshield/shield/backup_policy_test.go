@@ -0,0 +1,118 @@
+package shield
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestBackupPolicyReturnsFirstSuccess tests that a backup copy wins when
+// the primary attempt is slower than Delay, and that the loser's result
+// never reaches the caller.
+func TestBackupPolicyReturnsFirstSuccess(t *testing.T) {
+	bp := &BackupPolicy{Delay: 20 * time.Millisecond, MaxAttempts: 2}
+
+	var calls int32
+	result, err := bp.ExecuteWithBackup(context.Background(), nil, func(ctx context.Context) (interface{}, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			// Primary attempt: slow enough to be backed up.
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(200 * time.Millisecond):
+				return "slow", nil
+			}
+		}
+		return "fast", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "fast" {
+		t.Errorf("expected the backup copy to win with \"fast\", got %v", result)
+	}
+}
+
+// TestBackupPolicyRecordsEachCopyIndependentlyButReturnsOnlyTheWinner tests
+// that the caller only ever sees the winning copy's result, while the
+// CircuitBreaker still records every copy's outcome independently - so a
+// slow-but-eventually-successful loser still counts toward the breaker's
+// health the same way a plain (non-backed-up) call would.
+func TestBackupPolicyRecordsEachCopyIndependentlyButReturnsOnlyTheWinner(t *testing.T) {
+	bp := &BackupPolicy{Delay: 10 * time.Millisecond, MaxAttempts: 2}
+	cb := NewCircuitBreaker()
+
+	release := make(chan struct{})
+	var calls int32
+	result, err := bp.ExecuteWithBackup(context.Background(), cb, func(ctx context.Context) (interface{}, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			<-release // primary only completes after the backup copy has already won
+			return "primary", nil
+		}
+		return "backup", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "backup" {
+		t.Errorf("expected the caller to see only the winning copy's result \"backup\", got %v", result)
+	}
+
+	close(release)
+	time.Sleep(20 * time.Millisecond) // let the released primary also record its success
+	if stats := cb.GetStats(); stats.SuccessfulRequests != 2 {
+		t.Errorf("expected both copies to record a success independently, got %d", stats.SuccessfulRequests)
+	}
+}
+
+// TestBackupPolicyExhaustedReturnsLastError tests that BackupPolicy
+// publishes a short-circuit event and returns an error once every copy
+// fails.
+func TestBackupPolicyExhaustedReturnsLastError(t *testing.T) {
+	bp := &BackupPolicy{Delay: 5 * time.Millisecond, MaxAttempts: 2}
+	bus := NewEventBus()
+	bp.SetEventBus(bus, "test")
+	sub := bus.Subscribe()
+
+	_, err := bp.ExecuteWithBackup(context.Background(), nil, func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("expected an error once every backup copy fails")
+	}
+
+	select {
+	case event := <-sub:
+		if event.Type != EventPolicyShortCircuited {
+			t.Errorf("expected EventPolicyShortCircuited, got %v", event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a short-circuit event, got none")
+	}
+}
+
+// TestBackupPolicySingleAttemptBehavesLikePlainCall tests that
+// MaxAttempts of 1 never fires a backup copy.
+func TestBackupPolicySingleAttemptBehavesLikePlainCall(t *testing.T) {
+	bp := &BackupPolicy{Delay: 5 * time.Millisecond, MaxAttempts: 1}
+
+	var calls int32
+	result, err := bp.ExecuteWithBackup(context.Background(), nil, func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return "only", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "only" {
+		t.Errorf("expected \"only\", got %v", result)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("expected exactly 1 call with MaxAttempts 1, got %d", calls)
+	}
+}
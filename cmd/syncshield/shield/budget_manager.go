@@ -1,132 +1,381 @@
-package shield
-
-import (
-	"sync"
-	"time"
-)
-
-// BidRecord stores a single bid transaction with timestamp and amount
-type BidRecord struct {
-	Timestamp time.Time
-	Amount    float64
-}
-
-// BudgetManager provides thread-safe sliding window budget tracking
-// Critical for preventing overspend and API rate limit compliance
-type BudgetManager struct {
-	mu             sync.Mutex
-	records        []BidRecord
-	windowSize     time.Duration
-	maxBurstBudget float64
-}
-
-// NewBudgetManager creates a new budget manager with specified max budget
-func NewBudgetManager(maxBudget float64) *BudgetManager {
-	return &BudgetManager{
-		windowSize:     10 * time.Minute,
-		maxBurstBudget: maxBudget,
-		records:        make([]BidRecord, 0, 1000), // Pre-allocate capacity
-	}
-}
-
-// AddSpend records a successful bid and prunes expired records
-// This is called after a bid is successfully placed on an ad platform
-func (bm *BudgetManager) AddSpend(amount float64) {
-	bm.mu.Lock()
-	defer bm.mu.Unlock()
-
-	bm.records = append(bm.records, BidRecord{
-		Timestamp: time.Now(),
-		Amount:    amount,
-	})
-	bm.prune()
-}
-
-// CanSpend checks if adding the next bid would exceed the burst limit
-// Returns true if the bid can be placed without exceeding budget constraints
-func (bm *BudgetManager) CanSpend(nextAmount float64) bool {
-	bm.mu.Lock()
-	defer bm.mu.Unlock()
-
-	bm.prune()
-	var currentTotal float64
-	for _, r := range bm.records {
-		currentTotal += r.Amount
-	}
-
-	return (currentTotal + nextAmount) <= bm.maxBurstBudget
-}
-
-// GetCurrentSpend returns the total spend within the current window
-func (bm *BudgetManager) GetCurrentSpend() float64 {
-	bm.mu.Lock()
-	defer bm.mu.Unlock()
-
-	bm.prune()
-	var total float64
-	for _, r := range bm.records {
-		total += r.Amount
-	}
-	return total
-}
-
-// GetRemainingBudget returns how much budget is left in the current window
-func (bm *BudgetManager) GetRemainingBudget() float64 {
-	bm.mu.Lock()
-	defer bm.mu.Unlock()
-
-	bm.prune()
-	var total float64
-	for _, r := range bm.records {
-		total += r.Amount
-	}
-	return bm.maxBurstBudget - total
-}
-
-// prune removes expired records outside the sliding window
-// Must be called with lock held
-func (bm *BudgetManager) prune() {
-	cutoff := time.Now().Add(-bm.windowSize)
-	i := 0
-	for i < len(bm.records) && bm.records[i].Timestamp.Before(cutoff) {
-		i++
-	}
-	bm.records = bm.records[i:]
-}
-
-// GetWindowStats returns statistics about the current budget window
-type WindowStats struct {
-	CurrentSpend    float64
-	MaxBudget       float64
-	RemainingBudget float64
-	RecordCount     int
-	OldestRecord    time.Time
-	WindowDuration  time.Duration
-}
-
-// GetStats returns current window statistics for monitoring
-func (bm *BudgetManager) GetStats() WindowStats {
-	bm.mu.Lock()
-	defer bm.mu.Unlock()
-
-	bm.prune()
-
-	var total float64
-	var oldest time.Time
-	if len(bm.records) > 0 {
-		oldest = bm.records[0].Timestamp
-	}
-
-	for _, r := range bm.records {
-		total += r.Amount
-	}
-
-	return WindowStats{
-		CurrentSpend:    total,
-		MaxBudget:       bm.maxBurstBudget,
-		RemainingBudget: bm.maxBurstBudget - total,
-		RecordCount:     len(bm.records),
-		OldestRecord:    oldest,
-		WindowDuration:  bm.windowSize,
-	}
-}
+package shield
+
+import (
+	"sync"
+	"time"
+)
+
+// BidRecord stores a single bid transaction with timestamp and amount
+type BidRecord struct {
+	Timestamp time.Time
+	Amount    float64
+}
+
+// BudgetManager provides thread-safe sliding window budget tracking
+// Critical for preventing overspend and API rate limit compliance
+//
+// The window is tracked as a ring of fixed-width time buckets plus a
+// maintained currentTotal, so AddSpend/CanSpend/GetStats are O(1) amortized
+// instead of rescanning every record in the window - the hot path under the
+// burst scenarios (hundreds of bids/minute across many platforms) this
+// package is built for. records is kept alongside purely for audit
+// fidelity (GetStats' OldestRecord/RecordCount) and isn't on the hot path.
+type BudgetManager struct {
+	mu             sync.Mutex
+	windowSize     time.Duration
+	maxBurstBudget float64
+
+	// softLimit and committedLimit add a tiered bound on top of
+	// maxBurstBudget (the hard limit): Classify degrades bids once spend
+	// crosses softLimit, before the hard veto at maxBurstBudget kicks in.
+	// committedLimit is the optional finalized-spend floor tracked via
+	// RecordCommit; zero disables it.
+	softLimit      float64
+	committedLimit float64
+	committedSpend float64
+	pendingBids    map[string]float64
+
+	bucketWidth  time.Duration
+	buckets      []float64
+	currentTotal float64
+	lastBucket   int64
+	initialized  bool
+
+	records []BidRecord
+
+	eventBus    *EventBus
+	eventSource string
+}
+
+// BudgetDecision is Classify's verdict on whether a prospective bid amount
+// fits within the manager's tiered bounds.
+type BudgetDecision int
+
+const (
+	// BudgetAllow means the bid fits under the soft limit; bid as planned.
+	BudgetAllow BudgetDecision = iota
+	// BudgetAllowWithDegradation means the bid would push spend over the
+	// soft limit but still under the hard limit; callers should bid via a
+	// cheaper heuristic rather than full AI-predicted value.
+	BudgetAllowWithDegradation
+	// BudgetDeny means the bid would push spend over the hard limit.
+	BudgetDeny
+)
+
+// String returns a human readable decision.
+func (d BudgetDecision) String() string {
+	switch d {
+	case BudgetAllow:
+		return "allow"
+	case BudgetAllowWithDegradation:
+		return "allow_with_degradation"
+	case BudgetDeny:
+		return "deny"
+	default:
+		return "unknown"
+	}
+}
+
+// budgetThresholds are the utilization ratios AddSpend watches for, in
+// ascending order, so an EventBudgetThresholdCrossed fires the moment
+// spend crosses each one.
+var budgetThresholds = []float64{0.5, 0.8, 0.95, 1.0}
+
+// SetEventBus attaches bus so AddSpend publishes EventBudgetThresholdCrossed
+// as utilization crosses 50/80/95/100%. source identifies this manager in
+// published events (e.g. "x_smart"). A nil bus disables publishing.
+func (bm *BudgetManager) SetEventBus(bus *EventBus, source string) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	bm.eventBus = bus
+	bm.eventSource = source
+}
+
+// NewBudgetManager creates a new budget manager with specified max budget,
+// using the default 10-minute window in one-second buckets. The soft limit
+// equals the hard limit, so Classify never returns
+// BudgetAllowWithDegradation - callers that want graceful degradation
+// before the hard veto should use NewBudgetManagerWithLimits instead.
+func NewBudgetManager(maxBudget float64) *BudgetManager {
+	return NewBudgetManagerWithConfig(maxBudget, 10*time.Minute, time.Second)
+}
+
+// NewBudgetManagerWithConfig creates a budget manager with a configurable
+// window size and bucket width. windowSize/bucketWidth buckets are
+// allocated up front (e.g. 600 one-second buckets for a 10-minute window);
+// a bucketWidth that doesn't evenly divide windowSize rounds the bucket
+// count down, slightly widening the effective window.
+func NewBudgetManagerWithConfig(maxBudget float64, windowSize, bucketWidth time.Duration) *BudgetManager {
+	return newBudgetManager(maxBudget, maxBudget, 0, windowSize, bucketWidth)
+}
+
+// NewBudgetManagerWithLimits creates a tiered budget manager using the
+// default 10-minute window in one-second buckets. softLimit triggers
+// Classify's BudgetAllowWithDegradation before hardLimit's BudgetDeny.
+// committedLimit is the optional finalized-spend floor tracked via
+// RecordCommit; pass 0 to disable it.
+func NewBudgetManagerWithLimits(softLimit, hardLimit, committedLimit float64) *BudgetManager {
+	return newBudgetManager(softLimit, hardLimit, committedLimit, 10*time.Minute, time.Second)
+}
+
+func newBudgetManager(softLimit, hardLimit, committedLimit float64, windowSize, bucketWidth time.Duration) *BudgetManager {
+	numBuckets := int(windowSize / bucketWidth)
+	if numBuckets < 1 {
+		numBuckets = 1
+	}
+	return &BudgetManager{
+		windowSize:     windowSize,
+		maxBurstBudget: hardLimit,
+		softLimit:      softLimit,
+		committedLimit: committedLimit,
+		pendingBids:    make(map[string]float64),
+		bucketWidth:    bucketWidth,
+		buckets:        make([]float64, numBuckets),
+		records:        make([]BidRecord, 0, 1000), // Pre-allocate capacity
+	}
+}
+
+// AddSpend records a successful bid and prunes expired records
+// This is called after a bid is successfully placed on an ad platform
+func (bm *BudgetManager) AddSpend(amount float64) {
+	bm.AddSpendForBid("", amount)
+}
+
+// AddSpendForBid behaves like AddSpend but, when bidID is non-empty, also
+// tracks amount as in-flight (pending) spend under bidID until a caller
+// calls RecordCommit(bidID) to mark it finalized by the platform.
+func (bm *BudgetManager) AddSpendForBid(bidID string, amount float64) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	now := time.Now()
+	bm.advance(now)
+	prevTotal := bm.currentTotal
+	bm.buckets[bm.slotFor(bm.bucketID(now))] += amount
+	bm.currentTotal += amount
+
+	bm.records = append(bm.records, BidRecord{Timestamp: now, Amount: amount})
+	bm.pruneRecords(now)
+
+	if bidID != "" {
+		bm.pendingBids[bidID] = amount
+	}
+
+	bm.publishThresholdCrossings(prevTotal, bm.currentTotal)
+}
+
+// RecordCommit marks a previously recorded bid as finalized once the
+// platform has acknowledged it, moving its amount out of in-flight spend
+// and into committedSpend. A bidID not tracked by AddSpendForBid (e.g. one
+// recorded via plain AddSpend) is a no-op.
+func (bm *BudgetManager) RecordCommit(bidID string) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	amount, ok := bm.pendingBids[bidID]
+	if !ok {
+		return
+	}
+	delete(bm.pendingBids, bidID)
+	bm.committedSpend += amount
+}
+
+// Classify reports whether a prospective bid of amount fits within the
+// manager's tiered bounds: BudgetDeny over the hard limit,
+// BudgetAllowWithDegradation over the soft limit but under the hard limit,
+// BudgetAllow otherwise.
+func (bm *BudgetManager) Classify(amount float64) BudgetDecision {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	bm.advance(time.Now())
+	projected := bm.currentTotal + amount
+
+	if projected > bm.maxBurstBudget {
+		return BudgetDeny
+	}
+	if projected > bm.softLimit {
+		return BudgetAllowWithDegradation
+	}
+	return BudgetAllow
+}
+
+// publishThresholdCrossings emits EventBudgetThresholdCrossed for every
+// threshold in budgetThresholds that spend moved from below to at-or-above.
+// Must be called with lock held.
+func (bm *BudgetManager) publishThresholdCrossings(prevTotal, newTotal float64) {
+	if bm.eventBus == nil || bm.maxBurstBudget <= 0 {
+		return
+	}
+	prevRatio := prevTotal / bm.maxBurstBudget
+	newRatio := newTotal / bm.maxBurstBudget
+	for _, threshold := range budgetThresholds {
+		if prevRatio < threshold && newRatio >= threshold {
+			bm.eventBus.Publish(Event{
+				Type:   EventBudgetThresholdCrossed,
+				Source: bm.eventSource,
+				Data: map[string]interface{}{
+					"threshold":     threshold,
+					"current_spend": newTotal,
+					"max_budget":    bm.maxBurstBudget,
+				},
+			})
+		}
+	}
+}
+
+// CanSpend checks if adding the next bid would exceed the burst limit
+// Returns true if the bid can be placed without exceeding budget constraints
+func (bm *BudgetManager) CanSpend(nextAmount float64) bool {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	bm.advance(time.Now())
+	return (bm.currentTotal + nextAmount) <= bm.maxBurstBudget
+}
+
+// AdjustSpend applies delta directly to the current window's total spend,
+// bypassing the usual bucketed AddSpend path - for a reconciliation pass
+// (see connectors.Rewind) correcting CurrentSpend after a crash left it
+// diverged from what a platform's reporting API actually charged, not for
+// recording an ordinary bid. A negative delta can't drive currentTotal
+// below zero, since a window can't have spent less than nothing.
+func (bm *BudgetManager) AdjustSpend(delta float64) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	bm.advance(time.Now())
+	bm.currentTotal += delta
+	if bm.currentTotal < 0 {
+		bm.currentTotal = 0
+	}
+}
+
+// GetCurrentSpend returns the total spend within the current window
+func (bm *BudgetManager) GetCurrentSpend() float64 {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	bm.advance(time.Now())
+	return bm.currentTotal
+}
+
+// GetRemainingBudget returns how much budget is left in the current window
+func (bm *BudgetManager) GetRemainingBudget() float64 {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	bm.advance(time.Now())
+	return bm.maxBurstBudget - bm.currentTotal
+}
+
+// bucketID returns the index of the bucket t falls into, monotonically
+// increasing with time.
+func (bm *BudgetManager) bucketID(t time.Time) int64 {
+	return t.UnixNano() / int64(bm.bucketWidth)
+}
+
+// slotFor maps a bucketID onto its ring position.
+func (bm *BudgetManager) slotFor(id int64) int {
+	n := int64(len(bm.buckets))
+	return int(((id % n) + n) % n)
+}
+
+// advance subtracts any buckets that have fallen out of the window since
+// the last call from currentTotal and zeros them - O(buckets advanced),
+// amortized O(1) since real-time calls advance by at most a few buckets.
+// Must be called with lock held.
+func (bm *BudgetManager) advance(now time.Time) {
+	id := bm.bucketID(now)
+	if !bm.initialized {
+		bm.lastBucket = id
+		bm.initialized = true
+		return
+	}
+	if id == bm.lastBucket {
+		return
+	}
+
+	n := int64(len(bm.buckets))
+	span := id - bm.lastBucket
+	if span < 0 || span >= n {
+		// The whole window (or more) has elapsed since the last write.
+		for i := range bm.buckets {
+			bm.buckets[i] = 0
+		}
+		bm.currentTotal = 0
+	} else {
+		for s := bm.lastBucket + 1; s <= id; s++ {
+			slot := bm.slotFor(s)
+			bm.currentTotal -= bm.buckets[slot]
+			bm.buckets[slot] = 0
+		}
+	}
+	bm.lastBucket = id
+}
+
+// pruneRecords removes expired entries from the audit-fidelity records
+// list. Must be called with lock held.
+func (bm *BudgetManager) pruneRecords(now time.Time) {
+	cutoff := now.Add(-bm.windowSize)
+	i := 0
+	for i < len(bm.records) && bm.records[i].Timestamp.Before(cutoff) {
+		i++
+	}
+	bm.records = bm.records[i:]
+}
+
+// GetWindowStats returns statistics about the current budget window
+type WindowStats struct {
+	CurrentSpend    float64
+	MaxBudget       float64
+	RemainingBudget float64
+	RecordCount     int
+	OldestRecord    time.Time
+	WindowDuration  time.Duration
+
+	// SoftLimit is the degradation threshold Classify applies before the
+	// hard veto at MaxBudget.
+	SoftLimit float64
+	// CommittedLimit is the optional finalized-spend floor; zero if unset.
+	CommittedLimit float64
+	// CommittedSpend is the portion of CurrentSpend acknowledged by the
+	// platform via RecordCommit.
+	CommittedSpend float64
+	// InFlightSpend is the portion of CurrentSpend still pending
+	// RecordCommit.
+	InFlightSpend float64
+}
+
+// GetStats returns current window statistics for monitoring
+func (bm *BudgetManager) GetStats() WindowStats {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	now := time.Now()
+	bm.advance(now)
+	bm.pruneRecords(now)
+
+	var oldest time.Time
+	if len(bm.records) > 0 {
+		oldest = bm.records[0].Timestamp
+	}
+
+	var inFlight float64
+	for _, amount := range bm.pendingBids {
+		inFlight += amount
+	}
+
+	return WindowStats{
+		CurrentSpend:    bm.currentTotal,
+		MaxBudget:       bm.maxBurstBudget,
+		RemainingBudget: bm.maxBurstBudget - bm.currentTotal,
+		RecordCount:     len(bm.records),
+		OldestRecord:    oldest,
+		WindowDuration:  bm.windowSize,
+		SoftLimit:       bm.softLimit,
+		CommittedLimit:  bm.committedLimit,
+		CommittedSpend:  bm.committedSpend,
+		InFlightSpend:   inFlight,
+	}
+}
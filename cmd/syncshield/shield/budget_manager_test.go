@@ -0,0 +1,163 @@
+package shield
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBudgetManagerAddSpendAccumulatesWithinWindow(t *testing.T) {
+	bm := NewBudgetManagerWithConfig(100.0, time.Minute, time.Second)
+
+	bm.AddSpend(30.0)
+	bm.AddSpend(20.0)
+
+	if got := bm.GetCurrentSpend(); got != 50.0 {
+		t.Errorf("expected current spend 50.0, got %.2f", got)
+	}
+	if got := bm.GetRemainingBudget(); got != 50.0 {
+		t.Errorf("expected remaining budget 50.0, got %.2f", got)
+	}
+}
+
+func TestBudgetManagerAdjustSpendCorrectsCurrentTotal(t *testing.T) {
+	bm := NewBudgetManagerWithConfig(100.0, time.Minute, time.Second)
+	bm.AddSpend(50.0)
+
+	bm.AdjustSpend(-20.0)
+	if got := bm.GetCurrentSpend(); got != 30.0 {
+		t.Errorf("expected current spend 30.0 after rewinding $20, got %.2f", got)
+	}
+
+	bm.AdjustSpend(-100.0)
+	if got := bm.GetCurrentSpend(); got != 0 {
+		t.Errorf("expected current spend floored at 0, got %.2f", got)
+	}
+}
+
+func TestBudgetManagerCanSpendRespectsMaxBudget(t *testing.T) {
+	bm := NewBudgetManagerWithConfig(100.0, time.Minute, time.Second)
+	bm.AddSpend(90.0)
+
+	if bm.CanSpend(5.0) != true {
+		t.Error("expected a $5 bid to fit within $10 remaining budget")
+	}
+	if bm.CanSpend(15.0) != false {
+		t.Error("expected a $15 bid to exceed $10 remaining budget")
+	}
+}
+
+func TestBudgetManagerPrunesSpendOutsideWindow(t *testing.T) {
+	bm := NewBudgetManagerWithConfig(100.0, 50*time.Millisecond, time.Millisecond)
+	bm.AddSpend(40.0)
+
+	if got := bm.GetCurrentSpend(); got != 40.0 {
+		t.Fatalf("expected 40.0 immediately after AddSpend, got %.2f", got)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+
+	if got := bm.GetCurrentSpend(); got != 0 {
+		t.Errorf("expected spend to fall out of the window and reset to 0, got %.2f", got)
+	}
+	stats := bm.GetStats()
+	if stats.RecordCount != 0 {
+		t.Errorf("expected audit records to be pruned too, got %d", stats.RecordCount)
+	}
+}
+
+func TestBudgetManagerAdvanceOnlyDropsElapsedBuckets(t *testing.T) {
+	bm := NewBudgetManagerWithConfig(100.0, 100*time.Millisecond, 10*time.Millisecond)
+	bm.AddSpend(10.0)
+
+	time.Sleep(20 * time.Millisecond)
+	bm.AddSpend(10.0)
+
+	if got := bm.GetCurrentSpend(); got != 20.0 {
+		t.Errorf("expected both spends to still be within the 100ms window, got %.2f", got)
+	}
+}
+
+func TestBudgetManagerGetStatsReportsOldestRecordAndCount(t *testing.T) {
+	bm := NewBudgetManagerWithConfig(100.0, time.Minute, time.Second)
+	bm.AddSpend(10.0)
+	bm.AddSpend(10.0)
+
+	stats := bm.GetStats()
+	if stats.RecordCount != 2 {
+		t.Errorf("expected 2 records, got %d", stats.RecordCount)
+	}
+	if stats.OldestRecord.IsZero() {
+		t.Error("expected OldestRecord to be set")
+	}
+	if stats.MaxBudget != 100.0 || stats.WindowDuration != time.Minute {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestBudgetManagerClassifyTiersBySoftAndHardLimit(t *testing.T) {
+	bm := NewBudgetManagerWithLimits(80.0, 100.0, 0)
+	bm.AddSpend(70.0)
+
+	if got := bm.Classify(5.0); got != BudgetAllow {
+		t.Errorf("expected BudgetAllow under the soft limit, got %s", got)
+	}
+	if got := bm.Classify(15.0); got != BudgetAllowWithDegradation {
+		t.Errorf("expected BudgetAllowWithDegradation over soft but under hard, got %s", got)
+	}
+	if got := bm.Classify(35.0); got != BudgetDeny {
+		t.Errorf("expected BudgetDeny over the hard limit, got %s", got)
+	}
+}
+
+func TestBudgetManagerRecordCommitMovesInFlightToCommitted(t *testing.T) {
+	bm := NewBudgetManagerWithLimits(80.0, 100.0, 0)
+	bm.AddSpendForBid("bid-1", 10.0)
+
+	stats := bm.GetStats()
+	if stats.InFlightSpend != 10.0 || stats.CommittedSpend != 0 {
+		t.Fatalf("expected spend to start in-flight, got %+v", stats)
+	}
+
+	bm.RecordCommit("bid-1")
+
+	stats = bm.GetStats()
+	if stats.InFlightSpend != 0 || stats.CommittedSpend != 10.0 {
+		t.Errorf("expected RecordCommit to move spend to committed, got %+v", stats)
+	}
+	if stats.CurrentSpend != 10.0 {
+		t.Errorf("expected RecordCommit to leave total spend unchanged, got %.2f", stats.CurrentSpend)
+	}
+}
+
+func TestBudgetManagerRecordCommitUnknownBidIsNoOp(t *testing.T) {
+	bm := NewBudgetManagerWithLimits(80.0, 100.0, 0)
+	bm.AddSpend(10.0) // untracked - no bidID
+
+	bm.RecordCommit("never-existed")
+
+	stats := bm.GetStats()
+	if stats.CommittedSpend != 0 {
+		t.Errorf("expected no committed spend from an unknown bidID, got %.2f", stats.CommittedSpend)
+	}
+}
+
+func TestBudgetManagerThreadSafetyNeverExceedsBudget(t *testing.T) {
+	bm := NewBudgetManager(1000.0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if bm.CanSpend(15.0) {
+				bm.AddSpend(15.0)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stats := bm.GetStats(); stats.CurrentSpend > stats.MaxBudget {
+		t.Errorf("budget exceeded: spent %.2f > max %.2f", stats.CurrentSpend, stats.MaxBudget)
+	}
+}
@@ -2,10 +2,18 @@ package shield
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 )
 
+// ErrCircuitOpen is returned by Execute when the breaker is OPEN and declines
+// to attempt inner at all. Unlike CallWithCircuitBreaker, Execute has no
+// fallbackCall of its own to hand off to - pair CircuitBreaker with a
+// FallbackPolicy in a Compose chain to serve a degraded response instead of
+// propagating this error.
+var ErrCircuitOpen = errors.New("shield: circuit breaker open")
+
 // CircuitBreakerState defines the state of the circuit
 // CLOSED: healthy; OPEN: failing (use fallback); HALF_OPEN: probing recovery
 // Half-open allows limited calls to test if service recovered.
@@ -17,6 +25,33 @@ const (
 	HALF_OPEN
 )
 
+// LatencyMode selects how CircuitBreaker decides a latency counts as a
+// slow failure - see SetLatencyDetector.
+type LatencyMode int
+
+const (
+	// LatencyFixed treats any latency over the configured
+	// latencyThreshold (SetThresholds) as a slow failure - the original,
+	// default behavior.
+	LatencyFixed LatencyMode = iota
+
+	// LatencyEWMA treats any latency over latencyK times the
+	// exponentially weighted moving average of recent successful-call
+	// latencies as a slow failure, riding out gradual traffic-wide
+	// jitter instead of tripping on one fixed number.
+	LatencyEWMA
+
+	// LatencyQuantile is LatencyEWMA plus a P99-based floor: a latency
+	// counts as a slow failure once it exceeds
+	// max(ewma*latencyK, p99*1.5), so a regression that shifts the
+	// whole distribution - not just the mean - still trips the breaker.
+	LatencyQuantile
+)
+
+// defaultLatencyK is the default multiplier LatencyEWMA/LatencyQuantile
+// apply to the EWMA when deciding a latency is a slow failure.
+const defaultLatencyK = 3.0
+
 // CircuitBreaker implements resilience for SyncValue™ (gRPC) calls
 // It protects connectors from latency spikes or outages by switching to fallback.
 type CircuitBreaker struct {
@@ -30,17 +65,45 @@ type CircuitBreaker struct {
 	// Configuration
 	failureThreshold int           // failures before opening circuit
 	successThreshold int           // successes in HALF_OPEN before closing
-	latencyThreshold time.Duration // latency considered a failure
+	latencyThreshold time.Duration // latency considered a failure under LatencyFixed
 	resetTimeout     time.Duration // time before allowing HALF_OPEN probe
 
+	// Adaptive latency detection (see LatencyMode)
+	latencyMode   LatencyMode
+	latencyK      float64
+	latencyDigest *latencyDigest
+
 	// Metrics
 	totalRequests       int64
 	successfulRequests  int64
 	failedRequests      int64
 	fallbackActivations int64
+	cancelledRequests   int64
 
 	// Observability
 	metricsCollector *MetricsCollector // Optional: nil if metrics disabled
+	eventBus         *EventBus         // Optional: nil if event publishing disabled
+	eventSource      string
+}
+
+// SetEventBus attaches bus so state transitions publish EventCircuitOpened/
+// EventCircuitClosed/EventCircuitHalfOpen. source identifies this breaker
+// in published events. A nil bus disables publishing.
+func (cb *CircuitBreaker) SetEventBus(bus *EventBus, source string) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.eventBus = bus
+	cb.eventSource = source
+}
+
+// publishTransition emits eventType if an EventBus is attached. Must be
+// called with lock held (or after releasing it, since Publish only reads
+// cb.eventBus/eventSource - callers here hold the lock already).
+func (cb *CircuitBreaker) publishTransition(eventType EventType) {
+	if cb.eventBus == nil {
+		return
+	}
+	cb.eventBus.Publish(Event{Type: eventType, Source: cb.eventSource})
 }
 
 // CircuitBreakerStats exposes circuit state and counters
@@ -51,8 +114,19 @@ type CircuitBreakerStats struct {
 	SuccessfulRequests   int64
 	FailedRequests       int64
 	FallbackActivations  int64
+	CancelledRequests    int64
 	LastFailureTime      time.Time
 	TimeSinceLastFailure time.Duration
+
+	// Adaptive latency detection snapshot (see LatencyMode). EWMALatency,
+	// P50Latency and P99Latency are 0 until a successful call has been
+	// recorded. LatencyThreshold is the threshold actually in effect for
+	// cb's current LatencyMode, so operators can see what would (or
+	// wouldn't) have tripped a given call.
+	EWMALatency      time.Duration
+	P50Latency       time.Duration
+	P99Latency       time.Duration
+	LatencyThreshold time.Duration
 }
 
 // NewCircuitBreaker returns a breaker with sensible defaults
@@ -65,7 +139,55 @@ func NewCircuitBreaker() *CircuitBreaker {
 		resetTimeout:        30 * time.Second,
 		lastStateChangeTime: time.Now(),
 		metricsCollector:    nil, // Metrics disabled by default; enable via EnableMetrics()
+		latencyMode:         LatencyFixed,
+		latencyK:            defaultLatencyK,
+		latencyDigest:       newLatencyDigest(),
+	}
+}
+
+// SetLatencyDetector switches how CircuitBreaker judges a latency as a
+// slow failure (see LatencyMode). The EWMA/quantile digest is always
+// maintained regardless of mode - GetStats reports it - so switching
+// modes takes effect immediately using whatever history has already
+// accumulated.
+func (cb *CircuitBreaker) SetLatencyDetector(mode LatencyMode) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.latencyMode = mode
+}
+
+// secondsToDuration converts a latencyDigest value (float64 seconds)
+// back to a time.Duration.
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// currentLatencyThreshold returns the latency above which a call counts
+// as a slow failure under cb's current LatencyMode, falling back to the
+// fixed latencyThreshold until the digest has seen at least one sample.
+func (cb *CircuitBreaker) currentLatencyThreshold() time.Duration {
+	if cb.latencyMode == LatencyFixed || cb.latencyDigest.count == 0 {
+		return cb.latencyThreshold
+	}
+
+	ewmaThreshold := secondsToDuration(cb.latencyDigest.EWMA() * cb.latencyK)
+	if cb.latencyMode == LatencyEWMA {
+		return ewmaThreshold
+	}
+
+	p99Threshold := secondsToDuration(cb.latencyDigest.Quantile(0.99) * 1.5)
+	if p99Threshold > ewmaThreshold {
+		return p99Threshold
 	}
+	return ewmaThreshold
+}
+
+// isSlowFailure reports whether latency counts as a failure under cb's
+// current LatencyMode. Mirrors the existing relaxed-read convention at
+// its call sites (Execute, CallWithCircuitBreaker, RecordFailure already
+// read cb.latencyThreshold the same way), so it takes no lock itself.
+func (cb *CircuitBreaker) isSlowFailure(latency time.Duration) bool {
+	return latency > cb.currentLatencyThreshold()
 }
 
 // EnableMetrics activates observability metrics collection
@@ -94,6 +216,7 @@ func (cb *CircuitBreaker) RecordSuccess(latency time.Duration) {
 	cb.totalRequests++
 	cb.successfulRequests++
 	cb.failureCount = 0
+	cb.latencyDigest.Insert(latency.Seconds())
 
 	// Emit metrics if enabled
 	if cb.metricsCollector != nil {
@@ -117,6 +240,7 @@ func (cb *CircuitBreaker) RecordSuccess(latency time.Duration) {
 			if cb.metricsCollector != nil {
 				cb.metricsCollector.RecordStateTransition(oldState, CLOSED)
 			}
+			cb.publishTransition(EventCircuitClosed)
 		}
 	}
 }
@@ -133,7 +257,7 @@ func (cb *CircuitBreaker) RecordFailure(latency time.Duration) {
 
 	// Emit metrics if enabled
 	errorType := "generic"
-	if latency > cb.latencyThreshold {
+	if cb.isSlowFailure(latency) {
 		errorType = "latency_spike"
 		cb.failureCount++ // Latency spike counts as an extra failure
 	}
@@ -154,6 +278,7 @@ func (cb *CircuitBreaker) RecordFailure(latency time.Duration) {
 		if cb.metricsCollector != nil {
 			cb.metricsCollector.RecordStateTransition(oldState, OPEN)
 		}
+		cb.publishTransition(EventCircuitOpened)
 		return
 	}
 
@@ -165,6 +290,7 @@ func (cb *CircuitBreaker) RecordFailure(latency time.Duration) {
 		if cb.metricsCollector != nil {
 			cb.metricsCollector.RecordStateTransition(oldState, OPEN)
 		}
+		cb.publishTransition(EventCircuitOpened)
 	}
 }
 
@@ -187,6 +313,7 @@ func (cb *CircuitBreaker) CanExecute() bool {
 			if cb.metricsCollector != nil {
 				cb.metricsCollector.RecordStateTransition(oldState, HALF_OPEN)
 			}
+			cb.publishTransition(EventCircuitHalfOpen)
 			return true
 		}
 		return false
@@ -228,8 +355,28 @@ func (cb *CircuitBreaker) GetStats() CircuitBreakerStats {
 		SuccessfulRequests:   cb.successfulRequests,
 		FailedRequests:       cb.failedRequests,
 		FallbackActivations:  cb.fallbackActivations,
+		CancelledRequests:    cb.cancelledRequests,
 		LastFailureTime:      cb.lastFailureTime,
 		TimeSinceLastFailure: since,
+		EWMALatency:          secondsToDuration(cb.latencyDigest.EWMA()),
+		P50Latency:           secondsToDuration(cb.latencyDigest.Quantile(0.5)),
+		P99Latency:           secondsToDuration(cb.latencyDigest.Quantile(0.99)),
+		LatencyThreshold:     cb.currentLatencyThreshold(),
+	}
+}
+
+// RecordCancellation registers a call that was abandoned because the
+// caller's own context was cancelled or hit its deadline - distinct from
+// RecordFailure so an upstream caller's tight SLA timing out can never by
+// itself trip the breaker open.
+func (cb *CircuitBreaker) RecordCancellation() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.cancelledRequests++
+
+	if cb.metricsCollector != nil {
+		cb.metricsCollector.RecordCancellation()
 	}
 }
 
@@ -283,10 +430,41 @@ func (s CircuitBreakerState) String() string {
 	}
 }
 
+// Execute implements Policy. It is CallWithCircuitBreaker stripped of the
+// built-in fallbackCall: if the breaker is OPEN, it records a fallback
+// activation and returns ErrCircuitOpen without calling inner at all; a
+// failure from inner (error or latency over latencyThreshold) is recorded
+// and returned as-is. inner's own (latency, error) aren't observable from
+// this signature's return value, so Execute measures latency itself around
+// the call rather than requiring inner to report it.
+func (cb *CircuitBreaker) Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if !cb.CanExecute() {
+		cb.RecordFallback()
+		return nil, ErrCircuitOpen
+	}
+
+	start := time.Now()
+	result, err := inner(ctx)
+	latency := time.Since(start)
+
+	if err != nil || cb.isSlowFailure(latency) {
+		cb.RecordFailure(latency)
+		return result, err
+	}
+
+	cb.RecordSuccess(latency)
+	return result, nil
+}
+
 // CallWithCircuitBreaker wraps a gRPC call with breaker + fallback
 // grpcCall returns (result, latency, error)
 // fallbackCall returns (result, error)
 // Returns: result, source (grpc_success|grpc_failed|fallback|fallback_after_circuit_open), error
+//
+// A non-nil error is always a *Error (see error.go), so callers can tell a
+// budget veto from an upstream outage from a rejected half-open probe via
+// IsErrorRetryable/IsBudgetError or errors.As, instead of pattern-matching
+// the underlying error's string.
 func (cb *CircuitBreaker) CallWithCircuitBreaker(
 	ctx context.Context,
 	grpcCall func(context.Context) (interface{}, time.Duration, error),
@@ -297,26 +475,47 @@ func (cb *CircuitBreaker) CallWithCircuitBreaker(
 	if !cb.CanExecute() {
 		cb.RecordFallback()
 		res, err := fallbackCall(ctx)
-		return res, "fallback", err
+		return res, "fallback", wrapCallError(ErrorKindCircuitOpen, cb.GetState(), err)
 	}
 
 	// Attempt gRPC call
 	result, latency, err := grpcCall(ctx)
 
-	if err != nil || latency > cb.latencyThreshold {
+	if err != nil || cb.isSlowFailure(latency) {
 		cb.RecordFailure(latency)
 
 		// If circuit opened due to this failure, try fallback
 		if cb.IsFallbackMode() {
 			cb.RecordFallback()
 			fbResult, fbErr := fallbackCall(ctx)
-			return fbResult, "fallback_after_circuit_open", fbErr
+			return fbResult, "fallback_after_circuit_open", wrapCallError(ErrorKindUpstreamUnavailable, cb.GetState(), fbErr)
 		}
 
-		return result, "grpc_failed", err
+		return result, "grpc_failed", wrapCallError(upstreamErrorKind(err), cb.GetState(), err)
 	}
 
 	// Success path
 	cb.RecordSuccess(latency)
 	return result, "grpc_success", nil
 }
+
+// upstreamErrorKind classifies a failed grpcCall's error for
+// CallWithCircuitBreaker: a context deadline is a Timeout, everything else
+// (including a plain latency-threshold trip, where err is nil) is an
+// UpstreamUnavailable.
+func upstreamErrorKind(err error) ErrorKind {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorKindTimeout
+	}
+	return ErrorKindUpstreamUnavailable
+}
+
+// wrapCallError wraps a non-nil err as a *Error of kind at state, leaving a
+// nil err as nil so callers' existing "err == nil" success checks keep
+// working.
+func wrapCallError(kind ErrorKind, state CircuitBreakerState, err error) error {
+	if err == nil {
+		return nil
+	}
+	return NewError(kind, "", state, err)
+}
@@ -347,3 +347,111 @@ func TestCircuitBreakerMultipleOpenClose(t *testing.T) {
 
 	t.Logf("Successfully completed 3 full CLOSED -> OPEN -> HALF_OPEN -> CLOSED cycles")
 }
+
+// TestCircuitBreakerLatencyFixedModeIsDefault confirms SetLatencyDetector
+// isn't required to get the original 500ms-threshold behavior.
+func TestCircuitBreakerLatencyFixedModeIsDefault(t *testing.T) {
+	cb := NewCircuitBreaker()
+	if cb.GetStats().LatencyThreshold != 500*time.Millisecond {
+		t.Errorf("expected default LatencyThreshold of 500ms, got %v", cb.GetStats().LatencyThreshold)
+	}
+
+	cb.RecordFailure(600 * time.Millisecond)
+	cb.RecordFailure(600 * time.Millisecond)
+	if cb.GetState() != OPEN {
+		t.Errorf("expected LatencyFixed mode to still double-count a latency spike as a failure, got %v", cb.GetState())
+	}
+}
+
+// TestCircuitBreakerLatencyEWMARidesOutGradualJitter feeds a stream of
+// ~100ms successes - well over the fixed 500ms threshold's safety margin
+// isn't the point here, LatencyEWMA riding near its own baseline is - and
+// confirms a call near that baseline isn't treated as a slow failure even
+// though it would be under a naive "any latency over the long-run average"
+// rule without the *latencyK multiplier.
+func TestCircuitBreakerLatencyEWMARidesOutGradualJitter(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.SetLatencyDetector(LatencyEWMA)
+
+	for i := 0; i < 50; i++ {
+		cb.RecordSuccess(100 * time.Millisecond)
+	}
+
+	// 250ms is 2.5x the ~100ms EWMA baseline - under the default k=3.0
+	// multiplier, so it should read as ordinary jitter, not a failure.
+	cb.RecordFailure(250 * time.Millisecond)
+	if cb.GetState() != CLOSED {
+		t.Errorf("expected a call within 3x EWMA to not trip the breaker, got %v", cb.GetState())
+	}
+}
+
+// TestCircuitBreakerLatencyEWMATripsOnRealRegression confirms a latency
+// far outside the learned baseline still counts as a slow failure under
+// LatencyEWMA, double-counting the same way LatencyFixed's spikes do.
+func TestCircuitBreakerLatencyEWMATripsOnRealRegression(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.SetLatencyDetector(LatencyEWMA)
+	cb.SetThresholds(10, 2, 500*time.Millisecond, 30*time.Second)
+
+	for i := 0; i < 50; i++ {
+		cb.RecordSuccess(100 * time.Millisecond)
+	}
+
+	// 1s is 10x the ~100ms EWMA baseline - a real regression, not jitter.
+	cb.RecordFailure(1 * time.Second)
+	if cb.GetStats().FailureCount < 2 {
+		t.Errorf("expected a 10x-baseline latency to double-count as a failure, got FailureCount=%d", cb.GetStats().FailureCount)
+	}
+}
+
+// TestCircuitBreakerLatencyQuantileUsesP99Floor confirms LatencyQuantile
+// trips on a latency that clears the P99*1.5 floor even when it wouldn't
+// clear ewma*k alone, since a skewed-but-not-yet-mean-shifting
+// distribution is exactly what the P99 term is meant to catch.
+func TestCircuitBreakerLatencyQuantileUsesP99Floor(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.SetLatencyDetector(LatencyQuantile)
+	cb.SetThresholds(2, 2, 500*time.Millisecond, 30*time.Second)
+
+	// A mostly-fast distribution with a thin tail of slow calls, so P99
+	// sits well above the EWMA-derived threshold.
+	for i := 0; i < 90; i++ {
+		cb.RecordSuccess(50 * time.Millisecond)
+	}
+	for i := 0; i < 10; i++ {
+		cb.RecordSuccess(400 * time.Millisecond)
+	}
+
+	stats := cb.GetStats()
+	if stats.P99Latency < stats.EWMALatency {
+		t.Fatalf("expected P99 (%v) to sit above EWMA (%v) for a skewed distribution", stats.P99Latency, stats.EWMALatency)
+	}
+
+	cb.RecordFailure(stats.P99Latency * 2)
+	if cb.GetState() != OPEN {
+		t.Errorf("expected a latency well above the P99*1.5 floor to trip the breaker, got %v", cb.GetState())
+	}
+}
+
+// TestCircuitBreakerGetStatsReportsLatencyDigest confirms GetStats
+// surfaces the EWMA/P50/P99/threshold snapshot operators would use to
+// tune latencyK or decide which LatencyMode fits their traffic.
+func TestCircuitBreakerGetStatsReportsLatencyDigest(t *testing.T) {
+	cb := NewCircuitBreaker()
+
+	latencies := []time.Duration{50, 60, 70, 80, 90, 100, 110, 120, 130, 900}
+	for _, l := range latencies {
+		cb.RecordSuccess(l * time.Millisecond)
+	}
+
+	stats := cb.GetStats()
+	if stats.EWMALatency <= 0 {
+		t.Error("expected a non-zero EWMALatency after recording successes")
+	}
+	if stats.P50Latency <= 0 || stats.P99Latency <= 0 {
+		t.Errorf("expected non-zero P50/P99, got P50=%v P99=%v", stats.P50Latency, stats.P99Latency)
+	}
+	if stats.P99Latency < stats.P50Latency {
+		t.Errorf("expected P99 (%v) >= P50 (%v)", stats.P99Latency, stats.P50Latency)
+	}
+}
@@ -0,0 +1,410 @@
+package shield
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// failureScript atomically records a failed call against a distributed
+// breaker's Redis hash: it increments failureCount (twice for a latency
+// spike, mirroring CircuitBreaker.RecordFailure), applies the same
+// CLOSED/HALF_OPEN/OPEN transition rules as the in-process breaker, and -
+// only when the state actually changes - bumps the CAS version and
+// publishes the new state on KEYS[2] so every subscribed peer observes
+// the transition within milliseconds rather than waiting for their next
+// refresh tick.
+var failureScript = redis.NewScript(`
+local stateKey = KEYS[1]
+local channel = KEYS[2]
+local now = ARGV[1]
+local isLatencySpike = ARGV[2]
+local failureThreshold = tonumber(ARGV[3])
+
+local state = redis.call('HGET', stateKey, 'state')
+if not state or state == false then state = 'CLOSED' end
+
+local failureCount = tonumber(redis.call('HGET', stateKey, 'failureCount') or '0')
+failureCount = failureCount + 1
+if isLatencySpike == '1' then
+	failureCount = failureCount + 1
+end
+
+redis.call('HSET', stateKey, 'failureCount', failureCount, 'lastFailureTime', now)
+redis.call('HINCRBY', stateKey, 'totalRequests', 1)
+redis.call('HINCRBY', stateKey, 'failedRequests', 1)
+
+local newState = state
+if state == 'HALF_OPEN' then
+	newState = 'OPEN'
+elseif state ~= 'OPEN' and failureCount >= failureThreshold then
+	newState = 'OPEN'
+end
+
+if newState ~= state then
+	redis.call('HSET', stateKey, 'state', newState, 'lastStateChangeTime', now, 'halfOpenSuccesses', 0)
+	redis.call('HINCRBY', stateKey, 'version', 1)
+	redis.call('PUBLISH', channel, newState)
+end
+
+return newState
+`)
+
+// successScript is successScript's counterpart for a successful call: it
+// clears failureCount, and in HALF_OPEN counts toward successThreshold
+// before closing the circuit - again publishing on transition only.
+var successScript = redis.NewScript(`
+local stateKey = KEYS[1]
+local channel = KEYS[2]
+local now = ARGV[1]
+local successThreshold = tonumber(ARGV[2])
+
+local state = redis.call('HGET', stateKey, 'state')
+if not state or state == false then state = 'CLOSED' end
+
+redis.call('HSET', stateKey, 'failureCount', 0)
+redis.call('HINCRBY', stateKey, 'totalRequests', 1)
+redis.call('HINCRBY', stateKey, 'successfulRequests', 1)
+
+local newState = state
+if state == 'HALF_OPEN' then
+	local halfOpenSuccesses = tonumber(redis.call('HINCRBY', stateKey, 'halfOpenSuccesses', 1))
+	if halfOpenSuccesses >= successThreshold then
+		newState = 'CLOSED'
+	end
+end
+
+if newState ~= state then
+	redis.call('HSET', stateKey, 'state', newState, 'lastStateChangeTime', now, 'halfOpenSuccesses', 0)
+	redis.call('HINCRBY', stateKey, 'version', 1)
+	redis.call('PUBLISH', channel, newState)
+end
+
+return newState
+`)
+
+// probeScript is the "half-open probe token" CAS: it only moves an OPEN
+// breaker to HALF_OPEN, and only for the single replica that already won
+// the SETNX on the probe key (see tryBecomeProbe) - everyone else's
+// CanExecute still sees OPEN until this replica's probe call reports its
+// own outcome.
+var probeScript = redis.NewScript(`
+local stateKey = KEYS[1]
+local channel = KEYS[2]
+local now = ARGV[1]
+
+local state = redis.call('HGET', stateKey, 'state')
+if state == 'OPEN' then
+	redis.call('HSET', stateKey, 'state', 'HALF_OPEN', 'lastStateChangeTime', now, 'halfOpenSuccesses', 0)
+	redis.call('HINCRBY', stateKey, 'version', 1)
+	redis.call('PUBLISH', channel, 'HALF_OPEN')
+	return 'HALF_OPEN'
+end
+return state
+`)
+
+const (
+	defaultDistributedRefreshInterval = 250 * time.Millisecond
+	defaultProbeTokenTTL              = 5 * time.Second
+)
+
+// stateToRedis/stateFromRedis trade CircuitBreakerState for the bare
+// "CLOSED"/"OPEN"/"HALF_OPEN" strings the Lua scripts above read and
+// write - CircuitBreakerState.String() isn't used here since it returns
+// a human-readable "OPEN (failing)" form meant for logs, not a value
+// scripts compare against.
+func stateToRedis(s CircuitBreakerState) string {
+	switch s {
+	case OPEN:
+		return "OPEN"
+	case HALF_OPEN:
+		return "HALF_OPEN"
+	default:
+		return "CLOSED"
+	}
+}
+
+func stateFromRedis(s string) CircuitBreakerState {
+	switch s {
+	case "OPEN":
+		return OPEN
+	case "HALF_OPEN":
+		return HALF_OPEN
+	default:
+		return CLOSED
+	}
+}
+
+// DistributedCircuitBreaker is the Redis-backed counterpart to
+// CircuitBreaker: every kiki-agent replica behind a key shares the same
+// failure counts, state, and reset timestamp instead of each replica
+// independently re-discovering (and re-opening against) a downstream
+// outage. CanExecute only consults a small local cache - refreshed on a
+// tick and pushed to immediately by Redis pub/sub - so the hot RTB
+// decision path never blocks on a Redis round-trip; RecordSuccess/
+// RecordFailure still go to Redis synchronously, since those happen once
+// per call rather than on every decision.
+type DistributedCircuitBreaker struct {
+	client        *redis.Client
+	key           string
+	probeKey      string
+	eventsChannel string
+	replicaID     string
+
+	failureThreshold int
+	successThreshold int
+	latencyThreshold time.Duration
+	resetTimeout     time.Duration
+	refreshInterval  time.Duration
+	probeTokenTTL    time.Duration
+
+	// localState/localStateChangeTime are the local cache CanExecute reads,
+	// kept current by run()'s refresh tick and pub/sub subscription rather
+	// than by a Redis call on every CanExecute.
+	localState           atomic.Int32
+	localStateChangeTime atomic.Int64 // UnixNano
+
+	stopCh chan struct{}
+}
+
+// DistributedCircuitBreakerOption configures a DistributedCircuitBreaker
+// at construction time.
+type DistributedCircuitBreakerOption func(*DistributedCircuitBreaker)
+
+// WithDistributedThresholds overrides the failure/success/latency/reset
+// thresholds NewDistributedCircuitBreaker otherwise defaults to
+// NewCircuitBreaker's values for.
+func WithDistributedThresholds(failureThreshold, successThreshold int, latencyThreshold, resetTimeout time.Duration) DistributedCircuitBreakerOption {
+	return func(d *DistributedCircuitBreaker) {
+		d.failureThreshold = failureThreshold
+		d.successThreshold = successThreshold
+		d.latencyThreshold = latencyThreshold
+		d.resetTimeout = resetTimeout
+	}
+}
+
+// WithRefreshInterval overrides how often CanExecute's local cache is
+// reconciled against Redis as a safety net alongside pub/sub (default
+// defaultDistributedRefreshInterval).
+func WithRefreshInterval(d time.Duration) DistributedCircuitBreakerOption {
+	return func(dcb *DistributedCircuitBreaker) {
+		dcb.refreshInterval = d
+	}
+}
+
+// WithReplicaID overrides the identifier this replica uses when competing
+// for the half-open probe token. Defaults to hostname:pid.
+func WithReplicaID(id string) DistributedCircuitBreakerOption {
+	return func(d *DistributedCircuitBreaker) {
+		d.replicaID = id
+	}
+}
+
+// NewDistributedCircuitBreaker creates a breaker sharing state through
+// client under key. Call Start to begin refreshing the local cache (from
+// a tick and from Redis pub/sub); without it, CanExecute reads the
+// zero-value cache (CLOSED), the same safe default NewCircuitBreaker
+// starts in.
+func NewDistributedCircuitBreaker(client *redis.Client, key string, opts ...DistributedCircuitBreakerOption) *DistributedCircuitBreaker {
+	hostname, _ := os.Hostname()
+	d := &DistributedCircuitBreaker{
+		client:           client,
+		key:              key,
+		probeKey:         key + ":probe",
+		eventsChannel:    key + ":events",
+		replicaID:        fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+		failureThreshold: 3,
+		successThreshold: 2,
+		latencyThreshold: 500 * time.Millisecond,
+		resetTimeout:     30 * time.Second,
+		refreshInterval:  defaultDistributedRefreshInterval,
+		probeTokenTTL:    defaultProbeTokenTTL,
+		stopCh:           make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Start launches the background loop that keeps the local cache current:
+// an initial refresh, a subscription to eventsChannel for near-instant
+// propagation, and refreshInterval ticks as a safety net against a missed
+// pub/sub message. It runs until ctx is cancelled or Stop is called.
+func (d *DistributedCircuitBreaker) Start(ctx context.Context) {
+	d.refreshFromRedis(ctx)
+	go d.run(ctx)
+}
+
+// Stop ends the background refresh loop started by Start.
+func (d *DistributedCircuitBreaker) Stop() {
+	close(d.stopCh)
+}
+
+func (d *DistributedCircuitBreaker) run(ctx context.Context) {
+	sub := d.client.Subscribe(ctx, d.eventsChannel)
+	defer sub.Close()
+	msgCh := sub.Channel()
+
+	ticker := time.NewTicker(d.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.stopCh:
+			return
+		case msg, ok := <-msgCh:
+			if !ok {
+				return
+			}
+			d.applyLocal(stateFromRedis(msg.Payload), time.Now())
+		case <-ticker.C:
+			d.refreshFromRedis(ctx)
+		}
+	}
+}
+
+// refreshFromRedis reconciles the local cache against Redis's current
+// state/lastStateChangeTime - the periodic safety net for whatever a
+// pub/sub message might have missed (a dropped connection, a replica that
+// started after the transition was published).
+func (d *DistributedCircuitBreaker) refreshFromRedis(ctx context.Context) {
+	fields, err := d.client.HMGet(ctx, d.key, "state", "lastStateChangeTime").Result()
+	if err != nil {
+		log.Printf("⚠️ DistributedCircuitBreaker: refresh failed for %s: %v", d.key, err)
+		return
+	}
+
+	state := CLOSED
+	if s, ok := fields[0].(string); ok {
+		state = stateFromRedis(s)
+	}
+
+	changeTime := time.Now()
+	if raw, ok := fields[1].(string); ok {
+		var nanos int64
+		if _, err := fmt.Sscanf(raw, "%d", &nanos); err == nil {
+			changeTime = time.Unix(0, nanos)
+		}
+	}
+
+	d.applyLocal(state, changeTime)
+}
+
+func (d *DistributedCircuitBreaker) applyLocal(state CircuitBreakerState, changeTime time.Time) {
+	d.localState.Store(int32(state))
+	d.localStateChangeTime.Store(changeTime.UnixNano())
+}
+
+// CanExecute reports whether a call should be attempted, consulting only
+// the local cache - never Redis - except on the rare path where the cache
+// says OPEN and resetTimeout has elapsed, where it competes for the
+// half-open probe token (see tryBecomeProbe).
+func (d *DistributedCircuitBreaker) CanExecute() bool {
+	state := CircuitBreakerState(d.localState.Load())
+	switch state {
+	case CLOSED, HALF_OPEN:
+		return true
+	case OPEN:
+		changeTime := time.Unix(0, d.localStateChangeTime.Load())
+		if time.Since(changeTime) <= d.resetTimeout {
+			return false
+		}
+		return d.tryBecomeProbe()
+	default:
+		return false
+	}
+}
+
+// tryBecomeProbe competes for the half-open probe token via SETNX with a
+// TTL, so only one replica sends the recovery probe at a time - every
+// other replica's CanExecute keeps returning false until this one's
+// RecordSuccess/RecordFailure reports the probe's outcome and the state
+// transition (or lack of one) propagates back over eventsChannel.
+func (d *DistributedCircuitBreaker) tryBecomeProbe() bool {
+	ctx := context.Background()
+	acquired, err := d.client.SetNX(ctx, d.probeKey, d.replicaID, d.probeTokenTTL).Result()
+	if err != nil {
+		log.Printf("⚠️ DistributedCircuitBreaker: probe token check failed for %s: %v", d.key, err)
+		return false
+	}
+	if !acquired {
+		return false
+	}
+
+	result, err := probeScript.Run(ctx, d.client, []string{d.key, d.eventsChannel}, time.Now().UnixNano()).Result()
+	if err != nil {
+		log.Printf("⚠️ DistributedCircuitBreaker: probe transition failed for %s: %v", d.key, err)
+		return false
+	}
+
+	newState := stateFromRedis(fmt.Sprint(result))
+	d.applyLocal(newState, time.Now())
+	return newState == HALF_OPEN
+}
+
+// RecordSuccess registers a successful call, atomically applying
+// successScript against Redis and updating the local cache with the
+// result so a caller checking CanExecute immediately after sees the
+// up-to-date state without waiting for the next tick or pub/sub message.
+func (d *DistributedCircuitBreaker) RecordSuccess(latency time.Duration) {
+	ctx := context.Background()
+	result, err := successScript.Run(ctx, d.client, []string{d.key, d.eventsChannel},
+		time.Now().UnixNano(), d.successThreshold).Result()
+	if err != nil {
+		log.Printf("⚠️ DistributedCircuitBreaker: record success failed for %s: %v", d.key, err)
+		return
+	}
+	d.applyLocal(stateFromRedis(fmt.Sprint(result)), time.Now())
+}
+
+// RecordFailure registers a failed call or latency spike the same way
+// RecordSuccess does, via failureScript.
+func (d *DistributedCircuitBreaker) RecordFailure(latency time.Duration) {
+	ctx := context.Background()
+	isLatencySpike := "0"
+	if latency > d.latencyThreshold {
+		isLatencySpike = "1"
+	}
+	result, err := failureScript.Run(ctx, d.client, []string{d.key, d.eventsChannel},
+		time.Now().UnixNano(), isLatencySpike, d.failureThreshold).Result()
+	if err != nil {
+		log.Printf("⚠️ DistributedCircuitBreaker: record failure failed for %s: %v", d.key, err)
+		return
+	}
+	d.applyLocal(stateFromRedis(fmt.Sprint(result)), time.Now())
+}
+
+// GetState returns the locally cached state - the same one CanExecute
+// acts on, not necessarily Redis's absolute latest if a pub/sub message
+// is still in flight.
+func (d *DistributedCircuitBreaker) GetState() CircuitBreakerState {
+	return CircuitBreakerState(d.localState.Load())
+}
+
+// Execute implements Policy against Redis-shared breaker state the same
+// way CircuitBreaker.Execute does against in-process state.
+func (d *DistributedCircuitBreaker) Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if !d.CanExecute() {
+		return nil, ErrCircuitOpen
+	}
+
+	start := time.Now()
+	result, err := inner(ctx)
+	latency := time.Since(start)
+
+	if err != nil || latency > d.latencyThreshold {
+		d.RecordFailure(latency)
+		return result, err
+	}
+
+	d.RecordSuccess(latency)
+	return result, nil
+}
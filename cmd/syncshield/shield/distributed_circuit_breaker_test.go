@@ -0,0 +1,134 @@
+package shield
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func newTestRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestDistributedCircuitBreaker_OpensAfterFailureThreshold(t *testing.T) {
+	client := newTestRedisClient(t)
+	dcb := NewDistributedCircuitBreaker(client, "test:cb", WithDistributedThresholds(3, 2, 500*time.Millisecond, 30*time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dcb.Start(ctx)
+	defer dcb.Stop()
+
+	for i := 0; i < 3; i++ {
+		dcb.RecordFailure(50 * time.Millisecond)
+	}
+
+	if dcb.GetState() != OPEN {
+		t.Fatalf("expected OPEN after 3 failures, got %v", dcb.GetState())
+	}
+	if dcb.CanExecute() {
+		t.Error("expected CanExecute to return false while OPEN and within resetTimeout")
+	}
+}
+
+func TestDistributedCircuitBreaker_SharesStateAcrossInstances(t *testing.T) {
+	client := newTestRedisClient(t)
+
+	a := NewDistributedCircuitBreaker(client, "shared:cb", WithDistributedThresholds(2, 2, 500*time.Millisecond, 30*time.Second), WithReplicaID("replica-a"))
+	b := NewDistributedCircuitBreaker(client, "shared:cb", WithDistributedThresholds(2, 2, 500*time.Millisecond, 30*time.Second), WithReplicaID("replica-b"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a.Start(ctx)
+	b.Start(ctx)
+	defer a.Stop()
+	defer b.Stop()
+
+	// Replica a discovers the failures; replica b should learn the
+	// resulting OPEN state via pub/sub without recording any failures of
+	// its own.
+	a.RecordFailure(10 * time.Millisecond)
+	a.RecordFailure(10 * time.Millisecond)
+
+	if a.GetState() != OPEN {
+		t.Fatalf("expected replica a to see OPEN, got %v", a.GetState())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if b.GetState() == OPEN {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if b.GetState() != OPEN {
+		t.Fatalf("expected replica b to learn OPEN via pub/sub, got %v", b.GetState())
+	}
+}
+
+func TestDistributedCircuitBreaker_OnlyOneReplicaWinsTheHalfOpenProbe(t *testing.T) {
+	client := newTestRedisClient(t)
+
+	a := NewDistributedCircuitBreaker(client, "probe:cb", WithDistributedThresholds(1, 2, 500*time.Millisecond, 10*time.Millisecond), WithReplicaID("replica-a"))
+	b := NewDistributedCircuitBreaker(client, "probe:cb", WithDistributedThresholds(1, 2, 500*time.Millisecond, 10*time.Millisecond), WithReplicaID("replica-b"))
+
+	ctx := context.Background()
+	a.Start(ctx)
+	b.Start(ctx)
+	defer a.Stop()
+	defer b.Stop()
+
+	a.RecordFailure(10 * time.Millisecond)
+	if a.GetState() != OPEN {
+		t.Fatalf("expected OPEN after 1 failure with failureThreshold=1, got %v", a.GetState())
+	}
+
+	// Give both replicas' local caches time to catch up with the OPEN
+	// state, then wait out resetTimeout so a probe becomes eligible.
+	time.Sleep(50 * time.Millisecond)
+
+	aWon := a.tryBecomeProbe()
+	bWon := b.tryBecomeProbe()
+	if aWon == bWon {
+		t.Fatalf("expected exactly one replica to win the probe token, got a=%v b=%v", aWon, bWon)
+	}
+}
+
+func TestDistributedCircuitBreaker_ClosesAfterSuccessThresholdInHalfOpen(t *testing.T) {
+	client := newTestRedisClient(t)
+	dcb := NewDistributedCircuitBreaker(client, "recover:cb", WithDistributedThresholds(1, 2, 500*time.Millisecond, 10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dcb.Start(ctx)
+	defer dcb.Stop()
+
+	dcb.RecordFailure(10 * time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	if !dcb.tryBecomeProbe() {
+		t.Fatal("expected to win the uncontested probe token")
+	}
+	if dcb.GetState() != HALF_OPEN {
+		t.Fatalf("expected HALF_OPEN after winning the probe, got %v", dcb.GetState())
+	}
+
+	dcb.RecordSuccess(10 * time.Millisecond)
+	dcb.RecordSuccess(10 * time.Millisecond)
+
+	if dcb.GetState() != CLOSED {
+		t.Fatalf("expected CLOSED after successThreshold successes in HALF_OPEN, got %v", dcb.GetState())
+	}
+}
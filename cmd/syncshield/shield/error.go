@@ -0,0 +1,108 @@
+package shield
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies why a circuit-breaker-guarded or connector call
+// failed, so downstream observability (metrics, alerting, log filtering)
+// can distinguish "budget exceeded" from "grpc unavailable" from "half-open
+// probe rejected" instead of pattern-matching error strings - the same
+// motivation behind chainlink prefixing RPC errors with "RPCClient returned
+// error (...)".
+type ErrorKind int
+
+const (
+	ErrorKindUnknown ErrorKind = iota
+	ErrorKindBudgetExceeded
+	ErrorKindCircuitOpen
+	ErrorKindHalfOpenRejected
+	ErrorKindUpstreamUnavailable
+	ErrorKindTimeout
+	ErrorKindInvalidBid
+)
+
+// String renders k the way Error.Error() embeds it, and the way callers
+// filtering logs or metrics would match on it.
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindBudgetExceeded:
+		return "budget_exceeded"
+	case ErrorKindCircuitOpen:
+		return "circuit_open"
+	case ErrorKindHalfOpenRejected:
+		return "half_open_rejected"
+	case ErrorKindUpstreamUnavailable:
+		return "upstream_unavailable"
+	case ErrorKindTimeout:
+		return "timeout"
+	case ErrorKindInvalidBid:
+		return "invalid_bid"
+	default:
+		return "unknown"
+	}
+}
+
+// Error wraps a circuit-breaker or connector failure with the structure
+// IsRetryable/IsBudgetError (and any downstream observability) need:  what
+// kind of failure, which platform/endpoint, and the breaker's state at the
+// time. Underlying is always set, and Unwrap exposes it, so errors.Is/As
+// against a sentinel like ErrCircuitOpen keeps working through the wrapper.
+type Error struct {
+	Kind       ErrorKind
+	Platform   string
+	Endpoint   string
+	State      CircuitBreakerState
+	Underlying error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Platform != "" && e.Endpoint != "":
+		return fmt.Sprintf("shield: %s [platform=%s endpoint=%s state=%s]: %v", e.Kind, e.Platform, e.Endpoint, e.State, e.Underlying)
+	case e.Platform != "":
+		return fmt.Sprintf("shield: %s [platform=%s state=%s]: %v", e.Kind, e.Platform, e.State, e.Underlying)
+	default:
+		return fmt.Sprintf("shield: %s [state=%s]: %v", e.Kind, e.State, e.Underlying)
+	}
+}
+
+// Unwrap exposes Underlying so errors.Is(err, ErrCircuitOpen) and similar
+// sentinel checks still work when err is a *shield.Error.
+func (e *Error) Unwrap() error { return e.Underlying }
+
+// NewError builds an Error of kind wrapping underlying, for platform (may
+// be "" when the caller has no single platform in scope) at breaker state
+// state.
+func NewError(kind ErrorKind, platform string, state CircuitBreakerState, underlying error) *Error {
+	return &Error{Kind: kind, Platform: platform, State: state, Underlying: underlying}
+}
+
+// IsErrorRetryable reports whether err represents a failure worth
+// retrying. BudgetExceeded and InvalidBid are permanent for a given bid -
+// retrying won't make the budget bigger or the bid valid - every other
+// Kind (and any error that isn't a *shield.Error at all) is treated as
+// retryable, the same default callers had before this type existed. Named
+// IsErrorRetryable, not IsRetryable, to avoid colliding with the
+// IsRetryable predicate type RetryPolicy already uses (see retry.go).
+func IsErrorRetryable(err error) bool {
+	var se *Error
+	if !errors.As(err, &se) {
+		return true
+	}
+	switch se.Kind {
+	case ErrorKindBudgetExceeded, ErrorKindInvalidBid:
+		return false
+	default:
+		return true
+	}
+}
+
+// IsBudgetError reports whether err is a *shield.Error of Kind
+// BudgetExceeded, for callers (e.g. alerting) that only care about budget
+// vetoes rather than every possible failure kind.
+func IsBudgetError(err error) bool {
+	var se *Error
+	return errors.As(err, &se) && se.Kind == ErrorKindBudgetExceeded
+}
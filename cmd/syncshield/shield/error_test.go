@@ -0,0 +1,109 @@
+package shield
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestError_UnwrapExposesSentinelForErrorsIs(t *testing.T) {
+	err := NewError(ErrorKindCircuitOpen, "x", OPEN, ErrCircuitOpen)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected errors.Is to see through *Error to ErrCircuitOpen")
+	}
+}
+
+func TestError_AsExposesKindAndPlatform(t *testing.T) {
+	wrapped := NewError(ErrorKindBudgetExceeded, "meta", CLOSED, errors.New("budget exceeded"))
+	var se *Error
+	if !errors.As(error(wrapped), &se) {
+		t.Fatalf("expected errors.As to match *Error")
+	}
+	if se.Kind != ErrorKindBudgetExceeded || se.Platform != "meta" {
+		t.Errorf("expected Kind=BudgetExceeded Platform=meta, got Kind=%v Platform=%s", se.Kind, se.Platform)
+	}
+}
+
+func TestIsErrorRetryable_FalseForBudgetAndInvalidBid(t *testing.T) {
+	budgetErr := NewError(ErrorKindBudgetExceeded, "x", CLOSED, errors.New("over budget"))
+	invalidErr := NewError(ErrorKindInvalidBid, "x", CLOSED, errors.New("bad bid"))
+	if IsErrorRetryable(budgetErr) {
+		t.Errorf("expected a budget-exceeded error to not be retryable")
+	}
+	if IsErrorRetryable(invalidErr) {
+		t.Errorf("expected an invalid-bid error to not be retryable")
+	}
+}
+
+func TestIsErrorRetryable_TrueForUpstreamAndUnstructuredErrors(t *testing.T) {
+	upstreamErr := NewError(ErrorKindUpstreamUnavailable, "x", OPEN, errors.New("503"))
+	if !IsErrorRetryable(upstreamErr) {
+		t.Errorf("expected an upstream-unavailable error to be retryable")
+	}
+	if !IsErrorRetryable(errors.New("plain error")) {
+		t.Errorf("expected an unstructured error to default to retryable")
+	}
+}
+
+func TestIsBudgetError(t *testing.T) {
+	budgetErr := NewError(ErrorKindBudgetExceeded, "x", CLOSED, errors.New("over budget"))
+	timeoutErr := NewError(ErrorKindTimeout, "x", CLOSED, errors.New("deadline"))
+	if !IsBudgetError(budgetErr) {
+		t.Errorf("expected IsBudgetError to be true for a BudgetExceeded error")
+	}
+	if IsBudgetError(timeoutErr) {
+		t.Errorf("expected IsBudgetError to be false for a Timeout error")
+	}
+	if IsBudgetError(errors.New("plain error")) {
+		t.Errorf("expected IsBudgetError to be false for an unstructured error")
+	}
+}
+
+func TestCallWithCircuitBreaker_WrapsCircuitOpenAsStructuredError(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.SetThresholds(1, 1, 500*time.Millisecond, time.Hour)
+	cb.RecordFailure(0) // OPEN
+
+	grpcCall := func(ctx context.Context) (interface{}, time.Duration, error) {
+		t.Fatal("grpcCall should not run while the circuit is OPEN")
+		return nil, 0, nil
+	}
+	fallbackCall := func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("fallback also failed")
+	}
+
+	_, _, err := cb.CallWithCircuitBreaker(context.Background(), grpcCall, fallbackCall)
+	var se *Error
+	if !errors.As(err, &se) {
+		t.Fatalf("expected a *Error, got %v (%T)", err, err)
+	}
+	if se.Kind != ErrorKindCircuitOpen {
+		t.Errorf("expected Kind=CircuitOpen, got %v", se.Kind)
+	}
+}
+
+func TestCallWithCircuitBreaker_WrapsUpstreamFailureAsStructuredError(t *testing.T) {
+	cb := NewCircuitBreaker()
+	cb.SetThresholds(5, 2, 500*time.Millisecond, time.Hour)
+
+	grpcCall := func(ctx context.Context) (interface{}, time.Duration, error) {
+		return nil, 10 * time.Millisecond, errors.New("connection refused")
+	}
+	fallbackCall := func(ctx context.Context) (interface{}, error) {
+		t.Fatal("fallback should not run before the circuit trips open")
+		return nil, nil
+	}
+
+	_, source, err := cb.CallWithCircuitBreaker(context.Background(), grpcCall, fallbackCall)
+	if source != "grpc_failed" {
+		t.Fatalf("expected source grpc_failed, got %s", source)
+	}
+	var se *Error
+	if !errors.As(err, &se) {
+		t.Fatalf("expected a *Error, got %v (%T)", err, err)
+	}
+	if se.Kind != ErrorKindUpstreamUnavailable {
+		t.Errorf("expected Kind=UpstreamUnavailable, got %v", se.Kind)
+	}
+}
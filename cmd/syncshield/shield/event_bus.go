@@ -0,0 +1,199 @@
+package shield
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of structured event EventBus publishes.
+type EventType string
+
+const (
+	EventBudgetThresholdCrossed EventType = "budget_threshold_crossed"
+	EventBudgetVeto             EventType = "budget_veto"
+	EventCircuitOpened          EventType = "circuit_opened"
+	EventCircuitClosed          EventType = "circuit_closed"
+	EventCircuitHalfOpen        EventType = "circuit_half_open"
+	EventFallbackEngaged        EventType = "fallback_engaged"
+	EventRetryExhausted         EventType = "retry_exhausted"
+
+	// EventPolicyShortCircuited is published by a Policy (BulkheadPolicy,
+	// RateLimiterPolicy, HedgePolicy) that declined or exhausted a call
+	// without CircuitBreaker's or RetryPolicy's own dedicated event types
+	// applying - see Event.Data["policy"]/["reason"] for which policy and
+	// why.
+	EventPolicyShortCircuited EventType = "policy_short_circuited"
+)
+
+// Event is a structured notification about a shield state transition -
+// budget utilization, circuit breaker transitions, fallback engagement, or
+// exhausted retries - published to every subscriber and registered sink on
+// an EventBus, so operators can wire dashboards/alerting without polling
+// GetStats.
+type Event struct {
+	Type      EventType
+	Source    string // the publishing component, e.g. "x_smart", "budget_manager"
+	Timestamp time.Time
+	Data      map[string]interface{}
+}
+
+// EventSink is an additional destination EventBus fans events out to,
+// alongside any in-memory subscriber channels - e.g. an outbound webhook.
+type EventSink interface {
+	Write(event Event) error
+}
+
+// eventSubscriberBufferSize bounds each Subscribe channel. Publish never
+// blocks on a slow subscriber: once full, the oldest buffered event is
+// dropped to make room for the new one, since subscribers are for
+// observability (dashboards, tests), not a delivery guarantee.
+const eventSubscriberBufferSize = 64
+
+// eventSinkQueueDepth bounds each registered sink's pending-event channel.
+const eventSinkQueueDepth = 64
+
+// EventSinkRegistration wires one EventSink into an EventBus's fan-out.
+type EventSinkRegistration struct {
+	// Name identifies the sink in warning log lines.
+	Name string
+	Sink EventSink
+
+	// RetryPolicy governs Write retries; nil falls back to
+	// DefaultRetryPolicy().
+	RetryPolicy *RetryPolicy
+}
+
+// eventSinkWorker owns one registered sink's queue and retry loop.
+type eventSinkWorker struct {
+	reg   EventSinkRegistration
+	queue chan Event
+	done  chan struct{}
+}
+
+// EventBus fans out structured shield events to in-memory subscribers and
+// registered EventSinks. The zero value is not usable; construct one with
+// NewEventBus.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers []chan Event
+	workers     []*eventSinkWorker
+}
+
+// NewEventBus creates an empty EventBus ready for Subscribe/RegisterSink.
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe returns a channel receiving every event published after this
+// call. See eventSubscriberBufferSize for overflow behavior.
+func (b *EventBus) Subscribe() <-chan Event {
+	ch := make(chan Event, eventSubscriberBufferSize)
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, ch)
+	b.mu.Unlock()
+	return ch
+}
+
+// RegisterSink starts reg's async delivery worker and returns immediately.
+// Every event published afterward is queued to it.
+func (b *EventBus) RegisterSink(reg EventSinkRegistration) {
+	w := &eventSinkWorker{
+		reg:   reg,
+		queue: make(chan Event, eventSinkQueueDepth),
+		done:  make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	b.workers = append(b.workers, w)
+	b.mu.Unlock()
+
+	go b.runSinkWorker(w)
+}
+
+// runSinkWorker drains w.queue until it's closed, delivering each event to
+// w.reg.Sink under w.reg.RetryPolicy (or DefaultRetryPolicy), logging a
+// warning rather than blocking when retries are exhausted.
+func (b *EventBus) runSinkWorker(w *eventSinkWorker) {
+	defer close(w.done)
+
+	policy := w.reg.RetryPolicy
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+
+	for event := range w.queue {
+		policy.Reset()
+		_, _, err := policy.ExecuteWithRetry(context.Background(), func(ctx context.Context, attempt int) (interface{}, error) {
+			return nil, w.reg.Sink.Write(event)
+		}, DefaultIsRetryable)
+
+		if err != nil {
+			fmt.Printf("⚠️  event sink %q exhausted retries, dropping %s event: %v\n", w.reg.Name, event.Type, err)
+		}
+	}
+}
+
+// Publish fans event out to every subscriber channel and registered sink.
+// It never blocks: a full subscriber channel drops its oldest buffered
+// event, and a full sink queue drops the new event with a logged warning -
+// Publish is called from hot paths like PlaceBid and must never stall them.
+func (b *EventBus) Publish(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	b.mu.RLock()
+	subs := make([]chan Event, len(b.subscribers))
+	copy(subs, b.subscribers)
+	workers := make([]*eventSinkWorker, len(b.workers))
+	copy(workers, b.workers)
+	b.mu.RUnlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- event:
+		default:
+			// Drop the oldest buffered event to make room, then retry once.
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+
+	for _, w := range workers {
+		select {
+		case w.queue <- event:
+		default:
+			fmt.Printf("⚠️  event sink %q queue full, dropping %s event\n", w.reg.Name, event.Type)
+		}
+	}
+}
+
+// Close closes every registered sink's queue and waits for its worker to
+// drain, running all sinks' shutdowns concurrently so Close's latency is
+// the slowest sink's, not their sum. Subscriber channels are left open -
+// callers own those and may keep reading buffered events after Close.
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	workers := make([]*eventSinkWorker, len(b.workers))
+	copy(workers, b.workers)
+	b.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w *eventSinkWorker) {
+			defer wg.Done()
+			close(w.queue)
+			<-w.done
+		}(w)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,114 @@
+package shield
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventBusSubscribeReceivesPublishedEvent(t *testing.T) {
+	bus := NewEventBus()
+	ch := bus.Subscribe()
+
+	bus.Publish(Event{Type: EventCircuitOpened, Source: "test"})
+
+	select {
+	case got := <-ch:
+		if got.Type != EventCircuitOpened || got.Source != "test" {
+			t.Errorf("unexpected event: %+v", got)
+		}
+		if got.Timestamp.IsZero() {
+			t.Error("expected Publish to stamp a zero Timestamp")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for subscriber to receive event")
+	}
+}
+
+func TestEventBusPublishDropsOldestWhenSubscriberFull(t *testing.T) {
+	bus := NewEventBus()
+	ch := bus.Subscribe()
+
+	for i := 0; i < eventSubscriberBufferSize+10; i++ {
+		bus.Publish(Event{Type: EventBudgetVeto, Source: "test"})
+	}
+
+	if len(ch) != eventSubscriberBufferSize {
+		t.Fatalf("expected subscriber channel to stay at capacity %d, got %d", eventSubscriberBufferSize, len(ch))
+	}
+}
+
+// countingSink counts successful writes; it never errors.
+type countingSink struct {
+	writes int64
+}
+
+func (s *countingSink) Write(event Event) error {
+	atomic.AddInt64(&s.writes, 1)
+	return nil
+}
+
+func TestEventBusRegisterSinkDeliversPublishedEvents(t *testing.T) {
+	bus := NewEventBus()
+	sink := &countingSink{}
+	bus.RegisterSink(EventSinkRegistration{Name: "counting", Sink: sink})
+
+	bus.Publish(Event{Type: EventFallbackEngaged, Source: "test"})
+	bus.Close()
+
+	if got := atomic.LoadInt64(&sink.writes); got != 1 {
+		t.Errorf("expected sink to receive 1 event, got %d", got)
+	}
+}
+
+// flakySink fails its first N writes, then succeeds - exercises RetryPolicy.
+type flakySink struct {
+	failuresLeft int32
+	writes       int64
+}
+
+func (s *flakySink) Write(event Event) error {
+	atomic.AddInt64(&s.writes, 1)
+	if atomic.AddInt32(&s.failuresLeft, -1) >= 0 {
+		return errors.New("503 service unavailable")
+	}
+	return nil
+}
+
+func TestEventBusRegisterSinkRetriesTransientFailures(t *testing.T) {
+	bus := NewEventBus()
+	sink := &flakySink{failuresLeft: 2}
+	bus.RegisterSink(EventSinkRegistration{
+		Name: "flaky",
+		Sink: sink,
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:       5,
+			InitialBackoff:    time.Millisecond,
+			MaxBackoff:        5 * time.Millisecond,
+			BackoffMultiplier: 2.0,
+		},
+	})
+
+	bus.Publish(Event{Type: EventRetryExhausted, Source: "test"})
+	bus.Close()
+
+	if got := atomic.LoadInt64(&sink.writes); got != 3 {
+		t.Errorf("expected sink to be written to 3 times (2 failures + 1 success), got %d", got)
+	}
+}
+
+func TestEventBusCloseDrainsQueuedEvents(t *testing.T) {
+	bus := NewEventBus()
+	sink := &countingSink{}
+	bus.RegisterSink(EventSinkRegistration{Name: "counting", Sink: sink})
+
+	for i := 0; i < 5; i++ {
+		bus.Publish(Event{Type: EventCircuitClosed, Source: "test"})
+	}
+	bus.Close()
+
+	if got := atomic.LoadInt64(&sink.writes); got != 5 {
+		t.Errorf("expected Close to drain all 5 queued events, got %d", got)
+	}
+}
@@ -0,0 +1,102 @@
+package shield
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ChannelEventSink is an EventSink that forwards every event onto a Go
+// channel - the same shape Subscribe gives a caller, but usable as a
+// registered sink when a test wants RegisterSink's retry/queue plumbing
+// exercised rather than Subscribe's drop-oldest semantics.
+type ChannelEventSink struct {
+	Events chan Event
+}
+
+// NewChannelEventSink creates a ChannelEventSink with the given buffer
+// size.
+func NewChannelEventSink(buffer int) *ChannelEventSink {
+	return &ChannelEventSink{Events: make(chan Event, buffer)}
+}
+
+// Write implements EventSink.
+func (s *ChannelEventSink) Write(event Event) error {
+	select {
+	case s.Events <- event:
+		return nil
+	default:
+		return fmt.Errorf("channel event sink buffer full")
+	}
+}
+
+// webhookEventTimeout bounds a single delivery POST.
+const webhookEventTimeout = 5 * time.Second
+
+// WebhookEventSink posts each Event as a JSON body to an HTTP endpoint,
+// optionally signing it with an HMAC-SHA256 hex digest carried in
+// SignatureHeader - mirroring the compliance package's webhook/HMAC sink
+// conventions, adapted to shield's Event type.
+type WebhookEventSink struct {
+	URL    string
+	Secret string // empty disables signing
+
+	// SignatureHeader names the header the HMAC-SHA256 hex digest of the
+	// body is carried in. Defaults to X-Signature if empty.
+	SignatureHeader string
+
+	httpClient *http.Client
+}
+
+// NewWebhookEventSink creates a WebhookEventSink posting to url, signing
+// each delivery with secret if non-empty.
+func NewWebhookEventSink(url, secret string) *WebhookEventSink {
+	return &WebhookEventSink{
+		URL:             url,
+		Secret:          secret,
+		SignatureHeader: "X-Signature",
+		httpClient:      &http.Client{Timeout: webhookEventTimeout},
+	}
+}
+
+// Write implements EventSink.
+func (w *WebhookEventSink) Write(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if w.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.Secret))
+		mac.Write(body)
+		header := w.SignatureHeader
+		if header == "" {
+			header = "X-Signature"
+		}
+		req.Header.Set(header, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post event to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	defer io.Copy(io.Discard, resp.Body) // drain so the connection can be reused
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package shield
+
+import "math"
+
+// latencyWindowSize bounds how many recent successful-call latencies
+// latencyDigest's EWMA and quantile estimate are effectively computed
+// over, under LatencyEWMA/LatencyQuantile modes.
+const latencyWindowSize = 1000
+
+// digestMaxCentroids bounds how many (value, weight) centroids
+// latencyDigest keeps before compressing - a t-digest-style compression
+// ratio of roughly 1/e (368) of the window size.
+const digestMaxCentroids = 368
+
+// digestCentroid is one (mean, weight) bucket of a latencyDigest, the
+// building block a t-digest or the CKMS streaming quantile algorithm
+// summarizes a distribution with.
+type digestCentroid struct {
+	mean   float64
+	weight float64
+}
+
+// latencyDigest is a compact streaming summary of recent successful-call
+// latencies: an EWMA for "typical" latency, plus a t-digest-style set of
+// (value, weight) centroids for quantile estimation, so CircuitBreaker
+// can judge a latency against the traffic's own recent shape instead of
+// one fixed threshold. Latencies are stored as float64 seconds to keep
+// the digest's arithmetic independent of time.Duration's int64 ticks.
+//
+// It approximates a sliding window over the last latencyWindowSize
+// samples by renormalizing total centroid weight back down once it's
+// exceeded, rather than tracking a true FIFO of samples - cheap, and
+// close enough for "ride out jitter, still trip on a real regression".
+type latencyDigest struct {
+	centroids []digestCentroid
+	count     int64
+
+	ewma            float64
+	ewmaInitialized bool
+	alpha           float64 // EWMA smoothing factor, derived from latencyWindowSize
+}
+
+// newLatencyDigest returns an empty digest. Quantile and EWMA both
+// return 0 until at least one value has been inserted.
+func newLatencyDigest() *latencyDigest {
+	return &latencyDigest{
+		alpha: 2.0 / (float64(latencyWindowSize) + 1),
+	}
+}
+
+// Insert folds one successful call's latency (in seconds) into the EWMA
+// and the quantile digest.
+func (d *latencyDigest) Insert(valueSeconds float64) {
+	d.count++
+	if !d.ewmaInitialized {
+		d.ewma = valueSeconds
+		d.ewmaInitialized = true
+	} else {
+		d.ewma = d.alpha*valueSeconds + (1-d.alpha)*d.ewma
+	}
+	d.insertCentroid(valueSeconds)
+	d.compress()
+	d.decayWindow()
+}
+
+// insertCentroid merges value into an existing centroid with the same
+// mean if one exists, otherwise adds a new singleton centroid, keeping
+// centroids sorted by mean the way a t-digest does.
+func (d *latencyDigest) insertCentroid(value float64) {
+	const mergeEpsilon = 1e-9
+
+	lo, hi := 0, len(d.centroids)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if d.centroids[mid].mean < value {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	i := lo
+
+	if i < len(d.centroids) && d.centroids[i].mean-value < mergeEpsilon {
+		d.mergeAt(i, value, 1)
+		return
+	}
+	if i > 0 && value-d.centroids[i-1].mean < mergeEpsilon {
+		d.mergeAt(i-1, value, 1)
+		return
+	}
+
+	d.centroids = append(d.centroids, digestCentroid{})
+	copy(d.centroids[i+1:], d.centroids[i:])
+	d.centroids[i] = digestCentroid{mean: value, weight: 1}
+}
+
+// mergeAt folds (value, weight) into the centroid at index i, moving its
+// mean toward value in proportion to the incoming weight.
+func (d *latencyDigest) mergeAt(i int, value, weight float64) {
+	c := &d.centroids[i]
+	total := c.weight + weight
+	c.mean = (c.mean*c.weight + value*weight) / total
+	c.weight = total
+}
+
+// compress bounds the centroid count to digestMaxCentroids by repeatedly
+// merging the two closest neighbors - the same amortized cost tradeoff a
+// t-digest's compression pass makes.
+func (d *latencyDigest) compress() {
+	for len(d.centroids) > digestMaxCentroids {
+		closest := 0
+		smallestGap := math.MaxFloat64
+		for i := 0; i+1 < len(d.centroids); i++ {
+			if gap := d.centroids[i+1].mean - d.centroids[i].mean; gap < smallestGap {
+				smallestGap = gap
+				closest = i
+			}
+		}
+		d.mergeAt(closest, d.centroids[closest+1].mean, d.centroids[closest+1].weight)
+		d.centroids = append(d.centroids[:closest+1], d.centroids[closest+2:]...)
+	}
+}
+
+// decayWindow renormalizes total centroid weight back down to
+// latencyWindowSize once it's exceeded, approximating a sliding window
+// over the most recent samples.
+func (d *latencyDigest) decayWindow() {
+	total := d.totalWeight()
+	if total <= latencyWindowSize {
+		return
+	}
+	scale := float64(latencyWindowSize) / total
+	for i := range d.centroids {
+		d.centroids[i].weight *= scale
+	}
+}
+
+func (d *latencyDigest) totalWeight() float64 {
+	var total float64
+	for _, c := range d.centroids {
+		total += c.weight
+	}
+	return total
+}
+
+// Quantile estimates the q-th quantile (0 <= q <= 1) of the latencies
+// seen so far by walking the digest's centroids in mean order until
+// their cumulative weight reaches q's share of the total.
+func (d *latencyDigest) Quantile(q float64) float64 {
+	if len(d.centroids) == 0 {
+		return 0
+	}
+	target := q * d.totalWeight()
+
+	var cumulative float64
+	for i, c := range d.centroids {
+		cumulative += c.weight
+		if cumulative >= target || i == len(d.centroids)-1 {
+			return c.mean
+		}
+	}
+	return d.centroids[len(d.centroids)-1].mean
+}
+
+// EWMA returns the current exponentially weighted moving average of
+// recent successful-call latencies, in seconds.
+func (d *latencyDigest) EWMA() float64 {
+	return d.ewma
+}
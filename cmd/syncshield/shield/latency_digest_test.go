@@ -0,0 +1,62 @@
+package shield
+
+import "testing"
+
+func TestLatencyDigest_EWMATracksRecentValues(t *testing.T) {
+	d := newLatencyDigest()
+	for i := 0; i < 100; i++ {
+		d.Insert(0.1) // 100ms
+	}
+	if got := d.EWMA(); got < 0.099 || got > 0.101 {
+		t.Errorf("expected EWMA to converge to ~0.1, got %v", got)
+	}
+}
+
+func TestLatencyDigest_QuantileOrdersAcrossASkewedDistribution(t *testing.T) {
+	d := newLatencyDigest()
+	for i := 0; i < 90; i++ {
+		d.Insert(0.05)
+	}
+	for i := 0; i < 10; i++ {
+		d.Insert(0.5)
+	}
+
+	p50 := d.Quantile(0.5)
+	p99 := d.Quantile(0.99)
+	if p50 >= p99 {
+		t.Errorf("expected p50 (%v) < p99 (%v) for a skewed distribution", p50, p99)
+	}
+	if diff := p50 - 0.05; diff > 1e-6 || diff < -1e-6 {
+		t.Errorf("expected the median of a 90%% fast / 10%% slow mix to be ~the fast value, got %v", p50)
+	}
+}
+
+func TestLatencyDigest_QuantileOnEmptyDigestIsZero(t *testing.T) {
+	d := newLatencyDigest()
+	if got := d.Quantile(0.99); got != 0 {
+		t.Errorf("expected Quantile on an empty digest to be 0, got %v", got)
+	}
+	if got := d.EWMA(); got != 0 {
+		t.Errorf("expected EWMA on an empty digest to be 0, got %v", got)
+	}
+}
+
+func TestLatencyDigest_CompressBoundsCentroidCount(t *testing.T) {
+	d := newLatencyDigest()
+	for i := 0; i < 5000; i++ {
+		d.Insert(float64(i) / 1000)
+	}
+	if len(d.centroids) > digestMaxCentroids {
+		t.Errorf("expected compress to bound centroids to %d, got %d", digestMaxCentroids, len(d.centroids))
+	}
+}
+
+func TestLatencyDigest_DecayWindowBoundsTotalWeight(t *testing.T) {
+	d := newLatencyDigest()
+	for i := 0; i < latencyWindowSize*3; i++ {
+		d.Insert(0.1)
+	}
+	if total := d.totalWeight(); total > latencyWindowSize+1 {
+		t.Errorf("expected total weight to stay near the window size, got %v", total)
+	}
+}
@@ -1,54 +1,124 @@
 package shield
 
 import (
+	"math"
+	"strconv"
 	"sync"
 	"time"
 )
 
+// defaultErrorRateHalfLife is how long it takes a failure's contribution to
+// ErrorRateEWMA to decay to half its weight, absent a call to
+// SetErrorRateHalfLife.
+const defaultErrorRateHalfLife = 30 * time.Second
+
+// latencyHistogramBuckets are the Prometheus-style bucket boundaries (in
+// milliseconds) for the request latency histogram. Shared with
+// ServeHTTP/Handler so the exposed syncshield_request_latency_ms_bucket
+// series use the same boundaries recordLatency counts into.
+var latencyHistogramBuckets = []float64{10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
 // MetricsCollector provides observability for circuit breaker and resilience patterns
 // Exports Prometheus-compatible metrics for production monitoring
 type MetricsCollector struct {
 	mu sync.RWMutex
 
-	// Circuit Breaker State Counters
-	breakerStateClosed   int64 // Total time in CLOSED state (healthy)
-	breakerStateOpen     int64 // Total time in OPEN state (failing)
-	breakerStateHalfOpen int64 // Total time in HALF_OPEN state (probing recovery)
+	// name tags every series ServeHTTP renders with connector="name", so a
+	// process running more than one connector's CircuitBreaker can still be
+	// scraped from a single /metrics endpoint. Empty for a single-connector
+	// process, in which case the label is omitted.
+	name string
+
+	// createdAt anchors StateFraction's "fraction of the collector's
+	// lifetime" denominator; unlike lastStateChange, it never moves.
+	createdAt time.Time
+
+	// Circuit Breaker State Durations - cumulative time spent in each state,
+	// not transition counts. Each holds the time accrued across every past
+	// visit to that state; the time since lastStateChange in whichever state
+	// lastStateChangedTo names is still ongoing and is added in by
+	// TimeInState/StateFraction, not accounted for here until the next
+	// transition closes it out.
+	breakerStateClosed   time.Duration
+	breakerStateOpen     time.Duration
+	breakerStateHalfOpen time.Duration
 
 	// Request Counters
 	totalRequests      int64 // All requests attempted
 	successfulRequests int64 // Requests that succeeded via gRPC
 	failedRequests     int64 // Requests that failed (error or latency)
 	fallbackRequests   int64 // Requests served by fallback heuristic
+	cancelledRequests  int64 // Requests cancelled by the caller's own context, not counted as failures
 
 	// Latency Histograms (in milliseconds)
-	latencyBuckets map[string]int64 // Buckets: p50, p75, p90, p95, p99
-	latencySamples []float64        // Recent latency samples (for percentile calculation)
+	latencyBuckets map[string]int64 // Cumulative count per bucket, Prometheus histogram semantics
+	latencyDigest  *tDigest         // Streaming t-digest for percentile estimation - see tdigest.go
+	latencySum     float64          // Sum of every latency ever recorded, for _sum
+	latencyCount   int64            // Count of every latency ever recorded, for _count
 
 	// Error Tracking
 	errorsByType map[string]int64 // Count errors by type (timeout, 5xx, etc.)
 
+	// errorRateEWMA is a time-decayed failed/total ratio: every
+	// RecordSuccess/RecordFailure blends in a 0 or 1 observation weighted by
+	// how long it's been since the last observation, so it settles toward
+	// the recent failure rate instead of the lifetime average a raw counter
+	// ratio would give - a stabler signal for a circuit breaker to trip on.
+	errorRateEWMA       float64
+	errorRateHalfLife   time.Duration
+	errorRateLastUpdate time.Time
+
 	// State Transition Tracking
 	stateTransitions     int64 // Total state transitions
 	lastStateChange      time.Time
 	lastStateChangedFrom CircuitBreakerState
 	lastStateChangedTo   CircuitBreakerState
 
-	// Configuration
-	maxLatencySamples int // Max samples to keep for percentile calculation
 }
 
 // NewMetricsCollector creates a metrics collector with default configuration
 func NewMetricsCollector() *MetricsCollector {
+	now := time.Now()
 	return &MetricsCollector{
 		latencyBuckets:    make(map[string]int64),
 		errorsByType:      make(map[string]int64),
-		latencySamples:    make([]float64, 0, 1000),
-		maxLatencySamples: 1000,
-		lastStateChange:   time.Now(),
+		latencyDigest:     newTDigest(defaultTDigestCompression),
+		createdAt:         now,
+		lastStateChange:   now,
+		errorRateHalfLife: defaultErrorRateHalfLife,
 	}
 }
 
+// SetErrorRateHalfLife overrides ErrorRateEWMA's decay half-life (default
+// 30s). A shorter half-life reacts faster to a fresh burst of failures but
+// forgets them faster too; a longer one smooths out noise at the cost of
+// lagging behind a real regression.
+func (mc *MetricsCollector) SetErrorRateHalfLife(halfLife time.Duration) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.errorRateHalfLife = halfLife
+}
+
+// SetLatencyDigestCompression overrides the t-digest compression parameter
+// δ (default 100) used for latency percentile estimation. Must be called
+// before any latency has been recorded - it replaces the digest outright,
+// discarding whatever it had already absorbed.
+func (mc *MetricsCollector) SetLatencyDigestCompression(compression float64) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.latencyDigest = newTDigest(compression)
+}
+
+// NewNamedMetricsCollector creates a metrics collector whose ServeHTTP
+// output tags every series with connector="name". Use this instead of
+// NewMetricsCollector when more than one connector's CircuitBreaker will be
+// registered on the same MetricsRegistry.
+func NewNamedMetricsCollector(name string) *MetricsCollector {
+	mc := NewMetricsCollector()
+	mc.name = name
+	return mc
+}
+
 // RecordRequest increments total request counter
 func (mc *MetricsCollector) RecordRequest() {
 	mc.mu.Lock()
@@ -63,6 +133,7 @@ func (mc *MetricsCollector) RecordSuccess(latency time.Duration) {
 
 	mc.successfulRequests++
 	mc.recordLatency(latency)
+	mc.updateErrorRateEWMA(false)
 }
 
 // RecordFailure increments failed request counter, tracks latency, and categorizes error
@@ -72,6 +143,7 @@ func (mc *MetricsCollector) RecordFailure(latency time.Duration, errorType strin
 
 	mc.failedRequests++
 	mc.recordLatency(latency)
+	mc.updateErrorRateEWMA(true)
 
 	// Track error type (timeout, 5xx, etc.)
 	if errorType != "" {
@@ -79,6 +151,41 @@ func (mc *MetricsCollector) RecordFailure(latency time.Duration, errorType strin
 	}
 }
 
+// updateErrorRateEWMA blends a 0 (success) or 1 (failure) observation into
+// errorRateEWMA, weighted by how much of a half-life has elapsed since the
+// last observation - recordLatency and the caller's own lock protect this,
+// so it assumes mc.mu is already held.
+func (mc *MetricsCollector) updateErrorRateEWMA(isFailure bool) {
+	observation := 0.0
+	if isFailure {
+		observation = 1.0
+	}
+
+	if mc.errorRateLastUpdate.IsZero() {
+		mc.errorRateEWMA = observation
+		mc.errorRateLastUpdate = time.Now()
+		return
+	}
+
+	now := time.Now()
+	halfLife := mc.errorRateHalfLife
+	if halfLife <= 0 {
+		halfLife = defaultErrorRateHalfLife
+	}
+
+	decay := math.Pow(0.5, now.Sub(mc.errorRateLastUpdate).Seconds()/halfLife.Seconds())
+	mc.errorRateEWMA = observation*(1-decay) + mc.errorRateEWMA*decay
+	mc.errorRateLastUpdate = now
+}
+
+// ErrorRateEWMA returns the current time-decayed failure rate in [0, 1].
+// See errorRateEWMA for the decay model.
+func (mc *MetricsCollector) ErrorRateEWMA() float64 {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+	return mc.errorRateEWMA
+}
+
 // RecordFallback increments fallback request counter
 func (mc *MetricsCollector) RecordFallback() {
 	mc.mu.Lock()
@@ -86,66 +193,127 @@ func (mc *MetricsCollector) RecordFallback() {
 	mc.fallbackRequests++
 }
 
-// RecordStateTransition tracks circuit breaker state changes
+// RecordCancellation increments the cancelled-request counter. Cancellations
+// originate from the caller's own context deadline/cancel, not an upstream
+// failure, so they're tracked separately from RecordFailure.
+func (mc *MetricsCollector) RecordCancellation() {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.cancelledRequests++
+}
+
+// RecordStateTransition tracks circuit breaker state changes, crediting the
+// time since the last transition to the outgoing (from) state before
+// flipping lastStateChangedTo - see TimeInState for reading these back out.
 func (mc *MetricsCollector) RecordStateTransition(from, to CircuitBreakerState) {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
 
+	now := time.Now()
+	elapsed := now.Sub(mc.lastStateChange)
+
 	mc.stateTransitions++
-	mc.lastStateChange = time.Now()
 	mc.lastStateChangedFrom = from
 	mc.lastStateChangedTo = to
 
-	// Update time-in-state counters
-	// Note: This is a simplified version; for production, use time-weighted averages
-	switch to {
+	switch from {
 	case CLOSED:
-		mc.breakerStateClosed++
+		mc.breakerStateClosed += elapsed
 	case OPEN:
-		mc.breakerStateOpen++
+		mc.breakerStateOpen += elapsed
 	case HALF_OPEN:
-		mc.breakerStateHalfOpen++
+		mc.breakerStateHalfOpen += elapsed
 	}
+
+	mc.lastStateChange = now
 }
 
-// recordLatency adds a latency sample and updates histogram buckets
+// TimeInState returns the cumulative time the breaker has spent in state,
+// including time accrued in the current, still-ongoing visit if state is
+// the current state.
+func (mc *MetricsCollector) TimeInState(state CircuitBreakerState) time.Duration {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+	return mc.timeInStateLocked(state, time.Now())
+}
+
+// timeInStateLocked is TimeInState's body, reusable by callers (GetMetricsSummary,
+// StateFraction) that already hold mc.mu.
+func (mc *MetricsCollector) timeInStateLocked(state CircuitBreakerState, now time.Time) time.Duration {
+	var total time.Duration
+	switch state {
+	case CLOSED:
+		total = mc.breakerStateClosed
+	case OPEN:
+		total = mc.breakerStateOpen
+	case HALF_OPEN:
+		total = mc.breakerStateHalfOpen
+	}
+	if mc.lastStateChangedTo == state {
+		total += now.Sub(mc.lastStateChange)
+	}
+	return total
+}
+
+// StateFraction returns, for each circuit breaker state, the fraction
+// (0..1) of the collector's lifetime spent there - e.g. StateFraction()["open"]
+// is what a dashboard would render as "% of the last hour spent OPEN".
+func (mc *MetricsCollector) StateFraction() map[string]float64 {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	now := time.Now()
+	lifetime := now.Sub(mc.createdAt)
+	fractions := make(map[string]float64, 3)
+	for _, state := range []CircuitBreakerState{CLOSED, OPEN, HALF_OPEN} {
+		if lifetime <= 0 {
+			fractions[breakerStateLabel(state)] = 0
+			continue
+		}
+		fractions[breakerStateLabel(state)] = float64(mc.timeInStateLocked(state, now)) / float64(lifetime)
+	}
+	return fractions
+}
+
+// recordLatency adds a latency sample to the percentile digest and updates
+// histogram buckets
 func (mc *MetricsCollector) recordLatency(latency time.Duration) {
 	latencyMs := float64(latency.Milliseconds())
 
-	// Add to samples
-	mc.latencySamples = append(mc.latencySamples, latencyMs)
+	mc.latencyDigest.Add(latencyMs)
 
-	// Keep only recent samples
-	if len(mc.latencySamples) > mc.maxLatencySamples {
-		mc.latencySamples = mc.latencySamples[len(mc.latencySamples)-mc.maxLatencySamples:]
-	}
+	mc.latencySum += latencyMs
+	mc.latencyCount++
 
 	// Update histogram buckets
 	mc.updateHistogram(latencyMs)
 }
 
-// updateHistogram categorizes latency into buckets for quick querying
+// updateHistogram adds latencyMs to every bucket it falls into. Prometheus
+// histogram buckets are cumulative - le="100" counts every sample <= 100ms,
+// not just the ones that missed the smaller buckets - so histogram_quantile()
+// over these series requires incrementing every bucket >= latencyMs, not
+// just the first match.
 func (mc *MetricsCollector) updateHistogram(latencyMs float64) {
-	// Prometheus-style buckets
-	buckets := []float64{10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
-
-	for _, bucket := range buckets {
+	for _, bucket := range latencyHistogramBuckets {
 		if latencyMs <= bucket {
 			mc.latencyBuckets[formatBucket(bucket)]++
-			return
 		}
 	}
 
-	// Greater than all buckets
+	// +Inf always matches; mirrors _count for a Prometheus histogram.
 	mc.latencyBuckets["+Inf"]++
 }
 
-// GetLatencyPercentiles calculates p50, p75, p90, p95, p99 from recent samples
+// GetLatencyPercentiles estimates p50, p75, p90, p95, p99 from the latency
+// digest. Unlike the fixed-window sample slice this replaced, accuracy -
+// especially at p99 - no longer degrades as throughput grows past the
+// window size, and memory stays O(δ) regardless of request volume.
 func (mc *MetricsCollector) GetLatencyPercentiles() map[string]float64 {
 	mc.mu.RLock()
 	defer mc.mu.RUnlock()
 
-	if len(mc.latencySamples) == 0 {
+	if mc.latencyDigest.Count() == 0 {
 		return map[string]float64{
 			"p50": 0,
 			"p75": 0,
@@ -155,17 +323,12 @@ func (mc *MetricsCollector) GetLatencyPercentiles() map[string]float64 {
 		}
 	}
 
-	// Sort samples (simple bubble sort for small datasets)
-	samples := make([]float64, len(mc.latencySamples))
-	copy(samples, mc.latencySamples)
-	sortFloat64(samples)
-
 	return map[string]float64{
-		"p50": percentile(samples, 0.50),
-		"p75": percentile(samples, 0.75),
-		"p90": percentile(samples, 0.90),
-		"p95": percentile(samples, 0.95),
-		"p99": percentile(samples, 0.99),
+		"p50": mc.latencyDigest.Quantile(0.50),
+		"p75": mc.latencyDigest.Quantile(0.75),
+		"p90": mc.latencyDigest.Quantile(0.90),
+		"p95": mc.latencyDigest.Quantile(0.95),
+		"p99": mc.latencyDigest.Quantile(0.99),
 	}
 }
 
@@ -175,15 +338,19 @@ func (mc *MetricsCollector) GetMetricsSummary() MetricsSummary {
 	defer mc.mu.RUnlock()
 
 	percentiles := mc.GetLatencyPercentiles()
+	now := time.Now()
 
 	return MetricsSummary{
+		Name:                 mc.name,
 		TotalRequests:        mc.totalRequests,
 		SuccessfulRequests:   mc.successfulRequests,
 		FailedRequests:       mc.failedRequests,
 		FallbackRequests:     mc.fallbackRequests,
-		BreakerStateClosed:   mc.breakerStateClosed,
-		BreakerStateOpen:     mc.breakerStateOpen,
-		BreakerStateHalfOpen: mc.breakerStateHalfOpen,
+		CancelledRequests:    mc.cancelledRequests,
+		BreakerStateClosed:   mc.timeInStateLocked(CLOSED, now),
+		BreakerStateOpen:     mc.timeInStateLocked(OPEN, now),
+		BreakerStateHalfOpen: mc.timeInStateLocked(HALF_OPEN, now),
+		CurrentBreakerState:  mc.lastStateChangedTo,
 		StateTransitions:     mc.stateTransitions,
 		LastStateChange:      mc.lastStateChange,
 		LatencyP50:           percentiles["p50"],
@@ -191,6 +358,9 @@ func (mc *MetricsCollector) GetMetricsSummary() MetricsSummary {
 		LatencyP90:           percentiles["p90"],
 		LatencyP95:           percentiles["p95"],
 		LatencyP99:           percentiles["p99"],
+		LatencySumMs:         mc.latencySum,
+		LatencyCount:         mc.latencyCount,
+		ErrorRateEWMA:        mc.errorRateEWMA,
 		ErrorsByType:         copyMap(mc.errorsByType),
 		LatencyBuckets:       copyMap(mc.latencyBuckets),
 	}
@@ -198,14 +368,21 @@ func (mc *MetricsCollector) GetMetricsSummary() MetricsSummary {
 
 // MetricsSummary provides a point-in-time snapshot of all metrics
 type MetricsSummary struct {
+	Name string
+
 	TotalRequests      int64
 	SuccessfulRequests int64
 	FailedRequests     int64
 	FallbackRequests   int64
+	CancelledRequests  int64
 
-	BreakerStateClosed   int64
-	BreakerStateOpen     int64
-	BreakerStateHalfOpen int64
+	// BreakerStateClosed/Open/HalfOpen are cumulative time spent in each
+	// state, including the current, still-ongoing visit - not transition
+	// counts. See MetricsCollector.TimeInState.
+	BreakerStateClosed   time.Duration
+	BreakerStateOpen     time.Duration
+	BreakerStateHalfOpen time.Duration
+	CurrentBreakerState  CircuitBreakerState
 
 	StateTransitions int64
 	LastStateChange  time.Time
@@ -216,43 +393,29 @@ type MetricsSummary struct {
 	LatencyP95 float64
 	LatencyP99 float64
 
+	LatencySumMs float64
+	LatencyCount int64
+
+	// ErrorRateEWMA is the time-decayed failure rate at snapshot time. See
+	// MetricsCollector.ErrorRateEWMA.
+	ErrorRateEWMA float64
+
 	ErrorsByType   map[string]int64
 	LatencyBuckets map[string]int64
 }
 
 // Helper functions
 
+// formatBucket renders a histogram bucket boundary the way Prometheus
+// convention expects: whole milliseconds below 1s ("10ms"), otherwise
+// fractional seconds ("2.5s"). The previous implementation,
+// string(rune(value))+"ms", treated value as a Unicode code point and
+// produced control-character labels instead of numbers.
 func formatBucket(value float64) string {
 	if value < 1000 {
-		return string(rune(value)) + "ms"
-	}
-	return string(rune(value/1000)) + "s"
-}
-
-func percentile(sorted []float64, p float64) float64 {
-	if len(sorted) == 0 {
-		return 0
-	}
-
-	index := int(float64(len(sorted)) * p)
-	if index >= len(sorted) {
-		index = len(sorted) - 1
-	}
-
-	return sorted[index]
-}
-
-func sortFloat64(arr []float64) {
-	// Simple insertion sort for small datasets
-	for i := 1; i < len(arr); i++ {
-		key := arr[i]
-		j := i - 1
-		for j >= 0 && arr[j] > key {
-			arr[j+1] = arr[j]
-			j--
-		}
-		arr[j+1] = key
+		return strconv.FormatFloat(value, 'f', -1, 64) + "ms"
 	}
+	return strconv.FormatFloat(value/1000, 'f', -1, 64) + "s"
 }
 
 func copyMap(src map[string]int64) map[string]int64 {
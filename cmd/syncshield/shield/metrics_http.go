@@ -0,0 +1,158 @@
+package shield
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ServeHTTP renders mc's current state in the Prometheus text exposition
+// format (https://prometheus.io/docs/instrumenting/exposition_formats/),
+// suitable for mounting directly on a ServeMux - see Handler. Unlike
+// PrometheusExporter, which owns the global mux and bundles in RTB/push
+// support, ServeHTTP is just this one collector, so a process with several
+// CircuitBreakers can register each collector (ideally built with
+// NewNamedMetricsCollector) under its own path, or combine them behind one
+// endpoint with a MetricsRegistry.
+func (mc *MetricsCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	mc.writeTo(&responseWriterBuilder{w: w})
+}
+
+// Handler adapts mc to an http.Handler, for callers that want an
+// http.Handler value (e.g. to pass to mux.Handle) rather than ServeHTTP's
+// method value.
+func Handler(mc *MetricsCollector) http.Handler {
+	return http.HandlerFunc(mc.ServeHTTP)
+}
+
+// metricsBuilder is the minimal strings.Builder-like surface writeTo needs,
+// so the same rendering code can build an in-memory string (MetricsRegistry)
+// or stream straight to an http.ResponseWriter (ServeHTTP).
+type metricsBuilder interface {
+	WriteString(s string) (int, error)
+}
+
+// responseWriterBuilder adapts an http.ResponseWriter to metricsBuilder.
+type responseWriterBuilder struct {
+	w http.ResponseWriter
+}
+
+func (b *responseWriterBuilder) WriteString(s string) (int, error) {
+	return b.w.Write([]byte(s))
+}
+
+// labels renders a Prometheus label set from pairs (alternating key, value),
+// prefixed with connector="name" when mc was built with NewNamedMetricsCollector.
+// Returns "" (no braces at all) when there would be no labels.
+func (mc *MetricsCollector) labels(pairs ...string) string {
+	var parts []string
+	if mc.name != "" {
+		parts = append(parts, fmt.Sprintf(`connector="%s"`, mc.name))
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pairs[i], pairs[i+1]))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	result := parts[0]
+	for _, p := range parts[1:] {
+		result += "," + p
+	}
+	return "{" + result + "}"
+}
+
+// writeTo renders the full text-format payload for mc into b.
+func (mc *MetricsCollector) writeTo(b metricsBuilder) {
+	summary := mc.GetMetricsSummary()
+
+	b.WriteString("# HELP syncshield_requests_total Requests handled by this circuit breaker, by result\n")
+	b.WriteString("# TYPE syncshield_requests_total counter\n")
+	b.WriteString(fmt.Sprintf("syncshield_requests_total%s %d\n", mc.labels("result", "success"), summary.SuccessfulRequests))
+	b.WriteString(fmt.Sprintf("syncshield_requests_total%s %d\n", mc.labels("result", "failure"), summary.FailedRequests))
+	b.WriteString(fmt.Sprintf("syncshield_requests_total%s %d\n", mc.labels("result", "fallback"), summary.FallbackRequests))
+	b.WriteString(fmt.Sprintf("syncshield_requests_total%s %d\n", mc.labels("result", "cancelled"), summary.CancelledRequests))
+
+	b.WriteString("\n# HELP syncshield_breaker_state Current circuit breaker state (1 = current, 0 = not current)\n")
+	b.WriteString("# TYPE syncshield_breaker_state gauge\n")
+	for _, state := range []CircuitBreakerState{CLOSED, OPEN, HALF_OPEN} {
+		value := 0
+		if state == summary.CurrentBreakerState {
+			value = 1
+		}
+		b.WriteString(fmt.Sprintf("syncshield_breaker_state%s %d\n", mc.labels("state", breakerStateLabel(state)), value))
+	}
+
+	b.WriteString("\n# HELP syncshield_state_transitions_total Total circuit breaker state transitions\n")
+	b.WriteString("# TYPE syncshield_state_transitions_total counter\n")
+	b.WriteString(fmt.Sprintf("syncshield_state_transitions_total%s %d\n", mc.labels(), summary.StateTransitions))
+
+	b.WriteString("\n# HELP syncshield_request_latency_ms Request latency in milliseconds\n")
+	b.WriteString("# TYPE syncshield_request_latency_ms histogram\n")
+	for _, bucket := range latencyHistogramBuckets {
+		bucketLabel := formatBucket(bucket)
+		b.WriteString(fmt.Sprintf("syncshield_request_latency_ms_bucket%s %d\n", mc.labels("le", bucketLabel), summary.LatencyBuckets[bucketLabel]))
+	}
+	b.WriteString(fmt.Sprintf("syncshield_request_latency_ms_bucket%s %d\n", mc.labels("le", "+Inf"), summary.LatencyBuckets["+Inf"]))
+	b.WriteString(fmt.Sprintf("syncshield_request_latency_ms_sum%s %f\n", mc.labels(), summary.LatencySumMs))
+	b.WriteString(fmt.Sprintf("syncshield_request_latency_ms_count%s %d\n", mc.labels(), summary.LatencyCount))
+
+	if len(summary.ErrorsByType) > 0 {
+		b.WriteString("\n# HELP syncshield_errors_total Failed requests by error type\n")
+		b.WriteString("# TYPE syncshield_errors_total counter\n")
+		for errType, count := range summary.ErrorsByType {
+			b.WriteString(fmt.Sprintf("syncshield_errors_total%s %d\n", mc.labels("type", errType), count))
+		}
+	}
+}
+
+// breakerStateLabel renders state the way Prometheus label values
+// conventionally look: lowercase, underscore-separated.
+func breakerStateLabel(state CircuitBreakerState) string {
+	switch state {
+	case CLOSED:
+		return "closed"
+	case OPEN:
+		return "open"
+	case HALF_OPEN:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// MetricsRegistry combines several MetricsCollectors - typically one per
+// connector, built with NewNamedMetricsCollector - behind a single
+// /metrics endpoint, so a multi-connector process doesn't need a separate
+// scrape target per connector.
+type MetricsRegistry struct {
+	collectors []*MetricsCollector
+}
+
+// NewMetricsRegistry creates an empty registry.
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{}
+}
+
+// Register adds mc to the set rendered by ServeHTTP. Safe to call from one
+// goroutine during setup; Register is not safe to call concurrently with
+// ServeHTTP.
+func (reg *MetricsRegistry) Register(mc *MetricsCollector) {
+	reg.collectors = append(reg.collectors, mc)
+}
+
+// ServeHTTP renders every registered collector's metrics, one after
+// another, as a single Prometheus text-format payload.
+func (reg *MetricsRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+
+	b := &responseWriterBuilder{w: w}
+	for i, mc := range reg.collectors {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		mc.writeTo(b)
+	}
+}
@@ -0,0 +1,97 @@
+package shield
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatBucketRendersPlainNumbers(t *testing.T) {
+	if got := formatBucket(10); got != "10ms" {
+		t.Errorf("expected 10ms, got %q", got)
+	}
+	if got := formatBucket(2500); got != "2.5s" {
+		t.Errorf("expected 2.5s, got %q", got)
+	}
+}
+
+func TestHistogramBucketsAreCumulative(t *testing.T) {
+	collector := NewMetricsCollector()
+	collector.RecordSuccess(60 * time.Millisecond)
+
+	summary := collector.GetMetricsSummary()
+
+	// A 60ms sample falls under every bucket boundary >= 60 (100, 250, ...,
+	// +Inf), not just the first one it's <= to.
+	if summary.LatencyBuckets[formatBucket(100)] != 1 {
+		t.Errorf("expected the 100ms bucket to include a 60ms sample, got %d", summary.LatencyBuckets[formatBucket(100)])
+	}
+	if summary.LatencyBuckets[formatBucket(10000)] != 1 {
+		t.Errorf("expected the 10000ms bucket to include a 60ms sample, got %d", summary.LatencyBuckets[formatBucket(10000)])
+	}
+	if summary.LatencyBuckets["+Inf"] != 1 {
+		t.Errorf("expected +Inf bucket to count every sample, got %d", summary.LatencyBuckets["+Inf"])
+	}
+	// And it must not land in a bucket below its own value.
+	if summary.LatencyBuckets[formatBucket(50)] != 0 {
+		t.Errorf("expected the 50ms bucket to exclude a 60ms sample, got %d", summary.LatencyBuckets[formatBucket(50)])
+	}
+}
+
+func TestMetricsCollectorServeHTTP(t *testing.T) {
+	collector := NewMetricsCollector()
+	collector.RecordRequest()
+	collector.RecordSuccess(50 * time.Millisecond)
+	collector.RecordStateTransition(CLOSED, OPEN)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	Handler(collector).ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain; version=0.0.4" {
+		t.Errorf("unexpected Content-Type: %q", ct)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`syncshield_requests_total{result="success"} 1`,
+		`syncshield_breaker_state{state="open"} 1`,
+		`syncshield_breaker_state{state="closed"} 0`,
+		`syncshield_state_transitions_total 1`,
+		`syncshield_request_latency_ms_bucket{le="+Inf"} 1`,
+		`syncshield_request_latency_ms_sum`,
+		`syncshield_request_latency_ms_count 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected body to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsRegistryLabelsEachConnector(t *testing.T) {
+	reg := NewMetricsRegistry()
+
+	hubspot := NewNamedMetricsCollector("hubspot")
+	hubspot.RecordRequest()
+	hubspot.RecordSuccess(10 * time.Millisecond)
+	reg.Register(hubspot)
+
+	salesforce := NewNamedMetricsCollector("salesforce")
+	salesforce.RecordRequest()
+	salesforce.RecordFailure(10*time.Millisecond, "timeout")
+	reg.Register(salesforce)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `syncshield_requests_total{connector="hubspot",result="success"} 1`) {
+		t.Errorf("expected hubspot's success counter, got:\n%s", body)
+	}
+	if !strings.Contains(body, `syncshield_requests_total{connector="salesforce",result="failure"} 1`) {
+		t.Errorf("expected salesforce's failure counter, got:\n%s", body)
+	}
+}
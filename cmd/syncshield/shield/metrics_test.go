@@ -46,31 +46,34 @@ func TestMetricsCollectorBasicOperations(t *testing.T) {
 func TestMetricsCollectorLatencyPercentiles(t *testing.T) {
 	collector := NewMetricsCollector()
 
-	// Record latencies: 10, 50, 100, 150, 200ms
-	latencies := []time.Duration{10, 50, 100, 150, 200}
-	for _, latency := range latencies {
-		collector.RecordSuccess(latency * time.Millisecond)
+	// A uniform 1..1000ms spread gives the t-digest enough samples to
+	// resolve percentiles accurately - a handful of raw samples (as this
+	// test used before the digest replaced the sample buffer) isn't enough
+	// for a t-digest to do more than merge everything into one centroid.
+	for i := 1; i <= 1000; i++ {
+		collector.RecordSuccess(time.Duration(i) * time.Millisecond)
 	}
 
 	percentiles := collector.GetLatencyPercentiles()
 
-	// p50 should be around 100ms
-	if percentiles["p50"] < 90 || percentiles["p50"] > 110 {
-		t.Errorf("Expected p50 around 100ms, got %.2f", percentiles["p50"])
+	if percentiles["p50"] < 480 || percentiles["p50"] > 520 {
+		t.Errorf("Expected p50 around 500ms, got %.2f", percentiles["p50"])
 	}
 
-	// p99 should be around 200ms
-	if percentiles["p99"] < 190 || percentiles["p99"] > 210 {
-		t.Errorf("Expected p99 around 200ms, got %.2f", percentiles["p99"])
+	if percentiles["p99"] < 970 || percentiles["p99"] > 1000 {
+		t.Errorf("Expected p99 around 990ms, got %.2f", percentiles["p99"])
 	}
 }
 
 func TestMetricsCollectorStateTransitions(t *testing.T) {
 	collector := NewMetricsCollector()
 
-	// Record state transitions
+	// Record state transitions, each separated by a small sleep so the
+	// outgoing state accrues measurable duration rather than racing to 0.
 	collector.RecordStateTransition(CLOSED, OPEN)
+	time.Sleep(10 * time.Millisecond)
 	collector.RecordStateTransition(OPEN, HALF_OPEN)
+	time.Sleep(10 * time.Millisecond)
 	collector.RecordStateTransition(HALF_OPEN, CLOSED)
 
 	summary := collector.GetMetricsSummary()
@@ -79,17 +82,59 @@ func TestMetricsCollectorStateTransitions(t *testing.T) {
 		t.Errorf("Expected 3 state transitions, got %d", summary.StateTransitions)
 	}
 
-	// Check state counters (each transition increments the "to" state)
-	if summary.BreakerStateOpen != 1 {
-		t.Errorf("Expected 1 OPEN state, got %d", summary.BreakerStateOpen)
+	// BreakerStateOpen/HalfOpen now hold cumulative time spent in each
+	// state (closed out by the transition away from it), not event counts.
+	if summary.BreakerStateOpen < 10*time.Millisecond {
+		t.Errorf("Expected at least 10ms of OPEN duration, got %s", summary.BreakerStateOpen)
 	}
+	if summary.BreakerStateHalfOpen < 10*time.Millisecond {
+		t.Errorf("Expected at least 10ms of HALF_OPEN duration, got %s", summary.BreakerStateHalfOpen)
+	}
+
+	// CLOSED is the state we just transitioned into, so TimeInState must
+	// still report its just-started, ongoing visit.
+	if summary.CurrentBreakerState != CLOSED {
+		t.Errorf("Expected current state CLOSED, got %s", summary.CurrentBreakerState)
+	}
+	if collector.TimeInState(CLOSED) < 0 {
+		t.Errorf("Expected a non-negative CLOSED duration, got %s", collector.TimeInState(CLOSED))
+	}
+}
+
+func TestMetricsCollectorStateFractionSumsToOne(t *testing.T) {
+	collector := NewMetricsCollector()
+	collector.RecordStateTransition(CLOSED, OPEN)
+	time.Sleep(5 * time.Millisecond)
+
+	fractions := collector.StateFraction()
 
-	if summary.BreakerStateHalfOpen != 1 {
-		t.Errorf("Expected 1 HALF_OPEN state, got %d", summary.BreakerStateHalfOpen)
+	var total float64
+	for _, f := range fractions {
+		total += f
 	}
+	if total < 0.99 || total > 1.01 {
+		t.Errorf("Expected state fractions to sum to ~1, got %v (sum %.4f)", fractions, total)
+	}
+	if fractions["open"] <= 0 {
+		t.Errorf("Expected a positive OPEN fraction, got %v", fractions)
+	}
+}
+
+func TestMetricsCollectorErrorRateEWMADecaysTowardRecentObservations(t *testing.T) {
+	collector := NewMetricsCollector()
+	collector.SetErrorRateHalfLife(20 * time.Millisecond)
+
+	collector.RecordFailure(10*time.Millisecond, "timeout")
+	if rate := collector.ErrorRateEWMA(); rate != 1 {
+		t.Errorf("Expected the first observation to set EWMA to 1, got %.4f", rate)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	collector.RecordSuccess(10 * time.Millisecond)
 
-	if summary.BreakerStateClosed != 1 {
-		t.Errorf("Expected 1 CLOSED state, got %d", summary.BreakerStateClosed)
+	rate := collector.ErrorRateEWMA()
+	if rate <= 0 || rate >= 1 {
+		t.Errorf("Expected EWMA to have decayed toward the success but stay in (0,1), got %.4f", rate)
 	}
 }
 
@@ -140,8 +185,8 @@ func TestCircuitBreakerMetricsStateTransitions(t *testing.T) {
 		t.Errorf("Expected 1 state transition (CLOSED → OPEN), got %d", summary.StateTransitions)
 	}
 
-	if summary.BreakerStateOpen != 1 {
-		t.Errorf("Expected 1 OPEN state count, got %d", summary.BreakerStateOpen)
+	if summary.CurrentBreakerState != OPEN {
+		t.Errorf("Expected current state OPEN, got %s", summary.CurrentBreakerState)
 	}
 }
 
@@ -0,0 +1,190 @@
+package shield
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultHealthCheckInterval is how often MultiEndpointClient's background
+// goroutine pings endpoints whose breaker is OPEN, per Start.
+const defaultHealthCheckInterval = 10 * time.Second
+
+// EndpointHealthCheck pings a single endpoint out-of-band of real traffic,
+// so a background goroutine can recover an OPEN endpoint's breaker without
+// waiting for a probe call to be routed to it. Returning nil counts as a
+// success; any error counts as a failure, same as a failed grpcCall.
+type EndpointHealthCheck func(ctx context.Context, endpoint string) error
+
+// MultiEndpointClient rotates through a set of gRPC target addresses the
+// way etcd's httpClusterClient.Do rotates through cluster members: a
+// network or 5xx-class failure on one endpoint moves on to the next rather
+// than failing the call outright, while context cancellation or deadline
+// errors are returned to the caller immediately since trying another
+// endpoint can't fix those. Each endpoint gets its own CircuitBreaker,
+// keyed by address, so one endpoint's outage is never held against the
+// others, and CanExecute skips any endpoint whose breaker is OPEN.
+//
+// grpcCall keeps CallWithCircuitBreaker's (result, latency, error) shape,
+// just with the endpoint it should dial threaded through as a second
+// argument - a caller migrating off a single CircuitBreaker only has to
+// add that argument to an existing dial closure and swap the constructor.
+type MultiEndpointClient struct {
+	mu        sync.Mutex
+	endpoints []string
+	breakers  map[string]*CircuitBreaker
+
+	healthCheck         EndpointHealthCheck
+	healthCheckInterval time.Duration
+	stopCh              chan struct{}
+}
+
+// NewMultiEndpointClient builds a client over endpoints, each guarded by
+// its own shield.NewCircuitBreaker(). healthCheck may be nil, in which
+// case Start's background goroutine is a no-op and OPEN endpoints only
+// recover the normal CanExecute way, via resetTimeout plus a probe call.
+func NewMultiEndpointClient(endpoints []string, healthCheck EndpointHealthCheck) *MultiEndpointClient {
+	breakers := make(map[string]*CircuitBreaker, len(endpoints))
+	for _, endpoint := range endpoints {
+		breakers[endpoint] = NewCircuitBreaker()
+	}
+	return &MultiEndpointClient{
+		endpoints:           append([]string(nil), endpoints...),
+		breakers:            breakers,
+		healthCheck:         healthCheck,
+		healthCheckInterval: defaultHealthCheckInterval,
+		stopCh:              make(chan struct{}),
+	}
+}
+
+// Start launches the background health-check goroutine, mirroring
+// coordinator.LeaderElector's Start(ctx)/ticker-driven run loop. Safe to
+// call even with a nil healthCheck. Stop ends it.
+func (m *MultiEndpointClient) Start(ctx context.Context) {
+	go m.run(ctx)
+}
+
+// Stop ends the background health-check goroutine started by Start.
+func (m *MultiEndpointClient) Stop() {
+	close(m.stopCh)
+}
+
+func (m *MultiEndpointClient) run(ctx context.Context) {
+	ticker := time.NewTicker(m.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.pingOpenEndpoints(ctx)
+		}
+	}
+}
+
+// pingOpenEndpoints calls healthCheck against every endpoint whose breaker
+// is currently OPEN and eligible for a probe (resetTimeout elapsed),
+// recording the outcome the same way a real call would. A successful ping
+// advances an OPEN breaker to HALF_OPEN so idle endpoints recover without
+// needing live traffic routed to them first.
+func (m *MultiEndpointClient) pingOpenEndpoints(ctx context.Context) {
+	if m.healthCheck == nil {
+		return
+	}
+	for _, endpoint := range m.snapshotEndpoints() {
+		breaker := m.breakerFor(endpoint)
+		if breaker.GetState() != OPEN || !breaker.CanExecute() {
+			continue
+		}
+
+		start := time.Now()
+		err := m.healthCheck(ctx, endpoint)
+		latency := time.Since(start)
+		if err != nil {
+			breaker.RecordFailure(latency)
+			continue
+		}
+		breaker.RecordSuccess(latency)
+	}
+}
+
+// CallWithCircuitBreaker tries endpoints in order, skipping any whose
+// breaker reports CanExecute() == false, until one succeeds or every
+// endpoint has been tried. A context.Canceled or context.DeadlineExceeded
+// error is returned to the caller immediately rather than triggering
+// failover to the next endpoint - another endpoint can't satisfy a
+// deadline the caller has already given up on. fallbackCall runs at most
+// once, after every endpoint has failed or was skipped as OPEN.
+func (m *MultiEndpointClient) CallWithCircuitBreaker(
+	ctx context.Context,
+	grpcCall func(ctx context.Context, endpoint string) (interface{}, time.Duration, error),
+	fallbackCall func(context.Context) (interface{}, error),
+) (interface{}, string, error) {
+	endpoints := m.snapshotEndpoints()
+
+	var lastErr error
+	triedAny := false
+	for _, endpoint := range endpoints {
+		breaker := m.breakerFor(endpoint)
+		if !breaker.CanExecute() {
+			continue
+		}
+		triedAny = true
+
+		result, latency, err := grpcCall(ctx, endpoint)
+		if isNonFailoverable(err) {
+			breaker.RecordCancellation()
+			return result, "cancelled", err
+		}
+		if err != nil || breaker.isSlowFailure(latency) {
+			breaker.RecordFailure(latency)
+			lastErr = err
+			continue
+		}
+
+		breaker.RecordSuccess(latency)
+		return result, fmt.Sprintf("grpc_success:%s", endpoint), nil
+	}
+
+	source := "fallback_after_all_endpoints_failed"
+	if !triedAny {
+		source = "fallback_all_circuits_open"
+	}
+	res, err := fallbackCall(ctx)
+	if err != nil && lastErr != nil {
+		err = fmt.Errorf("%w (last endpoint error: %v)", err, lastErr)
+	}
+	return res, source, err
+}
+
+// isNonFailoverable reports whether err is a context cancellation or
+// deadline, the two cases CallWithCircuitBreaker returns to the caller
+// immediately instead of trying the next endpoint.
+func isNonFailoverable(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// breakerFor returns endpoint's CircuitBreaker, creating one with default
+// thresholds if endpoint was not part of the set passed to
+// NewMultiEndpointClient.
+func (m *MultiEndpointClient) breakerFor(endpoint string) *CircuitBreaker {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cb, ok := m.breakers[endpoint]
+	if !ok {
+		cb = NewCircuitBreaker()
+		m.breakers[endpoint] = cb
+	}
+	return cb
+}
+
+func (m *MultiEndpointClient) snapshotEndpoints() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.endpoints...)
+}
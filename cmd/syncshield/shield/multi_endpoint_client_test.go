@@ -0,0 +1,175 @@
+package shield
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMultiEndpointClient_FailsOverToNextEndpointOnFailure(t *testing.T) {
+	mc := NewMultiEndpointClient([]string{"endpoint-a", "endpoint-b"}, nil)
+
+	grpcCall := func(ctx context.Context, endpoint string) (interface{}, time.Duration, error) {
+		if endpoint == "endpoint-a" {
+			return nil, 0, fmt.Errorf("connection refused")
+		}
+		return "ok from " + endpoint, 10 * time.Millisecond, nil
+	}
+	fallbackCall := func(ctx context.Context) (interface{}, error) {
+		t.Fatal("fallback should not be reached when endpoint-b succeeds")
+		return nil, nil
+	}
+
+	result, source, err := mc.CallWithCircuitBreaker(context.Background(), grpcCall, fallbackCall)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "ok from endpoint-b" {
+		t.Errorf("expected failover to endpoint-b, got %v", result)
+	}
+	if source != "grpc_success:endpoint-b" {
+		t.Errorf("expected source to name the winning endpoint, got %s", source)
+	}
+}
+
+func TestMultiEndpointClient_SkipsEndpointsWithOpenCircuitBreaker(t *testing.T) {
+	mc := NewMultiEndpointClient([]string{"endpoint-a", "endpoint-b"}, nil)
+	mc.breakerFor("endpoint-a").SetThresholds(1, 2, 500*time.Millisecond, time.Hour)
+	mc.breakerFor("endpoint-a").RecordFailure(0)
+
+	var calledB bool
+	grpcCall := func(ctx context.Context, endpoint string) (interface{}, time.Duration, error) {
+		if endpoint == "endpoint-a" {
+			t.Fatal("endpoint-a's breaker is open, it should never be called")
+		}
+		calledB = true
+		return "ok", 10 * time.Millisecond, nil
+	}
+	fallbackCall := func(ctx context.Context) (interface{}, error) {
+		t.Fatal("fallback should not be reached")
+		return nil, nil
+	}
+
+	_, _, err := mc.CallWithCircuitBreaker(context.Background(), grpcCall, fallbackCall)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !calledB {
+		t.Error("expected endpoint-b to be tried")
+	}
+}
+
+func TestMultiEndpointClient_ContextCancellationShortCircuitsWithoutFailover(t *testing.T) {
+	mc := NewMultiEndpointClient([]string{"endpoint-a", "endpoint-b"}, nil)
+
+	grpcCall := func(ctx context.Context, endpoint string) (interface{}, time.Duration, error) {
+		if endpoint == "endpoint-b" {
+			t.Fatal("context cancellation should short-circuit before trying endpoint-b")
+		}
+		return nil, 0, context.Canceled
+	}
+	fallbackCall := func(ctx context.Context) (interface{}, error) {
+		t.Fatal("fallback should not be reached on context cancellation")
+		return nil, nil
+	}
+
+	_, source, err := mc.CallWithCircuitBreaker(context.Background(), grpcCall, fallbackCall)
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled to be returned as-is, got %v", err)
+	}
+	if source != "cancelled" {
+		t.Errorf("expected source %q, got %q", "cancelled", source)
+	}
+}
+
+func TestMultiEndpointClient_DeadlineExceededShortCircuitsWithoutFailover(t *testing.T) {
+	mc := NewMultiEndpointClient([]string{"endpoint-a", "endpoint-b"}, nil)
+
+	grpcCall := func(ctx context.Context, endpoint string) (interface{}, time.Duration, error) {
+		if endpoint == "endpoint-b" {
+			t.Fatal("deadline exceeded should short-circuit before trying endpoint-b")
+		}
+		return nil, 0, context.DeadlineExceeded
+	}
+	fallbackCall := func(ctx context.Context) (interface{}, error) {
+		t.Fatal("fallback should not be reached on deadline exceeded")
+		return nil, nil
+	}
+
+	_, _, err := mc.CallWithCircuitBreaker(context.Background(), grpcCall, fallbackCall)
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded to be returned as-is, got %v", err)
+	}
+}
+
+func TestMultiEndpointClient_FallbackRunsOnceWhenEveryEndpointFails(t *testing.T) {
+	mc := NewMultiEndpointClient([]string{"endpoint-a", "endpoint-b"}, nil)
+
+	grpcCall := func(ctx context.Context, endpoint string) (interface{}, time.Duration, error) {
+		return nil, 0, fmt.Errorf("%s: 503 service unavailable", endpoint)
+	}
+	fallbackCalls := 0
+	fallbackCall := func(ctx context.Context) (interface{}, error) {
+		fallbackCalls++
+		return "fallback response", nil
+	}
+
+	result, source, err := mc.CallWithCircuitBreaker(context.Background(), grpcCall, fallbackCall)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "fallback response" {
+		t.Errorf("expected fallback response, got %v", result)
+	}
+	if source != "fallback_after_all_endpoints_failed" {
+		t.Errorf("unexpected source: %s", source)
+	}
+	if fallbackCalls != 1 {
+		t.Errorf("expected fallback to run exactly once, got %d", fallbackCalls)
+	}
+}
+
+func TestMultiEndpointClient_FallsBackImmediatelyWhenEveryBreakerIsOpen(t *testing.T) {
+	mc := NewMultiEndpointClient([]string{"endpoint-a"}, nil)
+	mc.breakerFor("endpoint-a").SetThresholds(1, 2, 500*time.Millisecond, time.Hour)
+	mc.breakerFor("endpoint-a").RecordFailure(0)
+
+	grpcCall := func(ctx context.Context, endpoint string) (interface{}, time.Duration, error) {
+		t.Fatal("no endpoint should be called while its breaker is open")
+		return nil, 0, nil
+	}
+	fallbackCall := func(ctx context.Context) (interface{}, error) {
+		return "degraded", nil
+	}
+
+	result, source, err := mc.CallWithCircuitBreaker(context.Background(), grpcCall, fallbackCall)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "degraded" {
+		t.Errorf("expected degraded fallback response, got %v", result)
+	}
+	if source != "fallback_all_circuits_open" {
+		t.Errorf("unexpected source: %s", source)
+	}
+}
+
+func TestMultiEndpointClient_HealthCheckResetsAnOpenEndpoint(t *testing.T) {
+	mc := NewMultiEndpointClient([]string{"endpoint-a"}, func(ctx context.Context, endpoint string) error {
+		return nil
+	})
+	breaker := mc.breakerFor("endpoint-a")
+	breaker.SetThresholds(1, 1, 500*time.Millisecond, 10*time.Millisecond)
+	breaker.RecordFailure(0)
+	if breaker.GetState() != OPEN {
+		t.Fatalf("expected endpoint-a to be OPEN after 1 failure, got %v", breaker.GetState())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	mc.pingOpenEndpoints(context.Background())
+
+	if breaker.GetState() != CLOSED {
+		t.Errorf("expected a successful health check to close the breaker (successThreshold=1), got %v", breaker.GetState())
+	}
+}
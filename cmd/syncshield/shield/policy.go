@@ -0,0 +1,367 @@
+package shield
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Policy is a uniform resilience wrapper around a unit of work. RetryPolicy
+// and CircuitBreaker implement it alongside the policies in this file
+// (TimeoutPolicy, HedgePolicy, BulkheadPolicy, RateLimiterPolicy,
+// FallbackPolicy), so any combination can be layered with Compose instead of
+// each caller hand-wiring its own retry/timeout/breaker glue the way
+// predict.Client historically did.
+type Policy interface {
+	Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error)
+}
+
+// composed chains policies so the first wraps the second wraps the third,
+// and so on, with inner innermost.
+type composed struct {
+	policies []Policy
+}
+
+// Compose layers policies outermost-first: Compose(A, B, C).Execute(ctx, inner)
+// behaves as A.Execute(ctx, func(ctx) { return B.Execute(ctx, func(ctx) { return C.Execute(ctx, inner) }) }).
+// A failure or short-circuit from an outer policy (e.g. RetryPolicy giving
+// up) propagates out without the inner policies seeing it again; a
+// short-circuit from an inner policy (e.g. CircuitBreaker's ErrCircuitOpen)
+// is just another error to the policies wrapping it. Compose with zero
+// policies returns one that calls inner directly.
+func Compose(policies ...Policy) Policy {
+	return &composed{policies: policies}
+}
+
+func (c *composed) Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	return c.executeFrom(ctx, 0, inner)
+}
+
+func (c *composed) executeFrom(ctx context.Context, i int, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if i >= len(c.policies) {
+		return inner(ctx)
+	}
+	return c.policies[i].Execute(ctx, func(ctx context.Context) (interface{}, error) {
+		return c.executeFrom(ctx, i+1, inner)
+	})
+}
+
+// publishShortCircuit emits EventPolicyShortCircuited if bus is non-nil,
+// tagging which policy declined the call and why. Shared by BulkheadPolicy,
+// RateLimiterPolicy, and HedgePolicy, whose short-circuits don't fit
+// CircuitBreaker's or RetryPolicy's own dedicated event types.
+func publishShortCircuit(bus *EventBus, source, policy, reason string) {
+	if bus == nil {
+		return
+	}
+	bus.Publish(Event{
+		Type:   EventPolicyShortCircuited,
+		Source: source,
+		Data: map[string]interface{}{
+			"policy": policy,
+			"reason": reason,
+		},
+	})
+}
+
+// TimeoutPolicy bounds inner to Timeout by deriving a context.WithTimeout
+// around it. It relies on inner itself observing ctx cancellation (the way
+// an HTTP or gRPC client call already does) rather than forcibly abandoning
+// a goroutine that ignores it.
+type TimeoutPolicy struct {
+	Timeout time.Duration
+}
+
+func (tp TimeoutPolicy) Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, tp.Timeout)
+	defer cancel()
+	result, err := inner(ctx)
+	if err != nil && ctx.Err() != nil {
+		return result, fmt.Errorf("shield: timeout after %s: %w", tp.Timeout, ctx.Err())
+	}
+	return result, err
+}
+
+// HedgePolicy generalizes predict.Client's single-hedge hedgedAttempt into a
+// Policy: it launches inner immediately, and - while any result is still
+// outstanding - races a per-round Delay timer against incoming results,
+// launching one more attempt each time the timer fires before a success
+// arrives, up to Attempts total. The first success wins and cancels every
+// other in-flight attempt; if every attempt fails, the last error is
+// returned.
+type HedgePolicy struct {
+	// Delay is how long to wait for a result before launching another
+	// hedged attempt.
+	Delay time.Duration
+	// Attempts is the maximum number of concurrent attempts, including the
+	// first. Must be >= 1.
+	Attempts int
+
+	mu          sync.Mutex
+	eventBus    *EventBus
+	eventSource string
+}
+
+// SetEventBus attaches bus so an exhausted hedge (every attempt failed)
+// publishes EventPolicyShortCircuited. source identifies this policy in
+// published events. A nil bus disables publishing.
+func (hp *HedgePolicy) SetEventBus(bus *EventBus, source string) {
+	hp.mu.Lock()
+	defer hp.mu.Unlock()
+	hp.eventBus = bus
+	hp.eventSource = source
+}
+
+type hedgeResult struct {
+	result interface{}
+	err    error
+}
+
+func (hp *HedgePolicy) Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	attempts := hp.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult, attempts)
+	launch := func() {
+		go func() {
+			result, err := inner(ctx)
+			results <- hedgeResult{result: result, err: err}
+		}()
+	}
+
+	launch()
+	launched := 1
+	pending := 1
+	var lastErr error
+
+	for launched < attempts {
+		timer := time.NewTimer(hp.Delay)
+		select {
+		case res := <-results:
+			timer.Stop()
+			pending--
+			if res.err == nil {
+				return res.result, nil
+			}
+			lastErr = res.err
+		case <-timer.C:
+			launch()
+			launched++
+			pending++
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	// Every attempt is launched; drain remaining results until one succeeds
+	// or all are exhausted.
+	for ; pending > 0; pending-- {
+		select {
+		case res := <-results:
+			if res.err == nil {
+				return res.result, nil
+			}
+			lastErr = res.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	hp.mu.Lock()
+	bus, source := hp.eventBus, hp.eventSource
+	hp.mu.Unlock()
+	publishShortCircuit(bus, source, "hedge", "all attempts failed")
+
+	return nil, fmt.Errorf("shield: all %d hedged attempts failed: %w", launched, lastErr)
+}
+
+// ErrBulkheadFull is returned by BulkheadPolicy.Execute when QueueTimeout
+// elapses without a concurrency slot becoming free.
+var ErrBulkheadFull = errors.New("shield: bulkhead full")
+
+// BulkheadPolicy bounds the number of concurrent inner calls to
+// MaxConcurrent, queueing up to QueueTimeout for a free slot before
+// declining with ErrBulkheadFull. Unlike RateLimiterPolicy, it caps
+// in-flight work rather than throughput over time - the same role as
+// predict.Client's hand-rolled sem channel, generalized into a Policy.
+type BulkheadPolicy struct {
+	MaxConcurrent int
+	QueueTimeout  time.Duration
+
+	mu          sync.Mutex
+	sem         chan struct{}
+	eventBus    *EventBus
+	eventSource string
+}
+
+// NewBulkheadPolicy returns a BulkheadPolicy admitting at most maxConcurrent
+// calls at a time, queueing for up to queueTimeout before declining.
+func NewBulkheadPolicy(maxConcurrent int, queueTimeout time.Duration) *BulkheadPolicy {
+	return &BulkheadPolicy{
+		MaxConcurrent: maxConcurrent,
+		QueueTimeout:  queueTimeout,
+		sem:           make(chan struct{}, maxConcurrent),
+	}
+}
+
+// SetEventBus attaches bus so a declined call publishes
+// EventPolicyShortCircuited. source identifies this policy in published
+// events. A nil bus disables publishing.
+func (bp *BulkheadPolicy) SetEventBus(bus *EventBus, source string) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.eventBus = bus
+	bp.eventSource = source
+}
+
+func (bp *BulkheadPolicy) Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, bp.QueueTimeout)
+	defer cancel()
+
+	select {
+	case bp.sem <- struct{}{}:
+	case <-waitCtx.Done():
+		bp.mu.Lock()
+		bus, source := bp.eventBus, bp.eventSource
+		bp.mu.Unlock()
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		publishShortCircuit(bus, source, "bulkhead", "queue timeout")
+		return nil, ErrBulkheadFull
+	}
+	defer func() { <-bp.sem }()
+
+	return inner(ctx)
+}
+
+// ErrRateLimited is returned by RateLimiterPolicy.Execute when the caller's
+// bucket has no tokens available.
+var ErrRateLimited = errors.New("shield: rate limited")
+
+// rateLimiterBucket is one KeyFunc key's token bucket.
+type rateLimiterBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// RateLimiterPolicy admits inner calls at up to RatePerSecond, bursting up
+// to Burst, using a token bucket per KeyFunc(ctx) key. A nil KeyFunc shares
+// a single bucket across every call, rate-limiting the policy as a whole
+// rather than per-caller.
+type RateLimiterPolicy struct {
+	RatePerSecond float64
+	Burst         float64
+	KeyFunc       func(ctx context.Context) string
+
+	mu          sync.Mutex
+	buckets     map[string]*rateLimiterBucket
+	eventBus    *EventBus
+	eventSource string
+}
+
+// NewRateLimiterPolicy returns a RateLimiterPolicy admitting up to
+// ratePerSecond calls per second, bursting up to burst.
+func NewRateLimiterPolicy(ratePerSecond, burst float64) *RateLimiterPolicy {
+	return &RateLimiterPolicy{
+		RatePerSecond: ratePerSecond,
+		Burst:         burst,
+		buckets:       make(map[string]*rateLimiterBucket),
+	}
+}
+
+// SetEventBus attaches bus so a denied call publishes
+// EventPolicyShortCircuited. source identifies this policy in published
+// events. A nil bus disables publishing.
+func (rl *RateLimiterPolicy) SetEventBus(bus *EventBus, source string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.eventBus = bus
+	rl.eventSource = source
+}
+
+func (rl *RateLimiterPolicy) Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	key := ""
+	if rl.KeyFunc != nil {
+		key = rl.KeyFunc(ctx)
+	}
+
+	rl.mu.Lock()
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = &rateLimiterBucket{tokens: rl.Burst, lastRefill: time.Now()}
+		rl.buckets[key] = bucket
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(bucket.lastRefill).Seconds()
+	bucket.tokens += elapsed * rl.RatePerSecond
+	if bucket.tokens > rl.Burst {
+		bucket.tokens = rl.Burst
+	}
+	bucket.lastRefill = now
+
+	if bucket.tokens < 1 {
+		bus, source := rl.eventBus, rl.eventSource
+		rl.mu.Unlock()
+		publishShortCircuit(bus, source, "rate_limiter", "no tokens available")
+		return nil, ErrRateLimited
+	}
+
+	bucket.tokens--
+	rl.mu.Unlock()
+
+	return inner(ctx)
+}
+
+// FallbackPolicy calls inner and, on error, calls Fallback with that error
+// instead of propagating it - the same role as predict.Client's
+// call-site-level degraded-mode heuristic, generalized into a Policy so it
+// can be composed with Retry/Timeout/CircuitBreaker instead of living
+// outside the chain.
+type FallbackPolicy struct {
+	Fallback func(ctx context.Context, err error) (interface{}, error)
+
+	mu          sync.Mutex
+	eventBus    *EventBus
+	eventSource string
+}
+
+// SetEventBus attaches bus so an engaged fallback publishes
+// EventFallbackEngaged. source identifies this policy in published events.
+// A nil bus disables publishing.
+func (fp *FallbackPolicy) SetEventBus(bus *EventBus, source string) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+	fp.eventBus = bus
+	fp.eventSource = source
+}
+
+func (fp *FallbackPolicy) Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	result, err := inner(ctx)
+	if err == nil {
+		return result, nil
+	}
+
+	fp.mu.Lock()
+	bus, source := fp.eventBus, fp.eventSource
+	fp.mu.Unlock()
+	if bus != nil {
+		bus.Publish(Event{
+			Type:   EventFallbackEngaged,
+			Source: source,
+			Data:   map[string]interface{}{"error": err.Error()},
+		})
+	}
+
+	return fp.Fallback(ctx, err)
+}
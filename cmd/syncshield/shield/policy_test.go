@@ -0,0 +1,240 @@
+package shield
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestComposeOrdersOutermostFirst verifies Compose(A, B, C) wraps A around B
+// around C around inner, by recording the order each policy observes entry.
+func TestComposeOrdersOutermostFirst(t *testing.T) {
+	var order []string
+	record := func(name string) Policy {
+		return recordingPolicy{name: name, order: &order}
+	}
+
+	chain := Compose(record("A"), record("B"), record("C"))
+	_, err := chain.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		order = append(order, "inner")
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"A", "B", "C", "inner"}
+	if len(order) != len(expected) {
+		t.Fatalf("expected order %v, got %v", expected, order)
+	}
+	for i, name := range expected {
+		if order[i] != name {
+			t.Errorf("expected order %v, got %v", expected, order)
+			break
+		}
+	}
+}
+
+type recordingPolicy struct {
+	name  string
+	order *[]string
+}
+
+func (rp recordingPolicy) Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	*rp.order = append(*rp.order, rp.name)
+	return inner(ctx)
+}
+
+// TestTimeoutPolicyWrapsSlowCall tests that an inner call exceeding Timeout
+// returns a deadline-exceeded error.
+func TestTimeoutPolicyWrapsSlowCall(t *testing.T) {
+	tp := TimeoutPolicy{Timeout: 20 * time.Millisecond}
+
+	_, err := tp.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+			return "too slow", nil
+		}
+	})
+	if err == nil {
+		t.Fatal("expected timeout error, got nil")
+	}
+}
+
+// TestTimeoutPolicyAllowsFastCall tests that a call finishing within Timeout
+// succeeds normally.
+func TestTimeoutPolicyAllowsFastCall(t *testing.T) {
+	tp := TimeoutPolicy{Timeout: 100 * time.Millisecond}
+
+	result, err := tp.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return "fast", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "fast" {
+		t.Errorf("expected \"fast\", got %v", result)
+	}
+}
+
+// TestHedgePolicyReturnsFirstSuccess tests that a hedged second attempt wins
+// when the first attempt is slower than Delay.
+func TestHedgePolicyReturnsFirstSuccess(t *testing.T) {
+	hp := &HedgePolicy{Delay: 20 * time.Millisecond, Attempts: 2}
+
+	var calls int32
+	result, err := hp.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			// First attempt: slow enough to be hedged.
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(200 * time.Millisecond):
+				return "slow", nil
+			}
+		}
+		return "fast", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "fast" {
+		t.Errorf("expected the hedged attempt to win with \"fast\", got %v", result)
+	}
+}
+
+// TestHedgePolicyExhaustedReturnsLastError tests that HedgePolicy publishes
+// a short-circuit event and returns an error once every attempt fails.
+func TestHedgePolicyExhaustedReturnsLastError(t *testing.T) {
+	hp := &HedgePolicy{Delay: 5 * time.Millisecond, Attempts: 2}
+	bus := NewEventBus()
+	hp.SetEventBus(bus, "test")
+	sub := bus.Subscribe()
+
+	_, err := hp.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("expected an error once every hedged attempt fails")
+	}
+
+	select {
+	case event := <-sub:
+		if event.Type != EventPolicyShortCircuited {
+			t.Errorf("expected EventPolicyShortCircuited, got %v", event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a short-circuit event, got none")
+	}
+}
+
+// TestBulkheadPolicyRejectsOverCapacity tests that a call queued past
+// QueueTimeout with no free slot returns ErrBulkheadFull.
+func TestBulkheadPolicyRejectsOverCapacity(t *testing.T) {
+	bp := NewBulkheadPolicy(1, 20*time.Millisecond)
+
+	release := make(chan struct{})
+	go bp.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-release
+		return nil, nil
+	})
+	time.Sleep(10 * time.Millisecond) // let the first call claim the only slot
+
+	_, err := bp.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return "should not run", nil
+	})
+	close(release)
+
+	if !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("expected ErrBulkheadFull, got %v", err)
+	}
+}
+
+// TestRateLimiterPolicyDeniesWithoutTokens tests that exhausting Burst
+// tokens denies the next call with ErrRateLimited.
+func TestRateLimiterPolicyDeniesWithoutTokens(t *testing.T) {
+	rl := NewRateLimiterPolicy(1, 1)
+	inner := func(ctx context.Context) (interface{}, error) { return "ok", nil }
+
+	if _, err := rl.Execute(context.Background(), inner); err != nil {
+		t.Fatalf("expected first call to be admitted, got %v", err)
+	}
+	if _, err := rl.Execute(context.Background(), inner); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited on the second call, got %v", err)
+	}
+}
+
+// TestFallbackPolicyEngagesOnError tests that FallbackPolicy calls Fallback
+// with inner's error instead of propagating it.
+func TestFallbackPolicyEngagesOnError(t *testing.T) {
+	innerErr := errors.New("upstream failed")
+	fp := &FallbackPolicy{
+		Fallback: func(ctx context.Context, err error) (interface{}, error) {
+			if err != innerErr {
+				t.Errorf("expected Fallback to receive inner's error, got %v", err)
+			}
+			return "degraded", nil
+		},
+	}
+
+	result, err := fp.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return nil, innerErr
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "degraded" {
+		t.Errorf("expected \"degraded\", got %v", result)
+	}
+}
+
+// TestRetryPolicyExecuteImplementsPolicy tests that RetryPolicy.Execute
+// retries transient failures using IsRetryable (or DefaultIsRetryable).
+func TestRetryPolicyExecuteImplementsPolicy(t *testing.T) {
+	rp := &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, BackoffMultiplier: 1}
+
+	var attempts int
+	result, err := rp.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		attempts++
+		if attempts < 2 {
+			return nil, errors.New("connection refused")
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "ok" || attempts != 2 {
+		t.Errorf("expected success on attempt 2, got result=%v attempts=%d", result, attempts)
+	}
+}
+
+// TestCircuitBreakerExecuteShortCircuitsWhenOpen tests that
+// CircuitBreaker.Execute returns ErrCircuitOpen without calling inner once
+// the breaker is OPEN.
+func TestCircuitBreakerExecuteShortCircuitsWhenOpen(t *testing.T) {
+	cb := NewCircuitBreaker()
+	for i := 0; i < 3; i++ {
+		cb.RecordFailure(0)
+	}
+	if cb.GetState() != OPEN {
+		t.Fatalf("expected circuit to be OPEN, got %v", cb.GetState())
+	}
+
+	called := false
+	_, err := cb.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		called = true
+		return "should not run", nil
+	})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if called {
+		t.Error("expected inner not to be called while the circuit is OPEN")
+	}
+}
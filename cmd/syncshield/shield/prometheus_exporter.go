@@ -1,106 +1,292 @@
-package shield
-
-import (
-	"fmt"
-	"net/http"
-	"strings"
-	"time"
-)
-
-// PrometheusExporter exposes circuit breaker metrics in Prometheus format
-// Serves metrics at /metrics endpoint for scraping by Prometheus server
-type PrometheusExporter struct {
-	collector *MetricsCollector
-	port      int
-}
-
-// NewPrometheusExporter creates a new exporter for the given metrics collector
-func NewPrometheusExporter(collector *MetricsCollector, port int) *PrometheusExporter {
-	return &PrometheusExporter{
-		collector: collector,
-		port:      port,
-	}
-}
-
-// Start begins serving metrics on the configured port
-func (pe *PrometheusExporter) Start() error {
-	http.HandleFunc("/metrics", pe.metricsHandler)
-	http.HandleFunc("/health", pe.healthHandler)
-
-	addr := fmt.Sprintf(":%d", pe.port)
-	return http.ListenAndServe(addr, nil)
-}
-
-// metricsHandler serves Prometheus-formatted metrics
-func (pe *PrometheusExporter) metricsHandler(w http.ResponseWriter, r *http.Request) {
-	summary := pe.collector.GetMetricsSummary()
-
-	var builder strings.Builder
-
-	// Header comment
-	builder.WriteString("# HELP syncflow_circuit_breaker Circuit breaker resilience metrics\n")
-	builder.WriteString("# TYPE syncflow_circuit_breaker_requests_total counter\n")
-
-	// Request counters
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_requests_total{status=\"success\"} %d\n", summary.SuccessfulRequests))
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_requests_total{status=\"failure\"} %d\n", summary.FailedRequests))
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_requests_total{status=\"fallback\"} %d\n", summary.FallbackRequests))
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_requests_total{status=\"total\"} %d\n", summary.TotalRequests))
-
-	// State counters
-	builder.WriteString("\n# HELP syncflow_circuit_breaker_state_count State transition counts\n")
-	builder.WriteString("# TYPE syncflow_circuit_breaker_state_count counter\n")
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_state_count{state=\"closed\"} %d\n", summary.BreakerStateClosed))
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_state_count{state=\"open\"} %d\n", summary.BreakerStateOpen))
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_state_count{state=\"half_open\"} %d\n", summary.BreakerStateHalfOpen))
-
-	// State transitions
-	builder.WriteString("\n# HELP syncflow_circuit_breaker_transitions_total Total state transitions\n")
-	builder.WriteString("# TYPE syncflow_circuit_breaker_transitions_total counter\n")
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_transitions_total %d\n", summary.StateTransitions))
-
-	// Latency percentiles (as gauges)
-	builder.WriteString("\n# HELP syncflow_circuit_breaker_latency_ms Latency percentiles in milliseconds\n")
-	builder.WriteString("# TYPE syncflow_circuit_breaker_latency_ms gauge\n")
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_ms{quantile=\"0.5\"} %.2f\n", summary.LatencyP50))
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_ms{quantile=\"0.75\"} %.2f\n", summary.LatencyP75))
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_ms{quantile=\"0.90\"} %.2f\n", summary.LatencyP90))
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_ms{quantile=\"0.95\"} %.2f\n", summary.LatencyP95))
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_ms{quantile=\"0.99\"} %.2f\n", summary.LatencyP99))
-
-	// Latency histogram buckets
-	builder.WriteString("\n# HELP syncflow_circuit_breaker_latency_bucket Latency distribution buckets\n")
-	builder.WriteString("# TYPE syncflow_circuit_breaker_latency_bucket histogram\n")
-	for bucket, count := range summary.LatencyBuckets {
-		builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_bucket{le=\"%s\"} %d\n", bucket, count))
-	}
-
-	// Error counters by type
-	if len(summary.ErrorsByType) > 0 {
-		builder.WriteString("\n# HELP syncflow_circuit_breaker_errors_total Errors by type\n")
-		builder.WriteString("# TYPE syncflow_circuit_breaker_errors_total counter\n")
-		for errorType, count := range summary.ErrorsByType {
-			builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_errors_total{type=\"%s\"} %d\n", errorType, count))
-		}
-	}
-
-	// Last state change timestamp
-	builder.WriteString("\n# HELP syncflow_circuit_breaker_last_state_change_timestamp_seconds Last state change timestamp\n")
-	builder.WriteString("# TYPE syncflow_circuit_breaker_last_state_change_timestamp_seconds gauge\n")
-	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_last_state_change_timestamp_seconds %d\n", summary.LastStateChange.Unix()))
-
-	// Write response
-	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte(builder.String()))
-}
-
-// healthHandler provides a simple health check endpoint
-func (pe *PrometheusExporter) healthHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte(fmt.Sprintf(
-		`{"status":"healthy","timestamp":"%s"}`,
-		time.Now().Format(time.RFC3339),
-	)))
-}
+package shield
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrometheusExporter exposes circuit breaker metrics in Prometheus format
+// Serves metrics at /metrics endpoint for scraping by Prometheus server
+type PrometheusExporter struct {
+	collector *MetricsCollector
+	port      int
+
+	rtb *RTBMetricsCollector // optional: nil until EnableRTBMetrics is called
+
+	// PushAuthUser/PushAuthPassword, when set, add HTTP basic auth to every
+	// PushGateway request - optional because not every Pushgateway
+	// deployment requires it.
+	PushAuthUser     string
+	PushAuthPassword string
+
+	pushClient *http.Client
+}
+
+// NewPrometheusExporter creates a new exporter for the given metrics collector
+func NewPrometheusExporter(collector *MetricsCollector, port int) *PrometheusExporter {
+	return &PrometheusExporter{
+		collector:  collector,
+		port:       port,
+		pushClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// EnableRTBMetrics activates OpenRTB auction-domain metrics collection and
+// wires the nurl/burl/lurl callback endpoints into this exporter's mux.
+func (pe *PrometheusExporter) EnableRTBMetrics() *RTBMetricsCollector {
+	if pe.rtb == nil {
+		pe.rtb = NewRTBMetricsCollector()
+	}
+	return pe.rtb
+}
+
+// GetRTBMetricsCollector returns the RTB metrics collector (nil if disabled).
+func (pe *PrometheusExporter) GetRTBMetricsCollector() *RTBMetricsCollector {
+	return pe.rtb
+}
+
+// Start begins serving metrics on the configured port
+func (pe *PrometheusExporter) Start() error {
+	http.HandleFunc("/metrics", pe.metricsHandler)
+	http.HandleFunc("/health", pe.healthHandler)
+
+	// Exchange win/loss/billing callbacks - paths match the nurl/burl/lurl
+	// values TradeDeskSmartConnector.PlaceBid embeds in every OpenRTB bid.
+	http.HandleFunc("/win-notice", pe.winNoticeHandler)
+	http.HandleFunc("/billing", pe.billingNoticeHandler)
+	http.HandleFunc("/loss-notice", pe.lossNoticeHandler)
+
+	addr := fmt.Sprintf(":%d", pe.port)
+	return http.ListenAndServe(addr, nil)
+}
+
+// winNoticeHandler records a win notice (nurl callback) from the exchange.
+func (pe *PrometheusExporter) winNoticeHandler(w http.ResponseWriter, r *http.Request) {
+	if pe.rtb != nil {
+		pe.rtb.RecordWinNotice()
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// billingNoticeHandler records a billing notice (burl callback).
+func (pe *PrometheusExporter) billingNoticeHandler(w http.ResponseWriter, r *http.Request) {
+	if pe.rtb != nil {
+		pe.rtb.RecordBillingNotice()
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// lossNoticeHandler records a loss notice (lurl callback), tagged with the
+// exchange's "reason" query parameter if present.
+func (pe *PrometheusExporter) lossNoticeHandler(w http.ResponseWriter, r *http.Request) {
+	if pe.rtb != nil {
+		reason := r.URL.Query().Get("reason")
+		if reason == "" {
+			reason = "unknown"
+		}
+		pe.rtb.RecordLossNotice(reason)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// metricsHandler serves Prometheus-formatted metrics
+func (pe *PrometheusExporter) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(pe.renderMetrics()))
+}
+
+// renderMetrics builds the full text-format payload - the circuit-breaker
+// metrics plus, if enabled, the RTB auction metrics - shared by
+// metricsHandler and PushGateway so both always report the same numbers.
+func (pe *PrometheusExporter) renderMetrics() string {
+	summary := pe.collector.GetMetricsSummary()
+
+	var builder strings.Builder
+
+	// Header comment
+	builder.WriteString("# HELP syncflow_circuit_breaker Circuit breaker resilience metrics\n")
+	builder.WriteString("# TYPE syncflow_circuit_breaker_requests_total counter\n")
+
+	// Request counters
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_requests_total{status=\"success\"} %d\n", summary.SuccessfulRequests))
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_requests_total{status=\"failure\"} %d\n", summary.FailedRequests))
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_requests_total{status=\"fallback\"} %d\n", summary.FallbackRequests))
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_requests_total{status=\"total\"} %d\n", summary.TotalRequests))
+
+	// Cumulative time spent in each state, in seconds (including the
+	// current, still-ongoing visit) - not a transition count.
+	builder.WriteString("\n# HELP syncflow_circuit_breaker_state_seconds_total Cumulative time spent in each circuit breaker state\n")
+	builder.WriteString("# TYPE syncflow_circuit_breaker_state_seconds_total counter\n")
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_state_seconds_total{state=\"closed\"} %f\n", summary.BreakerStateClosed.Seconds()))
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_state_seconds_total{state=\"open\"} %f\n", summary.BreakerStateOpen.Seconds()))
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_state_seconds_total{state=\"half_open\"} %f\n", summary.BreakerStateHalfOpen.Seconds()))
+
+	// State transitions
+	builder.WriteString("\n# HELP syncflow_circuit_breaker_transitions_total Total state transitions\n")
+	builder.WriteString("# TYPE syncflow_circuit_breaker_transitions_total counter\n")
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_transitions_total %d\n", summary.StateTransitions))
+
+	// Latency percentiles (as gauges)
+	builder.WriteString("\n# HELP syncflow_circuit_breaker_latency_ms Latency percentiles in milliseconds\n")
+	builder.WriteString("# TYPE syncflow_circuit_breaker_latency_ms gauge\n")
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_ms{quantile=\"0.5\"} %.2f\n", summary.LatencyP50))
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_ms{quantile=\"0.75\"} %.2f\n", summary.LatencyP75))
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_ms{quantile=\"0.90\"} %.2f\n", summary.LatencyP90))
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_ms{quantile=\"0.95\"} %.2f\n", summary.LatencyP95))
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_ms{quantile=\"0.99\"} %.2f\n", summary.LatencyP99))
+
+	// Latency histogram buckets
+	builder.WriteString("\n# HELP syncflow_circuit_breaker_latency_bucket Latency distribution buckets\n")
+	builder.WriteString("# TYPE syncflow_circuit_breaker_latency_bucket histogram\n")
+	for bucket, count := range summary.LatencyBuckets {
+		builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_latency_bucket{le=\"%s\"} %d\n", bucket, count))
+	}
+
+	// Error counters by type
+	if len(summary.ErrorsByType) > 0 {
+		builder.WriteString("\n# HELP syncflow_circuit_breaker_errors_total Errors by type\n")
+		builder.WriteString("# TYPE syncflow_circuit_breaker_errors_total counter\n")
+		for errorType, count := range summary.ErrorsByType {
+			builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_errors_total{type=\"%s\"} %d\n", errorType, count))
+		}
+	}
+
+	// Last state change timestamp
+	builder.WriteString("\n# HELP syncflow_circuit_breaker_last_state_change_timestamp_seconds Last state change timestamp\n")
+	builder.WriteString("# TYPE syncflow_circuit_breaker_last_state_change_timestamp_seconds gauge\n")
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_last_state_change_timestamp_seconds %d\n", summary.LastStateChange.Unix()))
+
+	// Decayed error rate - a stabler trip signal than the raw lifetime ratio
+	builder.WriteString("\n# HELP syncflow_circuit_breaker_error_rate_ewma Time-decayed failure rate (0-1)\n")
+	builder.WriteString("# TYPE syncflow_circuit_breaker_error_rate_ewma gauge\n")
+	builder.WriteString(fmt.Sprintf("syncflow_circuit_breaker_error_rate_ewma %f\n", summary.ErrorRateEWMA))
+
+	if pe.rtb != nil {
+		pe.renderRTBMetrics(&builder)
+	}
+
+	return builder.String()
+}
+
+// renderRTBMetrics appends the OpenRTB auction-domain metrics block.
+func (pe *PrometheusExporter) renderRTBMetrics(builder *strings.Builder) {
+	rtbSummary := pe.rtb.Snapshot()
+
+	builder.WriteString("\n# HELP syncflow_rtb_bids_submitted_total Bids submitted to exchanges\n")
+	builder.WriteString("# TYPE syncflow_rtb_bids_submitted_total counter\n")
+	for key, count := range rtbSummary.BidsSubmitted {
+		builder.WriteString(fmt.Sprintf("syncflow_rtb_bids_submitted_total{platform=\"%s\",decision_source=\"%s\"} %d\n", key.platform, key.decisionSource, count))
+	}
+
+	builder.WriteString("\n# HELP syncflow_rtb_bid_price_dollars Submitted bid price in dollars\n")
+	builder.WriteString("# TYPE syncflow_rtb_bid_price_dollars histogram\n")
+	for _, bucket := range rtbBidPriceBuckets {
+		builder.WriteString(fmt.Sprintf("syncflow_rtb_bid_price_dollars_bucket{le=\"%s\"} %d\n", strconv.FormatFloat(bucket, 'f', -1, 64), rtbSummary.BidPriceBuckets[bucket]))
+	}
+	builder.WriteString(fmt.Sprintf("syncflow_rtb_bid_price_dollars_bucket{le=\"+Inf\"} %d\n", rtbSummary.BidPriceCount))
+	builder.WriteString(fmt.Sprintf("syncflow_rtb_bid_price_dollars_sum %f\n", rtbSummary.BidPriceSum))
+	builder.WriteString(fmt.Sprintf("syncflow_rtb_bid_price_dollars_count %d\n", rtbSummary.BidPriceCount))
+
+	builder.WriteString("\n# HELP syncflow_rtb_win_notices_total Win notices (nurl callbacks) received\n")
+	builder.WriteString("# TYPE syncflow_rtb_win_notices_total counter\n")
+	builder.WriteString(fmt.Sprintf("syncflow_rtb_win_notices_total %d\n", rtbSummary.WinNotices))
+
+	builder.WriteString("\n# HELP syncflow_rtb_loss_notices_total Loss notices (lurl callbacks) received\n")
+	builder.WriteString("# TYPE syncflow_rtb_loss_notices_total counter\n")
+	for reason, count := range rtbSummary.LossNotices {
+		builder.WriteString(fmt.Sprintf("syncflow_rtb_loss_notices_total{reason=\"%s\"} %d\n", reason, count))
+	}
+
+	builder.WriteString("\n# HELP syncflow_rtb_billing_notices_total Billing notices (burl callbacks) received\n")
+	builder.WriteString("# TYPE syncflow_rtb_billing_notices_total counter\n")
+	builder.WriteString(fmt.Sprintf("syncflow_rtb_billing_notices_total %d\n", rtbSummary.BillingNotices))
+
+	builder.WriteString("\n# HELP syncflow_rtb_budget_vetoes_total Bids blocked by BudgetManager\n")
+	builder.WriteString("# TYPE syncflow_rtb_budget_vetoes_total counter\n")
+	for platform, count := range rtbSummary.BudgetVetoes {
+		builder.WriteString(fmt.Sprintf("syncflow_rtb_budget_vetoes_total{platform=\"%s\"} %d\n", platform, count))
+	}
+
+	builder.WriteString("\n# HELP syncflow_rtb_fallback_bid_dollars Average HeuristicFallbackEngine bid in dollars\n")
+	builder.WriteString("# TYPE syncflow_rtb_fallback_bid_dollars gauge\n")
+	builder.WriteString(fmt.Sprintf("syncflow_rtb_fallback_bid_dollars %f\n", rtbSummary.FallbackBidAvgDollars))
+
+	builder.WriteString("\n# HELP syncflow_rtb_ltv_vs_bid_ratio Average predicted LTV per bid dollar\n")
+	builder.WriteString("# TYPE syncflow_rtb_ltv_vs_bid_ratio gauge\n")
+	builder.WriteString(fmt.Sprintf("syncflow_rtb_ltv_vs_bid_ratio %f\n", rtbSummary.LTVVsBidRatioAvg))
+}
+
+// healthHandler provides a simple health check endpoint
+func (pe *PrometheusExporter) healthHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(fmt.Sprintf(
+		`{"status":"healthy","timestamp":"%s"}`,
+		time.Now().Format(time.RFC3339),
+	)))
+}
+
+const (
+	pushGatewayMaxRetries = 3
+	pushGatewayRetryDelay = 2 * time.Second
+)
+
+// PushGateway pushes this exporter's text-format metrics to a Prometheus
+// Pushgateway at url under job every interval, for short-lived bidder
+// processes that exit before a scrape would ever reach them. Blocks the
+// calling goroutine - callers typically invoke it with `go`. Each push that
+// fails is retried up to pushGatewayMaxRetries times before being dropped,
+// so one Pushgateway outage doesn't blow through the whole interval.
+func (pe *PrometheusExporter) PushGateway(url, job string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := pe.pushOnce(url, job); err != nil {
+			log.Printf("⚠️ PushGateway: giving up on this interval after retries: %v", err)
+		}
+	}
+	return nil
+}
+
+// pushOnce sends one push, retrying up to pushGatewayMaxRetries times.
+func (pe *PrometheusExporter) pushOnce(url, job string) error {
+	endpoint := fmt.Sprintf("%s/metrics/job/%s", strings.TrimRight(url, "/"), job)
+	payload := pe.renderMetrics()
+
+	var lastErr error
+	for attempt := 0; attempt <= pushGatewayMaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(pushGatewayRetryDelay)
+		}
+
+		req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewBufferString(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+		if pe.PushAuthUser != "" {
+			req.SetBasicAuth(pe.PushAuthUser, pe.PushAuthPassword)
+		}
+
+		resp, err := pe.pushClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("pushgateway returned %d", resp.StatusCode)
+	}
+
+	return lastErr
+}
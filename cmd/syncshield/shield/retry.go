@@ -2,20 +2,142 @@ package shield
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
 	"time"
 )
 
 // RetryPolicy defines retry behavior for transient failures
 // Implements exponential backoff with jitter to prevent thundering herd
 type RetryPolicy struct {
-	MaxAttempts       int           // Maximum retry attempts (0 = no retries)
+	MaxAttempts       int           // Maximum retry attempts (0 = no retries), ignored when Forever is set
 	InitialBackoff    time.Duration // Initial backoff duration (e.g., 100ms)
 	MaxBackoff        time.Duration // Maximum backoff duration (e.g., 30s)
 	BackoffMultiplier float64       // Multiplier for exponential growth (e.g., 2.0)
 	JitterFraction    float64       // Jitter as fraction of backoff (0.0-1.0)
+
+	// RetryAfterMax caps how long ExecuteWithRetry will honor a
+	// RetryAfterError's hint, so a malicious or buggy server returning a
+	// huge Retry-After can't freeze the caller indefinitely. Zero falls
+	// back to MaxBackoff.
+	RetryAfterMax time.Duration
+
+	// MaxElapsedTime bounds the total wall-clock time ExecuteWithRetry will
+	// spend across all attempts of a single call, starting from the first
+	// attempt. Zero means never give up on elapsed time alone (the
+	// cenkalti/backoff convention) - MaxAttempts or ctx cancellation still
+	// apply unless Forever is also set.
+	MaxElapsedTime time.Duration
+
+	// Forever disables MaxAttempts, retrying until MaxElapsedTime elapses
+	// or ctx is canceled. Intended for long-lived reconnect loops that must
+	// survive a transient outage rather than give up after a fixed count.
+	Forever bool
+
+	// IsRetryable decides whether a failure from Execute's inner call
+	// should be retried. Nil defaults to DefaultIsRetryable. Unused by
+	// ExecuteWithRetry, which takes its own isRetryable argument directly.
+	IsRetryable IsRetryable
+
+	mu        sync.Mutex
+	startedAt time.Time
+
+	eventBus    *EventBus
+	eventSource string
+}
+
+// Reset clears the elapsed-time clock so a RetryPolicy instance can be
+// reused across independent outages instead of accumulating elapsed time
+// from a prior ExecuteWithRetry call.
+func (rp *RetryPolicy) Reset() {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.startedAt = time.Time{}
+}
+
+// SetEventBus attaches bus so ExecuteWithRetry publishes EventRetryExhausted
+// when it gives up on a call (max attempts or MaxElapsedTime, not a
+// permanent non-retryable error). source identifies this policy in
+// published events. A nil bus disables publishing.
+func (rp *RetryPolicy) SetEventBus(bus *EventBus, source string) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.eventBus = bus
+	rp.eventSource = source
+}
+
+// publishExhausted emits EventRetryExhausted if an EventBus is attached.
+func (rp *RetryPolicy) publishExhausted(attempts int, lastErr error) {
+	rp.mu.Lock()
+	bus, source := rp.eventBus, rp.eventSource
+	rp.mu.Unlock()
+	if bus == nil {
+		return
+	}
+	bus.Publish(Event{
+		Type:   EventRetryExhausted,
+		Source: source,
+		Data: map[string]interface{}{
+			"attempts": attempts,
+			"error":    lastErr.Error(),
+		},
+	})
+}
+
+// Execute implements Policy by running inner through ExecuteWithRetry,
+// using rp.IsRetryable (or DefaultIsRetryable if unset) to decide whether
+// each failure should be retried.
+func (rp *RetryPolicy) Execute(ctx context.Context, inner func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	isRetryable := rp.IsRetryable
+	if isRetryable == nil {
+		isRetryable = DefaultIsRetryable
+	}
+	result, _, err := rp.ExecuteWithRetry(ctx, func(ctx context.Context, attempt int) (interface{}, error) {
+		return inner(ctx)
+	}, isRetryable)
+	return result, err
+}
+
+// SetRetryableChecker sets rp.IsRetryable, the predicate Execute and Exec
+// use in place of a fixed isRetryable argument threaded through every call
+// site (ExecuteWithRetry still takes one directly, for callers that need
+// per-call rather than per-policy control). If overwrite is false, fn is
+// only applied when rp.IsRetryable is still unset, so a per-connector
+// preset (see NewLinkedInRetryPolicy et al.) can hand a caller a
+// pre-configured checker without a later call silently clobbering it.
+func (rp *RetryPolicy) SetRetryableChecker(fn IsRetryable, overwrite bool) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	if !overwrite && rp.IsRetryable != nil {
+		return
+	}
+	rp.IsRetryable = fn
+}
+
+// Exec is a Backoffer-style convenience over ExecuteWithRetry for call
+// sites that don't need attempt numbers or a typed result: it retries fn
+// until it succeeds, fn returns a non-retryable error, or attempts are
+// exhausted, using rp.IsRetryable (or DefaultIsRetryable if unset). Only
+// the latest error is returned rather than an accumulated history - PD's
+// backoff package deliberately dropped multierr accumulation for the same
+// reason: a caller deciding what to do next only ever needs to know why
+// the *last* attempt failed.
+func (rp *RetryPolicy) Exec(ctx context.Context, fn func() error) error {
+	rp.mu.Lock()
+	isRetryable := rp.IsRetryable
+	rp.mu.Unlock()
+	if isRetryable == nil {
+		isRetryable = DefaultIsRetryable
+	}
+	_, _, err := rp.ExecuteWithRetry(ctx, func(ctx context.Context, attempt int) (interface{}, error) {
+		return nil, fn()
+	}, isRetryable)
+	return err
 }
 
 // DefaultRetryPolicy returns sensible defaults for most use cases
@@ -82,10 +204,22 @@ func (rp *RetryPolicy) ExecuteWithRetry(
 	isRetryable IsRetryable,
 ) (interface{}, int, error) {
 
+	rp.mu.Lock()
+	if rp.startedAt.IsZero() {
+		rp.startedAt = time.Now()
+	}
+	startedAt := rp.startedAt
+	rp.mu.Unlock()
+
 	var lastErr error
 	attempt := 0
 
-	for attempt = 1; attempt <= rp.MaxAttempts; attempt++ {
+	for attempt = 1; rp.Forever || attempt <= rp.MaxAttempts; attempt++ {
+		if rp.MaxElapsedTime > 0 && time.Since(startedAt) > rp.MaxElapsedTime && lastErr != nil {
+			rp.publishExhausted(attempt-1, lastErr)
+			return nil, attempt - 1, fmt.Errorf("max elapsed time %s exceeded after %d attempts: %w", rp.MaxElapsedTime, attempt-1, lastErr)
+		}
+
 		// Execute function
 		result, err := fn(ctx, attempt)
 
@@ -103,12 +237,25 @@ func (rp *RetryPolicy) ExecuteWithRetry(
 		}
 
 		// Last attempt failed - no more retries
-		if attempt >= rp.MaxAttempts {
+		if !rp.Forever && attempt >= rp.MaxAttempts {
 			break
 		}
 
-		// Calculate backoff duration
+		// Calculate backoff duration, deferring to a server-supplied
+		// Retry-After hint when it asks for longer than the exponential
+		// value would.
 		backoff := rp.calculateBackoff(attempt)
+		var retryAfterErr RetryAfterError
+		if errors.As(err, &retryAfterErr) {
+			if hint := rp.capRetryAfter(retryAfterErr.RetryAfter()); hint > backoff {
+				backoff = hint
+			}
+		}
+		if rp.MaxElapsedTime > 0 {
+			if remaining := rp.MaxElapsedTime - time.Since(startedAt); remaining < backoff {
+				backoff = remaining
+			}
+		}
 
 		// Check context before sleeping
 		select {
@@ -120,6 +267,7 @@ func (rp *RetryPolicy) ExecuteWithRetry(
 	}
 
 	// All retries exhausted
+	rp.publishExhausted(attempt, lastErr)
 	return nil, attempt, fmt.Errorf("max retries exceeded (%d attempts): %w", attempt, lastErr)
 }
 
@@ -149,6 +297,66 @@ func (rp *RetryPolicy) calculateBackoff(attempt int) time.Duration {
 	return time.Duration(backoff)
 }
 
+// capRetryAfter bounds a server-supplied Retry-After hint at RetryAfterMax
+// (falling back to MaxBackoff if RetryAfterMax is unset), so a malicious or
+// buggy server can't freeze a caller indefinitely.
+func (rp *RetryPolicy) capRetryAfter(d time.Duration) time.Duration {
+	max := rp.RetryAfterMax
+	if max <= 0 {
+		max = rp.MaxBackoff
+	}
+	if max > 0 && d > max {
+		return max
+	}
+	return d
+}
+
+// RetryAfterError is implemented by errors that carry a server-supplied
+// minimum delay before the next attempt, such as an HTTP Retry-After
+// header. ExecuteWithRetry honors this over its own exponential backoff
+// whenever it asks for longer, capped via capRetryAfter.
+type RetryAfterError interface {
+	error
+	RetryAfter() time.Duration
+}
+
+// retryAfterError wraps an underlying error with a server-supplied minimum
+// retry delay, as returned by WrapRetryAfter.
+type retryAfterError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryAfterError) Error() string             { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error             { return e.err }
+func (e *retryAfterError) RetryAfter() time.Duration { return e.retryAfter }
+
+// WrapRetryAfter wraps err with the Retry-After duration carried by resp
+// (a delay in seconds, or an HTTP-date, per RFC 9110 10.2.3), so
+// ExecuteWithRetry waits at least that long before its next attempt. If
+// resp is nil, err is nil, or resp carries no usable Retry-After header,
+// err is returned unchanged. Platform connectors hitting rate-limited ad
+// APIs (Meta, Google, LinkedIn) should wrap a 429/503 response's error
+// with this before returning it to ExecuteWithRetry.
+func WrapRetryAfter(resp *http.Response, err error) error {
+	if resp == nil || err == nil {
+		return err
+	}
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return err
+	}
+	if seconds, parseErr := strconv.Atoi(header); parseErr == nil {
+		return &retryAfterError{err: err, retryAfter: time.Duration(seconds) * time.Second}
+	}
+	if when, parseErr := http.ParseTime(header); parseErr == nil {
+		if d := time.Until(when); d > 0 {
+			return &retryAfterError{err: err, retryAfter: d}
+		}
+	}
+	return err
+}
+
 // Helper function to check if string contains substring (case-insensitive)
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && containsRec(s, substr, 0))
@@ -0,0 +1,74 @@
+package shield
+
+import "time"
+
+// isRetryableWithCodes wraps DefaultIsRetryable, additionally treating any
+// of codes as a transient substring match against the error text. It
+// exists because DefaultIsRetryable's substring list is deliberately
+// generic (timeouts, connection errors, 5xx) and has no notion of a
+// specific platform's rate-limit or failover signal - those are exactly
+// what the per-connector presets below plug in.
+func isRetryableWithCodes(codes ...string) IsRetryable {
+	return func(err error) bool {
+		if DefaultIsRetryable(err) {
+			return true
+		}
+		if err == nil {
+			return false
+		}
+		errStr := err.Error()
+		for _, code := range codes {
+			if contains(errStr, code) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// NewLinkedInRetryPolicy returns a RetryPolicy tuned for the LinkedIn
+// Marketing API: a 10s MaxElapsedTime keeps a bid-placement call from
+// retrying past the point it's still useful for real-time bidding, and the
+// checker additionally treats 429 as transient, since LinkedIn rate-limits
+// writes aggressively and DefaultIsRetryable has no generic 429 handling.
+func NewLinkedInRetryPolicy() *RetryPolicy {
+	rp := DefaultRetryPolicy()
+	rp.MaxElapsedTime = 10 * time.Second
+	rp.IsRetryable = isRetryableWithCodes("429")
+	return rp
+}
+
+// NewMetaRetryPolicy returns a RetryPolicy tuned for the Meta Marketing
+// API: Meta's own rate limiter responds with error code 17 ("User request
+// limit reached") inside a 400 body rather than a 429 status, so the
+// checker treats that literal code as transient alongside the usual 5xx
+// set.
+func NewMetaRetryPolicy() *RetryPolicy {
+	rp := DefaultRetryPolicy()
+	rp.MaxElapsedTime = 10 * time.Second
+	rp.IsRetryable = isRetryableWithCodes(`"code": 17`, `"code":17`)
+	return rp
+}
+
+// NewGoogleRetryPolicy returns a RetryPolicy tuned for the Google Ads API:
+// RESOURCE_EXHAUSTED is Google's gRPC-style rate-limit signal, returned
+// alongside (not instead of) the usual 5xx set.
+func NewGoogleRetryPolicy() *RetryPolicy {
+	rp := DefaultRetryPolicy()
+	rp.MaxElapsedTime = 10 * time.Second
+	rp.IsRetryable = isRetryableWithCodes("RESOURCE_EXHAUSTED")
+	return rp
+}
+
+// NewPostgresRetryPolicy returns a RetryPolicy tuned for a Postgres
+// connection: MaxElapsedTime is longer than the ad-platform presets since
+// a reconnect loop should outlast a short failover rather than give up on
+// the same budget as a bid call, and the checker additionally treats
+// SQLSTATE 57P03 ("cannot_connect_now", returned during Postgres
+// startup/failover) and a too-many-connections refusal as transient.
+func NewPostgresRetryPolicy() *RetryPolicy {
+	rp := DefaultRetryPolicy()
+	rp.MaxElapsedTime = 60 * time.Second
+	rp.IsRetryable = isRetryableWithCodes("57P03", "too many connections")
+	return rp
+}
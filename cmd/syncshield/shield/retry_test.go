@@ -3,6 +3,9 @@ package shield
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -290,6 +293,283 @@ func TestRetryPolicyIntegrationWithCircuitBreaker(t *testing.T) {
 	t.Logf("✅ Retry prevented circuit breaker from opening: %d failures, then success", attempts-1)
 }
 
+func TestRetryPolicyForeverIgnoresMaxAttempts(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:       1, // Would normally stop after 1 attempt
+		Forever:           true,
+		InitialBackoff:    10 * time.Millisecond,
+		MaxBackoff:        10 * time.Millisecond,
+		BackoffMultiplier: 1.0,
+	}
+
+	callCount := 0
+	fn := func(ctx context.Context, attempt int) (interface{}, error) {
+		callCount++
+		if callCount < 5 {
+			return nil, errors.New("timeout")
+		}
+		return "connected", nil
+	}
+
+	result, attempts, err := policy.ExecuteWithRetry(context.Background(), fn, DefaultIsRetryable)
+	if err != nil {
+		t.Fatalf("Expected success once Forever outlasts MaxAttempts, got: %v", err)
+	}
+	if attempts != 5 || callCount != 5 {
+		t.Errorf("Expected 5 attempts past the 1-attempt MaxAttempts cap, got attempts=%d calls=%d", attempts, callCount)
+	}
+	if result != "connected" {
+		t.Errorf("Expected 'connected', got %v", result)
+	}
+}
+
+func TestRetryPolicyMaxElapsedTimeStopsRetrying(t *testing.T) {
+	policy := &RetryPolicy{
+		Forever:           true,
+		MaxElapsedTime:    150 * time.Millisecond,
+		InitialBackoff:    20 * time.Millisecond,
+		MaxBackoff:        20 * time.Millisecond,
+		BackoffMultiplier: 1.0,
+	}
+
+	callCount := 0
+	fn := func(ctx context.Context, attempt int) (interface{}, error) {
+		callCount++
+		return nil, errors.New("timeout")
+	}
+
+	start := time.Now()
+	_, _, err := policy.ExecuteWithRetry(context.Background(), fn, DefaultIsRetryable)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Expected an error once MaxElapsedTime is exceeded")
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Errorf("Expected ExecuteWithRetry to stop near MaxElapsedTime, took %v", elapsed)
+	}
+	if callCount < 2 {
+		t.Errorf("Expected at least 2 attempts before MaxElapsedTime elapsed, got %d", callCount)
+	}
+}
+
+func TestRetryPolicyResetClearsElapsedClock(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:       1,
+		MaxElapsedTime:    50 * time.Millisecond,
+		InitialBackoff:    10 * time.Millisecond,
+		MaxBackoff:        10 * time.Millisecond,
+		BackoffMultiplier: 1.0,
+	}
+
+	fn := func(ctx context.Context, attempt int) (interface{}, error) {
+		return "ok", nil
+	}
+
+	if _, _, err := policy.ExecuteWithRetry(context.Background(), fn, DefaultIsRetryable); err != nil {
+		t.Fatalf("unexpected error on first call: %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond) // Outlast MaxElapsedTime if the clock weren't reset
+
+	policy.Reset()
+	if _, _, err := policy.ExecuteWithRetry(context.Background(), fn, DefaultIsRetryable); err != nil {
+		t.Fatalf("expected Reset to give the reused policy a fresh elapsed-time clock, got: %v", err)
+	}
+}
+
+func TestRetryPolicyHonorsRetryAfterHint(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:       2,
+		InitialBackoff:    1 * time.Millisecond,
+		MaxBackoff:        1 * time.Second,
+		BackoffMultiplier: 1.0,
+	}
+
+	resp := httptest.NewRecorder()
+	resp.Header().Set("Retry-After", "1")
+	retryAfterErr := WrapRetryAfter(resp.Result(), errors.New("HTTP 429 rate limited"))
+
+	callCount := 0
+	fn := func(ctx context.Context, attempt int) (interface{}, error) {
+		callCount++
+		if callCount == 1 {
+			return nil, retryAfterErr
+		}
+		return "success", nil
+	}
+
+	start := time.Now()
+	_, attempts, err := policy.ExecuteWithRetry(context.Background(), fn, func(error) bool { return true })
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("expected success on second attempt, got: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+	if elapsed < 900*time.Millisecond {
+		t.Errorf("expected ExecuteWithRetry to wait out the 1s Retry-After hint, only waited %v", elapsed)
+	}
+}
+
+func TestRetryPolicyCapsRetryAfterAtRetryAfterMax(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:       2,
+		InitialBackoff:    1 * time.Millisecond,
+		MaxBackoff:        1 * time.Second,
+		BackoffMultiplier: 1.0,
+		RetryAfterMax:     50 * time.Millisecond,
+	}
+
+	resp := httptest.NewRecorder()
+	resp.Header().Set("Retry-After", "3600") // a buggy/malicious server asking for an hour
+	retryAfterErr := WrapRetryAfter(resp.Result(), errors.New("HTTP 429 rate limited"))
+
+	callCount := 0
+	fn := func(ctx context.Context, attempt int) (interface{}, error) {
+		callCount++
+		if callCount == 1 {
+			return nil, retryAfterErr
+		}
+		return "success", nil
+	}
+
+	start := time.Now()
+	_, _, err := policy.ExecuteWithRetry(context.Background(), fn, func(error) bool { return true })
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("expected success on second attempt, got: %v", err)
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Errorf("expected RetryAfterMax to cap the wait well under the 1h hint, took %v", elapsed)
+	}
+}
+
+func TestWrapRetryAfterReturnsErrUnchangedWithoutHeader(t *testing.T) {
+	resp := httptest.NewRecorder().Result()
+	err := errors.New("HTTP 500 internal server error")
+
+	wrapped := WrapRetryAfter(resp, err)
+	if wrapped != err {
+		t.Errorf("expected err returned unchanged when no Retry-After header is present")
+	}
+
+	var raErr RetryAfterError
+	if errors.As(wrapped, &raErr) {
+		t.Error("expected wrapped error not to implement RetryAfterError without a Retry-After header")
+	}
+}
+
+func TestWrapRetryAfterNilResponseOrError(t *testing.T) {
+	if WrapRetryAfter(nil, nil) != nil {
+		t.Error("expected WrapRetryAfter(nil, nil) to return nil")
+	}
+
+	resp := httptest.NewRecorder().Result()
+	resp.Header.Set("Retry-After", "5")
+	if WrapRetryAfter(resp, nil) != nil {
+		t.Error("expected WrapRetryAfter(resp, nil) to return nil")
+	}
+}
+
+func TestRetryPolicySetRetryableCheckerRespectsOverwrite(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	first := func(error) bool { return true }
+	second := func(error) bool { return false }
+
+	policy.SetRetryableChecker(first, false)
+	policy.SetRetryableChecker(second, false) // overwrite=false must not replace first
+	if policy.IsRetryable(errors.New("boom")) != true {
+		t.Error("expected the first checker to survive a non-overwriting SetRetryableChecker call")
+	}
+
+	policy.SetRetryableChecker(second, true) // overwrite=true must replace it
+	if policy.IsRetryable(errors.New("boom")) != false {
+		t.Error("expected overwrite=true to replace the existing checker")
+	}
+}
+
+func TestRetryPolicyExecRetriesUntilSuccess(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    1 * time.Millisecond,
+		MaxBackoff:        1 * time.Millisecond,
+		BackoffMultiplier: 1.0,
+	}
+	policy.SetRetryableChecker(DefaultIsRetryable, true)
+
+	callCount := 0
+	err := policy.Exec(context.Background(), func() error {
+		callCount++
+		if callCount < 2 {
+			return errors.New("timeout")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected Exec to succeed after a retry, got: %v", err)
+	}
+	if callCount != 2 {
+		t.Errorf("expected 2 calls, got %d", callCount)
+	}
+}
+
+func TestRetryPolicyExecReturnsOnlyLatestError(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    1 * time.Millisecond,
+		MaxBackoff:        1 * time.Millisecond,
+		BackoffMultiplier: 1.0,
+	}
+	policy.SetRetryableChecker(DefaultIsRetryable, true)
+
+	callCount := 0
+	err := policy.Exec(context.Background(), func() error {
+		callCount++
+		return fmt.Errorf("timeout on attempt %d", callCount)
+	})
+	if err == nil {
+		t.Fatal("expected an error once retries are exhausted")
+	}
+	if !strings.Contains(err.Error(), "timeout on attempt 3") {
+		t.Errorf("expected Exec's error to carry only the latest attempt's message, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "attempt 1") || strings.Contains(err.Error(), "attempt 2") {
+		t.Errorf("expected Exec not to accumulate earlier attempts' errors, got: %v", err)
+	}
+}
+
+func TestConnectorRetryPresetsSetExpectedIsRetryable(t *testing.T) {
+	tests := []struct {
+		name        string
+		newPolicy   func() *RetryPolicy
+		transientErr error
+	}{
+		{"LinkedIn", NewLinkedInRetryPolicy, errors.New("LinkedIn Ads API returned status 429: rate limited")},
+		{"Meta", NewMetaRetryPolicy, errors.New(`Meta Graph API error: {"code": 17, "message": "User request limit reached"}`)},
+		{"Google", NewGoogleRetryPolicy, errors.New("rpc error: code = ResourceExhausted desc = RESOURCE_EXHAUSTED")},
+		{"Postgres", NewPostgresRetryPolicy, errors.New("pq: SQLSTATE 57P03: the database system is starting up")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := tt.newPolicy()
+			if policy.MaxElapsedTime <= 0 {
+				t.Errorf("expected a non-zero MaxElapsedTime preset, got %v", policy.MaxElapsedTime)
+			}
+			if !policy.IsRetryable(tt.transientErr) {
+				t.Errorf("expected %q to be treated as retryable, got not retryable", tt.transientErr)
+			}
+			if policy.IsRetryable(errors.New("invalid input format")) {
+				t.Error("expected a non-transient error to still be rejected by the preset's checker")
+			}
+		})
+	}
+}
+
 // Helper functions
 
 func minBackoff(backoffs []time.Duration) time.Duration {
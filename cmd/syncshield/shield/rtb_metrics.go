@@ -0,0 +1,173 @@
+package shield
+
+import (
+	"sync"
+)
+
+// rtbBidPriceBuckets are exchange-appropriate cutoffs (in dollars) for the
+// bid price histogram - OpenRTB CPM bids cluster well below $1, so the
+// buckets are denser there than the generic latency buckets in metrics.go.
+var rtbBidPriceBuckets = []float64{0.01, 0.05, 0.10, 0.50, 1, 5, 25, 100}
+
+type rtbBidKey struct {
+	platform       string
+	decisionSource string
+}
+
+// RTBMetricsCollector tracks OpenRTB auction-domain counters and histograms
+// - bids submitted, win/loss/billing notices, budget vetoes, and how
+// fallback bidding and LTV prediction are performing - so PrometheusExporter
+// can render them alongside the generic circuit-breaker metrics in
+// MetricsCollector. Disabled by default; enable via
+// PrometheusExporter.EnableRTBMetrics.
+type RTBMetricsCollector struct {
+	mu sync.Mutex
+
+	bidsSubmitted   map[rtbBidKey]int64
+	bidPriceBuckets map[float64]int64 // cumulative count with price <= bucket
+	bidPriceCount   int64
+	bidPriceSum     float64
+
+	winNotices     int64
+	lossNotices    map[string]int64 // reason -> count
+	billingNotices int64
+
+	budgetVetoes map[string]int64 // platform -> count
+
+	fallbackBidSum   float64
+	fallbackBidCount int64
+
+	ltvVsBidRatioSum   float64
+	ltvVsBidRatioCount int64
+}
+
+// NewRTBMetricsCollector creates an empty RTB metrics collector.
+func NewRTBMetricsCollector() *RTBMetricsCollector {
+	return &RTBMetricsCollector{
+		bidsSubmitted:   make(map[rtbBidKey]int64),
+		bidPriceBuckets: make(map[float64]int64),
+		lossNotices:     make(map[string]int64),
+		budgetVetoes:    make(map[string]int64),
+	}
+}
+
+// RecordBidSubmitted counts one bid placed for platform via decisionSource
+// ("ai" or "fallback", matching PlaceBid's own decisionSource values) and
+// adds priceDollars to the bid price histogram.
+func (r *RTBMetricsCollector) RecordBidSubmitted(platform, decisionSource string, priceDollars float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.bidsSubmitted[rtbBidKey{platform: platform, decisionSource: decisionSource}]++
+
+	r.bidPriceCount++
+	r.bidPriceSum += priceDollars
+	for _, bucket := range rtbBidPriceBuckets {
+		if priceDollars <= bucket {
+			r.bidPriceBuckets[bucket]++
+		}
+	}
+}
+
+// RecordWinNotice counts one nurl callback from the exchange.
+func (r *RTBMetricsCollector) RecordWinNotice() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.winNotices++
+}
+
+// RecordLossNotice counts one lurl callback, tagged with the exchange's
+// loss reason code.
+func (r *RTBMetricsCollector) RecordLossNotice(reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lossNotices[reason]++
+}
+
+// RecordBillingNotice counts one burl callback.
+func (r *RTBMetricsCollector) RecordBillingNotice() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.billingNotices++
+}
+
+// RecordBudgetVeto counts one bid blocked by BudgetManager.CanSpend for
+// platform.
+func (r *RTBMetricsCollector) RecordBudgetVeto(platform string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.budgetVetoes[platform]++
+}
+
+// RecordFallbackBid adds one HeuristicFallbackEngine-calculated bid to the
+// running average exposed as syncflow_rtb_fallback_bid_dollars.
+func (r *RTBMetricsCollector) RecordFallbackBid(priceDollars float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallbackBidSum += priceDollars
+	r.fallbackBidCount++
+}
+
+// RecordLTVVsBidRatio adds one PredictedLTV/bidAmount sample to the running
+// average exposed as syncflow_rtb_ltv_vs_bid_ratio - how many dollars of
+// predicted lifetime value each bid dollar is buying.
+func (r *RTBMetricsCollector) RecordLTVVsBidRatio(ratio float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ltvVsBidRatioSum += ratio
+	r.ltvVsBidRatioCount++
+}
+
+// RTBMetricsSummary is a point-in-time snapshot of RTBMetricsCollector for
+// rendering.
+type RTBMetricsSummary struct {
+	BidsSubmitted   map[rtbBidKey]int64
+	BidPriceBuckets map[float64]int64
+	BidPriceCount   int64
+	BidPriceSum     float64
+
+	WinNotices     int64
+	LossNotices    map[string]int64
+	BillingNotices int64
+
+	BudgetVetoes map[string]int64
+
+	FallbackBidAvgDollars float64
+	LTVVsBidRatioAvg      float64
+}
+
+// Snapshot captures the current state of every counter/histogram.
+func (r *RTBMetricsCollector) Snapshot() RTBMetricsSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summary := RTBMetricsSummary{
+		BidsSubmitted:   make(map[rtbBidKey]int64, len(r.bidsSubmitted)),
+		BidPriceBuckets: make(map[float64]int64, len(r.bidPriceBuckets)),
+		BidPriceCount:   r.bidPriceCount,
+		BidPriceSum:     r.bidPriceSum,
+		WinNotices:      r.winNotices,
+		LossNotices:     make(map[string]int64, len(r.lossNotices)),
+		BillingNotices:  r.billingNotices,
+		BudgetVetoes:    make(map[string]int64, len(r.budgetVetoes)),
+	}
+	for k, v := range r.bidsSubmitted {
+		summary.BidsSubmitted[k] = v
+	}
+	for k, v := range r.bidPriceBuckets {
+		summary.BidPriceBuckets[k] = v
+	}
+	for k, v := range r.lossNotices {
+		summary.LossNotices[k] = v
+	}
+	for k, v := range r.budgetVetoes {
+		summary.BudgetVetoes[k] = v
+	}
+	if r.fallbackBidCount > 0 {
+		summary.FallbackBidAvgDollars = r.fallbackBidSum / float64(r.fallbackBidCount)
+	}
+	if r.ltvVsBidRatioCount > 0 {
+		summary.LTVVsBidRatioAvg = r.ltvVsBidRatioSum / float64(r.ltvVsBidRatioCount)
+	}
+	return summary
+}
@@ -0,0 +1,139 @@
+package shield
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRTBMetricsCollectorRecordsBidsAndPriceHistogram(t *testing.T) {
+	rtb := NewRTBMetricsCollector()
+
+	rtb.RecordBidSubmitted("tradedesk", "ai", 0.75)
+	rtb.RecordBidSubmitted("tradedesk", "fallback", 2.00)
+	rtb.RecordBidSubmitted("meta", "ai", 0.03)
+
+	summary := rtb.Snapshot()
+
+	if summary.BidsSubmitted[rtbBidKey{platform: "tradedesk", decisionSource: "ai"}] != 1 {
+		t.Errorf("expected 1 tradedesk/ai bid, got %d", summary.BidsSubmitted[rtbBidKey{platform: "tradedesk", decisionSource: "ai"}])
+	}
+	if summary.BidPriceCount != 3 {
+		t.Errorf("expected 3 bid price samples, got %d", summary.BidPriceCount)
+	}
+	if summary.BidPriceBuckets[0.05] != 1 {
+		t.Errorf("expected 1 sample in the 0.05 bucket, got %d", summary.BidPriceBuckets[0.05])
+	}
+	if summary.BidPriceBuckets[5] != 3 {
+		t.Errorf("expected all 3 samples to roll up into the 5 bucket, got %d", summary.BidPriceBuckets[5])
+	}
+}
+
+func TestRTBMetricsCollectorRecordsNoticesAndVetoes(t *testing.T) {
+	rtb := NewRTBMetricsCollector()
+
+	rtb.RecordWinNotice()
+	rtb.RecordWinNotice()
+	rtb.RecordLossNotice("lost_to_higher_bid")
+	rtb.RecordBillingNotice()
+	rtb.RecordBudgetVeto("tradedesk")
+	rtb.RecordFallbackBid(1.50)
+	rtb.RecordFallbackBid(2.50)
+	rtb.RecordLTVVsBidRatio(4.0)
+
+	summary := rtb.Snapshot()
+
+	if summary.WinNotices != 2 {
+		t.Errorf("expected 2 win notices, got %d", summary.WinNotices)
+	}
+	if summary.LossNotices["lost_to_higher_bid"] != 1 {
+		t.Errorf("expected 1 loss notice with reason lost_to_higher_bid, got %d", summary.LossNotices["lost_to_higher_bid"])
+	}
+	if summary.BillingNotices != 1 {
+		t.Errorf("expected 1 billing notice, got %d", summary.BillingNotices)
+	}
+	if summary.BudgetVetoes["tradedesk"] != 1 {
+		t.Errorf("expected 1 tradedesk budget veto, got %d", summary.BudgetVetoes["tradedesk"])
+	}
+	if summary.FallbackBidAvgDollars != 2.0 {
+		t.Errorf("expected average fallback bid of 2.0, got %.2f", summary.FallbackBidAvgDollars)
+	}
+	if summary.LTVVsBidRatioAvg != 4.0 {
+		t.Errorf("expected average LTV/bid ratio of 4.0, got %.2f", summary.LTVVsBidRatioAvg)
+	}
+}
+
+func TestPrometheusExporter_RenderMetricsIncludesRTBBlockOnlyWhenEnabled(t *testing.T) {
+	exporter := NewPrometheusExporter(NewMetricsCollector(), 0)
+
+	if strings.Contains(exporter.renderMetrics(), "syncflow_rtb_win_notices_total") {
+		t.Fatal("expected no RTB metrics before EnableRTBMetrics is called")
+	}
+
+	rtb := exporter.EnableRTBMetrics()
+	rtb.RecordWinNotice()
+
+	output := exporter.renderMetrics()
+	if !strings.Contains(output, "syncflow_rtb_win_notices_total 1") {
+		t.Fatalf("expected rendered metrics to include the win notice count, got:\n%s", output)
+	}
+}
+
+func TestPrometheusExporter_WinNoticeHandlerIncrementsRTBCounter(t *testing.T) {
+	exporter := NewPrometheusExporter(NewMetricsCollector(), 0)
+	rtb := exporter.EnableRTBMetrics()
+
+	req := httptest.NewRequest(http.MethodGet, "/win-notice", nil)
+	w := httptest.NewRecorder()
+	exporter.winNoticeHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if rtb.Snapshot().WinNotices != 1 {
+		t.Fatalf("expected 1 win notice recorded, got %d", rtb.Snapshot().WinNotices)
+	}
+}
+
+func TestPrometheusExporter_LossNoticeHandlerUsesReasonQueryParam(t *testing.T) {
+	exporter := NewPrometheusExporter(NewMetricsCollector(), 0)
+	rtb := exporter.EnableRTBMetrics()
+
+	req := httptest.NewRequest(http.MethodGet, "/loss-notice?reason=lost_to_higher_bid", nil)
+	w := httptest.NewRecorder()
+	exporter.lossNoticeHandler(w, req)
+
+	summary := rtb.Snapshot()
+	if summary.LossNotices["lost_to_higher_bid"] != 1 {
+		t.Fatalf("expected 1 loss notice tagged lost_to_higher_bid, got %+v", summary.LossNotices)
+	}
+}
+
+func TestPrometheusExporter_PushOnceSendsBasicAuthAndRetriesOnFailure(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "op" || pass != "secret" {
+			t.Errorf("expected basic auth op:secret, got ok=%v user=%q pass=%q", ok, user, pass)
+		}
+		if attempts < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := NewPrometheusExporter(NewMetricsCollector(), 0)
+	exporter.PushAuthUser = "op"
+	exporter.PushAuthPassword = "secret"
+
+	if err := exporter.pushOnce(server.URL, "bidder"); err != nil {
+		t.Fatalf("expected pushOnce to succeed after a retry, got: %v", err)
+	}
+	if attempts < 2 {
+		t.Fatalf("expected at least 2 attempts (one failure then a retry), got %d", attempts)
+	}
+}
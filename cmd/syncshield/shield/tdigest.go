@@ -0,0 +1,194 @@
+package shield
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// defaultTDigestCompression is δ - the t-digest compression parameter -
+// used when a MetricsCollector isn't given one explicitly. Higher values
+// keep more centroids (more memory, more accuracy); lower values compress
+// harder.
+const defaultTDigestCompression = 100
+
+// tdigestCentroid is one cluster in a t-digest: a weighted mean
+// approximating every individual sample that landed close enough to merge
+// into it.
+type tdigestCentroid struct {
+	mean   float64
+	weight float64
+}
+
+// tDigest is a streaming approximation of a value distribution that gives
+// accurate quantile estimates - especially at the tails (p99, p99.9) where
+// a fixed-size reservoir of recent samples is least accurate - from a
+// bounded number of centroids (O(δ) memory) regardless of how many values
+// have ever been added, unlike keeping the last N raw samples.
+//
+// This is the merging t-digest algorithm (Dunning & Ertl,
+// https://arxiv.org/abs/1902.04023): Add places each value into its
+// nearest centroid if doing so wouldn't let that centroid's weight exceed
+// a bound scaled by its position in the distribution (centroids near the
+// median can absorb far more samples than ones out at the tails, which is
+// what gives tail quantiles their precision), otherwise it starts a new
+// centroid. compact periodically re-merges everything once there are more
+// centroids than the compression target, keeping lookups cheap.
+type tDigest struct {
+	centroids   []tdigestCentroid // kept sorted by mean
+	compression float64           // δ
+	totalWeight float64
+}
+
+// newTDigest creates an empty digest with the given compression (δ). A
+// non-positive compression falls back to defaultTDigestCompression.
+func newTDigest(compression float64) *tDigest {
+	if compression <= 0 {
+		compression = defaultTDigestCompression
+	}
+	return &tDigest{compression: compression}
+}
+
+// Add records one occurrence of x.
+func (td *tDigest) Add(x float64) {
+	td.addWeighted(x, 1)
+}
+
+// addWeighted records weight occurrences of x, then compacts if that left
+// more than ⌈δ⌉ centroids.
+func (td *tDigest) addWeighted(x, weight float64) {
+	td.addCentroid(x, weight, td.totalWeight+weight)
+	if float64(len(td.centroids)) > math.Ceil(td.compression) {
+		td.compact()
+	}
+}
+
+// addCentroid does the actual merge-or-insert, with no compaction side
+// effect - compact's own rebuild loop uses this directly, since letting it
+// call addWeighted would recheck the threshold after every single
+// re-inserted centroid and recurse into another compact before the rebuild
+// it's already doing has even finished.
+//
+// scaleTotal is the total weight to use when computing a centroid's
+// position-scaled bound (see q below). A streaming Add passes the total
+// including the new sample; compact's rebuild passes the digest's full,
+// already-known final total throughout, rather than the partial total it
+// has re-accumulated so far - using the latter would make every centroid
+// look like it's further out in the tails than it really is until the
+// rebuild is almost done, artificially starving merges and leaving the
+// digest with far more centroids than the compression target intends.
+func (td *tDigest) addCentroid(x, weight, scaleTotal float64) {
+	if len(td.centroids) == 0 {
+		td.centroids = append(td.centroids, tdigestCentroid{mean: x, weight: weight})
+		td.totalWeight = weight
+		return
+	}
+
+	idx := td.nearestCentroid(x)
+	c := &td.centroids[idx]
+
+	// q is this centroid's position in the distribution (0 at the minimum,
+	// 1 at the maximum); maxWeight bounds how much more it may absorb
+	// before a new centroid must be started instead; Dunning & Ertl's scale
+	// function is what makes that bound small at the tails (q near 0 or 1)
+	// and large near the median (q near 0.5).
+	before := 0.0
+	for i := 0; i < idx; i++ {
+		before += td.centroids[i].weight
+	}
+	q := (before + c.weight/2) / scaleTotal
+	maxWeight := 4 * scaleTotal * q * (1 - q) / td.compression
+
+	if c.weight+weight <= maxWeight {
+		c.mean += (x - c.mean) * weight / (c.weight + weight)
+		c.weight += weight
+	} else {
+		td.insertCentroid(x, weight)
+	}
+	td.totalWeight += weight
+}
+
+// nearestCentroid returns the index of the centroid whose mean is closest
+// to x, via binary search since centroids are kept sorted by mean.
+func (td *tDigest) nearestCentroid(x float64) int {
+	idx := sort.Search(len(td.centroids), func(i int) bool {
+		return td.centroids[i].mean >= x
+	})
+	if idx == 0 {
+		return 0
+	}
+	if idx == len(td.centroids) {
+		return idx - 1
+	}
+	if x-td.centroids[idx-1].mean <= td.centroids[idx].mean-x {
+		return idx - 1
+	}
+	return idx
+}
+
+// insertCentroid starts a new centroid for x, keeping centroids sorted by mean.
+func (td *tDigest) insertCentroid(x, weight float64) {
+	pos := sort.Search(len(td.centroids), func(i int) bool {
+		return td.centroids[i].mean >= x
+	})
+	td.centroids = append(td.centroids, tdigestCentroid{})
+	copy(td.centroids[pos+1:], td.centroids[pos:])
+	td.centroids[pos] = tdigestCentroid{mean: x, weight: weight}
+}
+
+// compact re-merges every centroid from scratch in random order, which
+// keeps the result from depending on the arrival order of the original
+// samples. Runs whenever Add leaves more than ⌈δ⌉ centroids, bounding
+// lookup and insert cost to O(δ) regardless of throughput.
+func (td *tDigest) compact() {
+	old := td.centroids
+	rand.Shuffle(len(old), func(i, j int) { old[i], old[j] = old[j], old[i] })
+
+	total := td.totalWeight
+	fresh := newTDigest(td.compression)
+	for _, c := range old {
+		fresh.addCentroid(c.mean, c.weight, total)
+	}
+	td.centroids = fresh.centroids
+	td.totalWeight = fresh.totalWeight
+}
+
+// Quantile estimates the value at quantile p (0..1) by walking centroids
+// in order, accumulating weight until the running total reaches p's share
+// of totalWeight, then linearly interpolating between the centroids whose
+// midpoints bracket that target.
+func (td *tDigest) Quantile(p float64) float64 {
+	n := len(td.centroids)
+	if n == 0 {
+		return 0
+	}
+	if n == 1 || p <= 0 {
+		return td.centroids[0].mean
+	}
+	if p >= 1 {
+		return td.centroids[n-1].mean
+	}
+
+	target := p * td.totalWeight
+
+	cumulative := 0.0
+	for i, c := range td.centroids {
+		midpoint := cumulative + c.weight/2
+		if target <= midpoint {
+			if i == 0 {
+				return c.mean
+			}
+			prev := td.centroids[i-1]
+			prevMidpoint := cumulative - prev.weight/2
+			frac := (target - prevMidpoint) / (midpoint - prevMidpoint)
+			return prev.mean + frac*(c.mean-prev.mean)
+		}
+		cumulative += c.weight
+	}
+	return td.centroids[n-1].mean
+}
+
+// Count returns the total weight (sample count) the digest has absorbed.
+func (td *tDigest) Count() int64 {
+	return int64(td.totalWeight)
+}
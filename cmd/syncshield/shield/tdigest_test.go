@@ -0,0 +1,68 @@
+package shield
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTDigestQuantilesOnUniformDistribution(t *testing.T) {
+	td := newTDigest(defaultTDigestCompression)
+	for i := 1; i <= 10000; i++ {
+		td.Add(float64(i))
+	}
+
+	cases := []struct {
+		q        float64
+		want     float64
+		tolerant float64
+	}{
+		{0.50, 5000, 100},
+		{0.90, 9000, 100},
+		{0.99, 9900, 150},
+	}
+	for _, c := range cases {
+		got := td.Quantile(c.q)
+		if math.Abs(got-c.want) > c.tolerant {
+			t.Errorf("Quantile(%.2f): expected ~%.0f (±%.0f), got %.2f", c.q, c.want, c.tolerant, got)
+		}
+	}
+}
+
+func TestTDigestBoundsCentroidCount(t *testing.T) {
+	td := newTDigest(50)
+	for i := 0; i < 100000; i++ {
+		td.Add(rand.Float64() * 1000)
+	}
+
+	if got := len(td.centroids); got > 200 {
+		t.Errorf("expected centroid count to stay bounded near the compression target, got %d", got)
+	}
+}
+
+func TestTDigestCountTracksTotalWeight(t *testing.T) {
+	td := newTDigest(defaultTDigestCompression)
+	for i := 0; i < 500; i++ {
+		td.Add(float64(i))
+	}
+	if got := td.Count(); got != 500 {
+		t.Errorf("expected Count() 500, got %d", got)
+	}
+}
+
+func TestTDigestEmptyQuantileIsZero(t *testing.T) {
+	td := newTDigest(defaultTDigestCompression)
+	if got := td.Quantile(0.5); got != 0 {
+		t.Errorf("expected 0 for an empty digest, got %.2f", got)
+	}
+}
+
+func TestTDigestSingleValue(t *testing.T) {
+	td := newTDigest(defaultTDigestCompression)
+	td.Add(42)
+	for _, q := range []float64{0, 0.5, 0.99, 1} {
+		if got := td.Quantile(q); got != 42 {
+			t.Errorf("Quantile(%.2f): expected 42, got %.2f", q, got)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCampaignRequest implements campaignIDRequest without pulling in api/pb.
+type fakeCampaignRequest struct{ CampaignID string }
+
+func (r fakeCampaignRequest) GetCampaignID() string { return r.CampaignID }
+
+// fakeTokenValidator implements BearerTokenValidator from a fixed token ->
+// CN map.
+type fakeTokenValidator map[string]string
+
+func (f fakeTokenValidator) Validate(token string) (string, bool) {
+	cn, ok := f[token]
+	return cn, ok
+}
+
+func contextWithVerifiedCert(leaf, issuer *x509.Certificate) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{leaf, issuer}}}},
+	})
+}
+
+func unaryInfo(method string) *grpc.UnaryServerInfo {
+	return &grpc.UnaryServerInfo{FullMethod: method}
+}
+
+func TestStaticCampaignACL_AuthorizedCampaignsAndSet(t *testing.T) {
+	acl := NewStaticCampaignACL(map[string][]string{"agent-1": {"camp-1"}})
+
+	campaigns, ok := acl.AuthorizedCampaigns("agent-1")
+	if !ok || !campaigns["camp-1"] {
+		t.Fatalf("expected agent-1 authorized for camp-1, got %+v ok=%v", campaigns, ok)
+	}
+	if _, ok := acl.AuthorizedCampaigns("unknown"); ok {
+		t.Fatal("expected ok=false for an unregistered CN")
+	}
+
+	acl.Set("agent-1", []string{"camp-2"})
+	campaigns, ok = acl.AuthorizedCampaigns("agent-1")
+	if !ok || campaigns["camp-1"] || !campaigns["camp-2"] {
+		t.Fatalf("expected Set to replace the campaign set, got %+v", campaigns)
+	}
+}
+
+func TestAuthInterceptor_UnaryAllowsUnguardedMethodWithValidCert(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "agent-1", 1)
+	metrics := NewMetrics()
+	ai := NewAuthInterceptor(NewStaticCampaignACL(nil), metrics)
+
+	ctx := contextWithVerifiedCert(leaf, ca.cert)
+	handlerCalled := false
+	_, err := ai.Unary()(ctx, struct{}{}, unaryInfo("/SyncFlowService/GetStatus"),
+		func(ctx context.Context, req interface{}) (interface{}, error) { handlerCalled = true; return nil, nil })
+	if err != nil {
+		t.Fatalf("Unary failed: %v", err)
+	}
+	if !handlerCalled {
+		t.Fatal("expected the handler to be invoked for an unguarded method")
+	}
+	if metrics.authorized != 1 {
+		t.Fatalf("expected 1 authorized RPC recorded, got %d", metrics.authorized)
+	}
+}
+
+func TestAuthInterceptor_UnaryRejectsNoPeerCert(t *testing.T) {
+	ai := NewAuthInterceptor(NewStaticCampaignACL(nil), nil)
+	_, err := ai.Unary()(context.Background(), struct{}{}, unaryInfo("/SyncFlowService/GetStatus"),
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("handler must not be called")
+			return nil, nil
+		})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+}
+
+func TestAuthInterceptor_UnaryDeniesGuardedMethodForUnknownCN(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "stranger", 2)
+	ai := NewAuthInterceptor(NewStaticCampaignACL(nil), nil)
+	ctx := contextWithVerifiedCert(leaf, ca.cert)
+	_, err := ai.Unary()(ctx, fakeCampaignRequest{CampaignID: "camp-1"}, unaryInfo("/SyncFlowService/PlaceBid"),
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("handler must not be called")
+			return nil, nil
+		})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+}
+
+func TestAuthInterceptor_UnaryDeniesGuardedMethodForWrongCampaign(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "agent-1", 3)
+	acl := NewStaticCampaignACL(map[string][]string{"agent-1": {"camp-1"}})
+	ai := NewAuthInterceptor(acl, nil)
+	ctx := contextWithVerifiedCert(leaf, ca.cert)
+	_, err := ai.Unary()(ctx, fakeCampaignRequest{CampaignID: "camp-2"}, unaryInfo("/SyncFlowService/PlaceBid"),
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("handler must not be called")
+			return nil, nil
+		})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+}
+
+func TestAuthInterceptor_UnaryAllowsGuardedMethodForAuthorizedCampaign(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "agent-1", 4)
+	acl := NewStaticCampaignACL(map[string][]string{"agent-1": {"camp-1"}})
+	ai := NewAuthInterceptor(acl, nil)
+	ctx := contextWithVerifiedCert(leaf, ca.cert)
+	handlerCalled := false
+	_, err := ai.Unary()(ctx, fakeCampaignRequest{CampaignID: "camp-1"}, unaryInfo("/SyncFlowService/PlaceBid"),
+		func(ctx context.Context, req interface{}) (interface{}, error) { handlerCalled = true; return nil, nil })
+	if err != nil {
+		t.Fatalf("Unary failed: %v", err)
+	}
+	if !handlerCalled {
+		t.Fatal("expected the handler to be invoked for an authorized campaign")
+	}
+}
+
+func TestAuthInterceptor_UnaryFallbackAcceptsValidBearerToken(t *testing.T) {
+	ai := &AuthInterceptor{ACL: NewStaticCampaignACL(nil), Fallback: true, TokenValidator: fakeTokenValidator{"good-token": "agent-1"}}
+	md := metadata.New(map[string]string{"authorization": "Bearer good-token"})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	handlerCalled := false
+	_, err := ai.Unary()(ctx, struct{}{}, unaryInfo("/SyncFlowService/GetStatus"),
+		func(ctx context.Context, req interface{}) (interface{}, error) { handlerCalled = true; return nil, nil })
+	if err != nil {
+		t.Fatalf("Unary failed: %v", err)
+	}
+	if !handlerCalled {
+		t.Fatal("expected the handler to be invoked for a valid bearer token")
+	}
+}
+
+func TestAuthInterceptor_UnaryFallbackRejectsInvalidBearerToken(t *testing.T) {
+	ai := &AuthInterceptor{ACL: NewStaticCampaignACL(nil), Fallback: true, TokenValidator: fakeTokenValidator{}}
+	md := metadata.New(map[string]string{"authorization": "Bearer bad-token"})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	_, err := ai.Unary()(ctx, struct{}{}, unaryInfo("/SyncFlowService/GetStatus"),
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("handler must not be called")
+			return nil, nil
+		})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+}
+
+func TestAuthInterceptor_UnaryRejectsNoCertWithoutFallback(t *testing.T) {
+	ai := &AuthInterceptor{ACL: NewStaticCampaignACL(nil), Fallback: false}
+	md := metadata.New(map[string]string{"authorization": "Bearer irrelevant"})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	_, err := ai.Unary()(ctx, struct{}{}, unaryInfo("/SyncFlowService/GetStatus"),
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("handler must not be called")
+			return nil, nil
+		})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated when Fallback is disabled, got %v", err)
+	}
+}
+
+func TestBearerTokenFromContext(t *testing.T) {
+	md := metadata.New(map[string]string{"authorization": "Bearer abc123"})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	token, ok := bearerTokenFromContext(ctx)
+	if !ok || token != "abc123" {
+		t.Fatalf("expected (\"abc123\", true), got (%q, %v)", token, ok)
+	}
+
+	if _, ok := bearerTokenFromContext(context.Background()); ok {
+		t.Fatal("expected no token when there's no incoming metadata")
+	}
+}
@@ -0,0 +1,50 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// ClientConfig builds the *tls.Config a gRPC client (e.g. LTVConnector
+// dialing SyncValueService) presents its own short-lived certificate
+// through, the dial-side counterpart to ServerConfig.
+type ClientConfig struct {
+	// Cert hot-reloads this client's own identity certificate via
+	// GetClientCertificate.
+	Cert *HotReloadCert
+	// Trust supplies the CA pool the server's certificate is verified
+	// against.
+	Trust TrustStore
+	// ServerName overrides the name used to verify the server's
+	// certificate, for dialing by IP or through a proxy where the dial
+	// target doesn't match the certificate's SAN.
+	ServerName string
+}
+
+// TLSConfig builds a *tls.Config from c, suitable for
+// credentials.NewTLS(cfg) or grpc.WithTransportCredentials.
+func (c *ClientConfig) TLSConfig(ctx context.Context) (*tls.Config, error) {
+	pool, err := c.Trust.CAPool(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("mtls: loading CA pool: %w", err)
+	}
+	return &tls.Config{
+		RootCAs:              pool,
+		GetClientCertificate: c.Cert.GetClientCertificate,
+		ServerName:           c.ServerName,
+		MinVersion:           tls.VersionTLS12,
+	}, nil
+}
+
+// DialCredentials builds grpc.WithTransportCredentials-ready
+// TransportCredentials from c.
+func (c *ClientConfig) DialCredentials(ctx context.Context) (credentials.TransportCredentials, error) {
+	cfg, err := c.TLSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(cfg), nil
+}
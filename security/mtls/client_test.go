@@ -0,0 +1,60 @@
+package mtls
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+)
+
+func TestClientConfig_TLSConfigSetsServerNameAndRootCAs(t *testing.T) {
+	ca := newTestCA(t)
+	certFile, keyFile := writeLeafCertKeyFiles(t, ca, "client")
+	cert, err := NewHotReloadCert(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewHotReloadCert failed: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	cc := &ClientConfig{Cert: cert, Trust: stubTrustStoreFixed{pool: pool}, ServerName: "syncvalue.internal"}
+
+	cfg, err := cc.TLSConfig(context.Background())
+	if err != nil {
+		t.Fatalf("TLSConfig failed: %v", err)
+	}
+	if cfg.ServerName != "syncvalue.internal" {
+		t.Fatalf("expected ServerName to be set, got %q", cfg.ServerName)
+	}
+	if cfg.RootCAs == nil {
+		t.Fatal("expected RootCAs to be set from the trust store")
+	}
+	if cfg.GetClientCertificate == nil {
+		t.Fatal("expected a GetClientCertificate callback to be set")
+	}
+}
+
+func TestClientConfig_TLSConfigPropagatesTrustStoreError(t *testing.T) {
+	cc := &ClientConfig{Cert: &HotReloadCert{}, Trust: erroringTrustStore{}}
+	if _, err := cc.TLSConfig(context.Background()); err == nil {
+		t.Fatal("expected an error when the trust store fails")
+	}
+}
+
+func TestClientConfig_DialCredentialsBuildsTransportCredentials(t *testing.T) {
+	ca := newTestCA(t)
+	certFile, keyFile := writeLeafCertKeyFiles(t, ca, "client")
+	cert, err := NewHotReloadCert(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewHotReloadCert failed: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	cc := &ClientConfig{Cert: cert, Trust: stubTrustStoreFixed{pool: pool}}
+
+	creds, err := cc.DialCredentials(context.Background())
+	if err != nil {
+		t.Fatalf("DialCredentials failed: %v", err)
+	}
+	if creds == nil {
+		t.Fatal("expected non-nil TransportCredentials")
+	}
+}
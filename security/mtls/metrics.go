@@ -0,0 +1,69 @@
+package mtls
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// AuthFailureReason categorizes why AuthInterceptor rejected an RPC, so an
+// operator can tell a misconfigured caller (unknown_cn) apart from an
+// actual compromise (revoked_cert).
+type AuthFailureReason string
+
+const (
+	ReasonUnknownCN    AuthFailureReason = "unknown_cn"
+	ReasonExpiredCert  AuthFailureReason = "expired_cert"
+	ReasonRevokedCert  AuthFailureReason = "revoked_cert"
+	ReasonNoPeerCert   AuthFailureReason = "no_peer_cert"
+	ReasonACLDenied    AuthFailureReason = "acl_denied"
+	ReasonInvalidToken AuthFailureReason = "invalid_bearer_token"
+)
+
+// Metrics tracks Prometheus counters for mTLS auth outcomes.
+type Metrics struct {
+	mu sync.Mutex
+
+	authorized       int64
+	failuresByReason map[AuthFailureReason]int64
+}
+
+// NewMetrics creates an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{failuresByReason: make(map[AuthFailureReason]int64)}
+}
+
+// RecordAuthorized records an RPC AuthInterceptor let through.
+func (m *Metrics) RecordAuthorized() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.authorized++
+}
+
+// RecordFailure records an RPC AuthInterceptor rejected for reason.
+func (m *Metrics) RecordFailure(reason AuthFailureReason) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failuresByReason[reason]++
+}
+
+// Render writes Prometheus text-format output, matching
+// reconcile.Metrics.Render's HELP/TYPE-comment convention.
+func (m *Metrics) Render() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP mtls_auth_authorized_total RPCs authorized by AuthInterceptor\n")
+	b.WriteString("# TYPE mtls_auth_authorized_total counter\n")
+	fmt.Fprintf(&b, "mtls_auth_authorized_total %d\n", m.authorized)
+
+	b.WriteString("\n# HELP mtls_auth_failures_total RPCs rejected by AuthInterceptor, by reason\n")
+	b.WriteString("# TYPE mtls_auth_failures_total counter\n")
+	for reason, count := range m.failuresByReason {
+		fmt.Fprintf(&b, "mtls_auth_failures_total{reason=%q} %d\n", reason, count)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,29 @@
+package mtls
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetrics_RecordAuthorizedAndFailureRender(t *testing.T) {
+	m := NewMetrics()
+	m.RecordAuthorized()
+	m.RecordAuthorized()
+	m.RecordFailure(ReasonUnknownCN)
+
+	out := m.Render()
+	if !strings.Contains(out, "mtls_auth_authorized_total 2") {
+		t.Fatalf("expected authorized_total=2 in render, got %q", out)
+	}
+	if !strings.Contains(out, `mtls_auth_failures_total{reason="unknown_cn"} 1`) {
+		t.Fatalf("expected failures_total for unknown_cn=1 in render, got %q", out)
+	}
+}
+
+func TestMetrics_RenderZeroValue(t *testing.T) {
+	m := NewMetrics()
+	out := m.Render()
+	if !strings.Contains(out, "mtls_auth_authorized_total 0") {
+		t.Fatalf("expected authorized_total=0 on a fresh Metrics, got %q", out)
+	}
+}
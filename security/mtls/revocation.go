@@ -0,0 +1,156 @@
+package mtls
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// RevocationChecker reports whether a verified client certificate has
+// since been revoked - a signed, unexpired certificate can still have
+// been pulled by an operator (a leaked key, an offboarded agent), and
+// AuthInterceptor has to catch that between CA-chain verification and
+// granting the RPC.
+type RevocationChecker interface {
+	// IsRevoked reports whether cert (issued by issuer) has been
+	// revoked.
+	IsRevoked(ctx context.Context, cert, issuer *x509.Certificate) (bool, error)
+}
+
+// OCSPChecker checks revocation by querying the responder URL embedded in
+// the certificate's AuthorityInfoAccess extension (falling back to
+// ResponderURL if the certificate carries none).
+type OCSPChecker struct {
+	// ResponderURL is used only if cert has no OCSP responder URL of its
+	// own.
+	ResponderURL string
+
+	httpClient *http.Client
+}
+
+// NewOCSPChecker creates an OCSPChecker with fallbackResponderURL.
+func NewOCSPChecker(fallbackResponderURL string) *OCSPChecker {
+	return &OCSPChecker{
+		ResponderURL: fallbackResponderURL,
+		httpClient:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// IsRevoked implements RevocationChecker.
+func (c *OCSPChecker) IsRevoked(ctx context.Context, cert, issuer *x509.Certificate) (bool, error) {
+	responderURL := c.ResponderURL
+	if len(cert.OCSPServer) > 0 {
+		responderURL = cert.OCSPServer[0]
+	}
+	if responderURL == "" {
+		return false, fmt.Errorf("mtls: no OCSP responder URL configured for %s", cert.Subject.CommonName)
+	}
+
+	reqBytes, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return false, fmt.Errorf("mtls: building OCSP request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", responderURL, bytes.NewReader(reqBytes))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("mtls: OCSP request to %s: %w", responderURL, err)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("mtls: reading OCSP response: %w", err)
+	}
+
+	parsed, err := ocsp.ParseResponseForCert(respBytes, cert, issuer)
+	if err != nil {
+		return false, fmt.Errorf("mtls: parsing OCSP response: %w", err)
+	}
+	return parsed.Status == ocsp.Revoked, nil
+}
+
+// CRLChecker checks revocation against a CRL fetched from URL and cached
+// until the CRL's NextUpdate passes, so a revocation check doesn't refetch
+// the whole list on every RPC.
+type CRLChecker struct {
+	URL string
+
+	httpClient *http.Client
+
+	mu         sync.Mutex
+	revoked    map[string]struct{} // serial number (decimal string) -> present
+	nextUpdate time.Time
+}
+
+// NewCRLChecker creates a CRLChecker fetching from url.
+func NewCRLChecker(url string) *CRLChecker {
+	return &CRLChecker{
+		URL:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// IsRevoked implements RevocationChecker. issuer is unused by this
+// checker - the fetched CRL is assumed to already be scoped to the
+// relevant CA - but kept in the signature to satisfy RevocationChecker.
+func (c *CRLChecker) IsRevoked(ctx context.Context, cert, issuer *x509.Certificate) (bool, error) {
+	if err := c.refreshIfStale(ctx); err != nil {
+		return false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, revoked := c.revoked[cert.SerialNumber.String()]
+	return revoked, nil
+}
+
+func (c *CRLChecker) refreshIfStale(ctx context.Context) error {
+	c.mu.Lock()
+	stale := time.Now().After(c.nextUpdate)
+	c.mu.Unlock()
+	if !stale {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("mtls: fetching CRL from %s: %w", c.URL, err)
+	}
+	defer resp.Body.Close()
+
+	der, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("mtls: reading CRL response: %w", err)
+	}
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return fmt.Errorf("mtls: parsing CRL: %w", err)
+	}
+
+	revoked := make(map[string]struct{}, len(crl.RevokedCertificateEntries))
+	for _, entry := range crl.RevokedCertificateEntries {
+		revoked[entry.SerialNumber.String()] = struct{}{}
+	}
+
+	c.mu.Lock()
+	c.revoked = revoked
+	c.nextUpdate = crl.NextUpdate
+	c.mu.Unlock()
+	return nil
+}
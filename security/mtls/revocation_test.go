@@ -0,0 +1,122 @@
+package mtls
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func buildCRLDER(t *testing.T, ca *testCA, revokedSerials []int64, nextUpdate time.Time) []byte {
+	t.Helper()
+	var entries []x509.RevocationListEntry
+	for _, s := range revokedSerials {
+		entries = append(entries, x509.RevocationListEntry{SerialNumber: big.NewInt(s), RevocationTime: time.Now()})
+	}
+	tmpl := &x509.RevocationList{
+		Number:                    big.NewInt(1),
+		RevokedCertificateEntries: entries,
+		ThisUpdate:                time.Now().Add(-time.Minute),
+		NextUpdate:                nextUpdate,
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, tmpl, ca.cert, ca.key)
+	if err != nil {
+		t.Fatalf("creating CRL: %v", err)
+	}
+	return der
+}
+
+func TestCRLChecker_IsRevokedTrueForRevokedSerial(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "revoked-client", 42)
+	der := buildCRLDER(t, ca, []int64{42}, time.Now().Add(time.Hour))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write(der) }))
+	defer server.Close()
+
+	c := NewCRLChecker(server.URL)
+	revoked, err := c.IsRevoked(context.Background(), leaf, ca.cert)
+	if err != nil {
+		t.Fatalf("IsRevoked failed: %v", err)
+	}
+	if !revoked {
+		t.Fatal("expected the certificate to be reported revoked")
+	}
+}
+
+func TestCRLChecker_IsRevokedFalseForCleanSerial(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "clean-client", 7)
+	der := buildCRLDER(t, ca, []int64{42}, time.Now().Add(time.Hour))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write(der) }))
+	defer server.Close()
+
+	c := NewCRLChecker(server.URL)
+	revoked, err := c.IsRevoked(context.Background(), leaf, ca.cert)
+	if err != nil {
+		t.Fatalf("IsRevoked failed: %v", err)
+	}
+	if revoked {
+		t.Fatal("expected a clean serial to not be reported revoked")
+	}
+}
+
+func TestCRLChecker_IsRevokedCachesUntilNextUpdate(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "client", 1)
+	der := buildCRLDER(t, ca, nil, time.Now().Add(time.Hour))
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(der)
+	}))
+	defer server.Close()
+
+	c := NewCRLChecker(server.URL)
+	if _, err := c.IsRevoked(context.Background(), leaf, ca.cert); err != nil {
+		t.Fatalf("first IsRevoked failed: %v", err)
+	}
+	if _, err := c.IsRevoked(context.Background(), leaf, ca.cert); err != nil {
+		t.Fatalf("second IsRevoked failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("expected the CRL to be cached until NextUpdate, got %d requests", got)
+	}
+}
+
+func TestCRLChecker_IsRevokedRefetchesAfterNextUpdate(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "client", 1)
+	der := buildCRLDER(t, ca, nil, time.Now().Add(10*time.Millisecond))
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(der)
+	}))
+	defer server.Close()
+
+	c := NewCRLChecker(server.URL)
+	if _, err := c.IsRevoked(context.Background(), leaf, ca.cert); err != nil {
+		t.Fatalf("first IsRevoked failed: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, err := c.IsRevoked(context.Background(), leaf, ca.cert); err != nil {
+		t.Fatalf("second IsRevoked failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Fatalf("expected a refetch once NextUpdate passed, got %d requests", got)
+	}
+}
+
+func TestOCSPChecker_IsRevokedErrorsWithNoResponderConfigured(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "client", 1)
+	c := NewOCSPChecker("")
+	if _, err := c.IsRevoked(context.Background(), leaf, ca.cert); err == nil {
+		t.Fatal("expected an error when no OCSP responder is configured")
+	}
+}
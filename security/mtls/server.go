@@ -0,0 +1,205 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// HotReloadCert watches a cert/key file pair on disk and serves whichever
+// pair WatchForRotation last loaded, the server-side counterpart to
+// credential.MutualTLSProvider's client-side reload loop - a short-lived
+// leaf cert (ca issue --ttl=24h) has to be swapped in well before it
+// expires, without dropping in-flight connections.
+type HotReloadCert struct {
+	CertFile string
+	KeyFile  string
+
+	mu          sync.RWMutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+// NewHotReloadCert loads certFile/keyFile and returns a HotReloadCert
+// ready to use.
+func NewHotReloadCert(certFile, keyFile string) (*HotReloadCert, error) {
+	c := &HotReloadCert{CertFile: certFile, KeyFile: keyFile}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// WatchForRotation polls CertFile/KeyFile's mtimes every interval and
+// reloads when either changed. It blocks - callers invoke it with `go` -
+// and returns once stop is closed.
+func (c *HotReloadCert) WatchForRotation(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			changed, err := c.filesChanged()
+			if err != nil {
+				log.Printf("⚠️ HotReloadCert: checking cert files for rotation: %v", err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+			if err := c.reload(); err != nil {
+				log.Printf("⚠️ HotReloadCert: reload after rotation failed, keeping previous cert: %v", err)
+			}
+		}
+	}
+}
+
+func (c *HotReloadCert) filesChanged() (bool, error) {
+	certInfo, err := os.Stat(c.CertFile)
+	if err != nil {
+		return false, fmt.Errorf("stat cert file: %w", err)
+	}
+	keyInfo, err := os.Stat(c.KeyFile)
+	if err != nil {
+		return false, fmt.Errorf("stat key file: %w", err)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !certInfo.ModTime().Equal(c.certModTime) || !keyInfo.ModTime().Equal(c.keyModTime), nil
+}
+
+func (c *HotReloadCert) reload() error {
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return fmt.Errorf("mtls: load cert/key: %w", err)
+	}
+	certInfo, err := os.Stat(c.CertFile)
+	if err != nil {
+		return fmt.Errorf("mtls: stat cert file: %w", err)
+	}
+	keyInfo, err := os.Stat(c.KeyFile)
+	if err != nil {
+		return fmt.Errorf("mtls: stat key file: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cert = &cert
+	c.certModTime = certInfo.ModTime()
+	c.keyModTime = keyInfo.ModTime()
+	return nil
+}
+
+// GetCertificate is a tls.Config.GetCertificate callback: it hands back
+// whichever cert the last reload loaded, for a server presenting its own
+// identity.
+func (c *HotReloadCert) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert, nil
+}
+
+// GetClientCertificate is a tls.Config.GetClientCertificate callback: it
+// hands back whichever cert the last reload loaded, for this process
+// dialing out as an mTLS client (e.g. SyncFlow calling SyncValue).
+func (c *HotReloadCert) GetClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert, nil
+}
+
+// ServerConfig builds the *tls.Config a SyncValueService/SyncFlowService
+// gRPC server listens with.
+type ServerConfig struct {
+	// Cert hot-reloads this server's own identity certificate.
+	Cert *HotReloadCert
+	// Trust supplies the CA pool client certificates are verified
+	// against.
+	Trust TrustStore
+	// Revocation, if set, is consulted for every verified client
+	// certificate via VerifyPeerCertificate.
+	Revocation RevocationChecker
+	// Fallback allows a connection to proceed without a client
+	// certificate (for bearer-token auth, checked downstream by
+	// AuthInterceptor) during a migration window. Once every caller has
+	// a certificate, disable this to enforce tls.RequireAndVerifyClientCert.
+	Fallback bool
+	// Metrics records revocation-check failures; may be nil.
+	Metrics *Metrics
+}
+
+// TLSConfig builds a *tls.Config from c, suitable for
+// credentials.NewTLS(cfg) or grpc.Creds(credentials.NewTLS(cfg)).
+func (c *ServerConfig) TLSConfig(ctx context.Context) (*tls.Config, error) {
+	pool, err := c.Trust.CAPool(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("mtls: loading CA pool: %w", err)
+	}
+
+	clientAuth := tls.RequireAndVerifyClientCert
+	if c.Fallback {
+		clientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	cfg := &tls.Config{
+		ClientCAs:      pool,
+		ClientAuth:     clientAuth,
+		GetCertificate: c.Cert.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+	if c.Revocation != nil {
+		cfg.VerifyPeerCertificate = c.verifyPeerCertificate
+	}
+	return cfg, nil
+}
+
+// ServerCredentials builds grpc.ServerOption-ready TransportCredentials
+// from c.
+func (c *ServerConfig) ServerCredentials(ctx context.Context) (credentials.TransportCredentials, error) {
+	cfg, err := c.TLSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// verifyPeerCertificate runs after Go's own chain verification succeeds,
+// rejecting the handshake if c.Revocation reports the leaf certificate
+// revoked.
+func (c *ServerConfig) verifyPeerCertificate(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	for _, chain := range verifiedChains {
+		if len(chain) < 2 {
+			continue // no issuer in the chain to check against
+		}
+		leaf, issuer := chain[0], chain[1]
+		revoked, err := c.Revocation.IsRevoked(context.Background(), leaf, issuer)
+		if err != nil {
+			// Fail open on a revocation-check transport error - an
+			// unreachable CRL/OCSP responder shouldn't itself take down
+			// bidding - but record it so an operator notices the gap.
+			if c.Metrics != nil {
+				c.Metrics.RecordFailure(ReasonRevokedCert)
+			}
+			continue
+		}
+		if revoked {
+			if c.Metrics != nil {
+				c.Metrics.RecordFailure(ReasonRevokedCert)
+			}
+			return fmt.Errorf("mtls: certificate %s is revoked", leaf.Subject.CommonName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"testing"
+)
+
+type stubTrustStoreFixed struct{ pool *x509.CertPool }
+
+func (s stubTrustStoreFixed) CAPool(ctx context.Context) (*x509.CertPool, error) { return s.pool, nil }
+
+type erroringTrustStore struct{}
+
+func (erroringTrustStore) CAPool(ctx context.Context) (*x509.CertPool, error) {
+	return nil, errors.New("trust store unavailable")
+}
+
+type stubRevocationChecker struct {
+	fn func(cert, issuer *x509.Certificate) (bool, error)
+}
+
+func (s stubRevocationChecker) IsRevoked(ctx context.Context, cert, issuer *x509.Certificate) (bool, error) {
+	return s.fn(cert, issuer)
+}
+
+func newTestServerConfig(t *testing.T, ca *testCA) *ServerConfig {
+	t.Helper()
+	certFile, keyFile := writeLeafCertKeyFiles(t, ca, "server")
+	cert, err := NewHotReloadCert(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewHotReloadCert failed: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return &ServerConfig{Cert: cert, Trust: stubTrustStoreFixed{pool: pool}}
+}
+
+func TestServerConfig_TLSConfigRequiresClientCertByDefault(t *testing.T) {
+	ca := newTestCA(t)
+	sc := newTestServerConfig(t, ca)
+
+	cfg, err := sc.TLSConfig(context.Background())
+	if err != nil {
+		t.Fatalf("TLSConfig failed: %v", err)
+	}
+	if cfg.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Fatalf("expected RequireAndVerifyClientCert by default, got %v", cfg.ClientAuth)
+	}
+	if cfg.VerifyPeerCertificate != nil {
+		t.Fatal("expected no VerifyPeerCertificate callback when Revocation is unset")
+	}
+}
+
+func TestServerConfig_TLSConfigAllowsFallback(t *testing.T) {
+	ca := newTestCA(t)
+	sc := newTestServerConfig(t, ca)
+	sc.Fallback = true
+
+	cfg, err := sc.TLSConfig(context.Background())
+	if err != nil {
+		t.Fatalf("TLSConfig failed: %v", err)
+	}
+	if cfg.ClientAuth != tls.VerifyClientCertIfGiven {
+		t.Fatalf("expected VerifyClientCertIfGiven in fallback mode, got %v", cfg.ClientAuth)
+	}
+}
+
+func TestServerConfig_TLSConfigSetsVerifyPeerCertificateWhenRevocationConfigured(t *testing.T) {
+	ca := newTestCA(t)
+	sc := newTestServerConfig(t, ca)
+	sc.Revocation = stubRevocationChecker{fn: func(cert, issuer *x509.Certificate) (bool, error) { return false, nil }}
+
+	cfg, err := sc.TLSConfig(context.Background())
+	if err != nil {
+		t.Fatalf("TLSConfig failed: %v", err)
+	}
+	if cfg.VerifyPeerCertificate == nil {
+		t.Fatal("expected a VerifyPeerCertificate callback when Revocation is set")
+	}
+}
+
+func TestServerConfig_TLSConfigPropagatesTrustStoreError(t *testing.T) {
+	sc := &ServerConfig{Cert: &HotReloadCert{}, Trust: erroringTrustStore{}}
+	if _, err := sc.TLSConfig(context.Background()); err == nil {
+		t.Fatal("expected an error when the trust store fails")
+	}
+}
+
+func TestServerConfig_VerifyPeerCertificateRejectsRevoked(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "client", 1)
+	metrics := NewMetrics()
+	sc := &ServerConfig{
+		Revocation: stubRevocationChecker{fn: func(cert, issuer *x509.Certificate) (bool, error) { return true, nil }},
+		Metrics:    metrics,
+	}
+
+	err := sc.verifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, ca.cert}})
+	if err == nil {
+		t.Fatal("expected an error for a revoked certificate")
+	}
+	if metrics.failuresByReason[ReasonRevokedCert] != 1 {
+		t.Fatalf("expected 1 revoked_cert failure recorded, got %d", metrics.failuresByReason[ReasonRevokedCert])
+	}
+}
+
+func TestServerConfig_VerifyPeerCertificateAllowsClean(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "client", 1)
+	sc := &ServerConfig{Revocation: stubRevocationChecker{fn: func(cert, issuer *x509.Certificate) (bool, error) { return false, nil }}}
+
+	if err := sc.verifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, ca.cert}}); err != nil {
+		t.Fatalf("expected a clean certificate to pass, got %v", err)
+	}
+}
+
+func TestServerConfig_VerifyPeerCertificateFailsOpenOnCheckerError(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "client", 1)
+	metrics := NewMetrics()
+	sc := &ServerConfig{
+		Revocation: stubRevocationChecker{fn: func(cert, issuer *x509.Certificate) (bool, error) { return false, errors.New("responder unreachable") }},
+		Metrics:    metrics,
+	}
+
+	if err := sc.verifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, ca.cert}}); err != nil {
+		t.Fatalf("expected a revocation-check transport error to fail open, got %v", err)
+	}
+	if metrics.failuresByReason[ReasonRevokedCert] != 1 {
+		t.Fatalf("expected the transport error to still be recorded, got %d", metrics.failuresByReason[ReasonRevokedCert])
+	}
+}
+
+func TestServerConfig_VerifyPeerCertificateSkipsChainWithNoIssuer(t *testing.T) {
+	ca := newTestCA(t)
+	leaf := ca.issueLeaf(t, "client", 1)
+	sc := &ServerConfig{Revocation: stubRevocationChecker{fn: func(cert, issuer *x509.Certificate) (bool, error) {
+		t.Fatal("IsRevoked must not be called for a chain with no issuer")
+		return false, nil
+	}}}
+
+	if err := sc.verifyPeerCertificate(nil, [][]*x509.Certificate{{leaf}}); err != nil {
+		t.Fatalf("expected no error for a single-certificate chain, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+// Package mtls provides mutual TLS for this module's gRPC traffic instead
+// of a bearer-token-in-metadata scheme: every caller presents a
+// short-lived client certificate, verified against a CA bundle, and
+// AuthInterceptor maps the cert's Subject CN to the campaigns that caller
+// may bid on. ClientConfig is wired into ltvconnector.LTVConnector's dial
+// to SyncValueService. ServerConfig/AuthInterceptor have no gRPC server to
+// attach to yet - this repo doesn't implement or host SyncValueService or
+// SyncFlowService's server side, so they're ready for whichever binary
+// ends up owning that listener. credential.MutualTLSProvider is this
+// package's counterpart for HTTP connectors rather than gRPC.
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// TrustStore supplies the CA bundle AuthInterceptor verifies client
+// certificates against. Pluggable so a deployment can source its CA from
+// a local file, Vault's PKI secrets engine, or a Kubernetes Secret,
+// without AuthInterceptor caring which.
+type TrustStore interface {
+	// CAPool returns the current trusted CA pool. Implementations that
+	// back onto a rotating source (Vault, a Secret) re-fetch on every
+	// call rather than caching indefinitely, so a CA rotation takes
+	// effect without a process restart.
+	CAPool(ctx context.Context) (*x509.CertPool, error)
+}
+
+// FileTrustStore reads a PEM CA bundle from a local path, re-reading it on
+// every call - the same "stat and reload" cost MutualTLSProvider accepts
+// for its own CA bundle, since CAPool isn't called per-RPC but once per
+// new connection's handshake.
+type FileTrustStore struct {
+	Path string
+}
+
+// NewFileTrustStore creates a FileTrustStore reading path.
+func NewFileTrustStore(path string) *FileTrustStore {
+	return &FileTrustStore{Path: path}
+}
+
+// CAPool implements TrustStore.
+func (s *FileTrustStore) CAPool(ctx context.Context) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("mtls: reading CA bundle %s: %w", s.Path, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("mtls: no valid certificates found in CA bundle %s", s.Path)
+	}
+	return pool, nil
+}
+
+// VaultTrustStore fetches a CA bundle from Vault's PKI secrets engine over
+// its plain HTTP API - no Vault client is vendored in this repo (the same
+// gap predict.Client works around for api/pb), so this issues the REST
+// call directly, matching reconcile.StripeActualsFetcher's approach for an
+// unvendored third-party API.
+type VaultTrustStore struct {
+	// Addr is Vault's base address, e.g. "https://vault.internal:8200".
+	Addr string
+	// MountPath is the PKI secrets engine's mount, e.g. "pki".
+	MountPath string
+	// Token authenticates the request; callers are responsible for
+	// keeping it valid (Vault Agent, AppRole renewal, etc.).
+	Token string
+
+	httpClient *http.Client
+}
+
+// NewVaultTrustStore creates a VaultTrustStore against addr/mountPath,
+// authenticated with token.
+func NewVaultTrustStore(addr, mountPath, token string) *VaultTrustStore {
+	return &VaultTrustStore{
+		Addr:       addr,
+		MountPath:  mountPath,
+		Token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// CAPool implements TrustStore by reading the PKI mount's CA chain
+// endpoint (GET /v1/<mount>/ca_chain, PEM-encoded).
+func (s *VaultTrustStore) CAPool(ctx context.Context) (*x509.CertPool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET",
+		fmt.Sprintf("%s/v1/%s/ca_chain", s.Addr, s.MountPath), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", s.Token)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("mtls: fetching CA chain from vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mtls: vault ca_chain request failed: %d", resp.StatusCode)
+	}
+	pem, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("mtls: reading vault ca_chain response: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("mtls: no valid certificates found in vault ca_chain response")
+	}
+	return pool, nil
+}
+
+// K8sSecretTrustStore fetches a CA bundle from a Kubernetes Secret via the
+// API server's REST interface - no client-go is vendored in this repo, so
+// this talks to the API server directly using the pod's in-cluster
+// service account token, the same unvendored-REST-adapter approach
+// VaultTrustStore takes.
+type K8sSecretTrustStore struct {
+	// APIServer is the Kubernetes API server's base URL, e.g.
+	// "https://kubernetes.default.svc".
+	APIServer string
+	Namespace string
+	Name      string
+	// Key is the Secret's data key holding the PEM CA bundle, e.g.
+	// "ca.crt".
+	Key string
+	// Token is the bearer token presented to the API server - normally
+	// read from /var/run/secrets/kubernetes.io/serviceaccount/token.
+	Token string
+	// CAPool, if set, verifies the API server's own TLS certificate
+	// (normally the cluster's CA bundle, read from the same service
+	// account directory). Left nil only for local testing against an
+	// API server already trusted by the system pool.
+	APIServerCAPool *x509.CertPool
+
+	httpClient *http.Client
+}
+
+// NewK8sSecretTrustStore creates a K8sSecretTrustStore reading
+// namespace/name's Secret data[key] from apiServer, authenticated with
+// token and verifying the API server against apiServerCAPool.
+func NewK8sSecretTrustStore(apiServer, namespace, name, key, token string, apiServerCAPool *x509.CertPool) *K8sSecretTrustStore {
+	return &K8sSecretTrustStore{
+		APIServer:       apiServer,
+		Namespace:       namespace,
+		Name:            name,
+		Key:             key,
+		Token:           token,
+		APIServerCAPool: apiServerCAPool,
+		httpClient:      newK8sHTTPClient(apiServerCAPool),
+	}
+}
+
+// CAPool implements TrustStore by GETting the Secret and base64-decoding
+// its Key entry.
+func (s *K8sSecretTrustStore) CAPool(ctx context.Context) (*x509.CertPool, error) {
+	url := fmt.Sprintf("%s/api/v1/namespaces/%s/secrets/%s", s.APIServer, s.Namespace, s.Name)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("mtls: fetching secret %s/%s: %w", s.Namespace, s.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mtls: kubernetes secret request failed: %d", resp.StatusCode)
+	}
+
+	var secret struct {
+		Data map[string][]byte `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("mtls: decoding secret %s/%s: %w", s.Namespace, s.Name, err)
+	}
+	pem, ok := secret.Data[s.Key]
+	if !ok {
+		return nil, fmt.Errorf("mtls: secret %s/%s has no key %q", s.Namespace, s.Name, s.Key)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("mtls: no valid certificates found in secret %s/%s[%s]", s.Namespace, s.Name, s.Key)
+	}
+	return pool, nil
+}
+
+// newK8sHTTPClient builds an http.Client that verifies the API server
+// against caPool, or the system pool if caPool is nil.
+func newK8sHTTPClient(caPool *x509.CertPool) *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: caPool},
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package mtls
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileTrustStore_CAPoolReadsValidBundle(t *testing.T) {
+	ca := newTestCA(t)
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, pemEncodeCert(ca.cert), 0o600); err != nil {
+		t.Fatalf("writing CA bundle: %v", err)
+	}
+
+	s := NewFileTrustStore(path)
+	pool, err := s.CAPool(context.Background())
+	if err != nil {
+		t.Fatalf("CAPool failed: %v", err)
+	}
+	if pool == nil {
+		t.Fatal("expected a non-nil pool")
+	}
+}
+
+func TestFileTrustStore_CAPoolErrorsOnMissingFile(t *testing.T) {
+	s := NewFileTrustStore(filepath.Join(t.TempDir(), "missing.pem"))
+	if _, err := s.CAPool(context.Background()); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
+
+func TestFileTrustStore_CAPoolErrorsOnInvalidPEM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.pem")
+	if err := os.WriteFile(path, []byte("not a cert"), 0o600); err != nil {
+		t.Fatalf("writing bad bundle: %v", err)
+	}
+
+	s := NewFileTrustStore(path)
+	if _, err := s.CAPool(context.Background()); err == nil {
+		t.Fatal("expected an error for invalid PEM content")
+	}
+}
+
+func TestVaultTrustStore_CAPoolFetchesCAChain(t *testing.T) {
+	ca := newTestCA(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/pki/ca_chain" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			t.Errorf("expected the vault token header to be set")
+		}
+		w.Write(pemEncodeCert(ca.cert))
+	}))
+	defer server.Close()
+
+	s := NewVaultTrustStore(server.URL, "pki", "test-token")
+	pool, err := s.CAPool(context.Background())
+	if err != nil {
+		t.Fatalf("CAPool failed: %v", err)
+	}
+	if pool == nil {
+		t.Fatal("expected a non-nil pool")
+	}
+}
+
+func TestVaultTrustStore_CAPoolErrorsOnNon200(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	s := NewVaultTrustStore(server.URL, "pki", "bad-token")
+	if _, err := s.CAPool(context.Background()); err == nil {
+		t.Fatal("expected an error for a non-200 vault response")
+	}
+}
+
+func TestK8sSecretTrustStore_CAPoolDecodesSecretData(t *testing.T) {
+	ca := newTestCA(t)
+	pemBytes := pemEncodeCert(ca.cert)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string][]byte{"ca.crt": pemBytes},
+		})
+	}))
+	defer server.Close()
+
+	s := NewK8sSecretTrustStore(server.URL, "default", "ca-bundle", "ca.crt", "test-token", nil)
+	pool, err := s.CAPool(context.Background())
+	if err != nil {
+		t.Fatalf("CAPool failed: %v", err)
+	}
+	if pool == nil {
+		t.Fatal("expected a non-nil pool")
+	}
+}
+
+func TestK8sSecretTrustStore_CAPoolErrorsOnMissingKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string][]byte{}})
+	}))
+	defer server.Close()
+
+	s := NewK8sSecretTrustStore(server.URL, "default", "ca-bundle", "ca.crt", "test-token", nil)
+	if _, err := s.CAPool(context.Background()); err == nil {
+		t.Fatal("expected an error when the secret has no matching key")
+	}
+}
+
+func TestK8sSecretTrustStore_CAPoolErrorsOnNon200(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	s := NewK8sSecretTrustStore(server.URL, "default", "ca-bundle", "ca.crt", "test-token", nil)
+	if _, err := s.CAPool(context.Background()); err == nil {
+		t.Fatal("expected an error for a non-200 API server response")
+	}
+}